@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"time"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/jobs"
+)
+
+// absensiMarkAlpa marks students without scan or izin/sakit as alpa for one date or a range of dates
+func absensiMarkAlpa(args []string) {
+	fs := flag.NewFlagSet("absensi:mark-alpa", flag.ExitOnError)
+	tanggal := fs.String("tanggal", "", "Date to process (YYYY-MM-DD), default today")
+	sampai := fs.String("sampai", "", "Process every date from --tanggal up to this date (YYYY-MM-DD)")
+	dryRun := fs.Bool("dry-run", false, "Only report, do not write rekap rows")
+	asJSON := fs.Bool("json", false, "Print the full report as JSON")
+	fs.Parse(args)
+
+	dates := []string{*tanggal}
+	if *sampai != "" {
+		if *tanggal == "" {
+			fmt.Println("Error: --sampai requires --tanggal")
+			return
+		}
+		mulai, err := time.Parse("2006-01-02", *tanggal)
+		if err != nil {
+			fmt.Println("Error: invalid --tanggal, use YYYY-MM-DD")
+			return
+		}
+		selesai, err := time.Parse("2006-01-02", *sampai)
+		if err != nil || selesai.Before(mulai) {
+			fmt.Println("Error: invalid --sampai, use YYYY-MM-DD on or after --tanggal")
+			return
+		}
+		dates = nil
+		for d := mulai; !d.After(selesai); d = d.AddDate(0, 0, 1) {
+			dates = append(dates, d.Format("2006-01-02"))
+		}
+	}
+
+	db, err := openDatabase()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	service := jobs.NewAbsensiAlpaService(db)
+
+	for _, date := range dates {
+		report, err := service.Run(&dtos.AbsensiAlpaRunRequest{Tanggal: date, DryRun: *dryRun, Trigger: "cli"})
+		if err != nil {
+			fmt.Printf("%s: error: %v\n", date, err)
+			continue
+		}
+
+		if *asJSON {
+			out, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(out))
+			continue
+		}
+
+		fmt.Printf("%s: %s\n", report.Tanggal, report.Message)
+		for _, detail := range report.Details {
+			line := fmt.Sprintf("  [%s] %s %s (%s)", detail.Action, detail.NIS, detail.Nama, detail.Rombel)
+			if detail.Reason != "" {
+				line += ": " + detail.Reason
+			}
+			fmt.Println(line)
+		}
+	}
+}
@@ -0,0 +1,412 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	"pintu-backend/src/modules/repositories"
+
+	"gorm.io/gorm"
+)
+
+// errBenchmarkRollback aborts the benchmark transaction so the seeded rows are never committed
+var errBenchmarkRollback = errors.New("benchmark rollback")
+
+// dashboardBenchmark is one dashboard query timed on the row-fetch path and the GROUP BY path.
+// Both return a checksum that must match for the results to be considered equal.
+type dashboardBenchmark struct {
+	name      string
+	legacy    func() (string, error)
+	aggregate func() (string, error)
+}
+
+// absensiBenchmarkDashboard seeds a synthetic tahun pelajaran inside a transaction, times the
+// dashboard queries before (rows counted in Go) and after (GROUP BY in SQL) and rolls back
+func absensiBenchmarkDashboard(args []string) {
+	fs := flag.NewFlagSet("absensi:benchmark-dashboard", flag.ExitOnError)
+	jumlahRombel := fs.Int("rombel", 24, "Number of rombel to seed")
+	siswaPerRombel := fs.Int("siswa", 30, "Number of students per rombel")
+	jumlahHari := fs.Int("hari", 180, "Number of school days (Monday-Friday) to seed")
+	mulaiStr := fs.String("mulai", "2025-07-14", "First school day (YYYY-MM-DD)")
+	iterations := fs.Int("iterations", 5, "Runs per query, the average is reported")
+	fs.Parse(args)
+
+	mulai, err := time.Parse("2006-01-02", *mulaiStr)
+	if err != nil {
+		fmt.Println("Error: invalid --mulai, use YYYY-MM-DD")
+		return
+	}
+	if *jumlahRombel < 1 || *siswaPerRombel < 1 || *jumlahHari < 1 || *iterations < 1 {
+		fmt.Println("Error: --rombel, --siswa, --hari and --iterations must be at least 1")
+		return
+	}
+
+	// Walk forward until the requested number of weekdays is covered
+	selesai := mulai
+	for hari := 0; ; selesai = selesai.AddDate(0, 0, 1) {
+		if selesai.Weekday() != time.Saturday && selesai.Weekday() != time.Sunday {
+			hari++
+		}
+		if hari == *jumlahHari {
+			break
+		}
+	}
+
+	db, err := openDatabase()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		fmt.Printf("Seeding %d rombel x %d siswa x %d hari (%s s/d %s)...\n",
+			*jumlahRombel, *siswaPerRombel, *jumlahHari, mulai.Format("2006-01-02"), selesai.Format("2006-01-02"))
+
+		started := time.Now()
+		tahunPelajaranID, totalRows, err := seedAbsensiBenchmark(tx, *jumlahRombel, *siswaPerRombel, mulai, selesai)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Seeded %d rekapitulasi_absensi rows in %s\n\n", totalRows, time.Since(started).Round(time.Millisecond))
+
+		repository := repositories.NewAbsensiRepository(tx)
+		filter := repositories.AbsensiAggregateFilter{
+			TahunPelajaranID: tahunPelajaranID,
+			TanggalMulai:     &mulai,
+			TanggalSelesai:   &selesai,
+		}
+
+		fmt.Printf("%-22s %14s %14s %9s %s\n", "query", "legacy", "aggregate", "speedup", "parity")
+		for _, benchmark := range dashboardBenchmarks(repository, filter) {
+			legacyDuration, legacySum, err := timeBenchmark(benchmark.legacy, *iterations)
+			if err != nil {
+				return fmt.Errorf("%s (legacy): %w", benchmark.name, err)
+			}
+			aggregateDuration, aggregateSum, err := timeBenchmark(benchmark.aggregate, *iterations)
+			if err != nil {
+				return fmt.Errorf("%s (aggregate): %w", benchmark.name, err)
+			}
+
+			parity := "ok"
+			if legacySum != aggregateSum {
+				parity = fmt.Sprintf("MISMATCH legacy=%s aggregate=%s", legacySum, aggregateSum)
+			}
+			speedup := float64(legacyDuration) / float64(aggregateDuration)
+			fmt.Printf("%-22s %14s %14s %8.1fx %s\n", benchmark.name,
+				legacyDuration.Round(time.Microsecond), aggregateDuration.Round(time.Microsecond), speedup, parity)
+		}
+
+		fmt.Println("\nRolling back seeded data")
+		return errBenchmarkRollback
+	})
+	if err != nil && !errors.Is(err, errBenchmarkRollback) {
+		fmt.Printf("Error: %v\n", err)
+	}
+}
+
+// seedAbsensiBenchmark inserts a tahun pelajaran, kelas, rombel, peserta didik and one guru kelas
+// rekap row per student per weekday with roughly 85% hadir and 5% each of sakit, izin and alpa
+func seedAbsensiBenchmark(tx *gorm.DB, jumlahRombel, siswaPerRombel int, mulai, selesai time.Time) (uint, int64, error) {
+	tag := fmt.Sprintf("BN%d", time.Now().Unix()%100000)
+
+	var tahunPelajaranID uint
+	if err := tx.Raw("INSERT INTO tahun_pelajaran (tahun_pelajaran, status) VALUES (?, 'inactive') RETURNING id", tag).
+		Scan(&tahunPelajaranID).Error; err != nil {
+		return 0, 0, fmt.Errorf("seed tahun_pelajaran: %w", err)
+	}
+
+	var kelasID uint
+	if err := tx.Raw("INSERT INTO kelas (name, status) VALUES (?, 'inactive') RETURNING id", tag).
+		Scan(&kelasID).Error; err != nil {
+		return 0, 0, fmt.Errorf("seed kelas: %w", err)
+	}
+
+	steps := []struct {
+		name string
+		sql  string
+		args []interface{}
+	}{
+		{
+			name: "rombel",
+			sql: `INSERT INTO rombel (name, status, kelas_id)
+				SELECT ? || '-' || g, 'inactive', ? FROM generate_series(1, ?) g`,
+			args: []interface{}{tag, kelasID, jumlahRombel},
+		},
+		{
+			name: "peserta_didik",
+			sql: `INSERT INTO peserta_didik (nama, nis, jenis_kelamin, nisn, status)
+				SELECT 'Siswa ' || ? || ' ' || g, ? || lpad(g::text, 6, '0'),
+					CASE WHEN g % 2 = 0 THEN 'L' ELSE 'P' END, ? || lpad(g::text, 10, '0'), 'inactive'
+				FROM generate_series(1, ?) g`,
+			args: []interface{}{tag, tag, tag, jumlahRombel * siswaPerRombel},
+		},
+		{
+			name: "peserta_didik_rombel",
+			sql: `INSERT INTO peserta_didik_rombel (peserta_didik_id, rombel_id, tahun_pelajaran_id, status)
+				SELECT pd.id, rb.id, ?, 'active'
+				FROM (SELECT id, row_number() OVER (ORDER BY id) - 1 AS n FROM peserta_didik WHERE nis LIKE ? || '%') pd
+				JOIN (SELECT id, row_number() OVER (ORDER BY id) - 1 AS n FROM rombel WHERE kelas_id = ?) rb
+					ON rb.n = pd.n / ?`,
+			args: []interface{}{tahunPelajaranID, tag, kelasID, siswaPerRombel},
+		},
+		{
+			name: "rekapitulasi_absensi",
+			sql: `INSERT INTO rekapitulasi_absensi
+					(peserta_didik_rombel_id, rombel_id, tahun_pelajaran_id, semester, tanggal, status, metode_input)
+				SELECT s.id, s.rombel_id, s.tahun_pelajaran_id,
+					CASE WHEN EXTRACT(MONTH FROM s.tanggal) <= 6 THEN 2 ELSE 1 END, s.tanggal,
+					CASE WHEN s.x < 0.85 THEN 'hadir' WHEN s.x < 0.90 THEN 'sakit' WHEN s.x < 0.95 THEN 'izin' ELSE 'alpa' END,
+					'manual'
+				FROM (
+					SELECT prd.id, prd.rombel_id, prd.tahun_pelajaran_id, d::date AS tanggal, random() AS x
+					FROM peserta_didik_rombel prd
+					CROSS JOIN generate_series(?::date, ?::date, interval '1 day') d
+					WHERE prd.tahun_pelajaran_id = ? AND EXTRACT(ISODOW FROM d) < 6
+				) s`,
+			args: []interface{}{mulai.Format("2006-01-02"), selesai.Format("2006-01-02"), tahunPelajaranID},
+		},
+		{
+			name: "analyze",
+			sql:  "ANALYZE rekapitulasi_absensi",
+		},
+	}
+
+	var totalRows int64
+	for _, step := range steps {
+		result := tx.Exec(step.sql, step.args...)
+		if result.Error != nil {
+			return 0, 0, fmt.Errorf("seed %s: %w", step.name, result.Error)
+		}
+		if step.name == "rekapitulasi_absensi" {
+			totalRows = result.RowsAffected
+		}
+	}
+
+	return tahunPelajaranID, totalRows, nil
+}
+
+// dashboardBenchmarks pairs the pre-aggregation dashboard logic with the repository GROUP BY queries
+func dashboardBenchmarks(repository repositories.AbsensiRepository, filter repositories.AbsensiAggregateFilter) []dashboardBenchmark {
+	return []dashboardBenchmark{
+		{
+			name: "summary (harian)",
+			legacy: func() (string, error) {
+				rows, err := repository.GetDashboardSummary(filter.TahunPelajaranID, filter.RombelID, filter.Semester, filter.BidangStudiID, filter.TanggalMulai, filter.TanggalSelesai)
+				if err != nil {
+					return "", err
+				}
+				counts := make(map[string]map[string]int)
+				for _, row := range rows {
+					key := row.Tanggal.Format("2006-01-02")
+					if counts[key] == nil {
+						counts[key] = make(map[string]int)
+					}
+					counts[key][row.Status]++
+				}
+				return periodeChecksum(counts), nil
+			},
+			aggregate: func() (string, error) {
+				rows, err := repository.CountStatusPerPeriode(filter, "day")
+				if err != nil {
+					return "", err
+				}
+				return periodeCountChecksum(rows, "2006-01-02"), nil
+			},
+		},
+		{
+			name: "grafik (mingguan)",
+			legacy: func() (string, error) {
+				rows, err := repository.GetDashboardSummary(filter.TahunPelajaranID, filter.RombelID, filter.Semester, filter.BidangStudiID, filter.TanggalMulai, filter.TanggalSelesai)
+				if err != nil {
+					return "", err
+				}
+				counts := make(map[string]map[string]int)
+				for _, row := range rows {
+					weekStart := row.Tanggal
+					for weekStart.Weekday() != time.Monday {
+						weekStart = weekStart.AddDate(0, 0, -1)
+					}
+					key := weekStart.Format("2006-01-02")
+					if counts[key] == nil {
+						counts[key] = make(map[string]int)
+					}
+					counts[key][row.Status]++
+				}
+				return periodeChecksum(counts), nil
+			},
+			aggregate: func() (string, error) {
+				rows, err := repository.CountStatusPerPeriode(filter, "week")
+				if err != nil {
+					return "", err
+				}
+				return periodeCountChecksum(rows, "2006-01-02"), nil
+			},
+		},
+		{
+			name: "grafik (bulanan)",
+			legacy: func() (string, error) {
+				rows, err := repository.GetDashboardSummary(filter.TahunPelajaranID, filter.RombelID, filter.Semester, filter.BidangStudiID, filter.TanggalMulai, filter.TanggalSelesai)
+				if err != nil {
+					return "", err
+				}
+				counts := make(map[string]map[string]int)
+				for _, row := range rows {
+					key := row.Tanggal.Format("2006-01")
+					if counts[key] == nil {
+						counts[key] = make(map[string]int)
+					}
+					counts[key][row.Status]++
+				}
+				return periodeChecksum(counts), nil
+			},
+			aggregate: func() (string, error) {
+				rows, err := repository.CountStatusPerPeriode(filter, "month")
+				if err != nil {
+					return "", err
+				}
+				return periodeCountChecksum(rows, "2006-01"), nil
+			},
+		},
+		{
+			name: "perbandingan rombel",
+			legacy: func() (string, error) {
+				rows, err := repository.GetPerbandinganRombel(filter.TahunPelajaranID, filter.Semester, filter.BidangStudiID, filter.TanggalMulai, filter.TanggalSelesai)
+				if err != nil {
+					return "", err
+				}
+				hadir := make(map[uint]int)
+				siswa := make(map[uint]map[uint]bool)
+				for _, row := range rows {
+					if row.RombelID == nil {
+						continue
+					}
+					if siswa[*row.RombelID] == nil {
+						siswa[*row.RombelID] = make(map[uint]bool)
+					}
+					if row.PesertaDidikRombel != nil && row.PesertaDidikRombel.PesertaDidik != nil {
+						siswa[*row.RombelID][row.PesertaDidikRombel.PesertaDidik.ID] = true
+					}
+					if row.Status == "hadir" {
+						hadir[*row.RombelID]++
+					}
+				}
+				var parts []string
+				for rombelID := range siswa {
+					parts = append(parts, fmt.Sprintf("%d:%d/%d", rombelID, hadir[rombelID], len(siswa[rombelID])))
+				}
+				sort.Strings(parts)
+				return checksum(parts), nil
+			},
+			aggregate: func() (string, error) {
+				rows, err := repository.CountStatusPerRombel(filter)
+				if err != nil {
+					return "", err
+				}
+				var parts []string
+				for _, row := range rows {
+					parts = append(parts, fmt.Sprintf("%d:%d/%d", row.RombelID, row.Hadir, row.TotalSiswa))
+				}
+				sort.Strings(parts)
+				return checksum(parts), nil
+			},
+		},
+		{
+			name: "siswa terendah (10)",
+			legacy: func() (string, error) {
+				rows, err := repository.GetSiswaTerendah(filter.TahunPelajaranID, filter.RombelID, filter.Semester, filter.BidangStudiID, filter.TanggalMulai, filter.TanggalSelesai)
+				if err != nil {
+					return "", err
+				}
+				hadir := make(map[uint]int)
+				total := make(map[uint]int)
+				for _, row := range rows {
+					if row.PesertaDidikRombel == nil || row.PesertaDidikRombel.PesertaDidik == nil {
+						continue
+					}
+					id := row.PesertaDidikRombel.PesertaDidik.ID
+					total[id]++
+					if row.Status == "hadir" {
+						hadir[id]++
+					}
+				}
+				var persentase []float64
+				for id := range total {
+					persentase = append(persentase, float64(hadir[id])/float64(total[id]))
+				}
+				sort.Float64s(persentase)
+				if len(persentase) > 10 {
+					persentase = persentase[:10]
+				}
+				return persentaseChecksum(persentase), nil
+			},
+			aggregate: func() (string, error) {
+				rows, err := repository.CountStatusPerSiswa(filter, 10)
+				if err != nil {
+					return "", err
+				}
+				var persentase []float64
+				for _, row := range rows {
+					persentase = append(persentase, float64(row.Hadir)/float64(row.Total()))
+				}
+				return persentaseChecksum(persentase), nil
+			},
+		},
+	}
+}
+
+// timeBenchmark runs fn the given number of times and returns the average duration and the last checksum
+func timeBenchmark(fn func() (string, error), iterations int) (time.Duration, string, error) {
+	var total time.Duration
+	var sum string
+	for i := 0; i < iterations; i++ {
+		started := time.Now()
+		result, err := fn()
+		if err != nil {
+			return 0, "", err
+		}
+		total += time.Since(started)
+		sum = result
+	}
+	return total / time.Duration(iterations), sum, nil
+}
+
+// periodeChecksum summarises status counts keyed by periode
+func periodeChecksum(counts map[string]map[string]int) string {
+	var parts []string
+	for periode, status := range counts {
+		parts = append(parts, fmt.Sprintf("%s:%d/%d/%d/%d", periode, status["hadir"], status["sakit"], status["izin"], status["alpa"]))
+	}
+	sort.Strings(parts)
+	return checksum(parts)
+}
+
+// periodeCountChecksum summarises repository periode counts in the same format as periodeChecksum
+func periodeCountChecksum(rows []repositories.AbsensiPeriodeCount, layout string) string {
+	var parts []string
+	for _, row := range rows {
+		parts = append(parts, fmt.Sprintf("%s:%d/%d/%d/%d", row.Periode.Format(layout), row.Hadir, row.Sakit, row.Izin, row.Alpa))
+	}
+	sort.Strings(parts)
+	return checksum(parts)
+}
+
+// persentaseChecksum summarises an ascending list of attendance ratios
+func persentaseChecksum(persentase []float64) string {
+	parts := make([]string, 0, len(persentase))
+	for _, p := range persentase {
+		parts = append(parts, fmt.Sprintf("%.4f", p))
+	}
+	return checksum(parts)
+}
+
+// checksum keeps the parity output short: number of groups plus an FNV-1a hash of the parts
+func checksum(parts []string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(strings.Join(parts, ";")))
+	return fmt.Sprintf("%d#%08x", len(parts), hash.Sum32())
+}
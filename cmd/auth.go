@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"pintu-backend/src/modules/repositories"
+)
+
+// authPruneTokens deletes expired refresh tokens, denylisted access tokens and stale failed attempts
+func authPruneTokens(args []string) {
+	db, err := openDatabase()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	deleted, err := repositories.NewAuthTokenRepository(db).DeleteExpired()
+	if err != nil {
+		fmt.Printf("Error pruning tokens: %v\n", err)
+		return
+	}
+	fmt.Printf("Pruned %d expired token rows\n", deleted)
+
+	// Throttling windows are far shorter than a day
+	attempts, err := repositories.NewFailedAttemptRepository(db).DeleteOlderThan(time.Now().Add(-24 * time.Hour))
+	if err != nil {
+		fmt.Printf("Error pruning failed attempts: %v\n", err)
+		return
+	}
+	fmt.Printf("Pruned %d failed attempt rows\n", attempts)
+}
@@ -83,6 +83,16 @@ func main() {
 		seedRun(args)
 	case "seed:specific":
 		seedSpecific(args)
+	case "auth:prune-tokens":
+		authPruneTokens(args)
+	case "absensi:mark-alpa":
+		absensiMarkAlpa(args)
+	case "absensi:benchmark-dashboard":
+		absensiBenchmarkDashboard(args)
+	case "storage:migrate-private":
+		storageMigratePrivate(args)
+	case "storage:gc":
+		storageGC(args)
 	default:
 		fmt.Printf("Unknown command: %s\n\n", command)
 		printUsage()
@@ -107,6 +117,16 @@ Commands:
   migrate:file <filename>         Run specific migration file
   seed:run                        Run all seeders
   seed:specific <seeder>          Run specific seeder (permission|role|role_permission|user)
+  auth:prune-tokens               Delete expired tokens and failed login attempts older than a day
+  absensi:mark-alpa               Mark students without scan or izin/sakit as alpa
+                                  [--tanggal YYYY-MM-DD] [--sampai YYYY-MM-DD] [--dry-run] [--json]
+  absensi:benchmark-dashboard     Compare dashboard queries before/after SQL aggregation on seeded data
+                                  (rolled back) [--rombel N] [--siswa N] [--hari N] [--iterations N]
+  storage:migrate-private         Move existing documents of private upload directories to the private prefix
+                                  [--dry-run] [--keep-old]
+  storage:gc                      Report (or delete) uploaded objects no row references, and objects of rows
+                                  soft-deleted longer than the retention [--delete] [--grace 24h]
+                                  [--retention 720h] [--dir artikel]
 
 Examples:
   go run ./cmd generate:migration create_users_table
@@ -119,6 +139,11 @@ Examples:
   go run ./cmd migrate:file 20260206094811_create_users_table.sql
   go run ./cmd seed:run
   go run ./cmd seed:specific permission
+  go run ./cmd auth:prune-tokens
+  go run ./cmd absensi:mark-alpa --tanggal 2026-10-16 --dry-run
+  go run ./cmd absensi:benchmark-dashboard --rombel 24 --siswa 30 --hari 180
+  go run ./cmd storage:migrate-private --dry-run
+  go run ./cmd storage:gc --grace 48h
 	`)
 }
 
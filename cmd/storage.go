@@ -0,0 +1,455 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/utils"
+
+	"gorm.io/gorm"
+)
+
+// Formats of columns holding storage keys
+const (
+	storageColumnKey       = "key"        // a single key
+	storageColumnKeys      = "keys"       // JSON array of keys
+	storageColumnFileItems = "file_items" // JSON array of models.FileItem, keys in "url" and the variant urls
+	storageColumnVariants  = "variants"   // JSON object of models.FileVariant by variant name
+)
+
+// storageColumn is a column holding storage keys
+type storageColumn struct {
+	table  string
+	column string
+	format string
+}
+
+// storageColumns are all columns holding storage keys. A new column holding uploads must be added here, or
+// storage:gc reports its objects as orphans.
+var storageColumns = []storageColumn{
+	{"activity_galleries", "foto", storageColumnFileItems},
+	{"announcements", "gambar", storageColumnKey},
+	{"announcements", "files", storageColumnFileItems},
+	{"articles", "gambar", storageColumnKey},
+	{"articles", "gambar_variants", storageColumnVariants},
+	{"articles", "files", storageColumnFileItems},
+	{"jumbotron", "file", storageColumnKey},
+	{"jumbotron", "file_variants", storageColumnVariants},
+	{"kelulusan", "skl", storageColumnKey},
+	{"kepegawaian", "foto", storageColumnKey},
+	{"kepegawaian", "kk", storageColumnKey},
+	{"kepegawaian", "akta_lahir", storageColumnKey},
+	{"kepegawaian", "ktp", storageColumnKey},
+	{"kepegawaian", "ijazah_sd", storageColumnKey},
+	{"kepegawaian", "ijazah_smp", storageColumnKey},
+	{"kepegawaian", "ijazah_sma", storageColumnKey},
+	{"kepegawaian", "ijazah_s1", storageColumnKey},
+	{"kepegawaian", "ijazah_s2", storageColumnKey},
+	{"kepegawaian", "ijazah_s3", storageColumnKey},
+	{"kepegawaian", "sertifikat_pendidik", storageColumnKey},
+	{"kepegawaian", "sertifikat_lainnya", storageColumnKeys},
+	{"kepegawaian", "sk", storageColumnKey},
+	{"kepegawaian", "dokumen_lainnya", storageColumnKeys},
+	{"konfigurasi_mutasi_siswa", "template_sptjm", storageColumnKey},
+	{"kutipan_kepsek", "foto_kepsek", storageColumnKey},
+	{"mutasi_siswa", "rapor", storageColumnKey},
+	{"mutasi_siswa", "akte_kelahiran", storageColumnKey},
+	{"mutasi_siswa", "kartu_keluarga", storageColumnKey},
+	{"mutasi_siswa", "sptjm", storageColumnKey},
+	{"pengaduan", "file_pengaduan", storageColumnFileItems},
+	{"pengaduan", "file_jawaban", storageColumnFileItems},
+	{"pengaduan", "file_tindak_lanjut", storageColumnFileItems},
+	{"pengajuan_izin", "file_surat", storageColumnKey},
+	{"pengumuman_kelulusan", "foto_kepsek", storageColumnKey},
+	{"pengumuman_kelulusan", "ttd_kepsek", storageColumnKey},
+	{"pertanyaan", "file_pertanyaan", storageColumnFileItems},
+	{"pertanyaan", "file_jawaban", storageColumnFileItems},
+	{"peserta_didik", "photo", storageColumnKey},
+	{"prestasi", "foto", storageColumnFileItems},
+	{"rekapitulasi_absensi", "file_surat", storageColumnKey},
+	{"riwayat_rekapitulasi_absensi", "file_surat_sebelum", storageColumnKey},
+	{"riwayat_rekapitulasi_absensi", "file_surat_sesudah", storageColumnKey},
+	{"sarana_prasarana", "foto", storageColumnKey},
+}
+
+// storageDirectories are the top-level upload directories, listed by storage:gc with and without the private prefix
+var storageDirectories = []string{
+	"absensi-siswa",
+	"artikel",
+	"galeri-kegiatan",
+	"jumbotron",
+	"kelulusan-skl",
+	"kepegawaian",
+	"kepsek",
+	"layanan-umpan-balik",
+	"mutasi-siswa",
+	"pengumuman",
+	"pengumuman-kelulusan",
+	"peserta-didik",
+	"prestasi",
+	"sarpras",
+}
+
+// storageMigratePrivate moves objects of private upload directories stored before the private prefix existed
+// to utils.StoragePrivatePrefix and points the database at the new keys. Old objects are deleted only after
+// every column has been updated, so a key shared by several rows (rekap and its riwayat) keeps working.
+func storageMigratePrivate(args []string) {
+	fs := flag.NewFlagSet("storage:migrate-private", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "Only report, do not copy objects or update rows")
+	keepOld := fs.Bool("keep-old", false, "Keep the objects at their old key after moving")
+	fs.Parse(args)
+
+	db, err := openDatabase()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	migrator := &privateStorageMigrator{
+		db:      db,
+		storage: utils.NewStorage(),
+		dryRun:  *dryRun,
+		moved:   make(map[string]string),
+		failed:  make(map[string]bool),
+		keep:    make(map[string]bool),
+	}
+
+	for _, column := range storageColumns {
+		// Images with variants are never in a private directory
+		if column.format == storageColumnVariants {
+			continue
+		}
+		updated, err := migrator.migrateColumn(column)
+		if err != nil {
+			fmt.Printf("%s.%s: error: %v\n", column.table, column.column, err)
+			continue
+		}
+		if *dryRun {
+			fmt.Printf("%s.%s: %d rows to update\n", column.table, column.column, updated)
+			continue
+		}
+		fmt.Printf("%s.%s: %d rows updated\n", column.table, column.column, updated)
+	}
+
+	deleted := 0
+	if !*dryRun && !*keepOld {
+		oldKeys := make([]string, 0, len(migrator.moved))
+		for oldKey := range migrator.moved {
+			oldKeys = append(oldKeys, oldKey)
+		}
+		sort.Strings(oldKeys)
+
+		for _, oldKey := range oldKeys {
+			if migrator.keep[oldKey] {
+				continue
+			}
+			if err := migrator.storage.DeleteFile(oldKey); err != nil {
+				fmt.Printf("  delete %s: %v\n", oldKey, err)
+				continue
+			}
+			deleted++
+		}
+	}
+
+	if *dryRun {
+		fmt.Printf("Dry run: %d objects would move, %d missing or failing\n", len(migrator.moved), len(migrator.failed))
+		return
+	}
+	fmt.Printf("Moved %d objects (%d old objects deleted), %d missing or failing\n", len(migrator.moved), deleted, len(migrator.failed))
+}
+
+// privateStorageMigrator copies each private object once and remembers its new key
+type privateStorageMigrator struct {
+	db      *gorm.DB
+	storage utils.Storage
+	dryRun  bool
+	moved   map[string]string // old key -> new key
+	failed  map[string]bool   // keys that could not be copied, left as they are
+	keep    map[string]bool   // old keys still referenced by a row that failed to update
+}
+
+// migrateColumn rewrites the keys of one column and returns the number of updated rows
+func (m *privateStorageMigrator) migrateColumn(column storageColumn) (int, error) {
+	var rows []struct {
+		ID    uint
+		Value string
+	}
+	query := fmt.Sprintf("SELECT id, %s::text AS value FROM %s WHERE %s IS NOT NULL", column.column, column.table, column.column)
+	if err := m.db.Raw(query).Scan(&rows).Error; err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, row := range rows {
+		value, oldKeys, err := m.rewrite(column.format, row.Value)
+		if err != nil {
+			fmt.Printf("  %s.%s id %d: %v\n", column.table, column.column, row.ID, err)
+			continue
+		}
+		if len(oldKeys) == 0 {
+			continue
+		}
+		if m.dryRun {
+			updated++
+			continue
+		}
+
+		var expr interface{} = value
+		if column.format != storageColumnKey {
+			expr = gorm.Expr("?::jsonb", value)
+		}
+		if err := m.db.Table(column.table).Where("id = ?", row.ID).UpdateColumn(column.column, expr).Error; err != nil {
+			fmt.Printf("  %s.%s id %d: %v\n", column.table, column.column, row.ID, err)
+			for _, oldKey := range oldKeys {
+				m.keep[oldKey] = true
+			}
+			continue
+		}
+		updated++
+	}
+	return updated, nil
+}
+
+// rewrite replaces the private keys of a column value and returns the new value with the replaced old keys
+func (m *privateStorageMigrator) rewrite(format, value string) (string, []string, error) {
+	var oldKeys []string
+	move := func(key string) string {
+		newKey, ok := m.move(key)
+		if ok {
+			oldKeys = append(oldKeys, key)
+		}
+		return newKey
+	}
+
+	switch format {
+	case storageColumnKeys:
+		var keys []string
+		if err := json.Unmarshal([]byte(value), &keys); err != nil {
+			return "", nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		for i := range keys {
+			keys[i] = move(keys[i])
+		}
+		out, err := json.Marshal(keys)
+		return string(out), oldKeys, err
+	case storageColumnFileItems:
+		// Decoded as raw fields so everything other than url is kept as it is
+		var items []map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(value), &items); err != nil {
+			return "", nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		for _, item := range items {
+			var key string
+			if err := json.Unmarshal(item["url"], &key); err != nil {
+				continue
+			}
+			item["url"], _ = json.Marshal(move(key))
+		}
+		out, err := json.Marshal(items)
+		return string(out), oldKeys, err
+	default:
+		return move(value), oldKeys, nil
+	}
+}
+
+// move copies a legacy private object to the private prefix and returns its new key
+func (m *privateStorageMigrator) move(key string) (string, bool) {
+	if key == "" || strings.HasPrefix(key, utils.StoragePrivatePrefix) || !utils.IsPrivateStorageKey(key) {
+		return key, false
+	}
+	if newKey, ok := m.moved[key]; ok {
+		return newKey, true
+	}
+	if m.failed[key] {
+		return key, false
+	}
+
+	newKey := utils.StoragePrivatePrefix + key
+	if m.dryRun {
+		fmt.Printf("  %s -> %s\n", key, newKey)
+	} else if err := m.storage.CopyFile(key, newKey); err != nil {
+		fmt.Printf("  copy %s: %v\n", key, err)
+		m.failed[key] = true
+		return key, false
+	}
+	m.moved[key] = newKey
+	return newKey, true
+}
+
+// storageGC reports objects of the upload directories that no row references and, with --delete, deletes them.
+// Objects younger than the grace period are skipped since their row may not be saved yet. Objects referenced only
+// by rows soft-deleted longer than the retention window are purged as well.
+func storageGC(args []string) {
+	fs := flag.NewFlagSet("storage:gc", flag.ExitOnError)
+	deleteObjects := fs.Bool("delete", false, "Delete the reported objects instead of only reporting them")
+	grace := fs.Duration("grace", 24*time.Hour, "Skip unreferenced objects modified more recently than this")
+	retention := fs.Duration("retention", 30*24*time.Hour, "Purge objects of rows soft-deleted longer ago than this")
+	directory := fs.String("dir", "", "Only collect this top-level directory")
+	fs.Parse(args)
+
+	directories := storageDirectories
+	if *directory != "" {
+		directories = []string{strings.Trim(*directory, "/")}
+	}
+
+	db, err := openDatabase()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	storage := utils.NewStorage()
+
+	now := time.Now()
+	refs, err := collectStorageReferences(db, now.Add(-*retention))
+	if err != nil {
+		// Deleting with an incomplete picture of the references would delete files in use
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	var orphanCount, purgeCount, deleted int
+	var orphanBytes, purgeBytes int64
+	for _, dir := range directories {
+		var objects []utils.StorageObjectInfo
+		for _, prefix := range []string{dir + "/", utils.StoragePrivatePrefix + dir + "/"} {
+			listed, err := storage.List(prefix)
+			if err != nil {
+				fmt.Printf("Error: %s: %v\n", prefix, err)
+				return
+			}
+			objects = append(objects, listed...)
+		}
+
+		var referenced, recent int
+		for _, object := range objects {
+			reason := ""
+			switch {
+			case refs.live[object.Key]:
+				referenced++
+				continue
+			case refs.expired[object.Key]:
+				reason = "soft-deleted"
+				purgeCount++
+				purgeBytes += object.Size
+			case now.Sub(object.LastModified) < *grace:
+				recent++
+				continue
+			default:
+				reason = "orphan"
+				orphanCount++
+				orphanBytes += object.Size
+			}
+
+			fmt.Printf("  %s %s (%d bytes, %s)\n", reason, object.Key, object.Size, object.LastModified.Format("2006-01-02 15:04"))
+			if !*deleteObjects {
+				continue
+			}
+			if err := storage.DeleteFile(object.Key); err != nil {
+				fmt.Printf("  delete %s: %v\n", object.Key, err)
+				continue
+			}
+			deleted++
+		}
+		fmt.Printf("%s: %d objects, %d referenced, %d within grace period\n", dir, len(objects), referenced, recent)
+	}
+
+	fmt.Printf("Orphans: %d objects (%d bytes), soft-deleted rows: %d objects (%d bytes)\n", orphanCount, orphanBytes, purgeCount, purgeBytes)
+	if *deleteObjects {
+		fmt.Printf("Deleted %d objects\n", deleted)
+		return
+	}
+	fmt.Println("Report only, run again with --delete to delete these objects")
+}
+
+// storageReferences are the keys referenced by the database
+type storageReferences struct {
+	live    map[string]bool // referenced by a row that is not soft-deleted or within the retention window
+	expired map[string]bool // referenced only by rows soft-deleted before the retention window
+}
+
+// collectStorageReferences reads the keys of every storage column. Keys of rows soft-deleted before
+// deletedBefore count as expired unless another row still references them.
+func collectStorageReferences(db *gorm.DB, deletedBefore time.Time) (*storageReferences, error) {
+	refs := &storageReferences{live: make(map[string]bool), expired: make(map[string]bool)}
+	softDelete := make(map[string]bool)
+
+	for _, column := range storageColumns {
+		hasDeletedAt, ok := softDelete[column.table]
+		if !ok {
+			hasDeletedAt = db.Migrator().HasColumn(column.table, "deleted_at")
+			softDelete[column.table] = hasDeletedAt
+		}
+
+		deletedAt := "NULL::timestamptz"
+		if hasDeletedAt {
+			deletedAt = "deleted_at"
+		}
+		var rows []struct {
+			Value     string
+			DeletedAt *time.Time
+		}
+		query := fmt.Sprintf("SELECT %s::text AS value, %s AS deleted_at FROM %s WHERE %s IS NOT NULL",
+			column.column, deletedAt, column.table, column.column)
+		if err := db.Raw(query).Scan(&rows).Error; err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", column.table, column.column, err)
+		}
+
+		for _, row := range rows {
+			keys, err := storageColumnKeysOf(column.format, row.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", column.table, column.column, err)
+			}
+			expired := row.DeletedAt != nil && row.DeletedAt.Before(deletedBefore)
+			for _, key := range keys {
+				if expired {
+					refs.expired[key] = true
+				} else {
+					refs.live[key] = true
+				}
+			}
+		}
+	}
+
+	for key := range refs.live {
+		delete(refs.expired, key)
+	}
+	return refs, nil
+}
+
+// storageColumnKeysOf returns the keys held by a column value
+func storageColumnKeysOf(format, value string) ([]string, error) {
+	var keys []string
+	switch format {
+	case storageColumnKeys:
+		if err := json.Unmarshal([]byte(value), &keys); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+	case storageColumnFileItems:
+		var items []models.FileItem
+		if err := json.Unmarshal([]byte(value), &items); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		for _, item := range items {
+			keys = append(keys, item.URL)
+			for _, variant := range item.Variants {
+				keys = append(keys, variant.URL)
+			}
+		}
+	case storageColumnVariants:
+		var variants map[string]models.FileVariant
+		if err := json.Unmarshal([]byte(value), &variants); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		for _, variant := range variants {
+			keys = append(keys, variant.URL)
+		}
+	default:
+		keys = []string{value}
+	}
+	return keys, nil
+}
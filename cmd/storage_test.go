@@ -0,0 +1,48 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestStorageColumnKeysOf(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{"single key", storageColumnKey, "uploads/foto.jpg", []string{"uploads/foto.jpg"}, false},
+		{"key array", storageColumnKeys, `["a.pdf","b.pdf"]`, []string{"a.pdf", "b.pdf"}, false},
+		{"empty key array", storageColumnKeys, `[]`, []string{}, false},
+		{"invalid key array", storageColumnKeys, `a.pdf`, nil, true},
+		{
+			"file items with variants", storageColumnFileItems,
+			`[{"id":"1","url":"a.jpg","variants":{"thumbnail":{"url":"a_thumb.jpg"},"medium":{"url":"a_medium.jpg"}}},{"id":"2","url":"b.pdf"}]`,
+			[]string{"a.jpg", "a_medium.jpg", "a_thumb.jpg", "b.pdf"}, false,
+		},
+		{"invalid file items", storageColumnFileItems, `{"url":"a.jpg"}`, nil, true},
+		{
+			"variants", storageColumnVariants,
+			`{"thumbnail":{"url":"a_thumb.jpg"},"original":{"url":"a.jpg"}}`,
+			[]string{"a.jpg", "a_thumb.jpg"}, false,
+		},
+		{"invalid variants", storageColumnVariants, `["a.jpg"]`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := storageColumnKeysOf(tt.format, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("storageColumnKeysOf() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			// Variants come from a map, so their order is not fixed
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("storageColumnKeysOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"log"
 	"os"
 
+	"pintu-backend/src/jobs"
 	"pintu-backend/src/middleware"
 	"pintu-backend/src/routes"
+	"pintu-backend/src/utils"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -39,6 +41,11 @@ func main() {
 	// Create router
 	router := gin.Default()
 
+	// Only proxies in TRUSTED_PROXIES may set the client IP used by device IP allowlists and throttles
+	if err := router.SetTrustedProxies(utils.TrustedProxies()); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	// Setup CORS middleware
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowOrigins = []string{
@@ -96,6 +103,8 @@ func main() {
 	routes.RegisterStrukturOrganisasiRoutes(router, db)
 	routes.RegisterPesertaDidikRoutes(router, db)
 	routes.RegisterPesertaDidikRombelRoutes(router, db)
+	routes.RegisterKenaikanKelasRoutes(router, db)
+	routes.RegisterSiswaPortalRoutes(router, db)
 	routes.RegisterPrestasiRoutes(router, db)
 	routes.RegisterApplicationRoutes(router, db)
 	routes.RegisterKritikSaranRoutes(router, db)
@@ -103,11 +112,23 @@ func main() {
 	routes.RegisterPengaduanRoutes(router, db)
 	routes.RegisterAbsensiRoutes(router, db)
 	routes.RegisterAbsensiScanRoutes(router, db)
+	routes.RegisterAbsensiLiveRoutes(router, db)
+	routes.RegisterScannerDeviceRoutes(router, db)
 	routes.RegisterKonfigurasiAbsensiRoutes(router, db)
+	routes.RegisterJadwalAbsensiRoutes(router, db)
+	routes.RegisterKalenderAkademikRoutes(router, db)
+	routes.RegisterNotifikasiRoutes(router, db)
+	routes.RegisterPengajuanIzinRoutes(router, db)
+	routes.RegisterAbsensiPegawaiRoutes(router, db)
 	routes.RegisterKelulusanRoutes(router, db)
 	routes.RegisterPengumumanKelulusanRoutes(router, db)
 	routes.RegisterLayananSPMBRoutes(router, db)
 	routes.RegisterMutasiSiswaRoutes(router, db)
+	routes.RegisterStorageRoutes(router, db)
+
+	// Background jobs
+	jobs.StartAbsensiAlpaJob(db)
+	jobs.StartNotifikasiOutboxJob(db)
 
 	// Start server
 	port := os.Getenv("PORT")
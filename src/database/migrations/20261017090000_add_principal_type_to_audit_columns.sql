@@ -0,0 +1,48 @@
+-- Migration: add_principal_type_to_audit_columns
+-- Created: 2026-10-17 09:00:00
+-- Description: Record which principal table (user / pegawai / siswa) an audit ID points to.
+-- Existing rows keep NULL because the source table cannot be recovered reliably.
+
+BEGIN;
+
+ALTER TABLE systems ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE roles ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE permissions ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE tahun_pelajaran ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE bidang_studi ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE kelas ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE rombel ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE ekstrakurikuler ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE jumbotron ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE kutipan_kepsek ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE visi_misi ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE sarana_prasarana ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE announcements ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE activity_galleries ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE contacts ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE kepegawaian ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE struktur_organisasi ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE peserta_didik ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE peserta_didik_rombel ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE prestasi ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE anggota_tim_prestasi ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE kelulusan ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE pengumuman_kelulusan ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE setting_layanan_spmb ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+ALTER TABLE konfigurasi_mutasi_siswa ADD COLUMN IF NOT EXISTS created_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS updated_by_type VARCHAR(20);
+
+-- replied_by / deleted_by_id may now point at kepegawaian, so the users FK no longer applies
+ALTER TABLE pengaduan DROP CONSTRAINT IF EXISTS pengaduan_replied_by_fkey;
+ALTER TABLE pengaduan DROP CONSTRAINT IF EXISTS pengaduan_deleted_by_id_fkey;
+ALTER TABLE pengaduan ADD COLUMN IF NOT EXISTS replied_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS deleted_by_type VARCHAR(20);
+
+ALTER TABLE pertanyaan DROP CONSTRAINT IF EXISTS pertanyaan_replied_by_fkey;
+ALTER TABLE pertanyaan DROP CONSTRAINT IF EXISTS pertanyaan_deleted_by_id_fkey;
+ALTER TABLE pertanyaan ADD COLUMN IF NOT EXISTS replied_by_type VARCHAR(20), ADD COLUMN IF NOT EXISTS deleted_by_type VARCHAR(20);
+
+ALTER TABLE rekapitulasi_absensi ADD COLUMN IF NOT EXISTS dicatat_oleh_type VARCHAR(20);
+
+COMMIT;
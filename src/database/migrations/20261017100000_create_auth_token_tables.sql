@@ -0,0 +1,35 @@
+-- Migration: create_auth_token_tables
+-- Created: 2026-10-17 10:00:00
+-- Description: Rotating refresh tokens and the access token (jti) denylist.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id SERIAL PRIMARY KEY,
+    principal_type VARCHAR(20) NOT NULL,
+    principal_id INTEGER NOT NULL,
+    role_id INTEGER,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    access_jti VARCHAR(64) NOT NULL,
+    access_expires_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    replaced_by_id INTEGER REFERENCES refresh_tokens(id) ON DELETE SET NULL,
+    user_agent VARCHAR(255),
+    ip_address VARCHAR(45),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_principal ON refresh_tokens(principal_type, principal_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_access_jti ON refresh_tokens(access_jti);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_expires_at ON refresh_tokens(expires_at);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti VARCHAR(64) PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- Migration: add_code_to_systems_table
+-- Created: 2026-10-17 11:00:00
+-- Description: Stable system code so PINTU is looked up by code instead of a hard-coded ID.
+
+BEGIN;
+
+ALTER TABLE systems ADD COLUMN IF NOT EXISTS code VARCHAR(50);
+
+UPDATE systems SET code = UPPER(nama) WHERE code IS NULL;
+
+ALTER TABLE systems ALTER COLUMN code SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_systems_code ON systems(code);
+
+COMMIT;
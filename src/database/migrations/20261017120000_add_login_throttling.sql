@@ -0,0 +1,27 @@
+-- Migration: add_login_throttling
+-- Created: 2026-10-17 12:00:00
+-- Description: Failed attempt log for sliding-window throttling and temporary account lockout columns.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS failed_attempts (
+    id BIGSERIAL PRIMARY KEY,
+    scope VARCHAR(50) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    identifier VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_failed_attempts_ip ON failed_attempts(scope, ip_address, created_at);
+CREATE INDEX IF NOT EXISTS idx_failed_attempts_identifier ON failed_attempts(scope, identifier, created_at);
+CREATE INDEX IF NOT EXISTS idx_failed_attempts_created_at ON failed_attempts(created_at);
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS failed_login_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP;
+
+ALTER TABLE kepegawaian
+    ADD COLUMN IF NOT EXISTS failed_login_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP;
+
+COMMIT;
@@ -0,0 +1,13 @@
+-- Migration: add_login_columns_to_peserta_didik
+-- Created: 2026-10-17 13:00:00
+-- Description: Lockout columns and username lookup index for student login.
+
+BEGIN;
+
+ALTER TABLE peserta_didik
+    ADD COLUMN IF NOT EXISTS failed_login_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_peserta_didik_username ON peserta_didik(username) WHERE deleted_at IS NULL;
+
+COMMIT;
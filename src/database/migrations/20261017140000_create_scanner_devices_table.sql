@@ -0,0 +1,37 @@
+-- Migration: create_scanner_devices_table
+-- Created: 2026-10-17 14:00:00
+-- Description: Registered attendance scanner devices (API key or HMAC) and the device that recorded each absensi row.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS scanner_devices (
+    id SERIAL PRIMARY KEY,
+    nama VARCHAR(100) NOT NULL,
+    lokasi VARCHAR(255),
+    auth_mode VARCHAR(20) NOT NULL DEFAULT 'hmac',
+    key_hash VARCHAR(64),
+    hmac_secret VARCHAR(255),
+    allowed_cidrs JSONB NOT NULL DEFAULT '[]',
+    jam_aktif_mulai TIME,
+    jam_aktif_selesai TIME,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    last_seen_at TIMESTAMP,
+    last_seen_ip VARCHAR(45),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by_id INTEGER,
+    created_by_type VARCHAR(20),
+    updated_by_id INTEGER,
+    updated_by_type VARCHAR(20),
+    deleted_at TIMESTAMP,
+    CONSTRAINT chk_scanner_devices_auth_mode CHECK (auth_mode IN ('api_key', 'hmac'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_scanner_devices_deleted_at ON scanner_devices(deleted_at);
+
+ALTER TABLE absensi
+    ADD COLUMN IF NOT EXISTS scanner_device_id INTEGER REFERENCES scanner_devices(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_absensi_scanner_device_id ON absensi(scanner_device_id);
+
+COMMIT;
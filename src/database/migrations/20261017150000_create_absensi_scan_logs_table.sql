@@ -0,0 +1,22 @@
+-- Migration: create_absensi_scan_logs_table
+-- Created: 2026-10-17 15:00:00
+-- Description: Results of offline batch scans per device and client scan ID, so replayed uploads are idempotent.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS absensi_scan_logs (
+    id BIGSERIAL PRIMARY KEY,
+    scanner_device_id INTEGER NOT NULL REFERENCES scanner_devices(id) ON DELETE CASCADE,
+    client_scan_id VARCHAR(100) NOT NULL,
+    barcode VARCHAR(255) NOT NULL,
+    scanned_at TIMESTAMP NOT NULL,
+    peserta_didik_id INTEGER REFERENCES peserta_didik(id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_absensi_scan_logs_device_client UNIQUE (scanner_device_id, client_scan_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_absensi_scan_logs_created_at ON absensi_scan_logs(created_at);
+
+COMMIT;
@@ -0,0 +1,40 @@
+-- Migration: create_kalender_akademik_table
+-- Created: 2026-10-17 16:00:00
+-- Description: School calendar (holidays and school events) and the weekdays that are school days.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS kalender_akademik (
+    id SERIAL PRIMARY KEY,
+    tahun_pelajaran_id INTEGER REFERENCES tahun_pelajaran(id) ON DELETE CASCADE,
+    jenis VARCHAR(30) NOT NULL,
+    nama VARCHAR(255) NOT NULL,
+    tanggal_mulai DATE NOT NULL,
+    tanggal_selesai DATE NOT NULL,
+    semester INTEGER,
+    libur BOOLEAN NOT NULL DEFAULT FALSE,
+    keterangan TEXT,
+    sumber VARCHAR(20) NOT NULL DEFAULT 'manual',
+    external_uid VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by_id INTEGER,
+    created_by_type VARCHAR(20),
+    updated_by_id INTEGER,
+    updated_by_type VARCHAR(20),
+    deleted_at TIMESTAMP,
+    CONSTRAINT chk_kalender_akademik_jenis CHECK (jenis IN ('libur_nasional', 'libur_sekolah', 'kegiatan_sekolah')),
+    CONSTRAINT chk_kalender_akademik_tanggal CHECK (tanggal_selesai >= tanggal_mulai),
+    CONSTRAINT chk_kalender_akademik_semester CHECK (semester IS NULL OR semester IN (1, 2))
+);
+
+CREATE INDEX IF NOT EXISTS idx_kalender_akademik_tanggal ON kalender_akademik(tanggal_mulai, tanggal_selesai);
+CREATE INDEX IF NOT EXISTS idx_kalender_akademik_tahun_pelajaran ON kalender_akademik(tahun_pelajaran_id);
+CREATE INDEX IF NOT EXISTS idx_kalender_akademik_deleted_at ON kalender_akademik(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_kalender_akademik_external_uid ON kalender_akademik(external_uid) WHERE external_uid IS NOT NULL AND deleted_at IS NULL;
+
+-- ISO weekdays (1 = Senin ... 7 = Minggu) on which students attend school
+ALTER TABLE konfigurasi_absensi
+    ADD COLUMN IF NOT EXISTS hari_sekolah VARCHAR(20) NOT NULL DEFAULT '1,2,3,4,5';
+
+COMMIT;
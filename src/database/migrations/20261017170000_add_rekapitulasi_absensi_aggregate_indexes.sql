@@ -0,0 +1,27 @@
+-- Migration: add_rekapitulasi_absensi_aggregate_indexes
+-- Created: 2026-10-17 17:00:00
+-- Description: Covering indexes for the dashboard GROUP BY queries on rekapitulasi_absensi.
+-- Guru kelas and guru mapel rows are split into partial indexes since every dashboard query
+-- filters on bidang_studi_id IS NULL or on one bidang studi.
+
+BEGIN;
+
+-- Guru kelas, whole school: summary, grafik and perbandingan rombel
+CREATE INDEX IF NOT EXISTS idx_rekap_agg_kelas_tp_tanggal
+    ON rekapitulasi_absensi(tahun_pelajaran_id, tanggal)
+    INCLUDE (status, rombel_id, peserta_didik_rombel_id, semester)
+    WHERE bidang_studi_id IS NULL AND deleted_at IS NULL;
+
+-- Guru kelas, one rombel: summary, grafik and siswa terendah
+CREATE INDEX IF NOT EXISTS idx_rekap_agg_kelas_tp_rombel_tanggal
+    ON rekapitulasi_absensi(tahun_pelajaran_id, rombel_id, tanggal)
+    INCLUDE (status, peserta_didik_rombel_id, semester)
+    WHERE bidang_studi_id IS NULL AND deleted_at IS NULL;
+
+-- Guru mapel, per bidang studi (optionally one rombel)
+CREATE INDEX IF NOT EXISTS idx_rekap_agg_mapel_tp_bidang_tanggal
+    ON rekapitulasi_absensi(tahun_pelajaran_id, bidang_studi_id, tanggal)
+    INCLUDE (status, rombel_id, peserta_didik_rombel_id, semester)
+    WHERE bidang_studi_id IS NOT NULL AND deleted_at IS NULL;
+
+COMMIT;
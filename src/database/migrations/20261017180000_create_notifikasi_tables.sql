@@ -0,0 +1,71 @@
+-- Migration: create_notifikasi_tables
+-- Created: 2026-10-17 18:00:00
+-- Description: Parent contact and opt-in on peserta_didik, message templates per event/channel and the
+-- notification outbox that is retried until the email/WhatsApp gateway accepts the message.
+
+BEGIN;
+
+ALTER TABLE peserta_didik
+    ADD COLUMN IF NOT EXISTS nomor_hp_ortu VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS email_ortu VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS notifikasi_ortu BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS notifikasi_template (
+    id SERIAL PRIMARY KEY,
+    event VARCHAR(30) NOT NULL,    -- 'terlambat', 'alpa'
+    channel VARCHAR(20) NOT NULL,  -- 'email', 'whatsapp'
+    subject VARCHAR(255),
+    body TEXT NOT NULL,            -- Go text/template
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by_id INTEGER,
+    created_by_type VARCHAR(20),
+    updated_by_id INTEGER,
+    updated_by_type VARCHAR(20),
+    CONSTRAINT unique_notifikasi_template UNIQUE (event, channel)
+);
+
+INSERT INTO notifikasi_template (event, channel, subject, body) VALUES
+    ('terlambat', 'whatsapp', NULL,
+     'Yth. {{.NamaOrtu}}, ananda {{.Nama}} ({{.Rombel}}) tercatat datang terlambat pada {{.Tanggal}} pukul {{.Jam}} WIB. Terima kasih.'),
+    ('terlambat', 'email', 'Pemberitahuan Keterlambatan - {{.Nama}}',
+     'Yth. {{.NamaOrtu}},
+
+Ananda {{.Nama}} (NIS {{.NIS}}, {{.Rombel}}) tercatat datang terlambat pada {{.Tanggal}} pukul {{.Jam}} WIB.
+
+Terima kasih.'),
+    ('alpa', 'whatsapp', NULL,
+     'Yth. {{.NamaOrtu}}, ananda {{.Nama}} ({{.Rombel}}) tidak tercatat hadir pada {{.Tanggal}} tanpa keterangan. Mohon hubungi wali kelas bila ananda sakit atau izin.'),
+    ('alpa', 'email', 'Pemberitahuan Ketidakhadiran - {{.Nama}}',
+     'Yth. {{.NamaOrtu}},
+
+Ananda {{.Nama}} (NIS {{.NIS}}, {{.Rombel}}) tidak tercatat hadir pada {{.Tanggal}} tanpa keterangan.
+Mohon hubungi wali kelas bila ananda sakit atau izin.
+
+Terima kasih.')
+ON CONFLICT (event, channel) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS notifikasi_outbox (
+    id SERIAL PRIMARY KEY,
+    event VARCHAR(30) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    peserta_didik_id INTEGER REFERENCES peserta_didik(id) ON DELETE SET NULL,
+    recipient VARCHAR(255) NOT NULL,
+    subject VARCHAR(255),
+    body TEXT NOT NULL,
+    dedupe_key VARCHAR(150) NOT NULL UNIQUE,  -- event:peserta_didik_id:tanggal:channel
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',  -- 'pending', 'sent', 'failed'
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    sent_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifikasi_outbox_due ON notifikasi_outbox(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_notifikasi_outbox_status_created ON notifikasi_outbox(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_notifikasi_outbox_peserta_didik ON notifikasi_outbox(peserta_didik_id);
+
+COMMIT;
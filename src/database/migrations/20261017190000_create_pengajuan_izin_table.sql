@@ -0,0 +1,43 @@
+-- Migration: create_pengajuan_izin_table
+-- Created: 2026-10-17 19:00:00
+-- Description: Izin/sakit leave requests submitted by parents (public form) or students (siswa portal),
+-- reviewed by the wali kelas. Approval writes rekapitulasi_absensi rows with metode_input 'pengajuan'.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS pengajuan_izin (
+    id SERIAL PRIMARY KEY,
+    id_tiket VARCHAR(30) NOT NULL UNIQUE,
+    peserta_didik_id INTEGER NOT NULL REFERENCES peserta_didik(id),
+    peserta_didik_rombel_id INTEGER NOT NULL REFERENCES peserta_didik_rombel(id),
+    rombel_id INTEGER NOT NULL REFERENCES rombel(id),
+    tahun_pelajaran_id INTEGER NOT NULL REFERENCES tahun_pelajaran(id),
+    jenis VARCHAR(20) NOT NULL,             -- 'izin', 'sakit'
+    tanggal_mulai DATE NOT NULL,
+    tanggal_selesai DATE NOT NULL,
+    alasan TEXT NOT NULL,
+    file_surat TEXT,
+    nama_pengaju VARCHAR(255) NOT NULL,
+    telepon_pengaju VARCHAR(20),
+    diajukan_melalui VARCHAR(20) NOT NULL,  -- 'publik', 'siswa'
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',  -- 'pending', 'approved', 'rejected'
+    catatan_review TEXT,
+    jumlah_hari_tercatat INTEGER NOT NULL DEFAULT 0,
+    reviewed_by_id INTEGER,
+    reviewed_by_type VARCHAR(20),
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    CONSTRAINT check_pengajuan_izin_tanggal CHECK (tanggal_selesai >= tanggal_mulai)
+);
+
+-- Approval queue of a wali kelas
+CREATE INDEX IF NOT EXISTS idx_pengajuan_izin_rombel_status ON pengajuan_izin(rombel_id, status) WHERE deleted_at IS NULL;
+
+-- Overlap check per student
+CREATE INDEX IF NOT EXISTS idx_pengajuan_izin_peserta_didik_tanggal ON pengajuan_izin(peserta_didik_id, tanggal_mulai, tanggal_selesai) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_pengajuan_izin_deleted_at ON pengajuan_izin(deleted_at);
+
+COMMIT;
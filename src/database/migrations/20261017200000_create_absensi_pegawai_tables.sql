@@ -0,0 +1,41 @@
+-- Migration: create_absensi_pegawai_tables
+-- Created: 2026-10-17 20:00:00
+-- Description: Daily check-in/check-out of guru and tendik: barcode on kepegawaian, a separate time window
+-- configuration and one absensi_pegawai row per pegawai per day.
+
+BEGIN;
+
+ALTER TABLE kepegawaian
+    ADD COLUMN IF NOT EXISTS barcode VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS barcode_generated_at TIMESTAMP;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_kepegawaian_barcode ON kepegawaian(barcode) WHERE barcode IS NOT NULL AND barcode <> '';
+
+CREATE TABLE IF NOT EXISTS konfigurasi_absensi_pegawai (
+    id SERIAL PRIMARY KEY,
+    jam_datang_mulai TIME NOT NULL,
+    jam_max_datang TIME NOT NULL,
+    jam_datang_selesai TIME NOT NULL,
+    jam_pulang_mulai TIME NOT NULL,
+    jam_pulang_selesai TIME NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS absensi_pegawai (
+    id SERIAL PRIMARY KEY,
+    kepegawaian_id INTEGER NOT NULL REFERENCES kepegawaian(id),
+    tanggal DATE NOT NULL,
+    jam_datang TIME,
+    jam_pulang TIME,
+    status VARCHAR(20),  -- 'tepat_waktu', 'terlambat'
+    scanner_device_id INTEGER REFERENCES scanner_devices(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_absensi_pegawai_tanggal UNIQUE (kepegawaian_id, tanggal)
+);
+
+-- Monthly rekap and exports read one month for every pegawai
+CREATE INDEX IF NOT EXISTS idx_absensi_pegawai_tanggal ON absensi_pegawai(tanggal);
+
+COMMIT;
@@ -0,0 +1,69 @@
+-- Migration: create_jadwal_absensi_tables
+-- Created: 2026-10-17 21:00:00
+-- Description: Attendance schedule profiles with weekday overrides, assigned to all students, a kelas or a rombel,
+-- optionally only for a date range (e.g. Ramadan). konfigurasi_absensi stays the default when nothing applies.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS profil_jadwal_absensi (
+    id SERIAL PRIMARY KEY,
+    nama VARCHAR(100) NOT NULL,
+    keterangan TEXT,
+    jam_datang_mulai TIME NOT NULL,
+    jam_max_datang TIME NOT NULL,
+    jam_datang_selesai TIME NOT NULL,
+    jam_pulang_mulai TIME NOT NULL,
+    jam_pulang_selesai TIME NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by_id INTEGER,
+    created_by_type VARCHAR(20),
+    updated_by_id INTEGER,
+    updated_by_type VARCHAR(20)
+);
+
+-- Per weekday override of a profile; NULL times fall back to the profile times
+CREATE TABLE IF NOT EXISTS profil_jadwal_absensi_hari (
+    id SERIAL PRIMARY KEY,
+    profil_jadwal_absensi_id INTEGER NOT NULL REFERENCES profil_jadwal_absensi(id) ON DELETE CASCADE,
+    hari SMALLINT NOT NULL CHECK (hari BETWEEN 1 AND 7), -- ISO weekday, 1 = Senin ... 7 = Minggu
+    jam_datang_mulai TIME,
+    jam_max_datang TIME,
+    jam_datang_selesai TIME,
+    jam_pulang_mulai TIME,
+    jam_pulang_selesai TIME,
+    CONSTRAINT unique_profil_jadwal_absensi_hari UNIQUE (profil_jadwal_absensi_id, hari)
+);
+
+-- Which students use a profile: kelas_id, rombel_id or neither (all students).
+-- With tanggal_mulai/tanggal_selesai it is a special schedule that wins over the regular one in that range.
+CREATE TABLE IF NOT EXISTS penugasan_jadwal_absensi (
+    id SERIAL PRIMARY KEY,
+    profil_jadwal_absensi_id INTEGER NOT NULL REFERENCES profil_jadwal_absensi(id),
+    kelas_id INTEGER REFERENCES kelas(id) ON DELETE CASCADE,
+    rombel_id INTEGER REFERENCES rombel(id) ON DELETE CASCADE,
+    tanggal_mulai DATE,
+    tanggal_selesai DATE,
+    keterangan TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by_id INTEGER,
+    created_by_type VARCHAR(20),
+    updated_by_id INTEGER,
+    updated_by_type VARCHAR(20),
+    CONSTRAINT check_penugasan_jadwal_absensi_scope CHECK (kelas_id IS NULL OR rombel_id IS NULL),
+    CONSTRAINT check_penugasan_jadwal_absensi_tanggal CHECK (
+        (tanggal_mulai IS NULL AND tanggal_selesai IS NULL)
+        OR (tanggal_mulai IS NOT NULL AND tanggal_selesai IS NOT NULL AND tanggal_selesai >= tanggal_mulai)
+    )
+);
+
+-- One regular (undated) schedule per scope
+CREATE UNIQUE INDEX IF NOT EXISTS idx_penugasan_jadwal_absensi_reguler
+    ON penugasan_jadwal_absensi (COALESCE(kelas_id, 0), COALESCE(rombel_id, 0))
+    WHERE tanggal_mulai IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_penugasan_jadwal_absensi_profil ON penugasan_jadwal_absensi(profil_jadwal_absensi_id);
+
+COMMIT;
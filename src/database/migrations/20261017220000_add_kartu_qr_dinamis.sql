@@ -0,0 +1,26 @@
+-- Migration: add_kartu_qr_dinamis
+-- Created: 2026-10-17 22:00:00
+-- Description: Optional rotating HMAC-signed QR on the kartu pelajar: the scan mode on konfigurasi absensi
+--              and the nonces of signed payloads already scanned, to reject replays.
+
+BEGIN;
+
+-- statis: only the printed barcode, campuran: printed barcode or signed QR, dinamis: signed QR only
+ALTER TABLE konfigurasi_absensi
+    ADD COLUMN IF NOT EXISTS mode_kartu_qr VARCHAR(20) NOT NULL DEFAULT 'statis';
+
+ALTER TABLE konfigurasi_absensi
+    DROP CONSTRAINT IF EXISTS chk_konfigurasi_absensi_mode_kartu_qr;
+ALTER TABLE konfigurasi_absensi
+    ADD CONSTRAINT chk_konfigurasi_absensi_mode_kartu_qr CHECK (mode_kartu_qr IN ('statis', 'campuran', 'dinamis'));
+
+CREATE TABLE IF NOT EXISTS kartu_qr_nonce (
+    nonce VARCHAR(64) PRIMARY KEY,
+    peserta_didik_id INTEGER NOT NULL REFERENCES peserta_didik(id) ON DELETE CASCADE,
+    used_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_kartu_qr_nonce_expires_at ON kartu_qr_nonce(expires_at);
+
+COMMIT;
@@ -0,0 +1,37 @@
+-- Migration: add_semester_dates_to_tahun_pelajaran
+-- Created: 2026-10-17 23:00:00
+-- Description: Start and end date of both semesters on tahun pelajaran, used to map a date to its tahun pelajaran
+--              and semester. Backfilled from the name "YYYY/YYYY": semester 1 = 1 Juli - 31 Desember,
+--              semester 2 = 1 Januari - 30 Juni.
+
+BEGIN;
+
+ALTER TABLE tahun_pelajaran
+    ADD COLUMN IF NOT EXISTS semester1_mulai DATE,
+    ADD COLUMN IF NOT EXISTS semester1_selesai DATE,
+    ADD COLUMN IF NOT EXISTS semester2_mulai DATE,
+    ADD COLUMN IF NOT EXISTS semester2_selesai DATE;
+
+-- Existing tahun pelajaran from their name, the same months the code used to infer the semester from
+UPDATE tahun_pelajaran
+SET semester1_mulai = make_date(split_part(tahun_pelajaran, '/', 1)::INTEGER, 7, 1),
+    semester1_selesai = make_date(split_part(tahun_pelajaran, '/', 1)::INTEGER, 12, 31)
+WHERE semester1_mulai IS NULL AND tahun_pelajaran ~ '^[0-9]{4}/[0-9]{4}$';
+
+UPDATE tahun_pelajaran
+SET semester2_mulai = make_date(split_part(tahun_pelajaran, '/', 2)::INTEGER, 1, 1),
+    semester2_selesai = make_date(split_part(tahun_pelajaran, '/', 2)::INTEGER, 6, 30)
+WHERE semester2_mulai IS NULL AND tahun_pelajaran ~ '^[0-9]{4}/[0-9]{4}$';
+
+ALTER TABLE tahun_pelajaran
+    DROP CONSTRAINT IF EXISTS chk_tahun_pelajaran_semester;
+ALTER TABLE tahun_pelajaran
+    ADD CONSTRAINT chk_tahun_pelajaran_semester CHECK (
+        semester1_selesai >= semester1_mulai
+        AND semester2_mulai > semester1_selesai
+        AND semester2_selesai >= semester2_mulai
+    ) NOT VALID;
+
+CREATE INDEX IF NOT EXISTS idx_tahun_pelajaran_semester ON tahun_pelajaran(semester1_mulai, semester2_selesai);
+
+COMMIT;
@@ -0,0 +1,46 @@
+-- Migration: create_kenaikan_kelas_tables
+-- Created: 2026-10-18 00:00:00
+-- Description: Academic year rollover (kenaikan kelas): one row per processed rollover and one detail row per
+--              student with the decision (naik, tinggal kelas, lulus), so the rollover can be undone the same day.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS kenaikan_kelas (
+    id SERIAL PRIMARY KEY,
+    tahun_pelajaran_asal_id INTEGER NOT NULL REFERENCES tahun_pelajaran(id),
+    tahun_pelajaran_tujuan_id INTEGER NOT NULL REFERENCES tahun_pelajaran(id),
+    tahun_pelajaran_aktif_sebelumnya_id INTEGER REFERENCES tahun_pelajaran(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'diproses',
+    total_naik INTEGER NOT NULL DEFAULT 0,
+    total_tinggal_kelas INTEGER NOT NULL DEFAULT 0,
+    total_lulus INTEGER NOT NULL DEFAULT 0,
+    diproses_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dibatalkan_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_by_id INTEGER,
+    created_by_type VARCHAR(20),
+    updated_by_id INTEGER,
+    updated_by_type VARCHAR(20),
+    CONSTRAINT chk_kenaikan_kelas_status CHECK (status IN ('diproses', 'dibatalkan')),
+    CONSTRAINT chk_kenaikan_kelas_tahun_pelajaran CHECK (tahun_pelajaran_asal_id <> tahun_pelajaran_tujuan_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_kenaikan_kelas_tahun_pelajaran ON kenaikan_kelas(tahun_pelajaran_asal_id, tahun_pelajaran_tujuan_id);
+
+CREATE TABLE IF NOT EXISTS kenaikan_kelas_detail (
+    id SERIAL PRIMARY KEY,
+    kenaikan_kelas_id INTEGER NOT NULL REFERENCES kenaikan_kelas(id) ON DELETE CASCADE,
+    peserta_didik_id INTEGER NOT NULL REFERENCES peserta_didik(id) ON DELETE CASCADE,
+    peserta_didik_rombel_asal_id INTEGER NOT NULL REFERENCES peserta_didik_rombel(id) ON DELETE CASCADE,
+    rombel_asal_id INTEGER NOT NULL REFERENCES rombel(id),
+    keputusan VARCHAR(20) NOT NULL,
+    rombel_tujuan_id INTEGER REFERENCES rombel(id),
+    peserta_didik_rombel_tujuan_id INTEGER REFERENCES peserta_didik_rombel(id) ON DELETE SET NULL,
+    status_peserta_didik_sebelumnya VARCHAR(20) NOT NULL,
+    CONSTRAINT chk_kenaikan_kelas_detail_keputusan CHECK (keputusan IN ('naik', 'tinggal_kelas', 'lulus'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_kenaikan_kelas_detail_kenaikan_kelas ON kenaikan_kelas_detail(kenaikan_kelas_id);
+
+COMMIT;
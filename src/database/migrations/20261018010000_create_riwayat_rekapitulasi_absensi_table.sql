@@ -0,0 +1,39 @@
+-- Migration: create_riwayat_rekapitulasi_absensi_table
+-- Created: 2026-10-18 01:00:00
+-- Description: Append-only history of every rekapitulasi_absensi mutation (before/after values, actor, source and
+--              request IP), so a disputed status can be traced back to who changed it and when. Rows are never
+--              updated or deleted and outlive the rekap row they describe, hence no foreign key to it.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS riwayat_rekapitulasi_absensi (
+    id BIGSERIAL PRIMARY KEY,
+    rekapitulasi_absensi_id INTEGER NOT NULL,
+    peserta_didik_rombel_id INTEGER NOT NULL,
+    tanggal DATE NOT NULL,
+    bidang_studi_id INTEGER,
+    aksi VARCHAR(20) NOT NULL,
+    sumber VARCHAR(20) NOT NULL,
+    status_sebelum VARCHAR(20),
+    status_sesudah VARCHAR(20),
+    metode_input_sebelum VARCHAR(20),
+    metode_input_sesudah VARCHAR(20),
+    keterangan_sebelum TEXT,
+    keterangan_sesudah TEXT,
+    file_surat_sebelum TEXT,
+    file_surat_sesudah TEXT,
+    waktu_absen_sebelum TIMESTAMP,
+    waktu_absen_sesudah TIMESTAMP,
+    actor_id INTEGER,
+    actor_type VARCHAR(20),
+    actor_nama VARCHAR(255),
+    ip_address VARCHAR(45),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT chk_riwayat_rekap_aksi CHECK (aksi IN ('create', 'update', 'delete')),
+    CONSTRAINT chk_riwayat_rekap_sumber CHECK (sumber IN ('manual', 'sync', 'import', 'pengajuan', 'alpa_otomatis'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_riwayat_rekap_siswa_tanggal ON riwayat_rekapitulasi_absensi(peserta_didik_rombel_id, tanggal);
+CREATE INDEX IF NOT EXISTS idx_riwayat_rekap_rekapitulasi ON riwayat_rekapitulasi_absensi(rekapitulasi_absensi_id);
+
+COMMIT;
@@ -0,0 +1,13 @@
+-- Migration: add_image_variants_columns
+-- Created: 2026-10-18 02:00:00
+-- Description: Resized variants (thumbnail, medium, original) of the single image of articles and jumbotron,
+--              stored as {"thumbnail": {"url", "width", "height", "size"}, ...}. Images in the JSON arrays
+--              (articles.files, activity_galleries.foto, prestasi.foto) keep their variants per item instead.
+--              NULL for images uploaded before variants existed.
+
+BEGIN;
+
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS gambar_variants JSONB;
+ALTER TABLE jumbotron ADD COLUMN IF NOT EXISTS file_variants JSONB;
+
+COMMIT;
@@ -11,16 +11,19 @@ func SystemSeeder(db *gorm.DB) error {
 	system := []models.System{
 		{
 			Nama:        "PINTU",
+			Code:        "PINTU",
 			Description: "Portal Informasi Terpadu",
 			Status:      "active",
 		},
 		{
 			Nama:        "SIEKSA",
+			Code:        "SIEKSA",
 			Description: "Sistem Informasi Ekstrakurikuler Sukapura Satu",
 			Status:      "active",
 		},
 		{
 			Nama:        "SIPERSA",
+			Code:        "SIPERSA",
 			Description: "Sistem Informasi Perpustakaan Sukapura Satu",
 			Status:      "active",
 		},
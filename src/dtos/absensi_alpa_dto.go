@@ -0,0 +1,39 @@
+package dtos
+
+// AbsensiAlpaRunRequest represents the input of one automatic alpa marking run
+type AbsensiAlpaRunRequest struct {
+	Tanggal string `json:"tanggal"` // YYYY-MM-DD, default hari ini (Asia/Jakarta)
+	DryRun  bool   `json:"dry_run"` // Only report, do not write rekap rows
+	Trigger string `json:"trigger"` // scheduler or cli, for the report
+}
+
+// AbsensiAlpaReport represents the report of one automatic alpa marking run
+type AbsensiAlpaReport struct {
+	Tanggal               string                  `json:"tanggal"`
+	Trigger               string                  `json:"trigger"`
+	DryRun                bool                    `json:"dry_run"`
+	HariSekolah           bool                    `json:"hari_sekolah"`
+	Keterangan            string                  `json:"keterangan,omitempty"` // Why the date was skipped
+	TahunPelajaranID      uint                    `json:"tahun_pelajaran_id,omitempty"`
+	Semester              int                     `json:"semester,omitempty"`
+	TotalSiswa            int                     `json:"total_siswa"`
+	TotalSudahTercatat    int                     `json:"total_sudah_tercatat"`     // Already hadir/izin/sakit/alpa in rekap
+	TotalScanBelumSinkron int                     `json:"total_scan_belum_sinkron"` // Scanned but not synchronized to rekap yet
+	TotalAlpa             int                     `json:"total_alpa"`               // Written (or would be written on dry run)
+	TotalGagal            int                     `json:"total_gagal"`
+	StartedAt             string                  `json:"started_at"`
+	FinishedAt            string                  `json:"finished_at"`
+	Message               string                  `json:"message"`
+	Details               []AbsensiAlpaDetailItem `json:"details,omitempty"`
+}
+
+// AbsensiAlpaDetailItem represents one peserta didik marked (or to be marked) alpa
+type AbsensiAlpaDetailItem struct {
+	PesertaDidikRombelID uint   `json:"peserta_didik_rombel_id"`
+	PesertaDidikID       uint   `json:"peserta_didik_id"`
+	NIS                  string `json:"nis"`
+	Nama                 string `json:"nama"`
+	Rombel               string `json:"rombel"`
+	Action               string `json:"action"` // inserted, dry_run, skipped, failed
+	Reason               string `json:"reason,omitempty"`
+}
@@ -48,25 +48,26 @@ type AbsensiCreateErrorItem struct {
 
 // AbsensiResponse represents the response for a single absensi record
 type AbsensiResponse struct {
-	ID               uint   `json:"id"`
-	PesertaDidikID   uint   `json:"peserta_didik_id"`
-	PesertaDidikNama string `json:"peserta_didik_nama,omitempty"`
-	RombelID         *uint  `json:"rombel_id"`
-	RombelNama       string `json:"rombel_nama,omitempty"`
-	TahunPelajaranID uint   `json:"tahun_pelajaran_id"`
-	Semester         int    `json:"semester"`
-	Tanggal          string `json:"tanggal"`
-	BidangStudiID    *uint  `json:"bidang_studi_id"`
-	BidangStudiNama  string `json:"bidang_studi_nama,omitempty"`
-	PertemuanKe      *int   `json:"pertemuan_ke"`
-	Status           string `json:"status"`
-	WaktuAbsen       string `json:"waktu_absen,omitempty"`
-	MetodeInput      string `json:"metode_input"`
-	Keterangan       string `json:"keterangan,omitempty"`
-	FileSurat        string `json:"file_surat,omitempty"`
-	DicatatOlehID    *uint  `json:"dicatat_oleh_id"`
-	CreatedAt        string `json:"created_at"`
-	UpdatedAt        string `json:"updated_at"`
+	ID               uint    `json:"id"`
+	PesertaDidikID   uint    `json:"peserta_didik_id"`
+	PesertaDidikNama string  `json:"peserta_didik_nama,omitempty"`
+	RombelID         *uint   `json:"rombel_id"`
+	RombelNama       string  `json:"rombel_nama,omitempty"`
+	TahunPelajaranID uint    `json:"tahun_pelajaran_id"`
+	Semester         int     `json:"semester"`
+	Tanggal          string  `json:"tanggal"`
+	BidangStudiID    *uint   `json:"bidang_studi_id"`
+	BidangStudiNama  string  `json:"bidang_studi_nama,omitempty"`
+	PertemuanKe      *int    `json:"pertemuan_ke"`
+	Status           string  `json:"status"`
+	WaktuAbsen       string  `json:"waktu_absen,omitempty"`
+	MetodeInput      string  `json:"metode_input"`
+	Keterangan       string  `json:"keterangan,omitempty"`
+	FileSurat        string  `json:"file_surat,omitempty"`
+	DicatatOlehID    *uint   `json:"dicatat_oleh_id"`
+	DicatatOlehType  *string `json:"dicatat_oleh_type"`
+	CreatedAt        string  `json:"created_at"`
+	UpdatedAt        string  `json:"updated_at"`
 }
 
 // AbsensiRekapRequest represents the request for attendance recap
@@ -99,16 +100,17 @@ type AbsensiRekapSiswa struct {
 
 // AbsensiDetailTanggal represents attendance detail for a specific date
 type AbsensiDetailTanggal struct {
-	ID            uint   `json:"id"`
-	Tanggal       string `json:"tanggal"`
-	PertemuanKe   *int   `json:"pertemuan_ke,omitempty"` // Only for guru mapel (bidang_studi_id not null)
-	Status        string `json:"status"`
-	WaktuAbsen    string `json:"waktu_absen,omitempty"`
-	MetodeInput   string `json:"metode_input"`
-	Keterangan    string `json:"keterangan"`
-	FileSurat     string `json:"file_surat,omitempty"`
-	DicatatOleh   string `json:"dicatat_oleh"`
-	DicatatOlehID *uint  `json:"dicatat_oleh_id,omitempty"`
+	ID              uint    `json:"id"`
+	Tanggal         string  `json:"tanggal"`
+	PertemuanKe     *int    `json:"pertemuan_ke,omitempty"` // Only for guru mapel (bidang_studi_id not null)
+	Status          string  `json:"status"`
+	WaktuAbsen      string  `json:"waktu_absen,omitempty"`
+	MetodeInput     string  `json:"metode_input"`
+	Keterangan      string  `json:"keterangan"`
+	FileSurat       string  `json:"file_surat,omitempty"`
+	DicatatOleh     string  `json:"dicatat_oleh"`
+	DicatatOlehID   *uint   `json:"dicatat_oleh_id,omitempty"`
+	DicatatOlehType *string `json:"dicatat_oleh_type,omitempty"`
 }
 
 // AbsensiRekapResponse represents the response for attendance recap
@@ -157,6 +159,7 @@ type DashboardSummaryRequest struct {
 type DashboardSummaryResponse struct {
 	TotalSiswa      int                    `json:"total_siswa"`
 	TotalPertemuan  int                    `json:"total_pertemuan"`
+	TotalHariEfektif int                   `json:"total_hari_efektif"` // School days in range per kalender akademik
 	Summary         SummaryKehadiran       `json:"summary"`
 	Trend           *TrendKehadiran        `json:"trend,omitempty"`
 }
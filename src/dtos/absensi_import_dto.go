@@ -0,0 +1,15 @@
+package dtos
+
+// AbsensiImportResponse represents the response for importing rekap absensi from Excel
+type AbsensiImportResponse struct {
+	SuccessCount int                  `json:"success_count"`
+	UpdatedCount int                  `json:"updated_count"`
+	FailedCount  int                  `json:"failed_count"`
+	Errors       []AbsensiImportError `json:"errors,omitempty"`
+}
+
+// AbsensiImportError represents an error for a specific row during import
+type AbsensiImportError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
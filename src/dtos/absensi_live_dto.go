@@ -0,0 +1,39 @@
+package dtos
+
+// AbsensiLiveRequest represents the request for the live attendance feed and counters
+type AbsensiLiveRequest struct {
+	RombelID *uint `json:"rombel_id"` // Empty for all rombel
+}
+
+// AbsensiLiveScanEvent is streamed as "event: scan" for every successful scan of today
+type AbsensiLiveScanEvent struct {
+	PesertaDidikID  uint   `json:"peserta_didik_id"`
+	Nama            string `json:"nama"`
+	NISN            string `json:"nisn"`
+	RombelID        *uint  `json:"rombel_id"`
+	Rombel          string `json:"rombel"`
+	Tanggal         string `json:"tanggal"`
+	Jam             string `json:"jam"`
+	Jenis           string `json:"jenis"`  // datang, pulang
+	Status          string `json:"status"` // tepat_waktu, terlambat
+	ScannerDeviceID uint   `json:"scanner_device_id"`
+}
+
+// AbsensiLiveRingkasanItem holds today's hadir and belum datang counters of one rombel
+type AbsensiLiveRingkasanItem struct {
+	RombelID    uint   `json:"rombel_id"`
+	Rombel      string `json:"rombel"`
+	TotalSiswa  int    `json:"total_siswa"`
+	Hadir       int    `json:"hadir"`
+	TepatWaktu  int    `json:"tepat_waktu"`
+	Terlambat   int    `json:"terlambat"`
+	BelumDatang int    `json:"belum_datang"`
+	Pulang      int    `json:"pulang"`
+}
+
+// AbsensiLiveRingkasanResponse is returned by the counter endpoint and streamed as "event: ringkasan".
+// Events after a scan only carry the rombel of the scanned student.
+type AbsensiLiveRingkasanResponse struct {
+	Tanggal string                     `json:"tanggal"`
+	Data    []AbsensiLiveRingkasanItem `json:"data"`
+}
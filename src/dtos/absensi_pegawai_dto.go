@@ -0,0 +1,77 @@
+package dtos
+
+// AbsensiPegawaiScanResponse represents the response after a guru or tendik scans their barcode
+type AbsensiPegawaiScanResponse struct {
+	Success     bool         `json:"success"`
+	Message     string       `json:"message"`
+	Pegawai     *PegawaiInfo `json:"pegawai,omitempty"`
+	AbsensiInfo *AbsensiInfo `json:"absensi_info,omitempty"`
+}
+
+// PegawaiInfo contains basic kepegawaian info
+type PegawaiInfo struct {
+	ID      uint   `json:"id"`
+	Nama    string `json:"nama"`
+	NIP     string `json:"nip"`
+	Jabatan string `json:"jabatan"`
+}
+
+// KonfigurasiAbsensiPegawaiRequest represents the request for setting the staff scan time windows
+type KonfigurasiAbsensiPegawaiRequest struct {
+	JamDatangMulai   string `json:"jam_datang_mulai" binding:"required"`   // Format: "HH:MM" atau "HH:MM:SS"
+	JamMaxDatang     string `json:"jam_max_datang" binding:"required"`     // Format: "HH:MM" atau "HH:MM:SS"
+	JamDatangSelesai string `json:"jam_datang_selesai" binding:"required"` // Format: "HH:MM" atau "HH:MM:SS"
+	JamPulangMulai   string `json:"jam_pulang_mulai" binding:"required"`   // Format: "HH:MM" atau "HH:MM:SS"
+	JamPulangSelesai string `json:"jam_pulang_selesai" binding:"required"` // Format: "HH:MM" atau "HH:MM:SS"
+}
+
+// KonfigurasiAbsensiPegawaiResponse represents the response for konfigurasi absensi pegawai
+type KonfigurasiAbsensiPegawaiResponse struct {
+	ID               uint   `json:"id"`
+	JamDatangMulai   string `json:"jam_datang_mulai"`
+	JamMaxDatang     string `json:"jam_max_datang"`
+	JamDatangSelesai string `json:"jam_datang_selesai"`
+	JamPulangMulai   string `json:"jam_pulang_mulai"`
+	JamPulangSelesai string `json:"jam_pulang_selesai"`
+	CreatedAt        string `json:"created_at"`
+	UpdatedAt        string `json:"updated_at"`
+}
+
+// AbsensiPegawaiRekapRequest represents the request for the monthly rekap and exports of absensi pegawai
+type AbsensiPegawaiRekapRequest struct {
+	Bulan         int    `json:"bulan" binding:"required,min=1,max=12"`
+	Tahun         int    `json:"tahun" binding:"required,min=2000"`
+	Kategori      string `json:"kategori"` // "Pendidik", "Tenaga Kependidikan"; empty for all
+	KepegawaianID *uint  `json:"kepegawaian_id"`
+}
+
+// AbsensiPegawaiRekapResponse represents the monthly rekap of absensi pegawai
+type AbsensiPegawaiRekapResponse struct {
+	Bulan            int                       `json:"bulan"`
+	Tahun            int                       `json:"tahun"`
+	TotalHariEfektif int                       `json:"total_hari_efektif"` // School days of the month up to today
+	Data             []AbsensiPegawaiRekapItem `json:"data"`
+}
+
+// AbsensiPegawaiRekapItem represents the monthly totals of a single pegawai
+type AbsensiPegawaiRekapItem struct {
+	KepegawaianID    uint                       `json:"kepegawaian_id"`
+	Nama             string                     `json:"nama"`
+	NIP              string                     `json:"nip"`
+	Kategori         string                     `json:"kategori"`
+	Jabatan          string                     `json:"jabatan"`
+	TotalHadir       int                        `json:"total_hadir"`
+	TotalTepatWaktu  int                        `json:"total_tepat_waktu"`
+	TotalTerlambat   int                        `json:"total_terlambat"`
+	TotalTidakHadir  int                        `json:"total_tidak_hadir"`
+	TotalTanpaPulang int                        `json:"total_tanpa_pulang"` // Hadir without a pulang scan
+	Detail           []AbsensiPegawaiHarianItem `json:"detail"`
+}
+
+// AbsensiPegawaiHarianItem represents one school day of a pegawai in the rekap
+type AbsensiPegawaiHarianItem struct {
+	Tanggal   string  `json:"tanggal"`
+	JamDatang *string `json:"jam_datang"`
+	JamPulang *string `json:"jam_pulang"`
+	Status    string  `json:"status"` // "tepat_waktu", "terlambat", "tidak_hadir"
+}
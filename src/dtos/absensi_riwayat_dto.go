@@ -0,0 +1,34 @@
+package dtos
+
+// RiwayatRekapAbsensiRequest represents the request for the change history of a student-day
+type RiwayatRekapAbsensiRequest struct {
+	PesertaDidikRombelID uint   `json:"peserta_didik_rombel_id" binding:"required"`
+	Tanggal              string `json:"tanggal" binding:"required"`          // YYYY-MM-DD
+	BidangStudiID        *uint  `json:"bidang_studi_id" binding:"omitempty"` // Empty = guru kelas and every mapel of the day
+}
+
+// RiwayatRekapAbsensiNilai is the state of a rekap row before or after a change
+type RiwayatRekapAbsensiNilai struct {
+	Status      string `json:"status"`
+	MetodeInput string `json:"metode_input"`
+	Keterangan  string `json:"keterangan"`
+	FileSurat   string `json:"file_surat"`
+	WaktuAbsen  string `json:"waktu_absen"`
+}
+
+// RiwayatRekapAbsensiResponse represents one change of a rekap row
+type RiwayatRekapAbsensiResponse struct {
+	ID                    uint                      `json:"id"`
+	RekapitulasiAbsensiID uint                      `json:"rekapitulasi_absensi_id"`
+	Tanggal               string                    `json:"tanggal"`
+	BidangStudiID         *uint                     `json:"bidang_studi_id"`
+	Aksi                  string                    `json:"aksi"`    // create, update, delete
+	Sumber                string                    `json:"sumber"`  // manual, sync, import, pengajuan, alpa_otomatis
+	Sebelum               *RiwayatRekapAbsensiNilai `json:"sebelum"` // Nil for create
+	Sesudah               *RiwayatRekapAbsensiNilai `json:"sesudah"` // Nil for delete
+	ActorID               *uint                     `json:"actor_id"`
+	ActorType             *string                   `json:"actor_type"`
+	ActorNama             string                    `json:"actor_nama"`
+	IPAddress             string                    `json:"ip_address"`
+	CreatedAt             string                    `json:"created_at"`
+}
@@ -1,5 +1,7 @@
 package dtos
 
+import "time"
+
 // AbsensiScanRequest represents the request for scanning attendance
 type AbsensiScanRequest struct {
 	Barcode string `json:"barcode" binding:"required"`
@@ -28,3 +30,40 @@ type AbsensiInfo struct {
 	Status     string  `json:"status"` // "tepat_waktu", "terlambat"
 	IsUpdate   bool    `json:"is_update"` // true jika update, false jika insert baru
 }
+
+// AbsensiScanBatchItem represents a single scan queued on a device while offline
+type AbsensiScanBatchItem struct {
+	ClientScanID string    `json:"client_scan_id" binding:"required,max=100"` // Unique per device, reused on retry
+	Barcode      string    `json:"barcode" binding:"required"`
+	ScannedAt    time.Time `json:"scanned_at" binding:"required"` // RFC3339, e.g. "2026-10-17T06:45:12+07:00"
+}
+
+// AbsensiScanBatchRequest represents the request for uploading offline scans
+type AbsensiScanBatchRequest struct {
+	Items []AbsensiScanBatchItem `json:"items" binding:"required,min=1,max=500,dive"`
+}
+
+// AbsensiScanBatchResponse represents the response for an offline batch upload
+type AbsensiScanBatchResponse struct {
+	TotalProcessed int                          `json:"total_processed"`
+	TotalInserted  int                          `json:"total_inserted"`
+	TotalUpdated   int                          `json:"total_updated"`
+	TotalSkipped   int                          `json:"total_skipped"`
+	TotalDuplicate int                          `json:"total_duplicate"`
+	TotalFailed    int                          `json:"total_failed"`
+	Message        string                       `json:"message"`
+	Details        []AbsensiScanBatchDetailItem `json:"details"`
+}
+
+// AbsensiScanBatchDetailItem represents the result of each offline scan
+type AbsensiScanBatchDetailItem struct {
+	ClientScanID   string `json:"client_scan_id"`
+	PesertaDidikID uint   `json:"peserta_didik_id,omitempty"`
+	NISN           string `json:"nisn,omitempty"`
+	Nama           string `json:"nama,omitempty"`
+	Tanggal        string `json:"tanggal"`
+	Jam            string `json:"jam"`
+	Action         string `json:"action"`              // inserted, updated, skipped, failed
+	Reason         string `json:"reason,omitempty"`
+	Duplicate      bool   `json:"duplicate"`           // true if the result was stored by an earlier upload
+}
@@ -34,15 +34,17 @@ type ApplicationGetAllRequest struct {
 
 // ApplicationResponse represents the response payload for Application
 type ApplicationResponse struct {
-	ID               uint       `json:"id"`
-	Nama             string     `json:"nama"`
-	Link             string     `json:"link"`
-	ShowInJumbotron  bool       `json:"show_in_jumbotron"`
-	Status           string     `json:"status"`
-	CreatedAt        time.Time  `json:"created_at"`
-	UpdatedAt        time.Time  `json:"updated_at"`
-	CreatedByID      *uint      `json:"created_by_id,omitempty"`
-	UpdatedByID      *uint      `json:"updated_by_id,omitempty"`
+	ID              uint      `json:"id"`
+	Nama            string    `json:"nama"`
+	Link            string    `json:"link"`
+	ShowInJumbotron bool      `json:"show_in_jumbotron"`
+	Status          string    `json:"status"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	CreatedByID     *uint     `json:"created_by_id,omitempty"`
+	CreatedByType   *string   `json:"created_by_type,omitempty"`
+	UpdatedByID     *uint     `json:"updated_by_id,omitempty"`
+	UpdatedByType   *string   `json:"updated_by_type,omitempty"`
 }
 
 // ApplicationListResponse represents the response payload for listing Application
@@ -6,11 +6,22 @@ import (
 
 // FileItemDTO represents a single file in the files array
 type FileItemDTO struct {
-	ID        string `json:"id"`
-	Filename  string `json:"filename"`
-	URL       string `json:"url"`
-	Size      int64  `json:"size"`
-	Thumbnail string `json:"thumbnail,omitempty"` // "active" or "inactive"
+	ID        string                    `json:"id"`
+	Filename  string                    `json:"filename"`
+	URL       string                    `json:"url"`
+	Size      int64                     `json:"size"`
+	Thumbnail string                    `json:"thumbnail,omitempty"` // "active" or "inactive"
+	Width     int                       `json:"width,omitempty"`
+	Height    int                       `json:"height,omitempty"`
+	Variants  map[string]FileVariantDTO `json:"variants,omitempty"` // "thumbnail", "medium" and "original"
+}
+
+// FileVariantDTO represents a resized variant of an uploaded image
+type FileVariantDTO struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Size   int64  `json:"size"`
 }
 
 // ArticleCreateRequest represents the request payload for creating Article
@@ -39,20 +50,23 @@ type ArticleUpdateRequest struct {
 
 // ArticleResponse represents the response payload for Article
 type ArticleResponse struct {
-	ID              uint          `json:"id"`
-	Judul           string        `json:"judul"`
-	Tanggal         time.Time     `json:"tanggal"`
-	Kategori        string        `json:"kategori"`
-	Deskripsi       string        `json:"deskripsi"`
-	Gambar          string        `json:"gambar"`
-	Files           []FileItemDTO `json:"files"`
-	Penulis         string        `json:"penulis"`
-	StatusPublikasi string        `json:"status_publikasi"`
-	Status          string        `json:"status"`
-	CreatedAt       time.Time     `json:"created_at"`
-	UpdatedAt       time.Time     `json:"updated_at"`
-	CreatedByID     *uint         `json:"created_by_id"`
-	UpdatedByID     *uint         `json:"updated_by_id"`
+	ID              uint                      `json:"id"`
+	Judul           string                    `json:"judul"`
+	Tanggal         time.Time                 `json:"tanggal"`
+	Kategori        string                    `json:"kategori"`
+	Deskripsi       string                    `json:"deskripsi"`
+	Gambar          string                    `json:"gambar"`
+	GambarVariants  map[string]FileVariantDTO `json:"gambar_variants,omitempty"`
+	Files           []FileItemDTO             `json:"files"`
+	Penulis         string                    `json:"penulis"`
+	StatusPublikasi string                    `json:"status_publikasi"`
+	Status          string                    `json:"status"`
+	CreatedAt       time.Time                 `json:"created_at"`
+	UpdatedAt       time.Time                 `json:"updated_at"`
+	CreatedByID     *uint                     `json:"created_by_id"`
+	CreatedByType   *string                   `json:"created_by_type"`
+	UpdatedByID     *uint                     `json:"updated_by_id"`
+	UpdatedByType   *string                   `json:"updated_by_type"`
 }
 
 // ArticleListResponse represents the response payload for listing Article
@@ -88,13 +102,14 @@ type ArticleListWithPaginationResponse struct {
 
 // ArticlePublicResponse represents the public response for article
 type ArticlePublicResponse struct {
-	ID          uint      `json:"id"`
-	Judul       string    `json:"judul"`
-	Tanggal     time.Time `json:"tanggal"`
-	Kategori    string    `json:"kategori"`
-	Deskripsi   string    `json:"deskripsi"`
-	Gambar      string    `json:"gambar"`
-	Penulis     string    `json:"penulis"`
+	ID             uint                      `json:"id"`
+	Judul          string                    `json:"judul"`
+	Tanggal        time.Time                 `json:"tanggal"`
+	Kategori       string                    `json:"kategori"`
+	Deskripsi      string                    `json:"deskripsi"`
+	Gambar         string                    `json:"gambar"`
+	GambarVariants map[string]FileVariantDTO `json:"gambar_variants,omitempty"`
+	Penulis        string                    `json:"penulis"`
 }
 
 // ArticlePublicListResponse represents the public list response
@@ -121,12 +136,13 @@ type ArticlePublicDaftarResponse struct {
 
 // ArticlePublicDetailResponse represents the public detail response for a single article
 type ArticlePublicDetailResponse struct {
-	ID        uint          `json:"id"`
-	Judul     string        `json:"judul"`
-	Tanggal   time.Time     `json:"tanggal"`
-	Kategori  string        `json:"kategori"`
-	Deskripsi string        `json:"deskripsi"`
-	Gambar    string        `json:"gambar"`
-	Penulis   string        `json:"penulis"`
-	Files     []FileItemDTO `json:"files"`
+	ID             uint                      `json:"id"`
+	Judul          string                    `json:"judul"`
+	Tanggal        time.Time                 `json:"tanggal"`
+	Kategori       string                    `json:"kategori"`
+	Deskripsi      string                    `json:"deskripsi"`
+	Gambar         string                    `json:"gambar"`
+	GambarVariants map[string]FileVariantDTO `json:"gambar_variants,omitempty"`
+	Penulis        string                    `json:"penulis"`
+	Files          []FileItemDTO             `json:"files"`
 }
@@ -0,0 +1,48 @@
+package dtos
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestResponseAuditPrincipalTypes checks that no response exposes an audit ID without its principal type,
+// since users, kepegawaian and peserta_didik IDs overlap
+func TestResponseAuditPrincipalTypes(t *testing.T) {
+	files, err := filepath.Glob("*_dto.go")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Glob() = %d files, error %v", len(files), err)
+	}
+
+	for _, path := range files {
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		if err != nil {
+			t.Fatalf("ParseFile(%s) error = %v", path, err)
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			spec, ok := node.(*ast.TypeSpec)
+			if !ok || !strings.HasSuffix(spec.Name.Name, "Response") {
+				return true
+			}
+			structType, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				return true
+			}
+			fields := make(map[string]bool)
+			for _, field := range structType.Fields.List {
+				for _, name := range field.Names {
+					fields[name.Name] = true
+				}
+			}
+			for name := range fields {
+				if prefix, ok := strings.CutSuffix(name, "ByID"); ok && !fields[prefix+"ByType"] {
+					t.Errorf("%s has %s without %sByType", spec.Name.Name, name, prefix)
+				}
+			}
+			return true
+		})
+	}
+}
@@ -17,13 +17,15 @@ type BidangStudiUpdateRequest struct {
 
 // BidangStudiResponse represents the response payload for BidangStudi
 type BidangStudiResponse struct {
-	ID          uint      `json:"id"`
-	Name        string    `json:"name"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	CreatedByID *uint     `json:"created_by_id"`
-	UpdatedByID *uint     `json:"updated_by_id"`
+	ID            uint      `json:"id"`
+	Name          string    `json:"name"`
+	Status        string    `json:"status"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	CreatedByID   *uint     `json:"created_by_id"`
+	CreatedByType *string   `json:"created_by_type"`
+	UpdatedByID   *uint     `json:"updated_by_id"`
+	UpdatedByType *string   `json:"updated_by_type"`
 }
 
 // BidangStudiListResponse represents the response payload for listing BidangStudi
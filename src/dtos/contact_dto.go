@@ -44,22 +44,24 @@ type ContactUpdateRequest struct {
 
 // ContactResponse represents the response payload for Contact
 type ContactResponse struct {
-	ID        uint          `json:"id"`
-	Alamat    string        `json:"alamat"`
-	Telepon   string        `json:"telepon"`
-	Email     string        `json:"email"`
-	JamBuka   []JamBukaItem `json:"jam_buka"`
-	Gmaps     string        `json:"gmaps"`
-	Website   string        `json:"website"`
-	Youtube   string        `json:"youtube"`
-	Instagram string        `json:"instagram"`
-	Tiktok    string        `json:"tiktok"`
-	Facebook  string        `json:"facebook"`
-	Twitter   string        `json:"twitter"`
-	CreatedAt time.Time     `json:"created_at"`
-	UpdatedAt time.Time     `json:"updated_at"`
-	CreatedByID *uint       `json:"created_by_id"`
-	UpdatedByID *uint       `json:"updated_by_id"`
+	ID            uint          `json:"id"`
+	Alamat        string        `json:"alamat"`
+	Telepon       string        `json:"telepon"`
+	Email         string        `json:"email"`
+	JamBuka       []JamBukaItem `json:"jam_buka"`
+	Gmaps         string        `json:"gmaps"`
+	Website       string        `json:"website"`
+	Youtube       string        `json:"youtube"`
+	Instagram     string        `json:"instagram"`
+	Tiktok        string        `json:"tiktok"`
+	Facebook      string        `json:"facebook"`
+	Twitter       string        `json:"twitter"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	CreatedByID   *uint         `json:"created_by_id"`
+	CreatedByType *string       `json:"created_by_type"`
+	UpdatedByID   *uint         `json:"updated_by_id"`
+	UpdatedByType *string       `json:"updated_by_type"`
 }
 
 // ContactPublicResponse represents the public response for contact (without timestamps and audit fields)
@@ -28,16 +28,18 @@ type KelasInfo struct {
 
 // EkstrakurikulerResponse represents the response payload for Ekstrakurikuler
 type EkstrakurikulerResponse struct {
-	ID          uint        `json:"id"`
-	Name        string      `json:"name"`
-	KelasIDs    []uint      `json:"kelas_ids"`
-	Kelas       []KelasInfo `json:"kelas"`
-	Kategori    string      `json:"kategori"`
-	Status      string      `json:"status"`
-	CreatedAt   time.Time   `json:"created_at"`
-	UpdatedAt   time.Time   `json:"updated_at"`
-	CreatedByID *uint       `json:"created_by_id"`
-	UpdatedByID *uint       `json:"updated_by_id"`
+	ID            uint        `json:"id"`
+	Name          string      `json:"name"`
+	KelasIDs      []uint      `json:"kelas_ids"`
+	Kelas         []KelasInfo `json:"kelas"`
+	Kategori      string      `json:"kategori"`
+	Status        string      `json:"status"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	CreatedByID   *uint       `json:"created_by_id"`
+	CreatedByType *string     `json:"created_by_type"`
+	UpdatedByID   *uint       `json:"updated_by_id"`
+	UpdatedByType *string     `json:"updated_by_type"`
 }
 
 // EkstrakurikulerListResponse represents the response payload for listing Ekstrakurikuler
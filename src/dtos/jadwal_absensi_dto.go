@@ -0,0 +1,173 @@
+package dtos
+
+import "time"
+
+// ProfilJadwalAbsensiHariRequest represents a weekday override of a schedule profile.
+// Empty times keep the profile times, e.g. only jam_pulang_* for an earlier Friday dismissal.
+type ProfilJadwalAbsensiHariRequest struct {
+	Hari             int     `json:"hari" binding:"required,min=1,max=7"` // ISO weekday, 1 = Senin ... 7 = Minggu
+	JamDatangMulai   *string `json:"jam_datang_mulai"`                    // Format: "HH:MM" atau "HH:MM:SS"
+	JamMaxDatang     *string `json:"jam_max_datang"`
+	JamDatangSelesai *string `json:"jam_datang_selesai"`
+	JamPulangMulai   *string `json:"jam_pulang_mulai"`
+	JamPulangSelesai *string `json:"jam_pulang_selesai"`
+}
+
+// ProfilJadwalAbsensiCreateRequest represents the request payload for creating a schedule profile
+type ProfilJadwalAbsensiCreateRequest struct {
+	Nama             string                           `json:"nama" binding:"required,max=100"`
+	Keterangan       *string                          `json:"keterangan"`
+	JamDatangMulai   string                           `json:"jam_datang_mulai" binding:"required"`   // Format: "HH:MM" atau "HH:MM:SS"
+	JamMaxDatang     string                           `json:"jam_max_datang" binding:"required"`     // Format: "HH:MM" atau "HH:MM:SS"
+	JamDatangSelesai string                           `json:"jam_datang_selesai" binding:"required"` // Format: "HH:MM" atau "HH:MM:SS"
+	JamPulangMulai   string                           `json:"jam_pulang_mulai" binding:"required"`   // Format: "HH:MM" atau "HH:MM:SS"
+	JamPulangSelesai string                           `json:"jam_pulang_selesai" binding:"required"` // Format: "HH:MM" atau "HH:MM:SS"
+	Status           string                           `json:"status" binding:"omitempty,oneof=active inactive"`
+	Hari             []ProfilJadwalAbsensiHariRequest `json:"hari" binding:"omitempty,dive"`
+}
+
+// ProfilJadwalAbsensiUpdateRequest represents the request payload for updating a schedule profile.
+// Hari replaces all weekday overrides when sent; an empty list removes them.
+type ProfilJadwalAbsensiUpdateRequest struct {
+	ID               uint                              `json:"id" binding:"required"`
+	Nama             *string                           `json:"nama" binding:"omitempty,max=100"`
+	Keterangan       *string                           `json:"keterangan"`
+	JamDatangMulai   *string                           `json:"jam_datang_mulai"`
+	JamMaxDatang     *string                           `json:"jam_max_datang"`
+	JamDatangSelesai *string                           `json:"jam_datang_selesai"`
+	JamPulangMulai   *string                           `json:"jam_pulang_mulai"`
+	JamPulangSelesai *string                           `json:"jam_pulang_selesai"`
+	Status           *string                           `json:"status" binding:"omitempty,oneof=active inactive"`
+	Hari             *[]ProfilJadwalAbsensiHariRequest `json:"hari" binding:"omitempty,dive"`
+}
+
+// ProfilJadwalAbsensiHariResponse represents a weekday override in the response
+type ProfilJadwalAbsensiHariResponse struct {
+	Hari             int     `json:"hari"`
+	JamDatangMulai   *string `json:"jam_datang_mulai"`
+	JamMaxDatang     *string `json:"jam_max_datang"`
+	JamDatangSelesai *string `json:"jam_datang_selesai"`
+	JamPulangMulai   *string `json:"jam_pulang_mulai"`
+	JamPulangSelesai *string `json:"jam_pulang_selesai"`
+}
+
+// ProfilJadwalAbsensiResponse represents the response payload for a schedule profile
+type ProfilJadwalAbsensiResponse struct {
+	ID               uint                              `json:"id"`
+	Nama             string                            `json:"nama"`
+	Keterangan       *string                           `json:"keterangan"`
+	JamDatangMulai   string                            `json:"jam_datang_mulai"`
+	JamMaxDatang     string                            `json:"jam_max_datang"`
+	JamDatangSelesai string                            `json:"jam_datang_selesai"`
+	JamPulangMulai   string                            `json:"jam_pulang_mulai"`
+	JamPulangSelesai string                            `json:"jam_pulang_selesai"`
+	Status           string                            `json:"status"`
+	Hari             []ProfilJadwalAbsensiHariResponse `json:"hari"`
+	CreatedAt        time.Time                         `json:"created_at"`
+	UpdatedAt        time.Time                         `json:"updated_at"`
+	CreatedByID      *uint                             `json:"created_by_id"`
+	CreatedByType    *string                           `json:"created_by_type"`
+	UpdatedByID      *uint                             `json:"updated_by_id"`
+	UpdatedByType    *string                           `json:"updated_by_type"`
+}
+
+// ProfilJadwalAbsensiGetAllRequest represents the request for listing schedule profiles
+type ProfilJadwalAbsensiGetAllRequest struct {
+	Search struct {
+		Nama   string `json:"nama"`
+		Status string `json:"status"`
+	} `json:"search"`
+	Pagination struct {
+		Limit int `json:"limit"`
+		Page  int `json:"page"`
+	} `json:"pagination"`
+}
+
+// ProfilJadwalAbsensiListWithPaginationResponse represents the response with pagination
+type ProfilJadwalAbsensiListWithPaginationResponse struct {
+	Data       []ProfilJadwalAbsensiResponse `json:"data"`
+	Pagination PaginationInfo                `json:"pagination"`
+}
+
+// PenugasanJadwalAbsensiRequest represents the request payload for creating an assignment.
+// Leave kelas_id and rombel_id empty to apply to all students; set both tanggal for a special schedule.
+type PenugasanJadwalAbsensiRequest struct {
+	ProfilJadwalAbsensiID uint    `json:"profil_jadwal_absensi_id" binding:"required"`
+	KelasID               *uint   `json:"kelas_id"`
+	RombelID              *uint   `json:"rombel_id"`
+	TanggalMulai          *string `json:"tanggal_mulai"`   // YYYY-MM-DD
+	TanggalSelesai        *string `json:"tanggal_selesai"` // YYYY-MM-DD
+	Keterangan            *string `json:"keterangan"`
+}
+
+// PenugasanJadwalAbsensiUpdateRequest represents the request payload for updating an assignment; all fields are replaced
+type PenugasanJadwalAbsensiUpdateRequest struct {
+	ID uint `json:"id" binding:"required"`
+	PenugasanJadwalAbsensiRequest
+}
+
+// PenugasanJadwalAbsensiResponse represents the response payload for an assignment
+type PenugasanJadwalAbsensiResponse struct {
+	ID                    uint      `json:"id"`
+	ProfilJadwalAbsensiID uint      `json:"profil_jadwal_absensi_id"`
+	ProfilNama            string    `json:"profil_nama"`
+	Cakupan               string    `json:"cakupan"` // semua, kelas, rombel
+	KelasID               *uint     `json:"kelas_id"`
+	Kelas                 string    `json:"kelas,omitempty"`
+	RombelID              *uint     `json:"rombel_id"`
+	Rombel                string    `json:"rombel,omitempty"`
+	TanggalMulai          *string   `json:"tanggal_mulai"`
+	TanggalSelesai        *string   `json:"tanggal_selesai"`
+	Keterangan            *string   `json:"keterangan"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+// PenugasanJadwalAbsensiGetAllRequest represents the request for listing assignments
+type PenugasanJadwalAbsensiGetAllRequest struct {
+	Search struct {
+		ProfilJadwalAbsensiID *uint `json:"profil_jadwal_absensi_id"`
+		KelasID               *uint `json:"kelas_id"`
+		RombelID              *uint `json:"rombel_id"`
+	} `json:"search"`
+	Pagination struct {
+		Limit int `json:"limit"`
+		Page  int `json:"page"`
+	} `json:"pagination"`
+}
+
+// PenugasanJadwalAbsensiListWithPaginationResponse represents the response with pagination
+type PenugasanJadwalAbsensiListWithPaginationResponse struct {
+	Data       []PenugasanJadwalAbsensiResponse `json:"data"`
+	Pagination PaginationInfo                   `json:"pagination"`
+}
+
+// JadwalAbsensiPreviewRequest represents the request for previewing the schedule of a student on a date
+type JadwalAbsensiPreviewRequest struct {
+	PesertaDidikID uint   `json:"peserta_didik_id" binding:"required"`
+	Tanggal        string `json:"tanggal" binding:"required"` // YYYY-MM-DD
+}
+
+// JadwalAbsensiPreviewResponse represents the effective schedule of a student on a date
+type JadwalAbsensiPreviewResponse struct {
+	PesertaDidikID   uint   `json:"peserta_didik_id"`
+	NamaSiswa        string `json:"nama_siswa"`
+	RombelID         *uint  `json:"rombel_id"`
+	Rombel           string `json:"rombel"`
+	Tanggal          string `json:"tanggal"`
+	Hari             int    `json:"hari"`
+	HariSekolah      bool   `json:"hari_sekolah"`
+	KeteranganHari   string `json:"keterangan_hari,omitempty"` // Why it is not a school day
+	Sumber           string `json:"sumber"`                    // konfigurasi_absensi, penugasan
+	PenugasanID      *uint  `json:"penugasan_id"`
+	ProfilID         *uint  `json:"profil_jadwal_absensi_id"`
+	ProfilNama       string `json:"profil_nama,omitempty"`
+	Cakupan          string `json:"cakupan,omitempty"` // semua, kelas, rombel
+	JadwalKhusus     bool   `json:"jadwal_khusus"`     // Date-ranged assignment
+	OverrideHari     bool   `json:"override_hari"`     // Weekday override applied
+	JamDatangMulai   string `json:"jam_datang_mulai"`
+	JamMaxDatang     string `json:"jam_max_datang"`
+	JamDatangSelesai string `json:"jam_datang_selesai"`
+	JamPulangMulai   string `json:"jam_pulang_mulai"`
+	JamPulangSelesai string `json:"jam_pulang_selesai"`
+}
@@ -15,13 +15,16 @@ type JumbotronUpdateRequest struct {
 
 // JumbotronResponse represents the response payload for Jumbotron
 type JumbotronResponse struct {
-	ID          uint      `json:"id"`
-	File        string    `json:"file"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	CreatedByID *uint     `json:"created_by_id"`
-	UpdatedByID *uint     `json:"updated_by_id"`
+	ID            uint                      `json:"id"`
+	File          string                    `json:"file"`
+	FileVariants  map[string]FileVariantDTO `json:"file_variants,omitempty"`
+	Status        string                    `json:"status"`
+	CreatedAt     time.Time                 `json:"created_at"`
+	UpdatedAt     time.Time                 `json:"updated_at"`
+	CreatedByID   *uint                     `json:"created_by_id"`
+	CreatedByType *string                   `json:"created_by_type"`
+	UpdatedByID   *uint                     `json:"updated_by_id"`
+	UpdatedByType *string                   `json:"updated_by_type"`
 }
 
 // JumbotronListResponse represents the response payload for listing Jumbotron
@@ -34,6 +37,7 @@ type JumbotronListResponse struct {
 
 // JumbotronPublicResponse represents the public response payload for Jumbotron
 type JumbotronPublicResponse struct {
-	File   string `json:"file"`
-	Status string `json:"status"`
+	File         string                    `json:"file"`
+	FileVariants map[string]FileVariantDTO `json:"file_variants,omitempty"`
+	Status       string                    `json:"status"`
 }
@@ -0,0 +1,107 @@
+package dtos
+
+import "time"
+
+// KalenderAkademikCreateRequest represents the request payload for creating a KalenderAkademik entry
+type KalenderAkademikCreateRequest struct {
+	TahunPelajaranID *uint   `json:"tahun_pelajaran_id"`
+	Jenis            string  `json:"jenis" binding:"required,oneof=libur_nasional libur_sekolah kegiatan_sekolah"`
+	Nama             string  `json:"nama" binding:"required,max=255"`
+	TanggalMulai     string  `json:"tanggal_mulai" binding:"required"`       // YYYY-MM-DD
+	TanggalSelesai   string  `json:"tanggal_selesai" binding:"required"`     // YYYY-MM-DD
+	Semester         *int    `json:"semester" binding:"omitempty,oneof=1 2"` // Optional, the semester the entry belongs to
+	Libur            *bool   `json:"libur"`                                  // Default true for libur_*, false otherwise
+	Keterangan       *string `json:"keterangan"`
+}
+
+// KalenderAkademikUpdateRequest represents the request payload for updating a KalenderAkademik entry
+type KalenderAkademikUpdateRequest struct {
+	ID               uint    `json:"id" binding:"required"`
+	TahunPelajaranID *uint   `json:"tahun_pelajaran_id"`
+	Jenis            *string `json:"jenis" binding:"omitempty,oneof=libur_nasional libur_sekolah kegiatan_sekolah"`
+	Nama             *string `json:"nama" binding:"omitempty,max=255"`
+	TanggalMulai     *string `json:"tanggal_mulai"`
+	TanggalSelesai   *string `json:"tanggal_selesai"`
+	Semester         *int    `json:"semester" binding:"omitempty,oneof=1 2"`
+	Libur            *bool   `json:"libur"`
+	Keterangan       *string `json:"keterangan"`
+}
+
+// KalenderAkademikResponse represents the response payload for KalenderAkademik
+type KalenderAkademikResponse struct {
+	ID                 uint      `json:"id"`
+	TahunPelajaranID   *uint     `json:"tahun_pelajaran_id"`
+	TahunPelajaranNama string    `json:"tahun_pelajaran_nama,omitempty"`
+	Jenis              string    `json:"jenis"`
+	Nama               string    `json:"nama"`
+	TanggalMulai       string    `json:"tanggal_mulai"`
+	TanggalSelesai     string    `json:"tanggal_selesai"`
+	Semester           *int      `json:"semester"`
+	Libur              bool      `json:"libur"`
+	Keterangan         *string   `json:"keterangan"`
+	Sumber             string    `json:"sumber"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+	CreatedByID        *uint     `json:"created_by_id"`
+	CreatedByType      *string   `json:"created_by_type"`
+	UpdatedByID        *uint     `json:"updated_by_id"`
+	UpdatedByType      *string   `json:"updated_by_type"`
+}
+
+// KalenderAkademikGetAllRequest represents the request payload for getting kalender akademik with filters
+type KalenderAkademikGetAllRequest struct {
+	Search struct {
+		TahunPelajaranID *uint  `json:"tahun_pelajaran_id"`
+		Jenis            string `json:"jenis"`
+		Nama             string `json:"nama"`
+		TanggalMulai     string `json:"tanggal_mulai"`   // YYYY-MM-DD, entries ending on/after this date
+		TanggalSelesai   string `json:"tanggal_selesai"` // YYYY-MM-DD, entries starting on/before this date
+	} `json:"search"`
+	Pagination struct {
+		Limit int `json:"limit"`
+		Page  int `json:"page"`
+	} `json:"pagination"`
+}
+
+// KalenderAkademikListWithPaginationResponse represents the response with pagination
+type KalenderAkademikListWithPaginationResponse struct {
+	Data       []KalenderAkademikResponse `json:"data"`
+	Pagination PaginationInfo             `json:"pagination"`
+}
+
+// KalenderAkademikImportResponse represents the response for Excel/iCal import
+type KalenderAkademikImportResponse struct {
+	SuccessCount int                           `json:"success_count"`
+	UpdatedCount int                           `json:"updated_count"`
+	FailedCount  int                           `json:"failed_count"`
+	Errors       []KalenderAkademikImportError `json:"errors,omitempty"`
+}
+
+// KalenderAkademikImportError represents an error for a specific row or event during import
+type KalenderAkademikImportError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+// HariEfektifRequest represents the request for listing effective school days in a date range
+type HariEfektifRequest struct {
+	TanggalMulai   string `json:"tanggal_mulai" binding:"required"`   // YYYY-MM-DD
+	TanggalSelesai string `json:"tanggal_selesai" binding:"required"` // YYYY-MM-DD
+}
+
+// HariEfektifResponse represents effective school days in a date range
+type HariEfektifResponse struct {
+	TanggalMulai     string         `json:"tanggal_mulai"`
+	TanggalSelesai   string         `json:"tanggal_selesai"`
+	TotalHari        int            `json:"total_hari"`
+	TotalHariEfektif int            `json:"total_hari_efektif"`
+	Hari             []HariKalender `json:"hari"`
+}
+
+// HariKalender represents one date and whether it is a school day
+type HariKalender struct {
+	Tanggal     string `json:"tanggal"`
+	Hari        string `json:"hari"`
+	HariSekolah bool   `json:"hari_sekolah"`
+	Keterangan  string `json:"keterangan,omitempty"`
+}
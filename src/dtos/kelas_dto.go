@@ -17,13 +17,15 @@ type KelasUpdateRequest struct {
 
 // KelasResponse represents the response payload for Kelas
 type KelasResponse struct {
-	ID          uint      `json:"id"`
-	Name        string    `json:"name"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	CreatedByID *uint     `json:"created_by_id"`
-	UpdatedByID *uint     `json:"updated_by_id"`
+	ID            uint      `json:"id"`
+	Name          string    `json:"name"`
+	Status        string    `json:"status"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	CreatedByID   *uint     `json:"created_by_id"`
+	CreatedByType *string   `json:"created_by_type"`
+	UpdatedByID   *uint     `json:"updated_by_id"`
+	UpdatedByType *string   `json:"updated_by_type"`
 }
 
 // KelasListResponse represents the response payload for listing Kelas
@@ -1,6 +1,9 @@
 package dtos
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"pintu-backend/src/modules/models"
+)
 
 // KelulusanCreateRequest represents the request for creating kelulusan data
 type KelulusanCreateRequest struct {
@@ -15,21 +18,25 @@ type KelulusanCreateRequest struct {
 
 // KelulusanResponse represents the response for kelulusan data
 type KelulusanResponse struct {
-	ID            uint            `json:"id"`
-	NomorPeserta  string          `json:"nomor_peserta"`
-	NISN          string          `json:"nisn"`
-	Nama          string          `json:"nama"`
-	TanggalLahir  string          `json:"tanggal_lahir"`
-	Nilai         json.RawMessage `json:"nilai"`
-	RataRataNilai float64         `json:"rata_rata_nilai"` // Calculated average, 2 decimal places
-	Lulus         bool            `json:"lulus"`
-	SKL           string          `json:"skl,omitempty"`
-	MaxAttempts   int             `json:"max_attempts"`
-	AttemptCount  int             `json:"attempt_count"`
-	CreatedAt     string          `json:"created_at"`
-	UpdatedAt     string          `json:"updated_at"`
-	CreatedByID   *uint           `json:"created_by_id,omitempty"`
-	UpdatedByID   *uint           `json:"updated_by_id,omitempty"`
+	ID            uint                 `json:"id"`
+	NomorPeserta  string               `json:"nomor_peserta"`
+	NISN          string               `json:"nisn"`
+	Nama          string               `json:"nama"`
+	TanggalLahir  string               `json:"tanggal_lahir"`
+	Nilai         json.RawMessage      `json:"nilai"`
+	RataRataNilai float64              `json:"rata_rata_nilai"` // Calculated average, 2 decimal places
+	Lulus         bool                 `json:"lulus"`
+	SKL           string               `json:"skl,omitempty"`
+	MaxAttempts   int                  `json:"max_attempts"`
+	AttemptCount  int                  `json:"attempt_count"`
+	CreatedAt     string               `json:"created_at"`
+	UpdatedAt     string               `json:"updated_at"`
+	CreatedByID   *uint                `json:"created_by_id,omitempty"`
+	CreatedByType *string              `json:"created_by_type,omitempty"`
+	UpdatedByID   *uint                `json:"updated_by_id,omitempty"`
+	UpdatedByType *string              `json:"updated_by_type,omitempty"`
+	CreatedBy     *models.PrincipalRef `json:"created_by,omitempty"`
+	UpdatedBy     *models.PrincipalRef `json:"updated_by,omitempty"`
 }
 
 // KelulusanDownloadTemplateRequest represents the request for downloading template
@@ -0,0 +1,109 @@
+package dtos
+
+// KenaikanKelasUsulanRequest represents the request payload for the proposed rombel mapping of a rollover
+type KenaikanKelasUsulanRequest struct {
+	TahunPelajaranAsalID   uint `json:"tahun_pelajaran_asal_id" binding:"required"`
+	TahunPelajaranTujuanID uint `json:"tahun_pelajaran_tujuan_id" binding:"required"`
+}
+
+// KenaikanKelasUsulanRombel is the proposed next rombel of one rombel (kelas N -> N+1)
+type KenaikanKelasUsulanRombel struct {
+	RombelAsalID   uint    `json:"rombel_asal_id"`
+	RombelAsal     string  `json:"rombel_asal"`
+	KelasAsal      string  `json:"kelas_asal"`
+	JumlahSiswa    int     `json:"jumlah_siswa"`
+	Lulus          bool    `json:"lulus"` // Highest kelas, its students graduate
+	RombelTujuanID *uint   `json:"rombel_tujuan_id"`
+	RombelTujuan   *string `json:"rombel_tujuan"`
+}
+
+// KenaikanKelasUsulanResponse represents the proposed rombel mapping of a rollover
+type KenaikanKelasUsulanResponse struct {
+	TahunPelajaranAsal   string                      `json:"tahun_pelajaran_asal"`
+	TahunPelajaranTujuan string                      `json:"tahun_pelajaran_tujuan"`
+	Rombel               []KenaikanKelasUsulanRombel `json:"rombel"`
+}
+
+// KenaikanKelasMappingRombel overrides the proposed next rombel of a rombel
+type KenaikanKelasMappingRombel struct {
+	RombelAsalID   uint `json:"rombel_asal_id" binding:"required"`
+	RombelTujuanID uint `json:"rombel_tujuan_id" binding:"required"`
+}
+
+// KenaikanKelasKeputusanSiswa overrides the decision for one student, e.g. tinggal kelas
+type KenaikanKelasKeputusanSiswa struct {
+	PesertaDidikRombelID uint   `json:"peserta_didik_rombel_id" binding:"required"`
+	Keputusan            string `json:"keputusan" binding:"required,oneof=naik tinggal_kelas lulus"`
+	RombelTujuanID       *uint  `json:"rombel_tujuan_id" binding:"omitempty"` // Default: next rombel (naik) or the same rombel (tinggal_kelas)
+}
+
+// KenaikanKelasRequest represents the request payload for previewing and processing a rollover
+type KenaikanKelasRequest struct {
+	TahunPelajaranAsalID   uint                          `json:"tahun_pelajaran_asal_id" binding:"required"`
+	TahunPelajaranTujuanID uint                          `json:"tahun_pelajaran_tujuan_id" binding:"required"`
+	MappingRombel          []KenaikanKelasMappingRombel  `json:"mapping_rombel" binding:"omitempty,dive"`
+	KeputusanSiswa         []KenaikanKelasKeputusanSiswa `json:"keputusan_siswa" binding:"omitempty,dive"`
+}
+
+// KenaikanKelasSiswaItem is the outcome of a rollover for one student
+type KenaikanKelasSiswaItem struct {
+	PesertaDidikRombelID uint    `json:"peserta_didik_rombel_id"`
+	PesertaDidikID       uint    `json:"peserta_didik_id"`
+	NIS                  string  `json:"nis"`
+	Nama                 string  `json:"nama"`
+	RombelAsal           string  `json:"rombel_asal"`
+	Keputusan            string  `json:"keputusan"`
+	RombelTujuanID       *uint   `json:"rombel_tujuan_id"`
+	RombelTujuan         *string `json:"rombel_tujuan"`
+}
+
+// KenaikanKelasRombelTujuanItem is the number of students a target rombel receives
+type KenaikanKelasRombelTujuanItem struct {
+	RombelTujuanID uint   `json:"rombel_tujuan_id"`
+	RombelTujuan   string `json:"rombel_tujuan"`
+	JumlahSiswa    int    `json:"jumlah_siswa"`
+}
+
+// KenaikanKelasPreviewResponse represents the result a rollover would have. It can only be processed without errors.
+type KenaikanKelasPreviewResponse struct {
+	TahunPelajaranAsal   string                          `json:"tahun_pelajaran_asal"`
+	TahunPelajaranTujuan string                          `json:"tahun_pelajaran_tujuan"`
+	TotalNaik            int                             `json:"total_naik"`
+	TotalTinggalKelas    int                             `json:"total_tinggal_kelas"`
+	TotalLulus           int                             `json:"total_lulus"`
+	RombelTujuan         []KenaikanKelasRombelTujuanItem `json:"rombel_tujuan"`
+	Siswa                []KenaikanKelasSiswaItem        `json:"siswa"`
+	Errors               []string                        `json:"errors"`
+}
+
+// KenaikanKelasResponse represents a processed rollover
+type KenaikanKelasResponse struct {
+	ID                     uint    `json:"id"`
+	TahunPelajaranAsalID   uint    `json:"tahun_pelajaran_asal_id"`
+	TahunPelajaranAsal     string  `json:"tahun_pelajaran_asal"`
+	TahunPelajaranTujuanID uint    `json:"tahun_pelajaran_tujuan_id"`
+	TahunPelajaranTujuan   string  `json:"tahun_pelajaran_tujuan"`
+	Status                 string  `json:"status"`
+	TotalNaik              int     `json:"total_naik"`
+	TotalTinggalKelas      int     `json:"total_tinggal_kelas"`
+	TotalLulus             int     `json:"total_lulus"`
+	DiprosesAt             string  `json:"diproses_at"`
+	DibatalkanAt           *string `json:"dibatalkan_at"`
+	BisaDibatalkan         bool    `json:"bisa_dibatalkan"` // Only the latest rollover, on the day it was processed
+	CreatedByID            *uint   `json:"created_by_id"`
+	CreatedByType          *string `json:"created_by_type"`
+}
+
+// KenaikanKelasGetAllRequest represents the request payload for the rollover history
+type KenaikanKelasGetAllRequest struct {
+	Pagination struct {
+		Limit int `json:"limit"`
+		Page  int `json:"page"`
+	} `json:"pagination"`
+}
+
+// KenaikanKelasListWithPaginationResponse represents the rollover history with pagination
+type KenaikanKelasListWithPaginationResponse struct {
+	Data       []KenaikanKelasResponse `json:"data"`
+	Pagination PaginationInfo          `json:"pagination"`
+}
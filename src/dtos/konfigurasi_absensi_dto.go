@@ -9,6 +9,8 @@ type KonfigurasiAbsensiRequest struct {
 	JamPulangSelesai string `json:"jam_pulang_selesai" binding:"required"` // Format: "HH:MM" atau "HH:MM:SS"
 	NamaKepsek       string `json:"nama_kepsek"`
 	NIPKepsek        string `json:"nip_kepsek"`
+	HariSekolah      string `json:"hari_sekolah"`                                                    // ISO weekdays, 1 = Senin ... 7 = Minggu, e.g. "1,2,3,4,5"; kept if empty
+	ModeKartuQR      string `json:"mode_kartu_qr" binding:"omitempty,oneof=statis campuran dinamis"` // Kartu pelajar scan mode; kept if empty
 }
 
 // KonfigurasiAbsensiResponse represents the response for konfigurasi absensi
@@ -21,6 +23,8 @@ type KonfigurasiAbsensiResponse struct {
 	JamPulangSelesai string  `json:"jam_pulang_selesai"`
 	NamaKepsek       *string `json:"nama_kepsek"`
 	NIPKepsek        *string `json:"nip_kepsek"`
+	HariSekolah      string  `json:"hari_sekolah"`
+	ModeKartuQR      string  `json:"mode_kartu_qr"`
 	CreatedAt        string  `json:"created_at"`
 	UpdatedAt        string  `json:"updated_at"`
 }
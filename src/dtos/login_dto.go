@@ -10,8 +10,29 @@ type LoginRequest struct {
 
 // LoginResponse represents the response payload for successful login
 type LoginResponse struct {
-	Token       string              `json:"token"`
-	User        UserLoginResponse   `json:"user"`
-	Permissions []string            `json:"permissions"`
-	ExpiresAt   time.Time           `json:"expires_at"`
+	Token            string              `json:"token"`
+	RefreshToken     string              `json:"refresh_token"`
+	User             UserLoginResponse   `json:"user"`
+	ActiveRoleID     *uint               `json:"active_role_id"`
+	Permissions      []string            `json:"permissions"`
+	ExpiresAt        time.Time           `json:"expires_at"`
+	RefreshExpiresAt time.Time           `json:"refresh_expires_at"`
+}
+
+// SwitchRoleRequest represents the request payload for switching the active role
+type SwitchRoleRequest struct {
+	RoleID uint `json:"role_id" binding:"required"`
+}
+
+// RefreshTokenRequest represents the request payload for refreshing an access token
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// RefreshTokenResponse represents the response payload for a rotated token pair
+type RefreshTokenResponse struct {
+	Token            string    `json:"token"`
+	RefreshToken     string    `json:"refresh_token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
 }
@@ -2,8 +2,8 @@ package dtos
 
 // MutasiSiswaCreateRequest represents the request payload for creating Mutasi Siswa (public)
 type MutasiSiswaCreateRequest struct {
-	TahunPelajaranID int     `form:"tahun_pelajaran_id" binding:"required"`
-	Semester         int     `form:"semester" binding:"required"`
+	TahunPelajaranID int     `form:"tahun_pelajaran_id" binding:"required_with=Semester"`      // Default the current tahun pelajaran
+	Semester         int     `form:"semester" binding:"required_with=TahunPelajaranID,omitempty,oneof=1 2"` // Default the current semester
 	NamaLengkap      string  `form:"nama_lengkap" binding:"required"`
 	NamaPanggilan    *string `form:"nama_panggilan"`
 	NISN             *string `form:"nisn"`
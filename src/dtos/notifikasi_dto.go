@@ -0,0 +1,73 @@
+package dtos
+
+import "time"
+
+// NotifikasiTemplateUpdateRequest represents the request payload for updating a notifikasi template.
+// Subject and body are Go text/templates with {{.Nama}}, {{.NIS}}, {{.Rombel}}, {{.Tanggal}}, {{.Jam}}
+// and {{.NamaOrtu}}.
+type NotifikasiTemplateUpdateRequest struct {
+	ID      uint    `json:"id" binding:"required"`
+	Subject *string `json:"subject" binding:"omitempty,max=255"`
+	Body    *string `json:"body" binding:"omitempty,min=1"`
+	Status  *string `json:"status" binding:"omitempty,oneof=active inactive"`
+}
+
+// NotifikasiTemplateResponse represents the response payload for NotifikasiTemplate
+type NotifikasiTemplateResponse struct {
+	ID            uint      `json:"id"`
+	Event         string    `json:"event"`
+	Channel       string    `json:"channel"`
+	Subject       *string   `json:"subject"`
+	Body          string    `json:"body"`
+	Status        string    `json:"status"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	UpdatedByID   *uint     `json:"updated_by_id"`
+	UpdatedByType *string   `json:"updated_by_type"`
+}
+
+// NotifikasiOutboxResponse represents the response payload for NotifikasiOutbox
+type NotifikasiOutboxResponse struct {
+	ID             uint       `json:"id"`
+	Event          string     `json:"event"`
+	Channel        string     `json:"channel"`
+	PesertaDidikID *uint      `json:"peserta_didik_id"`
+	NamaSiswa      string     `json:"nama_siswa"`
+	Recipient      string     `json:"recipient"`
+	Subject        *string    `json:"subject"`
+	Body           string     `json:"body"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	LastError      *string    `json:"last_error"`
+	SentAt         *time.Time `json:"sent_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// NotifikasiOutboxGetAllRequest represents the request payload for getting the outbox with filters
+type NotifikasiOutboxGetAllRequest struct {
+	Search struct {
+		Status         string `json:"status"`  // pending, sent, failed
+		Event          string `json:"event"`   // terlambat, alpa
+		Channel        string `json:"channel"` // email, whatsapp
+		PesertaDidikID *uint  `json:"peserta_didik_id"`
+	} `json:"search"`
+	Pagination struct {
+		Limit int `json:"limit"`
+		Page  int `json:"page"`
+	} `json:"pagination"`
+}
+
+// NotifikasiOutboxListWithPaginationResponse represents the response with pagination
+type NotifikasiOutboxListWithPaginationResponse struct {
+	Data       []NotifikasiOutboxResponse `json:"data"`
+	Pagination PaginationInfo             `json:"pagination"`
+}
+
+// NotifikasiOutboxProcessResult summarises one pass over the due outbox messages
+type NotifikasiOutboxProcessResult struct {
+	Processed int `json:"processed"`
+	Sent      int `json:"sent"`
+	Retrying  int `json:"retrying"` // Failed, scheduled for another attempt
+	Failed    int `json:"failed"`   // Failed for the last time
+}
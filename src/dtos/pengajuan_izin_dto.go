@@ -0,0 +1,105 @@
+package dtos
+
+import "time"
+
+// PengajuanIzinCreateRequest represents the request payload for submitting an izin/sakit request
+// from the siswa portal. The surat (doctor's note for sakit) is sent as the file_surat form file.
+type PengajuanIzinCreateRequest struct {
+	Jenis          string `form:"jenis" binding:"required,oneof=izin sakit"`
+	TanggalMulai   string `form:"tanggal_mulai" binding:"required"`   // YYYY-MM-DD
+	TanggalSelesai string `form:"tanggal_selesai" binding:"required"` // YYYY-MM-DD
+	Alasan         string `form:"alasan" binding:"required"`
+	NamaPengaju    string `form:"nama_pengaju" binding:"omitempty,max=255"` // Default nama siswa
+	TeleponPengaju string `form:"telepon_pengaju" binding:"omitempty,max=20"`
+}
+
+// PengajuanIzinPublicCreateRequest represents the request payload for submitting an izin/sakit request
+// from the public form. The student is identified by NIS and tanggal lahir.
+type PengajuanIzinPublicCreateRequest struct {
+	NIS          string `form:"nis" binding:"required"`
+	TanggalLahir string `form:"tanggal_lahir" binding:"required"` // YYYY-MM-DD
+	PengajuanIzinCreateRequest
+}
+
+// PengajuanIzinResponse represents the response payload for PengajuanIzin
+type PengajuanIzinResponse struct {
+	ID                 uint       `json:"id"`
+	IDTiket            string     `json:"id_tiket"`
+	PesertaDidikID     uint       `json:"peserta_didik_id"`
+	NIS                string     `json:"nis"`
+	NamaSiswa          string     `json:"nama_siswa"`
+	RombelID           uint       `json:"rombel_id"`
+	Rombel             string     `json:"rombel"`
+	TahunPelajaranID   uint       `json:"tahun_pelajaran_id"`
+	Jenis              string     `json:"jenis"`
+	TanggalMulai       string     `json:"tanggal_mulai"`
+	TanggalSelesai     string     `json:"tanggal_selesai"`
+	Alasan             string     `json:"alasan"`
+	FileSurat          string     `json:"file_surat"`
+	NamaPengaju        string     `json:"nama_pengaju"`
+	TeleponPengaju     string     `json:"telepon_pengaju"`
+	DiajukanMelalui    string     `json:"diajukan_melalui"`
+	Status             string     `json:"status"`
+	CatatanReview      *string    `json:"catatan_review"`
+	JumlahHariTercatat int        `json:"jumlah_hari_tercatat"`
+	ReviewedByID       *uint      `json:"reviewed_by_id"`
+	ReviewedByType     *string    `json:"reviewed_by_type"`
+	ReviewedAt         *time.Time `json:"reviewed_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+// PengajuanIzinTrackRequest represents the request payload for tracking PengajuanIzin by ID Tiket
+type PengajuanIzinTrackRequest struct {
+	IDTiket string `json:"id_tiket" binding:"required"`
+}
+
+// PengajuanIzinTrackResponse represents the simplified response for tracking
+type PengajuanIzinTrackResponse struct {
+	IDTiket        string     `json:"id_tiket"`
+	NamaSiswa      string     `json:"nama_siswa"`
+	Rombel         string     `json:"rombel"`
+	Jenis          string     `json:"jenis"`
+	TanggalMulai   string     `json:"tanggal_mulai"`
+	TanggalSelesai string     `json:"tanggal_selesai"`
+	Status         string     `json:"status"`
+	CatatanReview  *string    `json:"catatan_review"`
+	ReviewedAt     *time.Time `json:"reviewed_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// PengajuanIzinGetAllRequest represents the request for the approval queue with filters
+type PengajuanIzinGetAllRequest struct {
+	Search struct {
+		IDTiket          string `json:"id_tiket"`
+		Status           string `json:"status"` // pending, approved, rejected
+		Jenis            string `json:"jenis"`  // izin, sakit
+		RombelID         *uint  `json:"rombel_id"`
+		PesertaDidikID   *uint  `json:"peserta_didik_id"`
+		TahunPelajaranID *uint  `json:"tahun_pelajaran_id"`
+		TanggalMulai     string `json:"tanggal_mulai"`   // YYYY-MM-DD
+		TanggalSelesai   string `json:"tanggal_selesai"` // YYYY-MM-DD
+	} `json:"search"`
+	Pagination struct {
+		Limit int `json:"limit"`
+		Page  int `json:"page"`
+	} `json:"pagination"`
+}
+
+// PengajuanIzinListWithPaginationResponse represents the response with pagination
+type PengajuanIzinListWithPaginationResponse struct {
+	Data       []PengajuanIzinResponse `json:"data"`
+	Pagination PaginationInfo          `json:"pagination"`
+}
+
+// PengajuanIzinReviewRequest represents the request payload for approving or rejecting a PengajuanIzin
+type PengajuanIzinReviewRequest struct {
+	ID      uint   `json:"id" binding:"required"`
+	Catatan string `json:"catatan"` // Required when rejecting
+}
+
+// PengajuanIzinApproveResponse represents the result of an approval
+type PengajuanIzinApproveResponse struct {
+	Pengajuan       PengajuanIzinResponse `json:"pengajuan"`
+	HariTercatat    int                   `json:"hari_tercatat"`
+	TanggalDilewati []string              `json:"tanggal_dilewati"` // Already recorded by a guru or scan, kept as is
+}
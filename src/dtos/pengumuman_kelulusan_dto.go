@@ -1,5 +1,7 @@
 package dtos
 
+import "pintu-backend/src/modules/models"
+
 // PengumumanKelulusanConfigRequest represents the request for configuring pengumuman kelulusan
 type PengumumanKelulusanConfigRequest struct {
 	ID                         *uint  `json:"id"` // Optional: if provided, update; if not, create
@@ -13,15 +15,19 @@ type PengumumanKelulusanConfigRequest struct {
 
 // PengumumanKelulusanResponse represents the response for pengumuman kelulusan
 type PengumumanKelulusanResponse struct {
-	ID                         uint   `json:"id"`
-	SambutanKelulusan          string `json:"sambutan_kelulusan"`
-	TanggalPengumumanNilai     string `json:"tanggal_pengumuman_nilai"`
-	TanggalPengumumanKelulusan string `json:"tanggal_pengumuman_kelulusan"`
-	FotoKepsek                 string `json:"foto_kepsek,omitempty"`
-	TtdKepsek                  string `json:"ttd_kepsek,omitempty"`
-	NamaKepsek                 string `json:"nama_kepsek,omitempty"`
-	CreatedAt                  string `json:"created_at"`
-	UpdatedAt                  string `json:"updated_at"`
-	CreatedByID                *uint  `json:"created_by_id,omitempty"`
-	UpdatedByID                *uint  `json:"updated_by_id,omitempty"`
+	ID                         uint                 `json:"id"`
+	SambutanKelulusan          string               `json:"sambutan_kelulusan"`
+	TanggalPengumumanNilai     string               `json:"tanggal_pengumuman_nilai"`
+	TanggalPengumumanKelulusan string               `json:"tanggal_pengumuman_kelulusan"`
+	FotoKepsek                 string               `json:"foto_kepsek,omitempty"`
+	TtdKepsek                  string               `json:"ttd_kepsek,omitempty"`
+	NamaKepsek                 string               `json:"nama_kepsek,omitempty"`
+	CreatedAt                  string               `json:"created_at"`
+	UpdatedAt                  string               `json:"updated_at"`
+	CreatedByID                *uint                `json:"created_by_id,omitempty"`
+	CreatedByType              *string              `json:"created_by_type,omitempty"`
+	UpdatedByID                *uint                `json:"updated_by_id,omitempty"`
+	UpdatedByType              *string              `json:"updated_by_type,omitempty"`
+	CreatedBy                  *models.PrincipalRef `json:"created_by,omitempty"`
+	UpdatedBy                  *models.PrincipalRef `json:"updated_by,omitempty"`
 }
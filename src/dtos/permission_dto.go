@@ -24,17 +24,19 @@ type PermissionUpdateRequest struct {
 
 // PermissionResponse represents the response payload for Permission
 type PermissionResponse struct {
-	ID          uint             `json:"id"`
-	Name        string           `json:"name"`
-	Description string           `json:"description"`
-	GroupName   string           `json:"group_name"`
-	SystemID    *uint            `json:"system_id"`
-	System      *SystemResponse  `json:"system"`
-	Status      string           `json:"status"`
-	CreatedAt   time.Time        `json:"created_at"`
-	UpdatedAt   time.Time        `json:"updated_at"`
-	CreatedByID *uint            `json:"created_by_id"`
-	UpdatedByID *uint            `json:"updated_by_id"`
+	ID            uint            `json:"id"`
+	Name          string          `json:"name"`
+	Description   string          `json:"description"`
+	GroupName     string          `json:"group_name"`
+	SystemID      *uint           `json:"system_id"`
+	System        *SystemResponse `json:"system"`
+	Status        string          `json:"status"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+	CreatedByID   *uint           `json:"created_by_id"`
+	CreatedByType *string         `json:"created_by_type"`
+	UpdatedByID   *uint           `json:"updated_by_id"`
+	UpdatedByType *string         `json:"updated_by_type"`
 }
 
 // PermissionListResponse represents the response payload for listing Permission
@@ -18,6 +18,9 @@ type PesertaDidikCreateRequest struct {
 	KodePos            string `json:"kode_pos" binding:"omitempty"`
 	NamaAyah           string `json:"nama_ayah" binding:"omitempty"`
 	NamaIbu            string `json:"nama_ibu" binding:"omitempty"`
+	NomorHPOrtu        string `json:"nomor_hp_ortu" binding:"omitempty,max=20"`
+	EmailOrtu          string `json:"email_ortu" binding:"omitempty,email"`
+	NotifikasiOrtu     bool   `json:"notifikasi_ortu"`
 	Status             string `json:"status" binding:"omitempty"`
 	Username           string `json:"username" binding:"omitempty"`
 	Password           string `json:"password" binding:"omitempty,min=3"`
@@ -43,6 +46,9 @@ type PesertaDidikUpdateRequest struct {
 	KodePos            string   `json:"kode_pos" binding:"omitempty"`
 	NamaAyah           string   `json:"nama_ayah" binding:"omitempty"`
 	NamaIbu            string   `json:"nama_ibu" binding:"omitempty"`
+	NomorHPOrtu        string   `json:"nomor_hp_ortu" binding:"omitempty,max=20"`
+	EmailOrtu          string   `json:"email_ortu" binding:"omitempty,email"`
+	NotifikasiOrtu     *bool    `json:"notifikasi_ortu" binding:"omitempty"`
 	Status             string   `json:"status" binding:"omitempty"`
 	Username           string   `json:"username" binding:"omitempty"`
 	Password           string   `json:"password" binding:"omitempty,min=3"`
@@ -71,44 +77,51 @@ type RombelDetailResponse struct {
 
 // TahunPelajaranDetailResponse represents tahun pelajaran details in response
 type TahunPelajaranDetailResponse struct {
-	ID               uint   `json:"id"`
-	TahunPelajaran   string `json:"tahun_pelajaran"`
-	Status           string `json:"status"`
-	CreatedAt        string `json:"created_at"`
-	UpdatedAt        string `json:"updated_at"`
-	CreatedByID      *uint  `json:"created_by_id"`
-	UpdatedByID      *uint  `json:"updated_by_id"`
+	ID             uint    `json:"id"`
+	TahunPelajaran string  `json:"tahun_pelajaran"`
+	Status         string  `json:"status"`
+	CreatedAt      string  `json:"created_at"`
+	UpdatedAt      string  `json:"updated_at"`
+	CreatedByID    *uint   `json:"created_by_id"`
+	CreatedByType  *string `json:"created_by_type"`
+	UpdatedByID    *uint   `json:"updated_by_id"`
+	UpdatedByType  *string `json:"updated_by_type"`
 }
 
 // PesertaDidikResponse represents the response payload for PesertaDidik
 type PesertaDidikResponse struct {
-	ID               uint                          `json:"id"`
-	Nama             string                        `json:"nama"`
-	NIS              string                        `json:"nis"`
-	JenisKelamin     string                        `json:"jenis_kelamin"`
-	NISN             string                        `json:"nisn"`
-	TempatLahir      string                        `json:"tempat_lahir"`
-	TanggalLahir     string                        `json:"tanggal_lahir"`
-	NIK              string                        `json:"nik"`
-	Agama            string                        `json:"agama"`
-	Alamat           string                        `json:"alamat"`
-	RT               string                        `json:"rt"`
-	RW               string                        `json:"rw"`
-	Kelurahan        string                        `json:"kelurahan"`
-	Kecamatan        string                        `json:"kecamatan"`
-	KodePos          string                        `json:"kode_pos"`
-	NamaAyah         string                        `json:"nama_ayah"`
-	NamaIbu          string                        `json:"nama_ibu"`
-	Status           string                        `json:"status"`
-	Username         string                        `json:"username"`
-	Photo            string                        `json:"photo,omitempty"`
-	Barcode          string                        `json:"barcode,omitempty"`
-	BarcodeGeneratedAt string                      `json:"barcode_generated_at,omitempty"`
-	Roles            []RoleResponse                `json:"roles"`
-	CreatedAt        string                        `json:"created_at"`
-	UpdatedAt        string                        `json:"updated_at"`
-	CreatedByID      *uint                         `json:"created_by_id"`
-	UpdatedByID      *uint                         `json:"updated_by_id"`
+	ID                 uint           `json:"id"`
+	Nama               string         `json:"nama"`
+	NIS                string         `json:"nis"`
+	JenisKelamin       string         `json:"jenis_kelamin"`
+	NISN               string         `json:"nisn"`
+	TempatLahir        string         `json:"tempat_lahir"`
+	TanggalLahir       string         `json:"tanggal_lahir"`
+	NIK                string         `json:"nik"`
+	Agama              string         `json:"agama"`
+	Alamat             string         `json:"alamat"`
+	RT                 string         `json:"rt"`
+	RW                 string         `json:"rw"`
+	Kelurahan          string         `json:"kelurahan"`
+	Kecamatan          string         `json:"kecamatan"`
+	KodePos            string         `json:"kode_pos"`
+	NamaAyah           string         `json:"nama_ayah"`
+	NamaIbu            string         `json:"nama_ibu"`
+	NomorHPOrtu        string         `json:"nomor_hp_ortu"`
+	EmailOrtu          string         `json:"email_ortu"`
+	NotifikasiOrtu     bool           `json:"notifikasi_ortu"`
+	Status             string         `json:"status"`
+	Username           string         `json:"username"`
+	Photo              string         `json:"photo,omitempty"`
+	Barcode            string         `json:"barcode,omitempty"`
+	BarcodeGeneratedAt string         `json:"barcode_generated_at,omitempty"`
+	Roles              []RoleResponse `json:"roles"`
+	CreatedAt          string         `json:"created_at"`
+	UpdatedAt          string         `json:"updated_at"`
+	CreatedByID        *uint          `json:"created_by_id"`
+	CreatedByType      *string        `json:"created_by_type"`
+	UpdatedByID        *uint          `json:"updated_by_id"`
+	UpdatedByType      *string        `json:"updated_by_type"`
 }
 
 // PesertaDidikListResponse represents list response
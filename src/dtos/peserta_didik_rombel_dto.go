@@ -43,7 +43,9 @@ type PesertaDidikRombelResponse struct {
 	CreatedAt        string                        `json:"created_at"`
 	UpdatedAt        string                        `json:"updated_at"`
 	CreatedByID      *uint                         `json:"created_by_id"`
+	CreatedByType    *string                       `json:"created_by_type"`
 	UpdatedByID      *uint                         `json:"updated_by_id"`
+	UpdatedByType    *string                       `json:"updated_by_type"`
 }
 
 // PesertaDidikRombelBulkCreateResponse represents the response for bulk create
@@ -6,11 +6,14 @@ import (
 
 // FotoItemDTO represents a single photo in the foto array
 type FotoItemDTO struct {
-	ID        string `json:"id"`
-	Filename  string `json:"filename"`
-	URL       string `json:"url"`
-	Size      int64  `json:"size"`
-	Thumbnail string `json:"thumbnail"` // "active" or "inactive"
+	ID        string                    `json:"id"`
+	Filename  string                    `json:"filename"`
+	URL       string                    `json:"url"`
+	Size      int64                     `json:"size"`
+	Thumbnail string                    `json:"thumbnail"` // "active" or "inactive"
+	Width     int                       `json:"width,omitempty"`
+	Height    int                       `json:"height,omitempty"`
+	Variants  map[string]FileVariantDTO `json:"variants,omitempty"` // "thumbnail", "medium" and "original"
 }
 
 // AnggotaTimPrestasiDTO represents anggota tim prestasi details
@@ -43,7 +46,7 @@ type PrestasiCreateRequest struct {
 	Juara             string `json:"juara" binding:"required"`
 	Keterangan        string `json:"keterangan" binding:"omitempty"`
 	EkstrakurikulerID *uint  `json:"ekstrakurikuler_id" binding:"omitempty"`
-	TahunPelajaranID  uint   `json:"tahun_pelajaran_id" binding:"required"`
+	TahunPelajaranID  uint   `json:"tahun_pelajaran_id" binding:"omitempty"` // Default the tahun pelajaran of tanggal_lomba
 	Status            string `json:"status" binding:"omitempty,oneof=active inactive"`
 	// Anggota tim data
 	AnggotaTim        []AnggotaTimCreateRequest `json:"anggota_tim" binding:"omitempty"`
@@ -86,24 +89,26 @@ type PrestasiResponse struct {
 	PesertaDidikRombelID *uint                         `json:"peserta_didik_rombel_id"`
 	PesertaDidikRombel   *PesertaDidikRombelResponse   `json:"peserta_didik_rombel"`
 	Jenis                string                        `json:"jenis"`
-	NamaGrup           string                      `json:"nama_grup"`
-	NamaPrestasi       string                      `json:"nama_prestasi"`
-	TingkatPrestasi    string                      `json:"tingkat_prestasi"`
-	Penyelenggara      string                      `json:"penyelenggara"`
-	TanggalLomba       time.Time                   `json:"tanggal_lomba"`
-	Juara              string                      `json:"juara"`
-	Keterangan         string                      `json:"keterangan"`
-	Foto               []FotoItemDTO               `json:"foto"`
-	EkstrakurikulerID  *uint                       `json:"ekstrakurikuler_id"`
-	Ekstrakurikuler    *EkstrakurikulerDetailDTO   `json:"ekstrakurikuler"`
-	TahunPelajaranID   uint                        `json:"tahun_pelajaran_id"`
-	TahunPelajaran     *TahunPelajaranDetailResponse `json:"tahun_pelajaran"`
-	Status             string                      `json:"status"`
-	AnggotaTimPrestasi []AnggotaTimPrestasiDTO     `json:"anggota_tim_prestasi"`
-	CreatedAt          time.Time                   `json:"created_at"`
-	UpdatedAt          time.Time                   `json:"updated_at"`
-	CreatedByID        *uint                       `json:"created_by_id"`
-	UpdatedByID        *uint                       `json:"updated_by_id"`
+	NamaGrup             string                        `json:"nama_grup"`
+	NamaPrestasi         string                        `json:"nama_prestasi"`
+	TingkatPrestasi      string                        `json:"tingkat_prestasi"`
+	Penyelenggara        string                        `json:"penyelenggara"`
+	TanggalLomba         time.Time                     `json:"tanggal_lomba"`
+	Juara                string                        `json:"juara"`
+	Keterangan           string                        `json:"keterangan"`
+	Foto                 []FotoItemDTO                 `json:"foto"`
+	EkstrakurikulerID    *uint                         `json:"ekstrakurikuler_id"`
+	Ekstrakurikuler      *EkstrakurikulerDetailDTO     `json:"ekstrakurikuler"`
+	TahunPelajaranID     uint                          `json:"tahun_pelajaran_id"`
+	TahunPelajaran       *TahunPelajaranDetailResponse `json:"tahun_pelajaran"`
+	Status               string                        `json:"status"`
+	AnggotaTimPrestasi   []AnggotaTimPrestasiDTO       `json:"anggota_tim_prestasi"`
+	CreatedAt            time.Time                     `json:"created_at"`
+	UpdatedAt            time.Time                     `json:"updated_at"`
+	CreatedByID          *uint                         `json:"created_by_id"`
+	CreatedByType        *string                       `json:"created_by_type"`
+	UpdatedByID          *uint                         `json:"updated_by_id"`
+	UpdatedByType        *string                       `json:"updated_by_type"`
 }
 
 // PrestasiListResponse represents the response payload for listing Prestasi
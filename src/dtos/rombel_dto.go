@@ -26,15 +26,17 @@ type KelasDetail struct {
 
 // RombelResponse represents the response payload for Rombel
 type RombelResponse struct {
-	ID          uint        `json:"id"`
-	Name        string      `json:"name"`
-	Status      string      `json:"status"`
-	KelasID     uint        `json:"kelas_id"`
-	Kelas       KelasDetail `json:"kelas"`
-	CreatedAt   time.Time   `json:"created_at"`
-	UpdatedAt   time.Time   `json:"updated_at"`
-	CreatedByID *uint       `json:"created_by_id"`
-	UpdatedByID *uint       `json:"updated_by_id"`
+	ID            uint        `json:"id"`
+	Name          string      `json:"name"`
+	Status        string      `json:"status"`
+	KelasID       uint        `json:"kelas_id"`
+	Kelas         KelasDetail `json:"kelas"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	CreatedByID   *uint       `json:"created_by_id"`
+	CreatedByType *string     `json:"created_by_type"`
+	UpdatedByID   *uint       `json:"updated_by_id"`
+	UpdatedByType *string     `json:"updated_by_type"`
 }
 
 // RombelListResponse represents the response payload for listing Rombel
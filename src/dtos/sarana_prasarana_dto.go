@@ -17,14 +17,16 @@ type SaranaPrasaranaUpdateRequest struct {
 
 // SaranaPrasaranaResponse represents the response payload for SaranaPrasarana
 type SaranaPrasaranaResponse struct {
-	ID          uint      `json:"id"`
-	Name        string    `json:"name"`
-	Foto        string    `json:"foto"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	CreatedByID *uint     `json:"created_by_id"`
-	UpdatedByID *uint     `json:"updated_by_id"`
+	ID            uint      `json:"id"`
+	Name          string    `json:"name"`
+	Foto          string    `json:"foto"`
+	Status        string    `json:"status"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	CreatedByID   *uint     `json:"created_by_id"`
+	CreatedByType *string   `json:"created_by_type"`
+	UpdatedByID   *uint     `json:"updated_by_id"`
+	UpdatedByType *string   `json:"updated_by_type"`
 }
 
 // SaranaPrasaranaListResponse represents the response payload for listing SaranaPrasarana
@@ -0,0 +1,76 @@
+package dtos
+
+import "time"
+
+// ScannerDeviceCreateRequest represents the request payload for registering a scanner device
+type ScannerDeviceCreateRequest struct {
+	Nama            string   `json:"nama" binding:"required,min=2,max=100"`
+	Lokasi          *string  `json:"lokasi" binding:"omitempty,max=255"`
+	AuthMode        string   `json:"auth_mode" binding:"omitempty,oneof=api_key hmac"`
+	AllowedCIDRs    []string `json:"allowed_cidrs"`     // e.g. ["10.10.0.0/24", "203.0.113.7"]; empty means any IP
+	JamAktifMulai   *string  `json:"jam_aktif_mulai"`   // Format: "HH:MM" atau "HH:MM:SS"
+	JamAktifSelesai *string  `json:"jam_aktif_selesai"` // Format: "HH:MM" atau "HH:MM:SS"
+	Status          string   `json:"status" binding:"omitempty,oneof=active inactive"`
+}
+
+// ScannerDeviceUpdateRequest represents the request payload for updating a scanner device.
+// An empty jam_aktif_mulai/jam_aktif_selesai removes the time window.
+type ScannerDeviceUpdateRequest struct {
+	ID              uint      `json:"id" binding:"required"`
+	Nama            *string   `json:"nama" binding:"omitempty,min=2,max=100"`
+	Lokasi          *string   `json:"lokasi" binding:"omitempty,max=255"`
+	AllowedCIDRs    *[]string `json:"allowed_cidrs"`
+	JamAktifMulai   *string   `json:"jam_aktif_mulai"`
+	JamAktifSelesai *string   `json:"jam_aktif_selesai"`
+	Status          *string   `json:"status" binding:"omitempty,oneof=active inactive"`
+}
+
+// ScannerDeviceRotateSecretRequest represents the request payload for issuing a new device credential
+type ScannerDeviceRotateSecretRequest struct {
+	ID       uint    `json:"id" binding:"required"`
+	AuthMode *string `json:"auth_mode" binding:"omitempty,oneof=api_key hmac"`
+}
+
+// ScannerDeviceResponse represents the response payload for ScannerDevice
+type ScannerDeviceResponse struct {
+	ID              uint       `json:"id"`
+	Nama            string     `json:"nama"`
+	Lokasi          *string    `json:"lokasi"`
+	AuthMode        string     `json:"auth_mode"`
+	AllowedCIDRs    []string   `json:"allowed_cidrs"`
+	JamAktifMulai   *string    `json:"jam_aktif_mulai"`
+	JamAktifSelesai *string    `json:"jam_aktif_selesai"`
+	Status          string     `json:"status"`
+	LastSeenAt      *time.Time `json:"last_seen_at"`
+	LastSeenIP      *string    `json:"last_seen_ip"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	CreatedByID     *uint      `json:"created_by_id"`
+	CreatedByType   *string    `json:"created_by_type"`
+	UpdatedByID     *uint      `json:"updated_by_id"`
+	UpdatedByType   *string    `json:"updated_by_type"`
+}
+
+// ScannerDeviceCredentialResponse is returned once on create and rotate; the secret is never shown again
+type ScannerDeviceCredentialResponse struct {
+	ScannerDeviceResponse
+	Secret string `json:"secret"`
+}
+
+// ScannerDeviceGetAllRequest represents the request payload for getting all scanner devices with filters
+type ScannerDeviceGetAllRequest struct {
+	Search struct {
+		Nama   string `json:"nama"`
+		Status string `json:"status"`
+	} `json:"search"`
+	Pagination struct {
+		Limit int `json:"limit"`
+		Page  int `json:"page"`
+	} `json:"pagination"`
+}
+
+// ScannerDeviceListWithPaginationResponse represents the response with pagination
+type ScannerDeviceListWithPaginationResponse struct {
+	Data       []ScannerDeviceResponse `json:"data"`
+	Pagination PaginationInfo          `json:"pagination"`
+}
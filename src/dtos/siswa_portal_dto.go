@@ -0,0 +1,20 @@
+package dtos
+
+// SiswaAbsensiRequest represents the request payload for the attendance history of the logged-in student
+type SiswaAbsensiRequest struct {
+	TahunPelajaranID *uint  `json:"tahun_pelajaran_id" binding:"omitempty"` // Default tahun pelajaran aktif
+	Semester         *int   `json:"semester" binding:"omitempty,oneof=1 2"`
+	BidangStudiID    *uint  `json:"bidang_studi_id" binding:"omitempty"`
+	Periode          string `json:"periode" binding:"required,oneof=harian mingguan bulanan"`
+	TanggalMulai     string `json:"tanggal_mulai" binding:"omitempty"`
+	TanggalSelesai   string `json:"tanggal_selesai" binding:"omitempty"`
+	LimitRiwayat     int    `json:"limit_riwayat" binding:"omitempty,min=1,max=100"` // Default 10, max 100
+}
+
+// KartuQRResponse represents a rotating signed QR of the kartu pelajar, to be shown on screen and scanned before it expires
+type KartuQRResponse struct {
+	Payload       string `json:"payload"`
+	QRCode        string `json:"qr_code"`        // PNG as data URI
+	BerlakuHingga string `json:"berlaku_hingga"` // RFC3339
+	RefreshDetik  int    `json:"refresh_detik"`  // Fetch a new QR after this many seconds
+}
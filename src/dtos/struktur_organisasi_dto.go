@@ -1,6 +1,9 @@
 package dtos
 
-import "time"
+import (
+	"pintu-backend/src/modules/models"
+	"time"
+)
 
 // StrukturOrganisasiCreateRequest represents the request payload for creating StrukturOrganisasi
 type StrukturOrganisasiCreateRequest struct {
@@ -73,18 +76,22 @@ type JabatanGroupResponse struct {
 
 // StrukturOrganisasiResponse represents the response payload for StrukturOrganisasi
 type StrukturOrganisasiResponse struct {
-	ID                uint                     `json:"id"`
-	PegawaiID         *uint                    `json:"pegawai_id"`
-	Pegawai           *PegawaiSimpleResponse   `json:"pegawai,omitempty"`
-	NamaNonPegawai    string                   `json:"nama_non_pegawai"`
-	JabatanNonPegawai string                   `json:"jabatan_non_pegawai"`
-	Urutan            int                      `json:"urutan"`
-	Relasi            string                   `json:"relasi"`
-	Status            string                   `json:"status"`
-	CreatedAt         time.Time                `json:"created_at"`
-	UpdatedAt         time.Time                `json:"updated_at"`
-	CreatedByID       *uint                    `json:"created_by_id"`
-	UpdatedByID       *uint                    `json:"updated_by_id"`
+	ID                uint                   `json:"id"`
+	PegawaiID         *uint                  `json:"pegawai_id"`
+	Pegawai           *PegawaiSimpleResponse `json:"pegawai,omitempty"`
+	NamaNonPegawai    string                 `json:"nama_non_pegawai"`
+	JabatanNonPegawai string                 `json:"jabatan_non_pegawai"`
+	Urutan            int                    `json:"urutan"`
+	Relasi            string                 `json:"relasi"`
+	Status            string                 `json:"status"`
+	CreatedAt         time.Time              `json:"created_at"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+	CreatedByID       *uint                  `json:"created_by_id"`
+	CreatedByType     *string                `json:"created_by_type"`
+	UpdatedByID       *uint                  `json:"updated_by_id"`
+	UpdatedByType     *string                `json:"updated_by_type"`
+	CreatedBy         *models.PrincipalRef   `json:"created_by,omitempty"`
+	UpdatedBy         *models.PrincipalRef   `json:"updated_by,omitempty"`
 }
 
 // StrukturOrganisasiListResponse represents the response payload for listing StrukturOrganisasi
@@ -7,6 +7,7 @@ import (
 // SystemCreateRequest represents the request payload for creating System
 type SystemCreateRequest struct {
 	Nama        string `json:"nama" binding:"required"`
+	Code        string `json:"code" binding:"omitempty"`
 	Description string `json:"description" binding:"omitempty"`
 	Status      string `json:"status" binding:"omitempty"`
 }
@@ -14,20 +15,24 @@ type SystemCreateRequest struct {
 // SystemUpdateRequest represents the request payload for updating System
 type SystemUpdateRequest struct {
 	Nama        string `json:"nama" binding:"omitempty"`
+	Code        string `json:"code" binding:"omitempty"`
 	Description string `json:"description" binding:"omitempty"`
 	Status      string `json:"status" binding:"omitempty"`
 }
 
 // SystemResponse represents the response payload for System
 type SystemResponse struct {
-	ID          uint      `json:"id"`
-	Nama        string    `json:"nama"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	CreatedByID *uint     `json:"created_by_id"`
-	UpdatedByID *uint     `json:"updated_by_id"`
+	ID            uint      `json:"id"`
+	Nama          string    `json:"nama"`
+	Code          string    `json:"code"`
+	Description   string    `json:"description"`
+	Status        string    `json:"status"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	CreatedByID   *uint     `json:"created_by_id"`
+	CreatedByType *string   `json:"created_by_type"`
+	UpdatedByID   *uint     `json:"updated_by_id"`
+	UpdatedByType *string   `json:"updated_by_type"`
 }
 
 // SystemGetAllRequest represents the request payload for getting all systems with filters
@@ -3,27 +3,42 @@ package dtos
 import "time"
 
 // TahunPelajaranCreateRequest represents the request payload for creating TahunPelajaran
+// The semester dates default to 1 Juli - 31 Desember and 1 Januari - 30 Juni when tahun_pelajaran is "YYYY/YYYY"
 type TahunPelajaranCreateRequest struct {
-	TahunPelajaran string `json:"tahun_pelajaran" binding:"required,min=4,max=20"`
-	Status         string `json:"status" binding:"omitempty,oneof=active inactive"`
+	TahunPelajaran   string `json:"tahun_pelajaran" binding:"required,min=4,max=20"`
+	Status           string `json:"status" binding:"omitempty,oneof=active inactive"`
+	Semester1Mulai   string `json:"semester1_mulai" binding:"omitempty"`   // YYYY-MM-DD
+	Semester1Selesai string `json:"semester1_selesai" binding:"omitempty"` // YYYY-MM-DD
+	Semester2Mulai   string `json:"semester2_mulai" binding:"omitempty"`   // YYYY-MM-DD
+	Semester2Selesai string `json:"semester2_selesai" binding:"omitempty"` // YYYY-MM-DD
 }
 
 // TahunPelajaranUpdateRequest represents the request payload for updating TahunPelajaran
 type TahunPelajaranUpdateRequest struct {
-	ID             uint    `json:"id" binding:"required"`
-	TahunPelajaran *string `json:"tahun_pelajaran" binding:"omitempty,min=4,max=20"`
-	Status         *string `json:"status" binding:"omitempty,oneof=active inactive"`
+	ID               uint    `json:"id" binding:"required"`
+	TahunPelajaran   *string `json:"tahun_pelajaran" binding:"omitempty,min=4,max=20"`
+	Status           *string `json:"status" binding:"omitempty,oneof=active inactive"`
+	Semester1Mulai   *string `json:"semester1_mulai" binding:"omitempty"`   // YYYY-MM-DD
+	Semester1Selesai *string `json:"semester1_selesai" binding:"omitempty"` // YYYY-MM-DD
+	Semester2Mulai   *string `json:"semester2_mulai" binding:"omitempty"`   // YYYY-MM-DD
+	Semester2Selesai *string `json:"semester2_selesai" binding:"omitempty"` // YYYY-MM-DD
 }
 
 // TahunPelajaranResponse represents the response payload for TahunPelajaran
 type TahunPelajaranResponse struct {
-	ID             uint      `json:"id"`
-	TahunPelajaran string    `json:"tahun_pelajaran"`
-	Status         string    `json:"status"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
-	CreatedByID    *uint     `json:"created_by_id"`
-	UpdatedByID    *uint     `json:"updated_by_id"`
+	ID               uint      `json:"id"`
+	TahunPelajaran   string    `json:"tahun_pelajaran"`
+	Status           string    `json:"status"`
+	Semester1Mulai   *string   `json:"semester1_mulai"`
+	Semester1Selesai *string   `json:"semester1_selesai"`
+	Semester2Mulai   *string   `json:"semester2_mulai"`
+	Semester2Selesai *string   `json:"semester2_selesai"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	CreatedByID      *uint     `json:"created_by_id"`
+	CreatedByType    *string   `json:"created_by_type"`
+	UpdatedByID      *uint     `json:"updated_by_id"`
+	UpdatedByType    *string   `json:"updated_by_type"`
 }
 
 // TahunPelajaranListResponse represents the response payload for listing TahunPelajaran
@@ -27,17 +27,26 @@ type UserUpdatePasswordRequest struct {
 	NewPassword string `json:"new_password" binding:"required,min=6"`
 }
 
+// UserUnlockRequest represents the request payload for unlocking a locked login account
+type UserUnlockRequest struct {
+	ID            uint   `json:"id" binding:"required"`
+	PrincipalType string `json:"principal_type" binding:"omitempty,oneof=user pegawai siswa"`
+}
+
 // UserResponse represents the response payload for User
 type UserResponse struct {
-	ID        uint            `json:"id"`
-	Nama      string          `json:"nama"`
-	Username  string          `json:"username"`
-	Roles     []RoleResponse  `json:"roles"`
-	Status    string          `json:"status"`
-	CreatedAt time.Time       `json:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at"`
-	CreatedByID *uint         `json:"created_by_id"`
-	UpdatedByID *uint         `json:"updated_by_id"`
+	ID            uint           `json:"id"`
+	Nama          string         `json:"nama"`
+	Username      string         `json:"username"`
+	Roles         []RoleResponse `json:"roles"`
+	Status        string         `json:"status"`
+	LockedUntil   *time.Time     `json:"locked_until"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	CreatedByID   *uint          `json:"created_by_id"`
+	CreatedByType *string        `json:"created_by_type"`
+	UpdatedByID   *uint          `json:"updated_by_id"`
+	UpdatedByType *string        `json:"updated_by_type"`
 }
 
 // UserListResponse represents the response payload for listing User
@@ -72,15 +81,18 @@ type PaginationInfo struct {
 
 // UserResponseDetail represents the detailed response payload for User
 type UserResponseDetail struct {
-	ID          uint            `json:"id"`
-	Nama        string          `json:"nama"`
-	Username    string          `json:"username"`
-	Roles       []RoleResponse  `json:"roles"`
-	Status      string          `json:"status"`
-	CreatedAt   time.Time       `json:"created_at"`
-	UpdatedAt   time.Time       `json:"updated_at"`
-	CreatedByID *uint           `json:"created_by_id"`
-	UpdatedByID *uint           `json:"updated_by_id"`
+	ID            uint           `json:"id"`
+	Nama          string         `json:"nama"`
+	Username      string         `json:"username"`
+	Roles         []RoleResponse `json:"roles"`
+	Status        string         `json:"status"`
+	LockedUntil   *time.Time     `json:"locked_until"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	CreatedByID   *uint          `json:"created_by_id"`
+	CreatedByType *string        `json:"created_by_type"`
+	UpdatedByID   *uint          `json:"updated_by_id"`
+	UpdatedByType *string        `json:"updated_by_type"`
 }
 
 // UserListWithPaginationResponse represents the response with pagination
@@ -98,6 +110,7 @@ type UserLoginRequest struct {
 // UserLoginResponse represents the response payload for user login
 type UserLoginResponse struct {
 	ID                    uint            `json:"id"`
+	PrincipalType         string          `json:"principal_type"`
 	Nama                  string          `json:"nama"`
 	Username              string          `json:"username"`
 	Roles                 []RoleResponse  `json:"roles"`
@@ -109,4 +122,7 @@ type UserLoginResponse struct {
 	RombelGuruKelasID     *uint           `json:"rombel_guru_kelas_id,omitempty"`
 	BidangStudiID         *uint           `json:"bidang_studi_id,omitempty"`
 	RombelBidangStudi     interface{}     `json:"rombel_bidang_studi,omitempty"`
+	// PesertaDidik fields (only populated for siswa login)
+	NIS                   *string         `json:"nis,omitempty"`
+	NISN                  *string         `json:"nisn,omitempty"`
 }
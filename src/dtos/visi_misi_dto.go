@@ -17,13 +17,15 @@ type VisiMisiUpdateRequest struct {
 
 // VisiMisiResponse represents the response payload for VisiMisi
 type VisiMisiResponse struct {
-	ID          uint      `json:"id"`
-	Visi        string    `json:"visi"`
-	Misi        string    `json:"misi"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	CreatedByID *uint     `json:"created_by_id"`
-	UpdatedByID *uint     `json:"updated_by_id"`
+	ID            uint      `json:"id"`
+	Visi          string    `json:"visi"`
+	Misi          string    `json:"misi"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	CreatedByID   *uint     `json:"created_by_id"`
+	CreatedByType *string   `json:"created_by_type"`
+	UpdatedByID   *uint     `json:"updated_by_id"`
+	UpdatedByType *string   `json:"updated_by_type"`
 }
 
 // VisiMisiListResponse represents the response payload for listing VisiMisi
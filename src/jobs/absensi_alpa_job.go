@@ -0,0 +1,72 @@
+package jobs
+
+import (
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/modules/services"
+	"pintu-backend/src/utils"
+
+	"gorm.io/gorm"
+)
+
+// absensiAlpaJobInterval is how often the job checks whether today's run is due
+const absensiAlpaJobInterval = time.Minute
+
+// NewAbsensiAlpaService wires the alpa service for the scheduler and the CLI
+func NewAbsensiAlpaService(db *gorm.DB) services.AbsensiAlpaService {
+	konfigurasiAbsensiRepo := repositories.NewKonfigurasiAbsensiRepository(db)
+	periodeService := services.NewPeriodeAkademikService(repositories.NewTahunPelajaranRepository(db))
+	kalenderService := services.NewKalenderAkademikService(repositories.NewKalenderAkademikRepository(db), konfigurasiAbsensiRepo, periodeService)
+	return services.NewAbsensiAlpaService(
+		repositories.NewAbsensiAlpaRepository(db),
+		periodeService,
+		konfigurasiAbsensiRepo,
+		kalenderService,
+		NewNotifikasiService(db),
+	)
+}
+
+// StartAbsensiAlpaJob runs the alpa marking once per day after jam_datang_selesai, in the background.
+// Set ABSENSI_ALPA_JOB_ENABLED=false to disable it, e.g. on all but one replica; runs are idempotent either way.
+func StartAbsensiAlpaJob(db *gorm.DB) {
+	if os.Getenv("ABSENSI_ALPA_JOB_ENABLED") == "false" {
+		log.Println("absensi alpa job disabled")
+		return
+	}
+
+	service := NewAbsensiAlpaService(db)
+
+	go func() {
+		var lastRun, lastErr string
+		ticker := time.NewTicker(absensiAlpaJobInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			today := time.Now().In(utils.JakartaLocation()).Format("2006-01-02")
+			if today == lastRun {
+				continue
+			}
+
+			report, err := service.Run(&dtos.AbsensiAlpaRunRequest{Tanggal: today, Trigger: "scheduler"})
+			if errors.Is(err, services.ErrJamDatangBelumSelesai) {
+				continue
+			}
+			if err != nil {
+				// Retried on the next tick, logged once until the error changes
+				if err.Error() != lastErr {
+					log.Printf("absensi alpa job %s: %v", today, err)
+					lastErr = err.Error()
+				}
+				continue
+			}
+
+			lastRun, lastErr = today, ""
+			log.Printf("absensi alpa job %s: %s", today, report.Message)
+		}
+	}()
+}
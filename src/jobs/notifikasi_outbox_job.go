@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/modules/services"
+	"pintu-backend/src/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// notifikasiOutboxJobInterval is how often the outbox is checked for due messages
+	notifikasiOutboxJobInterval = 30 * time.Second
+
+	// notifikasiOutboxBatchSize is the most messages sent per tick
+	notifikasiOutboxBatchSize = 50
+)
+
+// NewNotifikasiService wires the notifikasi service with the channels configured in the environment
+func NewNotifikasiService(db *gorm.DB) services.NotifikasiService {
+	return services.NewNotifikasiService(repositories.NewNotifikasiRepository(db), utils.NewNotifikasiChannels())
+}
+
+// StartNotifikasiOutboxJob delivers queued parent notifications in the background. Messages are leased
+// before sending, so several replicas can run it; set NOTIFIKASI_JOB_ENABLED=false to disable it.
+func StartNotifikasiOutboxJob(db *gorm.DB) {
+	if os.Getenv("NOTIFIKASI_JOB_ENABLED") == "false" {
+		log.Println("notifikasi outbox job disabled")
+		return
+	}
+
+	service := NewNotifikasiService(db)
+
+	go func() {
+		var lastErr string
+		ticker := time.NewTicker(notifikasiOutboxJobInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			result, err := service.ProcessOutbox(notifikasiOutboxBatchSize)
+			if err != nil {
+				// Logged once until the error changes
+				if err.Error() != lastErr {
+					log.Printf("notifikasi outbox job: %v", err)
+					lastErr = err.Error()
+				}
+				continue
+			}
+			lastErr = ""
+
+			if result.Processed > 0 {
+				log.Printf("notifikasi outbox job: %d sent, %d retrying, %d failed", result.Sent, result.Retrying, result.Failed)
+			}
+		}
+	}()
+}
@@ -4,13 +4,20 @@ import (
 	"net/http"
 	"strings"
 
+	"pintu-backend/src/modules/repositories"
 	"pintu-backend/src/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-// AuthMiddleware verifies JWT token
-func AuthMiddleware() gin.HandlerFunc {
+// principalContextKey is the gin context key holding the typed utils.Principal
+const principalContextKey = "principal"
+
+// AuthMiddleware verifies JWT token and rejects tokens on the revocation denylist
+func AuthMiddleware(db *gorm.DB) gin.HandlerFunc {
+	tokenRepository := repositories.NewAuthTokenRepository(db)
+
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -43,13 +50,44 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Check revocation (logout, deactivation, password change)
+		revoked, err := tokenRepository.IsAccessTokenRevoked(claims.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "failed to verify token",
+			})
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "token has been revoked",
+			})
+			c.Abort()
+			return
+		}
+
 		// Store claims in context
+		c.Set(principalContextKey, claims.Principal())
 		c.Set("userID", claims.UserID)
+		c.Set("principalType", claims.PrincipalType)
 		c.Set("username", claims.Username)
 		c.Set("nama", claims.Nama)
 		c.Set("roleID", claims.RoleID)
 		c.Set("status", claims.Status)
+		c.Set("jti", claims.ID)
+		c.Set("tokenExpiresAt", claims.ExpiresAt.Time)
 
 		c.Next()
 	}
 }
+
+// GetPrincipal returns the authenticated principal stored by AuthMiddleware
+func GetPrincipal(c *gin.Context) (utils.Principal, bool) {
+	value, exists := c.Get(principalContextKey)
+	if !exists {
+		return utils.Principal{}, false
+	}
+	principal, ok := value.(utils.Principal)
+	return principal, ok
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RequirePermission allows the request when the active role of the caller grants at
+// least one of the given permissions. Must run after AuthMiddleware.
+func RequirePermission(db *gorm.DB, permissions ...string) gin.HandlerFunc {
+	repository := repositories.NewPermissionRepository(db)
+	systemRepository := repositories.NewSystemRepository(db)
+
+	return func(c *gin.Context) {
+		principal, exists := GetPrincipal(c)
+		if !exists || principal.RoleID == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "unauthorized",
+			})
+			c.Abort()
+			return
+		}
+
+		cacheKey := fmt.Sprintf("%s:%d:%d", principal.Type, principal.ID, *principal.RoleID)
+		granted, ok := utils.GetCachedPermissions(cacheKey)
+		if !ok {
+			systemID, err := systemRepository.GetIDByCode(utils.PintuSystemCode())
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "failed to resolve permissions",
+				})
+				c.Abort()
+				return
+			}
+
+			names, err := repository.GetNamesByActiveRole(principal.Type, principal.ID, *principal.RoleID, systemID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "failed to resolve permissions",
+				})
+				c.Abort()
+				return
+			}
+			granted = utils.SetCachedPermissions(cacheKey, names)
+		}
+
+		for _, permission := range permissions {
+			if granted[permission] {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "you do not have permission to access this resource",
+		})
+		c.Abort()
+	}
+}
+
+// RequirePrincipalType allows the request only for the given principal types, e.g. to
+// keep the siswa portal away from staff tokens. Must run after AuthMiddleware.
+func RequirePrincipalType(principalTypes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, exists := GetPrincipal(c)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "unauthorized",
+			})
+			c.Abort()
+			return
+		}
+
+		for _, principalType := range principalTypes {
+			if principal.Type == principalType {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "you do not have permission to access this resource",
+		})
+		c.Abort()
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pintu-backend/src/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	utils.InvalidatePermissionCache()
+	admin, guru := uint(1), uint(2)
+
+	tests := []struct {
+		name       string
+		principal  *utils.Principal
+		granted    []string
+		wantStatus int
+	}{
+		{"holds the permission", &utils.Principal{ID: 1, Type: utils.PrincipalUser, RoleID: &admin}, []string{"READ_ARTICLE", "UPDATE_ARTICLE"}, http.StatusOK},
+		{"holds one of the permissions", &utils.Principal{ID: 2, Type: utils.PrincipalUser, RoleID: &admin}, []string{"UPDATE_ARTICLE"}, http.StatusOK},
+		{"holds other permissions", &utils.Principal{ID: 3, Type: utils.PrincipalUser, RoleID: &admin}, []string{"READ_USER"}, http.StatusForbidden},
+		{"holds no permission", &utils.Principal{ID: 4, Type: utils.PrincipalUser, RoleID: &admin}, nil, http.StatusForbidden},
+		{"pegawai with the id of a permitted user", &utils.Principal{ID: 1, Type: utils.PrincipalPegawai, RoleID: &admin}, nil, http.StatusForbidden},
+		{"other active role of a permitted user", &utils.Principal{ID: 1, Type: utils.PrincipalUser, RoleID: &guru}, nil, http.StatusForbidden},
+		{"no active role", &utils.Principal{ID: 1, Type: utils.PrincipalUser}, nil, http.StatusUnauthorized},
+		{"not authenticated", nil, nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A warm cache keeps the middleware away from the database
+			if tt.principal != nil && tt.principal.RoleID != nil {
+				utils.SetCachedPermissions(fmt.Sprintf("%s:%d:%d", tt.principal.Type, tt.principal.ID, *tt.principal.RoleID), tt.granted)
+			}
+
+			router := gin.New()
+			router.GET("/", func(c *gin.Context) {
+				if tt.principal != nil {
+					c.Set(principalContextKey, *tt.principal)
+				}
+			}, RequirePermission(nil, "READ_ARTICLE", "UPDATE_ARTICLE"), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+		})
+	}
+}
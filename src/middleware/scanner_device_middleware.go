@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// scannerDeviceContextKey is the gin context key holding the authenticated models.ScannerDevice
+const scannerDeviceContextKey = "scannerDevice"
+
+// maxDeviceClockSkew bounds how far X-Timestamp may drift from the server clock,
+// which also limits how long a captured signed request can be replayed
+const maxDeviceClockSkew = 5 * time.Minute
+
+// maxDeviceBodyBytes bounds the body of a device request: an offline batch of at most 500 items
+// (dtos.AbsensiScanBatchRequest) at 1 KiB per item leaves room for the longest client_scan_id and barcode
+const maxDeviceBodyBytes = 500 << 10
+
+// errDeviceBodyTooLarge is returned when a signed request body exceeds maxDeviceBodyBytes
+var errDeviceBodyTooLarge = errors.New("request body too large")
+
+// ScannerDeviceAuth authenticates registered attendance scanners. Every request must carry
+// X-Device-ID plus either X-Device-Key (api_key devices) or X-Timestamp and X-Signature
+// (hmac devices), and must come from the device's allowed IP ranges and active hours.
+func ScannerDeviceAuth(db *gorm.DB) gin.HandlerFunc {
+	return scannerDeviceAuth(db, true)
+}
+
+// ScannerDeviceBatchAuth is ScannerDeviceAuth for offline batch uploads, which may arrive
+// outside the device's active hours; the service checks each scan's own time instead.
+func ScannerDeviceBatchAuth(db *gorm.DB) gin.HandlerFunc {
+	return scannerDeviceAuth(db, false)
+}
+
+func scannerDeviceAuth(db *gorm.DB, checkActiveHours bool) gin.HandlerFunc {
+	repository := repositories.NewScannerDeviceRepository(db)
+
+	return func(c *gin.Context) {
+		if !limitDeviceBody(c) {
+			return
+		}
+
+		deviceID, err := strconv.ParseUint(c.GetHeader("X-Device-ID"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "missing or invalid device credentials",
+			})
+			c.Abort()
+			return
+		}
+
+		device, err := repository.GetByID(uint(deviceID))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "missing or invalid device credentials",
+			})
+			c.Abort()
+			return
+		}
+
+		valid, err := verifyDeviceCredentials(c, device)
+		if errors.Is(err, errDeviceBodyTooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error": errDeviceBodyTooLarge.Error(),
+			})
+			c.Abort()
+			return
+		}
+		if !valid {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "missing or invalid device credentials",
+			})
+			c.Abort()
+			return
+		}
+
+		if device.Status != "active" {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "device is inactive",
+			})
+			c.Abort()
+			return
+		}
+
+		if !deviceAllowsIP(device, c.ClientIP()) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "device is not allowed from this IP address",
+			})
+			c.Abort()
+			return
+		}
+
+		if checkActiveHours && !utils.WithinTimeWindow(device.JamAktifMulai, device.JamAktifSelesai, time.Now()) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "device is outside its active hours",
+			})
+			c.Abort()
+			return
+		}
+
+		if err := repository.TouchLastSeen(device.ID, c.ClientIP()); err != nil {
+			log.Printf("failed to update last seen of scanner device %d: %v", device.ID, err)
+		}
+
+		c.Set(scannerDeviceContextKey, *device)
+
+		c.Next()
+	}
+}
+
+// GetScannerDevice returns the scanner device stored by ScannerDeviceAuth
+func GetScannerDevice(c *gin.Context) (models.ScannerDevice, bool) {
+	value, exists := c.Get(scannerDeviceContextKey)
+	if !exists {
+		return models.ScannerDevice{}, false
+	}
+	device, ok := value.(models.ScannerDevice)
+	return device, ok
+}
+
+// limitDeviceBody rejects a request whose declared body exceeds maxDeviceBodyBytes with 413 and caps
+// the body of every other request, so neither the signature check nor the handler reads past the limit
+func limitDeviceBody(c *gin.Context) bool {
+	if c.Request.ContentLength > maxDeviceBodyBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error": errDeviceBodyTooLarge.Error(),
+		})
+		c.Abort()
+		return false
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxDeviceBodyBytes)
+	return true
+}
+
+// verifyDeviceCredentials checks the API key or the HMAC signature, depending on the device's auth mode.
+// The request body is restored after hashing so the handler can still bind it. A body over the limit
+// fails with errDeviceBodyTooLarge.
+func verifyDeviceCredentials(c *gin.Context, device *models.ScannerDevice) (bool, error) {
+	switch device.AuthMode {
+	case utils.DeviceAuthAPIKey:
+		key := c.GetHeader("X-Device-Key")
+		if key == "" || device.KeyHash == nil {
+			return false, nil
+		}
+		return subtle.ConstantTimeCompare([]byte(utils.HashDeviceKey(key)), []byte(*device.KeyHash)) == 1, nil
+
+	case utils.DeviceAuthHMAC:
+		timestamp := c.GetHeader("X-Timestamp")
+		signature := c.GetHeader("X-Signature")
+		if timestamp == "" || signature == "" || device.HMACSecret == nil {
+			return false, nil
+		}
+
+		unix, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return false, nil
+		}
+		skew := time.Since(time.Unix(unix, 0))
+		if skew > maxDeviceClockSkew || skew < -maxDeviceClockSkew {
+			return false, nil
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return false, errDeviceBodyTooLarge
+			}
+			return false, nil
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		return utils.VerifyDeviceSignature(*device.HMACSecret, signature, timestamp, c.Request.Method, c.Request.URL.Path, body), nil
+	}
+
+	return false, nil
+}
+
+// deviceAllowsIP reports whether ip is inside one of the device's allowed ranges; no ranges means any IP
+func deviceAllowsIP(device *models.ScannerDevice, ip string) bool {
+	var cidrs []string
+	if len(device.AllowedCIDRs) > 0 {
+		if err := json.Unmarshal(device.AllowedCIDRs, &cidrs); err != nil {
+			return false
+		}
+	}
+	if len(cidrs) == 0 {
+		return true
+	}
+
+	clientIP := net.ParseIP(ip)
+	if clientIP == nil {
+		return false
+	}
+
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err == nil && network.Contains(clientIP) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLimitDeviceBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		contentLength int64
+		wantNext      bool
+	}{
+		{"batch within the limit", maxDeviceBodyBytes, true},
+		{"unknown length", -1, true},
+		{"declared over the limit", maxDeviceBodyBytes + 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/absensi-scan/batch", strings.NewReader("{}"))
+			c.Request.ContentLength = tt.contentLength
+
+			if next := limitDeviceBody(c); next != tt.wantNext {
+				t.Fatalf("limitDeviceBody() = %v, want %v", next, tt.wantNext)
+			}
+			if !tt.wantNext && recorder.Code != http.StatusRequestEntityTooLarge {
+				t.Errorf("status = %d, want %d", recorder.Code, http.StatusRequestEntityTooLarge)
+			}
+		})
+	}
+}
+
+func TestVerifyDeviceCredentialsBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := "rahasia-perangkat"
+	device := &models.ScannerDevice{AuthMode: utils.DeviceAuthHMAC, HMACSecret: &secret}
+
+	tests := []struct {
+		name      string
+		size      int
+		wantValid bool
+		wantErr   error
+	}{
+		{"body at the limit", maxDeviceBodyBytes, true, nil},
+		{"chunked body over the limit", maxDeviceBodyBytes + 1, false, errDeviceBodyTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := bytes.Repeat([]byte("a"), tt.size)
+			timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+			path := "/api/v1/absensi-scan/batch"
+
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			// Without Content-Length the declared size check is skipped and only the reader limit applies
+			c.Request = httptest.NewRequest(http.MethodPost, path, io.NopCloser(bytes.NewReader(body)))
+			c.Request.ContentLength = -1
+			c.Request.Header.Set("X-Timestamp", timestamp)
+			c.Request.Header.Set("X-Signature", utils.SignDeviceRequest(secret, timestamp, http.MethodPost, path, body))
+
+			if !limitDeviceBody(c) {
+				t.Fatal("limitDeviceBody() rejected a request of unknown length")
+			}
+			valid, err := verifyDeviceCredentials(c, device)
+			if valid != tt.wantValid || !errors.Is(err, tt.wantErr) {
+				t.Errorf("verifyDeviceCredentials() = %v, %v, want %v, %v", valid, err, tt.wantValid, tt.wantErr)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/services"
 	"pintu-backend/src/utils"
 
@@ -56,9 +57,8 @@ func (c *AbsensiController) CreateAbsensiManual(ctx *gin.Context) {
 		return
 	}
 
-	// Get user ID from context (set by middleware)
-	userID, _ := ctx.Get("userID")
-	userIDUint := userID.(uint)
+	// Get principal from context (set by middleware)
+	actor, _ := middleware.GetPrincipal(ctx)
 
 	// Parse uploaded files
 	// Files are expected with field name pattern: file_surat_{index}
@@ -90,7 +90,7 @@ func (c *AbsensiController) CreateAbsensiManual(ctx *gin.Context) {
 	}
 
 	// Call service
-	result, err := c.service.CreateAbsensiManual(&req, filesMap, userIDUint)
+	result, err := c.service.CreateAbsensiManual(ctx.Request.Context(), &req, filesMap, actor, ctx.ClientIP())
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -129,9 +129,8 @@ func (c *AbsensiController) CreateAbsensiManualByID(ctx *gin.Context) {
 		return
 	}
 
-	// Get user ID from context (set by middleware)
-	userID, _ := ctx.Get("userID")
-	userIDUint := userID.(uint)
+	// Get principal from context (set by middleware)
+	actor, _ := middleware.GetPrincipal(ctx)
 
 	// Get file if uploaded
 	var file *multipart.FileHeader
@@ -141,7 +140,7 @@ func (c *AbsensiController) CreateAbsensiManualByID(ctx *gin.Context) {
 	}
 
 	// Call service
-	result, err := c.service.CreateAbsensiManualByID(&req, file, userIDUint)
+	result, err := c.service.CreateAbsensiManualByID(ctx.Request.Context(), &req, file, actor, ctx.ClientIP())
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -199,9 +198,8 @@ func (c *AbsensiController) UpdateRekapAbsensi(ctx *gin.Context) {
 		return
 	}
 
-	// Get user ID from context (set by middleware)
-	userID, _ := ctx.Get("userID")
-	userIDUint := userID.(uint)
+	// Get principal from context (set by middleware)
+	actor, _ := middleware.GetPrincipal(ctx)
 
 	// Get file if uploaded
 	var file *multipart.FileHeader
@@ -211,7 +209,7 @@ func (c *AbsensiController) UpdateRekapAbsensi(ctx *gin.Context) {
 	}
 
 	// Call service
-	result, err := c.service.UpdateRekapAbsensi(req.ID, &req, file, userIDUint)
+	result, err := c.service.UpdateRekapAbsensi(ctx.Request.Context(), req.ID, &req, file, actor, ctx.ClientIP())
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -220,6 +218,24 @@ func (c *AbsensiController) UpdateRekapAbsensi(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, result)
 }
 
+// GetRiwayatRekapAbsensi retrieves the change history of a student-day
+func (c *AbsensiController) GetRiwayatRekapAbsensi(ctx *gin.Context) {
+	var req dtos.RiwayatRekapAbsensiRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errors := utils.FormatValidationError(err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	result, err := c.service.GetRiwayatRekapAbsensi(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": result})
+}
+
 // GetDashboardSummary retrieves dashboard summary statistics
 func (c *AbsensiController) GetDashboardSummary(ctx *gin.Context) {
 	var req dtos.DashboardSummaryRequest
@@ -334,6 +350,56 @@ func (c *AbsensiController) GetDashboardSiswa(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"data": result})
 }
 
+// DownloadTemplateImport downloads the Excel template for rekap absensi import
+func (c *AbsensiController) DownloadTemplateImport(ctx *gin.Context) {
+	f, err := c.service.DownloadTemplateImport()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "gagal membuat template"})
+		return
+	}
+	defer f.Close()
+
+	ctx.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	ctx.Header("Content-Disposition", "attachment; filename=template_rekap_absensi.xlsx")
+
+	if err := f.Write(ctx.Writer); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "gagal mengirim file"})
+		return
+	}
+}
+
+// ImportExcel imports guru kelas rekap absensi of a rombel from an Excel file
+func (c *AbsensiController) ImportExcel(ctx *gin.Context) {
+	file, _, err := ctx.Request.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "file excel wajib diunggah"})
+		return
+	}
+	defer file.Close()
+
+	tahunPelajaranID, err := strconv.ParseUint(ctx.PostForm("tahun_pelajaran_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "tahun_pelajaran_id tidak valid"})
+		return
+	}
+	rombelID, err := strconv.ParseUint(ctx.PostForm("rombel_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "rombel_id tidak valid"})
+		return
+	}
+
+	// Get principal from context (set by middleware)
+	actor, _ := middleware.GetPrincipal(ctx)
+
+	result, err := c.service.ImportExcel(file, uint(tahunPelajaranID), uint(rombelID), actor, ctx.ClientIP())
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": result})
+}
+
 // ExportAbsensiExcel exports absensi data to Excel file
 func (c *AbsensiController) ExportAbsensiExcel(ctx *gin.Context) {
 	var req dtos.ExportAbsensiExcelRequest
@@ -401,12 +467,11 @@ func (c *AbsensiController) SynchronizeAbsensi(ctx *gin.Context) {
 		return
 	}
 
-	// Get user ID from context (set by middleware)
-	userID, _ := ctx.Get("userID")
-	userIDUint := userID.(uint)
+	// Get principal from context (set by middleware)
+	actor, _ := middleware.GetPrincipal(ctx)
 
 	// Call service
-	result, err := c.service.SynchronizeAbsensi(&req, userIDUint)
+	result, err := c.service.SynchronizeAbsensi(&req, actor, ctx.ClientIP())
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// absensiLiveHeartbeat keeps idle SSE connections open through proxies
+const absensiLiveHeartbeat = 20 * time.Second
+
+// AbsensiLiveController handles the real-time attendance feed
+type AbsensiLiveController struct {
+	service services.AbsensiLiveService
+}
+
+// NewAbsensiLiveController creates a new AbsensiLive controller
+func NewAbsensiLiveController(service services.AbsensiLiveService) *AbsensiLiveController {
+	return &AbsensiLiveController{service: service}
+}
+
+// GetRingkasan returns today's hadir and belum datang counters per rombel
+// @Summary Ringkasan Live Absensi
+// @Description Jumlah siswa hadir dan belum datang hari ini per rombel tahun pelajaran aktif
+// @Tags absensi-live
+// @Accept json
+// @Produce json
+// @Param body body dtos.AbsensiLiveRequest true "Request body"
+// @Success 200 {object} gin.H{data=dtos.AbsensiLiveRingkasanResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 500 {object} gin.H{error=string}
+// @Router /api/v1/absensi-siswa/get-ringkasan-live-absensi [post]
+func (c *AbsensiLiveController) GetRingkasan(ctx *gin.Context) {
+	var req dtos.AbsensiLiveRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := c.service.GetRingkasanHariIni(req.RombelID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// StreamFeed streams scans and counters as Server-Sent Events until the client disconnects
+// or the access token expires (the client reconnects with a refreshed token)
+// @Summary Live Feed Absensi (SSE)
+// @Description Stream text/event-stream: "ringkasan" saat terhubung dan setelah setiap scan, "scan" untuk setiap scan berhasil hari ini
+// @Tags absensi-live
+// @Accept json
+// @Produce text/event-stream
+// @Param body body dtos.AbsensiLiveRequest true "Request body"
+// @Router /api/v1/absensi-siswa/live-feed-absensi [post]
+func (c *AbsensiLiveController) StreamFeed(ctx *gin.Context) {
+	var req dtos.AbsensiLiveRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Subscribe before reading the counters so no scan falls in between
+	events, unsubscribe := c.service.Subscribe(req.RombelID)
+	defer unsubscribe()
+
+	ringkasan, err := c.service.GetRingkasanHariIni(req.RombelID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+
+	ctx.SSEvent("ringkasan", ringkasan)
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(absensiLiveHeartbeat)
+	defer heartbeat.Stop()
+
+	var tokenExpired <-chan time.Time
+	if expiresAt, ok := ctx.Get("tokenExpiresAt"); ok {
+		if t, ok := expiresAt.(time.Time); ok {
+			timer := time.NewTimer(time.Until(t))
+			defer timer.Stop()
+			tokenExpired = timer.C
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case <-tokenExpired:
+			ctx.SSEvent("expired", gin.H{"message": "token kedaluwarsa, sambungkan ulang"})
+			ctx.Writer.Flush()
+			return
+		case <-heartbeat.C:
+			if _, err := ctx.Writer.WriteString(": ping\n\n"); err != nil {
+				return
+			}
+			ctx.Writer.Flush()
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			ctx.SSEvent(event.Name, event.Data)
+			ctx.Writer.Flush()
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
+	"pintu-backend/src/modules/services"
+	"pintu-backend/src/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AbsensiPegawaiController handles HTTP requests for absensi of guru and tendik
+type AbsensiPegawaiController struct {
+	service services.AbsensiPegawaiService
+}
+
+// NewAbsensiPegawaiController creates a new Absensi Pegawai controller
+func NewAbsensiPegawaiController(service services.AbsensiPegawaiService) *AbsensiPegawaiController {
+	return &AbsensiPegawaiController{service: service}
+}
+
+// ScanAbsensiPegawai handles barcode scanning for staff attendance (public, scanner device auth)
+// @Summary Scan Absensi Pegawai
+// @Description Scan barcode untuk absensi guru dan tendik (datang/pulang) dari perangkat scanner terdaftar
+// @Tags absensi-pegawai
+// @Accept json
+// @Produce json
+// @Param body body dtos.AbsensiScanRequest true "Request body"
+// @Success 200 {object} dtos.AbsensiPegawaiScanResponse
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Failure 403 {object} gin.H{error=string}
+// @Router /api/v1/public/absensi-pegawai [post]
+func (c *AbsensiPegawaiController) ScanAbsensiPegawai(ctx *gin.Context) {
+	var req dtos.AbsensiScanRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get scanner device from context (set by scanner device middleware)
+	device, exists := middleware.GetScannerDevice(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "device not authenticated"})
+		return
+	}
+
+	response, err := c.service.ScanAbsensiPegawai(&req, device.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan sistem"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// UpsertKonfigurasi sets the staff scan time windows (auth required)
+// @Summary Setting Konfigurasi Absensi Pegawai
+// @Description Create atau update konfigurasi jam absensi pegawai dengan ID = 1, terpisah dari konfigurasi absensi siswa
+// @Tags absensi-pegawai
+// @Accept json
+// @Produce json
+// @Param body body dtos.KonfigurasiAbsensiPegawaiRequest true "Request body"
+// @Success 200 {object} gin.H{data=dtos.KonfigurasiAbsensiPegawaiResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Router /api/v1/absensi-pegawai/setting-konfigurasi-absensi-pegawai [post]
+func (c *AbsensiPegawaiController) UpsertKonfigurasi(ctx *gin.Context) {
+	var req dtos.KonfigurasiAbsensiPegawaiRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := c.service.UpsertKonfigurasi(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Konfigurasi absensi pegawai berhasil disimpan",
+		"data":    data,
+	})
+}
+
+// GetKonfigurasi gets the staff scan time windows (auth required)
+// @Summary Get Konfigurasi Absensi Pegawai
+// @Description Get konfigurasi absensi pegawai dengan ID = 1
+// @Tags absensi-pegawai
+// @Accept json
+// @Produce json
+// @Success 200 {object} gin.H{data=dtos.KonfigurasiAbsensiPegawaiResponse}
+// @Router /api/v1/absensi-pegawai/get-konfigurasi-absensi-pegawai [post]
+func (c *AbsensiPegawaiController) GetKonfigurasi(ctx *gin.Context) {
+	data, err := c.service.GetKonfigurasi()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// GetRekapBulanan gets the monthly rekap per pegawai (auth required)
+// @Summary Rekap Absensi Pegawai Bulanan
+// @Description Rekap hadir, terlambat dan tidak hadir per pegawai dalam satu bulan, dihitung dari hari efektif kalender akademik
+// @Tags absensi-pegawai
+// @Accept json
+// @Produce json
+// @Param body body dtos.AbsensiPegawaiRekapRequest true "Request body"
+// @Success 200 {object} gin.H{data=dtos.AbsensiPegawaiRekapResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Router /api/v1/absensi-pegawai/get-rekap-absensi-pegawai [post]
+func (c *AbsensiPegawaiController) GetRekapBulanan(ctx *gin.Context) {
+	var req dtos.AbsensiPegawaiRekapRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errors := utils.FormatValidationError(err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	result, err := c.service.GetRekapBulanan(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// ExportExcel exports the monthly absensi pegawai to Excel file
+func (c *AbsensiPegawaiController) ExportExcel(ctx *gin.Context) {
+	var req dtos.AbsensiPegawaiRekapRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errors := utils.FormatValidationError(err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	// Call service
+	file, err := c.service.ExportExcel(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Generate filename
+	filename := fmt.Sprintf("Daftar_Hadir_Pegawai_%d.xlsx", time.Now().Unix())
+
+	// Set headers for file download
+	ctx.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	ctx.Header("Content-Transfer-Encoding", "binary")
+
+	// Write file to response
+	if err := file.Write(ctx.Writer); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "gagal menulis file excel"})
+		return
+	}
+}
+
+// ExportPDF exports the monthly absensi pegawai to PDF file
+func (c *AbsensiPegawaiController) ExportPDF(ctx *gin.Context) {
+	var req dtos.AbsensiPegawaiRekapRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errors := utils.FormatValidationError(err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	// Call service
+	pdfBytes, err := c.service.ExportPDF(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Generate filename
+	filename := fmt.Sprintf("Daftar_Hadir_Pegawai_%d.pdf", time.Now().Unix())
+
+	// Set headers for file download
+	ctx.Header("Content-Type", "application/pdf")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	ctx.Header("Content-Transfer-Encoding", "binary")
+
+	// Write PDF to response
+	ctx.Data(http.StatusOK, "application/pdf", pdfBytes)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/services"
 
 	"github.com/gin-gonic/gin"
@@ -19,15 +20,17 @@ func NewAbsensiScanController(service services.AbsensiScanService) *AbsensiScanC
 	return &AbsensiScanController{service: service}
 }
 
-// ScanAbsensi handles barcode scanning for attendance (public, no auth)
+// ScanAbsensi handles barcode scanning for attendance (public, scanner device auth)
 // @Summary Scan Absensi Siswa
-// @Description Scan barcode untuk absensi siswa (datang/pulang)
+// @Description Scan barcode untuk absensi siswa (datang/pulang) dari perangkat scanner terdaftar
 // @Tags absensi-siswa
 // @Accept json
 // @Produce json
 // @Param body body dtos.AbsensiScanRequest true "Request body"
 // @Success 200 {object} dtos.AbsensiScanResponse
 // @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Failure 403 {object} gin.H{error=string}
 // @Router /api/v1/public/absensi-siswa [post]
 func (c *AbsensiScanController) ScanAbsensi(ctx *gin.Context) {
 	var req dtos.AbsensiScanRequest
@@ -36,7 +39,14 @@ func (c *AbsensiScanController) ScanAbsensi(ctx *gin.Context) {
 		return
 	}
 
-	response, err := c.service.ScanAbsensi(&req)
+	// Get scanner device from context (set by scanner device middleware)
+	device, exists := middleware.GetScannerDevice(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "device not authenticated"})
+		return
+	}
+
+	response, err := c.service.ScanAbsensi(&req, device.ID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan sistem"})
 		return
@@ -44,3 +54,38 @@ func (c *AbsensiScanController) ScanAbsensi(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, response)
 }
+
+// ScanAbsensiBatch handles scans uploaded by a device after it was offline (public, scanner device auth)
+// @Summary Upload Scan Absensi Offline
+// @Description Unggah daftar scan yang tertunda saat perangkat offline; setiap scan divalidasi dengan waktu scan aslinya dan client_scan_id yang sama tidak diproses ulang
+// @Tags absensi-siswa
+// @Accept json
+// @Produce json
+// @Param body body dtos.AbsensiScanBatchRequest true "Request body"
+// @Success 200 {object} gin.H{data=dtos.AbsensiScanBatchResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Failure 403 {object} gin.H{error=string}
+// @Router /api/v1/public/absensi-siswa-batch [post]
+func (c *AbsensiScanController) ScanAbsensiBatch(ctx *gin.Context) {
+	var req dtos.AbsensiScanBatchRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get scanner device from context (set by scanner device middleware)
+	device, exists := middleware.GetScannerDevice(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "device not authenticated"})
+		return
+	}
+
+	response, err := c.service.ScanAbsensiBatch(&req, device)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": response})
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/repositories"
 	"pintu-backend/src/modules/services"
 
@@ -95,15 +96,15 @@ func (c *ActivityGalleryController) Create(ctx *gin.Context) {
 		Status:          status,
 	}
 
-	// Get user ID from context (set by auth middleware)
-	userID, exists := ctx.Get("userID")
+	// Get principal from context (set by auth middleware)
+	actor, exists := middleware.GetPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
 	// Call service
-	data, err := c.service.Create(fotos, fotoThumbnails, req, userID.(uint))
+	data, err := c.service.Create(ctx.Request.Context(), fotos, fotoThumbnails, req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -328,14 +329,14 @@ func (c *ActivityGalleryController) Update(ctx *gin.Context) {
 		FotoThumbnailUpdates: fotoThumbnailUpdates,
 	}
 
-	// Get user ID from context
-	userID, exists := ctx.Get("userID")
+	// Get principal from context
+	actor, exists := middleware.GetPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
-	data, err := c.service.Update(uint(id), fotos, newFotoThumbnails, req, userID.(uint))
+	data, err := c.service.Update(ctx.Request.Context(), uint(id), fotos, newFotoThumbnails, req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/repositories"
 	"pintu-backend/src/modules/services"
 
@@ -38,14 +39,14 @@ func (c *ApplicationController) Create(ctx *gin.Context) {
 		return
 	}
 
-	// Get user ID from context (set by auth middleware)
-	userID, exists := ctx.Get("userID")
+	// Get principal from context (set by auth middleware)
+	actor, exists := middleware.GetPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
-	data, err := c.service.Create(&req, userID.(uint))
+	data, err := c.service.Create(&req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -156,14 +157,14 @@ func (c *ApplicationController) Update(ctx *gin.Context) {
 		return
 	}
 
-	// Get user ID from context
-	userID, exists := ctx.Get("userID")
+	// Get principal from context
+	actor, exists := middleware.GetPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
-	data, err := c.service.Update(&req, userID.(uint))
+	data, err := c.service.Update(&req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/repositories"
 	"pintu-backend/src/modules/services"
 
@@ -106,15 +107,15 @@ func (c *ArticleController) Create(ctx *gin.Context) {
 		Status:          status,
 	}
 
-	// Get user ID from context (set by auth middleware)
-	userID, exists := ctx.Get("userID")
+	// Get principal from context (set by auth middleware)
+	actor, exists := middleware.GetPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
 	// Call service
-	data, err := c.service.Create(gambar, files, req, userID.(uint))
+	data, err := c.service.Create(ctx.Request.Context(), gambar, files, req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -305,14 +306,14 @@ func (c *ArticleController) Update(ctx *gin.Context) {
 		FilesToDelete:   filesToDelete,
 	}
 
-	// Get user ID from context
-	userID, exists := ctx.Get("userID")
+	// Get principal from context
+	actor, exists := middleware.GetPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
-	data, err := c.service.Update(uint(id), gambar, files, req, userID.(uint))
+	data, err := c.service.Update(ctx.Request.Context(), uint(id), gambar, files, req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -0,0 +1,373 @@
+package controllers
+
+import (
+	"net/http"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/modules/services"
+	"pintu-backend/src/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JadwalAbsensiController handles HTTP requests for attendance schedule profiles and their assignments
+type JadwalAbsensiController struct {
+	service services.JadwalAbsensiService
+}
+
+// NewJadwalAbsensiController creates a new JadwalAbsensi controller
+func NewJadwalAbsensiController(service services.JadwalAbsensiService) *JadwalAbsensiController {
+	return &JadwalAbsensiController{service: service}
+}
+
+// CreateProfil creates a new schedule profile
+// @Summary Create Profil Jadwal Absensi
+// @Description Buat profil jam absensi (mis. Reguler, Ramadan) dengan override per hari
+// @Tags jadwal_absensi
+// @Accept json
+// @Produce json
+// @Param body body dtos.ProfilJadwalAbsensiCreateRequest true "Request body"
+// @Success 201 {object} gin.H{data=dtos.ProfilJadwalAbsensiResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Router /api/v1/absensi-siswa/create-profil-jadwal-absensi [post]
+func (c *JadwalAbsensiController) CreateProfil(ctx *gin.Context) {
+	var req dtos.ProfilJadwalAbsensiCreateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errors := utils.FormatValidationError(err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	// Get principal from context (set by auth middleware)
+	actor, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	data, err := c.service.CreateProfil(&req, actor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": data})
+}
+
+// GetAllProfil retrieves schedule profiles
+// @Summary Get all Profil Jadwal Absensi
+// @Description Retrieve profil jadwal absensi with filters and pagination
+// @Tags jadwal_absensi
+// @Accept json
+// @Produce json
+// @Param body body dtos.ProfilJadwalAbsensiGetAllRequest true "Request body"
+// @Success 200 {object} gin.H{data=[]dtos.ProfilJadwalAbsensiResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 500 {object} gin.H{error=string}
+// @Router /api/v1/absensi-siswa/get-profil-jadwal-absensi [post]
+func (c *JadwalAbsensiController) GetAllProfil(ctx *gin.Context) {
+	var req dtos.ProfilJadwalAbsensiGetAllRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Default values
+	limit := 10
+	page := 1
+	if req.Pagination.Limit > 0 && req.Pagination.Limit <= 100 {
+		limit = req.Pagination.Limit
+	}
+	if req.Pagination.Page > 0 {
+		page = req.Pagination.Page
+	}
+	offset := (page - 1) * limit
+
+	data, err := c.service.GetAllProfil(repositories.GetProfilJadwalAbsensiParams{
+		Nama:   req.Search.Nama,
+		Status: req.Search.Status,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": data.Data,
+		"pagination": gin.H{
+			"limit":       data.Pagination.Limit,
+			"offset":      data.Pagination.Offset,
+			"page":        data.Pagination.Page,
+			"total":       data.Pagination.Total,
+			"total_pages": data.Pagination.TotalPages,
+		},
+	})
+}
+
+// GetProfilByID retrieves a schedule profile by ID
+// @Summary Get Profil Jadwal Absensi by ID
+// @Tags jadwal_absensi
+// @Accept json
+// @Produce json
+// @Param body body dtos.IDRequest true "Request body with ID"
+// @Success 200 {object} gin.H{data=dtos.ProfilJadwalAbsensiResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 404 {object} gin.H{error=string}
+// @Router /api/v1/absensi-siswa/get-profil-jadwal-absensi-by-id [post]
+func (c *JadwalAbsensiController) GetProfilByID(ctx *gin.Context) {
+	var req dtos.IDRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	data, err := c.service.GetProfilByID(req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Profil jadwal absensi not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// UpdateProfil updates a schedule profile
+// @Summary Update Profil Jadwal Absensi
+// @Description Update profil jadwal absensi; hari mengganti seluruh override per hari bila dikirim
+// @Tags jadwal_absensi
+// @Accept json
+// @Produce json
+// @Param body body dtos.ProfilJadwalAbsensiUpdateRequest true "Request body"
+// @Success 200 {object} gin.H{data=dtos.ProfilJadwalAbsensiResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Router /api/v1/absensi-siswa/update-profil-jadwal-absensi [post]
+func (c *JadwalAbsensiController) UpdateProfil(ctx *gin.Context) {
+	var req dtos.ProfilJadwalAbsensiUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errors := utils.FormatValidationError(err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	// Get principal from context
+	actor, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	data, err := c.service.UpdateProfil(&req, actor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// DeleteProfil deletes a schedule profile that has no assignments
+// @Summary Delete Profil Jadwal Absensi
+// @Tags jadwal_absensi
+// @Accept json
+// @Produce json
+// @Param body body dtos.IDRequest true "Request body with ID"
+// @Success 200 {object} gin.H{message=string}
+// @Failure 400 {object} gin.H{error=string}
+// @Router /api/v1/absensi-siswa/delete-profil-jadwal-absensi [post]
+func (c *JadwalAbsensiController) DeleteProfil(ctx *gin.Context) {
+	var req dtos.IDRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	if err := c.service.DeleteProfil(req.ID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Profil jadwal absensi deleted successfully",
+	})
+}
+
+// CreatePenugasan assigns a schedule profile
+// @Summary Create Penugasan Jadwal Absensi
+// @Description Tugaskan profil jadwal ke semua siswa, satu kelas atau satu rombel; isi tanggal_mulai dan tanggal_selesai untuk jadwal khusus
+// @Tags jadwal_absensi
+// @Accept json
+// @Produce json
+// @Param body body dtos.PenugasanJadwalAbsensiRequest true "Request body"
+// @Success 201 {object} gin.H{data=dtos.PenugasanJadwalAbsensiResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Router /api/v1/absensi-siswa/create-penugasan-jadwal-absensi [post]
+func (c *JadwalAbsensiController) CreatePenugasan(ctx *gin.Context) {
+	var req dtos.PenugasanJadwalAbsensiRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errors := utils.FormatValidationError(err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	// Get principal from context (set by auth middleware)
+	actor, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	data, err := c.service.CreatePenugasan(&req, actor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": data})
+}
+
+// GetAllPenugasan retrieves schedule assignments
+// @Summary Get all Penugasan Jadwal Absensi
+// @Description Retrieve penugasan jadwal absensi with filters and pagination, regular assignments first
+// @Tags jadwal_absensi
+// @Accept json
+// @Produce json
+// @Param body body dtos.PenugasanJadwalAbsensiGetAllRequest true "Request body"
+// @Success 200 {object} gin.H{data=[]dtos.PenugasanJadwalAbsensiResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 500 {object} gin.H{error=string}
+// @Router /api/v1/absensi-siswa/get-penugasan-jadwal-absensi [post]
+func (c *JadwalAbsensiController) GetAllPenugasan(ctx *gin.Context) {
+	var req dtos.PenugasanJadwalAbsensiGetAllRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Default values
+	limit := 10
+	page := 1
+	if req.Pagination.Limit > 0 && req.Pagination.Limit <= 100 {
+		limit = req.Pagination.Limit
+	}
+	if req.Pagination.Page > 0 {
+		page = req.Pagination.Page
+	}
+	offset := (page - 1) * limit
+
+	data, err := c.service.GetAllPenugasan(repositories.GetPenugasanJadwalAbsensiParams{
+		ProfilJadwalAbsensiID: req.Search.ProfilJadwalAbsensiID,
+		KelasID:               req.Search.KelasID,
+		RombelID:              req.Search.RombelID,
+		Limit:                 limit,
+		Offset:                offset,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": data.Data,
+		"pagination": gin.H{
+			"limit":       data.Pagination.Limit,
+			"offset":      data.Pagination.Offset,
+			"page":        data.Pagination.Page,
+			"total":       data.Pagination.Total,
+			"total_pages": data.Pagination.TotalPages,
+		},
+	})
+}
+
+// UpdatePenugasan updates a schedule assignment
+// @Summary Update Penugasan Jadwal Absensi
+// @Tags jadwal_absensi
+// @Accept json
+// @Produce json
+// @Param body body dtos.PenugasanJadwalAbsensiUpdateRequest true "Request body"
+// @Success 200 {object} gin.H{data=dtos.PenugasanJadwalAbsensiResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Router /api/v1/absensi-siswa/update-penugasan-jadwal-absensi [post]
+func (c *JadwalAbsensiController) UpdatePenugasan(ctx *gin.Context) {
+	var req dtos.PenugasanJadwalAbsensiUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errors := utils.FormatValidationError(err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	// Get principal from context
+	actor, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	data, err := c.service.UpdatePenugasan(&req, actor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// DeletePenugasan deletes a schedule assignment
+// @Summary Delete Penugasan Jadwal Absensi
+// @Tags jadwal_absensi
+// @Accept json
+// @Produce json
+// @Param body body dtos.IDRequest true "Request body with ID"
+// @Success 200 {object} gin.H{message=string}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 404 {object} gin.H{error=string}
+// @Router /api/v1/absensi-siswa/delete-penugasan-jadwal-absensi [post]
+func (c *JadwalAbsensiController) DeletePenugasan(ctx *gin.Context) {
+	var req dtos.IDRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	if err := c.service.DeletePenugasan(req.ID); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Penugasan jadwal absensi not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Penugasan jadwal absensi deleted successfully",
+	})
+}
+
+// PreviewJadwal shows the effective schedule of a student on a date
+// @Summary Preview Jadwal Absensi Siswa
+// @Description Tampilkan jam absensi yang berlaku untuk seorang siswa pada suatu tanggal beserta sumbernya (penugasan atau konfigurasi absensi)
+// @Tags jadwal_absensi
+// @Accept json
+// @Produce json
+// @Param body body dtos.JadwalAbsensiPreviewRequest true "Request body"
+// @Success 200 {object} gin.H{data=dtos.JadwalAbsensiPreviewResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Router /api/v1/absensi-siswa/preview-jadwal-absensi [post]
+func (c *JadwalAbsensiController) PreviewJadwal(ctx *gin.Context) {
+	var req dtos.JadwalAbsensiPreviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errors := utils.FormatValidationError(err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	data, err := c.service.PreviewJadwal(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
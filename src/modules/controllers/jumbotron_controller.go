@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/services"
 
 	"github.com/gin-gonic/gin"
@@ -54,15 +55,15 @@ func (c *JumbotronController) Create(ctx *gin.Context) {
 		Status: status,
 	}
 
-	// Get user ID from context (set by auth middleware)
-	userID, exists := ctx.Get("userID")
+	// Get principal from context (set by auth middleware)
+	actor, exists := middleware.GetPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
 	// Call service
-	data, err := c.service.Create(file, req, userID.(uint))
+	data, err := c.service.Create(ctx.Request.Context(), file, req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -183,14 +184,14 @@ func (c *JumbotronController) Update(ctx *gin.Context) {
 	// Get status (optional)
 	status := ctx.PostForm("status")
 
-	// Get user ID from context
-	userID, exists := ctx.Get("userID")
+	// Get principal from context
+	actor, exists := middleware.GetPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
-	data, err := c.service.UpdateWithFile(id, file, status, userID.(uint))
+	data, err := c.service.UpdateWithFile(ctx.Request.Context(), id, file, status, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -0,0 +1,355 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/modules/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// KalenderAkademikController handles HTTP requests for KalenderAkademik
+type KalenderAkademikController struct {
+	service services.KalenderAkademikService
+}
+
+// NewKalenderAkademikController creates a new KalenderAkademik controller
+func NewKalenderAkademikController(service services.KalenderAkademikService) *KalenderAkademikController {
+	return &KalenderAkademikController{service: service}
+}
+
+// Create creates a new kalender akademik entry
+// @Summary Create new KalenderAkademik
+// @Description Create a holiday, school event or semester period
+// @Tags kalender_akademik
+// @Accept json
+// @Produce json
+// @Param body body dtos.KalenderAkademikCreateRequest true "Request body"
+// @Success 201 {object} gin.H{data=dtos.KalenderAkademikResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Router /api/v1/kalender-akademik/create-kalender-akademik [post]
+func (c *KalenderAkademikController) Create(ctx *gin.Context) {
+	var req dtos.KalenderAkademikCreateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get principal from context (set by auth middleware)
+	actor, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	data, err := c.service.Create(&req, actor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": data})
+}
+
+// GetAll retrieves all kalender akademik entries
+// @Summary Get all KalenderAkademik
+// @Description Retrieve kalender akademik entries with filters and pagination, ordered by tanggal_mulai
+// @Tags kalender_akademik
+// @Accept json
+// @Produce json
+// @Param body body dtos.KalenderAkademikGetAllRequest true "Request body"
+// @Success 200 {object} gin.H{data=[]dtos.KalenderAkademikResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Failure 500 {object} gin.H{error=string}
+// @Router /api/v1/kalender-akademik/get-kalender-akademik [post]
+func (c *KalenderAkademikController) GetAll(ctx *gin.Context) {
+	var req dtos.KalenderAkademikGetAllRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Default values
+	limit := 10
+	page := 1
+	if req.Pagination.Limit > 0 && req.Pagination.Limit <= 100 {
+		limit = req.Pagination.Limit
+	}
+	if req.Pagination.Page > 0 {
+		page = req.Pagination.Page
+	}
+	offset := (page - 1) * limit
+
+	filter := repositories.GetKalenderAkademikFilter{
+		TahunPelajaranID: req.Search.TahunPelajaranID,
+		Jenis:            req.Search.Jenis,
+		Nama:             req.Search.Nama,
+	}
+	if req.Search.TanggalMulai != "" {
+		tanggal, err := time.Parse("2006-01-02", req.Search.TanggalMulai)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "format tanggal_mulai tidak valid, gunakan YYYY-MM-DD"})
+			return
+		}
+		filter.TanggalMulai = &tanggal
+	}
+	if req.Search.TanggalSelesai != "" {
+		tanggal, err := time.Parse("2006-01-02", req.Search.TanggalSelesai)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "format tanggal_selesai tidak valid, gunakan YYYY-MM-DD"})
+			return
+		}
+		filter.TanggalSelesai = &tanggal
+	}
+
+	data, err := c.service.GetAllWithFilter(repositories.GetKalenderAkademikParams{
+		Filter: filter,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": data.Data,
+		"pagination": gin.H{
+			"limit":       data.Pagination.Limit,
+			"offset":      data.Pagination.Offset,
+			"page":        data.Pagination.Page,
+			"total":       data.Pagination.Total,
+			"total_pages": data.Pagination.TotalPages,
+		},
+	})
+}
+
+// GetByID retrieves KalenderAkademik by ID
+// @Summary Get KalenderAkademik by ID
+// @Description Retrieve kalender akademik entry by ID
+// @Tags kalender_akademik
+// @Accept json
+// @Produce json
+// @Param body body dtos.IDRequest true "Request body with ID"
+// @Success 200 {object} gin.H{data=dtos.KalenderAkademikResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Failure 404 {object} gin.H{error=string}
+// @Router /api/v1/kalender-akademik/get-kalender-akademik-by-id [post]
+func (c *KalenderAkademikController) GetByID(ctx *gin.Context) {
+	var req dtos.IDRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	data, err := c.service.GetByID(req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Kalender akademik not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// Update updates KalenderAkademik
+// @Summary Update KalenderAkademik
+// @Description Update kalender akademik entry
+// @Tags kalender_akademik
+// @Accept json
+// @Produce json
+// @Param body body dtos.KalenderAkademikUpdateRequest true "Request body"
+// @Success 200 {object} gin.H{data=dtos.KalenderAkademikResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Router /api/v1/kalender-akademik/update-kalender-akademik [post]
+func (c *KalenderAkademikController) Update(ctx *gin.Context) {
+	var req dtos.KalenderAkademikUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get principal from context
+	actor, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	data, err := c.service.Update(&req, actor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// Delete deletes KalenderAkademik by ID
+// @Summary Delete KalenderAkademik
+// @Description Delete kalender akademik entry by ID
+// @Tags kalender_akademik
+// @Accept json
+// @Produce json
+// @Param body body dtos.IDRequest true "Request body with ID"
+// @Success 200 {object} gin.H{message=string}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Failure 404 {object} gin.H{error=string}
+// @Router /api/v1/kalender-akademik/delete-kalender-akademik [post]
+func (c *KalenderAkademikController) Delete(ctx *gin.Context) {
+	var req dtos.IDRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	if err := c.service.Delete(req.ID); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Kalender akademik not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Kalender akademik deleted successfully",
+	})
+}
+
+// DownloadTemplate downloads the Excel template for kalender akademik import
+// @Summary Download KalenderAkademik import template
+// @Tags kalender_akademik
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Router /api/v1/kalender-akademik/download-template-kalender-akademik [post]
+func (c *KalenderAkademikController) DownloadTemplate(ctx *gin.Context) {
+	f, err := c.service.DownloadTemplate()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "gagal membuat template"})
+		return
+	}
+	defer f.Close()
+
+	ctx.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	ctx.Header("Content-Disposition", "attachment; filename=template_kalender_akademik.xlsx")
+
+	if err := f.Write(ctx.Writer); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "gagal mengirim file"})
+		return
+	}
+}
+
+// ImportExcel imports kalender akademik entries from an Excel file
+// @Summary Import KalenderAkademik from Excel
+// @Description Multipart form with file and optional tahun_pelajaran_id
+// @Tags kalender_akademik
+// @Accept multipart/form-data
+// @Produce json
+// @Success 200 {object} gin.H{data=dtos.KalenderAkademikImportResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Router /api/v1/kalender-akademik/import-excel-kalender-akademik [post]
+func (c *KalenderAkademikController) ImportExcel(ctx *gin.Context) {
+	file, _, err := ctx.Request.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "file excel wajib diunggah"})
+		return
+	}
+	defer file.Close()
+
+	tahunPelajaranID, err := formUintPtr(ctx, "tahun_pelajaran_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "tahun_pelajaran_id tidak valid"})
+		return
+	}
+
+	// Get principal from context (set by middleware)
+	actor, _ := middleware.GetPrincipal(ctx)
+
+	result, err := c.service.ImportExcel(file, tahunPelajaranID, actor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// ImportICal imports kalender akademik entries from an iCalendar (.ics) file
+// @Summary Import KalenderAkademik from iCal
+// @Description Multipart form with file, optional tahun_pelajaran_id and optional jenis (default libur_nasional)
+// @Tags kalender_akademik
+// @Accept multipart/form-data
+// @Produce json
+// @Success 200 {object} gin.H{data=dtos.KalenderAkademikImportResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Router /api/v1/kalender-akademik/import-ical-kalender-akademik [post]
+func (c *KalenderAkademikController) ImportICal(ctx *gin.Context) {
+	file, _, err := ctx.Request.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "file iCal wajib diunggah"})
+		return
+	}
+	defer file.Close()
+
+	tahunPelajaranID, err := formUintPtr(ctx, "tahun_pelajaran_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "tahun_pelajaran_id tidak valid"})
+		return
+	}
+
+	// Get principal from context (set by middleware)
+	actor, _ := middleware.GetPrincipal(ctx)
+
+	result, err := c.service.ImportICal(file, tahunPelajaranID, ctx.PostForm("jenis"), actor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// GetHariEfektif lists the school days in a date range
+// @Summary Get hari efektif
+// @Description List every date in the range with whether it is a school day, based on hari_sekolah and kalender akademik
+// @Tags kalender_akademik
+// @Accept json
+// @Produce json
+// @Param body body dtos.HariEfektifRequest true "Request body"
+// @Success 200 {object} gin.H{data=dtos.HariEfektifResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Router /api/v1/kalender-akademik/get-hari-efektif [post]
+func (c *KalenderAkademikController) GetHariEfektif(ctx *gin.Context) {
+	var req dtos.HariEfektifRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := c.service.GetHariEfektif(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// formUintPtr reads an optional unsigned integer form field
+func formUintPtr(ctx *gin.Context, key string) (*uint, error) {
+	value := ctx.PostForm(key)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	result := uint(parsed)
+	return &result, nil
+}
@@ -2,10 +2,13 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"mime/multipart"
 	"net/http"
+	"strconv"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/repositories"
 	"pintu-backend/src/modules/services"
 	"pintu-backend/src/utils"
@@ -54,9 +57,8 @@ func (c *KelulusanController) CreateKelulusan(ctx *gin.Context) {
 		return
 	}
 
-	// Get user ID from context (set by middleware)
-	userID, _ := ctx.Get("userID")
-	userIDUint := userID.(uint)
+	// Get principal from context (set by middleware)
+	actor, _ := middleware.GetPrincipal(ctx)
 
 	// Get SKL file if uploaded
 	var file *multipart.FileHeader
@@ -66,7 +68,7 @@ func (c *KelulusanController) CreateKelulusan(ctx *gin.Context) {
 	}
 
 	// Call service
-	result, err := c.service.CreateKelulusan(&req, file, userIDUint)
+	result, err := c.service.CreateKelulusan(ctx.Request.Context(), &req, file, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -112,11 +114,10 @@ func (c *KelulusanController) ImportExcel(ctx *gin.Context) {
 	}
 	defer file.Close()
 
-	// Get user ID from context (set by middleware)
-	userID, _ := ctx.Get("userID")
-	userIDUint := userID.(uint)
+	// Get principal from context (set by middleware)
+	actor, _ := middleware.GetPrincipal(ctx)
 
-	result, err := c.service.ImportExcel(file, userIDUint)
+	result, err := c.service.ImportExcel(file, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -228,9 +229,8 @@ func (c *KelulusanController) Update(ctx *gin.Context) {
 		return
 	}
 
-	// Get user ID from context (set by middleware)
-	userID, _ := ctx.Get("userID")
-	userIDUint := userID.(uint)
+	// Get principal from context (set by middleware)
+	actor, _ := middleware.GetPrincipal(ctx)
 
 	// Get SKL file if uploaded
 	var file *multipart.FileHeader
@@ -240,7 +240,7 @@ func (c *KelulusanController) Update(ctx *gin.Context) {
 	}
 
 	// Call service
-	result, err := c.service.Update(req.ID, &req, file, userIDUint)
+	result, err := c.service.Update(ctx.Request.Context(), req.ID, &req, file, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -286,8 +286,12 @@ func (c *KelulusanController) CekNilaiKelulusan(ctx *gin.Context) {
 		return
 	}
 
-	result, err := c.service.CekNilaiKelulusan(req.NISN, req.TanggalLahir)
+	result, err := c.service.CekNilaiKelulusan(req.NISN, req.TanggalLahir, ctx.ClientIP())
 	if err != nil {
+		if errors.Is(err, services.ErrTooManyAttempts) {
+			ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
@@ -305,8 +309,12 @@ func (c *KelulusanController) CekKelulusan(ctx *gin.Context) {
 		return
 	}
 
-	result, err := c.service.CekKelulusan(req.NISN, req.TanggalLahir)
+	result, err := c.service.CekKelulusan(req.NISN, req.TanggalLahir, ctx.ClientIP())
 	if err != nil {
+		if errors.Is(err, services.ErrTooManyAttempts) {
+			ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
@@ -324,8 +332,12 @@ func (c *KelulusanController) DownloadLaporanNilaiKelulusan(ctx *gin.Context) {
 		return
 	}
 
-	pdfBytes, err := c.service.DownloadLaporanNilaiKelulusan(req.NISN, req.TanggalLahir)
+	pdfBytes, err := c.service.DownloadLaporanNilaiKelulusan(req.NISN, req.TanggalLahir, ctx.ClientIP())
 	if err != nil {
+		if errors.Is(err, services.ErrTooManyAttempts) {
+			ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
@@ -333,7 +345,7 @@ func (c *KelulusanController) DownloadLaporanNilaiKelulusan(ctx *gin.Context) {
 	// Set headers for PDF download
 	ctx.Header("Content-Type", "application/pdf")
 	ctx.Header("Content-Disposition", "attachment; filename=laporan_nilai_kelulusan.pdf")
-	ctx.Header("Content-Length", string(len(pdfBytes)))
+	ctx.Header("Content-Length", strconv.Itoa(len(pdfBytes)))
 
 	// Send PDF
 	ctx.Data(http.StatusOK, "application/pdf", pdfBytes)
@@ -0,0 +1,218 @@
+package controllers
+
+import (
+	"net/http"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
+	"pintu-backend/src/modules/services"
+	"pintu-backend/src/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// KenaikanKelasController handles HTTP requests for the academic year rollover (kenaikan kelas) wizard
+type KenaikanKelasController struct {
+	service services.KenaikanKelasService
+}
+
+// NewKenaikanKelasController creates a new KenaikanKelas controller
+func NewKenaikanKelasController(service services.KenaikanKelasService) *KenaikanKelasController {
+	return &KenaikanKelasController{service: service}
+}
+
+// GetUsulan proposes the next rombel of every rombel
+// @Summary Get Usulan Kenaikan Kelas
+// @Description Usulan pemetaan rombel ke rombel berikutnya (kelas N ke N+1), rombel kelas tertinggi lulus
+// @Tags kenaikan_kelas
+// @Accept json
+// @Produce json
+// @Param body body dtos.KenaikanKelasUsulanRequest true "Request body"
+// @Success 200 {object} gin.H{data=dtos.KenaikanKelasUsulanResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Router /api/v1/kenaikan-kelas/get-usulan-kenaikan-kelas [post]
+func (c *KenaikanKelasController) GetUsulan(ctx *gin.Context) {
+	var req dtos.KenaikanKelasUsulanRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errors := utils.FormatValidationError(err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	data, err := c.service.GetUsulan(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// Preview returns the result a rollover would have
+// @Summary Preview Kenaikan Kelas
+// @Description Pratinjau hasil kenaikan kelas per siswa dan per rombel tujuan, beserta error yang menghalangi proses
+// @Tags kenaikan_kelas
+// @Accept json
+// @Produce json
+// @Param body body dtos.KenaikanKelasRequest true "Request body"
+// @Success 200 {object} gin.H{data=dtos.KenaikanKelasPreviewResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Router /api/v1/kenaikan-kelas/preview-kenaikan-kelas [post]
+func (c *KenaikanKelasController) Preview(ctx *gin.Context) {
+	var req dtos.KenaikanKelasRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errors := utils.FormatValidationError(err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	data, err := c.service.Preview(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// Proses processes a rollover
+// @Summary Proses Kenaikan Kelas
+// @Description Buat pemetaan rombel tahun pelajaran tujuan, tandai siswa kelas tertinggi lulus, dan aktifkan tahun pelajaran tujuan
+// @Tags kenaikan_kelas
+// @Accept json
+// @Produce json
+// @Param body body dtos.KenaikanKelasRequest true "Request body"
+// @Success 201 {object} gin.H{data=dtos.KenaikanKelasResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Router /api/v1/kenaikan-kelas/proses-kenaikan-kelas [post]
+func (c *KenaikanKelasController) Proses(ctx *gin.Context) {
+	var req dtos.KenaikanKelasRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errors := utils.FormatValidationError(err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	// Get principal from context (set by auth middleware)
+	actor, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	data, err := c.service.Proses(&req, actor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": data})
+}
+
+// Batalkan undoes a rollover
+// @Summary Batalkan Kenaikan Kelas
+// @Description Batalkan kenaikan kelas terakhir pada hari yang sama selama rombel tujuan belum memiliki absensi
+// @Tags kenaikan_kelas
+// @Accept json
+// @Produce json
+// @Param body body dtos.IDRequest true "Request body with ID"
+// @Success 200 {object} gin.H{data=dtos.KenaikanKelasResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Router /api/v1/kenaikan-kelas/batalkan-kenaikan-kelas [post]
+func (c *KenaikanKelasController) Batalkan(ctx *gin.Context) {
+	var req dtos.IDRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	// Get principal from context (set by auth middleware)
+	actor, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	data, err := c.service.Batalkan(req.ID, actor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// GetAll retrieves the rollover history
+// @Summary Get Riwayat Kenaikan Kelas
+// @Description Retrieve riwayat kenaikan kelas with pagination
+// @Tags kenaikan_kelas
+// @Accept json
+// @Produce json
+// @Param body body dtos.KenaikanKelasGetAllRequest true "Request body"
+// @Success 200 {object} gin.H{data=[]dtos.KenaikanKelasResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 500 {object} gin.H{error=string}
+// @Router /api/v1/kenaikan-kelas/get-riwayat-kenaikan-kelas [post]
+func (c *KenaikanKelasController) GetAll(ctx *gin.Context) {
+	var req dtos.KenaikanKelasGetAllRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Default values
+	limit := 10
+	page := 1
+	if req.Pagination.Limit > 0 && req.Pagination.Limit <= 100 {
+		limit = req.Pagination.Limit
+	}
+	if req.Pagination.Page > 0 {
+		page = req.Pagination.Page
+	}
+	offset := (page - 1) * limit
+
+	data, err := c.service.GetAll(limit, offset)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": data.Data,
+		"pagination": gin.H{
+			"limit":       data.Pagination.Limit,
+			"offset":      data.Pagination.Offset,
+			"page":        data.Pagination.Page,
+			"total":       data.Pagination.Total,
+			"total_pages": data.Pagination.TotalPages,
+		},
+	})
+}
+
+// GetByID retrieves a rollover by ID
+// @Summary Get Kenaikan Kelas by ID
+// @Tags kenaikan_kelas
+// @Accept json
+// @Produce json
+// @Param body body dtos.IDRequest true "Request body with ID"
+// @Success 200 {object} gin.H{data=dtos.KenaikanKelasResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 404 {object} gin.H{error=string}
+// @Router /api/v1/kenaikan-kelas/get-kenaikan-kelas-by-id [post]
+func (c *KenaikanKelasController) GetByID(ctx *gin.Context) {
+	var req dtos.IDRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	data, err := c.service.GetByID(req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
@@ -2,11 +2,13 @@ package controllers
 
 import (
 	"encoding/json"
+	"fmt"
 	"mime/multipart"
 	"net/http"
 	"strconv"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/repositories"
 	"pintu-backend/src/modules/services"
 	"pintu-backend/src/utils"
@@ -35,12 +37,11 @@ func (c *KepegawaianController) Create(ctx *gin.Context) {
 		return
 	}
 
-	// Get user ID from context (set by middleware)
-	userID, _ := ctx.Get("userID")
-	userIDUint := userID.(uint)
+	// Get principal from context (set by middleware)
+	actor, _ := middleware.GetPrincipal(ctx)
 
 	// Call service
-	result, err := c.service.Create(&req, userIDUint)
+	result, err := c.service.Create(&req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -157,8 +158,9 @@ func (c *KepegawaianController) GetAllWithoutPagination(ctx *gin.Context) {
 
 // Update updates a Kepegawaian
 func (c *KepegawaianController) Update(ctx *gin.Context) {
-	// Parse multipart form (max 100MB)
-	if err := ctx.Request.ParseMultipartForm(100 * 1024 * 1024); err != nil {
+	// Parse multipart form, keeping at most 10MB of files in memory (the rest goes to temporary files that
+	// uploads stream from)
+	if err := ctx.Request.ParseMultipartForm(10 * 1024 * 1024); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "failed to parse form"})
 		return
 	}
@@ -262,9 +264,8 @@ func (c *KepegawaianController) Update(ctx *gin.Context) {
 		}
 	}
 
-	// Get user ID from context
-	userID, _ := ctx.Get("userID")
-	userIDUint := userID.(uint)
+	// Get principal from context
+	actor, _ := middleware.GetPrincipal(ctx)
 
 	// Get files to delete (optional, as JSON string array)
 	var filesToDelete []string
@@ -326,7 +327,7 @@ func (c *KepegawaianController) Update(ctx *gin.Context) {
 	}
 
 	// Call service
-	result, err := c.service.Update(uint(id), foto, docMap, req, userIDUint)
+	result, err := c.service.Update(ctx.Request.Context(), uint(id), foto, docMap, req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -398,3 +399,53 @@ func (c *KepegawaianController) GetPublicTendikData(ctx *gin.Context) {
 
 	ctx.JSON(200, result)
 }
+
+// GenerateBarcodeAllKepegawaian generates barcodes for all active kepegawaian
+func (c *KepegawaianController) GenerateBarcodeAllKepegawaian(ctx *gin.Context) {
+	result, err := c.service.GenerateBarcodeAllKepegawaian()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// GenerateBarcodeKepegawaianByID generates or regenerates barcode for a specific kepegawaian by ID
+func (c *KepegawaianController) GenerateBarcodeKepegawaianByID(ctx *gin.Context) {
+	var req dtos.GenerateBarcodeByIDRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errors := utils.FormatValidationError(err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	result, err := c.service.GenerateBarcodeKepegawaianByID(req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// DownloadBarcodeKepegawaian downloads the barcode of a kepegawaian as a QR code PNG
+func (c *KepegawaianController) DownloadBarcodeKepegawaian(ctx *gin.Context) {
+	var req dtos.GenerateBarcodeByIDRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errors := utils.FormatValidationError(err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	png, filename, err := c.service.DownloadBarcodeKepegawaian(req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	ctx.Data(http.StatusOK, "image/png", png)
+}
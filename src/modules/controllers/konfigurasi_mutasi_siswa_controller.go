@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/services"
 
 	"github.com/gin-gonic/gin"
@@ -53,14 +54,14 @@ func (c *KonfigurasiMutasiSiswaController) UpsertSetting(ctx *gin.Context) {
 	// Get template SPTJM file if provided
 	file, _ := ctx.FormFile("template_sptjm")
 
-	// Get user ID from context
-	userID, exists := ctx.Get("userID")
+	// Get principal from context
+	actor, exists := middleware.GetPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
-	data, err := c.service.UpsertSetting(&req, file, userID.(uint))
+	data, err := c.service.UpsertSetting(ctx.Request.Context(), &req, file, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/services"
 
 	"github.com/gin-gonic/gin"
@@ -50,15 +51,15 @@ func (c *KutipanKepsekController) Create(ctx *gin.Context) {
 		KutipanKepsek: kutipanKepsek,
 	}
 
-	// Get user ID from context
-	userID, exists := ctx.Get("userID")
+	// Get principal from context
+	actor, exists := middleware.GetPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
 	// Call service
-	data, err := c.service.Create(file, req, userID.(uint))
+	data, err := c.service.Create(ctx.Request.Context(), file, req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -157,14 +158,14 @@ func (c *KutipanKepsekController) Update(ctx *gin.Context) {
 		req.KutipanKepsek = &kutipanKepsek
 	}
 
-	// Get user ID from context
-	userID, exists := ctx.Get("userID")
+	// Get principal from context
+	actor, exists := middleware.GetPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
-	data, err := c.service.UpdateWithFile(id, file, req, userID.(uint))
+	data, err := c.service.UpdateWithFile(ctx.Request.Context(), id, file, req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -1,9 +1,12 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/services"
 
 	"github.com/gin-gonic/gin"
@@ -27,9 +30,9 @@ func (c *LoginController) Login(ctx *gin.Context) {
 		return
 	}
 
-	response, err := c.service.Login(&req)
+	response, err := c.service.Login(&req, ctx.Request.UserAgent(), ctx.ClientIP())
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		ctx.JSON(loginErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -39,20 +42,104 @@ func (c *LoginController) Login(ctx *gin.Context) {
 	})
 }
 
-// Logout handles user logout
-func (c *LoginController) Logout(ctx *gin.Context) {
-	// In JWT stateless approach, logout is client-side
-	// Just return success message, client should delete token from environment
+// LoginSiswa handles peserta didik login
+func (c *LoginController) LoginSiswa(ctx *gin.Context) {
+	var req dtos.LoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := c.service.LoginSiswa(&req, ctx.Request.UserAgent(), ctx.ClientIP())
+	if err != nil {
+		ctx.JSON(loginErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response,
+	})
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token pair
+func (c *LoginController) Refresh(ctx *gin.Context) {
+	var req dtos.RefreshTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := c.service.Refresh(&req, ctx.Request.UserAgent(), ctx.ClientIP())
+	if err != nil {
+		ctx.JSON(loginErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response,
+	})
+}
+
+// SwitchRole re-issues the session for another role held by the caller
+func (c *LoginController) SwitchRole(ctx *gin.Context) {
+	var req dtos.SwitchRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
+	principal, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	jti := ctx.GetString("jti")
+	expiresAt, _ := ctx.Get("tokenExpiresAt")
+	response, err := c.service.SwitchRole(principal, jti, expiresAt.(time.Time), &req, ctx.Request.UserAgent(), ctx.ClientIP())
+	if err != nil {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response,
+	})
+}
+
+// Logout revokes the current access token and its refresh token
+func (c *LoginController) Logout(ctx *gin.Context) {
 	userID, exists := ctx.Get("userID")
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
 	}
 
+	jti := ctx.GetString("jti")
+	expiresAt, _ := ctx.Get("tokenExpiresAt")
+	if err := c.service.Logout(jti, expiresAt.(time.Time)); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"status":  "success",
-		"message": "Logout successful, please delete your token",
+		"message": "Logout successful",
 		"user_id": userID,
 	})
 }
+
+// loginErrorStatus maps login failures to their HTTP status
+func loginErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrTooManyAttempts):
+		return http.StatusTooManyRequests
+	case errors.Is(err, services.ErrAccountLocked):
+		return http.StatusLocked
+	default:
+		return http.StatusUnauthorized
+	}
+}
@@ -27,8 +27,8 @@ func NewMutasiSiswaController(service services.MutasiSiswaService) *MutasiSiswaC
 // @Tags mutasi-siswa
 // @Accept multipart/form-data
 // @Produce json
-// @Param tahun_pelajaran_id formData int true "Tahun Pelajaran ID"
-// @Param semester formData int true "Semester"
+// @Param tahun_pelajaran_id formData int false "Tahun Pelajaran ID (default: tahun pelajaran of today)"
+// @Param semester formData int false "Semester (default: semester of today)"
 // @Param nama_lengkap formData string true "Nama Lengkap"
 // @Param nama_panggilan formData string false "Nama Panggilan"
 // @Param nisn formData string false "NISN"
@@ -93,7 +93,7 @@ func (c *MutasiSiswaController) CreatePublic(ctx *gin.Context) {
 		files["sptjm"] = file
 	}
 
-	data, err := c.service.CreatePublic(&req, files)
+	data, err := c.service.CreatePublic(ctx.Request.Context(), &req, files)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -250,7 +250,7 @@ func (c *MutasiSiswaController) Update(ctx *gin.Context) {
 		files["sptjm"] = file
 	}
 
-	data, err := c.service.Update(&req, files)
+	data, err := c.service.Update(ctx.Request.Context(), &req, files)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
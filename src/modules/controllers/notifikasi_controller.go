@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"net/http"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/modules/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotifikasiController handles HTTP requests for notifikasi templates and the outbox
+type NotifikasiController struct {
+	service services.NotifikasiService
+}
+
+// NewNotifikasiController creates a new Notifikasi controller
+func NewNotifikasiController(service services.NotifikasiService) *NotifikasiController {
+	return &NotifikasiController{service: service}
+}
+
+// GetTemplates retrieves all notifikasi templates
+// @Summary Get all NotifikasiTemplate
+// @Description Retrieve the message templates per event (terlambat, alpa) and channel (email, whatsapp)
+// @Tags notifikasi
+// @Accept json
+// @Produce json
+// @Success 200 {object} gin.H{data=[]dtos.NotifikasiTemplateResponse}
+// @Failure 401 {object} gin.H{error=string}
+// @Failure 500 {object} gin.H{error=string}
+// @Router /api/v1/notifikasi/get-notifikasi-templates [post]
+func (c *NotifikasiController) GetTemplates(ctx *gin.Context) {
+	data, err := c.service.GetTemplates()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// UpdateTemplate updates a notifikasi template
+// @Summary Update NotifikasiTemplate
+// @Description Update subject, body (Go text/template) or status of a template; templates that do not render are rejected
+// @Tags notifikasi
+// @Accept json
+// @Produce json
+// @Param body body dtos.NotifikasiTemplateUpdateRequest true "Request body"
+// @Success 200 {object} gin.H{data=dtos.NotifikasiTemplateResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Router /api/v1/notifikasi/update-notifikasi-template [post]
+func (c *NotifikasiController) UpdateTemplate(ctx *gin.Context) {
+	var req dtos.NotifikasiTemplateUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get principal from context
+	actor, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	data, err := c.service.UpdateTemplate(&req, actor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// GetOutbox retrieves outbox messages
+// @Summary Get NotifikasiOutbox
+// @Description Retrieve queued, sent and failed notifications with filters and pagination
+// @Tags notifikasi
+// @Accept json
+// @Produce json
+// @Param body body dtos.NotifikasiOutboxGetAllRequest true "Request body"
+// @Success 200 {object} gin.H{data=[]dtos.NotifikasiOutboxResponse}
+// @Failure 401 {object} gin.H{error=string}
+// @Failure 500 {object} gin.H{error=string}
+// @Router /api/v1/notifikasi/get-notifikasi-outbox [post]
+func (c *NotifikasiController) GetOutbox(ctx *gin.Context) {
+	var req dtos.NotifikasiOutboxGetAllRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Default values
+	limit := 10
+	page := 1
+	if req.Pagination.Limit > 0 && req.Pagination.Limit <= 100 {
+		limit = req.Pagination.Limit
+	}
+	if req.Pagination.Page > 0 {
+		page = req.Pagination.Page
+	}
+	offset := (page - 1) * limit
+
+	data, err := c.service.GetOutboxWithFilter(repositories.GetNotifikasiOutboxParams{
+		Filter: repositories.GetNotifikasiOutboxFilter{
+			Status:         req.Search.Status,
+			Event:          req.Search.Event,
+			Channel:        req.Search.Channel,
+			PesertaDidikID: req.Search.PesertaDidikID,
+		},
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": data.Data,
+		"pagination": gin.H{
+			"limit":       data.Pagination.Limit,
+			"offset":      data.Pagination.Offset,
+			"page":        data.Pagination.Page,
+			"total":       data.Pagination.Total,
+			"total_pages": data.Pagination.TotalPages,
+		},
+	})
+}
+
+// RetryOutbox re-queues a failed message
+// @Summary Retry NotifikasiOutbox
+// @Description Put a failed notification back in the queue with a fresh set of attempts
+// @Tags notifikasi
+// @Accept json
+// @Produce json
+// @Param body body dtos.IDRequest true "Request body with ID"
+// @Success 200 {object} gin.H{data=dtos.NotifikasiOutboxResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Router /api/v1/notifikasi/retry-notifikasi-outbox [post]
+func (c *NotifikasiController) RetryOutbox(ctx *gin.Context) {
+	var req dtos.IDRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	data, err := c.service.RetryOutbox(req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// ProcessOutbox sends the due messages now instead of waiting for the background job
+// @Summary Process NotifikasiOutbox
+// @Description Send up to 50 due notifications immediately
+// @Tags notifikasi
+// @Accept json
+// @Produce json
+// @Success 200 {object} gin.H{data=dtos.NotifikasiOutboxProcessResult}
+// @Failure 401 {object} gin.H{error=string}
+// @Failure 500 {object} gin.H{error=string}
+// @Router /api/v1/notifikasi/process-notifikasi-outbox [post]
+func (c *NotifikasiController) ProcessOutbox(ctx *gin.Context) {
+	data, err := c.service.ProcessOutbox(50)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/services"
 
 	"github.com/gin-gonic/gin"
@@ -64,7 +65,7 @@ func (c *PengaduanController) CreatePublic(ctx *gin.Context) {
 	}
 
 	// Call service
-	data, err := c.service.CreatePublic(files, &req)
+	data, err := c.service.CreatePublic(ctx.Request.Context(), files, &req)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -227,15 +228,15 @@ func (c *PengaduanController) SendReply(ctx *gin.Context) {
 		DeskripsiJawaban: deskripsiJawaban,
 	}
 
-	// Get user ID from context
-	userID, exists := ctx.Get("userID")
+	// Get principal from context
+	actor, exists := middleware.GetPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
 	// Call service
-	data, err := c.service.SendReply(files, req, userID.(uint))
+	data, err := c.service.SendReply(ctx.Request.Context(), files, req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -314,15 +315,15 @@ func (c *PengaduanController) SaveTindakLanjut(ctx *gin.Context) {
 		FilesToDelete: filesToDelete,
 	}
 
-	// Get user ID from context (set by auth middleware)
-	userID, exists := ctx.Get("userID")
+	// Get principal from context (set by auth middleware)
+	actor, exists := middleware.GetPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
 	// Call service
-	result, err := c.service.SaveTindakLanjut(files, &req, userID.(uint))
+	result, err := c.service.SaveTindakLanjut(ctx.Request.Context(), files, &req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -382,14 +383,14 @@ func (c *PengaduanController) DeletePengaduan(ctx *gin.Context) {
 		return
 	}
 
-	// Get user ID from context
-	userID, exists := ctx.Get("userID")
+	// Get principal from context
+	actor, exists := middleware.GetPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
-	err := c.service.DeletePengaduan(req.ID, userID.(uint))
+	err := c.service.DeletePengaduan(req.ID, actor)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
@@ -0,0 +1,311 @@
+package controllers
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/modules/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PengajuanIzinController handles HTTP requests for izin/sakit requests
+type PengajuanIzinController struct {
+	service services.PengajuanIzinService
+}
+
+// NewPengajuanIzinController creates a new PengajuanIzin controller
+func NewPengajuanIzinController(service services.PengajuanIzinService) *PengajuanIzinController {
+	return &PengajuanIzinController{service: service}
+}
+
+// CreatePublic creates a new PengajuanIzin from the public form (no auth required)
+// @Summary Create new PengajuanIzin (Public)
+// @Description Parent submits an izin/sakit request for a date range. The student is identified by NIS and tanggal lahir; a surat is required for sakit.
+// @Tags pengajuan-izin
+// @Accept multipart/form-data
+// @Produce json
+// @Param nis formData string true "NIS siswa"
+// @Param tanggal_lahir formData string true "Tanggal lahir siswa (YYYY-MM-DD)"
+// @Param jenis formData string true "Jenis pengajuan (izin/sakit)"
+// @Param tanggal_mulai formData string true "Tanggal mulai (YYYY-MM-DD)"
+// @Param tanggal_selesai formData string true "Tanggal selesai (YYYY-MM-DD)"
+// @Param alasan formData string true "Alasan"
+// @Param nama_pengaju formData string true "Nama orang tua/wali"
+// @Param telepon_pengaju formData string false "Nomor telepon orang tua/wali"
+// @Param file_surat formData file false "Surat keterangan (wajib untuk sakit) - max 10MB"
+// @Success 201 {object} gin.H{data=dtos.PengajuanIzinResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 429 {object} gin.H{error=string}
+// @Router /api/v1/public/create-pengajuan-izin [post]
+func (c *PengajuanIzinController) CreatePublic(ctx *gin.Context) {
+	var req dtos.PengajuanIzinPublicCreateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := c.service.CreatePublic(ctx.Request.Context(), formFileSurat(ctx), &req, ctx.ClientIP())
+	if err != nil {
+		if errors.Is(err, services.ErrTooManyAttempts) {
+			ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": data})
+}
+
+// TrackPengajuanIzin tracks a PengajuanIzin by ID Tiket (no auth required)
+// @Summary Track PengajuanIzin by ID Tiket (Public)
+// @Description Track the review status of an izin/sakit request using ID Tiket
+// @Tags pengajuan-izin
+// @Accept json
+// @Produce json
+// @Param body body dtos.PengajuanIzinTrackRequest true "Request body with ID Tiket"
+// @Success 200 {object} gin.H{data=dtos.PengajuanIzinTrackResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 404 {object} gin.H{error=string}
+// @Router /api/v1/public/track-pengajuan-izin [post]
+func (c *PengajuanIzinController) TrackPengajuanIzin(ctx *gin.Context) {
+	var req dtos.PengajuanIzinTrackRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data, err := c.service.TrackByIDTiket(req.IDTiket)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// CreateBySiswa creates a new PengajuanIzin for the logged-in student
+// @Summary Create PengajuanIzin (Siswa)
+// @Description Submit an izin/sakit request from the siswa portal; a surat is required for sakit
+// @Tags pengajuan-izin
+// @Accept multipart/form-data
+// @Produce json
+// @Param jenis formData string true "Jenis pengajuan (izin/sakit)"
+// @Param tanggal_mulai formData string true "Tanggal mulai (YYYY-MM-DD)"
+// @Param tanggal_selesai formData string true "Tanggal selesai (YYYY-MM-DD)"
+// @Param alasan formData string true "Alasan"
+// @Param nama_pengaju formData string false "Nama pengaju, default nama siswa"
+// @Param telepon_pengaju formData string false "Nomor telepon pengaju"
+// @Param file_surat formData file false "Surat keterangan (wajib untuk sakit) - max 10MB"
+// @Success 201 {object} gin.H{data=dtos.PengajuanIzinResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Router /api/v1/siswa/create-pengajuan-izin [post]
+func (c *PengajuanIzinController) CreateBySiswa(ctx *gin.Context) {
+	var req dtos.PengajuanIzinCreateRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	siswa, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	data, err := c.service.CreateBySiswa(ctx.Request.Context(), siswa.ID, formFileSurat(ctx), &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": data})
+}
+
+// GetBySiswa retrieves the requests of the logged-in student
+// @Summary Get PengajuanIzin (Siswa)
+// @Description Retrieve the 50 most recent izin/sakit requests of the logged-in student
+// @Tags pengajuan-izin
+// @Produce json
+// @Success 200 {object} gin.H{data=[]dtos.PengajuanIzinResponse}
+// @Failure 401 {object} gin.H{error=string}
+// @Router /api/v1/siswa/get-pengajuan-izin [post]
+func (c *PengajuanIzinController) GetBySiswa(ctx *gin.Context) {
+	siswa, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	data, err := c.service.GetBySiswa(siswa.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// GetAll retrieves the approval queue with filters and pagination
+// @Summary Get all PengajuanIzin
+// @Description Retrieve izin/sakit requests, pending first. A wali kelas only sees their own rombel.
+// @Tags pengajuan-izin
+// @Accept json
+// @Produce json
+// @Param body body dtos.PengajuanIzinGetAllRequest true "Request body with filters and pagination"
+// @Success 200 {object} dtos.PengajuanIzinListWithPaginationResponse
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Router /api/v1/pengajuan-izin/get-pengajuan-izin [post]
+func (c *PengajuanIzinController) GetAll(ctx *gin.Context) {
+	var req dtos.PengajuanIzinGetAllRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	data, err := c.service.GetAllWithFilter(&req, actor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": data.Data,
+		"pagination": gin.H{
+			"limit":       data.Pagination.Limit,
+			"offset":      data.Pagination.Offset,
+			"page":        data.Pagination.Page,
+			"total":       data.Pagination.Total,
+			"total_pages": data.Pagination.TotalPages,
+		},
+	})
+}
+
+// GetByID retrieves PengajuanIzin by ID
+// @Summary Get PengajuanIzin by ID
+// @Description Retrieve the details of an izin/sakit request
+// @Tags pengajuan-izin
+// @Accept json
+// @Produce json
+// @Param body body dtos.IDRequest true "Request body with ID"
+// @Success 200 {object} gin.H{data=dtos.PengajuanIzinResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 404 {object} gin.H{error=string}
+// @Router /api/v1/pengajuan-izin/get-pengajuan-izin-by-id [post]
+func (c *PengajuanIzinController) GetByID(ctx *gin.Context) {
+	var req dtos.IDRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	actor, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	data, err := c.service.GetByID(req.ID, actor)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// Approve approves a pending PengajuanIzin
+// @Summary Approve PengajuanIzin
+// @Description Approve a pending request; izin/sakit is recorded in rekapitulasi absensi for every effective school day in the range. Days already recorded by a guru or scan are kept and listed in tanggal_dilewati.
+// @Tags pengajuan-izin
+// @Accept json
+// @Produce json
+// @Param body body dtos.PengajuanIzinReviewRequest true "Request body"
+// @Success 200 {object} gin.H{data=dtos.PengajuanIzinApproveResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 409 {object} gin.H{error=string}
+// @Router /api/v1/pengajuan-izin/approve-pengajuan-izin [post]
+func (c *PengajuanIzinController) Approve(ctx *gin.Context) {
+	var req dtos.PengajuanIzinReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	data, err := c.service.Approve(&req, actor, ctx.ClientIP())
+	if err != nil {
+		ctx.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// Reject rejects a pending PengajuanIzin
+// @Summary Reject PengajuanIzin
+// @Description Reject a pending request; catatan is required and shown on the tracking page
+// @Tags pengajuan-izin
+// @Accept json
+// @Produce json
+// @Param body body dtos.PengajuanIzinReviewRequest true "Request body"
+// @Success 200 {object} gin.H{data=dtos.PengajuanIzinResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 409 {object} gin.H{error=string}
+// @Router /api/v1/pengajuan-izin/reject-pengajuan-izin [post]
+func (c *PengajuanIzinController) Reject(ctx *gin.Context) {
+	var req dtos.PengajuanIzinReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	data, err := c.service.Reject(&req, actor)
+	if err != nil {
+		ctx.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// formFileSurat returns the optional file_surat upload
+func formFileSurat(ctx *gin.Context) *multipart.FileHeader {
+	fileHeader, err := ctx.FormFile("file_surat")
+	if err != nil {
+		return nil
+	}
+	return fileHeader
+}
+
+// reviewErrorStatus maps a review error to its HTTP status
+func reviewErrorStatus(err error) int {
+	if errors.Is(err, repositories.ErrPengajuanIzinSudahDireview) {
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
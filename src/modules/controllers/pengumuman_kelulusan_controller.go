@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/services"
 	"pintu-backend/src/utils"
 
@@ -53,9 +54,8 @@ func (c *PengumumanKelulusanController) ConfigurePengumuman(ctx *gin.Context) {
 		return
 	}
 
-	// Get user ID from context (set by middleware)
-	userID, _ := ctx.Get("userID")
-	userIDUint := userID.(uint)
+	// Get principal from context (set by middleware)
+	actor, _ := middleware.GetPrincipal(ctx)
 
 	// Get foto_kepsek file if uploaded
 	var fotoKepsek *multipart.FileHeader
@@ -72,7 +72,7 @@ func (c *PengumumanKelulusanController) ConfigurePengumuman(ctx *gin.Context) {
 	}
 
 	// Call service
-	result, err := c.service.ConfigurePengumuman(&req, fotoKepsek, ttdKepsek, userIDUint)
+	result, err := c.service.ConfigurePengumuman(ctx.Request.Context(), &req, fotoKepsek, ttdKepsek, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
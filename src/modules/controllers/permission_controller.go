@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
 	"pintu-backend/src/modules/services"
@@ -37,17 +38,17 @@ func (c *PermissionController) Create(ctx *gin.Context) {
 		return
 	}
 
-	// Get user ID from JWT token
-	userID, _ := ctx.Get("userID")
-	createdByID := userID.(uint)
+	// Get principal from JWT token
+	actor, _ := middleware.GetPrincipal(ctx)
 
 	permission := &models.Permission{
-		Name:        req.Name,
-		Description: req.Description,
-		GroupName:   req.GroupName,
-		SystemID:    &req.SystemID,
-		Status:      req.Status,
-		CreatedByID: &createdByID,
+		Name:          req.Name,
+		Description:   req.Description,
+		GroupName:     req.GroupName,
+		SystemID:      &req.SystemID,
+		Status:        req.Status,
+		CreatedByID:   &actor.ID,
+		CreatedByType: actor.TypePtr(),
 	}
 
 	if err := c.service.Create(permission); err != nil {
@@ -62,29 +63,33 @@ func (c *PermissionController) Create(ctx *gin.Context) {
 	var systemResponse *dtos.SystemResponse
 	if permissionData.System != nil {
 		systemResponse = &dtos.SystemResponse{
-			ID:          permissionData.System.ID,
-			Nama:        permissionData.System.Nama,
-			Description: permissionData.System.Description,
-			Status:      permissionData.System.Status,
-			CreatedAt:   permissionData.System.CreatedAt,
-			UpdatedAt:   permissionData.System.UpdatedAt,
-			CreatedByID: permissionData.System.CreatedByID,
-			UpdatedByID: permissionData.System.UpdatedByID,
+			ID:            permissionData.System.ID,
+			Nama:          permissionData.System.Nama,
+			Description:   permissionData.System.Description,
+			Status:        permissionData.System.Status,
+			CreatedAt:     permissionData.System.CreatedAt,
+			UpdatedAt:     permissionData.System.UpdatedAt,
+			CreatedByID:   permissionData.System.CreatedByID,
+			CreatedByType: permissionData.System.CreatedByType,
+			UpdatedByID:   permissionData.System.UpdatedByID,
+			UpdatedByType: permissionData.System.UpdatedByType,
 		}
 	}
 
 	response := dtos.PermissionResponse{
-		ID:          permissionData.ID,
-		Name:        permissionData.Name,
-		Description: permissionData.Description,
-		GroupName:   permissionData.GroupName,
-		SystemID:    permissionData.SystemID,
-		System:      systemResponse,
-		Status:      permissionData.Status,
-		CreatedAt:   permissionData.CreatedAt,
-		UpdatedAt:   permissionData.UpdatedAt,
-		CreatedByID: permissionData.CreatedByID,
-		UpdatedByID: permissionData.UpdatedByID,
+		ID:            permissionData.ID,
+		Name:          permissionData.Name,
+		Description:   permissionData.Description,
+		GroupName:     permissionData.GroupName,
+		SystemID:      permissionData.SystemID,
+		System:        systemResponse,
+		Status:        permissionData.Status,
+		CreatedAt:     permissionData.CreatedAt,
+		UpdatedAt:     permissionData.UpdatedAt,
+		CreatedByID:   permissionData.CreatedByID,
+		CreatedByType: permissionData.CreatedByType,
+		UpdatedByID:   permissionData.UpdatedByID,
+		UpdatedByType: permissionData.UpdatedByType,
 	}
 
 	ctx.JSON(http.StatusCreated, gin.H{"data": response})
@@ -110,29 +115,33 @@ func (c *PermissionController) GetByID(ctx *gin.Context) {
 	var systemResponse *dtos.SystemResponse
 	if data.System != nil {
 		systemResponse = &dtos.SystemResponse{
-			ID:          data.System.ID,
-			Nama:        data.System.Nama,
-			Description: data.System.Description,
-			Status:      data.System.Status,
-			CreatedAt:   data.System.CreatedAt,
-			UpdatedAt:   data.System.UpdatedAt,
-			CreatedByID: data.System.CreatedByID,
-			UpdatedByID: data.System.UpdatedByID,
+			ID:            data.System.ID,
+			Nama:          data.System.Nama,
+			Description:   data.System.Description,
+			Status:        data.System.Status,
+			CreatedAt:     data.System.CreatedAt,
+			UpdatedAt:     data.System.UpdatedAt,
+			CreatedByID:   data.System.CreatedByID,
+			CreatedByType: data.System.CreatedByType,
+			UpdatedByID:   data.System.UpdatedByID,
+			UpdatedByType: data.System.UpdatedByType,
 		}
 	}
 
 	response := dtos.PermissionResponse{
-		ID:          data.ID,
-		Name:        data.Name,
-		Description: data.Description,
-		GroupName:   data.GroupName,
-		SystemID:    data.SystemID,
-		System:      systemResponse,
-		Status:      data.Status,
-		CreatedAt:   data.CreatedAt,
-		UpdatedAt:   data.UpdatedAt,
-		CreatedByID: data.CreatedByID,
-		UpdatedByID: data.UpdatedByID,
+		ID:            data.ID,
+		Name:          data.Name,
+		Description:   data.Description,
+		GroupName:     data.GroupName,
+		SystemID:      data.SystemID,
+		System:        systemResponse,
+		Status:        data.Status,
+		CreatedAt:     data.CreatedAt,
+		UpdatedAt:     data.UpdatedAt,
+		CreatedByID:   data.CreatedByID,
+		CreatedByType: data.CreatedByType,
+		UpdatedByID:   data.UpdatedByID,
+		UpdatedByType: data.UpdatedByType,
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"data": response})
@@ -180,29 +189,33 @@ func (c *PermissionController) GetAll(ctx *gin.Context) {
 		var systemResponse *dtos.SystemResponse
 		if permission.System != nil {
 			systemResponse = &dtos.SystemResponse{
-				ID:          permission.System.ID,
-				Nama:        permission.System.Nama,
-				Description: permission.System.Description,
-				Status:      permission.System.Status,
-				CreatedAt:   permission.System.CreatedAt,
-				UpdatedAt:   permission.System.UpdatedAt,
-				CreatedByID: permission.System.CreatedByID,
-				UpdatedByID: permission.System.UpdatedByID,
+				ID:            permission.System.ID,
+				Nama:          permission.System.Nama,
+				Description:   permission.System.Description,
+				Status:        permission.System.Status,
+				CreatedAt:     permission.System.CreatedAt,
+				UpdatedAt:     permission.System.UpdatedAt,
+				CreatedByID:   permission.System.CreatedByID,
+				CreatedByType: permission.System.CreatedByType,
+				UpdatedByID:   permission.System.UpdatedByID,
+				UpdatedByType: permission.System.UpdatedByType,
 			}
 		}
 
 		responseData = append(responseData, dtos.PermissionResponse{
-			ID:          permission.ID,
-			Name:        permission.Name,
-			Description: permission.Description,
-			GroupName:   permission.GroupName,
-			SystemID:    permission.SystemID,
-			System:      systemResponse,
-			Status:      permission.Status,
-			CreatedAt:   permission.CreatedAt,
-			UpdatedAt:   permission.UpdatedAt,
-			CreatedByID: permission.CreatedByID,
-			UpdatedByID: permission.UpdatedByID,
+			ID:            permission.ID,
+			Name:          permission.Name,
+			Description:   permission.Description,
+			GroupName:     permission.GroupName,
+			SystemID:      permission.SystemID,
+			System:        systemResponse,
+			Status:        permission.Status,
+			CreatedAt:     permission.CreatedAt,
+			UpdatedAt:     permission.UpdatedAt,
+			CreatedByID:   permission.CreatedByID,
+			CreatedByType: permission.CreatedByType,
+			UpdatedByID:   permission.UpdatedByID,
+			UpdatedByType: permission.UpdatedByType,
 		})
 	}
 
@@ -261,29 +274,33 @@ func (c *PermissionController) GetBySystem(ctx *gin.Context) {
 		var systemResponse *dtos.SystemResponse
 		if permission.System != nil {
 			systemResponse = &dtos.SystemResponse{
-				ID:          permission.System.ID,
-				Nama:        permission.System.Nama,
-				Description: permission.System.Description,
-				Status:      permission.System.Status,
-				CreatedAt:   permission.System.CreatedAt,
-				UpdatedAt:   permission.System.UpdatedAt,
-				CreatedByID: permission.System.CreatedByID,
-				UpdatedByID: permission.System.UpdatedByID,
+				ID:            permission.System.ID,
+				Nama:          permission.System.Nama,
+				Description:   permission.System.Description,
+				Status:        permission.System.Status,
+				CreatedAt:     permission.System.CreatedAt,
+				UpdatedAt:     permission.System.UpdatedAt,
+				CreatedByID:   permission.System.CreatedByID,
+				CreatedByType: permission.System.CreatedByType,
+				UpdatedByID:   permission.System.UpdatedByID,
+				UpdatedByType: permission.System.UpdatedByType,
 			}
 		}
 
 		responseData = append(responseData, dtos.PermissionResponse{
-			ID:          permission.ID,
-			Name:        permission.Name,
-			Description: permission.Description,
-			GroupName:   permission.GroupName,
-			SystemID:    permission.SystemID,
-			System:      systemResponse,
-			Status:      permission.Status,
-			CreatedAt:   permission.CreatedAt,
-			UpdatedAt:   permission.UpdatedAt,
-			CreatedByID: permission.CreatedByID,
-			UpdatedByID: permission.UpdatedByID,
+			ID:            permission.ID,
+			Name:          permission.Name,
+			Description:   permission.Description,
+			GroupName:     permission.GroupName,
+			SystemID:      permission.SystemID,
+			System:        systemResponse,
+			Status:        permission.Status,
+			CreatedAt:     permission.CreatedAt,
+			UpdatedAt:     permission.UpdatedAt,
+			CreatedByID:   permission.CreatedByID,
+			CreatedByType: permission.CreatedByType,
+			UpdatedByID:   permission.UpdatedByID,
+			UpdatedByType: permission.UpdatedByType,
 		})
 	}
 
@@ -329,10 +346,10 @@ func (c *PermissionController) Update(ctx *gin.Context) {
 		data.Status = req.Status
 	}
 
-	// Get user ID from JWT token
-	userID, _ := ctx.Get("userID")
-	updatedByID := userID.(uint)
-	data.UpdatedByID = &updatedByID
+	// Get principal from JWT token
+	actor, _ := middleware.GetPrincipal(ctx)
+	data.UpdatedByID = &actor.ID
+	data.UpdatedByType = actor.TypePtr()
 
 	if err := c.service.Update(data); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -346,29 +363,33 @@ func (c *PermissionController) Update(ctx *gin.Context) {
 	var systemResponse *dtos.SystemResponse
 	if permissionData.System != nil {
 		systemResponse = &dtos.SystemResponse{
-			ID:          permissionData.System.ID,
-			Nama:        permissionData.System.Nama,
-			Description: permissionData.System.Description,
-			Status:      permissionData.System.Status,
-			CreatedAt:   permissionData.System.CreatedAt,
-			UpdatedAt:   permissionData.System.UpdatedAt,
-			CreatedByID: permissionData.System.CreatedByID,
-			UpdatedByID: permissionData.System.UpdatedByID,
+			ID:            permissionData.System.ID,
+			Nama:          permissionData.System.Nama,
+			Description:   permissionData.System.Description,
+			Status:        permissionData.System.Status,
+			CreatedAt:     permissionData.System.CreatedAt,
+			UpdatedAt:     permissionData.System.UpdatedAt,
+			CreatedByID:   permissionData.System.CreatedByID,
+			CreatedByType: permissionData.System.CreatedByType,
+			UpdatedByID:   permissionData.System.UpdatedByID,
+			UpdatedByType: permissionData.System.UpdatedByType,
 		}
 	}
 
 	response := dtos.PermissionResponse{
-		ID:          permissionData.ID,
-		Name:        permissionData.Name,
-		Description: permissionData.Description,
-		GroupName:   permissionData.GroupName,
-		SystemID:    permissionData.SystemID,
-		System:      systemResponse,
-		Status:      permissionData.Status,
-		CreatedAt:   permissionData.CreatedAt,
-		UpdatedAt:   permissionData.UpdatedAt,
-		CreatedByID: permissionData.CreatedByID,
-		UpdatedByID: permissionData.UpdatedByID,
+		ID:            permissionData.ID,
+		Name:          permissionData.Name,
+		Description:   permissionData.Description,
+		GroupName:     permissionData.GroupName,
+		SystemID:      permissionData.SystemID,
+		System:        systemResponse,
+		Status:        permissionData.Status,
+		CreatedAt:     permissionData.CreatedAt,
+		UpdatedAt:     permissionData.UpdatedAt,
+		CreatedByID:   permissionData.CreatedByID,
+		CreatedByType: permissionData.CreatedByType,
+		UpdatedByID:   permissionData.UpdatedByID,
+		UpdatedByType: permissionData.UpdatedByType,
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"data": response})
@@ -401,28 +422,32 @@ func mapPermissionToResponse(permission *models.Permission) *dtos.PermissionResp
 	var systemResponse *dtos.SystemResponse
 	if permission.System != nil {
 		systemResponse = &dtos.SystemResponse{
-			ID:          permission.System.ID,
-			Nama:        permission.System.Nama,
-			Description: permission.System.Description,
-			Status:      permission.System.Status,
-			CreatedAt:   permission.System.CreatedAt,
-			UpdatedAt:   permission.System.UpdatedAt,
-			CreatedByID: permission.System.CreatedByID,
-			UpdatedByID: permission.System.UpdatedByID,
+			ID:            permission.System.ID,
+			Nama:          permission.System.Nama,
+			Description:   permission.System.Description,
+			Status:        permission.System.Status,
+			CreatedAt:     permission.System.CreatedAt,
+			UpdatedAt:     permission.System.UpdatedAt,
+			CreatedByID:   permission.System.CreatedByID,
+			CreatedByType: permission.System.CreatedByType,
+			UpdatedByID:   permission.System.UpdatedByID,
+			UpdatedByType: permission.System.UpdatedByType,
 		}
 	}
 
 	return &dtos.PermissionResponse{
-		ID:          permission.ID,
-		Name:        permission.Name,
-		Description: permission.Description,
-		GroupName:   permission.GroupName,
-		SystemID:    permission.SystemID,
-		System:      systemResponse,
-		Status:      permission.Status,
-		CreatedAt:   permission.CreatedAt,
-		UpdatedAt:   permission.UpdatedAt,
-		CreatedByID: permission.CreatedByID,
-		UpdatedByID: permission.UpdatedByID,
+		ID:            permission.ID,
+		Name:          permission.Name,
+		Description:   permission.Description,
+		GroupName:     permission.GroupName,
+		SystemID:      permission.SystemID,
+		System:        systemResponse,
+		Status:        permission.Status,
+		CreatedAt:     permission.CreatedAt,
+		UpdatedAt:     permission.UpdatedAt,
+		CreatedByID:   permission.CreatedByID,
+		CreatedByType: permission.CreatedByType,
+		UpdatedByID:   permission.UpdatedByID,
+		UpdatedByType: permission.UpdatedByType,
 	}
 }
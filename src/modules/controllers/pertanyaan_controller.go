@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/services"
 
 	"github.com/gin-gonic/gin"
@@ -61,7 +62,7 @@ func (c *PertanyaanController) CreatePublic(ctx *gin.Context) {
 	}
 
 	// Call service
-	data, err := c.service.CreatePublic(files, &req)
+	data, err := c.service.CreatePublic(ctx.Request.Context(), files, &req)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -228,15 +229,15 @@ func (c *PertanyaanController) SendReply(ctx *gin.Context) {
 		DeskripsiJawaban: deskripsiJawaban,
 	}
 
-	// Get user ID from context
-	userID, exists := ctx.Get("userID")
+	// Get principal from context
+	actor, exists := middleware.GetPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
 	// Call service
-	data, err := c.service.SendReply(files, req, userID.(uint))
+	data, err := c.service.SendReply(ctx.Request.Context(), files, req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -298,14 +299,14 @@ func (c *PertanyaanController) DeletePertanyaan(ctx *gin.Context) {
 		return
 	}
 
-	// Get user ID from context
-	userID, exists := ctx.Get("userID")
+	// Get principal from context
+	actor, exists := middleware.GetPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
-	err := c.service.DeletePertanyaan(req.ID, userID.(uint))
+	err := c.service.DeletePertanyaan(req.ID, actor)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
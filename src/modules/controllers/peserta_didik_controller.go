@@ -3,8 +3,10 @@ package controllers
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/repositories"
 	"pintu-backend/src/modules/services"
 	"pintu-backend/src/utils"
@@ -33,12 +35,11 @@ func (c *PesertaDidikController) Create(ctx *gin.Context) {
 		return
 	}
 
-	// Get user ID from context (set by middleware)
-	userID, _ := ctx.Get("userID")
-	userIDUint := userID.(uint)
+	// Get principal from context (set by middleware)
+	actor, _ := middleware.GetPrincipal(ctx)
 
 	// Call service
-	result, err := c.service.Create(&req, userIDUint)
+	result, err := c.service.Create(&req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -234,22 +235,33 @@ func (c *PesertaDidikController) Update(ctx *gin.Context) {
 		KodePos:      ctx.PostForm("kode_pos"),
 		NamaAyah:     ctx.PostForm("nama_ayah"),
 		NamaIbu:      ctx.PostForm("nama_ibu"),
+		NomorHPOrtu:  ctx.PostForm("nomor_hp_ortu"),
+		EmailOrtu:    ctx.PostForm("email_ortu"),
 		Status:       ctx.PostForm("status"),
 		Username:     ctx.PostForm("username"),
 		Password:     ctx.PostForm("password"),
 	}
 
+	// Set NotifikasiOrtu only if provided
+	if notifikasiOrtu, ok := ctx.GetPostForm("notifikasi_ortu"); ok && notifikasiOrtu != "" {
+		enabled, err := strconv.ParseBool(notifikasiOrtu)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "notifikasi_ortu harus true atau false"})
+			return
+		}
+		req.NotifikasiOrtu = &enabled
+	}
+
 	// Set RoleIDs only if provided
 	if hasRoleIDs {
 		req.RoleIDs = &roleIDs
 	}
 
-	// Get user ID from context (set by middleware)
-	userID, _ := ctx.Get("userID")
-	userIDUint := userID.(uint)
+	// Get principal from context (set by middleware)
+	actor, _ := middleware.GetPrincipal(ctx)
 
 	// Call service with photo
-	result, err := c.service.Update(id, photo, req, userIDUint)
+	result, err := c.service.Update(ctx.Request.Context(), id, photo, req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -287,11 +299,10 @@ func (c *PesertaDidikController) ImportExcel(ctx *gin.Context) {
 	}
 	defer file.Close()
 
-	// Get user ID from context (set by middleware)
-	userID, _ := ctx.Get("userID")
-	userIDUint := userID.(uint)
+	// Get principal from context (set by middleware)
+	actor, _ := middleware.GetPrincipal(ctx)
 
-	result, err := c.service.ImportExcel(file, userIDUint)
+	result, err := c.service.ImportExcel(file, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -512,11 +523,10 @@ func (c *PesertaDidikController) ImportSiswaLulus(ctx *gin.Context) {
 	}
 	defer file.Close()
 
-	// Get user ID from context (set by middleware)
-	userID, _ := ctx.Get("userID")
-	userIDUint := userID.(uint)
+	// Get principal from context (set by middleware)
+	actor, _ := middleware.GetPrincipal(ctx)
 
-	result, err := c.service.ImportSiswaLulus(file, userIDUint)
+	result, err := c.service.ImportSiswaLulus(file, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "data": result})
 		return
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/repositories"
 	"pintu-backend/src/modules/services"
 	"pintu-backend/src/utils"
@@ -32,12 +33,11 @@ func (c *PesertaDidikRombelController) BulkCreate(ctx *gin.Context) {
 		return
 	}
 
-	// Get user ID from context (set by middleware)
-	userID, _ := ctx.Get("userID")
-	userIDUint := userID.(uint)
+	// Get principal from context (set by middleware)
+	actor, _ := middleware.GetPrincipal(ctx)
 
 	// Call service
-	result, err := c.service.BulkCreate(&req, userIDUint)
+	result, err := c.service.BulkCreate(&req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -134,12 +134,11 @@ func (c *PesertaDidikRombelController) Update(ctx *gin.Context) {
 		return
 	}
 
-	// Get user ID from context (set by middleware)
-	userID, _ := ctx.Get("userID")
-	userIDUint := userID.(uint)
+	// Get principal from context (set by middleware)
+	actor, _ := middleware.GetPrincipal(ctx)
 
 	// Call service
-	result, err := c.service.Update(req.ID, &req, userIDUint)
+	result, err := c.service.Update(req.ID, &req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -195,11 +194,10 @@ func (c *PesertaDidikRombelController) ImportExcel(ctx *gin.Context) {
 	}
 	defer file.Close()
 
-	// Get user ID from context (set by middleware)
-	userID, _ := ctx.Get("userID")
-	userIDUint := userID.(uint)
+	// Get principal from context (set by middleware)
+	actor, _ := middleware.GetPrincipal(ctx)
 
-	result, err := c.service.ImportExcel(file, userIDUint)
+	result, err := c.service.ImportExcel(file, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "data": result})
 		return
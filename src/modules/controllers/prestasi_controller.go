@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/repositories"
 	"pintu-backend/src/modules/services"
 
@@ -40,6 +41,7 @@ func NewPrestasiController(service services.PrestasiService) *PrestasiController
 // @Param juara formData string true "Rank/Position"
 // @Param keterangan formData string false "Description"
 // @Param ekstrakurikuler_id formData uint false "Ekstrakurikuler ID"
+// @Param tahun_pelajaran_id formData uint false "Tahun Pelajaran ID (default: tahun pelajaran of tanggal_lomba)"
 // @Param anggota_tim formData string false "Team members JSON array"
 // @Param foto formData file false "Achievement photos - multiple files allowed (max 5MB each)"
 // @Success 201 {object} gin.H{data=dtos.PrestasiResponse}
@@ -78,16 +80,15 @@ func (c *PrestasiController) Create(ctx *gin.Context) {
 		return
 	}
 
-	tahunPelajaranID := ctx.PostForm("tahun_pelajaran_id")
-	if tahunPelajaranID == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "tahun_pelajaran_id is required"})
-		return
-	}
-
-	tahunPelajaranIDUint, err := strconv.ParseUint(tahunPelajaranID, 10, 32)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid tahun_pelajaran_id format"})
-		return
+	// Optional, defaults to the tahun pelajaran of tanggal_lomba
+	var tahunPelajaranIDUint uint64
+	if tahunPelajaranID := ctx.PostForm("tahun_pelajaran_id"); tahunPelajaranID != "" {
+		parsed, err := strconv.ParseUint(tahunPelajaranID, 10, 32)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid tahun_pelajaran_id format"})
+			return
+		}
+		tahunPelajaranIDUint = parsed
 	}
 
 	// Get optional fields
@@ -157,15 +158,15 @@ func (c *PrestasiController) Create(ctx *gin.Context) {
 		AnggotaTim:        anggotaTim,
 	}
 
-	// Get user ID from context (set by auth middleware)
-	userID, exists := ctx.Get("userID")
+	// Get principal from context (set by auth middleware)
+	actor, exists := middleware.GetPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
 	// Call service
-	data, err := c.service.Create(foto, fotoThumbnails, req, userID.(uint))
+	data, err := c.service.Create(ctx.Request.Context(), foto, fotoThumbnails, req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -412,14 +413,14 @@ func (c *PrestasiController) Update(ctx *gin.Context) {
 		AnggotaTim:        anggotaTim,
 	}
 
-	// Get user ID from context
-	userID, exists := ctx.Get("userID")
+	// Get principal from context
+	actor, exists := middleware.GetPrincipal(ctx)
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
-	data, err := c.service.Update(uint(id), foto, fotoThumbnails, req, userID.(uint))
+	data, err := c.service.Update(ctx.Request.Context(), uint(id), foto, fotoThumbnails, req, actor)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
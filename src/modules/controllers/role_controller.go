@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
 	"pintu-backend/src/modules/services"
@@ -37,16 +38,16 @@ func (c *RoleController) Create(ctx *gin.Context) {
 		return
 	}
 
-	// Get user ID from JWT token
-	userID, _ := ctx.Get("userID")
-	createdByID := userID.(uint)
+	// Get principal from JWT token
+	actor, _ := middleware.GetPrincipal(ctx)
 
 	role := &models.Role{
-		Name:        req.Name,
-		Description: req.Description,
-		SystemID:    &req.SystemID,
-		Status:      req.Status,
-		CreatedByID: &createdByID,
+		Name:          req.Name,
+		Description:   req.Description,
+		SystemID:      &req.SystemID,
+		Status:        req.Status,
+		CreatedByID:   &actor.ID,
+		CreatedByType: actor.TypePtr(),
 	}
 
 	// Create role with permissions
@@ -183,10 +184,10 @@ func (c *RoleController) Update(ctx *gin.Context) {
 		data.Status = req.Status
 	}
 
-	// Get user ID from JWT token
-	userID, _ := ctx.Get("userID")
-	updatedByID := userID.(uint)
-	data.UpdatedByID = &updatedByID
+	// Get principal from JWT token
+	actor, _ := middleware.GetPrincipal(ctx)
+	data.UpdatedByID = &actor.ID
+	data.UpdatedByType = actor.TypePtr()
 
 	if err := c.service.Update(data); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -236,28 +237,32 @@ func mapRoleToResponse(role *models.Role) *dtos.RoleResponse {
 	var systemResponse *dtos.SystemResponse
 	if role.System != nil {
 		systemResponse = &dtos.SystemResponse{
-			ID:          role.System.ID,
-			Nama:        role.System.Nama,
-			Description: role.System.Description,
-			Status:      role.System.Status,
-			CreatedAt:   role.System.CreatedAt,
-			UpdatedAt:   role.System.UpdatedAt,
-			CreatedByID: role.System.CreatedByID,
-			UpdatedByID: role.System.UpdatedByID,
+			ID:            role.System.ID,
+			Nama:          role.System.Nama,
+			Description:   role.System.Description,
+			Status:        role.System.Status,
+			CreatedAt:     role.System.CreatedAt,
+			UpdatedAt:     role.System.UpdatedAt,
+			CreatedByID:   role.System.CreatedByID,
+			CreatedByType: role.System.CreatedByType,
+			UpdatedByID:   role.System.UpdatedByID,
+			UpdatedByType: role.System.UpdatedByType,
 		}
 	}
 
 	return &dtos.RoleResponse{
-		ID:          role.ID,
-		Name:        role.Name,
-		Description: role.Description,
-		SystemID:    role.SystemID,
-		System:      systemResponse,
-		Status:      role.Status,
-		CreatedAt:   role.CreatedAt,
-		UpdatedAt:   role.UpdatedAt,
-		CreatedByID: role.CreatedByID,
-		UpdatedByID: role.UpdatedByID,
+		ID:            role.ID,
+		Name:          role.Name,
+		Description:   role.Description,
+		SystemID:      role.SystemID,
+		System:        systemResponse,
+		Status:        role.Status,
+		CreatedAt:     role.CreatedAt,
+		UpdatedAt:     role.UpdatedAt,
+		CreatedByID:   role.CreatedByID,
+		CreatedByType: role.CreatedByType,
+		UpdatedByID:   role.UpdatedByID,
+		UpdatedByType: role.UpdatedByType,
 	}
 }
 
@@ -270,14 +275,16 @@ func (c *RoleController) mapRoleToResponseWithPermissionDetails(role *models.Rol
 	var systemResponse *dtos.SystemResponse
 	if role.System != nil {
 		systemResponse = &dtos.SystemResponse{
-			ID:          role.System.ID,
-			Nama:        role.System.Nama,
-			Description: role.System.Description,
-			Status:      role.System.Status,
-			CreatedAt:   role.System.CreatedAt,
-			UpdatedAt:   role.System.UpdatedAt,
-			CreatedByID: role.System.CreatedByID,
-			UpdatedByID: role.System.UpdatedByID,
+			ID:            role.System.ID,
+			Nama:          role.System.Nama,
+			Description:   role.System.Description,
+			Status:        role.System.Status,
+			CreatedAt:     role.System.CreatedAt,
+			UpdatedAt:     role.System.UpdatedAt,
+			CreatedByID:   role.System.CreatedByID,
+			CreatedByType: role.System.CreatedByType,
+			UpdatedByID:   role.System.UpdatedByID,
+			UpdatedByType: role.System.UpdatedByType,
 		}
 	}
 
@@ -301,16 +308,18 @@ func (c *RoleController) mapRoleToResponseWithPermissionDetails(role *models.Rol
 	}
 
 	return &dtos.RoleResponse{
-		ID:          role.ID,
-		Name:        role.Name,
-		Description: role.Description,
-		SystemID:    role.SystemID,
-		System:      systemResponse,
-		Status:      role.Status,
-		Permissions: permissionData,
-		CreatedAt:   role.CreatedAt,
-		UpdatedAt:   role.UpdatedAt,
-		CreatedByID: role.CreatedByID,
-		UpdatedByID: role.UpdatedByID,
+		ID:            role.ID,
+		Name:          role.Name,
+		Description:   role.Description,
+		SystemID:      role.SystemID,
+		System:        systemResponse,
+		Status:        role.Status,
+		Permissions:   permissionData,
+		CreatedAt:     role.CreatedAt,
+		UpdatedAt:     role.UpdatedAt,
+		CreatedByID:   role.CreatedByID,
+		CreatedByType: role.CreatedByType,
+		UpdatedByID:   role.UpdatedByID,
+		UpdatedByType: role.UpdatedByType,
 	}
 }
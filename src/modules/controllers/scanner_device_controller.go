@@ -0,0 +1,235 @@
+package controllers
+
+import (
+	"net/http"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/modules/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScannerDeviceController handles HTTP requests for ScannerDevice
+type ScannerDeviceController struct {
+	service services.ScannerDeviceService
+}
+
+// NewScannerDeviceController creates a new ScannerDevice controller
+func NewScannerDeviceController(service services.ScannerDeviceService) *ScannerDeviceController {
+	return &ScannerDeviceController{service: service}
+}
+
+// Create registers a new scanner device
+// @Summary Create new ScannerDevice
+// @Description Register a scanner device; the API key or HMAC secret is only returned in this response
+// @Tags scanner_device
+// @Accept json
+// @Produce json
+// @Param body body dtos.ScannerDeviceCreateRequest true "Request body"
+// @Success 201 {object} gin.H{data=dtos.ScannerDeviceCredentialResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Router /api/v1/scanner-devices/create-scanner-device [post]
+func (c *ScannerDeviceController) Create(ctx *gin.Context) {
+	var req dtos.ScannerDeviceCreateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get principal from context (set by auth middleware)
+	actor, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	data, err := c.service.Create(&req, actor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"data": data})
+}
+
+// GetAll retrieves all scanner devices
+// @Summary Get all ScannerDevice
+// @Description Retrieve scanner devices with filters and pagination
+// @Tags scanner_device
+// @Accept json
+// @Produce json
+// @Param body body dtos.ScannerDeviceGetAllRequest true "Request body"
+// @Success 200 {object} gin.H{data=[]dtos.ScannerDeviceResponse}
+// @Failure 401 {object} gin.H{error=string}
+// @Failure 500 {object} gin.H{error=string}
+// @Router /api/v1/scanner-devices/get-scanner-devices [post]
+func (c *ScannerDeviceController) GetAll(ctx *gin.Context) {
+	var req dtos.ScannerDeviceGetAllRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Default values
+	limit := 10
+	page := 1
+	if req.Pagination.Limit > 0 && req.Pagination.Limit <= 100 {
+		limit = req.Pagination.Limit
+	}
+	if req.Pagination.Page > 0 {
+		page = req.Pagination.Page
+	}
+	offset := (page - 1) * limit
+
+	data, err := c.service.GetAllWithFilter(repositories.GetScannerDeviceParams{
+		Filter: repositories.GetScannerDeviceFilter{
+			Nama:   req.Search.Nama,
+			Status: req.Search.Status,
+		},
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": data.Data,
+		"pagination": gin.H{
+			"limit":       data.Pagination.Limit,
+			"offset":      data.Pagination.Offset,
+			"page":        data.Pagination.Page,
+			"total":       data.Pagination.Total,
+			"total_pages": data.Pagination.TotalPages,
+		},
+	})
+}
+
+// GetByID retrieves ScannerDevice by ID
+// @Summary Get ScannerDevice by ID
+// @Description Retrieve scanner device details by ID
+// @Tags scanner_device
+// @Accept json
+// @Produce json
+// @Param body body dtos.IDRequest true "Request body with ID"
+// @Success 200 {object} gin.H{data=dtos.ScannerDeviceResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Failure 404 {object} gin.H{error=string}
+// @Router /api/v1/scanner-devices/get-scanner-device-by-id [post]
+func (c *ScannerDeviceController) GetByID(ctx *gin.Context) {
+	var req dtos.IDRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	data, err := c.service.GetByID(req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Scanner device not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// Update updates ScannerDevice
+// @Summary Update ScannerDevice
+// @Description Update scanner device name, location, allowed IP ranges, active hours or status
+// @Tags scanner_device
+// @Accept json
+// @Produce json
+// @Param body body dtos.ScannerDeviceUpdateRequest true "Request body"
+// @Success 200 {object} gin.H{data=dtos.ScannerDeviceResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Router /api/v1/scanner-devices/update-scanner-device [post]
+func (c *ScannerDeviceController) Update(ctx *gin.Context) {
+	var req dtos.ScannerDeviceUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get principal from context
+	actor, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	data, err := c.service.Update(&req, actor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// RotateSecret issues a new credential for a scanner device
+// @Summary Rotate ScannerDevice secret
+// @Description Issue a new API key or HMAC secret; the old credential stops working immediately
+// @Tags scanner_device
+// @Accept json
+// @Produce json
+// @Param body body dtos.ScannerDeviceRotateSecretRequest true "Request body"
+// @Success 200 {object} gin.H{data=dtos.ScannerDeviceCredentialResponse}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Router /api/v1/scanner-devices/rotate-scanner-device-secret [post]
+func (c *ScannerDeviceController) RotateSecret(ctx *gin.Context) {
+	var req dtos.ScannerDeviceRotateSecretRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get principal from context
+	actor, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	data, err := c.service.RotateSecret(&req, actor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// Delete deletes ScannerDevice by ID
+// @Summary Delete ScannerDevice
+// @Description Delete scanner device by ID; its credential stops working immediately
+// @Tags scanner_device
+// @Accept json
+// @Produce json
+// @Param body body dtos.IDRequest true "Request body with ID"
+// @Success 200 {object} gin.H{message=string}
+// @Failure 400 {object} gin.H{error=string}
+// @Failure 401 {object} gin.H{error=string}
+// @Failure 404 {object} gin.H{error=string}
+// @Router /api/v1/scanner-devices/delete-scanner-device [post]
+func (c *ScannerDeviceController) Delete(ctx *gin.Context) {
+	var req dtos.IDRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	if err := c.service.Delete(req.ID); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Scanner device not found"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Scanner device deleted successfully",
+	})
+}
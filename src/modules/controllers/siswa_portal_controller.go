@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
+	"pintu-backend/src/modules/services"
+	"pintu-backend/src/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SiswaPortalController handles HTTP requests for the logged-in peserta didik
+type SiswaPortalController struct {
+	service services.SiswaPortalService
+}
+
+// NewSiswaPortalController creates a new SiswaPortal controller
+func NewSiswaPortalController(service services.SiswaPortalService) *SiswaPortalController {
+	return &SiswaPortalController{service: service}
+}
+
+// GetProfil retrieves the profile of the logged-in student
+func (c *SiswaPortalController) GetProfil(ctx *gin.Context) {
+	siswa, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	result, err := c.service.GetProfil(siswa.ID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// GetRombelAktif retrieves the rombel of the logged-in student in the active tahun pelajaran
+func (c *SiswaPortalController) GetRombelAktif(ctx *gin.Context) {
+	siswa, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	result, err := c.service.GetRombelAktif(siswa.ID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// GetRiwayatAbsensi retrieves the attendance history of the logged-in student
+func (c *SiswaPortalController) GetRiwayatAbsensi(ctx *gin.Context) {
+	var req dtos.SiswaAbsensiRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errors := utils.FormatValidationError(err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	siswa, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	result, err := c.service.GetRiwayatAbsensi(siswa.ID, &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// GetPrestasi retrieves the prestasi of the logged-in student
+func (c *SiswaPortalController) GetPrestasi(ctx *gin.Context) {
+	siswa, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	result, err := c.service.GetPrestasi(siswa.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// DownloadKartuPelajar downloads the kartu pelajar of the logged-in student as PDF
+func (c *SiswaPortalController) DownloadKartuPelajar(ctx *gin.Context) {
+	siswa, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	pdfBytes, err := c.service.DownloadKartuPelajar(siswa.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Content-Type", "application/pdf")
+	ctx.Header("Content-Disposition", "attachment; filename=kartu_pelajar.pdf")
+	ctx.Header("Content-Length", fmt.Sprintf("%d", len(pdfBytes)))
+
+	ctx.Data(http.StatusOK, "application/pdf", pdfBytes)
+}
+
+// GetKartuQR returns a rotating signed QR of the logged-in student's kartu pelajar
+func (c *SiswaPortalController) GetKartuQR(ctx *gin.Context) {
+	siswa, exists := middleware.GetPrincipal(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	result, err := c.service.GetKartuQR(siswa.ID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// A QR that is still valid must not be served from a cache
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, gin.H{"data": result})
+}
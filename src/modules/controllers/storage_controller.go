@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"pintu-backend/src/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StorageController serves files of the local storage driver
+type StorageController struct {
+	storage *utils.LocalStorage
+}
+
+// NewStorageController creates a new Storage controller
+func NewStorageController(storage *utils.LocalStorage) *StorageController {
+	return &StorageController{storage: storage}
+}
+
+// ServeFile streams a stored file. A presigned URL (expires and signature query) must carry a valid, unexpired signature;
+// private files are only served through a presigned URL.
+// @Summary Serve stored file
+// @Description Serve file dari storage lokal (STORAGE_DRIVER=local)
+// @Tags storage
+// @Produce octet-stream
+// @Param key path string true "Storage key"
+// @Success 200 {file} binary
+// @Failure 403 {object} gin.H{error=string}
+// @Failure 404 {object} gin.H{error=string}
+// @Router /storage/{key} [get]
+func (c *StorageController) ServeFile(ctx *gin.Context) {
+	fileKey := strings.TrimPrefix(ctx.Param("key"), "/")
+
+	expires, signature := ctx.Query("expires"), ctx.Query("signature")
+	if (expires != "" || signature != "") && !c.storage.VerifyPresigned(fileKey, expires, signature, time.Now()) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "URL tidak valid atau sudah kedaluwarsa"})
+		return
+	}
+	if expires == "" && signature == "" && utils.IsPrivateStorageKey(fileKey) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "file ini hanya dapat diakses melalui URL bertanda tangan"})
+		return
+	}
+
+	reader, info, err := c.storage.Download(fileKey)
+	if err != nil {
+		if errors.Is(err, utils.ErrStorageObjectNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer reader.Close()
+
+	ctx.Header("Last-Modified", info.LastModified.UTC().Format(http.TimeFormat))
+	ctx.DataFromReader(http.StatusOK, info.Size, info.ContentType, reader, nil)
+}
@@ -2,8 +2,10 @@ package controllers
 
 import (
 	"net/http"
+	"strings"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
 	"pintu-backend/src/modules/services"
@@ -29,15 +31,22 @@ func (c *SystemController) Create(ctx *gin.Context) {
 		return
 	}
 
-	// Get user ID from JWT token
-	userID, _ := ctx.Get("userID")
-	createdByID := userID.(uint)
+	// Get principal from JWT token
+	actor, _ := middleware.GetPrincipal(ctx)
+
+	// Default the code to the upper-cased name
+	code := req.Code
+	if code == "" {
+		code = strings.ToUpper(req.Nama)
+	}
 
 	system := &models.System{
-		Nama:        req.Nama,
-		Description: req.Description,
-		Status:      req.Status,
-		CreatedByID: &createdByID,
+		Nama:          req.Nama,
+		Code:          code,
+		Description:   req.Description,
+		Status:        req.Status,
+		CreatedByID:   &actor.ID,
+		CreatedByType: actor.TypePtr(),
 	}
 
 	if err := c.service.Create(system); err != nil {
@@ -105,14 +114,17 @@ func (c *SystemController) GetAll(ctx *gin.Context) {
 	var responseData []dtos.SystemResponse
 	for _, system := range systems {
 		responseData = append(responseData, dtos.SystemResponse{
-			ID:          system.ID,
-			Nama:        system.Nama,
-			Description: system.Description,
-			Status:      system.Status,
-			CreatedAt:   system.CreatedAt,
-			UpdatedAt:   system.UpdatedAt,
-			CreatedByID: system.CreatedByID,
-			UpdatedByID: system.UpdatedByID,
+			ID:            system.ID,
+			Nama:          system.Nama,
+			Code:          system.Code,
+			Description:   system.Description,
+			Status:        system.Status,
+			CreatedAt:     system.CreatedAt,
+			UpdatedAt:     system.UpdatedAt,
+			CreatedByID:   system.CreatedByID,
+			CreatedByType: system.CreatedByType,
+			UpdatedByID:   system.UpdatedByID,
+			UpdatedByType: system.UpdatedByType,
 		})
 	}
 
@@ -135,6 +147,7 @@ func (c *SystemController) Update(ctx *gin.Context) {
 	type UpdateRequest struct {
 		ID          uint   `json:"id" binding:"required"`
 		Nama        string `json:"nama"`
+		Code        string `json:"code"`
 		Description string `json:"description"`
 		Status      string `json:"status"`
 	}
@@ -154,6 +167,9 @@ func (c *SystemController) Update(ctx *gin.Context) {
 	if req.Nama != "" {
 		data.Nama = req.Nama
 	}
+	if req.Code != "" {
+		data.Code = req.Code
+	}
 	if req.Description != "" {
 		data.Description = req.Description
 	}
@@ -161,10 +177,10 @@ func (c *SystemController) Update(ctx *gin.Context) {
 		data.Status = req.Status
 	}
 
-	// Get user ID from JWT token
-	userID, _ := ctx.Get("userID")
-	updatedByID := userID.(uint)
-	data.UpdatedByID = &updatedByID
+	// Get principal from JWT token
+	actor, _ := middleware.GetPrincipal(ctx)
+	data.UpdatedByID = &actor.ID
+	data.UpdatedByType = actor.TypePtr()
 
 	if err := c.service.Update(data); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -4,9 +4,11 @@ import (
 	"net/http"
 
 	"pintu-backend/src/dtos"
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
 	"pintu-backend/src/modules/services"
+	"pintu-backend/src/utils"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
@@ -37,16 +39,16 @@ func (c *UserController) Create(ctx *gin.Context) {
 		return
 	}
 
-	// Get user ID from JWT token
-	userID, _ := ctx.Get("userID")
-	createdByID := userID.(uint)
+	// Get principal from JWT token
+	actor, _ := middleware.GetPrincipal(ctx)
 
 	user := &models.User{
-		Nama:        req.Nama,
-		Username:    req.Username,
-		Password:    string(hashedPassword),
-		Status:      req.Status,
-		CreatedByID: &createdByID,
+		Nama:          req.Nama,
+		Username:      req.Username,
+		Password:      string(hashedPassword),
+		Status:        req.Status,
+		CreatedByID:   &actor.ID,
+		CreatedByType: actor.TypePtr(),
 	}
 
 	if err := c.service.Create(user, req.RoleIDs); err != nil {
@@ -181,10 +183,10 @@ func (c *UserController) Update(ctx *gin.Context) {
 		data.Status = req.Status
 	}
 
-	// Get user ID from JWT token
-	userID, _ := ctx.Get("userID")
-	updatedByID := userID.(uint)
-	data.UpdatedByID = &updatedByID
+	// Get principal from JWT token
+	actor, _ := middleware.GetPrincipal(ctx)
+	data.UpdatedByID = &actor.ID
+	data.UpdatedByType = actor.TypePtr()
 
 	if err := c.service.Update(data, req.RoleIDs); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -232,13 +234,13 @@ func (c *UserController) UpdatePassword(ctx *gin.Context) {
 
 	user.Password = string(hashedPassword)
 
-	// Get user ID from JWT token
-	userID, _ := ctx.Get("userID")
-	updatedByID := userID.(uint)
-	user.UpdatedByID = &updatedByID
+	// Get principal from JWT token
+	actor, _ := middleware.GetPrincipal(ctx)
+	user.UpdatedByID = &actor.ID
+	user.UpdatedByType = actor.TypePtr()
 
 	// Keep existing roles when updating password
-	if err := c.service.Update(user, []uint{}); err != nil {
+	if err := c.service.UpdatePassword(user); err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -264,6 +266,27 @@ func (c *UserController) Delete(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
 }
 
+// Unlock lifts the temporary login lockout of a user, pegawai or siswa account
+func (c *UserController) Unlock(ctx *gin.Context) {
+	var req dtos.UserUnlockRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principalType := req.PrincipalType
+	if principalType == "" {
+		principalType = utils.PrincipalUser
+	}
+
+	if err := c.service.Unlock(principalType, req.ID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Account unlocked successfully"})
+}
+
 // Helper function to map User model to UserResponse DTO
 func mapUserToResponse(user *models.User) *dtos.UserResponse {
 	if user == nil {
@@ -282,29 +305,34 @@ func mapUserToResponse(user *models.User) *dtos.UserResponse {
 		}
 
 		roles[i] = dtos.RoleResponse{
-			ID:          role.ID,
-			Name:        role.Name,
-			Description: role.Description,
-			SystemID:    role.SystemID,
-			System:      system,
-			Status:      role.Status,
-			CreatedAt:   role.CreatedAt,
-			UpdatedAt:   role.UpdatedAt,
-			CreatedByID: role.CreatedByID,
-			UpdatedByID: role.UpdatedByID,
+			ID:            role.ID,
+			Name:          role.Name,
+			Description:   role.Description,
+			SystemID:      role.SystemID,
+			System:        system,
+			Status:        role.Status,
+			CreatedAt:     role.CreatedAt,
+			UpdatedAt:     role.UpdatedAt,
+			CreatedByID:   role.CreatedByID,
+			CreatedByType: role.CreatedByType,
+			UpdatedByID:   role.UpdatedByID,
+			UpdatedByType: role.UpdatedByType,
 		}
 	}
 
 	return &dtos.UserResponse{
-		ID:          user.ID,
-		Nama:        user.Nama,
-		Username:    user.Username,
-		Roles:       roles,
-		Status:      user.Status,
-		CreatedAt:   user.CreatedAt,
-		UpdatedAt:   user.UpdatedAt,
-		CreatedByID: user.CreatedByID,
-		UpdatedByID: user.UpdatedByID,
+		ID:            user.ID,
+		Nama:          user.Nama,
+		Username:      user.Username,
+		Roles:         roles,
+		Status:        user.Status,
+		LockedUntil:   user.LockedUntil,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+		CreatedByID:   user.CreatedByID,
+		CreatedByType: user.CreatedByType,
+		UpdatedByID:   user.UpdatedByID,
+		UpdatedByType: user.UpdatedByType,
 	}
 }
 
@@ -326,28 +354,33 @@ func mapUserToResponseDetail(user *models.User) *dtos.UserResponseDetail {
 		}
 
 		roles[i] = dtos.RoleResponse{
-			ID:          role.ID,
-			Name:        role.Name,
-			Description: role.Description,
-			SystemID:    role.SystemID,
-			System:      system,
-			Status:      role.Status,
-			CreatedAt:   role.CreatedAt,
-			UpdatedAt:   role.UpdatedAt,
-			CreatedByID: role.CreatedByID,
-			UpdatedByID: role.UpdatedByID,
+			ID:            role.ID,
+			Name:          role.Name,
+			Description:   role.Description,
+			SystemID:      role.SystemID,
+			System:        system,
+			Status:        role.Status,
+			CreatedAt:     role.CreatedAt,
+			UpdatedAt:     role.UpdatedAt,
+			CreatedByID:   role.CreatedByID,
+			CreatedByType: role.CreatedByType,
+			UpdatedByID:   role.UpdatedByID,
+			UpdatedByType: role.UpdatedByType,
 		}
 	}
 
 	return &dtos.UserResponseDetail{
-		ID:          user.ID,
-		Nama:        user.Nama,
-		Username:    user.Username,
-		Roles:       roles,
-		Status:      user.Status,
-		CreatedAt:   user.CreatedAt,
-		UpdatedAt:   user.UpdatedAt,
-		CreatedByID: user.CreatedByID,
-		UpdatedByID: user.UpdatedByID,
+		ID:            user.ID,
+		Nama:          user.Nama,
+		Username:      user.Username,
+		Roles:         roles,
+		Status:        user.Status,
+		LockedUntil:   user.LockedUntil,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+		CreatedByID:   user.CreatedByID,
+		CreatedByType: user.CreatedByType,
+		UpdatedByID:   user.UpdatedByID,
+		UpdatedByType: user.UpdatedByType,
 	}
 }
@@ -12,6 +12,7 @@ type Absensi struct {
 	JamDatang        *string   `gorm:"column:jam_datang;type:time" json:"jam_datang"`
 	JamPulang        *string   `gorm:"column:jam_pulang;type:time" json:"jam_pulang"`
 	Status           *string   `gorm:"column:status;size:20" json:"status"`
+	ScannerDeviceID  *uint     `gorm:"column:scanner_device_id" json:"scanner_device_id"`
 	CreatedAt        time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt        time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
 	
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// AbsensiPegawai represents the daily check-in/check-out of a guru or tendik
+type AbsensiPegawai struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	KepegawaianID   uint      `gorm:"column:kepegawaian_id;not null" json:"kepegawaian_id"`
+	Tanggal         time.Time `gorm:"column:tanggal;type:date;not null" json:"tanggal"`
+	JamDatang       *string   `gorm:"column:jam_datang;type:time" json:"jam_datang"`
+	JamPulang       *string   `gorm:"column:jam_pulang;type:time" json:"jam_pulang"`
+	Status          *string   `gorm:"column:status;size:20" json:"status"`
+	ScannerDeviceID *uint     `gorm:"column:scanner_device_id" json:"scanner_device_id"`
+	CreatedAt       time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+
+	// Relationships
+	Kepegawaian *Kepegawaian `gorm:"foreignKey:KepegawaianID" json:"kepegawaian,omitempty"`
+}
+
+// TableName specifies the table name for AbsensiPegawai
+func (m *AbsensiPegawai) TableName() string {
+	return "absensi_pegawai"
+}
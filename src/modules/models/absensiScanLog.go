@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// AbsensiScanLog records the outcome of an offline batch scan, keyed by device and client scan ID
+type AbsensiScanLog struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	ScannerDeviceID uint      `gorm:"not null;uniqueIndex:uq_absensi_scan_logs_device_client" json:"scanner_device_id"`
+	ClientScanID    string    `gorm:"type:varchar(100);not null;uniqueIndex:uq_absensi_scan_logs_device_client" json:"client_scan_id"`
+	Barcode         string    `gorm:"type:varchar(255);not null" json:"barcode"`
+	ScannedAt       time.Time `gorm:"not null" json:"scanned_at"`
+	PesertaDidikID  *uint     `json:"peserta_didik_id"`
+	Action          string    `gorm:"type:varchar(20);not null" json:"action"`
+	Reason          *string   `gorm:"type:text" json:"reason"`
+	CreatedAt       time.Time `json:"created_at"`
+
+	// Relationships
+	PesertaDidik *PesertaDidik `gorm:"foreignKey:PesertaDidikID" json:"peserta_didik,omitempty"`
+}
+
+// TableName specifies the table name for AbsensiScanLog
+func (m *AbsensiScanLog) TableName() string {
+	return "absensi_scan_logs"
+}
@@ -17,7 +17,9 @@ type Application struct {
 	UpdatedAt        time.Time
 	DeletedAt        gorm.DeletedAt `gorm:"index"`
 	CreatedByID      *uint
+	CreatedByType    *string
 	UpdatedByID      *uint
+	UpdatedByType    *string
 }
 
 // TableName specifies the table name for Application
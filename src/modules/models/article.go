@@ -11,11 +11,22 @@ import (
 
 // FileItem represents a single file in the files array
 type FileItem struct {
-	ID        string `json:"id"`
-	Filename  string `json:"filename"`
-	URL       string `json:"url"`
-	Size      int64  `json:"size"`
-	Thumbnail string `json:"thumbnail,omitempty"` // "active" or "inactive"
+	ID        string                 `json:"id"`
+	Filename  string                 `json:"filename"`
+	URL       string                 `json:"url"`
+	Size      int64                  `json:"size"`
+	Thumbnail string                 `json:"thumbnail,omitempty"` // "active" or "inactive"
+	Width     int                    `json:"width,omitempty"`
+	Height    int                    `json:"height,omitempty"`
+	Variants  map[string]FileVariant `json:"variants,omitempty"` // "thumbnail", "medium" and "original"
+}
+
+// FileVariant represents a resized variant of an uploaded image, URL holds the storage key
+type FileVariant struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Size   int64  `json:"size"`
 }
 
 // Article represents the Article model
@@ -26,6 +37,7 @@ type Article struct {
 	Kategori        string         `gorm:"not null" json:"kategori"`
 	Deskripsi       string         `gorm:"type:text" json:"deskripsi"`
 	Gambar          string         `json:"gambar"`
+	GambarVariants  datatypes.JSON `gorm:"type:jsonb" json:"gambar_variants"`
 	Files           datatypes.JSON `gorm:"type:jsonb;default:'[]'" json:"files"`
 	Penulis         string         `gorm:"not null" json:"penulis"`
 	StatusPublikasi string         `gorm:"default:draft" json:"status_publikasi"`
@@ -33,7 +45,9 @@ type Article struct {
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
 	CreatedByID     *uint          `json:"created_by_id"`
+	CreatedByType   *string        `json:"created_by_type"`
 	UpdatedByID     *uint          `json:"updated_by_id"`
+	UpdatedByType   *string        `json:"updated_by_type"`
 	DeletedAt       gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 }
 
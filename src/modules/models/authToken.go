@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// RefreshToken represents a rotating refresh token issued alongside an access token
+type RefreshToken struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	PrincipalType   string     `gorm:"type:varchar(20);not null" json:"principal_type"`
+	PrincipalID     uint       `gorm:"not null" json:"principal_id"`
+	RoleID          *uint      `json:"role_id"`
+	TokenHash       string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	AccessJTI       string     `gorm:"column:access_jti;type:varchar(64);not null" json:"-"`
+	AccessExpiresAt time.Time  `gorm:"not null" json:"access_expires_at"`
+	ExpiresAt       time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt       *time.Time `json:"revoked_at"`
+	ReplacedByID    *uint      `json:"replaced_by_id"`
+	UserAgent       string     `gorm:"type:varchar(255)" json:"user_agent"`
+	IPAddress       string     `gorm:"type:varchar(45)" json:"ip_address"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name for RefreshToken
+func (m *RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// RevokedToken represents an access token jti on the denylist
+type RevokedToken struct {
+	JTI       string    `gorm:"column:jti;primaryKey;type:varchar(64)" json:"jti"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	RevokedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"revoked_at"`
+}
+
+// TableName specifies the table name for RevokedToken
+func (m *RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
@@ -24,7 +24,9 @@ type Contact struct {
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	CreatedByID *uint        `json:"created_by_id"`
+	CreatedByType *string      `json:"created_by_type"`
 	UpdatedByID *uint        `json:"updated_by_id"`
+	UpdatedByType *string      `json:"updated_by_type"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 }
 
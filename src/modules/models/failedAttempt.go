@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// FailedAttempt represents a failed authentication attempt counted by the throttling window
+type FailedAttempt struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Scope      string    `gorm:"type:varchar(50);not null" json:"scope"`
+	IPAddress  string    `gorm:"type:varchar(45);not null" json:"ip_address"`
+	Identifier string    `gorm:"type:varchar(255);not null" json:"identifier"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for FailedAttempt
+func (m *FailedAttempt) TableName() string {
+	return "failed_attempts"
+}
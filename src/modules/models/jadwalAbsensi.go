@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+)
+
+// ProfilJadwalAbsensi is a named set of scan time windows (e.g. "Reguler Kelas 1-2", "Ramadan")
+type ProfilJadwalAbsensi struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	Nama             string    `gorm:"column:nama;size:100;not null" json:"nama"`
+	Keterangan       *string   `gorm:"column:keterangan;type:text" json:"keterangan"`
+	JamDatangMulai   string    `gorm:"column:jam_datang_mulai;type:time;not null" json:"jam_datang_mulai"`
+	JamMaxDatang     string    `gorm:"column:jam_max_datang;type:time;not null" json:"jam_max_datang"`
+	JamDatangSelesai string    `gorm:"column:jam_datang_selesai;type:time;not null" json:"jam_datang_selesai"`
+	JamPulangMulai   string    `gorm:"column:jam_pulang_mulai;type:time;not null" json:"jam_pulang_mulai"`
+	JamPulangSelesai string    `gorm:"column:jam_pulang_selesai;type:time;not null" json:"jam_pulang_selesai"`
+	Status           string    `gorm:"column:status;default:active" json:"status"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	CreatedByID      *uint     `json:"created_by_id"`
+	CreatedByType    *string   `json:"created_by_type"`
+	UpdatedByID      *uint     `json:"updated_by_id"`
+	UpdatedByType    *string   `json:"updated_by_type"`
+
+	// Relationships
+	Hari []ProfilJadwalAbsensiHari `gorm:"foreignKey:ProfilJadwalAbsensiID" json:"hari,omitempty"`
+}
+
+// TableName specifies the table name for ProfilJadwalAbsensi
+func (m *ProfilJadwalAbsensi) TableName() string {
+	return "profil_jadwal_absensi"
+}
+
+// ProfilJadwalAbsensiHari overrides the times of a profile on one weekday (e.g. earlier pulang on Friday).
+// Nil times keep the profile times.
+type ProfilJadwalAbsensiHari struct {
+	ID                    uint    `gorm:"primaryKey" json:"id"`
+	ProfilJadwalAbsensiID uint    `gorm:"column:profil_jadwal_absensi_id;not null" json:"profil_jadwal_absensi_id"`
+	Hari                  int     `gorm:"column:hari;not null" json:"hari"` // ISO weekday, 1 = Senin ... 7 = Minggu
+	JamDatangMulai        *string `gorm:"column:jam_datang_mulai;type:time" json:"jam_datang_mulai"`
+	JamMaxDatang          *string `gorm:"column:jam_max_datang;type:time" json:"jam_max_datang"`
+	JamDatangSelesai      *string `gorm:"column:jam_datang_selesai;type:time" json:"jam_datang_selesai"`
+	JamPulangMulai        *string `gorm:"column:jam_pulang_mulai;type:time" json:"jam_pulang_mulai"`
+	JamPulangSelesai      *string `gorm:"column:jam_pulang_selesai;type:time" json:"jam_pulang_selesai"`
+}
+
+// TableName specifies the table name for ProfilJadwalAbsensiHari
+func (m *ProfilJadwalAbsensiHari) TableName() string {
+	return "profil_jadwal_absensi_hari"
+}
+
+// PenugasanJadwalAbsensi assigns a profile to all students (no kelas/rombel), a kelas or a rombel.
+// A date range makes it a special schedule for those dates only.
+type PenugasanJadwalAbsensi struct {
+	ID                    uint       `gorm:"primaryKey" json:"id"`
+	ProfilJadwalAbsensiID uint       `gorm:"column:profil_jadwal_absensi_id;not null" json:"profil_jadwal_absensi_id"`
+	KelasID               *uint      `gorm:"column:kelas_id" json:"kelas_id"`
+	RombelID              *uint      `gorm:"column:rombel_id" json:"rombel_id"`
+	TanggalMulai          *time.Time `gorm:"column:tanggal_mulai;type:date" json:"tanggal_mulai"`
+	TanggalSelesai        *time.Time `gorm:"column:tanggal_selesai;type:date" json:"tanggal_selesai"`
+	Keterangan            *string    `gorm:"column:keterangan;type:text" json:"keterangan"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+	CreatedByID           *uint      `json:"created_by_id"`
+	CreatedByType         *string    `json:"created_by_type"`
+	UpdatedByID           *uint      `json:"updated_by_id"`
+	UpdatedByType         *string    `json:"updated_by_type"`
+
+	// Relationships
+	Profil *ProfilJadwalAbsensi `gorm:"foreignKey:ProfilJadwalAbsensiID" json:"profil,omitempty"`
+	Kelas  *Kelas               `gorm:"foreignKey:KelasID" json:"kelas,omitempty"`
+	Rombel *Rombel              `gorm:"foreignKey:RombelID" json:"rombel,omitempty"`
+}
+
+// TableName specifies the table name for PenugasanJadwalAbsensi
+func (m *PenugasanJadwalAbsensi) TableName() string {
+	return "penugasan_jadwal_absensi"
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -10,11 +11,14 @@ import (
 type Jumbotron struct {
 	ID          uint            `gorm:"primaryKey" json:"id"`
 	File        string          `gorm:"not null" json:"file"`
+	FileVariants  datatypes.JSON  `gorm:"type:jsonb" json:"file_variants"`
 	Status      string          `gorm:"default:active" json:"status"`
 	CreatedAt   time.Time       `json:"created_at"`
 	UpdatedAt   time.Time       `json:"updated_at"`
 	CreatedByID *uint           `json:"created_by_id"`
+	CreatedByType *string         `json:"created_by_type"`
 	UpdatedByID *uint           `json:"updated_by_id"`
+	UpdatedByType *string         `json:"updated_by_type"`
 	DeletedAt   gorm.DeletedAt  `gorm:"index" json:"deleted_at,omitempty"`
 }
 
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// KalenderAkademik represents a school calendar entry: a holiday or a school event
+type KalenderAkademik struct {
+	ID               uint           `gorm:"primaryKey" json:"id"`
+	TahunPelajaranID *uint          `json:"tahun_pelajaran_id"`
+	Jenis            string         `gorm:"type:varchar(30);not null" json:"jenis"` // libur_nasional, libur_sekolah, kegiatan_sekolah
+	Nama             string         `gorm:"type:varchar(255);not null" json:"nama"`
+	TanggalMulai     time.Time      `gorm:"type:date;not null" json:"tanggal_mulai"`
+	TanggalSelesai   time.Time      `gorm:"type:date;not null" json:"tanggal_selesai"`
+	Semester         *int           `json:"semester"`
+	Libur            bool           `gorm:"not null;default:false" json:"libur"` // true = no school on these dates
+	Keterangan       *string        `gorm:"type:text" json:"keterangan"`
+	Sumber           string         `gorm:"type:varchar(20);not null;default:manual" json:"sumber"` // manual, excel, ical
+	ExternalUID      *string        `gorm:"column:external_uid;type:varchar(255)" json:"external_uid"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	CreatedByID      *uint          `json:"created_by_id"`
+	CreatedByType    *string        `json:"created_by_type"`
+	UpdatedByID      *uint          `json:"updated_by_id"`
+	UpdatedByType    *string        `json:"updated_by_type"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Relationships
+	TahunPelajaran *TahunPelajaran `gorm:"foreignKey:TahunPelajaranID" json:"tahun_pelajaran,omitempty"`
+}
+
+// TableName specifies the table name for KalenderAkademik
+func (m *KalenderAkademik) TableName() string {
+	return "kalender_akademik"
+}
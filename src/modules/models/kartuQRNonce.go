@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// KartuQRNonce records the nonce of a signed kartu pelajar QR that has been scanned, so it cannot be replayed
+type KartuQRNonce struct {
+	Nonce          string    `gorm:"column:nonce;primaryKey;size:64" json:"nonce"`
+	PesertaDidikID uint      `gorm:"column:peserta_didik_id;not null" json:"peserta_didik_id"`
+	UsedAt         time.Time `gorm:"column:used_at;not null" json:"used_at"`
+	ExpiresAt      time.Time `gorm:"column:expires_at;not null" json:"expires_at"`
+}
+
+// TableName specifies the table name for KartuQRNonce
+func (m *KartuQRNonce) TableName() string {
+	return "kartu_qr_nonce"
+}
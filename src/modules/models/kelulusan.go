@@ -22,12 +22,14 @@ type Kelulusan struct {
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
 	CreatedByID   *uint          `json:"created_by_id"`
+	CreatedByType *string        `json:"created_by_type"`
 	UpdatedByID   *uint          `json:"updated_by_id"`
+	UpdatedByType *string        `json:"updated_by_type"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
-	
-	// Foreign key relationships
-	CreatedBy *User `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
-	UpdatedBy *User `gorm:"foreignKey:UpdatedByID" json:"updated_by,omitempty"`
+
+	// Audit principals, resolved by their principal type
+	CreatedBy *PrincipalRef `gorm:"-" json:"created_by,omitempty"`
+	UpdatedBy *PrincipalRef `gorm:"-" json:"updated_by,omitempty"`
 }
 
 // TableName specifies the table name for Kelulusan
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+)
+
+// Kenaikan kelas status
+const (
+	KenaikanKelasStatusDiproses   = "diproses"
+	KenaikanKelasStatusDibatalkan = "dibatalkan"
+)
+
+// Kenaikan kelas decision per student
+const (
+	KeputusanNaik         = "naik"
+	KeputusanTinggalKelas = "tinggal_kelas"
+	KeputusanLulus        = "lulus"
+)
+
+// KenaikanKelas is one processed academic year rollover from a source to a target tahun pelajaran
+type KenaikanKelas struct {
+	ID                              uint       `gorm:"primaryKey" json:"id"`
+	TahunPelajaranAsalID            uint       `gorm:"column:tahun_pelajaran_asal_id;not null" json:"tahun_pelajaran_asal_id"`
+	TahunPelajaranTujuanID          uint       `gorm:"column:tahun_pelajaran_tujuan_id;not null" json:"tahun_pelajaran_tujuan_id"`
+	TahunPelajaranAktifSebelumnyaID *uint      `gorm:"column:tahun_pelajaran_aktif_sebelumnya_id" json:"tahun_pelajaran_aktif_sebelumnya_id"` // Restored on undo
+	Status                          string     `gorm:"column:status;not null;default:diproses" json:"status"`
+	TotalNaik                       int        `gorm:"column:total_naik;not null;default:0" json:"total_naik"`
+	TotalTinggalKelas               int        `gorm:"column:total_tinggal_kelas;not null;default:0" json:"total_tinggal_kelas"`
+	TotalLulus                      int        `gorm:"column:total_lulus;not null;default:0" json:"total_lulus"`
+	DiprosesAt                      time.Time  `gorm:"column:diproses_at;not null" json:"diproses_at"`
+	DibatalkanAt                    *time.Time `gorm:"column:dibatalkan_at" json:"dibatalkan_at"`
+	CreatedAt                       time.Time  `json:"created_at"`
+	UpdatedAt                       time.Time  `json:"updated_at"`
+	CreatedByID                     *uint      `json:"created_by_id"`
+	CreatedByType                   *string    `json:"created_by_type"`
+	UpdatedByID                     *uint      `json:"updated_by_id"`
+	UpdatedByType                   *string    `json:"updated_by_type"`
+
+	// Relationships
+	TahunPelajaranAsal   *TahunPelajaran       `gorm:"foreignKey:TahunPelajaranAsalID" json:"tahun_pelajaran_asal,omitempty"`
+	TahunPelajaranTujuan *TahunPelajaran       `gorm:"foreignKey:TahunPelajaranTujuanID" json:"tahun_pelajaran_tujuan,omitempty"`
+	Detail               []KenaikanKelasDetail `gorm:"foreignKey:KenaikanKelasID" json:"detail,omitempty"`
+}
+
+// TableName specifies the table name for KenaikanKelas
+func (m *KenaikanKelas) TableName() string {
+	return "kenaikan_kelas"
+}
+
+// KenaikanKelasDetail records what a rollover did to one student, enough to undo it
+type KenaikanKelasDetail struct {
+	ID                           uint   `gorm:"primaryKey" json:"id"`
+	KenaikanKelasID              uint   `gorm:"column:kenaikan_kelas_id;not null" json:"kenaikan_kelas_id"`
+	PesertaDidikID               uint   `gorm:"column:peserta_didik_id;not null" json:"peserta_didik_id"`
+	PesertaDidikRombelAsalID     uint   `gorm:"column:peserta_didik_rombel_asal_id;not null" json:"peserta_didik_rombel_asal_id"`
+	RombelAsalID                 uint   `gorm:"column:rombel_asal_id;not null" json:"rombel_asal_id"`
+	Keputusan                    string `gorm:"column:keputusan;not null" json:"keputusan"`
+	RombelTujuanID               *uint  `gorm:"column:rombel_tujuan_id" json:"rombel_tujuan_id"`                             // Nil for lulus
+	PesertaDidikRombelTujuanID   *uint  `gorm:"column:peserta_didik_rombel_tujuan_id" json:"peserta_didik_rombel_tujuan_id"` // Row created by the rollover
+	StatusPesertaDidikSebelumnya string `gorm:"column:status_peserta_didik_sebelumnya;not null" json:"status_peserta_didik_sebelumnya"`
+}
+
+// TableName specifies the table name for KenaikanKelasDetail
+func (m *KenaikanKelasDetail) TableName() string {
+	return "kenaikan_kelas_detail"
+}
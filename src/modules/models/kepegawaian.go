@@ -34,11 +34,17 @@ type Kepegawaian struct {
 	SertifikatLainnya     datatypes.JSON `gorm:"column:sertifikat_lainnya;type:jsonb;default:'[]'" json:"sertifikat_lainnya"`
 	SK                    string         `gorm:"column:sk" json:"sk"`
 	DokumenLainnya        datatypes.JSON `gorm:"column:dokumen_lainnya;type:jsonb;default:'[]'" json:"dokumen_lainnya"`
+	Barcode               string         `gorm:"column:barcode" json:"barcode,omitempty"`
+	BarcodeGeneratedAt    *time.Time     `gorm:"column:barcode_generated_at" json:"barcode_generated_at,omitempty"`
 	Status                string         `gorm:"column:status;default:active" json:"status"`
+	FailedLoginAttempts   int            `gorm:"column:failed_login_attempts;default:0" json:"failed_login_attempts"`
+	LockedUntil           *time.Time     `gorm:"column:locked_until" json:"locked_until"`
 	CreatedAt             time.Time      `gorm:"column:created_at" json:"created_at"`
 	UpdatedAt             time.Time      `gorm:"column:updated_at" json:"updated_at"`
 	CreatedByID           *uint          `gorm:"column:created_by_id" json:"created_by_id"`
+	CreatedByType         *string        `gorm:"column:created_by_type" json:"created_by_type"`
 	UpdatedByID           *uint          `gorm:"column:updated_by_id" json:"updated_by_id"`
+	UpdatedByType         *string        `gorm:"column:updated_by_type" json:"updated_by_type"`
 	DeletedAt             gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deleted_at,omitempty"`
 	// Foreign key relationships
 	BidangStudi           *BidangStudi   `gorm:"foreignKey:BidangStudiID" json:"bidang_studi,omitempty"`
@@ -4,6 +4,13 @@ import (
 	"time"
 )
 
+// Kartu pelajar scan modes: the printed static barcode, the rotating signed QR from the portal siswa, or both
+const (
+	ModeKartuQRStatis   = "statis"
+	ModeKartuQRCampuran = "campuran"
+	ModeKartuQRDinamis  = "dinamis"
+)
+
 // KonfigurasiAbsensi represents the Konfigurasi Absensi model
 type KonfigurasiAbsensi struct {
 	ID                uint      `gorm:"primaryKey" json:"id"`
@@ -14,6 +21,8 @@ type KonfigurasiAbsensi struct {
 	JamPulangSelesai  string    `gorm:"column:jam_pulang_selesai;type:time;not null" json:"jam_pulang_selesai"`
 	NamaKepsek        *string   `gorm:"column:nama_kepsek;size:200" json:"nama_kepsek"`
 	NIPKepsek         *string   `gorm:"column:nip_kepsek;size:50" json:"nip_kepsek"`
+	HariSekolah       string    `gorm:"column:hari_sekolah;size:20;default:'1,2,3,4,5'" json:"hari_sekolah"` // ISO weekdays, 1 = Senin ... 7 = Minggu
+	ModeKartuQR       string    `gorm:"column:mode_kartu_qr;size:20;default:statis" json:"mode_kartu_qr"`    // statis, campuran, dinamis
 	CreatedAt         time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt         time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
 }
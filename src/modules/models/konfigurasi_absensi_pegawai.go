@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// KonfigurasiAbsensiPegawai holds the scan time windows of guru and tendik, separate from the student windows
+type KonfigurasiAbsensiPegawai struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	JamDatangMulai   string    `gorm:"column:jam_datang_mulai;type:time;not null" json:"jam_datang_mulai"`
+	JamMaxDatang     string    `gorm:"column:jam_max_datang;type:time;not null" json:"jam_max_datang"`
+	JamDatangSelesai string    `gorm:"column:jam_datang_selesai;type:time;not null" json:"jam_datang_selesai"`
+	JamPulangMulai   string    `gorm:"column:jam_pulang_mulai;type:time;not null" json:"jam_pulang_mulai"`
+	JamPulangSelesai string    `gorm:"column:jam_pulang_selesai;type:time;not null" json:"jam_pulang_selesai"`
+	CreatedAt        time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt        time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// TableName specifies the table name for KonfigurasiAbsensiPegawai
+func (m *KonfigurasiAbsensiPegawai) TableName() string {
+	return "konfigurasi_absensi_pegawai"
+}
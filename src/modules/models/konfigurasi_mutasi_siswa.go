@@ -20,7 +20,9 @@ type KonfigurasiMutasiSiswa struct {
 	CreatedAt               time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt               time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
 	CreatedByID             *uint          `json:"created_by_id"`
+	CreatedByType           *string        `json:"created_by_type"`
 	UpdatedByID             *uint          `json:"updated_by_id"`
+	UpdatedByType           *string        `json:"updated_by_type"`
 	DeletedAt               gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
 
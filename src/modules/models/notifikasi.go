@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+)
+
+// Notifikasi events and channels
+const (
+	NotifikasiEventTerlambat = "terlambat"
+	NotifikasiEventAlpa      = "alpa"
+
+	NotifikasiChannelEmail    = "email"
+	NotifikasiChannelWhatsApp = "whatsapp"
+
+	NotifikasiStatusPending = "pending"
+	NotifikasiStatusSent    = "sent"
+	NotifikasiStatusFailed  = "failed"
+)
+
+// NotifikasiTemplate is the text/template used to render one event for one channel
+type NotifikasiTemplate struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	Event         string    `gorm:"type:varchar(30);not null" json:"event"`
+	Channel       string    `gorm:"type:varchar(20);not null" json:"channel"`
+	Subject       *string   `gorm:"type:varchar(255)" json:"subject"`
+	Body          string    `gorm:"type:text;not null" json:"body"`
+	Status        string    `gorm:"type:varchar(20);default:active" json:"status"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	CreatedByID   *uint     `json:"created_by_id"`
+	CreatedByType *string   `json:"created_by_type"`
+	UpdatedByID   *uint     `json:"updated_by_id"`
+	UpdatedByType *string   `json:"updated_by_type"`
+}
+
+// TableName specifies the table name for NotifikasiTemplate
+func (m *NotifikasiTemplate) TableName() string {
+	return "notifikasi_template"
+}
+
+// NotifikasiOutbox is a rendered message waiting to be delivered, or the record of its delivery
+type NotifikasiOutbox struct {
+	ID             uint          `gorm:"primaryKey" json:"id"`
+	Event          string        `gorm:"type:varchar(30);not null" json:"event"`
+	Channel        string        `gorm:"type:varchar(20);not null" json:"channel"`
+	PesertaDidikID *uint         `json:"peserta_didik_id"`
+	PesertaDidik   *PesertaDidik `gorm:"foreignKey:PesertaDidikID" json:"peserta_didik,omitempty"`
+	Recipient      string        `gorm:"type:varchar(255);not null" json:"recipient"`
+	Subject        *string       `gorm:"type:varchar(255)" json:"subject"`
+	Body           string        `gorm:"type:text;not null" json:"body"`
+	DedupeKey      string        `gorm:"type:varchar(150);uniqueIndex;not null" json:"dedupe_key"`
+	Status         string        `gorm:"type:varchar(20);default:pending" json:"status"`
+	Attempts       int           `gorm:"default:0" json:"attempts"`
+	NextAttemptAt  time.Time     `json:"next_attempt_at"`
+	LastError      *string       `gorm:"type:text" json:"last_error"`
+	SentAt         *time.Time    `json:"sent_at"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+// TableName specifies the table name for NotifikasiOutbox
+func (m *NotifikasiOutbox) TableName() string {
+	return "notifikasi_outbox"
+}
@@ -31,9 +31,11 @@ type Pengaduan struct {
 	TanggalSelesai     *time.Time     `json:"tanggal_selesai"`
 	Status             string         `gorm:"size:50;default:'pending'" json:"status"`
 	RepliedBy          *uint          `json:"replied_by"`
+	RepliedByType      *string        `json:"replied_by_type"`
 	CreatedAt          time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
 	DeletedAt          gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 	DeletedByID        *uint          `json:"deleted_by_id"`
+	DeletedByType      *string        `json:"deleted_by_type"`
 }
 
 // TableName specifies the table name for Pengaduan
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Pengajuan izin statuses and submission sources
+const (
+	PengajuanIzinStatusPending  = "pending"
+	PengajuanIzinStatusApproved = "approved"
+	PengajuanIzinStatusRejected = "rejected"
+
+	PengajuanIzinMelaluiPublik = "publik"
+	PengajuanIzinMelaluiSiswa  = "siswa"
+)
+
+// PengajuanIzin is an izin/sakit leave request for a date range, waiting for the wali kelas
+type PengajuanIzin struct {
+	ID                   uint           `gorm:"primaryKey" json:"id"`
+	IDTiket              string         `gorm:"type:varchar(30);unique;not null" json:"id_tiket"`
+	PesertaDidikID       uint           `gorm:"not null" json:"peserta_didik_id"`
+	PesertaDidikRombelID uint           `gorm:"not null" json:"peserta_didik_rombel_id"`
+	RombelID             uint           `gorm:"not null" json:"rombel_id"`
+	TahunPelajaranID     uint           `gorm:"not null" json:"tahun_pelajaran_id"`
+	Jenis                string         `gorm:"type:varchar(20);not null" json:"jenis"` // izin, sakit
+	TanggalMulai         time.Time      `gorm:"type:date;not null" json:"tanggal_mulai"`
+	TanggalSelesai       time.Time      `gorm:"type:date;not null" json:"tanggal_selesai"`
+	Alasan               string         `gorm:"type:text;not null" json:"alasan"`
+	FileSurat            string         `gorm:"type:text" json:"file_surat"`
+	NamaPengaju          string         `gorm:"type:varchar(255);not null" json:"nama_pengaju"`
+	TeleponPengaju       string         `gorm:"type:varchar(20)" json:"telepon_pengaju"`
+	DiajukanMelalui      string         `gorm:"type:varchar(20);not null" json:"diajukan_melalui"`
+	Status               string         `gorm:"type:varchar(20);default:pending" json:"status"`
+	CatatanReview        *string        `gorm:"type:text" json:"catatan_review"`
+	JumlahHariTercatat   int            `gorm:"default:0" json:"jumlah_hari_tercatat"` // Rekap rows written on approval
+	ReviewedByID         *uint          `json:"reviewed_by_id"`
+	ReviewedByType       *string        `json:"reviewed_by_type"`
+	ReviewedAt           *time.Time     `json:"reviewed_at"`
+	CreatedAt            time.Time      `json:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at"`
+	DeletedAt            gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Relationships
+	PesertaDidik *PesertaDidik `gorm:"foreignKey:PesertaDidikID" json:"peserta_didik,omitempty"`
+	Rombel       *Rombel       `gorm:"foreignKey:RombelID" json:"rombel,omitempty"`
+}
+
+// TableName specifies the table name for PengajuanIzin
+func (m *PengajuanIzin) TableName() string {
+	return "pengajuan_izin"
+}
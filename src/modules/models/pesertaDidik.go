@@ -25,16 +25,23 @@ type PesertaDidik struct {
 	KodePos            string         `json:"kode_pos"`
 	NamaAyah           string         `json:"nama_ayah"`
 	NamaIbu            string         `json:"nama_ibu"`
+	NomorHPOrtu        string         `gorm:"column:nomor_hp_ortu" json:"nomor_hp_ortu"`
+	EmailOrtu          string         `json:"email_ortu"`
+	NotifikasiOrtu     bool           `gorm:"default:false" json:"notifikasi_ortu"` // Parent opted in to attendance notifications
 	Status             string         `gorm:"default:active" json:"status"`
 	Username           string         `json:"username"`
 	Password           string         `json:"password,omitempty"`
+	FailedLoginAttempts int           `gorm:"default:0" json:"failed_login_attempts"`
+	LockedUntil        *time.Time     `json:"locked_until"`
 	Photo              string         `json:"photo,omitempty"`
 	Barcode            string         `json:"barcode,omitempty"`
 	BarcodeGeneratedAt *time.Time     `json:"barcode_generated_at,omitempty"`
 	CreatedAt          time.Time      `json:"created_at"`
 	UpdatedAt          time.Time      `json:"updated_at"`
 	CreatedByID        *uint          `json:"created_by_id"`
+	CreatedByType      *string        `json:"created_by_type"`
 	UpdatedByID        *uint          `json:"updated_by_id"`
+	UpdatedByType      *string        `json:"updated_by_type"`
 	DeletedAt          gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 	// Many-to-many relationship
 	Roles              []Role         `gorm:"many2many:peserta_didik_roles" json:"roles,omitempty"`
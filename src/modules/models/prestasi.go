@@ -11,11 +11,14 @@ import (
 
 // FotoItem represents a single photo in the foto array
 type FotoItem struct {
-	ID        string `json:"id"`
-	Filename  string `json:"filename"`
-	URL       string `json:"url"`
-	Size      int64  `json:"size"`
-	Thumbnail string `json:"thumbnail"` // "active" or "inactive"
+	ID        string                 `json:"id"`
+	Filename  string                 `json:"filename"`
+	URL       string                 `json:"url"`
+	Size      int64                  `json:"size"`
+	Thumbnail string                 `json:"thumbnail"` // "active" or "inactive"
+	Width     int                    `json:"width,omitempty"`
+	Height    int                    `json:"height,omitempty"`
+	Variants  map[string]FileVariant `json:"variants,omitempty"` // "thumbnail", "medium" and "original"
 }
 
 // AnggotaTimPrestasi represents the anggota tim prestasi model
@@ -27,7 +30,9 @@ type AnggotaTimPrestasi struct {
 	UpdatedAt            time.Time      `json:"updated_at"`
 	DeletedAt            gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 	CreatedByID          *uint          `json:"created_by_id"`
+	CreatedByType        *string        `json:"created_by_type"`
 	UpdatedByID          *uint          `json:"updated_by_id"`
+	UpdatedByType        *string        `json:"updated_by_type"`
 	// Foreign key relationships
 	Prestasi             *Prestasi            `gorm:"foreignKey:PrestasiID" json:"prestasi,omitempty"`
 	PesertaDidikRombel   *PesertaDidikRombel  `gorm:"foreignKey:PesertaDidikRombelID" json:"peserta_didik_rombel,omitempty"`
@@ -53,7 +58,9 @@ type Prestasi struct {
 	UpdatedAt             time.Time            `json:"updated_at"`
 	DeletedAt             gorm.DeletedAt       `gorm:"index" json:"deleted_at,omitempty"`
 	CreatedByID           *uint                `json:"created_by_id"`
+	CreatedByType         *string              `json:"created_by_type"`
 	UpdatedByID           *uint                `json:"updated_by_id"`
+	UpdatedByType         *string              `json:"updated_by_type"`
 	// Foreign key relationships
 	PesertaDidikRombel    *PesertaDidikRombel  `gorm:"foreignKey:PesertaDidikRombelID" json:"peserta_didik_rombel,omitempty"`
 	Ekstrakurikuler       *Ekstrakurikuler     `gorm:"foreignKey:EkstrakurikulerID" json:"ekstrakurikuler,omitempty"`
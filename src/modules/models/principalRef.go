@@ -0,0 +1,9 @@
+package models
+
+// PrincipalRef names the user, pegawai or siswa an audit column points to. It is resolved from the
+// *_by_id and *_by_type columns, not a gorm association, because the ID alone does not name the table.
+type PrincipalRef struct {
+	ID   uint   `json:"id"`
+	Type string `json:"type"`
+	Nama string `json:"nama"`
+}
@@ -6,6 +6,13 @@ import (
 	"gorm.io/gorm"
 )
 
+// MetodeInputAlpaOtomatis marks rekap rows written by the automatic alpa job, so they can be told
+// apart from alpa entered by a guru and replaced once a scan or manual entry turns up
+const MetodeInputAlpaOtomatis = "alpa_otomatis"
+
+// MetodeInputPengajuan marks rekap rows written when the wali kelas approves a pengajuan izin/sakit
+const MetodeInputPengajuan = "pengajuan"
+
 // RekapitulasiAbsensi represents the Rekapitulasi Absensi model for teacher attendance recording
 type RekapitulasiAbsensi struct {
 	ID                   uint           `gorm:"primaryKey" json:"id"`
@@ -22,6 +29,7 @@ type RekapitulasiAbsensi struct {
 	Keterangan           string         `gorm:"column:keterangan;type:text" json:"keterangan"`
 	FileSurat            string         `gorm:"column:file_surat;type:text" json:"file_surat"`
 	DicatatOlehID        *uint          `gorm:"column:dicatat_oleh_id" json:"dicatat_oleh_id"`
+	DicatatOlehType      *string        `gorm:"column:dicatat_oleh_type" json:"dicatat_oleh_type"`
 	CreatedAt            time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt            time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
 	DeletedAt            gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
@@ -32,6 +40,7 @@ type RekapitulasiAbsensi struct {
 	TahunPelajaran       *TahunPelajaran     `gorm:"foreignKey:TahunPelajaranID" json:"tahun_pelajaran,omitempty"`
 	BidangStudi          *BidangStudi        `gorm:"foreignKey:BidangStudiID" json:"bidang_studi,omitempty"`
 	DicatatOleh          *User               `gorm:"foreignKey:DicatatOlehID" json:"dicatat_oleh,omitempty"`
+	DicatatOlehPegawai   *Kepegawaian        `gorm:"foreignKey:DicatatOlehID" json:"dicatat_oleh_pegawai,omitempty"`
 }
 
 // TableName specifies the table name for RekapitulasiAbsensi
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+)
+
+// Riwayat rekap absensi actions
+const (
+	AksiRiwayatRekapCreate = "create"
+	AksiRiwayatRekapUpdate = "update"
+	AksiRiwayatRekapDelete = "delete"
+)
+
+// Riwayat rekap absensi sources, the code path that changed the rekap row
+const (
+	SumberRiwayatRekapManual       = "manual"
+	SumberRiwayatRekapSync         = "sync"
+	SumberRiwayatRekapImport       = "import"
+	SumberRiwayatRekapPengajuan    = "pengajuan"
+	SumberRiwayatRekapAlpaOtomatis = "alpa_otomatis"
+)
+
+// RiwayatRekapitulasiAbsensi is one append-only history entry of a rekapitulasi_absensi mutation
+type RiwayatRekapitulasiAbsensi struct {
+	ID                    uint       `gorm:"primaryKey" json:"id"`
+	RekapitulasiAbsensiID uint       `gorm:"column:rekapitulasi_absensi_id;not null" json:"rekapitulasi_absensi_id"`
+	PesertaDidikRombelID  uint       `gorm:"column:peserta_didik_rombel_id;not null" json:"peserta_didik_rombel_id"`
+	Tanggal               time.Time  `gorm:"column:tanggal;type:date;not null" json:"tanggal"`
+	BidangStudiID         *uint      `gorm:"column:bidang_studi_id" json:"bidang_studi_id"`
+	Aksi                  string     `gorm:"column:aksi;size:20;not null" json:"aksi"`
+	Sumber                string     `gorm:"column:sumber;size:20;not null" json:"sumber"`
+	StatusSebelum         *string    `gorm:"column:status_sebelum" json:"status_sebelum"`
+	StatusSesudah         *string    `gorm:"column:status_sesudah" json:"status_sesudah"`
+	MetodeInputSebelum    *string    `gorm:"column:metode_input_sebelum" json:"metode_input_sebelum"`
+	MetodeInputSesudah    *string    `gorm:"column:metode_input_sesudah" json:"metode_input_sesudah"`
+	KeteranganSebelum     *string    `gorm:"column:keterangan_sebelum" json:"keterangan_sebelum"`
+	KeteranganSesudah     *string    `gorm:"column:keterangan_sesudah" json:"keterangan_sesudah"`
+	FileSuratSebelum      *string    `gorm:"column:file_surat_sebelum" json:"file_surat_sebelum"`
+	FileSuratSesudah      *string    `gorm:"column:file_surat_sesudah" json:"file_surat_sesudah"`
+	WaktuAbsenSebelum     *time.Time `gorm:"column:waktu_absen_sebelum" json:"waktu_absen_sebelum"`
+	WaktuAbsenSesudah     *time.Time `gorm:"column:waktu_absen_sesudah" json:"waktu_absen_sesudah"`
+	ActorID               *uint      `gorm:"column:actor_id" json:"actor_id"` // Nil for the alpa job
+	ActorType             *string    `gorm:"column:actor_type" json:"actor_type"`
+	ActorNama             string     `gorm:"column:actor_nama" json:"actor_nama"`
+	IPAddress             string     `gorm:"column:ip_address" json:"ip_address"`
+	CreatedAt             time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TableName specifies the table name for RiwayatRekapitulasiAbsensi
+func (m *RiwayatRekapitulasiAbsensi) TableName() string {
+	return "riwayat_rekapitulasi_absensi"
+}
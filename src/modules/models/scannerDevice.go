@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ScannerDevice represents a registered attendance scanner allowed to call the public scan endpoint
+type ScannerDevice struct {
+	ID              uint           `gorm:"primaryKey" json:"id"`
+	Nama            string         `gorm:"type:varchar(100);not null" json:"nama"`
+	Lokasi          *string        `gorm:"type:varchar(255)" json:"lokasi"`
+	AuthMode        string         `gorm:"type:varchar(20);not null;default:hmac" json:"auth_mode"`
+	KeyHash         *string        `gorm:"type:varchar(64)" json:"-"`
+	HMACSecret      *string        `gorm:"column:hmac_secret;type:varchar(255)" json:"-"`
+	AllowedCIDRs    datatypes.JSON `gorm:"column:allowed_cidrs;type:jsonb;default:'[]'" json:"allowed_cidrs"`
+	JamAktifMulai   *string        `gorm:"type:time" json:"jam_aktif_mulai"`
+	JamAktifSelesai *string        `gorm:"type:time" json:"jam_aktif_selesai"`
+	Status          string         `gorm:"type:varchar(20);default:active" json:"status"`
+	LastSeenAt      *time.Time     `json:"last_seen_at"`
+	LastSeenIP      *string        `gorm:"column:last_seen_ip;type:varchar(45)" json:"last_seen_ip"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	CreatedByID     *uint          `json:"created_by_id"`
+	CreatedByType   *string        `json:"created_by_type"`
+	UpdatedByID     *uint          `json:"updated_by_id"`
+	UpdatedByType   *string        `json:"updated_by_type"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+// TableName specifies the table name for ScannerDevice
+func (m *ScannerDevice) TableName() string {
+	return "scanner_devices"
+}
@@ -18,12 +18,14 @@ type StrukturOrganisasi struct {
 	CreatedAt          time.Time      `gorm:"column:created_at" json:"created_at"`
 	UpdatedAt          time.Time      `gorm:"column:updated_at" json:"updated_at"`
 	CreatedByID        *uint          `gorm:"column:created_by_id" json:"created_by_id"`
+	CreatedByType      *string        `gorm:"column:created_by_type" json:"created_by_type"`
 	UpdatedByID        *uint          `gorm:"column:updated_by_id" json:"updated_by_id"`
+	UpdatedByType      *string        `gorm:"column:updated_by_type" json:"updated_by_type"`
 	DeletedAt          gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deleted_at,omitempty"`
 	// Foreign key relationships
 	Pegawai            *Kepegawaian   `gorm:"foreignKey:PegawaiID" json:"pegawai,omitempty"`
-	CreatedBy          *User          `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
-	UpdatedBy          *User          `gorm:"foreignKey:UpdatedByID" json:"updated_by,omitempty"`
+	CreatedBy          *PrincipalRef  `gorm:"-" json:"created_by,omitempty"` // Resolved by principal type
+	UpdatedBy          *PrincipalRef  `gorm:"-" json:"updated_by,omitempty"`
 }
 
 // TableName specifies the table name for StrukturOrganisasi
@@ -10,12 +10,15 @@ import (
 type System struct {
 	ID          uint            `gorm:"primaryKey" json:"id"`
 	Nama        string          `gorm:"not null" json:"nama"`
+	Code        string          `gorm:"uniqueIndex;not null" json:"code"`
 	Description string          `json:"description"`
 	Status      string          `gorm:"default:active" json:"status"`
 	CreatedAt   time.Time       `json:"created_at"`
 	UpdatedAt   time.Time       `json:"updated_at"`
 	CreatedByID *uint           `json:"created_by_id"`
+	CreatedByType *string         `json:"created_by_type"`
 	UpdatedByID *uint           `json:"updated_by_id"`
+	UpdatedByType *string         `json:"updated_by_type"`
 	DeletedAt   gorm.DeletedAt  `gorm:"index" json:"deleted_at,omitempty"`
 }
 
@@ -11,10 +11,16 @@ type TahunPelajaran struct {
 	ID              uint            `gorm:"primaryKey" json:"id"`
 	TahunPelajaran  string          `gorm:"uniqueIndex;not null" json:"tahun_pelajaran"`
 	Status          string          `gorm:"default:active" json:"status"`
+	Semester1Mulai   *time.Time     `gorm:"column:semester1_mulai;type:date" json:"semester1_mulai"`
+	Semester1Selesai *time.Time     `gorm:"column:semester1_selesai;type:date" json:"semester1_selesai"`
+	Semester2Mulai   *time.Time     `gorm:"column:semester2_mulai;type:date" json:"semester2_mulai"`
+	Semester2Selesai *time.Time     `gorm:"column:semester2_selesai;type:date" json:"semester2_selesai"`
 	CreatedAt       time.Time       `json:"created_at"`
 	UpdatedAt       time.Time       `json:"updated_at"`
 	CreatedByID     *uint           `json:"created_by_id"`
+	CreatedByType   *string         `json:"created_by_type"`
 	UpdatedByID     *uint           `json:"updated_by_id"`
+	UpdatedByType   *string         `json:"updated_by_type"`
 	DeletedAt       gorm.DeletedAt  `gorm:"index" json:"deleted_at,omitempty"`
 }
 
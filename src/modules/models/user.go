@@ -13,10 +13,14 @@ type User struct {
 	Username    string         `gorm:"uniqueIndex;not null" json:"username"`
 	Password    string         `gorm:"not null" json:"-"`
 	Status      string         `gorm:"default:active" json:"status"`
+	FailedLoginAttempts int        `gorm:"default:0" json:"failed_login_attempts"`
+	LockedUntil *time.Time     `json:"locked_until"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	CreatedByID *uint          `json:"created_by_id"`
+	CreatedByType *string        `json:"created_by_type"`
 	UpdatedByID *uint          `json:"updated_by_id"`
+	UpdatedByType *string        `json:"updated_by_type"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 	// Many-to-many relationship
 	Roles       []Role         `gorm:"many2many:user_roles" json:"roles,omitempty"`
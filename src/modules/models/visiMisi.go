@@ -6,13 +6,15 @@ import (
 
 // VisiMisi represents the Visi Misi model
 type VisiMisi struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	Visi        string    `gorm:"type:text;not null" json:"visi"`
-	Misi        string    `gorm:"type:text;not null" json:"misi"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	CreatedByID *uint     `json:"created_by_id"`
-	UpdatedByID *uint     `json:"updated_by_id"`
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	Visi          string    `gorm:"type:text;not null" json:"visi"`
+	Misi          string    `gorm:"type:text;not null" json:"misi"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	CreatedByID   *uint     `json:"created_by_id"`
+	CreatedByType *string   `json:"created_by_type"`
+	UpdatedByID   *uint     `json:"updated_by_id"`
+	UpdatedByType *string   `json:"updated_by_type"`
 }
 
 // TableName specifies the table name for VisiMisi
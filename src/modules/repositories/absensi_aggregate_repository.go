@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AbsensiAggregateFilter filters rekapitulasi_absensi rows for the dashboard aggregates
+type AbsensiAggregateFilter struct {
+	TahunPelajaranID uint
+	RombelID         *uint
+	Semester         *int
+	BidangStudiID    *uint // nil means guru kelas rows (bidang_studi_id IS NULL)
+	TanggalMulai     *time.Time
+	TanggalSelesai   *time.Time
+	ExcludeTanggal   []time.Time // e.g. non-school days from kalender akademik
+}
+
+// AbsensiStatusCount holds the status pivot of one group
+type AbsensiStatusCount struct {
+	Hadir int
+	Sakit int
+	Izin  int
+	Alpa  int
+}
+
+// Total returns the number of rows in the group
+func (c AbsensiStatusCount) Total() int {
+	return c.Hadir + c.Sakit + c.Izin + c.Alpa
+}
+
+// AbsensiPeriodeCount holds the status pivot of one day, week (starting Monday) or month
+type AbsensiPeriodeCount struct {
+	Periode time.Time
+	AbsensiStatusCount
+}
+
+// AbsensiRombelCount holds the status pivot and number of students of one rombel
+type AbsensiRombelCount struct {
+	RombelID   uint
+	RombelNama string
+	TotalSiswa int
+	AbsensiStatusCount
+}
+
+// AbsensiSiswaCount holds the status pivot of one peserta didik
+type AbsensiSiswaCount struct {
+	PesertaDidikID uint
+	NIS            string
+	Nama           string
+	AbsensiStatusCount
+}
+
+// periodeExpressions maps the supported periode to its grouping expression
+var periodeExpressions = map[string]string{
+	"day":   "ra.tanggal",
+	"week":  "date_trunc('week', ra.tanggal)::date",
+	"month": "date_trunc('month', ra.tanggal)::date",
+}
+
+const statusPivotSelect = "COUNT(*) FILTER (WHERE ra.status = 'hadir') AS hadir, " +
+	"COUNT(*) FILTER (WHERE ra.status = 'sakit') AS sakit, " +
+	"COUNT(*) FILTER (WHERE ra.status = 'izin') AS izin, " +
+	"COUNT(*) FILTER (WHERE ra.status = 'alpa') AS alpa"
+
+// aggregateQuery builds the filtered base query on rekapitulasi_absensi aliased as ra
+func (r *AbsensiRepositoryImpl) aggregateQuery(filter AbsensiAggregateFilter) *gorm.DB {
+	query := r.db.Table("rekapitulasi_absensi AS ra").
+		Where("ra.deleted_at IS NULL").
+		Where("ra.tahun_pelajaran_id = ?", filter.TahunPelajaranID)
+
+	if filter.RombelID != nil {
+		query = query.Where("ra.rombel_id = ?", *filter.RombelID)
+	}
+
+	if filter.Semester != nil {
+		query = query.Where("ra.semester = ?", *filter.Semester)
+	}
+
+	if filter.BidangStudiID == nil {
+		query = query.Where("ra.bidang_studi_id IS NULL")
+	} else {
+		query = query.Where("ra.bidang_studi_id = ?", *filter.BidangStudiID)
+	}
+
+	if filter.TanggalMulai != nil {
+		query = query.Where("ra.tanggal >= ?", filter.TanggalMulai.Format("2006-01-02"))
+	}
+	if filter.TanggalSelesai != nil {
+		query = query.Where("ra.tanggal <= ?", filter.TanggalSelesai.Format("2006-01-02"))
+	}
+
+	if len(filter.ExcludeTanggal) > 0 {
+		excluded := make([]string, 0, len(filter.ExcludeTanggal))
+		for _, tanggal := range filter.ExcludeTanggal {
+			excluded = append(excluded, tanggal.Format("2006-01-02"))
+		}
+		query = query.Where("ra.tanggal NOT IN ?", excluded)
+	}
+
+	return query
+}
+
+// CountStatusPerPeriode counts statuses grouped by day, week or month, ordered by periode
+func (r *AbsensiRepositoryImpl) CountStatusPerPeriode(filter AbsensiAggregateFilter, periode string) ([]AbsensiPeriodeCount, error) {
+	expression, ok := periodeExpressions[periode]
+	if !ok {
+		return nil, errors.New("periode tidak valid")
+	}
+
+	var data []AbsensiPeriodeCount
+	err := r.aggregateQuery(filter).
+		Select(expression + " AS periode, " + statusPivotSelect).
+		Group(expression).
+		Order("periode").
+		Scan(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// CountStatusPerRombel counts statuses and unique students grouped by rombel
+func (r *AbsensiRepositoryImpl) CountStatusPerRombel(filter AbsensiAggregateFilter) ([]AbsensiRombelCount, error) {
+	var data []AbsensiRombelCount
+	err := r.aggregateQuery(filter).
+		Select("ra.rombel_id, COALESCE(rb.name, '') AS rombel_nama, " +
+			"COUNT(DISTINCT prd.peserta_didik_id) AS total_siswa, " + statusPivotSelect).
+		Joins("LEFT JOIN rombel rb ON rb.id = ra.rombel_id AND rb.deleted_at IS NULL").
+		Joins("LEFT JOIN peserta_didik_rombel prd ON prd.id = ra.peserta_didik_rombel_id").
+		Where("ra.rombel_id IS NOT NULL").
+		Group("ra.rombel_id, rb.name").
+		Scan(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// CountStatusPerSiswa counts statuses grouped by peserta didik, lowest attendance first
+func (r *AbsensiRepositoryImpl) CountStatusPerSiswa(filter AbsensiAggregateFilter, limit int) ([]AbsensiSiswaCount, error) {
+	var data []AbsensiSiswaCount
+	query := r.aggregateQuery(filter).
+		Select("pd.id AS peserta_didik_id, pd.nis, pd.nama, " + statusPivotSelect).
+		Joins("JOIN peserta_didik_rombel prd ON prd.id = ra.peserta_didik_rombel_id").
+		Joins("JOIN peserta_didik pd ON pd.id = prd.peserta_didik_id AND pd.deleted_at IS NULL").
+		Group("pd.id, pd.nis, pd.nama").
+		Order("COUNT(*) FILTER (WHERE ra.status = 'hadir')::numeric / COUNT(*) ASC, pd.nama ASC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Scan(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
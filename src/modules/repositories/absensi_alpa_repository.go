@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"time"
+
+	"pintu-backend/src/modules/models"
+
+	"gorm.io/gorm"
+)
+
+// AbsensiAlpaRepository handles data operations for the automatic alpa marking job
+type AbsensiAlpaRepository interface {
+	GetActivePesertaDidikRombel(tahunPelajaranID uint) ([]models.PesertaDidikRombel, error)
+	GetTercatatPesertaDidikRombelIDs(tahunPelajaranID uint, tanggal time.Time) ([]uint, error)
+	GetScannedPesertaDidikIDs(tanggal time.Time) ([]uint, error)
+	GetPengajuanIzinPendingPesertaDidikIDs(tanggal time.Time) ([]uint, error)
+	CreateAlpaIfMissing(data *models.RekapitulasiAbsensi) (bool, error)
+}
+
+type AbsensiAlpaRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAbsensiAlpaRepository creates a new AbsensiAlpa repository
+func NewAbsensiAlpaRepository(db *gorm.DB) AbsensiAlpaRepository {
+	return &AbsensiAlpaRepositoryImpl{db: db}
+}
+
+// GetActivePesertaDidikRombel retrieves active rombel members whose peserta didik is active
+func (r *AbsensiAlpaRepositoryImpl) GetActivePesertaDidikRombel(tahunPelajaranID uint) ([]models.PesertaDidikRombel, error) {
+	var data []models.PesertaDidikRombel
+	if err := r.db.Preload("PesertaDidik").Preload("Rombel").
+		Joins("JOIN peserta_didik ON peserta_didik.id = peserta_didik_rombel.peserta_didik_id AND peserta_didik.deleted_at IS NULL").
+		Where("peserta_didik_rombel.tahun_pelajaran_id = ? AND peserta_didik_rombel.status = ?", tahunPelajaranID, "active").
+		Where("peserta_didik.status = ?", "active").
+		Order("peserta_didik_rombel.rombel_id ASC, peserta_didik_rombel.peserta_didik_id ASC").
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// GetTercatatPesertaDidikRombelIDs retrieves rombel members that already have a guru kelas rekap row on the date,
+// whatever the status (hadir, izin, sakit or alpa)
+func (r *AbsensiAlpaRepositoryImpl) GetTercatatPesertaDidikRombelIDs(tahunPelajaranID uint, tanggal time.Time) ([]uint, error) {
+	var ids []uint
+	if err := r.db.Model(&models.RekapitulasiAbsensi{}).
+		Where("tahun_pelajaran_id = ? AND tanggal = ? AND bidang_studi_id IS NULL", tahunPelajaranID, tanggal).
+		Pluck("peserta_didik_rombel_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// GetScannedPesertaDidikIDs retrieves peserta didik with a datang scan on the date that may not be synchronized yet
+func (r *AbsensiAlpaRepositoryImpl) GetScannedPesertaDidikIDs(tanggal time.Time) ([]uint, error) {
+	var ids []uint
+	if err := r.db.Model(&models.Absensi{}).
+		Where("tanggal = ? AND jam_datang IS NOT NULL", tanggal).
+		Pluck("peserta_didik_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// GetPengajuanIzinPendingPesertaDidikIDs retrieves peserta didik with a pengajuan izin covering the date
+// that the wali kelas has not reviewed yet
+func (r *AbsensiAlpaRepositoryImpl) GetPengajuanIzinPendingPesertaDidikIDs(tanggal time.Time) ([]uint, error) {
+	var ids []uint
+	if err := r.db.Model(&models.PengajuanIzin{}).
+		Where("status = ? AND tanggal_mulai <= ? AND tanggal_selesai >= ?", models.PengajuanIzinStatusPending, tanggal, tanggal).
+		Pluck("peserta_didik_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// CreateAlpaIfMissing inserts the rekap row unless a guru kelas row for the same member and date exists.
+// The check and insert are a single statement so concurrent runs cannot write the row twice. An inserted
+// row is recorded in the rekap history.
+func (r *AbsensiAlpaRepositoryImpl) CreateAlpaIfMissing(data *models.RekapitulasiAbsensi) (bool, error) {
+	inserted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		if err := tx.Raw(`
+			INSERT INTO rekapitulasi_absensi
+				(peserta_didik_rombel_id, rombel_id, tahun_pelajaran_id, semester, tanggal, status, metode_input, keterangan, file_surat, created_at, updated_at)
+			SELECT ?, ?, ?, ?, ?, ?, ?, ?, '', NOW(), NOW()
+			WHERE NOT EXISTS (
+				SELECT 1 FROM rekapitulasi_absensi
+				WHERE peserta_didik_rombel_id = ? AND tanggal = ? AND bidang_studi_id IS NULL AND deleted_at IS NULL
+			)
+			RETURNING id`,
+			data.PesertaDidikRombelID, data.RombelID, data.TahunPelajaranID, data.Semester, data.Tanggal,
+			data.Status, data.MetodeInput, data.Keterangan,
+			data.PesertaDidikRombelID, data.Tanggal,
+		).Scan(&ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		data.ID = ids[0]
+		inserted = true
+		return CatatRiwayatRekapAbsensi(tx, nil, data, AuditRekapAbsensi{Sumber: models.SumberRiwayatRekapAlpaOtomatis})
+	})
+	if err != nil {
+		return false, err
+	}
+	return inserted, nil
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AbsensiKehadiranRombelCount holds the scan counters of one rombel on one day
+type AbsensiKehadiranRombelCount struct {
+	RombelID   uint
+	RombelNama string
+	TotalSiswa int
+	Hadir      int
+	TepatWaktu int
+	Terlambat  int
+	Pulang     int
+}
+
+// AbsensiLiveRepository defines the interface for the live attendance counters
+type AbsensiLiveRepository interface {
+	CountKehadiranPerRombel(tanggal time.Time, rombelID *uint) ([]AbsensiKehadiranRombelCount, error)
+}
+
+type AbsensiLiveRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAbsensiLiveRepository creates a new AbsensiLive repository
+func NewAbsensiLiveRepository(db *gorm.DB) AbsensiLiveRepository {
+	return &AbsensiLiveRepositoryImpl{db: db}
+}
+
+// CountKehadiranPerRombel counts the active students of each rombel in the active tahun pelajaran
+// and how many of them scanned datang/pulang on the date, ordered by rombel name
+func (r *AbsensiLiveRepositoryImpl) CountKehadiranPerRombel(tanggal time.Time, rombelID *uint) ([]AbsensiKehadiranRombelCount, error) {
+	query := r.db.Table("peserta_didik_rombel AS prd").
+		Select("rb.id AS rombel_id, rb.name AS rombel_nama, "+
+			"COUNT(prd.peserta_didik_id) AS total_siswa, "+
+			"COUNT(a.id) FILTER (WHERE a.jam_datang IS NOT NULL) AS hadir, "+
+			"COUNT(a.id) FILTER (WHERE a.status = 'tepat_waktu') AS tepat_waktu, "+
+			"COUNT(a.id) FILTER (WHERE a.status = 'terlambat') AS terlambat, "+
+			"COUNT(a.id) FILTER (WHERE a.jam_pulang IS NOT NULL) AS pulang").
+		Joins("JOIN tahun_pelajaran tp ON tp.id = prd.tahun_pelajaran_id AND tp.status = 'active' AND tp.deleted_at IS NULL").
+		Joins("JOIN rombel rb ON rb.id = prd.rombel_id AND rb.deleted_at IS NULL").
+		Joins("JOIN peserta_didik pd ON pd.id = prd.peserta_didik_id AND pd.status = 'active' AND pd.deleted_at IS NULL").
+		Joins("LEFT JOIN absensi a ON a.peserta_didik_id = prd.peserta_didik_id AND a.tanggal = ?", tanggal.Format("2006-01-02")).
+		Where("prd.deleted_at IS NULL AND prd.status = ?", "active")
+
+	if rombelID != nil {
+		query = query.Where("prd.rombel_id = ?", *rombelID)
+	}
+
+	var data []AbsensiKehadiranRombelCount
+	if err := query.Group("rb.id, rb.name").Order("rb.name").Scan(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
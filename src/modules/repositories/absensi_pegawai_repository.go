@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"pintu-backend/src/modules/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AbsensiPegawaiRepository defines the interface for Absensi Pegawai repository
+type AbsensiPegawaiRepository interface {
+	GetKepegawaianByBarcode(barcode string) (*models.Kepegawaian, error)
+	GetKonfigurasi() (*models.KonfigurasiAbsensiPegawai, error)
+	CreateKonfigurasi(data *models.KonfigurasiAbsensiPegawai) error
+	UpdateKonfigurasi(data *models.KonfigurasiAbsensiPegawai) error
+	GetByKepegawaianAndDate(kepegawaianID uint, tanggal time.Time) (*models.AbsensiPegawai, error)
+	Upsert(absensi *models.AbsensiPegawai) error
+	GetActiveKepegawaian(kategori string, kepegawaianID *uint) ([]models.Kepegawaian, error)
+	GetByPeriode(tanggalMulai, tanggalSelesai time.Time, kepegawaianIDs []uint) ([]models.AbsensiPegawai, error)
+	GetKonfigurasiAbsensi() (*models.KonfigurasiAbsensi, error)
+}
+
+type AbsensiPegawaiRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAbsensiPegawaiRepository creates a new Absensi Pegawai repository
+func NewAbsensiPegawaiRepository(db *gorm.DB) AbsensiPegawaiRepository {
+	return &AbsensiPegawaiRepositoryImpl{db: db}
+}
+
+// GetKepegawaianByBarcode retrieves kepegawaian by barcode
+func (r *AbsensiPegawaiRepositoryImpl) GetKepegawaianByBarcode(barcode string) (*models.Kepegawaian, error) {
+	var kepegawaian models.Kepegawaian
+	if err := r.db.Where("barcode = ?", barcode).First(&kepegawaian).Error; err != nil {
+		return nil, err
+	}
+	return &kepegawaian, nil
+}
+
+// GetKonfigurasi retrieves konfigurasi absensi pegawai with ID = 1
+func (r *AbsensiPegawaiRepositoryImpl) GetKonfigurasi() (*models.KonfigurasiAbsensiPegawai, error) {
+	var config models.KonfigurasiAbsensiPegawai
+	if err := r.db.First(&config, 1).Error; err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// CreateKonfigurasi creates a new konfigurasi absensi pegawai record
+func (r *AbsensiPegawaiRepositoryImpl) CreateKonfigurasi(data *models.KonfigurasiAbsensiPegawai) error {
+	return r.db.Create(data).Error
+}
+
+// UpdateKonfigurasi updates an existing konfigurasi absensi pegawai record
+func (r *AbsensiPegawaiRepositoryImpl) UpdateKonfigurasi(data *models.KonfigurasiAbsensiPegawai) error {
+	return r.db.Save(data).Error
+}
+
+// GetByKepegawaianAndDate retrieves absensi pegawai by kepegawaian ID and date
+func (r *AbsensiPegawaiRepositoryImpl) GetByKepegawaianAndDate(kepegawaianID uint, tanggal time.Time) (*models.AbsensiPegawai, error) {
+	var absensi models.AbsensiPegawai
+	if err := r.db.Where("kepegawaian_id = ? AND tanggal = ?", kepegawaianID, tanggal.Format("2006-01-02")).First(&absensi).Error; err != nil {
+		return nil, err
+	}
+	return &absensi, nil
+}
+
+// Upsert creates or updates absensi pegawai record using UPSERT
+func (r *AbsensiPegawaiRepositoryImpl) Upsert(absensi *models.AbsensiPegawai) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "kepegawaian_id"}, {Name: "tanggal"}},
+		DoUpdates: clause.AssignmentColumns([]string{"jam_datang", "jam_pulang", "status", "scanner_device_id", "updated_at"}),
+	}).Omit("Kepegawaian").Create(absensi).Error
+}
+
+// GetActiveKepegawaian retrieves active kepegawaian for the rekap, optionally narrowed by kategori or a single ID
+func (r *AbsensiPegawaiRepositoryImpl) GetActiveKepegawaian(kategori string, kepegawaianID *uint) ([]models.Kepegawaian, error) {
+	var data []models.Kepegawaian
+	query := r.db.Where("status = ?", "active")
+	if kategori != "" {
+		query = query.Where("LOWER(kategori) = ?", strings.ToLower(kategori))
+	}
+	if kepegawaianID != nil {
+		query = query.Where("id = ?", *kepegawaianID)
+	}
+	if err := query.Order("nama ASC").Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// GetByPeriode retrieves absensi pegawai rows between two dates (inclusive) for the given kepegawaian
+func (r *AbsensiPegawaiRepositoryImpl) GetByPeriode(tanggalMulai, tanggalSelesai time.Time, kepegawaianIDs []uint) ([]models.AbsensiPegawai, error) {
+	var data []models.AbsensiPegawai
+	if len(kepegawaianIDs) == 0 {
+		return data, nil
+	}
+	if err := r.db.Where("tanggal BETWEEN ? AND ?", tanggalMulai.Format("2006-01-02"), tanggalSelesai.Format("2006-01-02")).
+		Where("kepegawaian_id IN ?", kepegawaianIDs).
+		Order("tanggal ASC").
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// GetKonfigurasiAbsensi retrieves the student konfigurasi absensi, which holds the kepala sekolah signing the exports
+func (r *AbsensiPegawaiRepositoryImpl) GetKonfigurasiAbsensi() (*models.KonfigurasiAbsensi, error) {
+	var config models.KonfigurasiAbsensi
+	if err := r.db.First(&config, 1).Error; err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
@@ -9,10 +9,11 @@ import (
 
 // AbsensiRepository handles data operations for Absensi
 type AbsensiRepository interface {
-	Create(data *models.RekapitulasiAbsensi) error
+	Create(data *models.RekapitulasiAbsensi, audit AuditRekapAbsensi) error
 	GetByID(id uint) (*models.RekapitulasiAbsensi, error)
 	GetPesertaDidikRombelID(pesertaDidikID, rombelID uint) (uint, error)
 	GetPesertaDidikRombelByID(id uint) (*models.PesertaDidikRombel, error)
+	GetPesertaDidikRombelByNIS(rombelID uint, nis string) (*models.PesertaDidikRombel, error)
 	CheckDuplicateGuruKelas(rombelID, tahunPelajaranID uint, semester int, tanggal time.Time) (*models.RekapitulasiAbsensi, error)
 	CheckDuplicateGuruMapel(rombelID, tahunPelajaranID, bidangStudiID uint, semester, pertemuanKe int, bulan, tahun int) (*models.RekapitulasiAbsensi, error)
 	GetByPesertaDidikTanggalMapel(pesertaDidikRombelID uint, tanggal time.Time, bidangStudiID *uint) (*models.RekapitulasiAbsensi, error)
@@ -20,11 +21,14 @@ type AbsensiRepository interface {
 	GetPertemuanTanggal(rombelID uint, bidangStudiID uint, tahunPelajaranID uint, bulan int, tahun int, pertemuanKe int) (*time.Time, error)
 	BulkCreate(dataList []models.RekapitulasiAbsensi) error
 	GetRekapAbsensi(tahunPelajaranID, rombelID uint, semester, bulan, tahun *int, tanggalMulai, tanggalSelesai *time.Time, bidangStudiID *uint) ([]models.RekapitulasiAbsensi, error)
-	Update(data *models.RekapitulasiAbsensi) error
+	Update(data *models.RekapitulasiAbsensi, audit AuditRekapAbsensi) error
 	GetDashboardSummary(tahunPelajaranID uint, rombelID *uint, semester *int, bidangStudiID *uint, tanggalMulai, tanggalSelesai *time.Time) ([]models.RekapitulasiAbsensi, error)
 	CountUniqueSiswa(tahunPelajaranID uint, rombelID *uint, semester *int, bidangStudiID *uint) (int, error)
 	GetPerbandinganRombel(tahunPelajaranID uint, semester *int, bidangStudiID *uint, tanggalMulai, tanggalSelesai *time.Time) ([]models.RekapitulasiAbsensi, error)
 	GetSiswaTerendah(tahunPelajaranID uint, rombelID *uint, semester *int, bidangStudiID *uint, tanggalMulai, tanggalSelesai *time.Time) ([]models.RekapitulasiAbsensi, error)
+	CountStatusPerPeriode(filter AbsensiAggregateFilter, periode string) ([]AbsensiPeriodeCount, error)
+	CountStatusPerRombel(filter AbsensiAggregateFilter) ([]AbsensiRombelCount, error)
+	CountStatusPerSiswa(filter AbsensiAggregateFilter, limit int) ([]AbsensiSiswaCount, error)
 	GetDashboardSiswa(pesertaDidikRombelID, tahunPelajaranID, rombelID uint, semester *int, bidangStudiID *uint, tanggalMulai, tanggalSelesai *time.Time) ([]models.RekapitulasiAbsensi, error)
 	GetAbsensiScanByDate(tanggal time.Time) ([]models.Absensi, error)
 	GetAbsensiScanByMonth(bulan, tahun int) ([]models.Absensi, error)
@@ -40,9 +44,14 @@ func NewAbsensiRepository(db *gorm.DB) AbsensiRepository {
 	return &AbsensiRepositoryImpl{db: db}
 }
 
-// Create creates a new Absensi record
-func (r *AbsensiRepositoryImpl) Create(data *models.RekapitulasiAbsensi) error {
-	return r.db.Create(data).Error
+// Create creates a new Absensi record and its history entry
+func (r *AbsensiRepositoryImpl) Create(data *models.RekapitulasiAbsensi, audit AuditRekapAbsensi) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(data).Error; err != nil {
+			return err
+		}
+		return CatatRiwayatRekapAbsensi(tx, nil, data, audit)
+	})
 }
 
 // GetPesertaDidikRombelID gets peserta_didik_rombel_id by peserta_didik_id and rombel_id
@@ -66,11 +75,24 @@ func (r *AbsensiRepositoryImpl) GetPesertaDidikRombelByID(id uint) (*models.Pese
 	return &pesertaDidikRombel, nil
 }
 
+// GetPesertaDidikRombelByNIS gets the peserta_didik_rombel of a student in a rombel by the student's NIS
+func (r *AbsensiRepositoryImpl) GetPesertaDidikRombelByNIS(rombelID uint, nis string) (*models.PesertaDidikRombel, error) {
+	var pesertaDidikRombel models.PesertaDidikRombel
+	err := r.db.Joins("JOIN peserta_didik ON peserta_didik.id = peserta_didik_rombel.peserta_didik_id").
+		Where("peserta_didik_rombel.rombel_id = ? AND peserta_didik.nis = ?", rombelID, nis).
+		First(&pesertaDidikRombel).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pesertaDidikRombel, nil
+}
+
 // CheckDuplicateGuruKelas checks if absensi for guru kelas already exists on this date
 func (r *AbsensiRepositoryImpl) CheckDuplicateGuruKelas(rombelID, tahunPelajaranID uint, semester int, tanggal time.Time) (*models.RekapitulasiAbsensi, error) {
 	var data models.RekapitulasiAbsensi
 	err := r.db.Where("rombel_id = ? AND tahun_pelajaran_id = ? AND semester = ? AND tanggal = ? AND bidang_studi_id IS NULL", 
 		rombelID, tahunPelajaranID, semester, tanggal).
+		Where("metode_input <> ?", models.MetodeInputAlpaOtomatis). // Rows of the alpa job are replaced by manual input
 		First(&data).Error
 	if err != nil {
 		return nil, err
@@ -124,7 +146,7 @@ func (r *AbsensiRepositoryImpl) BulkCreate(dataList []models.RekapitulasiAbsensi
 func (r *AbsensiRepositoryImpl) GetRekapAbsensi(tahunPelajaranID, rombelID uint, semester, bulan, tahun *int, tanggalMulai, tanggalSelesai *time.Time, bidangStudiID *uint) ([]models.RekapitulasiAbsensi, error) {
 	var data []models.RekapitulasiAbsensi
 	
-	query := r.db.Preload("PesertaDidikRombel.PesertaDidik").Preload("Rombel").Preload("BidangStudi").Preload("DicatatOleh").
+	query := r.db.Preload("PesertaDidikRombel.PesertaDidik").Preload("Rombel").Preload("BidangStudi").Preload("DicatatOleh").Preload("DicatatOlehPegawai").
 		Where("tahun_pelajaran_id = ? AND rombel_id = ?", tahunPelajaranID, rombelID)
 	
 	// Filter by semester if provided
@@ -174,15 +196,24 @@ func (r *AbsensiRepositoryImpl) GetRekapAbsensi(tahunPelajaranID, rombelID uint,
 // GetByID retrieves Absensi by ID
 func (r *AbsensiRepositoryImpl) GetByID(id uint) (*models.RekapitulasiAbsensi, error) {
 	var data models.RekapitulasiAbsensi
-	if err := r.db.Preload("PesertaDidikRombel.PesertaDidik").Preload("Rombel").Preload("TahunPelajaran").Preload("BidangStudi").Preload("DicatatOleh").First(&data, id).Error; err != nil {
+	if err := r.db.Preload("PesertaDidikRombel.PesertaDidik").Preload("Rombel").Preload("TahunPelajaran").Preload("BidangStudi").Preload("DicatatOleh").Preload("DicatatOlehPegawai").First(&data, id).Error; err != nil {
 		return nil, err
 	}
 	return &data, nil
 }
 
-// Update updates an Absensi record
-func (r *AbsensiRepositoryImpl) Update(data *models.RekapitulasiAbsensi) error {
-	return r.db.Save(data).Error
+// Update updates an Absensi record and records the stored values it replaces in the history
+func (r *AbsensiRepositoryImpl) Update(data *models.RekapitulasiAbsensi, audit AuditRekapAbsensi) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var sebelum models.RekapitulasiAbsensi
+		if err := tx.First(&sebelum, data.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(data).Error; err != nil {
+			return err
+		}
+		return CatatRiwayatRekapAbsensi(tx, &sebelum, data, audit)
+	})
 }
 
 // CheckPertemuanExists checks if a pertemuan already exists in a specific month for guru mapel
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"errors"
 	"pintu-backend/src/modules/models"
 	"time"
 
@@ -8,12 +9,19 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+// ErrKartuQRNonceSudahDipakai is returned when a signed QR nonce was already used by another scan
+var ErrKartuQRNonceSudahDipakai = errors.New("QR kartu pelajar sudah pernah dipindai, muat ulang QR di portal siswa")
+
 // AbsensiScanRepository defines the interface for Absensi Scan repository
 type AbsensiScanRepository interface {
 	GetPesertaDidikByBarcode(barcode string) (*models.PesertaDidik, error)
+	GetPesertaDidikByID(id uint) (*models.PesertaDidik, error)
+	IsKartuQRNonceUsed(nonce string) (bool, error)
 	GetKonfigurasiAbsensi() (*models.KonfigurasiAbsensi, error)
 	GetAbsensiByPesertaDidikAndDate(pesertaDidikID uint, tanggal time.Time) (*models.Absensi, error)
-	UpsertAbsensi(absensi *models.Absensi) error
+	UpsertAbsensi(absensi *models.Absensi, nonce *models.KartuQRNonce) error
+	GetScanLogsByClientScanIDs(scannerDeviceID uint, clientScanIDs []string) ([]models.AbsensiScanLog, error)
+	CreateScanLog(log *models.AbsensiScanLog) error
 }
 
 type AbsensiScanRepositoryImpl struct {
@@ -34,6 +42,29 @@ func (r *AbsensiScanRepositoryImpl) GetPesertaDidikByBarcode(barcode string) (*m
 	return &pesertaDidik, nil
 }
 
+// GetPesertaDidikByID retrieves peserta didik by ID, for signed kartu pelajar QR scans
+func (r *AbsensiScanRepositoryImpl) GetPesertaDidikByID(id uint) (*models.PesertaDidik, error) {
+	var pesertaDidik models.PesertaDidik
+	if err := r.db.First(&pesertaDidik, id).Error; err != nil {
+		return nil, err
+	}
+	return &pesertaDidik, nil
+}
+
+// IsKartuQRNonceUsed reports whether a signed QR nonce was already used by a saved scan.
+// Nonces past their expiry are cleaned up on the way, the signature check rejects them anyway.
+func (r *AbsensiScanRepositoryImpl) IsKartuQRNonceUsed(nonce string) (bool, error) {
+	if err := r.db.Where("expires_at < ?", time.Now()).Delete(&models.KartuQRNonce{}).Error; err != nil {
+		return false, err
+	}
+
+	var count int64
+	if err := r.db.Model(&models.KartuQRNonce{}).Where("nonce = ?", nonce).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 // GetKonfigurasiAbsensi retrieves konfigurasi absensi with ID = 1
 func (r *AbsensiScanRepositoryImpl) GetKonfigurasiAbsensi() (*models.KonfigurasiAbsensi, error) {
 	var config models.KonfigurasiAbsensi
@@ -52,10 +83,45 @@ func (r *AbsensiScanRepositoryImpl) GetAbsensiByPesertaDidikAndDate(pesertaDidik
 	return &absensi, nil
 }
 
-// UpsertAbsensi creates or updates absensi record using UPSERT
-func (r *AbsensiScanRepositoryImpl) UpsertAbsensi(absensi *models.Absensi) error {
+// UpsertAbsensi creates or updates absensi record using UPSERT. The signed QR nonce of the scan, if any, is
+// used up in the same transaction, so a scan that fails to save can be retried with the same QR.
+func (r *AbsensiScanRepositoryImpl) UpsertAbsensi(absensi *models.Absensi, nonce *models.KartuQRNonce) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if nonce != nil {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(nonce)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrKartuQRNonceSudahDipakai
+			}
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "peserta_didik_id"}, {Name: "tanggal"}},
+			DoUpdates: clause.AssignmentColumns([]string{"jam_datang", "jam_pulang", "status", "scanner_device_id", "updated_at"}),
+		}).Create(absensi).Error
+	})
+}
+
+// GetScanLogsByClientScanIDs retrieves stored batch scan results of a device for the given client scan IDs
+func (r *AbsensiScanRepositoryImpl) GetScanLogsByClientScanIDs(scannerDeviceID uint, clientScanIDs []string) ([]models.AbsensiScanLog, error) {
+	var logs []models.AbsensiScanLog
+	if len(clientScanIDs) == 0 {
+		return logs, nil
+	}
+	if err := r.db.Preload("PesertaDidik").
+		Where("scanner_device_id = ? AND client_scan_id IN ?", scannerDeviceID, clientScanIDs).
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// CreateScanLog stores a batch scan result; a concurrent replay of the same client scan ID is ignored
+func (r *AbsensiScanRepositoryImpl) CreateScanLog(log *models.AbsensiScanLog) error {
 	return r.db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "peserta_didik_id"}, {Name: "tanggal"}},
-		DoUpdates: clause.AssignmentColumns([]string{"jam_datang", "jam_pulang", "status", "updated_at"}),
-	}).Create(absensi).Error
+		Columns:   []clause.Column{{Name: "scanner_device_id"}, {Name: "client_scan_id"}},
+		DoNothing: true,
+	}).Omit("PesertaDidik").Create(log).Error
 }
@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"time"
+
+	"pintu-backend/src/modules/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AuthTokenRepository handles data operations for refresh tokens and the access token denylist
+type AuthTokenRepository interface {
+	CreateRefreshToken(data *models.RefreshToken) error
+	GetRefreshTokenByHash(tokenHash string) (*models.RefreshToken, error)
+	RotateRefreshToken(old *models.RefreshToken, data *models.RefreshToken) error
+	RevokeSessionByAccessJTI(jti string, accessExpiresAt time.Time) error
+	RevokeAllForPrincipal(principalType string, principalID uint) error
+	IsAccessTokenRevoked(jti string) (bool, error)
+	DeleteExpired() (int64, error)
+}
+
+type AuthTokenRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAuthTokenRepository creates a new AuthToken repository
+func NewAuthTokenRepository(db *gorm.DB) AuthTokenRepository {
+	return &AuthTokenRepositoryImpl{db: db}
+}
+
+// CreateRefreshToken creates a new RefreshToken record
+func (r *AuthTokenRepositoryImpl) CreateRefreshToken(data *models.RefreshToken) error {
+	return r.db.Create(data).Error
+}
+
+// GetRefreshTokenByHash retrieves RefreshToken by its SHA-256 hash
+func (r *AuthTokenRepositoryImpl) GetRefreshTokenByHash(tokenHash string) (*models.RefreshToken, error) {
+	var data models.RefreshToken
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&data).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// RotateRefreshToken revokes the old refresh token and its access token, then stores the
+// replacement atomically. Fails with gorm.ErrRecordNotFound when the old token was
+// already revoked concurrently.
+func (r *AuthTokenRepositoryImpl) RotateRefreshToken(old *models.RefreshToken, data *models.RefreshToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(data).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", old.ID).
+			Updates(map[string]interface{}{
+				"revoked_at":     time.Now(),
+				"replaced_by_id": data.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if old.AccessExpiresAt.Before(time.Now()) {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.RevokedToken{
+			JTI:       old.AccessJTI,
+			ExpiresAt: old.AccessExpiresAt,
+		}).Error
+	})
+}
+
+// RevokeSessionByAccessJTI denylists the access token and revokes the refresh token issued with it
+func (r *AuthTokenRepositoryImpl) RevokeSessionByAccessJTI(jti string, accessExpiresAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.RevokedToken{
+			JTI:       jti,
+			ExpiresAt: accessExpiresAt,
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.RefreshToken{}).
+			Where("access_jti = ? AND revoked_at IS NULL", jti).
+			Update("revoked_at", time.Now()).Error
+	})
+}
+
+// RevokeAllForPrincipal revokes every active session of a principal, including
+// the access tokens that are still within their lifetime
+func (r *AuthTokenRepositoryImpl) RevokeAllForPrincipal(principalType string, principalID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return revokeAllForPrincipal(tx, principalType, principalID)
+	})
+}
+
+// revokeAllForPrincipal is RevokeAllForPrincipal inside a transaction of the caller, so other
+// repositories can revoke sessions together with the change that ends them
+func revokeAllForPrincipal(tx *gorm.DB, principalType string, principalID uint) error {
+	now := time.Now()
+
+	var sessions []models.RefreshToken
+	if err := tx.Where("principal_type = ? AND principal_id = ? AND revoked_at IS NULL", principalType, principalID).
+		Find(&sessions).Error; err != nil {
+		return err
+	}
+
+	revoked := make([]models.RevokedToken, 0, len(sessions))
+	for _, session := range sessions {
+		if session.AccessExpiresAt.After(now) {
+			revoked = append(revoked, models.RevokedToken{
+				JTI:       session.AccessJTI,
+				ExpiresAt: session.AccessExpiresAt,
+			})
+		}
+	}
+
+	if len(revoked) > 0 {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&revoked).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(&models.RefreshToken{}).
+		Where("principal_type = ? AND principal_id = ? AND revoked_at IS NULL", principalType, principalID).
+		Update("revoked_at", now).Error
+}
+
+// IsAccessTokenRevoked reports whether the access token jti is on the denylist
+func (r *AuthTokenRepositoryImpl) IsAccessTokenRevoked(jti string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// DeleteExpired removes denylist entries and refresh tokens that can no longer be used
+func (r *AuthTokenRepositoryImpl) DeleteExpired() (int64, error) {
+	now := time.Now()
+
+	revoked := r.db.Where("expires_at < ?", now).Delete(&models.RevokedToken{})
+	if revoked.Error != nil {
+		return 0, revoked.Error
+	}
+
+	refresh := r.db.Where("expires_at < ?", now).Delete(&models.RefreshToken{})
+	if refresh.Error != nil {
+		return revoked.RowsAffected, refresh.Error
+	}
+
+	return revoked.RowsAffected + refresh.RowsAffected, nil
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"time"
+
+	"pintu-backend/src/modules/models"
+
+	"gorm.io/gorm"
+)
+
+// FailedAttemptRepository handles data operations for failed authentication attempts
+type FailedAttemptRepository interface {
+	Create(data *models.FailedAttempt) error
+	CountByIPSince(scope, ipAddress string, since time.Time) (int64, error)
+	CountByIdentifierSince(scope, identifier string, since time.Time) (int64, error)
+	DeleteByIdentifier(scope, identifier string) error
+	DeleteOlderThan(before time.Time) (int64, error)
+}
+
+type FailedAttemptRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewFailedAttemptRepository creates a new FailedAttempt repository
+func NewFailedAttemptRepository(db *gorm.DB) FailedAttemptRepository {
+	return &FailedAttemptRepositoryImpl{db: db}
+}
+
+// Create records a failed attempt
+func (r *FailedAttemptRepositoryImpl) Create(data *models.FailedAttempt) error {
+	return r.db.Create(data).Error
+}
+
+// CountByIPSince counts failed attempts of a scope from an IP address since the given time
+func (r *FailedAttemptRepositoryImpl) CountByIPSince(scope, ipAddress string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.FailedAttempt{}).
+		Where("scope = ? AND ip_address = ? AND created_at > ?", scope, ipAddress, since).
+		Count(&count).Error
+	return count, err
+}
+
+// CountByIdentifierSince counts failed attempts of a scope for an identifier since the given time
+func (r *FailedAttemptRepositoryImpl) CountByIdentifierSince(scope, identifier string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.FailedAttempt{}).
+		Where("scope = ? AND identifier = ? AND created_at > ?", scope, identifier, since).
+		Count(&count).Error
+	return count, err
+}
+
+// DeleteByIdentifier clears the failed attempts of an identifier, e.g. after a successful login
+func (r *FailedAttemptRepositoryImpl) DeleteByIdentifier(scope, identifier string) error {
+	return r.db.Where("scope = ? AND identifier = ?", scope, identifier).Delete(&models.FailedAttempt{}).Error
+}
+
+// DeleteOlderThan removes failed attempts that fall outside every throttling window
+func (r *FailedAttemptRepositoryImpl) DeleteOlderThan(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&models.FailedAttempt{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,262 @@
+package repositories
+
+import (
+	"pintu-backend/src/modules/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// GetProfilJadwalAbsensiParams represents parameters for GetAllProfil
+type GetProfilJadwalAbsensiParams struct {
+	Nama   string
+	Status string
+	Limit  int
+	Offset int
+}
+
+// GetPenugasanJadwalAbsensiParams represents parameters for GetAllPenugasan
+type GetPenugasanJadwalAbsensiParams struct {
+	ProfilJadwalAbsensiID *uint
+	KelasID               *uint
+	RombelID              *uint
+	Limit                 int
+	Offset                int
+}
+
+// JadwalAbsensiRepository defines the interface for jadwal absensi (schedule profiles and their assignments)
+type JadwalAbsensiRepository interface {
+	CreateProfil(data *models.ProfilJadwalAbsensi) error
+	GetProfilByID(id uint) (*models.ProfilJadwalAbsensi, error)
+	GetAllProfil(params GetProfilJadwalAbsensiParams) ([]models.ProfilJadwalAbsensi, int64, error)
+	UpdateProfil(data *models.ProfilJadwalAbsensi) error
+	DeleteProfil(id uint) error
+	CountPenugasanByProfil(profilID uint) (int64, error)
+	CreatePenugasan(data *models.PenugasanJadwalAbsensi) error
+	GetPenugasanByID(id uint) (*models.PenugasanJadwalAbsensi, error)
+	GetAllPenugasan(params GetPenugasanJadwalAbsensiParams) ([]models.PenugasanJadwalAbsensi, int64, error)
+	UpdatePenugasan(data *models.PenugasanJadwalAbsensi) error
+	DeletePenugasan(id uint) error
+	ExistsPenugasanReguler(kelasID, rombelID *uint, excludeID uint) (bool, error)
+	GetPenugasanBerlaku(kelasID, rombelID *uint, tanggal time.Time) ([]models.PenugasanJadwalAbsensi, error)
+	GetRombelAktifPesertaDidik(pesertaDidikID uint) (*models.PesertaDidikRombel, error)
+	GetKonfigurasiAbsensi() (*models.KonfigurasiAbsensi, error)
+}
+
+type JadwalAbsensiRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewJadwalAbsensiRepository creates a new JadwalAbsensi repository
+func NewJadwalAbsensiRepository(db *gorm.DB) JadwalAbsensiRepository {
+	return &JadwalAbsensiRepositoryImpl{db: db}
+}
+
+// CreateProfil creates a profile together with its weekday overrides
+func (r *JadwalAbsensiRepositoryImpl) CreateProfil(data *models.ProfilJadwalAbsensi) error {
+	return r.db.Create(data).Error
+}
+
+// GetProfilByID retrieves a profile with its weekday overrides
+func (r *JadwalAbsensiRepositoryImpl) GetProfilByID(id uint) (*models.ProfilJadwalAbsensi, error) {
+	var data models.ProfilJadwalAbsensi
+	if err := r.db.Preload("Hari", func(db *gorm.DB) *gorm.DB {
+		return db.Order("hari ASC")
+	}).First(&data, id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetAllProfil retrieves profiles with filters and pagination
+func (r *JadwalAbsensiRepositoryImpl) GetAllProfil(params GetProfilJadwalAbsensiParams) ([]models.ProfilJadwalAbsensi, int64, error) {
+	var data []models.ProfilJadwalAbsensi
+	var total int64
+
+	query := r.db.Model(&models.ProfilJadwalAbsensi{})
+	if params.Nama != "" {
+		query = query.Where("nama ILIKE ?", "%"+params.Nama+"%")
+	}
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("Hari", func(db *gorm.DB) *gorm.DB {
+		return db.Order("hari ASC")
+	}).Order("nama ASC").Limit(params.Limit).Offset(params.Offset).Find(&data).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return data, total, nil
+}
+
+// UpdateProfil updates a profile and replaces its weekday overrides
+func (r *JadwalAbsensiRepositoryImpl) UpdateProfil(data *models.ProfilJadwalAbsensi) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("profil_jadwal_absensi_id = ?", data.ID).Delete(&models.ProfilJadwalAbsensiHari{}).Error; err != nil {
+			return err
+		}
+		for i := range data.Hari {
+			data.Hari[i].ID = 0
+			data.Hari[i].ProfilJadwalAbsensiID = data.ID
+		}
+		if len(data.Hari) > 0 {
+			if err := tx.Create(&data.Hari).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Omit("Hari").Save(data).Error
+	})
+}
+
+// DeleteProfil deletes a profile; its weekday overrides are removed by the foreign key
+func (r *JadwalAbsensiRepositoryImpl) DeleteProfil(id uint) error {
+	result := r.db.Delete(&models.ProfilJadwalAbsensi{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CountPenugasanByProfil counts the assignments that still use a profile
+func (r *JadwalAbsensiRepositoryImpl) CountPenugasanByProfil(profilID uint) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.PenugasanJadwalAbsensi{}).Where("profil_jadwal_absensi_id = ?", profilID).Count(&total).Error
+	return total, err
+}
+
+// CreatePenugasan creates a new assignment
+func (r *JadwalAbsensiRepositoryImpl) CreatePenugasan(data *models.PenugasanJadwalAbsensi) error {
+	return r.db.Omit("Profil", "Kelas", "Rombel").Create(data).Error
+}
+
+// GetPenugasanByID retrieves an assignment with its profile, kelas and rombel
+func (r *JadwalAbsensiRepositoryImpl) GetPenugasanByID(id uint) (*models.PenugasanJadwalAbsensi, error) {
+	var data models.PenugasanJadwalAbsensi
+	if err := r.db.Preload("Profil").Preload("Kelas").Preload("Rombel").First(&data, id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetAllPenugasan retrieves assignments with filters and pagination, regular ones first
+func (r *JadwalAbsensiRepositoryImpl) GetAllPenugasan(params GetPenugasanJadwalAbsensiParams) ([]models.PenugasanJadwalAbsensi, int64, error) {
+	var data []models.PenugasanJadwalAbsensi
+	var total int64
+
+	query := r.db.Model(&models.PenugasanJadwalAbsensi{})
+	if params.ProfilJadwalAbsensiID != nil {
+		query = query.Where("profil_jadwal_absensi_id = ?", *params.ProfilJadwalAbsensiID)
+	}
+	if params.KelasID != nil {
+		query = query.Where("kelas_id = ?", *params.KelasID)
+	}
+	if params.RombelID != nil {
+		query = query.Where("rombel_id = ?", *params.RombelID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("Profil").Preload("Kelas").Preload("Rombel").
+		Order("tanggal_mulai ASC NULLS FIRST, id ASC").
+		Limit(params.Limit).Offset(params.Offset).
+		Find(&data).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return data, total, nil
+}
+
+// UpdatePenugasan updates an assignment
+func (r *JadwalAbsensiRepositoryImpl) UpdatePenugasan(data *models.PenugasanJadwalAbsensi) error {
+	return r.db.Omit("Profil", "Kelas", "Rombel").Save(data).Error
+}
+
+// DeletePenugasan deletes an assignment
+func (r *JadwalAbsensiRepositoryImpl) DeletePenugasan(id uint) error {
+	result := r.db.Delete(&models.PenugasanJadwalAbsensi{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ExistsPenugasanReguler checks whether the scope already has a regular (undated) assignment
+func (r *JadwalAbsensiRepositoryImpl) ExistsPenugasanReguler(kelasID, rombelID *uint, excludeID uint) (bool, error) {
+	query := r.db.Model(&models.PenugasanJadwalAbsensi{}).Where("tanggal_mulai IS NULL").Where("id <> ?", excludeID)
+	if kelasID != nil {
+		query = query.Where("kelas_id = ?", *kelasID)
+	} else {
+		query = query.Where("kelas_id IS NULL")
+	}
+	if rombelID != nil {
+		query = query.Where("rombel_id = ?", *rombelID)
+	} else {
+		query = query.Where("rombel_id IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return false, err
+	}
+	return total > 0, nil
+}
+
+// GetPenugasanBerlaku retrieves the assignments of active profiles that may apply to a student of the
+// given kelas and rombel on a date: the rombel's, the kelas's and the all-students ones, regular or covering the date
+func (r *JadwalAbsensiRepositoryImpl) GetPenugasanBerlaku(kelasID, rombelID *uint, tanggal time.Time) ([]models.PenugasanJadwalAbsensi, error) {
+	var data []models.PenugasanJadwalAbsensi
+
+	scope := r.db.Where("penugasan_jadwal_absensi.kelas_id IS NULL AND penugasan_jadwal_absensi.rombel_id IS NULL")
+	if kelasID != nil {
+		scope = scope.Or("penugasan_jadwal_absensi.kelas_id = ?", *kelasID)
+	}
+	if rombelID != nil {
+		scope = scope.Or("penugasan_jadwal_absensi.rombel_id = ?", *rombelID)
+	}
+
+	err := r.db.Joins("JOIN profil_jadwal_absensi ON profil_jadwal_absensi.id = penugasan_jadwal_absensi.profil_jadwal_absensi_id").
+		Where("profil_jadwal_absensi.status = ?", "active").
+		Where(scope).
+		Where("(penugasan_jadwal_absensi.tanggal_mulai IS NULL OR ? BETWEEN penugasan_jadwal_absensi.tanggal_mulai AND penugasan_jadwal_absensi.tanggal_selesai)", tanggal.Format("2006-01-02")).
+		Preload("Profil.Hari").
+		Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// GetRombelAktifPesertaDidik retrieves the student's active rombel in the active tahun pelajaran
+func (r *JadwalAbsensiRepositoryImpl) GetRombelAktifPesertaDidik(pesertaDidikID uint) (*models.PesertaDidikRombel, error) {
+	var data models.PesertaDidikRombel
+	if err := r.db.Joins("JOIN tahun_pelajaran ON tahun_pelajaran.id = peserta_didik_rombel.tahun_pelajaran_id").
+		Where("peserta_didik_rombel.peserta_didik_id = ? AND peserta_didik_rombel.status = ?", pesertaDidikID, "active").
+		Where("tahun_pelajaran.status = ?", "active").
+		Preload("Rombel").
+		First(&data).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetKonfigurasiAbsensi retrieves konfigurasi absensi with ID = 1, the default schedule
+func (r *JadwalAbsensiRepositoryImpl) GetKonfigurasiAbsensi() (*models.KonfigurasiAbsensi, error) {
+	var config models.KonfigurasiAbsensi
+	if err := r.db.First(&config, 1).Error; err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
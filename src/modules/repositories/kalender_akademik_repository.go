@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"time"
+
+	"pintu-backend/src/modules/models"
+
+	"gorm.io/gorm"
+)
+
+// GetKalenderAkademikFilter represents filter parameters for GetAllWithFilter
+type GetKalenderAkademikFilter struct {
+	TahunPelajaranID *uint
+	Jenis            string
+	Nama             string
+	TanggalMulai     *time.Time
+	TanggalSelesai   *time.Time
+}
+
+// GetKalenderAkademikParams represents parameters for GetAllWithFilter
+type GetKalenderAkademikParams struct {
+	Filter GetKalenderAkademikFilter
+	Limit  int
+	Offset int
+}
+
+// KalenderAkademikRepository handles data operations for KalenderAkademik
+type KalenderAkademikRepository interface {
+	Create(data *models.KalenderAkademik) error
+	GetByID(id uint) (*models.KalenderAkademik, error)
+	GetByExternalUID(uid string) (*models.KalenderAkademik, error)
+	GetAllWithFilter(params GetKalenderAkademikParams) ([]models.KalenderAkademik, int64, error)
+	GetLiburBetween(tanggalMulai, tanggalSelesai time.Time) ([]models.KalenderAkademik, error)
+	Update(data *models.KalenderAkademik) error
+	Delete(id uint) error
+}
+
+type KalenderAkademikRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewKalenderAkademikRepository creates a new KalenderAkademik repository
+func NewKalenderAkademikRepository(db *gorm.DB) KalenderAkademikRepository {
+	return &KalenderAkademikRepositoryImpl{db: db}
+}
+
+// Create creates a new KalenderAkademik record
+func (r *KalenderAkademikRepositoryImpl) Create(data *models.KalenderAkademik) error {
+	return r.db.Omit("TahunPelajaran").Create(data).Error
+}
+
+// GetByID retrieves KalenderAkademik by ID
+func (r *KalenderAkademikRepositoryImpl) GetByID(id uint) (*models.KalenderAkademik, error) {
+	var data models.KalenderAkademik
+	if err := r.db.Preload("TahunPelajaran").First(&data, id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetByExternalUID retrieves the KalenderAkademik imported from an iCal event UID
+func (r *KalenderAkademikRepositoryImpl) GetByExternalUID(uid string) (*models.KalenderAkademik, error) {
+	var data models.KalenderAkademik
+	if err := r.db.Where("external_uid = ?", uid).First(&data).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetAllWithFilter retrieves KalenderAkademik records with filters and pagination
+func (r *KalenderAkademikRepositoryImpl) GetAllWithFilter(params GetKalenderAkademikParams) ([]models.KalenderAkademik, int64, error) {
+	var data []models.KalenderAkademik
+	var total int64
+
+	query := r.db.Model(&models.KalenderAkademik{})
+
+	// Apply filters
+	if params.Filter.TahunPelajaranID != nil {
+		query = query.Where("tahun_pelajaran_id = ?", *params.Filter.TahunPelajaranID)
+	}
+	if params.Filter.Jenis != "" {
+		query = query.Where("jenis = ?", params.Filter.Jenis)
+	}
+	if params.Filter.Nama != "" {
+		query = query.Where("nama ILIKE ?", "%"+params.Filter.Nama+"%")
+	}
+	if params.Filter.TanggalMulai != nil {
+		query = query.Where("tanggal_selesai >= ?", params.Filter.TanggalMulai.Format("2006-01-02"))
+	}
+	if params.Filter.TanggalSelesai != nil {
+		query = query.Where("tanggal_mulai <= ?", params.Filter.TanggalSelesai.Format("2006-01-02"))
+	}
+
+	// Get total count
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get paginated data ordered by tanggal_mulai ASC
+	if err := query.Preload("TahunPelajaran").Order("tanggal_mulai ASC, id ASC").Limit(params.Limit).Offset(params.Offset).Find(&data).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return data, total, nil
+}
+
+// GetLiburBetween retrieves the non-school entries overlapping the given date range
+func (r *KalenderAkademikRepositoryImpl) GetLiburBetween(tanggalMulai, tanggalSelesai time.Time) ([]models.KalenderAkademik, error) {
+	var data []models.KalenderAkademik
+	if err := r.db.Where("libur = ? AND tanggal_mulai <= ? AND tanggal_selesai >= ?",
+		true, tanggalSelesai.Format("2006-01-02"), tanggalMulai.Format("2006-01-02")).
+		Order("tanggal_mulai ASC").
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Update updates KalenderAkademik record
+func (r *KalenderAkademikRepositoryImpl) Update(data *models.KalenderAkademik) error {
+	return r.db.Omit("TahunPelajaran").Save(data).Error
+}
+
+// Delete deletes KalenderAkademik record by ID
+func (r *KalenderAkademikRepositoryImpl) Delete(id uint) error {
+	return r.db.Delete(&models.KalenderAkademik{}, id).Error
+}
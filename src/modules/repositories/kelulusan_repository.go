@@ -50,7 +50,10 @@ func (r *KelulusanRepositoryImpl) Create(data *models.Kelulusan) error {
 // GetByID retrieves Kelulusan by ID
 func (r *KelulusanRepositoryImpl) GetByID(id uint) (*models.Kelulusan, error) {
 	var data models.Kelulusan
-	if err := r.db.Preload("CreatedBy").Preload("UpdatedBy").First(&data, id).Error; err != nil {
+	if err := r.db.First(&data, id).Error; err != nil {
+		return nil, err
+	}
+	if err := resolvePrincipalRefs(r.db, kelulusanAudits(&data)); err != nil {
 		return nil, err
 	}
 	return &data, nil
@@ -103,8 +106,6 @@ func (r *KelulusanRepositoryImpl) GetAllWithFilter(params GetKelulusanParams) ([
 
 	// Apply pagination and get data
 	if err := query.
-		Preload("CreatedBy").
-		Preload("UpdatedBy").
 		Order("created_at DESC").
 		Limit(params.Limit).
 		Offset(params.Offset).
@@ -112,9 +113,22 @@ func (r *KelulusanRepositoryImpl) GetAllWithFilter(params GetKelulusanParams) ([
 		return nil, 0, err
 	}
 
+	var audits []principalAudit
+	for i := range data {
+		audits = append(audits, kelulusanAudits(&data[i])...)
+	}
+	if err := resolvePrincipalRefs(r.db, audits); err != nil {
+		return nil, 0, err
+	}
+
 	return data, total, nil
 }
 
+// kelulusanAudits returns the audit columns of a Kelulusan
+func kelulusanAudits(data *models.Kelulusan) []principalAudit {
+	return auditPrincipals(data.CreatedByID, data.CreatedByType, &data.CreatedBy, data.UpdatedByID, data.UpdatedByType, &data.UpdatedBy)
+}
+
 
 // Update updates a Kelulusan record
 func (r *KelulusanRepositoryImpl) Update(data *models.Kelulusan) error {
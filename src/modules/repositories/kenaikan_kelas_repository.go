@@ -0,0 +1,259 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Kenaikan kelas errors returned from inside the transaction
+var (
+	ErrTahunPelajaranTujuanTerisi = errors.New("tahun pelajaran tujuan sudah memiliki pemetaan rombel, reset terlebih dahulu")
+	ErrKenaikanKelasSudahDipakai  = errors.New("rombel tahun pelajaran tujuan sudah memiliki absensi atau pengajuan izin, kenaikan kelas tidak dapat dibatalkan")
+	ErrKenaikanKelasDibatalkan    = errors.New("kenaikan kelas sudah dibatalkan")
+	ErrKenaikanKelasBukanTerakhir = errors.New("kenaikan kelas hanya dapat dibatalkan pada hari yang sama dan hanya yang terakhir diproses")
+)
+
+// KenaikanKelasRepository handles data operations for academic year rollovers
+type KenaikanKelasRepository interface {
+	GetTahunPelajaranByID(id uint) (*models.TahunPelajaran, error)
+	GetActiveTahunPelajaran() (*models.TahunPelajaran, error)
+	GetRombelAktif() ([]models.Rombel, error)
+	GetPesertaDidikRombelAktif(tahunPelajaranID uint) ([]models.PesertaDidikRombel, error)
+	CountPesertaDidikRombel(tahunPelajaranID uint) (int64, error)
+	Proses(data *models.KenaikanKelas) error
+	Batalkan(data *models.KenaikanKelas, actorID *uint, actorType *string) error
+	GetByID(id uint) (*models.KenaikanKelas, error)
+	GetLatestDiproses() (*models.KenaikanKelas, error)
+	GetAll(limit, offset int) ([]models.KenaikanKelas, int64, error)
+}
+
+type KenaikanKelasRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewKenaikanKelasRepository creates a new KenaikanKelas repository
+func NewKenaikanKelasRepository(db *gorm.DB) KenaikanKelasRepository {
+	return &KenaikanKelasRepositoryImpl{db: db}
+}
+
+// GetTahunPelajaranByID retrieves a tahun pelajaran by ID
+func (r *KenaikanKelasRepositoryImpl) GetTahunPelajaranByID(id uint) (*models.TahunPelajaran, error) {
+	var data models.TahunPelajaran
+	if err := r.db.First(&data, id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetActiveTahunPelajaran retrieves the active tahun pelajaran
+func (r *KenaikanKelasRepositoryImpl) GetActiveTahunPelajaran() (*models.TahunPelajaran, error) {
+	var data models.TahunPelajaran
+	if err := r.db.Where("status = ?", "active").First(&data).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetRombelAktif retrieves every active rombel with its kelas
+func (r *KenaikanKelasRepositoryImpl) GetRombelAktif() ([]models.Rombel, error) {
+	var data []models.Rombel
+	if err := r.db.Preload("Kelas").Where("status = ?", "active").Order("name ASC").Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// GetPesertaDidikRombelAktif retrieves the active rombel members of a tahun pelajaran whose peserta didik is still active
+func (r *KenaikanKelasRepositoryImpl) GetPesertaDidikRombelAktif(tahunPelajaranID uint) ([]models.PesertaDidikRombel, error) {
+	var data []models.PesertaDidikRombel
+	if err := r.db.Preload("PesertaDidik").Preload("Rombel.Kelas").
+		Joins("JOIN peserta_didik ON peserta_didik.id = peserta_didik_rombel.peserta_didik_id AND peserta_didik.deleted_at IS NULL").
+		Where("peserta_didik_rombel.tahun_pelajaran_id = ? AND peserta_didik_rombel.status = ? AND peserta_didik.status = ?", tahunPelajaranID, "active", "active").
+		Order("peserta_didik_rombel.rombel_id ASC, peserta_didik.nama ASC").
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// CountPesertaDidikRombel counts the rombel members of a tahun pelajaran
+func (r *KenaikanKelasRepositoryImpl) CountPesertaDidikRombel(tahunPelajaranID uint) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.PesertaDidikRombel{}).Where("tahun_pelajaran_id = ?", tahunPelajaranID).Count(&total).Error
+	return total, err
+}
+
+// Proses creates the rombel members of the target year, marks lulus students and revokes their sessions,
+// activates the target year and saves the rollover with its details, all in one transaction
+func (r *KenaikanKelasRepositoryImpl) Proses(data *models.KenaikanKelas) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var total int64
+		if err := tx.Model(&models.PesertaDidikRombel{}).Where("tahun_pelajaran_id = ?", data.TahunPelajaranTujuanID).Count(&total).Error; err != nil {
+			return err
+		}
+		if total > 0 {
+			return ErrTahunPelajaranTujuanTerisi
+		}
+
+		for i := range data.Detail {
+			detail := &data.Detail[i]
+			if detail.Keputusan == models.KeputusanLulus {
+				if err := tx.Model(&models.PesertaDidik{}).Where("id = ?", detail.PesertaDidikID).Updates(map[string]interface{}{
+					"status":          "lulus",
+					"updated_by_id":   data.CreatedByID,
+					"updated_by_type": data.CreatedByType,
+				}).Error; err != nil {
+					return err
+				}
+				// A lulus student can no longer log in, end the sessions that are still open
+				if err := revokeAllForPrincipal(tx, utils.PrincipalSiswa, detail.PesertaDidikID); err != nil {
+					return err
+				}
+				continue
+			}
+
+			member := &models.PesertaDidikRombel{
+				PesertaDidikID:   detail.PesertaDidikID,
+				RombelID:         *detail.RombelTujuanID,
+				TahunPelajaranID: data.TahunPelajaranTujuanID,
+				Status:           "active",
+				CreatedByID:      data.CreatedByID,
+				CreatedByType:    data.CreatedByType,
+			}
+			if err := tx.Create(member).Error; err != nil {
+				return err
+			}
+			detail.PesertaDidikRombelTujuanID = &member.ID
+		}
+
+		if err := tx.Model(&models.TahunPelajaran{}).Where("status = ?", "active").Update("status", "inactive").Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.TahunPelajaran{}).Where("id = ?", data.TahunPelajaranTujuanID).Update("status", "active").Error; err != nil {
+			return err
+		}
+
+		return tx.Create(data).Error
+	})
+}
+
+// Batalkan removes the rombel members created by a rollover, restores the status of lulus students and the
+// previously active year, and marks the rollover as undone, all in one transaction. The rollover row is locked
+// and re-checked so concurrent requests cannot undo it twice or undo one that is no longer the latest.
+func (r *KenaikanKelasRepositoryImpl) Batalkan(data *models.KenaikanKelas, actorID *uint, actorType *string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current models.KenaikanKelas
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, data.ID).Error; err != nil {
+			return err
+		}
+		if current.Status != models.KenaikanKelasStatusDiproses {
+			return ErrKenaikanKelasDibatalkan
+		}
+		var latest models.KenaikanKelas
+		if err := tx.Where("status = ?", models.KenaikanKelasStatusDiproses).Order("diproses_at DESC, id DESC").First(&latest).Error; err != nil {
+			return err
+		}
+		if latest.ID != current.ID {
+			return ErrKenaikanKelasBukanTerakhir
+		}
+
+		var memberIDs []uint
+		for _, detail := range data.Detail {
+			if detail.PesertaDidikRombelTujuanID != nil {
+				memberIDs = append(memberIDs, *detail.PesertaDidikRombelTujuanID)
+			}
+		}
+
+		if len(memberIDs) > 0 {
+			var dipakai int64
+			if err := tx.Table("rekapitulasi_absensi").Where("peserta_didik_rombel_id IN ?", memberIDs).Count(&dipakai).Error; err != nil {
+				return err
+			}
+			if dipakai == 0 {
+				if err := tx.Model(&models.PengajuanIzin{}).Where("peserta_didik_rombel_id IN ?", memberIDs).Count(&dipakai).Error; err != nil {
+					return err
+				}
+			}
+			if dipakai > 0 {
+				return ErrKenaikanKelasSudahDipakai
+			}
+
+			if err := tx.Unscoped().Where("id IN ?", memberIDs).Delete(&models.PesertaDidikRombel{}).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, detail := range data.Detail {
+			if detail.Keputusan != models.KeputusanLulus {
+				continue
+			}
+			if err := tx.Model(&models.PesertaDidik{}).Where("id = ?", detail.PesertaDidikID).Updates(map[string]interface{}{
+				"status":          detail.StatusPesertaDidikSebelumnya,
+				"updated_by_id":   actorID,
+				"updated_by_type": actorType,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		if data.TahunPelajaranAktifSebelumnyaID != nil {
+			if err := tx.Model(&models.TahunPelajaran{}).Where("status = ?", "active").Update("status", "inactive").Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.TahunPelajaran{}).Where("id = ?", *data.TahunPelajaranAktifSebelumnyaID).Update("status", "active").Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		return tx.Model(data).Updates(map[string]interface{}{
+			"status":          models.KenaikanKelasStatusDibatalkan,
+			"dibatalkan_at":   now,
+			"updated_by_id":   actorID,
+			"updated_by_type": actorType,
+		}).Error
+	})
+}
+
+// GetByID retrieves a rollover with its tahun pelajaran and details
+func (r *KenaikanKelasRepositoryImpl) GetByID(id uint) (*models.KenaikanKelas, error) {
+	var data models.KenaikanKelas
+	if err := r.db.Preload("TahunPelajaranAsal").Preload("TahunPelajaranTujuan").Preload("Detail").First(&data, id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetLatestDiproses retrieves the most recent rollover that has not been undone
+func (r *KenaikanKelasRepositoryImpl) GetLatestDiproses() (*models.KenaikanKelas, error) {
+	var data models.KenaikanKelas
+	if err := r.db.Where("status = ?", models.KenaikanKelasStatusDiproses).Order("diproses_at DESC, id DESC").First(&data).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetAll retrieves the rollover history, newest first
+func (r *KenaikanKelasRepositoryImpl) GetAll(limit, offset int) ([]models.KenaikanKelas, int64, error) {
+	var data []models.KenaikanKelas
+	var total int64
+
+	if err := r.db.Model(&models.KenaikanKelas{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.db.Preload("TahunPelajaranAsal").Preload("TahunPelajaranTujuan").
+		Order("diproses_at DESC, id DESC").
+		Limit(limit).Offset(offset).
+		Find(&data).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return data, total, nil
+}
@@ -47,6 +47,7 @@ type KepegawaianRepository interface {
 	GetRombelsByIDs(ids []uint) ([]models.Rombel, error)
 	GetPublicPendidikData() ([]models.Kepegawaian, error)
 	GetPublicTendikData() ([]models.Kepegawaian, error)
+	UpdateBarcode(kepegawaianID uint, barcode string) error
 }
 
 type KepegawaianRepositoryImpl struct {
@@ -320,3 +321,13 @@ func (r *KepegawaianRepositoryImpl) GetPublicTendikData() ([]models.Kepegawaian,
 	
 	return data, nil
 }
+
+// UpdateBarcode updates barcode and barcode_generated_at for a kepegawaian
+func (r *KepegawaianRepositoryImpl) UpdateBarcode(kepegawaianID uint, barcode string) error {
+	return r.db.Model(&models.Kepegawaian{}).
+		Where("id = ?", kepegawaianID).
+		Updates(map[string]interface{}{
+			"barcode":              barcode,
+			"barcode_generated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}).Error
+}
@@ -1,7 +1,11 @@
 package repositories
 
 import (
+	"errors"
+	"time"
+
 	"pintu-backend/src/modules/models"
+	"pintu-backend/src/utils"
 
 	"gorm.io/gorm"
 )
@@ -10,6 +14,13 @@ import (
 type LoginRepository interface {
 	GetByUsername(username string) (*models.User, error)
 	GetKepegawaianByUsername(username string) (*models.Kepegawaian, error)
+	GetByID(id uint) (*models.User, error)
+	GetKepegawaianByID(id uint) (*models.Kepegawaian, error)
+	GetPesertaDidikByUsername(username string) (*models.PesertaDidik, error)
+	GetPesertaDidikByID(id uint) (*models.PesertaDidik, error)
+	IncrementFailedLogins(principalType string, id uint) (int, error)
+	Lock(principalType string, id uint, until time.Time) error
+	Unlock(principalType string, id uint) error
 }
 
 type LoginRepositoryImpl struct {
@@ -38,3 +49,95 @@ func (r *LoginRepositoryImpl) GetKepegawaianByUsername(username string) (*models
 	}
 	return &kepegawaian, nil
 }
+
+// GetByID retrieves user by ID with roles and permissions
+func (r *LoginRepositoryImpl) GetByID(id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.Preload("Roles.System").Preload("Roles.Permissions").First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetKepegawaianByID retrieves kepegawaian by ID with roles and permissions
+func (r *LoginRepositoryImpl) GetKepegawaianByID(id uint) (*models.Kepegawaian, error) {
+	var kepegawaian models.Kepegawaian
+	if err := r.db.Preload("Roles.System").Preload("Roles.Permissions").First(&kepegawaian, id).Error; err != nil {
+		return nil, err
+	}
+	return &kepegawaian, nil
+}
+
+// GetPesertaDidikByUsername retrieves peserta didik by username
+func (r *LoginRepositoryImpl) GetPesertaDidikByUsername(username string) (*models.PesertaDidik, error) {
+	var pesertaDidik models.PesertaDidik
+	if err := r.db.Where("username = ? AND username <> ''", username).First(&pesertaDidik).Error; err != nil {
+		return nil, err
+	}
+	return &pesertaDidik, nil
+}
+
+// GetPesertaDidikByID retrieves peserta didik by ID
+func (r *LoginRepositoryImpl) GetPesertaDidikByID(id uint) (*models.PesertaDidik, error) {
+	var pesertaDidik models.PesertaDidik
+	if err := r.db.First(&pesertaDidik, id).Error; err != nil {
+		return nil, err
+	}
+	return &pesertaDidik, nil
+}
+
+// IncrementFailedLogins adds one failed login to the account and returns the new count
+func (r *LoginRepositoryImpl) IncrementFailedLogins(principalType string, id uint) (int, error) {
+	model, err := accountModel(principalType)
+	if err != nil {
+		return 0, err
+	}
+
+	var attempts int
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(model).Where("id = ?", id).
+			UpdateColumn("failed_login_attempts", gorm.Expr("failed_login_attempts + 1")).Error; err != nil {
+			return err
+		}
+		return tx.Model(model).Where("id = ?", id).Pluck("failed_login_attempts", &attempts).Error
+	})
+	return attempts, err
+}
+
+// Lock locks the account until the given time and resets its failed login counter
+func (r *LoginRepositoryImpl) Lock(principalType string, id uint, until time.Time) error {
+	model, err := accountModel(principalType)
+	if err != nil {
+		return err
+	}
+	return r.db.Model(model).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"failed_login_attempts": 0,
+		"locked_until":          until,
+	}).Error
+}
+
+// Unlock clears the lock and the failed login counter of the account
+func (r *LoginRepositoryImpl) Unlock(principalType string, id uint) error {
+	model, err := accountModel(principalType)
+	if err != nil {
+		return err
+	}
+	return r.db.Model(model).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"failed_login_attempts": 0,
+		"locked_until":          nil,
+	}).Error
+}
+
+// accountModel returns the model backing a login principal type
+func accountModel(principalType string) (interface{}, error) {
+	switch principalType {
+	case utils.PrincipalUser:
+		return &models.User{}, nil
+	case utils.PrincipalPegawai:
+		return &models.Kepegawaian{}, nil
+	case utils.PrincipalSiswa:
+		return &models.PesertaDidik{}, nil
+	default:
+		return nil, errors.New("unsupported principal type")
+	}
+}
@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"time"
+
+	"pintu-backend/src/modules/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetNotifikasiOutboxFilter represents filter parameters for GetOutboxWithFilter
+type GetNotifikasiOutboxFilter struct {
+	Status         string
+	Event          string
+	Channel        string
+	PesertaDidikID *uint
+}
+
+// GetNotifikasiOutboxParams represents parameters for GetOutboxWithFilter
+type GetNotifikasiOutboxParams struct {
+	Filter GetNotifikasiOutboxFilter
+	Limit  int
+	Offset int
+}
+
+// NotifikasiRepository handles data operations for notifikasi templates and the outbox
+type NotifikasiRepository interface {
+	GetActiveTemplatesByEvent(event string) ([]models.NotifikasiTemplate, error)
+	GetAllTemplates() ([]models.NotifikasiTemplate, error)
+	GetTemplateByID(id uint) (*models.NotifikasiTemplate, error)
+	UpdateTemplate(data *models.NotifikasiTemplate) error
+	GetRombelAktifName(pesertaDidikID uint) (string, error)
+	EnqueueOutbox(data *models.NotifikasiOutbox) (bool, error)
+	ClaimDueOutbox(limit int, lease time.Duration) ([]models.NotifikasiOutbox, error)
+	UpdateOutbox(data *models.NotifikasiOutbox) error
+	GetOutboxByID(id uint) (*models.NotifikasiOutbox, error)
+	GetOutboxWithFilter(params GetNotifikasiOutboxParams) ([]models.NotifikasiOutbox, int64, error)
+}
+
+type NotifikasiRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewNotifikasiRepository creates a new Notifikasi repository
+func NewNotifikasiRepository(db *gorm.DB) NotifikasiRepository {
+	return &NotifikasiRepositoryImpl{db: db}
+}
+
+// GetActiveTemplatesByEvent retrieves the active templates of an event, one per channel
+func (r *NotifikasiRepositoryImpl) GetActiveTemplatesByEvent(event string) ([]models.NotifikasiTemplate, error) {
+	var data []models.NotifikasiTemplate
+	if err := r.db.Where("event = ? AND status = ?", event, "active").Order("channel").Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// GetAllTemplates retrieves every template ordered by event and channel
+func (r *NotifikasiRepositoryImpl) GetAllTemplates() ([]models.NotifikasiTemplate, error) {
+	var data []models.NotifikasiTemplate
+	if err := r.db.Order("event, channel").Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// GetTemplateByID retrieves NotifikasiTemplate by ID
+func (r *NotifikasiRepositoryImpl) GetTemplateByID(id uint) (*models.NotifikasiTemplate, error) {
+	var data models.NotifikasiTemplate
+	if err := r.db.First(&data, id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// UpdateTemplate updates NotifikasiTemplate record
+func (r *NotifikasiRepositoryImpl) UpdateTemplate(data *models.NotifikasiTemplate) error {
+	return r.db.Save(data).Error
+}
+
+// GetRombelAktifName returns the rombel name of the peserta didik in the active tahun pelajaran
+func (r *NotifikasiRepositoryImpl) GetRombelAktifName(pesertaDidikID uint) (string, error) {
+	var name string
+	err := r.db.Table("peserta_didik_rombel AS prd").
+		Select("rb.name").
+		Joins("JOIN rombel rb ON rb.id = prd.rombel_id").
+		Joins("JOIN tahun_pelajaran tp ON tp.id = prd.tahun_pelajaran_id AND tp.status = 'active'").
+		Where("prd.peserta_didik_id = ? AND prd.deleted_at IS NULL", pesertaDidikID).
+		Order("prd.id DESC").
+		Limit(1).
+		Scan(&name).Error
+	return name, err
+}
+
+// EnqueueOutbox stores a message unless one with the same dedupe key exists; reports whether it was stored
+func (r *NotifikasiRepositoryImpl) EnqueueOutbox(data *models.NotifikasiOutbox) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "dedupe_key"}},
+		DoNothing: true,
+	}).Create(data)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ClaimDueOutbox leases up to limit pending messages that are due by pushing next_attempt_at forward,
+// so concurrent workers skip them. A message whose worker dies is picked up again once the lease ends.
+func (r *NotifikasiRepositoryImpl) ClaimDueOutbox(limit int, lease time.Duration) ([]models.NotifikasiOutbox, error) {
+	var data []models.NotifikasiOutbox
+	err := r.db.Raw(`
+		UPDATE notifikasi_outbox
+		SET next_attempt_at = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id IN (
+			SELECT id FROM notifikasi_outbox
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		time.Now().Add(lease), models.NotifikasiStatusPending, time.Now(), limit,
+	).Scan(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// UpdateOutbox updates NotifikasiOutbox record
+func (r *NotifikasiRepositoryImpl) UpdateOutbox(data *models.NotifikasiOutbox) error {
+	return r.db.Omit("PesertaDidik").Save(data).Error
+}
+
+// GetOutboxByID retrieves NotifikasiOutbox by ID
+func (r *NotifikasiRepositoryImpl) GetOutboxByID(id uint) (*models.NotifikasiOutbox, error) {
+	var data models.NotifikasiOutbox
+	if err := r.db.Preload("PesertaDidik").First(&data, id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetOutboxWithFilter retrieves NotifikasiOutbox records with filters and pagination
+func (r *NotifikasiRepositoryImpl) GetOutboxWithFilter(params GetNotifikasiOutboxParams) ([]models.NotifikasiOutbox, int64, error) {
+	var data []models.NotifikasiOutbox
+	var total int64
+
+	query := r.db
+
+	// Apply filters
+	if params.Filter.Status != "" {
+		query = query.Where("status = ?", params.Filter.Status)
+	}
+	if params.Filter.Event != "" {
+		query = query.Where("event = ?", params.Filter.Event)
+	}
+	if params.Filter.Channel != "" {
+		query = query.Where("channel = ?", params.Filter.Channel)
+	}
+	if params.Filter.PesertaDidikID != nil {
+		query = query.Where("peserta_didik_id = ?", *params.Filter.PesertaDidikID)
+	}
+
+	// Get total count
+	if err := query.Model(&models.NotifikasiOutbox{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get paginated data ordered by created_at DESC
+	if err := query.Preload("PesertaDidik").Order("created_at DESC").Limit(params.Limit).Offset(params.Offset).Find(&data).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return data, total, nil
+}
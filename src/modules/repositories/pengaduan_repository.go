@@ -15,7 +15,7 @@ type PengaduanRepository interface {
 	GetByIDTiket(idTiket string) (*models.Pengaduan, error)
 	GetAllWithFilter(params GetPengaduanParams) ([]models.Pengaduan, int64, error)
 	Update(data *models.Pengaduan) error
-	SoftDeleteWithUser(id uint, userID uint) error
+	SoftDeleteWithUser(id uint, userID uint, userType *string) error
 }
 
 // GetPengaduanFilter represents filter parameters
@@ -76,11 +76,12 @@ func (r *PengaduanRepositoryImpl) Update(data *models.Pengaduan) error {
 	return r.db.Save(data).Error
 }
 
-// SoftDeleteWithUser soft deletes Pengaduan and sets deleted_by_id
-func (r *PengaduanRepositoryImpl) SoftDeleteWithUser(id uint, userID uint) error {
+// SoftDeleteWithUser soft deletes Pengaduan and sets deleted_by_id and deleted_by_type
+func (r *PengaduanRepositoryImpl) SoftDeleteWithUser(id uint, userID uint, userType *string) error {
 	return r.db.Model(&models.Pengaduan{}).Where("id = ?", id).Updates(map[string]interface{}{
-		"deleted_at":    time.Now(),
-		"deleted_by_id": userID,
+		"deleted_at":      time.Now(),
+		"deleted_by_id":   userID,
+		"deleted_by_type": userType,
 	}).Error
 }
 
@@ -0,0 +1,219 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"pintu-backend/src/modules/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrPengajuanIzinSudahDireview is returned when a pengajuan izin is no longer pending at review time
+var ErrPengajuanIzinSudahDireview = errors.New("pengajuan izin sudah direview")
+
+// GetPengajuanIzinFilter represents filter parameters for GetAllWithFilter
+type GetPengajuanIzinFilter struct {
+	IDTiket          string
+	Status           string
+	Jenis            string
+	RombelID         *uint
+	PesertaDidikID   *uint
+	TahunPelajaranID *uint
+	TanggalMulai     *time.Time // Requests that end on or after this date
+	TanggalSelesai   *time.Time // Requests that start on or before this date
+}
+
+// GetPengajuanIzinParams represents parameters for GetAllWithFilter
+type GetPengajuanIzinParams struct {
+	Filter GetPengajuanIzinFilter
+	Limit  int
+	Offset int
+}
+
+// PengajuanIzinRepository handles data operations for PengajuanIzin
+type PengajuanIzinRepository interface {
+	Create(data *models.PengajuanIzin) error
+	GetByID(id uint) (*models.PengajuanIzin, error)
+	GetByIDTiket(idTiket string) (*models.PengajuanIzin, error)
+	GetAllWithFilter(params GetPengajuanIzinParams) ([]models.PengajuanIzin, int64, error)
+	HasOverlap(pesertaDidikID uint, tanggalMulai, tanggalSelesai time.Time) (bool, error)
+	Update(data *models.PengajuanIzin) error
+	Approve(data *models.PengajuanIzin, rows []models.RekapitulasiAbsensi, audit AuditRekapAbsensi) (int, []time.Time, error)
+	Reject(data *models.PengajuanIzin) error
+}
+
+type PengajuanIzinRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPengajuanIzinRepository creates a new PengajuanIzin repository
+func NewPengajuanIzinRepository(db *gorm.DB) PengajuanIzinRepository {
+	return &PengajuanIzinRepositoryImpl{db: db}
+}
+
+// Create creates a new PengajuanIzin record
+func (r *PengajuanIzinRepositoryImpl) Create(data *models.PengajuanIzin) error {
+	return r.db.Create(data).Error
+}
+
+// GetByID retrieves PengajuanIzin by ID
+func (r *PengajuanIzinRepositoryImpl) GetByID(id uint) (*models.PengajuanIzin, error) {
+	var data models.PengajuanIzin
+	if err := r.db.Preload("PesertaDidik").Preload("Rombel").First(&data, id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetByIDTiket retrieves PengajuanIzin by ID Tiket
+func (r *PengajuanIzinRepositoryImpl) GetByIDTiket(idTiket string) (*models.PengajuanIzin, error) {
+	var data models.PengajuanIzin
+	if err := r.db.Preload("PesertaDidik").Preload("Rombel").Where("id_tiket = ?", idTiket).First(&data).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetAllWithFilter retrieves PengajuanIzin records with filters and pagination, oldest pending first
+func (r *PengajuanIzinRepositoryImpl) GetAllWithFilter(params GetPengajuanIzinParams) ([]models.PengajuanIzin, int64, error) {
+	var data []models.PengajuanIzin
+	var total int64
+
+	query := r.db.Model(&models.PengajuanIzin{})
+
+	// Apply filters
+	if params.Filter.IDTiket != "" {
+		query = query.Where("id_tiket ILIKE ?", "%"+params.Filter.IDTiket+"%")
+	}
+	if params.Filter.Status != "" {
+		query = query.Where("status = ?", params.Filter.Status)
+	}
+	if params.Filter.Jenis != "" {
+		query = query.Where("jenis = ?", params.Filter.Jenis)
+	}
+	if params.Filter.RombelID != nil {
+		query = query.Where("rombel_id = ?", *params.Filter.RombelID)
+	}
+	if params.Filter.PesertaDidikID != nil {
+		query = query.Where("peserta_didik_id = ?", *params.Filter.PesertaDidikID)
+	}
+	if params.Filter.TahunPelajaranID != nil {
+		query = query.Where("tahun_pelajaran_id = ?", *params.Filter.TahunPelajaranID)
+	}
+	if params.Filter.TanggalMulai != nil {
+		query = query.Where("tanggal_selesai >= ?", params.Filter.TanggalMulai.Format("2006-01-02"))
+	}
+	if params.Filter.TanggalSelesai != nil {
+		query = query.Where("tanggal_mulai <= ?", params.Filter.TanggalSelesai.Format("2006-01-02"))
+	}
+
+	// Get total count
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Pending requests first so the queue reads top-down, then by submission time
+	if err := query.Preload("PesertaDidik").Preload("Rombel").
+		Order("CASE WHEN status = 'pending' THEN 0 ELSE 1 END, created_at ASC").
+		Limit(params.Limit).Offset(params.Offset).
+		Find(&data).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return data, total, nil
+}
+
+// HasOverlap reports whether the student already has a pending or approved request overlapping the range
+func (r *PengajuanIzinRepositoryImpl) HasOverlap(pesertaDidikID uint, tanggalMulai, tanggalSelesai time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.PengajuanIzin{}).
+		Where("peserta_didik_id = ? AND status IN ?", pesertaDidikID,
+			[]string{models.PengajuanIzinStatusPending, models.PengajuanIzinStatusApproved}).
+		Where("tanggal_mulai <= ? AND tanggal_selesai >= ?", tanggalSelesai.Format("2006-01-02"), tanggalMulai.Format("2006-01-02")).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Update updates PengajuanIzin record
+func (r *PengajuanIzinRepositoryImpl) Update(data *models.PengajuanIzin) error {
+	return r.db.Omit("PesertaDidik", "Rombel").Save(data).Error
+}
+
+// Approve stores the reviewed pengajuan and writes its guru kelas rekap rows in one transaction.
+// A row written by the alpa job is replaced; any other row on the date (scan, manual entry by a guru)
+// is kept and its date returned as skipped. Every written or replaced row is recorded in the rekap history.
+// Fails with ErrPengajuanIzinSudahDireview when another reviewer got there first.
+func (r *PengajuanIzinRepositoryImpl) Approve(data *models.PengajuanIzin, rows []models.RekapitulasiAbsensi, audit AuditRekapAbsensi) (int, []time.Time, error) {
+	written := 0
+	var skipped []time.Time
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var current models.PengajuanIzin
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, data.ID).Error; err != nil {
+			return err
+		}
+		if current.Status != models.PengajuanIzinStatusPending {
+			return ErrPengajuanIzinSudahDireview
+		}
+
+		for i := range rows {
+			row := rows[i]
+
+			var existing models.RekapitulasiAbsensi
+			err := tx.Where("peserta_didik_rombel_id = ? AND tanggal = ? AND bidang_studi_id IS NULL", row.PesertaDidikRombelID, row.Tanggal).
+				First(&existing).Error
+			switch {
+			case err == nil && existing.MetodeInput == models.MetodeInputAlpaOtomatis:
+				if err := HapusRekapAlpaOtomatis(tx, &existing, audit); err != nil {
+					return err
+				}
+			case err == nil:
+				skipped = append(skipped, row.Tanggal)
+				continue
+			case !errors.Is(err, gorm.ErrRecordNotFound):
+				return err
+			}
+
+			if err := tx.Create(&row).Error; err != nil {
+				return err
+			}
+			if err := CatatRiwayatRekapAbsensi(tx, nil, &row, audit); err != nil {
+				return err
+			}
+			written++
+		}
+
+		data.JumlahHariTercatat = written
+		return tx.Omit("PesertaDidik", "Rombel").Save(data).Error
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return written, skipped, nil
+}
+
+// Reject stores the rejection only while the pengajuan is still pending, so it cannot overwrite a concurrent
+// approval. Fails with ErrPengajuanIzinSudahDireview when another reviewer got there first.
+func (r *PengajuanIzinRepositoryImpl) Reject(data *models.PengajuanIzin) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.PengajuanIzin{}).
+			Where("id = ? AND status = ?", data.ID, models.PengajuanIzinStatusPending).
+			Updates(map[string]interface{}{
+				"status":           data.Status,
+				"catatan_review":   data.CatatanReview,
+				"reviewed_by_id":   data.ReviewedByID,
+				"reviewed_by_type": data.ReviewedByType,
+				"reviewed_at":      data.ReviewedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrPengajuanIzinSudahDireview
+		}
+		return nil
+	})
+}
@@ -31,7 +31,10 @@ func (r *PengumumanKelulusanRepositoryImpl) Create(data *models.PengumumanKelulu
 // GetByID retrieves PengumumanKelulusan by ID
 func (r *PengumumanKelulusanRepositoryImpl) GetByID(id uint) (*models.PengumumanKelulusan, error) {
 	var data models.PengumumanKelulusan
-	if err := r.db.Preload("CreatedBy").Preload("UpdatedBy").First(&data, id).Error; err != nil {
+	if err := r.db.First(&data, id).Error; err != nil {
+		return nil, err
+	}
+	if err := resolvePrincipalRefs(r.db, pengumumanKelulusanAudits(&data)); err != nil {
 		return nil, err
 	}
 	return &data, nil
@@ -46,8 +49,16 @@ func (r *PengumumanKelulusanRepositoryImpl) Update(data *models.PengumumanKelulu
 func (r *PengumumanKelulusanRepositoryImpl) GetFirst() (*models.PengumumanKelulusan, error) {
 	var data models.PengumumanKelulusan
 	// Always get record with ID = 1
-	if err := r.db.Preload("CreatedBy").Preload("UpdatedBy").Where("id = ?", 1).First(&data).Error; err != nil {
+	if err := r.db.Where("id = ?", 1).First(&data).Error; err != nil {
+		return nil, err
+	}
+	if err := resolvePrincipalRefs(r.db, pengumumanKelulusanAudits(&data)); err != nil {
 		return nil, err
 	}
 	return &data, nil
 }
+
+// pengumumanKelulusanAudits returns the audit columns of a PengumumanKelulusan
+func pengumumanKelulusanAudits(data *models.PengumumanKelulusan) []principalAudit {
+	return auditPrincipals(data.CreatedByID, data.CreatedByType, &data.CreatedBy, data.UpdatedByID, data.UpdatedByType, &data.UpdatedBy)
+}
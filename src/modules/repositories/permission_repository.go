@@ -1,7 +1,9 @@
 package repositories
 
 import (
+	"fmt"
 	"pintu-backend/src/modules/models"
+	"pintu-backend/src/utils"
 	"strings"
 
 	"gorm.io/gorm"
@@ -18,6 +20,7 @@ type PermissionRepository interface {
 	GetByIDs(ids []uint) ([]models.Permission, error)
 	Update(data *models.Permission) error
 	Delete(id uint) error
+	GetNamesByActiveRole(principalType string, id uint, roleID uint, systemID uint) ([]string, error)
 }
 
 // GetPermissionsFilter represents filters for getting permissions
@@ -138,13 +141,14 @@ func (r *PermissionRepositoryImpl) GetByIDs(ids []uint) ([]models.Permission, er
 // Update updates Permission record
 func (r *PermissionRepositoryImpl) Update(data *models.Permission) error {
 	result := r.db.Model(&models.Permission{}).Where("id = ?", data.ID).Updates(map[string]interface{}{
-		"name":           data.Name,
-		"description":    data.Description,
-		"group_name":     data.GroupName,
-		"system_id":      data.SystemID,
-		"status":         data.Status,
-		"updated_by_id":  data.UpdatedByID,
-		"updated_at":     data.UpdatedAt,
+		"name":            data.Name,
+		"description":     data.Description,
+		"group_name":      data.GroupName,
+		"system_id":       data.SystemID,
+		"status":          data.Status,
+		"updated_by_id":   data.UpdatedByID,
+		"updated_by_type": data.UpdatedByType,
+		"updated_at":      data.UpdatedAt,
 	})
 	return result.Error
 }
@@ -153,3 +157,36 @@ func (r *PermissionRepositoryImpl) Update(data *models.Permission) error {
 func (r *PermissionRepositoryImpl) Delete(id uint) error {
 	return r.db.Delete(&models.Permission{}, id).Error
 }
+
+// GetNamesByActiveRole retrieves the permission names granted by the session's active
+// role. Returns nothing when the principal no longer holds the role or the role belongs
+// to another system.
+func (r *PermissionRepositoryImpl) GetNamesByActiveRole(principalType string, id uint, roleID uint, systemID uint) ([]string, error) {
+	var pivotTable, pivotColumn string
+	switch principalType {
+	case utils.PrincipalUser:
+		pivotTable, pivotColumn = "user_roles", "user_id"
+	case utils.PrincipalPegawai:
+		pivotTable, pivotColumn = "kepegawaian_roles", "kepegawaian_id"
+	case utils.PrincipalSiswa:
+		// Siswa do not hold PINTU roles
+		return []string{}, nil
+	default:
+		return nil, fmt.Errorf("unknown principal type: %s", principalType)
+	}
+
+	var names []string
+	err := r.db.Table("permissions").
+		Distinct("permissions.name").
+		Joins("INNER JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("INNER JOIN roles ON roles.id = role_permissions.role_id").
+		Joins("INNER JOIN "+pivotTable+" ON "+pivotTable+".role_id = roles.id").
+		Where(pivotTable+"."+pivotColumn+" = ?", id).
+		Where("roles.id = ? AND roles.system_id = ?", roleID, systemID).
+		Where("roles.deleted_at IS NULL AND permissions.deleted_at IS NULL").
+		Pluck("permissions.name", &names).Error
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
@@ -16,7 +16,7 @@ type PertanyaanRepository interface {
 	GetAll() ([]models.Pertanyaan, error)
 	Update(data *models.Pertanyaan) error
 	Delete(id uint) error
-	SoftDeleteWithUser(id uint, userID uint) error
+	SoftDeleteWithUser(id uint, userID uint, userType *string) error
 }
 
 // GetPertanyaanFilter represents filter parameters
@@ -90,11 +90,12 @@ func (r *PertanyaanRepositoryImpl) Delete(id uint) error {
 	return r.db.Delete(&models.Pertanyaan{}, id).Error
 }
 
-// SoftDeleteWithUser soft deletes Pertanyaan and sets deleted_by_id
-func (r *PertanyaanRepositoryImpl) SoftDeleteWithUser(id uint, userID uint) error {
+// SoftDeleteWithUser soft deletes Pertanyaan and sets deleted_by_id and deleted_by_type
+func (r *PertanyaanRepositoryImpl) SoftDeleteWithUser(id uint, userID uint, userType *string) error {
 	return r.db.Model(&models.Pertanyaan{}).Where("id = ?", id).Updates(map[string]interface{}{
-		"deleted_at":    time.Now(),
-		"deleted_by_id": userID,
+		"deleted_at":      time.Now(),
+		"deleted_by_id":   userID,
+		"deleted_by_type": userType,
 	}).Error
 }
 
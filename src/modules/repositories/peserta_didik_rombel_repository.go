@@ -73,12 +73,13 @@ func (r *PesertaDidikRombelRepositoryImpl) GetByID(id uint) (*models.PesertaDidi
 func (r *PesertaDidikRombelRepositoryImpl) Update(data *models.PesertaDidikRombel) error {
 	// Use Updates with Select to update only specific fields and avoid association issues
 	return r.db.Model(data).
-		Select("RombelID", "TahunPelajaranID", "Status", "UpdatedByID", "UpdatedAt").
+		Select("RombelID", "TahunPelajaranID", "Status", "UpdatedByID", "UpdatedByType", "UpdatedAt").
 		Updates(map[string]interface{}{
-			"rombel_id":         data.RombelID,
+			"rombel_id":          data.RombelID,
 			"tahun_pelajaran_id": data.TahunPelajaranID,
-			"status":            data.Status,
-			"updated_by_id":     data.UpdatedByID,
+			"status":             data.Status,
+			"updated_by_id":      data.UpdatedByID,
+			"updated_by_type":    data.UpdatedByType,
 		}).Error
 }
 
@@ -38,6 +38,7 @@ type PrestasiRepository interface {
 	GetByID(id uint) (*models.Prestasi, error)
 	GetAll(limit int, offset int) ([]models.Prestasi, int64, error)
 	GetAllWithFilter(params GetPrestasiParams) ([]models.Prestasi, int64, error)
+	GetByPesertaDidikID(pesertaDidikID uint) ([]models.Prestasi, error)
 	GetPublicLatest() ([]models.Prestasi, error)
 	GetPublicList(sort string, offset int) ([]models.Prestasi, int64, error)
 	GetPublicDetailByID(id uint) (*models.Prestasi, error)
@@ -194,6 +195,33 @@ func (r *PrestasiRepositoryImpl) GetAllWithFilter(params GetPrestasiParams) ([]m
 	return data, total, nil
 }
 
+// GetByPesertaDidikID retrieves active Prestasi won by a student, individually or as a team member
+func (r *PrestasiRepositoryImpl) GetByPesertaDidikID(pesertaDidikID uint) ([]models.Prestasi, error) {
+	var data []models.Prestasi
+	mappingIDs := r.db.Model(&models.PesertaDidikRombel{}).Select("id").Where("peserta_didik_id = ?", pesertaDidikID)
+	teamPrestasiIDs := r.db.Model(&models.AnggotaTimPrestasi{}).Select("prestasi_id").Where("peserta_didik_rombel_id IN (?)", mappingIDs)
+
+	if err := r.db.Where("status = ?", "active").
+		Where("peserta_didik_rombel_id IN (?) OR id IN (?)", mappingIDs, teamPrestasiIDs).
+		Preload("PesertaDidikRombel").
+		Preload("PesertaDidikRombel.PesertaDidik").
+		Preload("PesertaDidikRombel.Rombel").
+		Preload("PesertaDidikRombel.Rombel.Kelas").
+		Preload("PesertaDidikRombel.TahunPelajaran").
+		Preload("Ekstrakurikuler").
+		Preload("TahunPelajaran").
+		Preload("AnggotaTimPrestasi").
+		Preload("AnggotaTimPrestasi.PesertaDidikRombel").
+		Preload("AnggotaTimPrestasi.PesertaDidikRombel.PesertaDidik").
+		Preload("AnggotaTimPrestasi.PesertaDidikRombel.Rombel").
+		Preload("AnggotaTimPrestasi.PesertaDidikRombel.Rombel.Kelas").
+		Preload("AnggotaTimPrestasi.PesertaDidikRombel.TahunPelajaran").
+		Order("tanggal_lomba DESC").Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
 // Update updates Prestasi record
 func (r *PrestasiRepositoryImpl) Update(data *models.Prestasi) error {
 	return r.db.Save(data).Error
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/utils"
+
+	"gorm.io/gorm"
+)
+
+// principalTables maps each principal type to the table holding its nama
+var principalTables = map[string]string{
+	utils.PrincipalUser:    "users",
+	utils.PrincipalPegawai: "kepegawaian",
+	utils.PrincipalSiswa:   "peserta_didik",
+}
+
+// principalAudit points an audit column pair at the field its resolved principal is written to
+type principalAudit struct {
+	ID   *uint
+	Type *string
+	Ref  **models.PrincipalRef
+}
+
+// auditPrincipals returns the created_by and updated_by columns of one record
+func auditPrincipals(createdByID *uint, createdByType *string, createdBy **models.PrincipalRef,
+	updatedByID *uint, updatedByType *string, updatedBy **models.PrincipalRef) []principalAudit {
+	return []principalAudit{
+		{ID: createdByID, Type: createdByType, Ref: createdBy},
+		{ID: updatedByID, Type: updatedByType, Ref: updatedBy},
+	}
+}
+
+// resolvePrincipalRefs fills each audit's Ref with one query per principal type. Columns written
+// before the principal type was recorded carry no type and stay unresolved, since their ID alone
+// does not say which table it belongs to. Soft deleted principals are still named.
+func resolvePrincipalRefs(db *gorm.DB, audits []principalAudit) error {
+	idsByType := make(map[string][]uint)
+	for _, audit := range audits {
+		if audit.ID == nil || audit.Type == nil {
+			continue
+		}
+		if _, ok := principalTables[*audit.Type]; ok {
+			idsByType[*audit.Type] = append(idsByType[*audit.Type], *audit.ID)
+		}
+	}
+
+	names := make(map[string]map[uint]string, len(idsByType))
+	for principalType, ids := range idsByType {
+		var rows []struct {
+			ID   uint
+			Nama string
+		}
+		if err := db.Table(principalTables[principalType]).Select("id, nama").Where("id IN ?", ids).Scan(&rows).Error; err != nil {
+			return err
+		}
+		names[principalType] = make(map[uint]string, len(rows))
+		for _, row := range rows {
+			names[principalType][row.ID] = row.Nama
+		}
+	}
+
+	for _, audit := range audits {
+		if audit.ID == nil || audit.Type == nil {
+			continue
+		}
+		if nama, ok := names[*audit.Type][*audit.ID]; ok {
+			*audit.Ref = &models.PrincipalRef{ID: *audit.ID, Type: *audit.Type, Nama: nama}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"time"
+
+	"pintu-backend/src/modules/models"
+
+	"gorm.io/gorm"
+)
+
+// AuditRekapAbsensi identifies who changed a rekap row, through which code path and from where
+type AuditRekapAbsensi struct {
+	Sumber    string // models.SumberRiwayatRekap*
+	ActorID   *uint
+	ActorType *string
+	ActorNama string
+	IPAddress string
+}
+
+// CatatRiwayatRekapAbsensi appends a history entry for a rekap mutation using the caller's transaction:
+// sebelum nil is a create, sesudah nil is a delete
+func CatatRiwayatRekapAbsensi(tx *gorm.DB, sebelum, sesudah *models.RekapitulasiAbsensi, audit AuditRekapAbsensi) error {
+	row := sesudah
+	aksi := models.AksiRiwayatRekapUpdate
+	switch {
+	case sebelum == nil:
+		aksi = models.AksiRiwayatRekapCreate
+	case sesudah == nil:
+		aksi = models.AksiRiwayatRekapDelete
+		row = sebelum
+	}
+
+	riwayat := &models.RiwayatRekapitulasiAbsensi{
+		RekapitulasiAbsensiID: row.ID,
+		PesertaDidikRombelID:  row.PesertaDidikRombelID,
+		Tanggal:               row.Tanggal,
+		BidangStudiID:         row.BidangStudiID,
+		Aksi:                  aksi,
+		Sumber:                audit.Sumber,
+		ActorID:               audit.ActorID,
+		ActorType:             audit.ActorType,
+		ActorNama:             audit.ActorNama,
+		IPAddress:             audit.IPAddress,
+	}
+	if sebelum != nil {
+		riwayat.StatusSebelum = &sebelum.Status
+		riwayat.MetodeInputSebelum = &sebelum.MetodeInput
+		riwayat.KeteranganSebelum = &sebelum.Keterangan
+		riwayat.FileSuratSebelum = &sebelum.FileSurat
+		riwayat.WaktuAbsenSebelum = sebelum.WaktuAbsen
+	}
+	if sesudah != nil {
+		riwayat.StatusSesudah = &sesudah.Status
+		riwayat.MetodeInputSesudah = &sesudah.MetodeInput
+		riwayat.KeteranganSesudah = &sesudah.Keterangan
+		riwayat.FileSuratSesudah = &sesudah.FileSurat
+		riwayat.WaktuAbsenSesudah = sesudah.WaktuAbsen
+	}
+
+	return tx.Create(riwayat).Error
+}
+
+// HapusRekapAlpaOtomatis deletes a row of the alpa job that is being replaced and records the delete in the
+// history, using the caller's transaction
+func HapusRekapAlpaOtomatis(tx *gorm.DB, existing *models.RekapitulasiAbsensi, audit AuditRekapAbsensi) error {
+	if err := tx.Unscoped().Delete(existing).Error; err != nil {
+		return err
+	}
+	return CatatRiwayatRekapAbsensi(tx, existing, nil, audit)
+}
+
+// RiwayatRekapitulasiAbsensiRepository reads the rekap history, entries are only written through CatatRiwayatRekapAbsensi
+type RiwayatRekapitulasiAbsensiRepository interface {
+	GetBySiswaTanggal(pesertaDidikRombelID uint, tanggal time.Time, bidangStudiID *uint) ([]models.RiwayatRekapitulasiAbsensi, error)
+}
+
+type RiwayatRekapitulasiAbsensiRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRiwayatRekapitulasiAbsensiRepository creates a new RiwayatRekapitulasiAbsensi repository
+func NewRiwayatRekapitulasiAbsensiRepository(db *gorm.DB) RiwayatRekapitulasiAbsensiRepository {
+	return &RiwayatRekapitulasiAbsensiRepositoryImpl{db: db}
+}
+
+// GetBySiswaTanggal retrieves the history of a student-day, oldest first. Without bidangStudiID both the guru kelas
+// and every mapel row of the day are returned.
+func (r *RiwayatRekapitulasiAbsensiRepositoryImpl) GetBySiswaTanggal(pesertaDidikRombelID uint, tanggal time.Time, bidangStudiID *uint) ([]models.RiwayatRekapitulasiAbsensi, error) {
+	var data []models.RiwayatRekapitulasiAbsensi
+	query := r.db.Where("peserta_didik_rombel_id = ? AND tanggal = ?", pesertaDidikRombelID, tanggal)
+	if bidangStudiID != nil {
+		query = query.Where("bidang_studi_id = ?", *bidangStudiID)
+	}
+	if err := query.Order("created_at ASC, id ASC").Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -101,12 +101,13 @@ func (r *RoleRepositoryImpl) GetAllWithFilter(params GetRolesParams) ([]models.R
 // Update updates Role record
 func (r *RoleRepositoryImpl) Update(data *models.Role) error {
 	result := r.db.Model(&models.Role{}).Where("id = ?", data.ID).Updates(map[string]interface{}{
-		"name":           data.Name,
-		"description":    data.Description,
-		"system_id":      data.SystemID,
-		"status":         data.Status,
-		"updated_by_id":  data.UpdatedByID,
-		"updated_at":     data.UpdatedAt,
+		"name":            data.Name,
+		"description":     data.Description,
+		"system_id":       data.SystemID,
+		"status":          data.Status,
+		"updated_by_id":   data.UpdatedByID,
+		"updated_by_type": data.UpdatedByType,
+		"updated_at":      data.UpdatedAt,
 	})
 	return result.Error
 }
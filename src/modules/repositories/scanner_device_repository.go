@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"time"
+
+	"pintu-backend/src/modules/models"
+
+	"gorm.io/gorm"
+)
+
+// GetScannerDeviceFilter represents filter parameters for GetAllWithFilter
+type GetScannerDeviceFilter struct {
+	Nama   string
+	Status string
+}
+
+// GetScannerDeviceParams represents parameters for GetAllWithFilter
+type GetScannerDeviceParams struct {
+	Filter GetScannerDeviceFilter
+	Limit  int
+	Offset int
+}
+
+// ScannerDeviceRepository handles data operations for ScannerDevice
+type ScannerDeviceRepository interface {
+	Create(data *models.ScannerDevice) error
+	GetByID(id uint) (*models.ScannerDevice, error)
+	GetAllWithFilter(params GetScannerDeviceParams) ([]models.ScannerDevice, int64, error)
+	Update(data *models.ScannerDevice) error
+	Delete(id uint) error
+	TouchLastSeen(id uint, ipAddress string) error
+}
+
+type ScannerDeviceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewScannerDeviceRepository creates a new ScannerDevice repository
+func NewScannerDeviceRepository(db *gorm.DB) ScannerDeviceRepository {
+	return &ScannerDeviceRepositoryImpl{db: db}
+}
+
+// Create creates a new ScannerDevice record
+func (r *ScannerDeviceRepositoryImpl) Create(data *models.ScannerDevice) error {
+	return r.db.Create(data).Error
+}
+
+// GetByID retrieves ScannerDevice by ID
+func (r *ScannerDeviceRepositoryImpl) GetByID(id uint) (*models.ScannerDevice, error) {
+	var data models.ScannerDevice
+	if err := r.db.First(&data, id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetAllWithFilter retrieves ScannerDevice records with filters and pagination
+func (r *ScannerDeviceRepositoryImpl) GetAllWithFilter(params GetScannerDeviceParams) ([]models.ScannerDevice, int64, error) {
+	var data []models.ScannerDevice
+	var total int64
+
+	query := r.db
+
+	// Apply filters
+	if params.Filter.Nama != "" {
+		query = query.Where("nama ILIKE ?", "%"+params.Filter.Nama+"%")
+	}
+	if params.Filter.Status != "" {
+		query = query.Where("status = ?", params.Filter.Status)
+	}
+
+	// Get total count
+	if err := query.Model(&models.ScannerDevice{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get paginated data ordered by created_at DESC
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Offset).Find(&data).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return data, total, nil
+}
+
+// Update updates ScannerDevice record
+func (r *ScannerDeviceRepositoryImpl) Update(data *models.ScannerDevice) error {
+	return r.db.Save(data).Error
+}
+
+// Delete deletes ScannerDevice record by ID
+func (r *ScannerDeviceRepositoryImpl) Delete(id uint) error {
+	return r.db.Delete(&models.ScannerDevice{}, id).Error
+}
+
+// TouchLastSeen records when and from where the device last made an authenticated request
+func (r *ScannerDeviceRepositoryImpl) TouchLastSeen(id uint, ipAddress string) error {
+	return r.db.Model(&models.ScannerDevice{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"last_seen_at": time.Now(),
+			"last_seen_ip": ipAddress,
+		}).Error
+}
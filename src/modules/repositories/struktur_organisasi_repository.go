@@ -54,6 +54,9 @@ func (r *StrukturOrganisasiRepositoryImpl) GetByID(id uint) (*models.StrukturOrg
 	if err := r.db.Preload("Pegawai").First(&data, id).Error; err != nil {
 		return nil, err
 	}
+	if err := resolvePrincipalRefs(r.db, strukturOrganisasiAudits(&data)); err != nil {
+		return nil, err
+	}
 	return &data, nil
 }
 
@@ -71,6 +74,9 @@ func (r *StrukturOrganisasiRepositoryImpl) GetAll(limit int, offset int) ([]mode
 	if err := r.db.Preload("Pegawai").Order("created_at DESC").Limit(limit).Offset(offset).Find(&data).Error; err != nil {
 		return nil, 0, err
 	}
+	if err := resolveStrukturOrganisasiPrincipals(r.db, data); err != nil {
+		return nil, 0, err
+	}
 
 	return data, total, nil
 }
@@ -117,14 +123,31 @@ func (r *StrukturOrganisasiRepositoryImpl) GetAllWithFilter(params GetStrukturOr
 	if err := query.Order("struktur_organisasi.created_at DESC").Limit(params.Limit).Offset(params.Offset).Find(&data).Error; err != nil {
 		return nil, 0, err
 	}
+	if err := resolveStrukturOrganisasiPrincipals(r.db, data); err != nil {
+		return nil, 0, err
+	}
 
 	return data, total, nil
 }
 
+// strukturOrganisasiAudits returns the audit columns of a StrukturOrganisasi
+func strukturOrganisasiAudits(data *models.StrukturOrganisasi) []principalAudit {
+	return auditPrincipals(data.CreatedByID, data.CreatedByType, &data.CreatedBy, data.UpdatedByID, data.UpdatedByType, &data.UpdatedBy)
+}
+
+// resolveStrukturOrganisasiPrincipals resolves the audit principals of a page in one query per type
+func resolveStrukturOrganisasiPrincipals(db *gorm.DB, data []models.StrukturOrganisasi) error {
+	var audits []principalAudit
+	for i := range data {
+		audits = append(audits, strukturOrganisasiAudits(&data[i])...)
+	}
+	return resolvePrincipalRefs(db, audits)
+}
+
 // Update updates StrukturOrganisasi record
 func (r *StrukturOrganisasiRepositoryImpl) Update(data *models.StrukturOrganisasi) error {
 	return r.db.Model(data).
-		Select("pegawai_id", "nama_non_pegawai", "jabatan_non_pegawai", "urutan", "relasi", "status", "updated_by_id", "updated_at").
+		Select("pegawai_id", "nama_non_pegawai", "jabatan_non_pegawai", "urutan", "relasi", "status", "updated_by_id", "updated_by_type", "updated_at").
 		Save(data).Error
 }
 
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"pintu-backend/src/modules/models"
+	"pintu-backend/src/utils"
 	"strings"
 
 	"gorm.io/gorm"
@@ -11,6 +12,8 @@ import (
 type SystemRepository interface {
 	Create(data *models.System) error
 	GetByID(id uint) (*models.System, error)
+	GetByCode(code string) (*models.System, error)
+	GetIDByCode(code string) (uint, error)
 	GetAll() ([]models.System, error)
 	GetAllWithFilter(params GetSystemsParams) ([]models.System, int64, error)
 	Update(data *models.System) error
@@ -53,6 +56,30 @@ func (r *SystemRepositoryImpl) GetByID(id uint) (*models.System, error) {
 	return &data, nil
 }
 
+// GetByCode retrieves System by code
+func (r *SystemRepositoryImpl) GetByCode(code string) (*models.System, error) {
+	var data models.System
+	if err := r.db.Where("code = ?", code).First(&data).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// GetIDByCode resolves the System ID for a code, cached until systems change
+func (r *SystemRepositoryImpl) GetIDByCode(code string) (uint, error) {
+	if id, ok := utils.GetCachedSystemID(code); ok {
+		return id, nil
+	}
+
+	data, err := r.GetByCode(code)
+	if err != nil {
+		return 0, err
+	}
+
+	utils.SetCachedSystemID(code, data.ID)
+	return data.ID, nil
+}
+
 // GetAll retrieves all System records sorted by created_at DESC
 func (r *SystemRepositoryImpl) GetAll() ([]models.System, error) {
 	var data []models.System
@@ -93,11 +120,13 @@ func (r *SystemRepositoryImpl) GetAllWithFilter(params GetSystemsParams) ([]mode
 // Update updates System record
 func (r *SystemRepositoryImpl) Update(data *models.System) error {
 	result := r.db.Model(&models.System{}).Where("id = ?", data.ID).Updates(map[string]interface{}{
-		"nama":           data.Nama,
-		"description":    data.Description,
-		"status":         data.Status,
-		"updated_by_id":  data.UpdatedByID,
-		"updated_at":     data.UpdatedAt,
+		"nama":            data.Nama,
+		"code":            data.Code,
+		"description":     data.Description,
+		"status":          data.Status,
+		"updated_by_id":   data.UpdatedByID,
+		"updated_by_type": data.UpdatedByType,
+		"updated_at":      data.UpdatedAt,
 	})
 	return result.Error
 }
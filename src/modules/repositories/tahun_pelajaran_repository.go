@@ -27,6 +27,7 @@ type TahunPelajaranRepository interface {
 	GetAllWithFilter(params GetTahunPelajaranParams) ([]models.TahunPelajaran, int64, error)
 	GetByTahunPelajaran(tahunPelajaran string) (*models.TahunPelajaran, error)
 	GetActiveAcademicYear() (*models.TahunPelajaran, error)
+	GetAllWithSemester() ([]models.TahunPelajaran, error)
 	Update(data *models.TahunPelajaran) error
 	Delete(id uint) error
 	UpdateAllStatusToInactive() error
@@ -91,6 +92,17 @@ func (r *TahunPelajaranRepositoryImpl) GetActiveAcademicYear() (*models.TahunPel
 	return &data, nil
 }
 
+// GetAllWithSemester retrieves every TahunPelajaran whose semester dates are set, oldest first
+func (r *TahunPelajaranRepositoryImpl) GetAllWithSemester() ([]models.TahunPelajaran, error) {
+	var data []models.TahunPelajaran
+	if err := r.db.Where("semester1_mulai IS NOT NULL AND semester1_selesai IS NOT NULL AND semester2_mulai IS NOT NULL AND semester2_selesai IS NOT NULL").
+		Order("semester1_mulai ASC").
+		Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
 // Update updates TahunPelajaran record
 func (r *TahunPelajaranRepositoryImpl) Update(data *models.TahunPelajaran) error {
 	return r.db.Save(data).Error
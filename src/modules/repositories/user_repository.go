@@ -125,12 +125,13 @@ func (r *UserRepositoryImpl) GetAllWithFilter(params GetUsersParams) ([]models.U
 func (r *UserRepositoryImpl) Update(data *models.User) error {
 	// Use Update with map to explicitly set fields
 	result := r.db.Model(&models.User{}).Where("id = ?", data.ID).Updates(map[string]interface{}{
-		"nama":          data.Nama,
-		"username":      data.Username,
-		"password":      data.Password,
-		"status":        data.Status,
-		"updated_by_id": data.UpdatedByID,
-		"updated_at":    data.UpdatedAt,
+		"nama":            data.Nama,
+		"username":        data.Username,
+		"password":        data.Password,
+		"status":          data.Status,
+		"updated_by_id":   data.UpdatedByID,
+		"updated_by_type": data.UpdatedByType,
+		"updated_at":      data.UpdatedAt,
 	})
 	return result.Error
 }
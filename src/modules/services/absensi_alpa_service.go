@@ -0,0 +1,229 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+)
+
+// ErrJamDatangBelumSelesai is returned when today's run starts before jam_datang_selesai
+var ErrJamDatangBelumSelesai = errors.New("jam datang belum selesai, coba lagi setelah jam_datang_selesai")
+
+// AbsensiAlpaService marks active students without any attendance record as alpa
+type AbsensiAlpaService interface {
+	Run(req *dtos.AbsensiAlpaRunRequest) (*dtos.AbsensiAlpaReport, error)
+}
+
+type AbsensiAlpaServiceImpl struct {
+	repository             repositories.AbsensiAlpaRepository
+	periodeService         PeriodeAkademikService
+	konfigurasiAbsensiRepo repositories.KonfigurasiAbsensiRepository
+	kalenderService        KalenderAkademikService
+	notifikasiService      NotifikasiService
+}
+
+// NewAbsensiAlpaService creates a new AbsensiAlpa service
+func NewAbsensiAlpaService(
+	repository repositories.AbsensiAlpaRepository,
+	periodeService PeriodeAkademikService,
+	konfigurasiAbsensiRepo repositories.KonfigurasiAbsensiRepository,
+	kalenderService KalenderAkademikService,
+	notifikasiService NotifikasiService,
+) AbsensiAlpaService {
+	return &AbsensiAlpaServiceImpl{
+		repository:             repository,
+		periodeService:         periodeService,
+		konfigurasiAbsensiRepo: konfigurasiAbsensiRepo,
+		kalenderService:        kalenderService,
+		notifikasiService:      notifikasiService,
+	}
+}
+
+// Run writes alpa rows for the date for every active rombel member of the date's tahun pelajaran that has
+// no guru kelas rekap row (hadir, izin, sakit or alpa) and no datang scan. Only runs on effective school
+// days, and for today only after jam_datang_selesai.
+func (s *AbsensiAlpaServiceImpl) Run(req *dtos.AbsensiAlpaRunRequest) (*dtos.AbsensiAlpaReport, error) {
+	startedAt := time.Now().In(utils.JakartaLocation())
+	today := dateOnly(startedAt)
+
+	tanggal := today
+	if req.Tanggal != "" {
+		parsed, err := time.Parse("2006-01-02", req.Tanggal)
+		if err != nil {
+			return nil, errors.New("format tanggal tidak valid, gunakan YYYY-MM-DD")
+		}
+		tanggal = parsed
+	}
+	if tanggal.After(today) {
+		return nil, errors.New("tanggal tidak boleh di masa depan")
+	}
+
+	trigger := req.Trigger
+	if trigger == "" {
+		trigger = "cli"
+	}
+
+	report := &dtos.AbsensiAlpaReport{
+		Tanggal:   tanggal.Format("2006-01-02"),
+		Trigger:   trigger,
+		DryRun:    req.DryRun,
+		StartedAt: startedAt.Format(time.RFC3339),
+	}
+	finish := func(message string) *dtos.AbsensiAlpaReport {
+		report.Message = message
+		report.FinishedAt = time.Now().In(utils.JakartaLocation()).Format(time.RFC3339)
+		return report
+	}
+
+	// 1. Only effective school days
+	hariSekolah, keterangan, err := s.kalenderService.CekHariSekolah(tanggal)
+	if err != nil {
+		return nil, err
+	}
+	report.HariSekolah = hariSekolah
+	if !hariSekolah {
+		report.Keterangan = keterangan
+		return finish(fmt.Sprintf("Bukan hari sekolah (%s), tidak ada siswa yang ditandai alpa", keterangan)), nil
+	}
+
+	// 2. Students can still scan in until jam_datang_selesai
+	config, err := s.konfigurasiAbsensiRepo.GetByID(1)
+	if err != nil {
+		return nil, errors.New("konfigurasi absensi belum diatur")
+	}
+	if tanggal.Equal(today) && startedAt.Format("15:04:05") < normalizeJam(config.JamDatangSelesai) {
+		return nil, ErrJamDatangBelumSelesai
+	}
+
+	// 3. Tahun pelajaran and semester of the date, and its members
+	periode, err := s.periodeService.ResolvePeriode(tanggal)
+	if err != nil {
+		return nil, err
+	}
+	semester := periode.Semester
+	report.TahunPelajaranID = periode.TahunPelajaranID
+	report.Semester = semester
+
+	members, err := s.repository.GetActivePesertaDidikRombel(periode.TahunPelajaranID)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data siswa")
+	}
+	report.TotalSiswa = len(members)
+
+	tercatatIDs, err := s.repository.GetTercatatPesertaDidikRombelIDs(periode.TahunPelajaranID, tanggal)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data rekapitulasi absensi")
+	}
+	tercatat := make(map[uint]bool, len(tercatatIDs))
+	for _, id := range tercatatIDs {
+		tercatat[id] = true
+	}
+
+	scannedIDs, err := s.repository.GetScannedPesertaDidikIDs(tanggal)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data absensi scan")
+	}
+	scanned := make(map[uint]bool, len(scannedIDs))
+	for _, id := range scannedIDs {
+		scanned[id] = true
+	}
+
+	// Alpa is still written for a pending pengajuan izin (approval replaces it), but the parent who
+	// asked for leave is not told their child is absent without notice
+	pengajuanIDs, err := s.repository.GetPengajuanIzinPendingPesertaDidikIDs(tanggal)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data pengajuan izin")
+	}
+	pengajuanPending := make(map[uint]bool, len(pengajuanIDs))
+	for _, id := range pengajuanIDs {
+		pengajuanPending[id] = true
+	}
+
+	// 4. Mark everyone else alpa
+	for _, member := range members {
+		if tercatat[member.ID] {
+			report.TotalSudahTercatat++
+			continue
+		}
+
+		detail := dtos.AbsensiAlpaDetailItem{
+			PesertaDidikRombelID: member.ID,
+			PesertaDidikID:       member.PesertaDidikID,
+		}
+		if member.PesertaDidik != nil {
+			detail.NIS = member.PesertaDidik.NIS
+			detail.Nama = member.PesertaDidik.Nama
+		}
+		if member.Rombel != nil {
+			detail.Rombel = member.Rombel.Name
+		}
+
+		// A scan that has not been synchronized yet is not an absence
+		if scanned[member.PesertaDidikID] {
+			report.TotalScanBelumSinkron++
+			continue
+		}
+
+		if req.DryRun {
+			detail.Action = "dry_run"
+			report.TotalAlpa++
+			report.Details = append(report.Details, detail)
+			continue
+		}
+
+		rombelID := member.RombelID
+		inserted, err := s.repository.CreateAlpaIfMissing(&models.RekapitulasiAbsensi{
+			PesertaDidikRombelID: member.ID,
+			RombelID:             &rombelID,
+			TahunPelajaranID:     periode.TahunPelajaranID,
+			Semester:             semester,
+			Tanggal:              tanggal,
+			Status:               "alpa",
+			MetodeInput:          models.MetodeInputAlpaOtomatis,
+			Keterangan:           "Tidak ada scan maupun keterangan izin/sakit",
+		})
+		switch {
+		case err != nil:
+			detail.Action = "failed"
+			detail.Reason = err.Error()
+			report.TotalGagal++
+		case !inserted:
+			// Recorded by a guru or another run in the meantime
+			detail.Action = "skipped"
+			detail.Reason = "sudah tercatat"
+			report.TotalSudahTercatat++
+		default:
+			detail.Action = "inserted"
+			report.TotalAlpa++
+
+			// Parents are only told about today, not about dates filled in afterwards
+			if tanggal.Equal(today) && !pengajuanPending[member.PesertaDidikID] {
+				if err := s.notifikasiService.NotifyAlpa(member.PesertaDidik, detail.Rombel, tanggal); err != nil {
+					log.Printf("notifikasi alpa peserta didik %d: %v", member.PesertaDidikID, err)
+				}
+			}
+		}
+		report.Details = append(report.Details, detail)
+	}
+
+	message := fmt.Sprintf("%d siswa ditandai alpa, %d sudah tercatat, %d scan belum disinkronkan, %d gagal",
+		report.TotalAlpa, report.TotalSudahTercatat, report.TotalScanBelumSinkron, report.TotalGagal)
+	if req.DryRun {
+		message = "Dry run: " + message
+	}
+	return finish(message), nil
+}
+
+// normalizeJam pads "HH:MM" to "HH:MM:SS" so times compare as strings
+func normalizeJam(jam string) string {
+	if len(jam) == 5 {
+		return jam + ":00"
+	}
+	return jam
+}
@@ -101,6 +101,19 @@ func (s *AbsensiServiceImpl) exportPDFGuruKelas(req *dtos.ExportAbsensiExcelRequ
 	endDate := startDate.AddDate(0, 1, -1)
 	daysInMonth := endDate.Day()
 
+	// Load kalender akademik to mark non-school days
+	kalender, err := s.kalenderService.LoadKalender(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	liburMap := make(map[int]bool)
+	for day := 1; day <= daysInMonth; day++ {
+		if ok, _ := kalender.IsHariSekolah(startDate.AddDate(0, 0, day-1)); !ok {
+			liburMap[day] = true
+		}
+	}
+	totalHariEfektif := daysInMonth - len(liburMap)
+
 	// Get all absensi data for this month
 	var absensiList []models.RekapitulasiAbsensi
 	if err := s.db.Where("rombel_id = ? AND tahun_pelajaran_id = ? AND bidang_studi_id IS NULL", req.RombelID, req.TahunPelajaranID).
@@ -133,7 +146,7 @@ func (s *AbsensiServiceImpl) exportPDFGuruKelas(req *dtos.ExportAbsensiExcelRequ
 	// Subtitle
 	bulanNama := []string{"", "JANUARI", "FEBRUARI", "MARET", "APRIL", "MEI", "JUNI",
 		"JULI", "AGUSTUS", "SEPTEMBER", "OKTOBER", "NOVEMBER", "DESEMBER"}
-	subtitle := fmt.Sprintf("BULAN %s TAHUN %d", bulanNama[*req.Bulan], *req.Tahun)
+	subtitle := fmt.Sprintf("BULAN %s TAHUN %d (%d HARI EFEKTIF)", bulanNama[*req.Bulan], *req.Tahun, totalHariEfektif)
 	pdf.CellFormat(287, 7, subtitle, "", 1, "C", false, 0, "")
 	pdf.Ln(2)
 
@@ -211,6 +224,9 @@ func (s *AbsensiServiceImpl) exportPDFGuruKelas(req *dtos.ExportAbsensiExcelRequ
 		// Calculate summary
 		totalSakit, totalIzin, totalAlpa := 0, 0, 0
 		for day := 1; day <= daysInMonth; day++ {
+			if liburMap[day] {
+				continue
+			}
 			status, _ := absensiMap[pdr.ID][day]
 			switch status {
 			case "sakit":
@@ -236,8 +252,13 @@ func (s *AbsensiServiceImpl) exportPDFGuruKelas(req *dtos.ExportAbsensiExcelRequ
 		}
 		pdf.CellFormat(plWidth, 6, jenisKelamin, "1", 0, "C", false, 0, "")
 
-		// Date columns
+		// Date columns (non-school days shaded and marked L)
+		pdf.SetFillColor(217, 217, 217)
 		for day := 1; day <= daysInMonth; day++ {
+			if liburMap[day] {
+				pdf.CellFormat(dateWidth, 6, "L", "1", 0, "C", true, 0, "")
+				continue
+			}
 			status, exists := absensiMap[pdr.ID][day]
 			mark := "-"
 			if exists {
@@ -306,7 +327,7 @@ func (s *AbsensiServiceImpl) exportPDFGuruKelas(req *dtos.ExportAbsensiExcelRequ
 	pdf.CellFormat(60, 6, fmt.Sprintf("NIP. %s", nipKepsek), "", 1, "C", false, 0, "")
 
 	var buf bytes.Buffer
-	err := pdf.Output(&buf)
+	err = pdf.Output(&buf)
 	if err != nil {
 		return nil, err
 	}
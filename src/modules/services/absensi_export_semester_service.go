@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"pintu-backend/src/dtos"
 	"pintu-backend/src/modules/models"
@@ -59,7 +60,13 @@ func (s *AbsensiServiceImpl) exportGuruBidangStudiSemester(req *dtos.ExportAbsen
 	
 	// Initialize all months for the semester (even if no data yet)
 	var semesterMonths []int
-	if *req.Semester == 1 {
+	if periode, err := s.periodeService.GetPeriode(req.TahunPelajaranID, *req.Semester); err == nil {
+		// Months covered by the semester dates of tahun pelajaran
+		bulanAkhir := time.Date(periode.TanggalSelesai.Year(), periode.TanggalSelesai.Month(), 1, 0, 0, 0, 0, time.UTC)
+		for d := time.Date(periode.TanggalMulai.Year(), periode.TanggalMulai.Month(), 1, 0, 0, 0, 0, time.UTC); !d.After(bulanAkhir) && len(semesterMonths) < 12; d = d.AddDate(0, 1, 0) {
+			semesterMonths = append(semesterMonths, int(d.Month()))
+		}
+	} else if *req.Semester == 1 {
 		// Semester 1: Juli (7) sampai Desember (12)
 		semesterMonths = []int{7, 8, 9, 10, 11, 12}
 	} else {
@@ -76,6 +76,19 @@ func (s *AbsensiServiceImpl) exportGuruKelas(req *dtos.ExportAbsensiExcelRequest
 	endDate := startDate.AddDate(0, 1, -1) // Last day of month
 	daysInMonth := endDate.Day()
 
+	// Load kalender akademik to mark non-school days
+	kalender, err := s.kalenderService.LoadKalender(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	liburMap := make(map[int]bool)
+	for day := 1; day <= daysInMonth; day++ {
+		if ok, _ := kalender.IsHariSekolah(startDate.AddDate(0, 0, day-1)); !ok {
+			liburMap[day] = true
+		}
+	}
+	totalHariEfektif := daysInMonth - len(liburMap)
+
 	// Get all students in rombel (only active students)
 	var pesertaDidikRombels []models.PesertaDidikRombel
 	if err := s.db.Preload("PesertaDidik", "status = ?", "active").
@@ -135,7 +148,7 @@ func (s *AbsensiServiceImpl) exportGuruKelas(req *dtos.ExportAbsensiExcelRequest
 	// Subtitle row (row 2)
 	bulanNama := []string{"", "JANUARI", "FEBRUARI", "MARET", "APRIL", "MEI", "JUNI",
 		"JULI", "AGUSTUS", "SEPTEMBER", "OKTOBER", "NOVEMBER", "DESEMBER"}
-	subtitle := fmt.Sprintf("BULAN %s TAHUN %d", bulanNama[*req.Bulan], *req.Tahun)
+	subtitle := fmt.Sprintf("BULAN %s TAHUN %d (%d HARI EFEKTIF)", bulanNama[*req.Bulan], *req.Tahun, totalHariEfektif)
 	lastColCell2, _ := excelize.CoordinatesToCellName(lastCol, 2)
 	f.SetCellValue(sheetName, "A2", subtitle)
 	f.MergeCell(sheetName, "A2", lastColCell2)
@@ -165,6 +178,18 @@ func (s *AbsensiServiceImpl) exportGuruKelas(req *dtos.ExportAbsensiExcelRequest
 		},
 	})
 
+	// Libur style (light gray background) for non-school days
+	liburStyle, _ := f.NewStyle(&excelize.Style{
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#D9D9D9"}, Pattern: 1},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+		Border: []excelize.Border{
+			{Type: "left", Color: "#000000", Style: 1},
+			{Type: "right", Color: "#000000", Style: 1},
+			{Type: "top", Color: "#000000", Style: 1},
+			{Type: "bottom", Color: "#000000", Style: 1},
+		},
+	})
+
 	// Header Row 1 (row 4): NO, NAMA SISWA, P/L, JULI (merged), JUMLAH ABSEN (merged), JUMLAH
 	headerRow1 := 4
 	f.SetCellValue(sheetName, "A4", "NO")
@@ -250,6 +275,13 @@ func (s *AbsensiServiceImpl) exportGuruKelas(req *dtos.ExportAbsensiExcelRequest
 			col := monthStartCol + day - 1
 			cell, _ := excelize.CoordinatesToCellName(col, row)
 			
+			// Non-school day (weekend, libur, outside semester)
+			if liburMap[day] {
+				f.SetCellValue(sheetName, cell, "L")
+				f.SetCellStyle(sheetName, cell, cell, liburStyle)
+				continue
+			}
+			
 			if status, exists := absensiMap[pdr.ID][day]; exists {
 				switch status {
 				case "hadir":
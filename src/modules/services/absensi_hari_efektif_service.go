@@ -0,0 +1,85 @@
+package services
+
+import (
+	"time"
+
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+)
+
+// hariEfektifPerTanggal loads kalender akademik for the requested range (or the span of the data when no
+// range is given), drops dates that are not school days and counts the school days in range up to today
+func (s *AbsensiServiceImpl) hariEfektifPerTanggal(perTanggal []repositories.AbsensiPeriodeCount, tanggalMulai, tanggalSelesai *time.Time) ([]repositories.AbsensiPeriodeCount, int, error) {
+	var mulai, selesai time.Time
+	if tanggalMulai != nil {
+		mulai = *tanggalMulai
+	}
+	if tanggalSelesai != nil {
+		selesai = *tanggalSelesai
+	}
+	for _, count := range perTanggal {
+		tanggal := dateOnly(count.Periode)
+		if tanggalMulai == nil && (mulai.IsZero() || tanggal.Before(mulai)) {
+			mulai = tanggal
+		}
+		if tanggalSelesai == nil && tanggal.After(selesai) {
+			selesai = tanggal
+		}
+	}
+	if mulai.IsZero() || selesai.IsZero() || selesai.Before(mulai) {
+		return perTanggal, 0, nil
+	}
+
+	kalender, err := s.kalenderService.LoadKalender(mulai, selesai)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Days that have not happened yet are not effective days yet
+	today := dateOnly(time.Now().In(utils.JakartaLocation()))
+	if selesai.After(today) {
+		selesai = today
+	}
+
+	totalHariEfektif := 0
+	if !selesai.Before(mulai) {
+		totalHariEfektif = len(kalender.HariEfektif(mulai, selesai))
+	}
+
+	filtered := make([]repositories.AbsensiPeriodeCount, 0, len(perTanggal))
+	for _, count := range perTanggal {
+		if ok, _ := kalender.IsHariSekolah(dateOnly(count.Periode)); ok {
+			filtered = append(filtered, count)
+		}
+	}
+
+	return filtered, totalHariEfektif, nil
+}
+
+// bukanHariSekolah lists the dates in range that are not school days, to leave them out of SQL aggregates
+func bukanHariSekolah(kalender *KalenderSekolah, tanggalMulai, tanggalSelesai time.Time) []time.Time {
+	var days []time.Time
+	for d := tanggalMulai; !d.After(tanggalSelesai); d = d.AddDate(0, 0, 1) {
+		if ok, _ := kalender.IsHariSekolah(d); !ok {
+			days = append(days, d)
+		}
+	}
+	return days
+}
+
+// filterHariSekolah drops rows recorded on dates that are not school days
+func filterHariSekolah(absensiList []models.RekapitulasiAbsensi, kalender *KalenderSekolah) []models.RekapitulasiAbsensi {
+	filtered := make([]models.RekapitulasiAbsensi, 0, len(absensiList))
+	for _, absensi := range absensiList {
+		if ok, _ := kalender.IsHariSekolah(dateOnly(absensi.Tanggal)); ok {
+			filtered = append(filtered, absensi)
+		}
+	}
+	return filtered
+}
+
+// dateOnly strips the time and location so dates compare as calendar days
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
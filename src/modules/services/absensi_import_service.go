@@ -0,0 +1,172 @@
+package services
+
+import (
+	"errors"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/utils"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// DownloadTemplateImport generates an Excel template for importing guru kelas rekap absensi
+func (s *AbsensiServiceImpl) DownloadTemplateImport() (*excelize.File, error) {
+	f := excelize.NewFile()
+	sheetName := "Sheet1"
+
+	headers := []string{"nis", "tanggal", "status", "keterangan"}
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue(sheetName, cell, header)
+	}
+
+	// Keep NIS and dates as text so they are read back exactly as typed
+	textStyle, _ := f.NewStyle(&excelize.Style{NumFmt: 49})
+	f.SetColStyle(sheetName, "A:B", textStyle)
+
+	examples := [][]interface{}{
+		{"2024001", "2026-10-12", "hadir", ""},
+		{"2024002", "2026-10-12", "sakit", "Demam"},
+	}
+	for rowIdx, example := range examples {
+		for colIdx, value := range example {
+			cell, _ := excelize.CoordinatesToCellName(colIdx+1, rowIdx+2)
+			f.SetCellValue(sheetName, cell, value)
+		}
+	}
+
+	f.SetColWidth(sheetName, "A", "C", 15)
+	f.SetColWidth(sheetName, "D", "D", 40)
+
+	return f, nil
+}
+
+// ImportExcel imports guru kelas rekap absensi of one rombel from an Excel file. A student-day that already has a
+// rekap row is updated, so the history keeps the values it replaces. Every write is recorded with sumber import.
+func (s *AbsensiServiceImpl) ImportExcel(file multipart.File, tahunPelajaranID, rombelID uint, actor utils.Principal, ipAddress string) (*dtos.AbsensiImportResponse, error) {
+	f, err := excelize.OpenReader(file)
+	if err != nil {
+		return nil, errors.New("gagal membuka file excel")
+	}
+	defer f.Close()
+
+	rows, err := f.GetRows("Sheet1")
+	if err != nil {
+		return nil, errors.New("gagal membaca Sheet1")
+	}
+
+	if len(rows) < 2 {
+		return nil, errors.New("file excel kosong atau tidak ada data")
+	}
+
+	// Find column indices
+	columns := map[string]int{}
+	for i, header := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(header))] = i
+	}
+	for _, required := range []string{"nis", "tanggal", "status"} {
+		if _, ok := columns[required]; !ok {
+			return nil, errors.New("kolom wajib tidak lengkap: nis, tanggal, status")
+		}
+	}
+
+	response := &dtos.AbsensiImportResponse{}
+	audit := auditRekapAbsensi(models.SumberRiwayatRekapImport, actor, ipAddress)
+	now := time.Now()
+
+	for i, row := range rows {
+		// Skip header row and empty rows
+		if i == 0 || len(row) == 0 {
+			continue
+		}
+		rowNum := i + 1
+
+		// Helper to safely get column value
+		getCol := func(name string) string {
+			idx, ok := columns[name]
+			if ok && idx < len(row) {
+				return strings.TrimSpace(row[idx])
+			}
+			return ""
+		}
+
+		nis := getCol("nis")
+		if nis == "" && getCol("tanggal") == "" {
+			continue
+		}
+		failed := func(message string) {
+			response.FailedCount++
+			response.Errors = append(response.Errors, dtos.AbsensiImportError{Row: rowNum, Message: message})
+		}
+
+		tanggal, err := time.Parse("2006-01-02", getCol("tanggal"))
+		if err != nil {
+			failed("format tanggal tidak valid, gunakan YYYY-MM-DD")
+			continue
+		}
+		status := strings.ToLower(getCol("status"))
+		switch status {
+		case "hadir", "sakit", "izin", "alpa":
+		default:
+			failed("status harus hadir, sakit, izin atau alpa")
+			continue
+		}
+
+		periode, err := s.periodeService.ResolvePeriode(tanggal)
+		if err != nil {
+			failed(err.Error())
+			continue
+		}
+		if periode.TahunPelajaranID != tahunPelajaranID {
+			failed("tanggal di luar tahun pelajaran yang dipilih")
+			continue
+		}
+
+		pesertaDidikRombel, err := s.repository.GetPesertaDidikRombelByNIS(rombelID, nis)
+		if err != nil {
+			failed("siswa dengan NIS " + nis + " tidak ditemukan di rombel")
+			continue
+		}
+
+		existing, _ := s.repository.GetByPesertaDidikTanggalMapel(pesertaDidikRombel.ID, tanggal, nil)
+		if existing != nil {
+			existing.Status = status
+			existing.Keterangan = getCol("keterangan")
+			existing.MetodeInput = "manual"
+			existing.DicatatOlehID = &actor.ID
+			existing.DicatatOlehType = actor.TypePtr()
+
+			if err := s.repository.Update(existing, audit); err != nil {
+				failed("gagal update: " + err.Error())
+				continue
+			}
+			response.UpdatedCount++
+			continue
+		}
+
+		absensi := &models.RekapitulasiAbsensi{
+			PesertaDidikRombelID: pesertaDidikRombel.ID,
+			RombelID:             &rombelID,
+			TahunPelajaranID:     tahunPelajaranID,
+			Semester:             periode.Semester,
+			Tanggal:              tanggal,
+			Status:               status,
+			WaktuAbsen:           &now,
+			MetodeInput:          "manual",
+			Keterangan:           getCol("keterangan"),
+			DicatatOlehID:        &actor.ID,
+			DicatatOlehType:      actor.TypePtr(),
+		}
+		if err := s.repository.Create(absensi, audit); err != nil {
+			failed("gagal menyimpan: " + err.Error())
+			continue
+		}
+		response.SuccessCount++
+	}
+
+	return response, nil
+}
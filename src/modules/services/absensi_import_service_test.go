@@ -0,0 +1,132 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// fakeAbsensiImportRepository keeps the students of one rombel by NIS and the rekap rows it writes
+type fakeAbsensiImportRepository struct {
+	repositories.AbsensiRepository
+	siswa  map[string]uint
+	rekap  []models.RekapitulasiAbsensi
+	audits []repositories.AuditRekapAbsensi
+}
+
+func (r *fakeAbsensiImportRepository) GetPesertaDidikRombelByNIS(rombelID uint, nis string) (*models.PesertaDidikRombel, error) {
+	id, ok := r.siswa[nis]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return &models.PesertaDidikRombel{ID: id, RombelID: rombelID}, nil
+}
+
+func (r *fakeAbsensiImportRepository) GetByPesertaDidikTanggalMapel(pesertaDidikRombelID uint, tanggal time.Time, bidangStudiID *uint) (*models.RekapitulasiAbsensi, error) {
+	for i := range r.rekap {
+		if r.rekap[i].PesertaDidikRombelID == pesertaDidikRombelID && r.rekap[i].Tanggal.Equal(tanggal) {
+			existing := r.rekap[i]
+			return &existing, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeAbsensiImportRepository) Create(data *models.RekapitulasiAbsensi, audit repositories.AuditRekapAbsensi) error {
+	data.ID = uint(len(r.rekap) + 1)
+	r.rekap = append(r.rekap, *data)
+	r.audits = append(r.audits, audit)
+	return nil
+}
+
+func (r *fakeAbsensiImportRepository) Update(data *models.RekapitulasiAbsensi, audit repositories.AuditRekapAbsensi) error {
+	for i := range r.rekap {
+		if r.rekap[i].ID == data.ID {
+			r.rekap[i] = *data
+		}
+	}
+	r.audits = append(r.audits, audit)
+	return nil
+}
+
+// excelUpload wraps an in-memory workbook as an uploaded multipart file
+type excelUpload struct {
+	*bytes.Reader
+}
+
+func (excelUpload) Close() error { return nil }
+
+func newExcelUpload(t *testing.T, rows [][]interface{}) excelUpload {
+	t.Helper()
+	f := excelize.NewFile()
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := f.SetSheetRow("Sheet1", cell, &row); err != nil {
+			t.Fatalf("SetSheetRow() error = %v", err)
+		}
+	}
+	buffer, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatalf("WriteToBuffer() error = %v", err)
+	}
+	return excelUpload{bytes.NewReader(buffer.Bytes())}
+}
+
+func TestImportExcelRekapAbsensi(t *testing.T) {
+	tanggal := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	repository := &fakeAbsensiImportRepository{
+		siswa: map[string]uint{"2024001": 10, "2024002": 11},
+		rekap: []models.RekapitulasiAbsensi{{ID: 1, PesertaDidikRombelID: 11, Tanggal: tanggal, Status: "alpa", MetodeInput: models.MetodeInputAlpaOtomatis}},
+	}
+	service := &AbsensiServiceImpl{repository: repository, periodeService: newTestPeriodeAkademikService()}
+	actor := utils.Principal{ID: 5, Type: utils.PrincipalPegawai, Nama: "Wali Kelas"}
+
+	file := newExcelUpload(t, [][]interface{}{
+		{"nis", "tanggal", "status", "keterangan"},
+		{"2024001", "2026-10-12", "hadir", ""},
+		{"2024002", "2026-10-12", "Sakit", "Demam"},
+		{"2024003", "2026-10-12", "hadir", ""},
+		{"2024001", "12/10/2026", "hadir", ""},
+		{"2024001", "2026-10-13", "bolos", ""},
+		{"2024001", "2026-12-28", "hadir", ""},
+		{"2024001", "2026-03-02", "hadir", ""},
+	})
+
+	response, err := service.ImportExcel(file, 2, 20, actor, "203.0.113.7")
+	if err != nil {
+		t.Fatalf("ImportExcel() error = %v", err)
+	}
+	if response.SuccessCount != 1 || response.UpdatedCount != 1 || response.FailedCount != 5 {
+		t.Fatalf("success/updated/failed = %d/%d/%d (%v), want 1/1/5", response.SuccessCount, response.UpdatedCount, response.FailedCount, response.Errors)
+	}
+	for i, wantRow := range []int{4, 5, 6, 7, 8} {
+		if response.Errors[i].Row != wantRow {
+			t.Errorf("error %d is for row %d, want row %d", i, response.Errors[i].Row, wantRow)
+		}
+	}
+
+	created := repository.rekap[1]
+	if created.PesertaDidikRombelID != 10 || created.Semester != 1 || created.TahunPelajaranID != 2 || *created.RombelID != 20 || created.BidangStudiID != nil {
+		t.Errorf("created rekap = siswa %d, tahun pelajaran %d semester %d, rombel %d, want siswa 10, 2 semester 1, rombel 20",
+			created.PesertaDidikRombelID, created.TahunPelajaranID, created.Semester, *created.RombelID)
+	}
+	updated := repository.rekap[0]
+	if updated.Status != "sakit" || updated.Keterangan != "Demam" || updated.MetodeInput != "manual" {
+		t.Errorf("updated rekap = %s/%q/%s, want sakit/\"Demam\"/manual", updated.Status, updated.Keterangan, updated.MetodeInput)
+	}
+
+	if len(repository.audits) != 2 {
+		t.Fatalf("audits = %d, want 2", len(repository.audits))
+	}
+	for _, audit := range repository.audits {
+		if audit.Sumber != models.SumberRiwayatRekapImport || *audit.ActorID != 5 || audit.IPAddress != "203.0.113.7" {
+			t.Errorf("audit = sumber %s, actor %v, ip %s, want import by 5 from 203.0.113.7", audit.Sumber, audit.ActorID, audit.IPAddress)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+)
+
+// AbsensiLiveService publishes successful scans to the in-process hub and serves today's counters per rombel
+type AbsensiLiveService interface {
+	PublishScan(event dtos.AbsensiLiveScanEvent)
+	GetRingkasanHariIni(rombelID *uint) (*dtos.AbsensiLiveRingkasanResponse, error)
+	Subscribe(rombelID *uint) (<-chan utils.AbsensiEvent, func())
+}
+
+type AbsensiLiveServiceImpl struct {
+	repository repositories.AbsensiLiveRepository
+	hub        *utils.AbsensiHub
+}
+
+// NewAbsensiLiveService creates a new AbsensiLive service
+func NewAbsensiLiveService(repository repositories.AbsensiLiveRepository, hub *utils.AbsensiHub) AbsensiLiveService {
+	return &AbsensiLiveServiceImpl{
+		repository: repository,
+		hub:        hub,
+	}
+}
+
+// PublishScan streams a scan and, for students with a rombel, the updated counters of that rombel.
+// Nothing is computed while no client is listening, and the counters are counted off the scan request.
+func (s *AbsensiLiveServiceImpl) PublishScan(event dtos.AbsensiLiveScanEvent) {
+	if s.hub.TotalSubscribers() == 0 {
+		return
+	}
+
+	s.hub.Publish(utils.AbsensiEvent{Name: "scan", RombelID: event.RombelID, Data: event})
+
+	if event.RombelID != nil {
+		go s.publishRingkasan(*event.RombelID)
+	}
+}
+
+// publishRingkasan streams today's counters of one rombel
+func (s *AbsensiLiveServiceImpl) publishRingkasan(rombelID uint) {
+	ringkasan, err := s.GetRingkasanHariIni(&rombelID)
+	if err != nil {
+		log.Printf("ringkasan live absensi rombel %d: %v", rombelID, err)
+		return
+	}
+	s.hub.Publish(utils.AbsensiEvent{Name: "ringkasan", RombelID: &rombelID, Data: ringkasan})
+}
+
+// GetRingkasanHariIni returns today's hadir and belum datang counters per rombel (Asia/Jakarta)
+func (s *AbsensiLiveServiceImpl) GetRingkasanHariIni(rombelID *uint) (*dtos.AbsensiLiveRingkasanResponse, error) {
+	today := time.Now().In(utils.JakartaLocation())
+
+	counts, err := s.repository.CountKehadiranPerRombel(today, rombelID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dtos.AbsensiLiveRingkasanItem, len(counts))
+	for i, count := range counts {
+		items[i] = dtos.AbsensiLiveRingkasanItem{
+			RombelID:    count.RombelID,
+			Rombel:      count.RombelNama,
+			TotalSiswa:  count.TotalSiswa,
+			Hadir:       count.Hadir,
+			TepatWaktu:  count.TepatWaktu,
+			Terlambat:   count.Terlambat,
+			BelumDatang: count.TotalSiswa - count.Hadir,
+			Pulang:      count.Pulang,
+		}
+	}
+
+	return &dtos.AbsensiLiveRingkasanResponse{
+		Tanggal: today.Format("2006-01-02"),
+		Data:    items,
+	}, nil
+}
+
+// Subscribe subscribes to the live events, optionally of one rombel only
+func (s *AbsensiLiveServiceImpl) Subscribe(rombelID *uint) (<-chan utils.AbsensiEvent, func()) {
+	return s.hub.Subscribe(rombelID)
+}
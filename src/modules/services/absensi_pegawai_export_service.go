@@ -0,0 +1,366 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"pintu-backend/src/dtos"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/xuri/excelize/v2"
+)
+
+// ExportExcel exports the monthly absensi pegawai to an Excel file
+func (s *AbsensiPegawaiServiceImpl) ExportExcel(req *dtos.AbsensiPegawaiRekapRequest) (*excelize.File, error) {
+	rekap, err := s.loadRekapBulanan(req)
+	if err != nil {
+		return nil, err
+	}
+
+	f := excelize.NewFile()
+	sheetName := "Daftar Hadir Pegawai"
+	f.SetSheetName("Sheet1", sheetName)
+
+	// Title row (row 1)
+	titleStyle, _ := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Size: 14},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+	})
+
+	// Total columns = 3 (NO, NAMA, NIP) + daysInMonth + 3 (H, T, TH)
+	monthStartCol := 4 // Column D
+	monthEndCol := monthStartCol + rekap.daysInMonth - 1
+	jumlahStartCol := monthEndCol + 1
+	lastCol := jumlahStartCol + 2
+	lastColCell, _ := excelize.CoordinatesToCellName(lastCol, 1)
+
+	f.SetCellValue(sheetName, "A1", judulExportPegawai(req.Kategori))
+	f.MergeCell(sheetName, "A1", lastColCell)
+	f.SetCellStyle(sheetName, "A1", lastColCell, titleStyle)
+
+	// Subtitle row (row 2)
+	subtitle := fmt.Sprintf("BULAN %s TAHUN %d (%d HARI EFEKTIF)", bulanNamaPegawai[rekap.bulan], rekap.tahun, rekap.daysInMonth-len(rekap.liburMap))
+	lastColCell2, _ := excelize.CoordinatesToCellName(lastCol, 2)
+	f.SetCellValue(sheetName, "A2", subtitle)
+	f.MergeCell(sheetName, "A2", lastColCell2)
+	f.SetCellStyle(sheetName, "A2", lastColCell2, titleStyle)
+
+	border := []excelize.Border{
+		{Type: "left", Color: "#000000", Style: 1},
+		{Type: "right", Color: "#000000", Style: 1},
+		{Type: "top", Color: "#000000", Style: 1},
+		{Type: "bottom", Color: "#000000", Style: 1},
+	}
+
+	// Header style (gray background, white text, bold, centered)
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Color: "#FFFFFF"},
+		Fill:      excelize.Fill{Type: "pattern", Color: []string{"#808080"}, Pattern: 1},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+		Border:    border,
+	})
+
+	// Data style (centered with border)
+	dataStyle, _ := f.NewStyle(&excelize.Style{
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+		Border:    border,
+	})
+
+	// Libur style (light gray background) for non-school days
+	liburStyle, _ := f.NewStyle(&excelize.Style{
+		Fill:      excelize.Fill{Type: "pattern", Color: []string{"#D9D9D9"}, Pattern: 1},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+		Border:    border,
+	})
+
+	// Header Row 1 (row 4): NO, NAMA, NIP, BULAN (merged), JUMLAH (merged)
+	headerRow1 := 4
+	headerRow2 := 5
+	f.SetCellValue(sheetName, "A4", "NO")
+	f.SetCellValue(sheetName, "B4", "NAMA")
+	f.SetCellValue(sheetName, "C4", "NIP")
+	f.MergeCell(sheetName, "A4", "A5")
+	f.MergeCell(sheetName, "B4", "B5")
+	f.MergeCell(sheetName, "C4", "C5")
+
+	monthStartCell, _ := excelize.CoordinatesToCellName(monthStartCol, headerRow1)
+	monthEndCell, _ := excelize.CoordinatesToCellName(monthEndCol, headerRow1)
+	f.SetCellValue(sheetName, monthStartCell, bulanNamaPegawai[rekap.bulan])
+	f.MergeCell(sheetName, monthStartCell, monthEndCell)
+
+	jumlahStartCell, _ := excelize.CoordinatesToCellName(jumlahStartCol, headerRow1)
+	jumlahEndCell, _ := excelize.CoordinatesToCellName(lastCol, headerRow1)
+	f.SetCellValue(sheetName, jumlahStartCell, "JUMLAH")
+	f.MergeCell(sheetName, jumlahStartCell, jumlahEndCell)
+
+	// Header Row 2 (row 5): dates 1-31, H, T, TH
+	for day := 1; day <= rekap.daysInMonth; day++ {
+		cell, _ := excelize.CoordinatesToCellName(monthStartCol+day-1, headerRow2)
+		f.SetCellValue(sheetName, cell, day)
+	}
+	for i, label := range []string{"H", "T", "TH"} {
+		cell, _ := excelize.CoordinatesToCellName(jumlahStartCol+i, headerRow2)
+		f.SetCellValue(sheetName, cell, label)
+	}
+
+	// Apply header styles to all header cells
+	for col := 1; col <= lastCol; col++ {
+		cell1, _ := excelize.CoordinatesToCellName(col, headerRow1)
+		cell2, _ := excelize.CoordinatesToCellName(col, headerRow2)
+		f.SetCellStyle(sheetName, cell1, cell1, headerStyle)
+		f.SetCellStyle(sheetName, cell2, cell2, headerStyle)
+	}
+
+	// Data rows starting from row 6
+	dataStartRow := 6
+	for idx, pegawai := range rekap.pegawai {
+		row := dataStartRow + idx
+
+		f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), idx+1)
+		f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), pegawai.Nama)
+		f.SetCellValue(sheetName, fmt.Sprintf("C%d", row), pegawai.NIP)
+
+		countH, countT, countTH := 0, 0, 0
+		for day := 1; day <= rekap.daysInMonth; day++ {
+			cell, _ := excelize.CoordinatesToCellName(monthStartCol+day-1, row)
+
+			// Non-school day (weekend, libur, outside semester)
+			if rekap.liburMap[day] {
+				f.SetCellValue(sheetName, cell, "L")
+				f.SetCellStyle(sheetName, cell, cell, liburStyle)
+				continue
+			}
+
+			status, _ := rekap.statusHarian(pegawai.ID, day)
+			f.SetCellValue(sheetName, cell, markAbsensiPegawai(status, "✓"))
+			switch status {
+			case "tepat_waktu":
+				countH++
+			case "terlambat":
+				countH++
+				countT++
+			case "tidak_hadir":
+				countTH++
+			}
+			f.SetCellStyle(sheetName, cell, cell, dataStyle)
+		}
+
+		for i, count := range []int{countH, countT, countTH} {
+			cell, _ := excelize.CoordinatesToCellName(jumlahStartCol+i, row)
+			f.SetCellValue(sheetName, cell, count)
+			f.SetCellStyle(sheetName, cell, cell, dataStyle)
+		}
+
+		f.SetCellStyle(sheetName, fmt.Sprintf("A%d", row), fmt.Sprintf("C%d", row), dataStyle)
+	}
+
+	// Set column widths
+	f.SetColWidth(sheetName, "A", "A", 5)  // NO
+	f.SetColWidth(sheetName, "B", "B", 30) // NAMA
+	f.SetColWidth(sheetName, "C", "C", 22) // NIP
+	for col := monthStartCol; col <= monthEndCol; col++ {
+		colName, _ := excelize.ColumnNumberToName(col)
+		f.SetColWidth(sheetName, colName, colName, 4) // Date columns
+	}
+	for col := jumlahStartCol; col <= lastCol; col++ {
+		colName, _ := excelize.ColumnNumberToName(col)
+		f.SetColWidth(sheetName, colName, colName, 5) // H, T, TH
+	}
+
+	// Keterangan below the table
+	keteranganRow := dataStartRow + len(rekap.pegawai) + 1
+	f.SetCellValue(sheetName, fmt.Sprintf("B%d", keteranganRow), "Keterangan: ✓ = tepat waktu, T = terlambat, A = tidak hadir, L = libur")
+
+	// Signature section at the bottom right
+	namaKepsek, nipKepsek := s.kepalaSekolah()
+	signatureStartRow := keteranganRow + 2
+	signatureCol := lastCol - 7
+	if signatureCol < monthStartCol {
+		signatureCol = monthStartCol
+	}
+	signatureColName, _ := excelize.ColumnNumberToName(signatureCol)
+	signatureStyle, _ := f.NewStyle(&excelize.Style{
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+	})
+
+	signatureLines := map[int]string{
+		0: "Mengetahui,",
+		1: "Kepala SDN Sukapura 01",
+		5: namaKepsek,
+		6: fmt.Sprintf("NIP. %s", nipKepsek),
+	}
+	for offset, text := range signatureLines {
+		cell := fmt.Sprintf("%s%d", signatureColName, signatureStartRow+offset)
+		f.SetCellValue(sheetName, cell, text)
+		f.SetCellStyle(sheetName, cell, cell, signatureStyle)
+	}
+
+	return f, nil
+}
+
+// ExportPDF exports the monthly absensi pegawai to a PDF file
+func (s *AbsensiPegawaiServiceImpl) ExportPDF(req *dtos.AbsensiPegawaiRekapRequest) ([]byte, error) {
+	rekap, err := s.loadRekapBulanan(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create PDF - Landscape A4
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.SetMargins(5, 10, 5)
+	pdf.SetAutoPageBreak(true, 10)
+	pdf.AddPage()
+
+	// Title
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(287, 7, judulExportPegawai(req.Kategori), "", 1, "C", false, 0, "")
+
+	// Subtitle
+	subtitle := fmt.Sprintf("BULAN %s TAHUN %d (%d HARI EFEKTIF)", bulanNamaPegawai[rekap.bulan], rekap.tahun, rekap.daysInMonth-len(rekap.liburMap))
+	pdf.CellFormat(287, 7, subtitle, "", 1, "C", false, 0, "")
+	pdf.Ln(2)
+
+	// Column widths, total width 287mm (A4 Landscape with 5mm margins)
+	noWidth := 8.0
+	namaWidth := 45.0
+	nipWidth := 35.0
+	dateWidth := 5.5
+	jumlahWidth := 8.0
+
+	totalWidth := noWidth + namaWidth + nipWidth + float64(rekap.daysInMonth)*dateWidth + 3*jumlahWidth
+	if totalWidth > 287 {
+		dateWidth = (287 - noWidth - namaWidth - nipWidth - 3*jumlahWidth) / float64(rekap.daysInMonth)
+		totalWidth = 287
+	}
+
+	// X offset to center table
+	xOffset := (297 - totalWidth) / 2
+
+	// Header
+	pdf.SetFillColor(128, 128, 128)
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont("Arial", "B", 8)
+
+	startY := pdf.GetY()
+	pdf.SetX(xOffset)
+
+	// Row 1 headers - with merged cells
+	pdf.CellFormat(noWidth, 12, "NO", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(namaWidth, 12, "NAMA", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(nipWidth, 12, "NIP", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(float64(rekap.daysInMonth)*dateWidth, 6, bulanNamaPegawai[rekap.bulan], "1", 0, "C", true, 0, "")
+	pdf.CellFormat(3*jumlahWidth, 6, "JUMLAH", "1", 0, "C", true, 0, "")
+
+	// Row 2 headers (dates and H, T, TH)
+	pdf.SetXY(xOffset+noWidth+namaWidth+nipWidth, startY+6)
+	for day := 1; day <= rekap.daysInMonth; day++ {
+		pdf.CellFormat(dateWidth, 6, fmt.Sprintf("%d", day), "1", 0, "C", true, 0, "")
+	}
+	pdf.CellFormat(jumlahWidth, 6, "H", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(jumlahWidth, 6, "T", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(jumlahWidth, 6, "TH", "1", 0, "C", true, 0, "")
+	pdf.Ln(-1)
+
+	// Data rows
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetFont("Arial", "", 7)
+
+	for idx, pegawai := range rekap.pegawai {
+		pdf.SetX(xOffset)
+		pdf.CellFormat(noWidth, 6, fmt.Sprintf("%d", idx+1), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(namaWidth, 6, pegawai.Nama, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(nipWidth, 6, pegawai.NIP, "1", 0, "C", false, 0, "")
+
+		// Date columns (non-school days shaded and marked L)
+		countH, countT, countTH := 0, 0, 0
+		pdf.SetFillColor(217, 217, 217)
+		for day := 1; day <= rekap.daysInMonth; day++ {
+			if rekap.liburMap[day] {
+				pdf.CellFormat(dateWidth, 6, "L", "1", 0, "C", true, 0, "")
+				continue
+			}
+			status, _ := rekap.statusHarian(pegawai.ID, day)
+			switch status {
+			case "tepat_waktu":
+				countH++
+			case "terlambat":
+				countH++
+				countT++
+			case "tidak_hadir":
+				countTH++
+			}
+			pdf.CellFormat(dateWidth, 6, markAbsensiPegawai(status, "v"), "1", 0, "C", false, 0, "")
+		}
+
+		pdf.CellFormat(jumlahWidth, 6, fmt.Sprintf("%d", countH), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(jumlahWidth, 6, fmt.Sprintf("%d", countT), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(jumlahWidth, 6, fmt.Sprintf("%d", countTH), "1", 1, "C", false, 0, "")
+	}
+
+	// Keterangan
+	pdf.Ln(2)
+	pdf.SetX(xOffset)
+	pdf.CellFormat(totalWidth, 5, "Keterangan: v = tepat waktu, T = terlambat, A = tidak hadir, L = libur", "", 1, "L", false, 0, "")
+
+	// Signature section at the bottom right
+	pdf.Ln(8)
+	namaKepsek, nipKepsek := s.kepalaSekolah()
+	signatureX := 200.0
+	pdf.SetFont("Arial", "", 10)
+	for _, line := range []string{"Mengetahui,", "Kepala SDN Sukapura 01"} {
+		pdf.SetX(signatureX)
+		pdf.CellFormat(60, 6, line, "", 1, "C", false, 0, "")
+	}
+	pdf.Ln(18) // Empty space for signature
+	pdf.SetX(signatureX)
+	pdf.CellFormat(60, 6, namaKepsek, "", 1, "C", false, 0, "")
+	pdf.SetX(signatureX)
+	pdf.CellFormat(60, 6, fmt.Sprintf("NIP. %s", nipKepsek), "", 1, "C", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// bulanNamaPegawai lists the month names used in the export headers
+var bulanNamaPegawai = []string{"", "JANUARI", "FEBRUARI", "MARET", "APRIL", "MEI", "JUNI",
+	"JULI", "AGUSTUS", "SEPTEMBER", "OKTOBER", "NOVEMBER", "DESEMBER"}
+
+// judulExportPegawai builds the export title, naming the kategori when the export is narrowed to one
+func judulExportPegawai(kategori string) string {
+	if kategori == "" {
+		return "DAFTAR HADIR GURU DAN TENAGA KEPENDIDIKAN"
+	}
+	return fmt.Sprintf("DAFTAR HADIR %s", strings.ToUpper(kategori))
+}
+
+// markAbsensiPegawai maps a daily status to its mark in the exports
+func markAbsensiPegawai(status, hadirMark string) string {
+	switch status {
+	case "tepat_waktu":
+		return hadirMark
+	case "terlambat":
+		return "T"
+	case "tidak_hadir":
+		return "A"
+	}
+	return "-"
+}
+
+// kepalaSekolah returns the kepala sekolah signing the exports, set in konfigurasi absensi
+func (s *AbsensiPegawaiServiceImpl) kepalaSekolah() (string, string) {
+	namaKepsek := "___________________"
+	nipKepsek := "___________________"
+	if konfigAbsensi, err := s.repository.GetKonfigurasiAbsensi(); err == nil {
+		if konfigAbsensi.NamaKepsek != nil && *konfigAbsensi.NamaKepsek != "" {
+			namaKepsek = *konfigAbsensi.NamaKepsek
+		}
+		if konfigAbsensi.NIPKepsek != nil && *konfigAbsensi.NIPKepsek != "" {
+			nipKepsek = *konfigAbsensi.NIPKepsek
+		}
+	}
+	return namaKepsek, nipKepsek
+}
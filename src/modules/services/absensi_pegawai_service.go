@@ -0,0 +1,408 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// AbsensiPegawaiService handles business logic for absensi of guru and tendik
+type AbsensiPegawaiService interface {
+	ScanAbsensiPegawai(req *dtos.AbsensiScanRequest, scannerDeviceID uint) (*dtos.AbsensiPegawaiScanResponse, error)
+	UpsertKonfigurasi(req *dtos.KonfigurasiAbsensiPegawaiRequest) (*dtos.KonfigurasiAbsensiPegawaiResponse, error)
+	GetKonfigurasi() (*dtos.KonfigurasiAbsensiPegawaiResponse, error)
+	GetRekapBulanan(req *dtos.AbsensiPegawaiRekapRequest) (*dtos.AbsensiPegawaiRekapResponse, error)
+	ExportExcel(req *dtos.AbsensiPegawaiRekapRequest) (*excelize.File, error)
+	ExportPDF(req *dtos.AbsensiPegawaiRekapRequest) ([]byte, error)
+}
+
+type AbsensiPegawaiServiceImpl struct {
+	repository      repositories.AbsensiPegawaiRepository
+	kalenderService KalenderAkademikService
+}
+
+// NewAbsensiPegawaiService creates a new Absensi Pegawai service
+func NewAbsensiPegawaiService(repository repositories.AbsensiPegawaiRepository, kalenderService KalenderAkademikService) AbsensiPegawaiService {
+	return &AbsensiPegawaiServiceImpl{
+		repository:      repository,
+		kalenderService: kalenderService,
+	}
+}
+
+// ScanAbsensiPegawai processes a guru or tendik scan from an authenticated scanner device
+func (s *AbsensiPegawaiServiceImpl) ScanAbsensiPegawai(req *dtos.AbsensiScanRequest, scannerDeviceID uint) (*dtos.AbsensiPegawaiScanResponse, error) {
+	// 1. Get konfigurasi pegawai (direct from DB, no cache)
+	config, err := s.repository.GetKonfigurasi()
+	if err != nil {
+		return &dtos.AbsensiPegawaiScanResponse{
+			Success: false,
+			Message: "Konfigurasi absensi pegawai belum diatur",
+		}, nil
+	}
+
+	// 2. Validate barcode and get kepegawaian
+	pegawai, err := s.repository.GetKepegawaianByBarcode(req.Barcode)
+	if err != nil {
+		return &dtos.AbsensiPegawaiScanResponse{
+			Success: false,
+			Message: "Barcode tidak ditemukan",
+		}, nil
+	}
+
+	// 3. Validate status kepegawaian (must be active)
+	if pegawai.Status != "active" {
+		return &dtos.AbsensiPegawaiScanResponse{
+			Success: false,
+			Message: "Pegawai tidak aktif, tidak dapat melakukan absensi",
+		}, nil
+	}
+
+	// 4. Validate hari sekolah (weekend, libur, outside semester)
+	now := time.Now().In(utils.JakartaLocation())
+	hariSekolah, keterangan, err := s.kalenderService.CekHariSekolah(now)
+	if err != nil {
+		return nil, err
+	}
+	if !hariSekolah {
+		return &dtos.AbsensiPegawaiScanResponse{
+			Success: false,
+			Message: fmt.Sprintf("Bukan hari sekolah (%s), absensi tidak dapat dicatat", keterangan),
+		}, nil
+	}
+
+	currentDate := now.Format("2006-01-02")
+	currentTime := now.Format("15:04:05")
+
+	// 5. Validate time range against the staff windows
+	scanType, status, validationErr := validateJendelaScan(currentTime, jendelaScan{
+		DatangMulai:   config.JamDatangMulai,
+		MaxDatang:     config.JamMaxDatang,
+		DatangSelesai: config.JamDatangSelesai,
+		PulangMulai:   config.JamPulangMulai,
+		PulangSelesai: config.JamPulangSelesai,
+	})
+	if validationErr != nil {
+		return &dtos.AbsensiPegawaiScanResponse{
+			Success: false,
+			Message: validationErr.Error(),
+		}, nil
+	}
+
+	pegawaiInfo := &dtos.PegawaiInfo{
+		ID:      pegawai.ID,
+		Nama:    pegawai.Nama,
+		NIP:     pegawai.NIP,
+		Jabatan: pegawai.Jabatan,
+	}
+
+	// 6. Check existing absensi of today
+	absensi, err := s.repository.GetByKepegawaianAndDate(pegawai.ID, now)
+	isUpdate := err == nil
+
+	if isUpdate {
+		alreadyScanned := ""
+		if scanType == "datang" && absensi.JamDatang != nil {
+			alreadyScanned = "Anda sudah melakukan absen datang hari ini"
+		}
+		if scanType == "pulang" && absensi.JamPulang != nil {
+			alreadyScanned = "Anda sudah melakukan absen pulang hari ini"
+		}
+		if alreadyScanned != "" {
+			statusValue := "unknown"
+			if absensi.Status != nil {
+				statusValue = *absensi.Status
+			}
+			return &dtos.AbsensiPegawaiScanResponse{
+				Success: true,
+				Message: alreadyScanned,
+				Pegawai: pegawaiInfo,
+				AbsensiInfo: &dtos.AbsensiInfo{
+					Tanggal:   currentDate,
+					JamDatang: absensi.JamDatang,
+					JamPulang: absensi.JamPulang,
+					Status:    statusValue,
+					IsUpdate:  false,
+				},
+			}, nil
+		}
+	}
+
+	// Pulang requires datang first
+	if scanType == "pulang" && (!isUpdate || absensi.JamDatang == nil) {
+		return &dtos.AbsensiPegawaiScanResponse{
+			Success: false,
+			Message: "Anda belum melakukan absen datang, tidak dapat melakukan absen pulang",
+		}, nil
+	}
+
+	if !isUpdate {
+		absensi = &models.AbsensiPegawai{
+			KepegawaianID: pegawai.ID,
+			Tanggal:       now,
+		}
+	}
+
+	// 7. Update fields based on scan type and record the device that scanned
+	absensi.ScannerDeviceID = &scannerDeviceID
+	if scanType == "datang" {
+		absensi.JamDatang = &currentTime
+		absensi.Status = &status
+	} else {
+		absensi.JamPulang = &currentTime
+		// Status tidak berubah saat pulang
+	}
+
+	// 8. Save to database using UPSERT
+	if err := s.repository.Upsert(absensi); err != nil {
+		return &dtos.AbsensiPegawaiScanResponse{
+			Success: false,
+			Message: "Gagal menyimpan data absensi",
+		}, err
+	}
+
+	statusValue := ""
+	if absensi.Status != nil {
+		statusValue = *absensi.Status
+	}
+
+	return &dtos.AbsensiPegawaiScanResponse{
+		Success: true,
+		Message: buildScanSuccessMessage(scanType, status, isUpdate),
+		Pegawai: pegawaiInfo,
+		AbsensiInfo: &dtos.AbsensiInfo{
+			Tanggal:   currentDate,
+			JamDatang: absensi.JamDatang,
+			JamPulang: absensi.JamPulang,
+			Status:    statusValue,
+			IsUpdate:  isUpdate,
+		},
+	}, nil
+}
+
+// UpsertKonfigurasi creates or updates Konfigurasi Absensi Pegawai with ID = 1
+func (s *AbsensiPegawaiServiceImpl) UpsertKonfigurasi(req *dtos.KonfigurasiAbsensiPegawaiRequest) (*dtos.KonfigurasiAbsensiPegawaiResponse, error) {
+	existing, err := s.repository.GetKonfigurasi()
+	if err != nil {
+		// Record not found, create new one with ID = 1
+		data := &models.KonfigurasiAbsensiPegawai{
+			ID:               1,
+			JamDatangMulai:   req.JamDatangMulai,
+			JamMaxDatang:     req.JamMaxDatang,
+			JamDatangSelesai: req.JamDatangSelesai,
+			JamPulangMulai:   req.JamPulangMulai,
+			JamPulangSelesai: req.JamPulangSelesai,
+		}
+
+		if err := s.repository.CreateKonfigurasi(data); err != nil {
+			return nil, err
+		}
+
+		return s.mapKonfigurasiToResponse(data), nil
+	}
+
+	// Record exists, update it
+	existing.JamDatangMulai = req.JamDatangMulai
+	existing.JamMaxDatang = req.JamMaxDatang
+	existing.JamDatangSelesai = req.JamDatangSelesai
+	existing.JamPulangMulai = req.JamPulangMulai
+	existing.JamPulangSelesai = req.JamPulangSelesai
+
+	if err := s.repository.UpdateKonfigurasi(existing); err != nil {
+		return nil, err
+	}
+
+	return s.mapKonfigurasiToResponse(existing), nil
+}
+
+// GetKonfigurasi retrieves konfigurasi absensi pegawai with ID = 1, nil when not set yet
+func (s *AbsensiPegawaiServiceImpl) GetKonfigurasi() (*dtos.KonfigurasiAbsensiPegawaiResponse, error) {
+	data, err := s.repository.GetKonfigurasi()
+	if err != nil {
+		return nil, nil
+	}
+
+	return s.mapKonfigurasiToResponse(data), nil
+}
+
+// rekapPegawaiBulanan holds one month of absensi pegawai, shared by the rekap endpoint and the exports
+type rekapPegawaiBulanan struct {
+	bulan       int
+	tahun       int
+	startDate   time.Time
+	daysInMonth int
+	liburMap    map[int]bool // Day of month -> not a school day
+	sampaiHari  int          // Last day of month already passed (today or earlier)
+	pegawai     []models.Kepegawaian
+	absensiMap  map[uint]map[int]models.AbsensiPegawai // kepegawaianID -> day -> absensi
+}
+
+// hariEfektif counts the school days of the month up to today
+func (r *rekapPegawaiBulanan) hariEfektif() int {
+	total := 0
+	for day := 1; day <= r.sampaiHari; day++ {
+		if !r.liburMap[day] {
+			total++
+		}
+	}
+	return total
+}
+
+// loadRekapBulanan loads the active pegawai, the kalender and the absensi rows of the requested month
+func (s *AbsensiPegawaiServiceImpl) loadRekapBulanan(req *dtos.AbsensiPegawaiRekapRequest) (*rekapPegawaiBulanan, error) {
+	startDate := time.Date(req.Tahun, time.Month(req.Bulan), 1, 0, 0, 0, 0, time.UTC)
+	endDate := startDate.AddDate(0, 1, -1) // Last day of month
+	daysInMonth := endDate.Day()
+
+	pegawai, err := s.repository.GetActiveKepegawaian(req.Kategori, req.KepegawaianID)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data kepegawaian")
+	}
+	if len(pegawai) == 0 {
+		return nil, errors.New("tidak ada pegawai aktif")
+	}
+
+	// Load kalender akademik to mark non-school days
+	kalender, err := s.kalenderService.LoadKalender(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	liburMap := make(map[int]bool)
+	for day := 1; day <= daysInMonth; day++ {
+		if ok, _ := kalender.IsHariSekolah(startDate.AddDate(0, 0, day-1)); !ok {
+			liburMap[day] = true
+		}
+	}
+
+	// Days that have not happened yet do not count as tidak hadir
+	today := dateOnly(time.Now().In(utils.JakartaLocation()))
+	sampaiHari := daysInMonth
+	if today.Before(startDate) {
+		sampaiHari = 0
+	} else if !today.After(endDate) {
+		sampaiHari = today.Day()
+	}
+
+	ids := make([]uint, 0, len(pegawai))
+	for _, p := range pegawai {
+		ids = append(ids, p.ID)
+	}
+	absensiList, err := s.repository.GetByPeriode(startDate, endDate, ids)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data absensi pegawai")
+	}
+
+	absensiMap := make(map[uint]map[int]models.AbsensiPegawai)
+	for _, absensi := range absensiList {
+		if _, exists := absensiMap[absensi.KepegawaianID]; !exists {
+			absensiMap[absensi.KepegawaianID] = make(map[int]models.AbsensiPegawai)
+		}
+		absensiMap[absensi.KepegawaianID][absensi.Tanggal.Day()] = absensi
+	}
+
+	return &rekapPegawaiBulanan{
+		bulan:       req.Bulan,
+		tahun:       req.Tahun,
+		startDate:   startDate,
+		daysInMonth: daysInMonth,
+		liburMap:    liburMap,
+		sampaiHari:  sampaiHari,
+		pegawai:     pegawai,
+		absensiMap:  absensiMap,
+	}, nil
+}
+
+// statusHarian returns the status of a pegawai on a school day: the datang status, or tidak_hadir
+// once the day has passed without a datang scan ("" for days still to come)
+func (r *rekapPegawaiBulanan) statusHarian(kepegawaianID uint, day int) (string, *models.AbsensiPegawai) {
+	if absensi, exists := r.absensiMap[kepegawaianID][day]; exists && absensi.JamDatang != nil {
+		status := "tepat_waktu"
+		if absensi.Status != nil {
+			status = *absensi.Status
+		}
+		return status, &absensi
+	}
+	if day <= r.sampaiHari {
+		return "tidak_hadir", nil
+	}
+	return "", nil
+}
+
+// GetRekapBulanan builds the monthly totals and daily detail per pegawai
+func (s *AbsensiPegawaiServiceImpl) GetRekapBulanan(req *dtos.AbsensiPegawaiRekapRequest) (*dtos.AbsensiPegawaiRekapResponse, error) {
+	rekap, err := s.loadRekapBulanan(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]dtos.AbsensiPegawaiRekapItem, 0, len(rekap.pegawai))
+	for _, pegawai := range rekap.pegawai {
+		item := dtos.AbsensiPegawaiRekapItem{
+			KepegawaianID: pegawai.ID,
+			Nama:          pegawai.Nama,
+			NIP:           pegawai.NIP,
+			Kategori:      pegawai.Kategori,
+			Jabatan:       pegawai.Jabatan,
+			Detail:        []dtos.AbsensiPegawaiHarianItem{},
+		}
+
+		for day := 1; day <= rekap.sampaiHari; day++ {
+			if rekap.liburMap[day] {
+				continue
+			}
+
+			status, absensi := rekap.statusHarian(pegawai.ID, day)
+			harian := dtos.AbsensiPegawaiHarianItem{
+				Tanggal: rekap.startDate.AddDate(0, 0, day-1).Format("2006-01-02"),
+				Status:  status,
+			}
+
+			switch status {
+			case "tidak_hadir":
+				item.TotalTidakHadir++
+			default:
+				item.TotalHadir++
+				if status == "terlambat" {
+					item.TotalTerlambat++
+				} else {
+					item.TotalTepatWaktu++
+				}
+				harian.JamDatang = absensi.JamDatang
+				harian.JamPulang = absensi.JamPulang
+				if absensi.JamPulang == nil {
+					item.TotalTanpaPulang++
+				}
+			}
+
+			item.Detail = append(item.Detail, harian)
+		}
+
+		data = append(data, item)
+	}
+
+	return &dtos.AbsensiPegawaiRekapResponse{
+		Bulan:            rekap.bulan,
+		Tahun:            rekap.tahun,
+		TotalHariEfektif: rekap.hariEfektif(),
+		Data:             data,
+	}, nil
+}
+
+// mapKonfigurasiToResponse maps KonfigurasiAbsensiPegawai model to response DTO
+func (s *AbsensiPegawaiServiceImpl) mapKonfigurasiToResponse(data *models.KonfigurasiAbsensiPegawai) *dtos.KonfigurasiAbsensiPegawaiResponse {
+	return &dtos.KonfigurasiAbsensiPegawaiResponse{
+		ID:               data.ID,
+		JamDatangMulai:   data.JamDatangMulai,
+		JamMaxDatang:     data.JamMaxDatang,
+		JamDatangSelesai: data.JamDatangSelesai,
+		JamPulangMulai:   data.JamPulangMulai,
+		JamPulangSelesai: data.JamPulangSelesai,
+		CreatedAt:        data.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:        data.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
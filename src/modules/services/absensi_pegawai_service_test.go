@@ -0,0 +1,205 @@
+package services
+
+import (
+	"errors"
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"testing"
+	"time"
+)
+
+// fakeAbsensiPegawaiRepository keeps pegawai and at most one absensi row per pegawai in memory
+type fakeAbsensiPegawaiRepository struct {
+	repositories.AbsensiPegawaiRepository
+	konfigurasi models.KonfigurasiAbsensiPegawai
+	pegawai     []models.Kepegawaian
+	absensi     []models.AbsensiPegawai
+	upserts     int
+}
+
+func (r *fakeAbsensiPegawaiRepository) GetKonfigurasi() (*models.KonfigurasiAbsensiPegawai, error) {
+	konfigurasi := r.konfigurasi
+	return &konfigurasi, nil
+}
+
+func (r *fakeAbsensiPegawaiRepository) GetKepegawaianByBarcode(barcode string) (*models.Kepegawaian, error) {
+	for _, pegawai := range r.pegawai {
+		if pegawai.Barcode == barcode {
+			return &pegawai, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeAbsensiPegawaiRepository) GetByKepegawaianAndDate(kepegawaianID uint, tanggal time.Time) (*models.AbsensiPegawai, error) {
+	for _, absensi := range r.absensi {
+		if absensi.KepegawaianID == kepegawaianID {
+			return &absensi, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeAbsensiPegawaiRepository) Upsert(data *models.AbsensiPegawai) error {
+	r.upserts++
+	r.absensi = []models.AbsensiPegawai{*data}
+	return nil
+}
+
+func (r *fakeAbsensiPegawaiRepository) GetActiveKepegawaian(kategori string, kepegawaianID *uint) ([]models.Kepegawaian, error) {
+	return r.pegawai, nil
+}
+
+func (r *fakeAbsensiPegawaiRepository) GetByPeriode(startDate, endDate time.Time, kepegawaianIDs []uint) ([]models.AbsensiPegawai, error) {
+	return r.absensi, nil
+}
+
+// jendelaSepanjangHari opens one scan window for the whole day so the tests do not depend on the clock
+func jendelaSepanjangHari(scanType string) models.KonfigurasiAbsensiPegawai {
+	// "24:00:00" sorts after every clock time, which keeps the other window closed
+	konfigurasi := models.KonfigurasiAbsensiPegawai{
+		JamDatangMulai: "24:00:00", JamMaxDatang: "24:00:00", JamDatangSelesai: "24:00:00",
+		JamPulangMulai: "24:00:00", JamPulangSelesai: "24:00:00",
+	}
+	switch scanType {
+	case "datang":
+		konfigurasi.JamDatangMulai, konfigurasi.JamMaxDatang, konfigurasi.JamDatangSelesai = "00:00:00", "23:59:59", "23:59:59"
+	case "pulang":
+		konfigurasi.JamPulangMulai, konfigurasi.JamPulangSelesai = "00:00:00", "23:59:59"
+	}
+	return konfigurasi
+}
+
+func TestValidateJendelaScan(t *testing.T) {
+	jendela := jendelaScan{DatangMulai: "06:00:00", MaxDatang: "07:15:00", DatangSelesai: "09:00:00", PulangMulai: "14:00:00", PulangSelesai: "17:00:00"}
+
+	tests := []struct {
+		currentTime  string
+		wantScanType string
+		wantStatus   string
+		wantErr      bool
+	}{
+		{"06:00:00", "datang", "tepat_waktu", false},
+		{"07:15:00", "datang", "tepat_waktu", false},
+		{"07:15:01", "datang", "terlambat", false},
+		{"09:00:00", "datang", "terlambat", false},
+		{"14:00:00", "pulang", "", false},
+		{"17:00:00", "pulang", "", false},
+		{"05:59:59", "", "", true},
+		{"11:00:00", "", "", true},
+		{"17:00:01", "", "", true},
+	}
+
+	for _, tt := range tests {
+		scanType, status, err := validateJendelaScan(tt.currentTime, jendela)
+		if scanType != tt.wantScanType || status != tt.wantStatus || (err != nil) != tt.wantErr {
+			t.Errorf("validateJendelaScan(%s) = %q, %q, %v, want %q, %q, error %v", tt.currentTime, scanType, status, err, tt.wantScanType, tt.wantStatus, tt.wantErr)
+		}
+	}
+}
+
+func TestScanAbsensiPegawai(t *testing.T) {
+	jamDatang := "07:00:00"
+	tepatWaktu := "tepat_waktu"
+	sudahDatang := models.AbsensiPegawai{KepegawaianID: 1, JamDatang: &jamDatang, Status: &tepatWaktu}
+
+	tests := []struct {
+		name        string
+		jendela     string
+		barcode     string
+		libur       string
+		absensi     []models.AbsensiPegawai
+		wantSuccess bool
+		wantUpserts int
+	}{
+		{"datang", "datang", "PGW-1", "", nil, true, 1},
+		{"datang twice", "datang", "PGW-1", "", []models.AbsensiPegawai{sudahDatang}, true, 0},
+		{"pulang after datang", "pulang", "PGW-1", "", []models.AbsensiPegawai{sudahDatang}, true, 1},
+		{"pulang without datang", "pulang", "PGW-1", "", nil, false, 0},
+		{"outside the scan windows", "", "PGW-1", "", nil, false, 0},
+		{"not a school day", "datang", "PGW-1", "Libur Nasional", nil, false, 0},
+		{"unknown barcode", "datang", "PGW-9", "", nil, false, 0},
+		{"pegawai not active", "datang", "PGW-2", "", nil, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeAbsensiPegawaiRepository{
+				konfigurasi: jendelaSepanjangHari(tt.jendela),
+				pegawai: []models.Kepegawaian{
+					{ID: 1, Nama: "Guru Aktif", Barcode: "PGW-1", Status: "active"},
+					{ID: 2, Nama: "Guru Pensiun", Barcode: "PGW-2", Status: "inactive"},
+				},
+				absensi: tt.absensi,
+			}
+			service := NewAbsensiPegawaiService(repository, &fakeKalenderAkademikService{libur: tt.libur})
+
+			response, err := service.ScanAbsensiPegawai(&dtos.AbsensiScanRequest{Barcode: tt.barcode}, 4)
+			if err != nil {
+				t.Fatalf("ScanAbsensiPegawai() error = %v", err)
+			}
+			if response.Success != tt.wantSuccess {
+				t.Errorf("Success = %v (%s), want %v", response.Success, response.Message, tt.wantSuccess)
+			}
+			if repository.upserts != tt.wantUpserts {
+				t.Fatalf("upserts = %d, want %d", repository.upserts, tt.wantUpserts)
+			}
+			if tt.wantUpserts > 0 {
+				saved := repository.absensi[0]
+				if saved.JamDatang == nil || *saved.Status != "tepat_waktu" || *saved.ScannerDeviceID != 4 {
+					t.Errorf("saved absensi = %v/%v from device %v, want datang tepat_waktu from device 4", saved.JamDatang, saved.Status, saved.ScannerDeviceID)
+				}
+				if (tt.jendela == "pulang") != (saved.JamPulang != nil) {
+					t.Errorf("JamPulang = %v after a %s scan", saved.JamPulang, tt.jendela)
+				}
+			}
+		})
+	}
+}
+
+func TestGetRekapBulananPegawai(t *testing.T) {
+	// October 2025 has passed entirely and holds 23 weekdays
+	tanggal := func(day int) time.Time { return time.Date(2025, 10, day, 0, 0, 0, 0, time.UTC) }
+	jamDatang, jamPulang := "07:00:00", "15:00:00"
+	tepatWaktu, terlambat := "tepat_waktu", "terlambat"
+
+	repository := &fakeAbsensiPegawaiRepository{
+		pegawai: []models.Kepegawaian{{ID: 1, Nama: "Guru Rajin"}, {ID: 2, Nama: "Guru Cuti"}},
+		absensi: []models.AbsensiPegawai{
+			{KepegawaianID: 1, Tanggal: tanggal(1), JamDatang: &jamDatang, JamPulang: &jamPulang, Status: &tepatWaktu},
+			{KepegawaianID: 1, Tanggal: tanggal(2), JamDatang: &jamDatang, Status: &terlambat},
+			// A Saturday scan is not counted
+			{KepegawaianID: 1, Tanggal: tanggal(4), JamDatang: &jamDatang, Status: &tepatWaktu},
+		},
+	}
+	service := NewAbsensiPegawaiService(repository, &fakeKalenderLoader{})
+
+	rekap, err := service.GetRekapBulanan(&dtos.AbsensiPegawaiRekapRequest{Bulan: 10, Tahun: 2025})
+	if err != nil {
+		t.Fatalf("GetRekapBulanan() error = %v", err)
+	}
+	if rekap.TotalHariEfektif != 23 || len(rekap.Data) != 2 {
+		t.Fatalf("TotalHariEfektif = %d, pegawai = %d, want 23 and 2", rekap.TotalHariEfektif, len(rekap.Data))
+	}
+
+	tests := []struct {
+		item                                                  dtos.AbsensiPegawaiRekapItem
+		hadir, tepatWaktu, terlambat, tidakHadir, tanpaPulang int
+	}{
+		{rekap.Data[0], 2, 1, 1, 21, 1},
+		{rekap.Data[1], 0, 0, 0, 23, 0},
+	}
+	for _, tt := range tests {
+		item := tt.item
+		if item.TotalHadir != tt.hadir || item.TotalTepatWaktu != tt.tepatWaktu || item.TotalTerlambat != tt.terlambat ||
+			item.TotalTidakHadir != tt.tidakHadir || item.TotalTanpaPulang != tt.tanpaPulang {
+			t.Errorf("%s totals = hadir %d, tepat waktu %d, terlambat %d, tidak hadir %d, tanpa pulang %d, want %d, %d, %d, %d, %d",
+				item.Nama, item.TotalHadir, item.TotalTepatWaktu, item.TotalTerlambat, item.TotalTidakHadir, item.TotalTanpaPulang,
+				tt.hadir, tt.tepatWaktu, tt.terlambat, tt.tidakHadir, tt.tanpaPulang)
+		}
+		if len(item.Detail) != 23 {
+			t.Errorf("%s detail = %d days, want 23", item.Nama, len(item.Detail))
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+)
+
+// GetRiwayatRekapAbsensi retrieves every change of the rekap rows of a student-day, oldest first
+func (s *AbsensiServiceImpl) GetRiwayatRekapAbsensi(req *dtos.RiwayatRekapAbsensiRequest) ([]dtos.RiwayatRekapAbsensiResponse, error) {
+	tanggal, err := time.Parse("2006-01-02", req.Tanggal)
+	if err != nil {
+		return nil, errors.New("format tanggal tidak valid, gunakan YYYY-MM-DD")
+	}
+
+	data, err := s.riwayatRepository.GetBySiswaTanggal(req.PesertaDidikRombelID, tanggal, req.BidangStudiID)
+	if err != nil {
+		return nil, errors.New("gagal mengambil riwayat absensi")
+	}
+
+	responses := make([]dtos.RiwayatRekapAbsensiResponse, len(data))
+	for i, item := range data {
+		responses[i] = dtos.RiwayatRekapAbsensiResponse{
+			ID:                    item.ID,
+			RekapitulasiAbsensiID: item.RekapitulasiAbsensiID,
+			Tanggal:               item.Tanggal.Format("2006-01-02"),
+			BidangStudiID:         item.BidangStudiID,
+			Aksi:                  item.Aksi,
+			Sumber:                item.Sumber,
+			ActorID:               item.ActorID,
+			ActorType:             item.ActorType,
+			ActorNama:             item.ActorNama,
+			IPAddress:             item.IPAddress,
+			CreatedAt:             item.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+		if item.Aksi != models.AksiRiwayatRekapCreate {
+			responses[i].Sebelum = s.nilaiRiwayat(item.StatusSebelum, item.MetodeInputSebelum, item.KeteranganSebelum, item.FileSuratSebelum, item.WaktuAbsenSebelum)
+		}
+		if item.Aksi != models.AksiRiwayatRekapDelete {
+			responses[i].Sesudah = s.nilaiRiwayat(item.StatusSesudah, item.MetodeInputSesudah, item.KeteranganSesudah, item.FileSuratSesudah, item.WaktuAbsenSesudah)
+		}
+	}
+
+	return responses, nil
+}
+
+// nilaiRiwayat maps the before or after columns of a history entry
+func (s *AbsensiServiceImpl) nilaiRiwayat(status, metodeInput, keterangan, fileSurat *string, waktuAbsen *time.Time) *dtos.RiwayatRekapAbsensiNilai {
+	nilai := &dtos.RiwayatRekapAbsensiNilai{}
+	if status != nil {
+		nilai.Status = *status
+	}
+	if metodeInput != nil {
+		nilai.MetodeInput = *metodeInput
+	}
+	if keterangan != nil {
+		nilai.Keterangan = *keterangan
+	}
+	if fileSurat != nil {
+		nilai.FileSurat = utils.FileURL(s.storage, *fileSurat)
+	}
+	if waktuAbsen != nil {
+		nilai.WaktuAbsen = waktuAbsen.Format("2006-01-02 15:04:05")
+	}
+	return nilai
+}
+
+// auditRekapAbsensi builds the history audit of a rekap change made by actor from ipAddress
+func auditRekapAbsensi(sumber string, actor utils.Principal, ipAddress string) repositories.AuditRekapAbsensi {
+	return repositories.AuditRekapAbsensi{
+		Sumber:    sumber,
+		ActorID:   &actor.ID,
+		ActorType: actor.TypePtr(),
+		ActorNama: actor.Nama,
+		IPAddress: ipAddress,
+	}
+}
@@ -1,31 +1,52 @@
 package services
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"pintu-backend/src/dtos"
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+	"sort"
 	"time"
 )
 
+const (
+	// maxOfflineScanAge is how old a queued offline scan may be when it is uploaded
+	maxOfflineScanAge = 7 * 24 * time.Hour
+
+	// maxOfflineScanClockSkew tolerates device clocks running slightly ahead of the server
+	maxOfflineScanClockSkew = 5 * time.Minute
+)
+
 // AbsensiScanService handles business logic for Absensi Scan
 type AbsensiScanService interface {
-	ScanAbsensi(req *dtos.AbsensiScanRequest) (*dtos.AbsensiScanResponse, error)
+	ScanAbsensi(req *dtos.AbsensiScanRequest, scannerDeviceID uint) (*dtos.AbsensiScanResponse, error)
+	ScanAbsensiBatch(req *dtos.AbsensiScanBatchRequest, device models.ScannerDevice) (*dtos.AbsensiScanBatchResponse, error)
 }
 
 type AbsensiScanServiceImpl struct {
-	repository repositories.AbsensiScanRepository
+	repository        repositories.AbsensiScanRepository
+	kalenderService   KalenderAkademikService
+	notifikasiService NotifikasiService
+	jadwalService     JadwalAbsensiService
+	liveService       AbsensiLiveService
 }
 
 // NewAbsensiScanService creates a new Absensi Scan service
-func NewAbsensiScanService(repository repositories.AbsensiScanRepository) AbsensiScanService {
+func NewAbsensiScanService(repository repositories.AbsensiScanRepository, kalenderService KalenderAkademikService, notifikasiService NotifikasiService, jadwalService JadwalAbsensiService, liveService AbsensiLiveService) AbsensiScanService {
 	return &AbsensiScanServiceImpl{
-		repository: repository,
+		repository:        repository,
+		kalenderService:   kalenderService,
+		notifikasiService: notifikasiService,
+		jadwalService:     jadwalService,
+		liveService:       liveService,
 	}
 }
 
-// ScanAbsensi processes attendance scanning
-func (s *AbsensiScanServiceImpl) ScanAbsensi(req *dtos.AbsensiScanRequest) (*dtos.AbsensiScanResponse, error) {
+// ScanAbsensi processes attendance scanning from an authenticated scanner device
+func (s *AbsensiScanServiceImpl) ScanAbsensi(req *dtos.AbsensiScanRequest, scannerDeviceID uint) (*dtos.AbsensiScanResponse, error) {
 	// 1. Get konfigurasi (direct from DB, no cache)
 	config, err := s.repository.GetKonfigurasiAbsensi()
 	if err != nil {
@@ -35,48 +56,270 @@ func (s *AbsensiScanServiceImpl) ScanAbsensi(req *dtos.AbsensiScanRequest) (*dto
 		}, nil
 	}
 
-	// 2. Validate barcode and get peserta didik
-	pesertaDidik, err := s.repository.GetPesertaDidikByBarcode(req.Barcode)
+	// 2. Validate barcode (static or signed kartu pelajar QR) and get peserta didik
+	now := time.Now().In(utils.JakartaLocation())
+	pesertaDidik, nonce, reason, err := s.resolvePesertaDidik(req.Barcode, config, now)
 	if err != nil {
+		return nil, err
+	}
+	if pesertaDidik == nil {
 		return &dtos.AbsensiScanResponse{
 			Success: false,
-			Message: "Barcode tidak ditemukan",
+			Message: reason,
 		}, nil
 	}
 
-	// 3. Validate status peserta didik (must be active)
+	// 3. Record with the current time (Asia/Jakarta)
+	response, _, err := s.recordScan(pesertaDidik, nonce, config, now, scannerDeviceID)
+	return response, err
+}
+
+// ScanAbsensiBatch records scans queued by a device while offline, using each scan's own time.
+// Client scan IDs already stored for the device return their earlier result instead of being applied again.
+func (s *AbsensiScanServiceImpl) ScanAbsensiBatch(req *dtos.AbsensiScanBatchRequest, device models.ScannerDevice) (*dtos.AbsensiScanBatchResponse, error) {
+	config, err := s.repository.GetKonfigurasiAbsensi()
+	if err != nil {
+		return nil, errors.New("konfigurasi absensi belum diatur")
+	}
+
+	clientScanIDs := make([]string, 0, len(req.Items))
+	for _, item := range req.Items {
+		clientScanIDs = append(clientScanIDs, item.ClientScanID)
+	}
+	storedLogs, err := s.repository.GetScanLogsByClientScanIDs(device.ID, clientScanIDs)
+	if err != nil {
+		return nil, err
+	}
+	stored := make(map[string]models.AbsensiScanLog, len(storedLogs))
+	for _, log := range storedLogs {
+		stored[log.ClientScanID] = log
+	}
+
+	// Apply scans in the order they happened so datang is recorded before pulang
+	items := make([]dtos.AbsensiScanBatchItem, len(req.Items))
+	copy(items, req.Items)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].ScannedAt.Before(items[j].ScannedAt)
+	})
+
+	loc := utils.JakartaLocation()
+	now := time.Now()
+	seen := make(map[string]bool, len(items))
+	response := &dtos.AbsensiScanBatchResponse{Details: []dtos.AbsensiScanBatchDetailItem{}}
+
+	for _, item := range items {
+		response.TotalProcessed++
+		scannedAt := item.ScannedAt.In(loc)
+		detail := dtos.AbsensiScanBatchDetailItem{
+			ClientScanID: item.ClientScanID,
+			Tanggal:      scannedAt.Format("2006-01-02"),
+			Jam:          scannedAt.Format("15:04:05"),
+		}
+
+		// Replayed upload or the same scan twice in one batch
+		if log, ok := stored[item.ClientScanID]; ok || seen[item.ClientScanID] {
+			response.TotalDuplicate++
+			detail.Duplicate = true
+			detail.Action = "skipped"
+			if ok {
+				detail.Action = log.Action
+				if log.Reason != nil {
+					detail.Reason = *log.Reason
+				}
+				if log.PesertaDidik != nil {
+					detail.PesertaDidikID = log.PesertaDidik.ID
+					detail.NISN = log.PesertaDidik.NISN
+					detail.Nama = log.PesertaDidik.Nama
+				}
+			}
+			response.Details = append(response.Details, detail)
+			continue
+		}
+		seen[item.ClientScanID] = true
+
+		var pesertaDidik *models.PesertaDidik
+		var nonce *models.KartuQRNonce
+		action, reason := "skipped", ""
+		switch {
+		case scannedAt.After(now.Add(maxOfflineScanClockSkew)):
+			reason = "Waktu scan berada di masa depan"
+		case now.Sub(scannedAt) > maxOfflineScanAge:
+			reason = "Waktu scan sudah terlalu lama untuk diunggah"
+		case !utils.WithinTimeWindow(device.JamAktifMulai, device.JamAktifSelesai, scannedAt):
+			reason = "Waktu scan di luar jam aktif perangkat"
+		default:
+			// Signed QR payloads are checked against the scan time, not the upload time
+			pesertaDidik, nonce, reason, err = s.resolvePesertaDidik(item.Barcode, config, scannedAt)
+			if err != nil {
+				// Not stored, so the device can retry the same client scan ID
+				response.TotalFailed++
+				detail.Action = "failed"
+				detail.Reason = "Gagal memeriksa barcode"
+				response.Details = append(response.Details, detail)
+				continue
+			}
+			if pesertaDidik == nil {
+				break
+			}
+
+			var scanResponse *dtos.AbsensiScanResponse
+			scanResponse, action, err = s.recordScan(pesertaDidik, nonce, config, scannedAt, device.ID)
+			if err != nil {
+				// Not stored, so the device can retry the same client scan ID
+				response.TotalFailed++
+				detail.Action = "failed"
+				detail.Reason = err.Error()
+				detail.PesertaDidikID = pesertaDidik.ID
+				detail.NISN = pesertaDidik.NISN
+				detail.Nama = pesertaDidik.Nama
+				response.Details = append(response.Details, detail)
+				continue
+			}
+			if action == "skipped" {
+				reason = scanResponse.Message
+			}
+		}
+
+		log := &models.AbsensiScanLog{
+			ScannerDeviceID: device.ID,
+			ClientScanID:    item.ClientScanID,
+			Barcode:         item.Barcode,
+			ScannedAt:       scannedAt,
+			Action:          action,
+		}
+		if reason != "" {
+			log.Reason = &reason
+		}
+		if pesertaDidik != nil {
+			log.PesertaDidikID = &pesertaDidik.ID
+			detail.PesertaDidikID = pesertaDidik.ID
+			detail.NISN = pesertaDidik.NISN
+			detail.Nama = pesertaDidik.Nama
+		}
+		if err := s.repository.CreateScanLog(log); err != nil {
+			return nil, err
+		}
+
+		switch action {
+		case "inserted":
+			response.TotalInserted++
+		case "updated":
+			response.TotalUpdated++
+		default:
+			response.TotalSkipped++
+		}
+		detail.Action = action
+		detail.Reason = reason
+		response.Details = append(response.Details, detail)
+	}
+
+	response.Message = fmt.Sprintf("Unggah scan offline selesai: %d diproses, %d ditambahkan, %d diupdate, %d dilewati, %d duplikat, %d gagal",
+		response.TotalProcessed, response.TotalInserted, response.TotalUpdated, response.TotalSkipped, response.TotalDuplicate, response.TotalFailed)
+
+	return response, nil
+}
+
+// resolvePesertaDidik finds the student of a scanned value: a printed static barcode or a rotating
+// signed kartu pelajar QR, as allowed by the konfigurasi mode. A nil student comes with the reason
+// to show on the scanner. The nonce of a signed QR is returned unused, recordScan uses it up with the save.
+func (s *AbsensiScanServiceImpl) resolvePesertaDidik(barcode string, config *models.KonfigurasiAbsensi, scannedAt time.Time) (*models.PesertaDidik, *models.KartuQRNonce, string, error) {
+	if !utils.IsKartuQR(barcode) {
+		if config.ModeKartuQR == models.ModeKartuQRDinamis {
+			return nil, nil, "Gunakan QR kartu pelajar dari portal siswa", nil
+		}
+		pesertaDidik, err := s.repository.GetPesertaDidikByBarcode(barcode)
+		if err != nil {
+			return nil, nil, "Barcode tidak ditemukan", nil
+		}
+		return pesertaDidik, nil, "", nil
+	}
+
+	if config.ModeKartuQR != models.ModeKartuQRCampuran && config.ModeKartuQR != models.ModeKartuQRDinamis {
+		return nil, nil, "QR kartu pelajar dinamis tidak diaktifkan", nil
+	}
+
+	claims, err := utils.VerifyKartuQR(barcode, scannedAt, utils.KartuQRTTL, utils.KartuQRClockSkew)
+	if err != nil {
+		return nil, nil, err.Error(), nil
+	}
+
+	pesertaDidik, err := s.repository.GetPesertaDidikByID(claims.PesertaDidikID)
+	if err != nil {
+		return nil, nil, "Siswa tidak ditemukan", nil
+	}
+
+	used, err := s.repository.IsKartuQRNonceUsed(claims.Nonce)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if used {
+		return nil, nil, repositories.ErrKartuQRNonceSudahDipakai.Error(), nil
+	}
+
+	// Keep the nonce as long as an offline upload of the scan could still be accepted
+	nonce := &models.KartuQRNonce{
+		Nonce:          claims.Nonce,
+		PesertaDidikID: pesertaDidik.ID,
+		UsedAt:         time.Now(),
+		ExpiresAt:      claims.IssuedAt.Add(utils.KartuQRTTL + utils.KartuQRClockSkew + maxOfflineScanAge),
+	}
+	return pesertaDidik, nonce, "", nil
+}
+
+// recordScan validates a scan made at the given time against the konfigurasi and upserts the absensi row,
+// using up the signed QR nonce (nil for static barcodes) only when the row is saved.
+// The returned action is "inserted", "updated" or "skipped". The response is never nil, also when an error is returned.
+func (s *AbsensiScanServiceImpl) recordScan(pesertaDidik *models.PesertaDidik, nonce *models.KartuQRNonce, config *models.KonfigurasiAbsensi, now time.Time, scannerDeviceID uint) (*dtos.AbsensiScanResponse, string, error) {
+	// 1. Validate status peserta didik (must be active)
 	if pesertaDidik.Status != "active" {
 		return &dtos.AbsensiScanResponse{
 			Success: false,
 			Message: "Siswa tidak aktif, tidak dapat melakukan absensi",
-		}, nil
+		}, "skipped", nil
 	}
 
-	// 4. Get current time (with Asia/Jakarta timezone)
-	loc, err := time.LoadLocation("Asia/Jakarta")
+	// 2. Validate hari sekolah (weekend, libur, outside semester)
+	hariSekolah, keterangan, err := s.kalenderService.CekHariSekolah(now)
 	if err != nil {
-		// Fallback: use FixedZone if LoadLocation fails (for containers without tzdata)
-		loc = time.FixedZone("WIB", 7*60*60) // UTC+7
+		return &dtos.AbsensiScanResponse{
+			Success: false,
+			Message: "Gagal memeriksa hari sekolah",
+		}, "", err
 	}
-	now := time.Now().In(loc)
+	if !hariSekolah {
+		return &dtos.AbsensiScanResponse{
+			Success: false,
+			Message: fmt.Sprintf("Bukan hari sekolah (%s), absensi tidak dapat dicatat", keterangan),
+		}, "skipped", nil
+	}
+
+	// 3. Scan time (Asia/Jakarta)
 	currentDate := now.Format("2006-01-02")
 	currentTime := now.Format("15:04:05")
 
-	// 5. Validate time range
-	scanType, status, validationErr := s.validateScanTime(currentTime, config)
+	// 4. Validate time range against the student's schedule for the day
+	jadwal, err := s.jadwalService.ResolveJadwal(pesertaDidik.ID, now, config)
+	if err != nil {
+		return &dtos.AbsensiScanResponse{
+			Success: false,
+			Message: "Gagal memeriksa jadwal absensi",
+		}, "", err
+	}
+	scanType, status, validationErr := s.validateScanTime(currentTime, jadwal)
 	if validationErr != nil {
 		return &dtos.AbsensiScanResponse{
 			Success: false,
 			Message: validationErr.Error(),
-		}, nil
+		}, "skipped", nil
 	}
 
-	// 6. Check existing absensi
+	// 5. Check existing absensi
 	existingAbsensi, err := s.repository.GetAbsensiByPesertaDidikAndDate(pesertaDidik.ID, now)
 	
 	if err == nil {
-		// Record exists, check if already scanned for this type
-		if scanType == "datang" && existingAbsensi.JamDatang != nil {
+		// Record exists, check if already scanned for this type.
+		// An earlier datang (e.g. uploaded late from an offline device) replaces a later one.
+		if scanType == "datang" && existingAbsensi.JamDatang != nil && currentTime >= *existingAbsensi.JamDatang {
 			// Already scanned for datang
 			statusValue := "unknown"
 			if existingAbsensi.Status != nil {
@@ -98,7 +341,7 @@ func (s *AbsensiScanServiceImpl) ScanAbsensi(req *dtos.AbsensiScanRequest) (*dto
 					Status:    statusValue,
 					IsUpdate:  false,
 				},
-			}, nil
+			}, "skipped", nil
 		}
 		
 		if scanType == "pulang" {
@@ -108,7 +351,7 @@ func (s *AbsensiScanServiceImpl) ScanAbsensi(req *dtos.AbsensiScanRequest) (*dto
 				return &dtos.AbsensiScanResponse{
 					Success: false,
 					Message: "Anda belum melakukan absen datang, tidak dapat melakukan absen pulang",
-				}, nil
+				}, "skipped", nil
 			}
 			
 			// Already scanned for pulang
@@ -133,7 +376,7 @@ func (s *AbsensiScanServiceImpl) ScanAbsensi(req *dtos.AbsensiScanRequest) (*dto
 						Status:    statusValue,
 						IsUpdate:  false,
 					},
-				}, nil
+				}, "skipped", nil
 			}
 		}
 	} else {
@@ -143,11 +386,11 @@ func (s *AbsensiScanServiceImpl) ScanAbsensi(req *dtos.AbsensiScanRequest) (*dto
 			return &dtos.AbsensiScanResponse{
 				Success: false,
 				Message: "Anda belum melakukan absen datang, tidak dapat melakukan absen pulang",
-			}, nil
+			}, "skipped", nil
 		}
 	}
 	
-	// 7. Prepare absensi record
+	// 6. Prepare absensi record
 	var absensi *models.Absensi
 	isUpdate := false
 
@@ -163,7 +406,8 @@ func (s *AbsensiScanServiceImpl) ScanAbsensi(req *dtos.AbsensiScanRequest) (*dto
 		isUpdate = true
 	}
 
-	// 8. Update fields based on scan type
+	// 7. Update fields based on scan type and record the device that scanned
+	absensi.ScannerDeviceID = &scannerDeviceID
 	if scanType == "datang" {
 		absensi.JamDatang = &currentTime
 		absensi.Status = &status
@@ -172,15 +416,55 @@ func (s *AbsensiScanServiceImpl) ScanAbsensi(req *dtos.AbsensiScanRequest) (*dto
 		// Status tidak berubah saat pulang
 	}
 
-	// 9. Save to database using UPSERT
-	if err := s.repository.UpsertAbsensi(absensi); err != nil {
+	// 8. Save to database using UPSERT
+	if err := s.repository.UpsertAbsensi(absensi, nonce); err != nil {
+		if errors.Is(err, repositories.ErrKartuQRNonceSudahDipakai) {
+			// The same QR was saved by a concurrent scan
+			return &dtos.AbsensiScanResponse{
+				Success: false,
+				Message: err.Error(),
+			}, "skipped", nil
+		}
 		return &dtos.AbsensiScanResponse{
 			Success: false,
 			Message: "Gagal menyimpan data absensi",
-		}, err
+		}, "", err
 	}
 
-	// 10. Build response
+	action := "inserted"
+	if isUpdate {
+		action = "updated"
+	}
+
+	isToday := currentDate == time.Now().In(utils.JakartaLocation()).Format("2006-01-02")
+
+	// Tell opted-in parents about a late arrival today; old offline scans are not worth a message anymore
+	if scanType == "datang" && status == "terlambat" && isToday {
+		if err := s.notifikasiService.NotifyTerlambat(pesertaDidik, now); err != nil {
+			log.Printf("notifikasi terlambat peserta didik %d: %v", pesertaDidik.ID, err)
+		}
+	}
+
+	// Stream today's scans to the gate display and dashboards
+	if isToday {
+		event := dtos.AbsensiLiveScanEvent{
+			PesertaDidikID:  pesertaDidik.ID,
+			Nama:            pesertaDidik.Nama,
+			NISN:            pesertaDidik.NISN,
+			Tanggal:         currentDate,
+			Jam:             currentTime,
+			Jenis:           scanType,
+			Status:          *absensi.Status,
+			ScannerDeviceID: scannerDeviceID,
+		}
+		if jadwal.Rombel != nil {
+			event.RombelID = &jadwal.Rombel.ID
+			event.Rombel = jadwal.Rombel.Name
+		}
+		s.liveService.PublishScan(event)
+	}
+
+	// 9. Build response
 	return &dtos.AbsensiScanResponse{
 		Success: true,
 		Message: s.buildSuccessMessage(scanType, status, isUpdate),
@@ -196,17 +480,33 @@ func (s *AbsensiScanServiceImpl) ScanAbsensi(req *dtos.AbsensiScanRequest) (*dto
 			Status:    *absensi.Status,
 			IsUpdate:  isUpdate,
 		},
-	}, nil
+	}, action, nil
 }
 
-// validateScanTime validates if current time is within allowed range
-func (s *AbsensiScanServiceImpl) validateScanTime(currentTime string, config *models.KonfigurasiAbsensi) (scanType string, status string, err error) {
+// validateScanTime validates if the scan time is within the windows of the student's schedule
+// (schedule profile of the rombel/kelas, or the konfigurasi absensi when none is assigned)
+func (s *AbsensiScanServiceImpl) validateScanTime(currentTime string, jadwal *JadwalAbsensiEfektif) (scanType string, status string, err error) {
+	return validateJendelaScan(currentTime, jadwal.jendela())
+}
+
+// jendelaScan is the datang/pulang time window shared by the siswa and pegawai scan configurations
+type jendelaScan struct {
+	DatangMulai   string
+	MaxDatang     string
+	DatangSelesai string
+	PulangMulai   string
+	PulangSelesai string
+}
+
+// validateJendelaScan returns the scan type (datang/pulang) and datang status for a HH:MM:SS time,
+// or an error describing the allowed ranges when the time falls outside both windows
+func validateJendelaScan(currentTime string, jendela jendelaScan) (scanType string, status string, err error) {
 	// Check if within "datang" time range
-	if currentTime >= config.JamDatangMulai && currentTime <= config.JamDatangSelesai {
+	if currentTime >= jendela.DatangMulai && currentTime <= jendela.DatangSelesai {
 		scanType = "datang"
 		
 		// Check if tepat waktu or terlambat
-		if currentTime <= config.JamMaxDatang {
+		if currentTime <= jendela.MaxDatang {
 			status = "tepat_waktu"
 		} else {
 			status = "terlambat"
@@ -215,7 +515,7 @@ func (s *AbsensiScanServiceImpl) validateScanTime(currentTime string, config *mo
 	}
 
 	// Check if within "pulang" time range
-	if currentTime >= config.JamPulangMulai && currentTime <= config.JamPulangSelesai {
+	if currentTime >= jendela.PulangMulai && currentTime <= jendela.PulangSelesai {
 		scanType = "pulang"
 		status = "" // Status tidak berubah saat pulang
 		return scanType, status, nil
@@ -223,11 +523,16 @@ func (s *AbsensiScanServiceImpl) validateScanTime(currentTime string, config *mo
 
 	// Outside allowed time range - provide helpful error message
 	return "", "", fmt.Errorf("Scan absensi hanya dapat dilakukan pada jam yang ditentukan. Jam sekarang: %s. Rentang datang: %s-%s. Rentang pulang: %s-%s", 
-		currentTime, config.JamDatangMulai, config.JamDatangSelesai, config.JamPulangMulai, config.JamPulangSelesai)
+		currentTime, jendela.DatangMulai, jendela.DatangSelesai, jendela.PulangMulai, jendela.PulangSelesai)
 }
 
 // buildSuccessMessage creates success message based on scan type and status
 func (s *AbsensiScanServiceImpl) buildSuccessMessage(scanType, status string, isUpdate bool) string {
+	return buildScanSuccessMessage(scanType, status, isUpdate)
+}
+
+// buildScanSuccessMessage creates the scan success message shared by absensi siswa and absensi pegawai
+func buildScanSuccessMessage(scanType, status string, isUpdate bool) string {
 	action := "Absensi"
 	if isUpdate {
 		action = "Update absensi"
@@ -0,0 +1,392 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+	"testing"
+	"time"
+)
+
+// fakeAbsensiScanRepository keeps students, absensi rows, used QR nonces and scan logs in memory
+type fakeAbsensiScanRepository struct {
+	repositories.AbsensiScanRepository
+	siswa       map[string]*models.PesertaDidik // by barcode
+	absensi     map[string]models.Absensi       // by peserta didik ID and tanggal
+	nonces      map[string]bool
+	logs        []models.AbsensiScanLog
+	upserts     int
+	upsertErr   error
+	modeKartuQR string
+}
+
+func newFakeAbsensiScanRepository(siswa ...*models.PesertaDidik) *fakeAbsensiScanRepository {
+	r := &fakeAbsensiScanRepository{
+		siswa:   make(map[string]*models.PesertaDidik),
+		absensi: make(map[string]models.Absensi),
+		nonces:  make(map[string]bool),
+	}
+	for _, pesertaDidik := range siswa {
+		r.siswa[pesertaDidik.Barcode] = pesertaDidik
+	}
+	return r
+}
+
+func fakeAbsensiKey(pesertaDidikID uint, tanggal time.Time) string {
+	return fmt.Sprintf("%d:%s", pesertaDidikID, tanggal.Format("2006-01-02"))
+}
+
+func (r *fakeAbsensiScanRepository) GetPesertaDidikByBarcode(barcode string) (*models.PesertaDidik, error) {
+	if pesertaDidik, ok := r.siswa[barcode]; ok {
+		return pesertaDidik, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeAbsensiScanRepository) GetPesertaDidikByID(id uint) (*models.PesertaDidik, error) {
+	for _, pesertaDidik := range r.siswa {
+		if pesertaDidik.ID == id {
+			return pesertaDidik, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeAbsensiScanRepository) IsKartuQRNonceUsed(nonce string) (bool, error) {
+	return r.nonces[nonce], nil
+}
+
+func (r *fakeAbsensiScanRepository) GetKonfigurasiAbsensi() (*models.KonfigurasiAbsensi, error) {
+	return &models.KonfigurasiAbsensi{
+		ModeKartuQR:      r.modeKartuQR,
+		JamDatangMulai:   "06:00:00",
+		JamMaxDatang:     "07:00:00",
+		JamDatangSelesai: "09:00:00",
+		JamPulangMulai:   "13:00:00",
+		JamPulangSelesai: "16:00:00",
+	}, nil
+}
+
+func (r *fakeAbsensiScanRepository) GetAbsensiByPesertaDidikAndDate(pesertaDidikID uint, tanggal time.Time) (*models.Absensi, error) {
+	if absensi, ok := r.absensi[fakeAbsensiKey(pesertaDidikID, tanggal)]; ok {
+		return &absensi, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeAbsensiScanRepository) UpsertAbsensi(absensi *models.Absensi, nonce *models.KartuQRNonce) error {
+	if r.upsertErr != nil {
+		return r.upsertErr
+	}
+	if nonce != nil {
+		if r.nonces[nonce.Nonce] {
+			return repositories.ErrKartuQRNonceSudahDipakai
+		}
+		r.nonces[nonce.Nonce] = true
+	}
+	r.upserts++
+	r.absensi[fakeAbsensiKey(absensi.PesertaDidikID, absensi.Tanggal)] = *absensi
+	return nil
+}
+
+func (r *fakeAbsensiScanRepository) GetScanLogsByClientScanIDs(scannerDeviceID uint, clientScanIDs []string) ([]models.AbsensiScanLog, error) {
+	var logs []models.AbsensiScanLog
+	for _, log := range r.logs {
+		for _, clientScanID := range clientScanIDs {
+			if log.ScannerDeviceID == scannerDeviceID && log.ClientScanID == clientScanID {
+				logs = append(logs, log)
+			}
+		}
+	}
+	return logs, nil
+}
+
+func (r *fakeAbsensiScanRepository) CreateScanLog(log *models.AbsensiScanLog) error {
+	stored := *log
+	if pesertaDidik, ok := r.siswa[log.Barcode]; ok {
+		stored.PesertaDidik = pesertaDidik
+	}
+	r.logs = append(r.logs, stored)
+	return nil
+}
+
+// fakeKalenderAkademikService only implements CekHariSekolah, every day being a school day unless libur is set
+type fakeKalenderAkademikService struct {
+	KalenderAkademikService
+	libur string
+	err   error
+}
+
+func (s *fakeKalenderAkademikService) CekHariSekolah(tanggal time.Time) (bool, string, error) {
+	return s.err == nil && s.libur == "", s.libur, s.err
+}
+
+// fakeJadwalAbsensiService resolves every student to the konfigurasi absensi
+type fakeJadwalAbsensiService struct {
+	JadwalAbsensiService
+	err error
+}
+
+func (s *fakeJadwalAbsensiService) ResolveJadwal(pesertaDidikID uint, tanggal time.Time, fallback *models.KonfigurasiAbsensi) (*JadwalAbsensiEfektif, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &JadwalAbsensiEfektif{
+		JamDatangMulai:   fallback.JamDatangMulai,
+		JamMaxDatang:     fallback.JamMaxDatang,
+		JamDatangSelesai: fallback.JamDatangSelesai,
+		JamPulangMulai:   fallback.JamPulangMulai,
+		JamPulangSelesai: fallback.JamPulangSelesai,
+	}, nil
+}
+
+// newTestAbsensiScanService builds the scan service on an in-memory repository where every day is a school day
+func newTestAbsensiScanService(repository *fakeAbsensiScanRepository) *AbsensiScanServiceImpl {
+	return &AbsensiScanServiceImpl{
+		repository:      repository,
+		kalenderService: &fakeKalenderAkademikService{},
+		jadwalService:   &fakeJadwalAbsensiService{},
+	}
+}
+
+// testSiswa returns an active student scanned with the given barcode
+func testSiswa(id uint, barcode string) *models.PesertaDidik {
+	return &models.PesertaDidik{ID: id, Nama: fmt.Sprintf("Siswa %d", id), NISN: fmt.Sprintf("00%d", id), Barcode: barcode, Status: "active"}
+}
+
+// yesterdayAt returns a time of yesterday in Asia/Jakarta, old enough to skip the live and today-only effects
+func yesterdayAt(hour, minute int) time.Time {
+	y, m, d := time.Now().In(utils.JakartaLocation()).AddDate(0, 0, -1).Date()
+	return time.Date(y, m, d, hour, minute, 0, 0, utils.JakartaLocation())
+}
+
+func TestScanAbsensiBatchDedupe(t *testing.T) {
+	device := models.ScannerDevice{ID: 1}
+
+	tests := []struct {
+		name        string
+		stored      []models.AbsensiScanLog
+		items       []dtos.AbsensiScanBatchItem
+		wantActions []string // per detail, in scan time order
+		wantDup     int
+		wantUpserts int
+	}{
+		{
+			name: "new scans",
+			items: []dtos.AbsensiScanBatchItem{
+				{ClientScanID: "a", Barcode: "BRC-1", ScannedAt: yesterdayAt(6, 30)},
+				{ClientScanID: "b", Barcode: "BRC-2", ScannedAt: yesterdayAt(7, 30)},
+			},
+			wantActions: []string{"inserted", "inserted"},
+			wantUpserts: 2,
+		},
+		{
+			name: "replayed upload returns the stored result",
+			stored: []models.AbsensiScanLog{
+				{ScannerDeviceID: 1, ClientScanID: "a", Barcode: "BRC-1", Action: "inserted"},
+			},
+			items: []dtos.AbsensiScanBatchItem{
+				{ClientScanID: "a", Barcode: "BRC-1", ScannedAt: yesterdayAt(6, 30)},
+				{ClientScanID: "b", Barcode: "BRC-2", ScannedAt: yesterdayAt(7, 30)},
+			},
+			wantActions: []string{"inserted", "inserted"},
+			wantDup:     1,
+			wantUpserts: 1,
+		},
+		{
+			name: "client scan id stored for another device",
+			stored: []models.AbsensiScanLog{
+				{ScannerDeviceID: 2, ClientScanID: "a", Barcode: "BRC-1", Action: "inserted"},
+			},
+			items: []dtos.AbsensiScanBatchItem{
+				{ClientScanID: "a", Barcode: "BRC-1", ScannedAt: yesterdayAt(6, 30)},
+			},
+			wantActions: []string{"inserted"},
+			wantUpserts: 1,
+		},
+		{
+			name: "same scan twice in one batch",
+			items: []dtos.AbsensiScanBatchItem{
+				{ClientScanID: "a", Barcode: "BRC-1", ScannedAt: yesterdayAt(6, 30)},
+				{ClientScanID: "a", Barcode: "BRC-1", ScannedAt: yesterdayAt(6, 30)},
+			},
+			wantActions: []string{"inserted", "skipped"},
+			wantDup:     1,
+			wantUpserts: 1,
+		},
+		{
+			name: "pulang queued before datang is applied after it",
+			items: []dtos.AbsensiScanBatchItem{
+				{ClientScanID: "pulang", Barcode: "BRC-1", ScannedAt: yesterdayAt(14, 0)},
+				{ClientScanID: "datang", Barcode: "BRC-1", ScannedAt: yesterdayAt(6, 30)},
+			},
+			wantActions: []string{"inserted", "updated"},
+			wantUpserts: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := newFakeAbsensiScanRepository(testSiswa(1, "BRC-1"), testSiswa(2, "BRC-2"))
+			for _, log := range tt.stored {
+				repository.CreateScanLog(&log)
+			}
+			service := newTestAbsensiScanService(repository)
+
+			response, err := service.ScanAbsensiBatch(&dtos.AbsensiScanBatchRequest{Items: tt.items}, device)
+			if err != nil {
+				t.Fatalf("ScanAbsensiBatch() error = %v", err)
+			}
+
+			if len(response.Details) != len(tt.wantActions) {
+				t.Fatalf("details = %d, want %d", len(response.Details), len(tt.wantActions))
+			}
+			for i, want := range tt.wantActions {
+				if got := response.Details[i].Action; got != want {
+					t.Errorf("detail %d action = %q, want %q", i, got, want)
+				}
+			}
+			if response.TotalDuplicate != tt.wantDup {
+				t.Errorf("TotalDuplicate = %d, want %d", response.TotalDuplicate, tt.wantDup)
+			}
+			if repository.upserts != tt.wantUpserts {
+				t.Errorf("absensi upserts = %d, want %d", repository.upserts, tt.wantUpserts)
+			}
+			if len(repository.logs) != len(tt.stored)+len(tt.wantActions)-tt.wantDup {
+				t.Errorf("scan logs = %d, want one per applied scan", len(repository.logs)-len(tt.stored))
+			}
+		})
+	}
+}
+
+func TestScanAbsensiBatchRetryAfterFailure(t *testing.T) {
+	repository := newFakeAbsensiScanRepository(testSiswa(1, "BRC-1"))
+	service := newTestAbsensiScanService(repository)
+	req := &dtos.AbsensiScanBatchRequest{Items: []dtos.AbsensiScanBatchItem{
+		{ClientScanID: "a", Barcode: "BRC-1", ScannedAt: yesterdayAt(6, 30)},
+	}}
+
+	repository.upsertErr = errors.New("database tidak tersedia")
+	response, err := service.ScanAbsensiBatch(req, models.ScannerDevice{ID: 1})
+	if err != nil {
+		t.Fatalf("ScanAbsensiBatch() error = %v", err)
+	}
+	if response.TotalFailed != 1 || len(repository.logs) != 0 {
+		t.Fatalf("TotalFailed = %d, scan logs = %d, want 1 and 0", response.TotalFailed, len(repository.logs))
+	}
+
+	// The failed scan was not logged, so uploading it again applies it
+	repository.upsertErr = nil
+	response, err = service.ScanAbsensiBatch(req, models.ScannerDevice{ID: 1})
+	if err != nil {
+		t.Fatalf("ScanAbsensiBatch() retry error = %v", err)
+	}
+	if response.TotalInserted != 1 || response.TotalDuplicate != 0 {
+		t.Errorf("retry TotalInserted = %d, TotalDuplicate = %d, want 1 and 0", response.TotalInserted, response.TotalDuplicate)
+	}
+}
+
+func TestScanAbsensiBatchRetryKartuQRAfterFailure(t *testing.T) {
+	t.Setenv("KARTU_QR_SECRET", "test-secret")
+
+	repository := newFakeAbsensiScanRepository(testSiswa(1, "BRC-1"))
+	repository.modeKartuQR = models.ModeKartuQRDinamis
+	service := newTestAbsensiScanService(repository)
+	scannedAt := yesterdayAt(6, 30)
+	qr, err := utils.SignKartuQR(1, scannedAt)
+	if err != nil {
+		t.Fatalf("SignKartuQR() error = %v", err)
+	}
+	batch := func(clientScanID string) *dtos.AbsensiScanBatchRequest {
+		return &dtos.AbsensiScanBatchRequest{Items: []dtos.AbsensiScanBatchItem{
+			{ClientScanID: clientScanID, Barcode: qr, ScannedAt: scannedAt},
+		}}
+	}
+
+	repository.upsertErr = errors.New("database tidak tersedia")
+	response, err := service.ScanAbsensiBatch(batch("a"), models.ScannerDevice{ID: 1})
+	if err != nil {
+		t.Fatalf("ScanAbsensiBatch() error = %v", err)
+	}
+	if response.TotalFailed != 1 || len(repository.nonces) != 0 {
+		t.Fatalf("TotalFailed = %d, used nonces = %d, want 1 and 0", response.TotalFailed, len(repository.nonces))
+	}
+
+	// The nonce was not used up by the failed save, so the retry is not taken for a replay
+	repository.upsertErr = nil
+	response, err = service.ScanAbsensiBatch(batch("a"), models.ScannerDevice{ID: 1})
+	if err != nil {
+		t.Fatalf("ScanAbsensiBatch() retry error = %v", err)
+	}
+	if response.TotalInserted != 1 {
+		t.Fatalf("retry TotalInserted = %d, reason %q, want 1", response.TotalInserted, response.Details[0].Reason)
+	}
+
+	// Once saved, the same QR under another client scan ID is a replay
+	response, err = service.ScanAbsensiBatch(batch("b"), models.ScannerDevice{ID: 1})
+	if err != nil {
+		t.Fatalf("ScanAbsensiBatch() replay error = %v", err)
+	}
+	if response.TotalSkipped != 1 || response.Details[0].Reason != repositories.ErrKartuQRNonceSudahDipakai.Error() {
+		t.Errorf("replay TotalSkipped = %d, reason %q, want 1 and %q", response.TotalSkipped, response.Details[0].Reason, repositories.ErrKartuQRNonceSudahDipakai)
+	}
+}
+
+func TestScanAbsensiBatchRecordScanError(t *testing.T) {
+	tests := []struct {
+		name        string
+		kalenderErr error
+		jadwalErr   error
+		wantReason  string
+	}{
+		{
+			name:        "cek hari sekolah gagal",
+			kalenderErr: errors.New("kalender tidak dapat dibaca"),
+			wantReason:  "kalender tidak dapat dibaca",
+		},
+		{
+			name:       "resolve jadwal gagal",
+			jadwalErr:  errors.New("penugasan jadwal tidak dapat dibaca"),
+			wantReason: "penugasan jadwal tidak dapat dibaca",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := newFakeAbsensiScanRepository(testSiswa(7, "BRC-1"))
+			service := NewAbsensiScanService(
+				repository,
+				&fakeKalenderAkademikService{err: tt.kalenderErr},
+				nil,
+				&fakeJadwalAbsensiService{err: tt.jadwalErr},
+				nil,
+			)
+
+			req := &dtos.AbsensiScanBatchRequest{Items: []dtos.AbsensiScanBatchItem{
+				{ClientScanID: "scan-1", Barcode: "BRC-1", ScannedAt: yesterdayAt(6, 30)},
+			}}
+			response, err := service.ScanAbsensiBatch(req, models.ScannerDevice{ID: 1})
+			if err != nil {
+				t.Fatalf("ScanAbsensiBatch() error = %v", err)
+			}
+
+			if response.TotalFailed != 1 || len(response.Details) != 1 {
+				t.Fatalf("TotalFailed = %d, details = %d, want 1 and 1", response.TotalFailed, len(response.Details))
+			}
+			detail := response.Details[0]
+			if detail.Action != "failed" || detail.Reason != tt.wantReason {
+				t.Errorf("detail = %q/%q, want failed/%q", detail.Action, detail.Reason, tt.wantReason)
+			}
+			if detail.PesertaDidikID != 7 {
+				t.Errorf("PesertaDidikID = %d, want 7", detail.PesertaDidikID)
+			}
+			// Failed scans are not logged so the device can retry them
+			if len(repository.logs) != 0 {
+				t.Errorf("stored %d scan logs, want 0", len(repository.logs))
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"mime/multipart"
@@ -17,44 +18,62 @@ import (
 )
 
 type AbsensiService interface {
-	CreateAbsensiManual(req *dtos.AbsensiManualCreateRequest, files map[uint][]*multipart.FileHeader, userID uint) (*dtos.AbsensiManualCreateResponse, error)
-	CreateAbsensiManualByID(req *dtos.AbsensiManualCreateByIDRequest, file *multipart.FileHeader, userID uint) (*dtos.AbsensiResponse, error)
+	CreateAbsensiManual(ctx context.Context, req *dtos.AbsensiManualCreateRequest, files map[uint][]*multipart.FileHeader, actor utils.Principal, ipAddress string) (*dtos.AbsensiManualCreateResponse, error)
+	CreateAbsensiManualByID(ctx context.Context, req *dtos.AbsensiManualCreateByIDRequest, file *multipart.FileHeader, actor utils.Principal, ipAddress string) (*dtos.AbsensiResponse, error)
 	GetRekapAbsensi(req *dtos.AbsensiRekapRequest) (*dtos.AbsensiRekapResponse, error)
-	UpdateRekapAbsensi(id uint, req *dtos.AbsensiUpdateRequest, file *multipart.FileHeader, userID uint) (*dtos.AbsensiUpdateResponse, error)
+	UpdateRekapAbsensi(ctx context.Context, id uint, req *dtos.AbsensiUpdateRequest, file *multipart.FileHeader, actor utils.Principal, ipAddress string) (*dtos.AbsensiUpdateResponse, error)
 	GetDashboardSummary(req *dtos.DashboardSummaryRequest) (*dtos.DashboardSummaryResponse, error)
 	GetGrafikKehadiran(req *dtos.GrafikKehadiranRequest) (*dtos.GrafikKehadiranResponse, error)
 	GetStatistikPerHari(req *dtos.StatistikPerHariRequest) (*dtos.StatistikPerHariResponse, error)
 	GetPerbandinganRombel(req *dtos.PerbandinganRombelRequest) (*dtos.PerbandinganRombelResponse, error)
 	GetSiswaTerendah(req *dtos.SiswaTerendahRequest) (*dtos.SiswaTerendahResponse, error)
 	GetDashboardSiswa(req *dtos.DashboardSiswaRequest) (*dtos.DashboardSiswaResponse, error)
-	SynchronizeAbsensi(req *dtos.AbsensiSyncRequest, userID uint) (*dtos.AbsensiSyncResponse, error)
+	SynchronizeAbsensi(req *dtos.AbsensiSyncRequest, actor utils.Principal, ipAddress string) (*dtos.AbsensiSyncResponse, error)
+	GetRiwayatRekapAbsensi(req *dtos.RiwayatRekapAbsensiRequest) ([]dtos.RiwayatRekapAbsensiResponse, error)
+	DownloadTemplateImport() (*excelize.File, error)
+	ImportExcel(file multipart.File, tahunPelajaranID, rombelID uint, actor utils.Principal, ipAddress string) (*dtos.AbsensiImportResponse, error)
 	ExportAbsensiExcel(req *dtos.ExportAbsensiExcelRequest) (*excelize.File, error)
 	ExportAbsensiPDF(req *dtos.ExportAbsensiExcelRequest) ([]byte, error)
 }
 
 type AbsensiServiceImpl struct {
-	repository repositories.AbsensiRepository
-	r2Storage  *utils.R2Storage
-	db         *gorm.DB
+	repository        repositories.AbsensiRepository
+	riwayatRepository repositories.RiwayatRekapitulasiAbsensiRepository
+	kalenderService   KalenderAkademikService
+	periodeService    PeriodeAkademikService
+	storage           utils.Storage
+	db                *gorm.DB
 }
 
 // NewAbsensiService creates a new Absensi service
-func NewAbsensiService(repository repositories.AbsensiRepository, db *gorm.DB) AbsensiService {
+func NewAbsensiService(repository repositories.AbsensiRepository, riwayatRepository repositories.RiwayatRekapitulasiAbsensiRepository, kalenderService KalenderAkademikService, periodeService PeriodeAkademikService, db *gorm.DB) AbsensiService {
 	return &AbsensiServiceImpl{
-		repository: repository,
-		r2Storage:  utils.NewR2Storage(),
-		db:         db,
+		repository:        repository,
+		riwayatRepository: riwayatRepository,
+		kalenderService:   kalenderService,
+		periodeService:    periodeService,
+		storage:           utils.NewStorage(),
+		db:                db,
 	}
 }
 
 // CreateAbsensiManual creates multiple absensi records (bulk input) with file upload support
-func (s *AbsensiServiceImpl) CreateAbsensiManual(req *dtos.AbsensiManualCreateRequest, files map[uint][]*multipart.FileHeader, userID uint) (*dtos.AbsensiManualCreateResponse, error) {
+func (s *AbsensiServiceImpl) CreateAbsensiManual(ctx context.Context, req *dtos.AbsensiManualCreateRequest, files map[uint][]*multipart.FileHeader, actor utils.Principal, ipAddress string) (*dtos.AbsensiManualCreateResponse, error) {
 	// Parse tanggal (YYYY-MM-DD format)
 	tanggal, err := time.Parse("2006-01-02", req.Tanggal)
 	if err != nil {
 		return nil, errors.New("format tanggal tidak valid, gunakan YYYY-MM-DD")
 	}
 
+	// The date must belong to the requested tahun pelajaran and semester
+	periode, err := s.periodeService.ResolvePeriode(tanggal)
+	if err != nil {
+		return nil, err
+	}
+	if err := cocokkanPeriode(periode, req.TahunPelajaranID, req.Semester); err != nil {
+		return nil, err
+	}
+
 	// Validasi duplicate untuk guru kelas (bidang_studi_id = NULL)
 	if req.BidangStudiID == nil {
 		// Check if already exists for this rombel, tahun_pelajaran, semester, tanggal
@@ -103,6 +122,7 @@ func (s *AbsensiServiceImpl) CreateAbsensiManual(req *dtos.AbsensiManualCreateRe
 	totalFailed := 0
 	var errorItems []dtos.AbsensiCreateErrorItem
 	var uploadedFiles []string // Track uploaded files for cleanup
+	audit := auditRekapAbsensi(models.SumberRiwayatRekapManual, actor, ipAddress)
 
 	// Start database transaction
 	tx := s.db.Begin()
@@ -116,7 +136,7 @@ func (s *AbsensiServiceImpl) CreateAbsensiManual(req *dtos.AbsensiManualCreateRe
 			tx.Rollback()
 			// Clean up uploaded files
 			for _, filePath := range uploadedFiles {
-				s.r2Storage.DeleteFile(filePath)
+				s.storage.DeleteFile(filePath)
 			}
 		}
 	}()
@@ -129,18 +149,30 @@ func (s *AbsensiServiceImpl) CreateAbsensiManual(req *dtos.AbsensiManualCreateRe
 			tx.Rollback()
 			// Clean up uploaded files
 			for _, filePath := range uploadedFiles {
-				s.r2Storage.DeleteFile(filePath)
+				s.storage.DeleteFile(filePath)
 			}
 			return nil, fmt.Errorf("data peserta didik rombel ID %d tidak ditemukan", item.PesertaDidikRombelID)
 		}
 
 		// Check if absensi already exists for this student on this date and mapel
 		existing, _ := s.repository.GetByPesertaDidikTanggalMapel(item.PesertaDidikRombelID, tanggal, req.BidangStudiID)
+		if existing != nil && existing.MetodeInput == models.MetodeInputAlpaOtomatis {
+			// Replace the row written by the alpa job
+			if err := repositories.HapusRekapAlpaOtomatis(tx, existing, audit); err != nil {
+				tx.Rollback()
+				// Clean up uploaded files
+				for _, filePath := range uploadedFiles {
+					s.storage.DeleteFile(filePath)
+				}
+				return nil, fmt.Errorf("gagal mengganti alpa otomatis untuk peserta didik rombel ID %d: %s", item.PesertaDidikRombelID, err.Error())
+			}
+			existing = nil
+		}
 		if existing != nil {
 			tx.Rollback()
 			// Clean up uploaded files
 			for _, filePath := range uploadedFiles {
-				s.r2Storage.DeleteFile(filePath)
+				s.storage.DeleteFile(filePath)
 			}
 			var errorMsg string
 			if req.BidangStudiID == nil {
@@ -158,15 +190,16 @@ func (s *AbsensiServiceImpl) CreateAbsensiManual(req *dtos.AbsensiManualCreateRe
 			fileHeader := fileHeaders[0]
 			
 			// Upload to R2 in absensi-siswa folder
-			uploadedPath, err := s.r2Storage.UploadFile(fileHeader, "absensi-siswa")
+			stored, err := utils.SaveUpload(ctx, s.storage, fileHeader, utils.UploadCategoryDokumen, "absensi-siswa")
 			if err != nil {
 				tx.Rollback()
 				// Clean up uploaded files
 				for _, filePath := range uploadedFiles {
-					s.r2Storage.DeleteFile(filePath)
+					s.storage.DeleteFile(filePath)
 				}
 				return nil, fmt.Errorf("gagal upload file untuk peserta didik rombel ID %d: %s", item.PesertaDidikRombelID, err.Error())
 			}
+			uploadedPath := stored.Key
 			fileSuratPath = uploadedPath
 			uploadedFiles = append(uploadedFiles, uploadedPath) // Track for cleanup
 		}
@@ -185,14 +218,19 @@ func (s *AbsensiServiceImpl) CreateAbsensiManual(req *dtos.AbsensiManualCreateRe
 			MetodeInput:          "manual",
 			Keterangan:           item.Keterangan,
 			FileSurat:            fileSuratPath,
-			DicatatOlehID:        &userID,
+			DicatatOlehID:        &actor.ID,
+			DicatatOlehType:      actor.TypePtr(),
 		}
 
-		if err := tx.Create(absensi).Error; err != nil {
+		err = tx.Create(absensi).Error
+		if err == nil {
+			err = repositories.CatatRiwayatRekapAbsensi(tx, nil, absensi, audit)
+		}
+		if err != nil {
 			tx.Rollback()
 			// Clean up uploaded files
 			for _, filePath := range uploadedFiles {
-				s.r2Storage.DeleteFile(filePath)
+				s.storage.DeleteFile(filePath)
 			}
 			return nil, fmt.Errorf("gagal menyimpan data untuk peserta didik rombel ID %d: %s", item.PesertaDidikRombelID, err.Error())
 		}
@@ -204,7 +242,7 @@ func (s *AbsensiServiceImpl) CreateAbsensiManual(req *dtos.AbsensiManualCreateRe
 	if err := tx.Commit().Error; err != nil {
 		// Clean up uploaded files if commit fails
 		for _, filePath := range uploadedFiles {
-			s.r2Storage.DeleteFile(filePath)
+			s.storage.DeleteFile(filePath)
 		}
 		return nil, errors.New("gagal menyimpan transaksi ke database")
 	}
@@ -219,22 +257,23 @@ func (s *AbsensiServiceImpl) CreateAbsensiManual(req *dtos.AbsensiManualCreateRe
 	}, nil
 }
 
-// CreateAbsensiManualByID creates a single absensi record by peserta didik rombel ID with auto semester detection
-func (s *AbsensiServiceImpl) CreateAbsensiManualByID(req *dtos.AbsensiManualCreateByIDRequest, file *multipart.FileHeader, userID uint) (*dtos.AbsensiResponse, error) {
+// CreateAbsensiManualByID creates a single absensi record by peserta didik rombel ID, the semester is resolved from the tanggal
+func (s *AbsensiServiceImpl) CreateAbsensiManualByID(ctx context.Context, req *dtos.AbsensiManualCreateByIDRequest, file *multipart.FileHeader, actor utils.Principal, ipAddress string) (*dtos.AbsensiResponse, error) {
 	// Parse tanggal (YYYY-MM-DD format)
 	tanggal, err := time.Parse("2006-01-02", req.Tanggal)
 	if err != nil {
 		return nil, errors.New("format tanggal tidak valid, gunakan YYYY-MM-DD")
 	}
 
-	// Auto detect semester based on month
-	// July-December (7-12) = Semester 1
-	// January-June (1-6) = Semester 2
-	month := int(tanggal.Month())
-	semester := 2
-	if month >= 7 && month <= 12 {
-		semester = 1
+	// Resolve semester from the semester dates of tahun pelajaran
+	periode, err := s.periodeService.ResolvePeriode(tanggal)
+	if err != nil {
+		return nil, err
+	}
+	if periode.TahunPelajaranID != req.TahunPelajaranID {
+		return nil, fmt.Errorf("tanggal termasuk tahun pelajaran %s", periode.TahunPelajaran)
 	}
+	semester := periode.Semester
 
 	// Parse waktu_absen if provided (YYYY-MM-DD HH:MM:SS format)
 	var waktuAbsen *time.Time
@@ -258,6 +297,12 @@ func (s *AbsensiServiceImpl) CreateAbsensiManualByID(req *dtos.AbsensiManualCrea
 
 	// Check if absensi already exists for this student on this date and mapel
 	existing, _ := s.repository.GetByPesertaDidikTanggalMapel(req.PesertaDidikRombelID, tanggal, req.BidangStudiID)
+	var alpaOtomatis *models.RekapitulasiAbsensi
+	if existing != nil && existing.MetodeInput == models.MetodeInputAlpaOtomatis {
+		// Replace the row written by the alpa job, in the same transaction as the insert below
+		alpaOtomatis = existing
+		existing = nil
+	}
 	if existing != nil {
 		var errorMsg string
 		if req.BidangStudiID == nil {
@@ -272,10 +317,11 @@ func (s *AbsensiServiceImpl) CreateAbsensiManualByID(req *dtos.AbsensiManualCrea
 	var fileSuratPath string
 	if file != nil {
 		// Upload to R2 in absensi-siswa folder
-		uploadedPath, err := s.r2Storage.UploadFile(file, "absensi-siswa")
+		stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryDokumen, "absensi-siswa")
 		if err != nil {
 			return nil, fmt.Errorf("gagal upload file: %s", err.Error())
 		}
+		uploadedPath := stored.Key
 		fileSuratPath = uploadedPath
 	}
 
@@ -284,7 +330,7 @@ func (s *AbsensiServiceImpl) CreateAbsensiManualByID(req *dtos.AbsensiManualCrea
 		PesertaDidikRombelID: req.PesertaDidikRombelID,
 		RombelID:             &req.RombelID,
 		TahunPelajaranID:     req.TahunPelajaranID,
-		Semester:             semester, // Auto detected
+		Semester:             semester, // Resolved from tanggal
 		Tanggal:              tanggal,
 		BidangStudiID:        req.BidangStudiID, // NULL = guru kelas, NOT NULL = guru mapel
 		PertemuanKe:          req.PertemuanKe,   // NULL = guru kelas, NOT NULL = guru mapel
@@ -293,15 +339,31 @@ func (s *AbsensiServiceImpl) CreateAbsensiManualByID(req *dtos.AbsensiManualCrea
 		MetodeInput:          "manual",
 		Keterangan:           req.Keterangan,
 		FileSurat:            fileSuratPath,
-		DicatatOlehID:        &userID,
+		DicatatOlehID:        &actor.ID,
+		DicatatOlehType:      actor.TypePtr(),
 	}
 
-	if err := s.db.Create(absensi).Error; err != nil {
+	audit := auditRekapAbsensi(models.SumberRiwayatRekapManual, actor, ipAddress)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if alpaOtomatis != nil {
+			if err := repositories.HapusRekapAlpaOtomatis(tx, alpaOtomatis, audit); err != nil {
+				return fmt.Errorf("gagal mengganti alpa otomatis: %s", err.Error())
+			}
+		}
+		if err := tx.Create(absensi).Error; err != nil {
+			return fmt.Errorf("gagal menyimpan data absensi: %s", err.Error())
+		}
+		if err := repositories.CatatRiwayatRekapAbsensi(tx, nil, absensi, audit); err != nil {
+			return fmt.Errorf("gagal menyimpan riwayat absensi: %s", err.Error())
+		}
+		return nil
+	})
+	if err != nil {
 		// Clean up uploaded file if save fails
 		if fileSuratPath != "" {
-			s.r2Storage.DeleteFile(fileSuratPath)
+			s.storage.DeleteFile(fileSuratPath)
 		}
-		return nil, fmt.Errorf("gagal menyimpan data absensi: %s", err.Error())
+		return nil, err
 	}
 
 	// Load relationships for response
@@ -309,6 +371,7 @@ func (s *AbsensiServiceImpl) CreateAbsensiManualByID(req *dtos.AbsensiManualCrea
 		Preload("Rombel").
 		Preload("BidangStudi").
 		Preload("DicatatOleh").
+		Preload("DicatatOlehPegawai").
 		First(absensi, absensi.ID)
 
 	// Map to response
@@ -432,24 +495,29 @@ func (s *AbsensiServiceImpl) GetRekapAbsensi(req *dtos.AbsensiRekapRequest) (*dt
 		}
 
 		dicatatOleh := ""
-		if absensi.DicatatOleh != nil {
+		if absensi.DicatatOlehType != nil && *absensi.DicatatOlehType == utils.PrincipalPegawai {
+			if absensi.DicatatOlehPegawai != nil {
+				dicatatOleh = absensi.DicatatOlehPegawai.Nama
+			}
+		} else if absensi.DicatatOleh != nil {
 			dicatatOleh = absensi.DicatatOleh.Nama
 		}
 
 		// Generate full URL for file_surat
-		fileSuratURL := s.r2Storage.GetPublicURL(absensi.FileSurat)
+		fileSuratURL := utils.FileURL(s.storage, absensi.FileSurat)
 
 		siswa.DetailPerTanggal = append(siswa.DetailPerTanggal, dtos.AbsensiDetailTanggal{
-			ID:            absensi.ID,
-			Tanggal:       absensi.Tanggal.Format("2006-01-02"),
-			PertemuanKe:   absensi.PertemuanKe, // Will be nil for guru kelas, has value for guru mapel
-			Status:        absensi.Status,
-			WaktuAbsen:    waktuAbsen,
-			MetodeInput:   absensi.MetodeInput,
-			Keterangan:    absensi.Keterangan,
-			FileSurat:     fileSuratURL,
-			DicatatOleh:   dicatatOleh,
-			DicatatOlehID: absensi.DicatatOlehID,
+			ID:              absensi.ID,
+			Tanggal:         absensi.Tanggal.Format("2006-01-02"),
+			PertemuanKe:     absensi.PertemuanKe, // Will be nil for guru kelas, has value for guru mapel
+			Status:          absensi.Status,
+			WaktuAbsen:      waktuAbsen,
+			MetodeInput:     absensi.MetodeInput,
+			Keterangan:      absensi.Keterangan,
+			FileSurat:       fileSuratURL,
+			DicatatOleh:     dicatatOleh,
+			DicatatOlehID:   absensi.DicatatOlehID,
+			DicatatOlehType: absensi.DicatatOlehType,
 		})
 	}
 
@@ -486,7 +554,7 @@ func (s *AbsensiServiceImpl) GetRekapAbsensi(req *dtos.AbsensiRekapRequest) (*dt
 }
 
 // UpdateRekapAbsensi updates a single absensi record
-func (s *AbsensiServiceImpl) UpdateRekapAbsensi(id uint, req *dtos.AbsensiUpdateRequest, file *multipart.FileHeader, userID uint) (*dtos.AbsensiUpdateResponse, error) {
+func (s *AbsensiServiceImpl) UpdateRekapAbsensi(ctx context.Context, id uint, req *dtos.AbsensiUpdateRequest, file *multipart.FileHeader, actor utils.Principal, ipAddress string) (*dtos.AbsensiUpdateResponse, error) {
 	// Get existing absensi record
 	existing, err := s.repository.GetByID(id)
 	if err != nil {
@@ -495,6 +563,9 @@ func (s *AbsensiServiceImpl) UpdateRekapAbsensi(id uint, req *dtos.AbsensiUpdate
 
 	oldFileSurat := existing.FileSurat
 
+	// The surat of an approved pengajuan izin is shared by every day of the request, keep it in R2
+	sharedFileSurat := existing.MetodeInput == models.MetodeInputPengajuan
+
 	// Update status
 	existing.Status = req.Status
 
@@ -502,13 +573,14 @@ func (s *AbsensiServiceImpl) UpdateRekapAbsensi(id uint, req *dtos.AbsensiUpdate
 	existing.Keterangan = req.Keterangan
 
 	// Update dicatat_oleh_id
-	existing.DicatatOlehID = &userID
+	existing.DicatatOlehID = &actor.ID
+	existing.DicatatOlehType = actor.TypePtr()
 
 	// Handle file deletion if requested
 	if req.DeleteFileSurat {
 		// Delete old file from R2 if exists
-		if oldFileSurat != "" {
-			_ = s.r2Storage.DeleteFile(oldFileSurat)
+		if oldFileSurat != "" && !sharedFileSurat {
+			_ = s.storage.DeleteFile(oldFileSurat)
 		}
 		existing.FileSurat = ""
 	}
@@ -516,24 +588,25 @@ func (s *AbsensiServiceImpl) UpdateRekapAbsensi(id uint, req *dtos.AbsensiUpdate
 	// Handle file upload if provided (this will override delete_file_surat if both are sent)
 	if file != nil {
 		// Upload new file to R2
-		uploadedPath, err := s.r2Storage.UploadFile(file, "absensi-siswa")
+		stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryDokumen, "absensi-siswa")
 		if err != nil {
 			return nil, fmt.Errorf("gagal upload file: %s", err.Error())
 		}
+		uploadedPath := stored.Key
 
 		// Delete old file from R2 if exists (only if different from new file)
-		if oldFileSurat != "" && oldFileSurat != uploadedPath {
-			_ = s.r2Storage.DeleteFile(oldFileSurat)
+		if oldFileSurat != "" && oldFileSurat != uploadedPath && !sharedFileSurat {
+			_ = s.storage.DeleteFile(oldFileSurat)
 		}
 
 		existing.FileSurat = uploadedPath
 	}
 
-	// Save to database
-	if err := s.repository.Update(existing); err != nil {
+	// Save to database, the stored values are kept in riwayat_rekapitulasi_absensi
+	if err := s.repository.Update(existing, auditRekapAbsensi(models.SumberRiwayatRekapManual, actor, ipAddress)); err != nil {
 		// If update failed and new file was uploaded, delete the new file
 		if file != nil && existing.FileSurat != "" && existing.FileSurat != oldFileSurat {
-			s.r2Storage.DeleteFile(existing.FileSurat)
+			s.storage.DeleteFile(existing.FileSurat)
 		}
 		return nil, errors.New("gagal mengupdate data absensi")
 	}
@@ -569,8 +642,9 @@ func (s *AbsensiServiceImpl) mapToResponse(data *models.RekapitulasiAbsensi) *dt
 		Status:           data.Status,
 		MetodeInput:      data.MetodeInput,
 		Keterangan:       data.Keterangan,
-		FileSurat:        s.r2Storage.GetPublicURL(data.FileSurat),
+		FileSurat:        utils.FileURL(s.storage, data.FileSurat),
 		DicatatOlehID:    data.DicatatOlehID,
+		DicatatOlehType:  data.DicatatOlehType,
 		CreatedAt:        data.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt:        data.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
@@ -611,15 +685,15 @@ func (s *AbsensiServiceImpl) GetDashboardSummary(req *dtos.DashboardSummaryReque
 		tanggalSelesai = &t
 	}
 	
-	// Get all absensi records based on filters
-	absensiList, err := s.repository.GetDashboardSummary(
-		req.TahunPelajaranID,
-		req.RombelID,
-		req.Semester,
-		req.BidangStudiID,
-		tanggalMulai,
-		tanggalSelesai,
-	)
+	// Count statuses per tanggal in SQL
+	perTanggal, err := s.repository.CountStatusPerPeriode(repositories.AbsensiAggregateFilter{
+		TahunPelajaranID: req.TahunPelajaranID,
+		RombelID:         req.RombelID,
+		Semester:         req.Semester,
+		BidangStudiID:    req.BidangStudiID,
+		TanggalMulai:     tanggalMulai,
+		TanggalSelesai:   tanggalSelesai,
+	}, "day")
 	if err != nil {
 		return nil, errors.New("gagal mengambil data absensi")
 	}
@@ -635,37 +709,34 @@ func (s *AbsensiServiceImpl) GetDashboardSummary(req *dtos.DashboardSummaryReque
 		return nil, errors.New("gagal menghitung jumlah siswa")
 	}
 	
+	// Ignore non-school days and count effective school days in range
+	perTanggal, totalHariEfektif, err := s.hariEfektifPerTanggal(perTanggal, tanggalMulai, tanggalSelesai)
+	if err != nil {
+		return nil, err
+	}
+	
 	// Calculate summary statistics
 	totalHadir := 0
 	totalSakit := 0
 	totalIzin := 0
 	totalAlpa := 0
 	
-	// Count unique dates (pertemuan)
-	dateMap := make(map[string]bool)
-	
-	for _, absensi := range absensiList {
-		// Count by status
-		switch absensi.Status {
-		case "hadir":
-			totalHadir++
-		case "sakit":
-			totalSakit++
-		case "izin":
-			totalIzin++
-		case "alpa":
-			totalAlpa++
-		}
-		
-		// Track unique dates
-		dateKey := absensi.Tanggal.Format("2006-01-02")
-		dateMap[dateKey] = true
+	for _, count := range perTanggal {
+		totalHadir += count.Hadir
+		totalSakit += count.Sakit
+		totalIzin += count.Izin
+		totalAlpa += count.Alpa
 	}
 	
-	totalPertemuan := len(dateMap)
+	// Every tanggal with records is a pertemuan
+	totalPertemuan := len(perTanggal)
 	
 	// Calculate persentase kehadiran (rounded to 2 decimal places)
 	totalKehadiran := totalHadir + totalSakit + totalIzin + totalAlpa
+	if req.BidangStudiID == nil && totalSiswa*totalHariEfektif > totalKehadiran {
+		// Guru kelas records every school day, so unrecorded effective days count as not present
+		totalKehadiran = totalSiswa * totalHariEfektif
+	}
 	persentaseKehadiran := 0.0
 	if totalKehadiran > 0 {
 		persentaseKehadiran = float64(int(float64(totalHadir) / float64(totalKehadiran) * 10000)) / 100
@@ -673,8 +744,9 @@ func (s *AbsensiServiceImpl) GetDashboardSummary(req *dtos.DashboardSummaryReque
 	
 	// Build response
 	response := &dtos.DashboardSummaryResponse{
-		TotalSiswa:     totalSiswa,
-		TotalPertemuan: totalPertemuan,
+		TotalSiswa:       totalSiswa,
+		TotalPertemuan:   totalPertemuan,
+		TotalHariEfektif: totalHariEfektif,
 		Summary: dtos.SummaryKehadiran{
 			TotalHadir:          totalHadir,
 			TotalSakit:          totalSakit,
@@ -685,28 +757,16 @@ func (s *AbsensiServiceImpl) GetDashboardSummary(req *dtos.DashboardSummaryReque
 	}
 	
 	// Calculate trend (always show, even if only 1 date)
-	trend := s.calculateTrendFromData(absensiList, dateMap)
+	trend := s.calculateTrendFromData(perTanggal)
 	response.Trend = trend
 	
 	return response, nil
 }
 
 // calculateTrendFromData calculates attendance trend from the last 2 dates in data
-func (s *AbsensiServiceImpl) calculateTrendFromData(absensiList []models.RekapitulasiAbsensi, dateMap map[string]bool) *dtos.TrendKehadiran {
-	// Get all unique dates and sort them
-	var dates []time.Time
-	for dateStr := range dateMap {
-		date, _ := time.Parse("2006-01-02", dateStr)
-		dates = append(dates, date)
-	}
-	
-	// Sort dates descending (newest first)
-	sort.Slice(dates, func(i, j int) bool {
-		return dates[i].After(dates[j])
-	})
-	
+func (s *AbsensiServiceImpl) calculateTrendFromData(perTanggal []repositories.AbsensiPeriodeCount) *dtos.TrendKehadiran {
 	// If no dates, return zero trend
-	if len(dates) == 0 {
+	if len(perTanggal) == 0 {
 		return &dtos.TrendKehadiran{
 			HadirKemarin: "0",
 			HadirHariIni: "0",
@@ -714,17 +774,11 @@ func (s *AbsensiServiceImpl) calculateTrendFromData(absensiList []models.Rekapit
 		}
 	}
 	
+	// Rows are ordered by tanggal, so the newest date is last
+	hadirHariIni := perTanggal[len(perTanggal)-1].Hadir
+	
 	// If only 1 date, show it as "hari ini" with 0 for "kemarin"
-	if len(dates) == 1 {
-		hariIni := dates[0]
-		hadirHariIni := 0
-		
-		for _, absensi := range absensiList {
-			if absensi.Status == "hadir" && absensi.Tanggal.Format("2006-01-02") == hariIni.Format("2006-01-02") {
-				hadirHariIni++
-			}
-		}
-		
+	if len(perTanggal) == 1 {
 		return &dtos.TrendKehadiran{
 			HadirKemarin: "0",
 			HadirHariIni: fmt.Sprintf("%d", hadirHariIni),
@@ -732,22 +786,7 @@ func (s *AbsensiServiceImpl) calculateTrendFromData(absensiList []models.Rekapit
 		}
 	}
 	
-	// Get the last 2 dates
-	hariIni := dates[0]
-	kemarin := dates[1]
-	
-	hadirKemarin := 0
-	hadirHariIni := 0
-	
-	for _, absensi := range absensiList {
-		if absensi.Status == "hadir" {
-			if absensi.Tanggal.Format("2006-01-02") == kemarin.Format("2006-01-02") {
-				hadirKemarin++
-			} else if absensi.Tanggal.Format("2006-01-02") == hariIni.Format("2006-01-02") {
-				hadirHariIni++
-			}
-		}
-	}
+	hadirKemarin := perTanggal[len(perTanggal)-2].Hadir
 	
 	// Calculate percentage change (rounded to 2 decimal places)
 	perubahan := 0.0
@@ -766,7 +805,7 @@ func (s *AbsensiServiceImpl) calculateTrendFromData(absensiList []models.Rekapit
 		Perubahan:    perubahanStr,
 	}
 }
-
+	
 // GetGrafikKehadiran retrieves attendance chart data
 func (s *AbsensiServiceImpl) GetGrafikKehadiran(req *dtos.GrafikKehadiranRequest) (*dtos.GrafikKehadiranResponse, error) {
 	// Parse tanggal_mulai and tanggal_selesai
@@ -785,20 +824,23 @@ func (s *AbsensiServiceImpl) GetGrafikKehadiran(req *dtos.GrafikKehadiranRequest
 		return nil, errors.New("tanggal_selesai harus setelah atau sama dengan tanggal_mulai")
 	}
 	
-	// Get all absensi records based on filters
-	absensiList, err := s.repository.GetDashboardSummary(
-		req.TahunPelajaranID,
-		req.RombelID,
-		req.Semester,
-		req.BidangStudiID,
-		&tanggalMulai,
-		&tanggalSelesai,
-	)
+	// Load kalender akademik to leave out non-school days
+	kalender, err := s.kalenderService.LoadKalender(tanggalMulai, tanggalSelesai)
 	if err != nil {
-		return nil, errors.New("gagal mengambil data absensi")
+		return nil, err
 	}
 	
-	// Group data by periode
+	filter := repositories.AbsensiAggregateFilter{
+		TahunPelajaranID: req.TahunPelajaranID,
+		RombelID:         req.RombelID,
+		Semester:         req.Semester,
+		BidangStudiID:    req.BidangStudiID,
+		TanggalMulai:     &tanggalMulai,
+		TanggalSelesai:   &tanggalSelesai,
+		ExcludeTanggal:   bukanHariSekolah(kalender, tanggalMulai, tanggalSelesai),
+	}
+	
+	// Group data by periode in SQL
 	var labels []string
 	var dataHadir []int
 	var dataSakit []int
@@ -807,11 +849,23 @@ func (s *AbsensiServiceImpl) GetGrafikKehadiran(req *dtos.GrafikKehadiranRequest
 	
 	switch req.Periode {
 	case "harian":
-		labels, dataHadir, dataSakit, dataIzin, dataAlpa = s.groupByHarian(absensiList, tanggalMulai, tanggalSelesai)
+		perTanggal, err := s.repository.CountStatusPerPeriode(filter, "day")
+		if err != nil {
+			return nil, errors.New("gagal mengambil data absensi")
+		}
+		labels, dataHadir, dataSakit, dataIzin, dataAlpa = s.groupByHarian(perTanggal, kalender, tanggalMulai, tanggalSelesai)
 	case "mingguan":
-		labels, dataHadir, dataSakit, dataIzin, dataAlpa = s.groupByMingguan(absensiList, tanggalMulai, tanggalSelesai)
+		perMinggu, err := s.repository.CountStatusPerPeriode(filter, "week")
+		if err != nil {
+			return nil, errors.New("gagal mengambil data absensi")
+		}
+		labels, dataHadir, dataSakit, dataIzin, dataAlpa = s.groupByMingguan(perMinggu, tanggalMulai, tanggalSelesai)
 	case "bulanan":
-		labels, dataHadir, dataSakit, dataIzin, dataAlpa = s.groupByBulanan(absensiList, tanggalMulai, tanggalSelesai)
+		perBulan, err := s.repository.CountStatusPerPeriode(filter, "month")
+		if err != nil {
+			return nil, errors.New("gagal mengambil data absensi")
+		}
+		labels, dataHadir, dataSakit, dataIzin, dataAlpa = s.groupByBulanan(perBulan, tanggalMulai, tanggalSelesai)
 	default:
 		return nil, errors.New("periode tidak valid, gunakan: harian, mingguan, atau bulanan")
 	}
@@ -842,53 +896,38 @@ func (s *AbsensiServiceImpl) GetGrafikKehadiran(req *dtos.GrafikKehadiranRequest
 	return response, nil
 }
 
-// groupByHarian groups attendance data by daily
-func (s *AbsensiServiceImpl) groupByHarian(absensiList []models.RekapitulasiAbsensi, tanggalMulai, tanggalSelesai time.Time) ([]string, []int, []int, []int, []int) {
-	// Create map to store data per date
-	dataMap := make(map[string]map[string]int)
-	
-	// Initialize all dates in range
-	for d := tanggalMulai; !d.After(tanggalSelesai); d = d.AddDate(0, 0, 1) {
-		dateKey := d.Format("2006-01-02")
-		dataMap[dateKey] = map[string]int{
-			"hadir": 0,
-			"sakit": 0,
-			"izin":  0,
-			"alpa":  0,
-		}
-	}
-	
-	// Count attendance by date
-	for _, absensi := range absensiList {
-		dateKey := absensi.Tanggal.Format("2006-01-02")
-		if data, exists := dataMap[dateKey]; exists {
-			data[absensi.Status]++
-		}
+// groupByHarian lays out per-tanggal counts on the school days in range
+func (s *AbsensiServiceImpl) groupByHarian(perTanggal []repositories.AbsensiPeriodeCount, kalender *KalenderSekolah, tanggalMulai, tanggalSelesai time.Time) ([]string, []int, []int, []int, []int) {
+	// Index counts by date
+	dataMap := make(map[string]repositories.AbsensiStatusCount)
+	for _, count := range perTanggal {
+		dataMap[count.Periode.Format("2006-01-02")] = count.AbsensiStatusCount
 	}
 	
 	// Build arrays
 	var labels []string
 	var dataHadir, dataSakit, dataIzin, dataAlpa []int
 	
-	for d := tanggalMulai; !d.After(tanggalSelesai); d = d.AddDate(0, 0, 1) {
-		dateKey := d.Format("2006-01-02")
+	for _, d := range kalender.HariEfektif(tanggalMulai, tanggalSelesai) {
 		labels = append(labels, d.Format("02 Jan"))
-		
-		data := dataMap[dateKey]
-		dataHadir = append(dataHadir, data["hadir"])
-		dataSakit = append(dataSakit, data["sakit"])
-		dataIzin = append(dataIzin, data["izin"])
-		dataAlpa = append(dataAlpa, data["alpa"])
+	
+		data := dataMap[d.Format("2006-01-02")]
+		dataHadir = append(dataHadir, data.Hadir)
+		dataSakit = append(dataSakit, data.Sakit)
+		dataIzin = append(dataIzin, data.Izin)
+		dataAlpa = append(dataAlpa, data.Alpa)
 	}
 	
 	return labels, dataHadir, dataSakit, dataIzin, dataAlpa
 }
-
-// groupByMingguan groups attendance data by weekly
-func (s *AbsensiServiceImpl) groupByMingguan(absensiList []models.RekapitulasiAbsensi, tanggalMulai, tanggalSelesai time.Time) ([]string, []int, []int, []int, []int) {
-	// Create map to store data per week
-	dataMap := make(map[string]map[string]int)
-	weekLabels := make(map[string]string)
+	
+// groupByMingguan lays out per-week counts (weeks start on Monday) on every week in range
+func (s *AbsensiServiceImpl) groupByMingguan(perMinggu []repositories.AbsensiPeriodeCount, tanggalMulai, tanggalSelesai time.Time) ([]string, []int, []int, []int, []int) {
+	// Index counts by week start
+	dataMap := make(map[string]repositories.AbsensiStatusCount)
+	for _, count := range perMinggu {
+		dataMap[count.Periode.Format("2006-01-02")] = count.AbsensiStatusCount
+	}
 	
 	// Get start of first week (Monday)
 	startWeek := tanggalMulai
@@ -896,112 +935,59 @@ func (s *AbsensiServiceImpl) groupByMingguan(absensiList []models.RekapitulasiAb
 		startWeek = startWeek.AddDate(0, 0, -1)
 	}
 	
-	// Initialize all weeks in range
+	// Build arrays (sorted by week)
+	var labels []string
+	var dataHadir, dataSakit, dataIzin, dataAlpa []int
+	
 	for d := startWeek; !d.After(tanggalSelesai); d = d.AddDate(0, 0, 7) {
-		weekKey := d.Format("2006-01-02")
 		endWeek := d.AddDate(0, 0, 6)
-		
-		dataMap[weekKey] = map[string]int{
-			"hadir": 0,
-			"sakit": 0,
-			"izin":  0,
-			"alpa":  0,
-		}
-		
+	
 		// Label format: "02-08 Jan" or "30 Dec - 05 Jan"
 		if d.Month() == endWeek.Month() {
-			weekLabels[weekKey] = fmt.Sprintf("%02d-%02d %s", d.Day(), endWeek.Day(), d.Format("Jan"))
+			labels = append(labels, fmt.Sprintf("%02d-%02d %s", d.Day(), endWeek.Day(), d.Format("Jan")))
 		} else {
-			weekLabels[weekKey] = fmt.Sprintf("%02d %s - %02d %s", d.Day(), d.Format("Jan"), endWeek.Day(), endWeek.Format("Jan"))
-		}
-	}
-	
-	// Count attendance by week
-	for _, absensi := range absensiList {
-		// Find which week this date belongs to
-		weekStart := absensi.Tanggal
-		for weekStart.Weekday() != time.Monday {
-			weekStart = weekStart.AddDate(0, 0, -1)
+			labels = append(labels, fmt.Sprintf("%02d %s - %02d %s", d.Day(), d.Format("Jan"), endWeek.Day(), endWeek.Format("Jan")))
 		}
-		
-		weekKey := weekStart.Format("2006-01-02")
-		if data, exists := dataMap[weekKey]; exists {
-			data[absensi.Status]++
-		}
-	}
 	
-	// Build arrays (sorted by week)
-	var weeks []time.Time
-	for d := startWeek; !d.After(tanggalSelesai); d = d.AddDate(0, 0, 7) {
-		weeks = append(weeks, d)
-	}
-	
-	var labels []string
-	var dataHadir, dataSakit, dataIzin, dataAlpa []int
-	
-	for _, week := range weeks {
-		weekKey := week.Format("2006-01-02")
-		labels = append(labels, weekLabels[weekKey])
-		
-		data := dataMap[weekKey]
-		dataHadir = append(dataHadir, data["hadir"])
-		dataSakit = append(dataSakit, data["sakit"])
-		dataIzin = append(dataIzin, data["izin"])
-		dataAlpa = append(dataAlpa, data["alpa"])
+		data := dataMap[d.Format("2006-01-02")]
+		dataHadir = append(dataHadir, data.Hadir)
+		dataSakit = append(dataSakit, data.Sakit)
+		dataIzin = append(dataIzin, data.Izin)
+		dataAlpa = append(dataAlpa, data.Alpa)
 	}
 	
 	return labels, dataHadir, dataSakit, dataIzin, dataAlpa
 }
-
-// groupByBulanan groups attendance data by monthly
-func (s *AbsensiServiceImpl) groupByBulanan(absensiList []models.RekapitulasiAbsensi, tanggalMulai, tanggalSelesai time.Time) ([]string, []int, []int, []int, []int) {
-	// Create map to store data per month
-	dataMap := make(map[string]map[string]int)
-	
-	// Initialize all months in range
-	currentMonth := time.Date(tanggalMulai.Year(), tanggalMulai.Month(), 1, 0, 0, 0, 0, time.UTC)
-	endMonth := time.Date(tanggalSelesai.Year(), tanggalSelesai.Month(), 1, 0, 0, 0, 0, time.UTC)
 	
-	for !currentMonth.After(endMonth) {
-		monthKey := currentMonth.Format("2006-01")
-		dataMap[monthKey] = map[string]int{
-			"hadir": 0,
-			"sakit": 0,
-			"izin":  0,
-			"alpa":  0,
-		}
-		currentMonth = currentMonth.AddDate(0, 1, 0)
-	}
-	
-	// Count attendance by month
-	for _, absensi := range absensiList {
-		monthKey := absensi.Tanggal.Format("2006-01")
-		if data, exists := dataMap[monthKey]; exists {
-			data[absensi.Status]++
-		}
+// groupByBulanan lays out per-month counts on every month in range
+func (s *AbsensiServiceImpl) groupByBulanan(perBulan []repositories.AbsensiPeriodeCount, tanggalMulai, tanggalSelesai time.Time) ([]string, []int, []int, []int, []int) {
+	// Index counts by month
+	dataMap := make(map[string]repositories.AbsensiStatusCount)
+	for _, count := range perBulan {
+		dataMap[count.Periode.Format("2006-01")] = count.AbsensiStatusCount
 	}
 	
 	// Build arrays
 	var labels []string
 	var dataHadir, dataSakit, dataIzin, dataAlpa []int
 	
-	currentMonth = time.Date(tanggalMulai.Year(), tanggalMulai.Month(), 1, 0, 0, 0, 0, time.UTC)
+	currentMonth := time.Date(tanggalMulai.Year(), tanggalMulai.Month(), 1, 0, 0, 0, 0, time.UTC)
+	endMonth := time.Date(tanggalSelesai.Year(), tanggalSelesai.Month(), 1, 0, 0, 0, 0, time.UTC)
 	for !currentMonth.After(endMonth) {
-		monthKey := currentMonth.Format("2006-01")
 		labels = append(labels, currentMonth.Format("Jan 2006"))
-		
-		data := dataMap[monthKey]
-		dataHadir = append(dataHadir, data["hadir"])
-		dataSakit = append(dataSakit, data["sakit"])
-		dataIzin = append(dataIzin, data["izin"])
-		dataAlpa = append(dataAlpa, data["alpa"])
-		
+	
+		data := dataMap[currentMonth.Format("2006-01")]
+		dataHadir = append(dataHadir, data.Hadir)
+		dataSakit = append(dataSakit, data.Sakit)
+		dataIzin = append(dataIzin, data.Izin)
+		dataAlpa = append(dataAlpa, data.Alpa)
+	
 		currentMonth = currentMonth.AddDate(0, 1, 0)
 	}
 	
 	return labels, dataHadir, dataSakit, dataIzin, dataAlpa
 }
-
+	
 // GetStatistikPerHari retrieves daily attendance statistics (pattern by day of week)
 func (s *AbsensiServiceImpl) GetStatistikPerHari(req *dtos.StatistikPerHariRequest) (*dtos.StatistikPerHariResponse, error) {
 	// Create date range for the specified month
@@ -1021,6 +1007,13 @@ func (s *AbsensiServiceImpl) GetStatistikPerHari(req *dtos.StatistikPerHariReque
 		return nil, errors.New("gagal mengambil data absensi")
 	}
 	
+	// Load kalender akademik to leave out non-school days
+	kalender, err := s.kalenderService.LoadKalender(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	absensiList = filterHariSekolah(absensiList, kalender)
+	
 	// Initialize map for each day of week (Monday to Sunday)
 	dayNames := []string{"Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu", "Minggu"}
 	dayStats := make(map[string]*struct {
@@ -1037,8 +1030,8 @@ func (s *AbsensiServiceImpl) GetStatistikPerHari(req *dtos.StatistikPerHariReque
 		}{}
 	}
 	
-	// Count occurrences of each day in the month
-	for d := startDate; !d.After(endDate); d = d.AddDate(0, 0, 1) {
+	// Count occurrences of each day in the month (school days only)
+	for _, d := range kalender.HariEfektif(startDate, endDate) {
 		dayName := s.getDayNameInIndonesian(d.Weekday())
 		dayStats[dayName].Count++
 	}
@@ -1133,82 +1126,37 @@ func (s *AbsensiServiceImpl) GetPerbandinganRombel(req *dtos.PerbandinganRombelR
 		tanggalSelesai = &t
 	}
 	
-	// Get all absensi records
-	absensiList, err := s.repository.GetPerbandinganRombel(
-		req.TahunPelajaranID,
-		req.Semester,
-		req.BidangStudiID,
-		tanggalMulai,
-		tanggalSelesai,
-	)
+	// Count statuses and unique students per rombel in SQL
+	perRombel, err := s.repository.CountStatusPerRombel(repositories.AbsensiAggregateFilter{
+		TahunPelajaranID: req.TahunPelajaranID,
+		Semester:         req.Semester,
+		BidangStudiID:    req.BidangStudiID,
+		TanggalMulai:     tanggalMulai,
+		TanggalSelesai:   tanggalSelesai,
+	})
 	if err != nil {
 		return nil, errors.New("gagal mengambil data absensi")
 	}
 	
-	// Group by rombel_id
-	rombelMap := make(map[uint]*dtos.RombelKehadiran)
-	siswaPerRombel := make(map[uint]map[uint]bool) // Track unique students per rombel
-	
-	for _, absensi := range absensiList {
-		if absensi.RombelID == nil {
-			continue
-		}
-		
-		rombelID := *absensi.RombelID
-		
-		// Initialize rombel data if not exists
-		if _, exists := rombelMap[rombelID]; !exists {
-			rombelNama := ""
-			if absensi.Rombel != nil {
-				rombelNama = absensi.Rombel.Name
-			}
-			
-			rombelMap[rombelID] = &dtos.RombelKehadiran{
-				RombelID:        rombelID,
-				RombelNama:      rombelNama,
-				TotalSiswa:      0,
-				PersentaseHadir: 0,
-				TotalHadir:      0,
-				TotalSakit:      0,
-				TotalIzin:       0,
-				TotalAlpa:       0,
-			}
-			siswaPerRombel[rombelID] = make(map[uint]bool)
-		}
-		
-		// Track unique students
-		if absensi.PesertaDidikRombel != nil && absensi.PesertaDidikRombel.PesertaDidik != nil {
-			siswaPerRombel[rombelID][absensi.PesertaDidikRombel.PesertaDidik.ID] = true
-		}
-		
-		// Count by status
-		rombel := rombelMap[rombelID]
-		switch absensi.Status {
-		case "hadir":
-			rombel.TotalHadir++
-		case "sakit":
-			rombel.TotalSakit++
-		case "izin":
-			rombel.TotalIzin++
-		case "alpa":
-			rombel.TotalAlpa++
-		}
-	}
-	
-	// Calculate total_siswa and persentase_hadir for each rombel
+	// Calculate persentase_hadir for each rombel
 	var data []dtos.RombelKehadiran
-	for rombelID, rombel := range rombelMap {
-		// Count unique students
-		rombel.TotalSiswa = len(siswaPerRombel[rombelID])
-		
-		// Calculate persentase hadir
-		totalKehadiran := rombel.TotalHadir + rombel.TotalSakit + rombel.TotalIzin + rombel.TotalAlpa
-		if totalKehadiran > 0 {
+	for _, count := range perRombel {
+		rombel := dtos.RombelKehadiran{
+			RombelID:   count.RombelID,
+			RombelNama: count.RombelNama,
+			TotalSiswa: count.TotalSiswa,
+			TotalHadir: count.Hadir,
+			TotalSakit: count.Sakit,
+			TotalIzin:  count.Izin,
+			TotalAlpa:  count.Alpa,
+		}
+	
+		if totalKehadiran := count.Total(); totalKehadiran > 0 {
 			rombel.PersentaseHadir = float64(rombel.TotalHadir) / float64(totalKehadiran) * 100
 			rombel.PersentaseHadir = float64(int(rombel.PersentaseHadir*100)) / 100 // Round to 2 decimal places
 		}
-		
-		data = append(data, *rombel)
+	
+		data = append(data, rombel)
 	}
 	
 	// Sort by persentase_hadir descending (highest first)
@@ -1242,79 +1190,41 @@ func (s *AbsensiServiceImpl) GetSiswaTerendah(req *dtos.SiswaTerendahRequest) (*
 		tanggalSelesai = &t
 	}
 	
-	// Get all absensi records
-	absensiList, err := s.repository.GetSiswaTerendah(
-		req.TahunPelajaranID,
-		req.RombelID,
-		req.Semester,
-		req.BidangStudiID,
-		tanggalMulai,
-		tanggalSelesai,
-	)
-	if err != nil {
-		return nil, errors.New("gagal mengambil data absensi")
+	// Apply limit (default 10)
+	limit := 10
+	if req.Limit > 0 {
+		limit = req.Limit
 	}
 	
-	// Group by peserta_didik_id
-	siswaMap := make(map[uint]*dtos.SiswaKehadiran)
-	
-	for _, absensi := range absensiList {
-		// Get peserta_didik_id from PesertaDidikRombel
-		pesertaDidikID := uint(0)
-		var nis, nama string
-		if absensi.PesertaDidikRombel != nil && absensi.PesertaDidikRombel.PesertaDidik != nil {
-			pesertaDidikID = absensi.PesertaDidikRombel.PesertaDidik.ID
-			nis = absensi.PesertaDidikRombel.PesertaDidik.NIS
-			nama = absensi.PesertaDidikRombel.PesertaDidik.Nama
-		}
-
-		// Initialize siswa data if not exists
-		if _, exists := siswaMap[pesertaDidikID]; !exists {
-			siswaMap[pesertaDidikID] = &dtos.SiswaKehadiran{
-				PesertaDidikID:  pesertaDidikID,
-				NIS:             nis,
-				Nama:            nama,
-				TotalHadir:      0,
-				TotalAbsen:      0,
-				TotalPertemuan:  0,
-				PersentaseHadir: 0,
-			}
-		}
-		
-		siswa := siswaMap[pesertaDidikID]
-		siswa.TotalPertemuan++
-		
-		// Count by status
-		if absensi.Status == "hadir" {
-			siswa.TotalHadir++
-		} else {
-			siswa.TotalAbsen++
-		}
+	// Count statuses per siswa in SQL, lowest attendance first
+	perSiswa, err := s.repository.CountStatusPerSiswa(repositories.AbsensiAggregateFilter{
+		TahunPelajaranID: req.TahunPelajaranID,
+		RombelID:         req.RombelID,
+		Semester:         req.Semester,
+		BidangStudiID:    req.BidangStudiID,
+		TanggalMulai:     tanggalMulai,
+		TanggalSelesai:   tanggalSelesai,
+	}, limit)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data absensi")
 	}
 	
-	// Calculate persentase hadir and convert to slice
+	// Calculate persentase hadir
 	var data []dtos.SiswaKehadiran
-	for _, siswa := range siswaMap {
+	for _, count := range perSiswa {
+		siswa := dtos.SiswaKehadiran{
+			PesertaDidikID: count.PesertaDidikID,
+			NIS:            count.NIS,
+			Nama:           count.Nama,
+			TotalHadir:     count.Hadir,
+			TotalAbsen:     count.Total() - count.Hadir,
+			TotalPertemuan: count.Total(),
+		}
 		if siswa.TotalPertemuan > 0 {
 			siswa.PersentaseHadir = float64(siswa.TotalHadir) / float64(siswa.TotalPertemuan) * 100
 			siswa.PersentaseHadir = float64(int(siswa.PersentaseHadir*100)) / 100 // Round to 2 decimal places
 		}
-		data = append(data, *siswa)
-	}
-	
-	// Sort by persentase_hadir ascending (lowest first)
-	sort.Slice(data, func(i, j int) bool {
-		return data[i].PersentaseHadir < data[j].PersentaseHadir
-	})
-	
-	// Apply limit (default 10)
-	limit := 10
-	if req.Limit > 0 {
-		limit = req.Limit
-	}
-	
-	if len(data) > limit {
-		data = data[:limit]
+		data = append(data, siswa)
 	}
 	
 	return &dtos.SiswaTerendahResponse{
@@ -1488,7 +1398,7 @@ func (s *AbsensiServiceImpl) GetDashboardSiswa(req *dtos.DashboardSiswaRequest)
 			WaktuAbsen:  waktuAbsen,
 			MetodeInput: absensi.MetodeInput,
 			Keterangan:  absensi.Keterangan,
-			FileSurat:   s.r2Storage.GetPublicURL(absensi.FileSurat),
+			FileSurat:   utils.FileURL(s.storage, absensi.FileSurat),
 			PertemuanKe: absensi.PertemuanKe,
 		}
 		
@@ -1696,7 +1606,7 @@ func (s *AbsensiServiceImpl) buildGrafikBulananSiswa(absensiList []models.Rekapi
 }
 
 // SynchronizeAbsensi synchronizes data from absensi (scan) to rekapitulasi_absensi
-func (s *AbsensiServiceImpl) SynchronizeAbsensi(req *dtos.AbsensiSyncRequest, userID uint) (*dtos.AbsensiSyncResponse, error) {
+func (s *AbsensiServiceImpl) SynchronizeAbsensi(req *dtos.AbsensiSyncRequest, actor utils.Principal, ipAddress string) (*dtos.AbsensiSyncResponse, error) {
 	var absensiScanList []models.Absensi
 	var err error
 	
@@ -1779,11 +1689,17 @@ func (s *AbsensiServiceImpl) SynchronizeAbsensi(req *dtos.AbsensiSyncRequest, us
 		return nil, errors.New("tidak ada data absensi scan yang ditemukan")
 	}
 	
+	periodes, err := s.periodeService.LoadPeriode()
+	if err != nil {
+		return nil, err
+	}
+	
 	totalProcessed := 0
 	totalInserted := 0
 	totalUpdated := 0
 	totalSkipped := 0
 	var details []dtos.AbsensiSyncDetailItem
+	audit := auditRekapAbsensi(models.SumberRiwayatRekapSync, actor, ipAddress)
 	
 	// Process each absensi scan record
 	for _, absensiScan := range absensiScanList {
@@ -1818,11 +1734,21 @@ func (s *AbsensiServiceImpl) SynchronizeAbsensi(req *dtos.AbsensiSyncRequest, us
 			continue
 		}
 		
-		// Determine semester based on month (Juli-Desember = 1, Januari-Juni = 2)
-		semester := 1
-		if absensiScan.Tanggal.Month() >= 1 && absensiScan.Tanggal.Month() <= 6 {
-			semester = 2
+		// Resolve semester from the semester dates of tahun pelajaran
+		periode, err := periodes.Resolve(absensiScan.Tanggal)
+		if err != nil || periode.TahunPelajaranID != req.TahunPelajaranID {
+			totalSkipped++
+			details = append(details, dtos.AbsensiSyncDetailItem{
+				PesertaDidikID: absensiScan.PesertaDidikID,
+				NIS:            absensiScan.PesertaDidik.NIS,
+				Nama:           absensiScan.PesertaDidik.Nama,
+				Tanggal:        absensiScan.Tanggal.Format("2006-01-02"),
+				Action:         "skipped",
+				Reason:         "di luar periode semester tahun pelajaran",
+			})
+			continue
 		}
+		semester := periode.Semester
 		
 		// Combine tanggal and jam_datang to create waktu_absen (timestamp)
 		waktuAbsenStr := fmt.Sprintf("%s %s", absensiScan.Tanggal.Format("2006-01-02"), *absensiScan.JamDatang)
@@ -1848,9 +1774,10 @@ func (s *AbsensiServiceImpl) SynchronizeAbsensi(req *dtos.AbsensiSyncRequest, us
 			existing.Status = "hadir"
 			existing.WaktuAbsen = &waktuAbsen
 			existing.MetodeInput = "auto"
-			existing.DicatatOlehID = &userID
+			existing.DicatatOlehID = &actor.ID
+			existing.DicatatOlehType = actor.TypePtr()
 			
-			if err := s.repository.Update(existing); err != nil {
+			if err := s.repository.Update(existing, audit); err != nil {
 				totalSkipped++
 				details = append(details, dtos.AbsensiSyncDetailItem{
 					PesertaDidikID: absensiScan.PesertaDidikID,
@@ -1886,10 +1813,11 @@ func (s *AbsensiServiceImpl) SynchronizeAbsensi(req *dtos.AbsensiSyncRequest, us
 				MetodeInput:          "auto",
 				Keterangan:           "",
 				FileSurat:            "",
-				DicatatOlehID:        &userID,
+				DicatatOlehID:        &actor.ID,
+				DicatatOlehType:      actor.TypePtr(),
 			}
 			
-			if err := s.repository.Create(newRekap); err != nil {
+			if err := s.repository.Create(newRekap, audit); err != nil {
 				totalSkipped++
 				details = append(details, dtos.AbsensiSyncDetailItem{
 					PesertaDidikID: absensiScan.PesertaDidikID,
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,7 +15,7 @@ import (
 )
 
 type ActivityGalleryService interface {
-	Create(fotos []*multipart.FileHeader, fotoThumbnails []string, req *dtos.ActivityGalleryCreateRequest, userID uint) (*dtos.ActivityGalleryResponse, error)
+	Create(ctx context.Context, fotos []*multipart.FileHeader, fotoThumbnails []string, req *dtos.ActivityGalleryCreateRequest, actor utils.Principal) (*dtos.ActivityGalleryResponse, error)
 	GetByID(id uint) (*dtos.ActivityGalleryResponse, error)
 	GetAll(limit int, offset int) (*dtos.ActivityGalleryListResponse, error)
 	GetAllWithFilter(params repositories.GetActivityGalleryParams) (*dtos.ActivityGalleryListWithPaginationResponse, error)
@@ -22,25 +23,25 @@ type ActivityGalleryService interface {
 	GetPublicList(req *dtos.ActivityGalleryPublicListRequest) (*dtos.ActivityGalleryPublicDaftarResponse, error)
 	GetPublicDetailByID(id uint) (*dtos.ActivityGalleryPublicDetailResponse, error)
 	GetPublicOtherGalleries(excludeID uint) (*dtos.ActivityGalleryPublicListResponse, error)
-	Update(id uint, fotos []*multipart.FileHeader, fotoThumbnails []string, req *dtos.ActivityGalleryUpdateRequest, userID uint) (*dtos.ActivityGalleryResponse, error)
+	Update(ctx context.Context, id uint, fotos []*multipart.FileHeader, fotoThumbnails []string, req *dtos.ActivityGalleryUpdateRequest, actor utils.Principal) (*dtos.ActivityGalleryResponse, error)
 	Delete(id uint) error
 }
 
 type ActivityGalleryServiceImpl struct {
 	repository repositories.ActivityGalleryRepository
-	r2Storage  *utils.R2Storage
+	storage    utils.Storage
 }
 
 // NewActivityGalleryService creates a new ActivityGallery service
-func NewActivityGalleryService(repository repositories.ActivityGalleryRepository, r2Storage *utils.R2Storage) ActivityGalleryService {
+func NewActivityGalleryService(repository repositories.ActivityGalleryRepository, storage utils.Storage) ActivityGalleryService {
 	return &ActivityGalleryServiceImpl{
 		repository: repository,
-		r2Storage:  r2Storage,
+		storage:    storage,
 	}
 }
 
 // Create creates a new ActivityGallery with foto uploads to R2
-func (s *ActivityGalleryServiceImpl) Create(fotos []*multipart.FileHeader, fotoThumbnails []string, req *dtos.ActivityGalleryCreateRequest, userID uint) (*dtos.ActivityGalleryResponse, error) {
+func (s *ActivityGalleryServiceImpl) Create(ctx context.Context, fotos []*multipart.FileHeader, fotoThumbnails []string, req *dtos.ActivityGalleryCreateRequest, actor utils.Principal) (*dtos.ActivityGalleryResponse, error) {
 	// Parse tanggal
 	tanggal, err := time.Parse("2006-01-02", req.Tanggal)
 	if err != nil {
@@ -55,28 +56,15 @@ func (s *ActivityGalleryServiceImpl) Create(fotos []*multipart.FileHeader, fotoT
 				continue
 			}
 
-			// Validate foto file size (max 10MB per file)
-			if foto.Size > 10*1024*1024 { // 10MB
-				return nil, errors.New("each foto must not exceed 10MB")
-			}
-
-			// Validate foto type - allow image files only
-			allowedTypes := map[string]bool{
-				"image/jpeg": true,
-				"image/png":  true,
-				"image/gif":  true,
-				"image/webp": true,
-			}
-			contentType := foto.Header.Get("Content-Type")
-			if !allowedTypes[contentType] {
-				return nil, errors.New("only image files are allowed for foto (jpeg, png, gif, webp)")
-			}
-
-			// Upload foto to R2 in galeri-kegiatan directory
-			fileKey, err := s.r2Storage.UploadFile(foto, "galeri-kegiatan")
+			// Validate, strip metadata and upload foto with its variants in galeri-kegiatan directory
+			stored, err := utils.SaveUpload(ctx, s.storage, foto, utils.UploadCategoryGaleri, "galeri-kegiatan")
 			if err != nil {
+				for _, item := range fotoItems {
+					s.deleteFoto(item)
+				}
 				return nil, err
 			}
+			fileKey := stored.Key
 
 			// Generate unique file ID: file_timestamp_randomstring
 			fileID := fmt.Sprintf("file_%d_%s", time.Now().UnixNano(), fileKey[len(fileKey)-8:])
@@ -94,8 +82,11 @@ func (s *ActivityGalleryServiceImpl) Create(fotos []*multipart.FileHeader, fotoT
 				ID:        fileID,
 				Filename:  foto.Filename,
 				URL:       fileKey,
-				Size:      foto.Size,
+				Size:      stored.Size,
 				Thumbnail: thumbnail,
+				Width:     stored.Width,
+				Height:    stored.Height,
+				Variants:  fileVariants(stored),
 			})
 		}
 	}
@@ -121,13 +112,14 @@ func (s *ActivityGalleryServiceImpl) Create(fotos []*multipart.FileHeader, fotoT
 		Foto:            fotosJSON,
 		StatusPublikasi: statusPublikasi,
 		Status:          status,
-		CreatedByID:     &userID,
+		CreatedByID:     &actor.ID,
+		CreatedByType:   actor.TypePtr(),
 	}
 
 	if err := s.repository.Create(data); err != nil {
 		// If database save fails, delete uploaded files
 		for _, item := range fotoItems {
-			_ = s.r2Storage.DeleteFile(item.URL)
+			s.deleteFoto(item)
 		}
 		return nil, err
 	}
@@ -215,7 +207,7 @@ func (s *ActivityGalleryServiceImpl) GetAllWithFilter(params repositories.GetAct
 }
 
 // Update updates ActivityGallery
-func (s *ActivityGalleryServiceImpl) Update(id uint, fotos []*multipart.FileHeader, fotoThumbnails []string, req *dtos.ActivityGalleryUpdateRequest, userID uint) (*dtos.ActivityGalleryResponse, error) {
+func (s *ActivityGalleryServiceImpl) Update(ctx context.Context, id uint, fotos []*multipart.FileHeader, fotoThumbnails []string, req *dtos.ActivityGalleryUpdateRequest, actor utils.Principal) (*dtos.ActivityGalleryResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(id)
 	if err != nil {
@@ -255,7 +247,7 @@ func (s *ActivityGalleryServiceImpl) Update(id uint, fotos []*multipart.FileHead
 			for _, foto := range existingFotoItems {
 				if deleteMap[foto.ID] {
 					// Delete from R2
-					_ = s.r2Storage.DeleteFile(foto.URL)
+					s.deleteFoto(foto)
 				} else {
 					remainingFotos = append(remainingFotos, foto)
 				}
@@ -268,6 +260,7 @@ func (s *ActivityGalleryServiceImpl) Update(id uint, fotos []*multipart.FileHead
 	}
 
 	// Add new fotos if provided (fotos lama tetap)
+	var uploadedFotos []models.FileItem
 	if len(fotos) > 0 {
 		var existingFotoItems []models.FileItem
 		// Get existing fotos
@@ -281,28 +274,15 @@ func (s *ActivityGalleryServiceImpl) Update(id uint, fotos []*multipart.FileHead
 				continue
 			}
 
-			// Validate foto file size (max 10MB per file)
-			if foto.Size > 10*1024*1024 { // 10MB
-				return nil, errors.New("each foto must not exceed 10MB")
-			}
-
-			// Validate foto type
-			allowedTypes := map[string]bool{
-				"image/jpeg": true,
-				"image/png":  true,
-				"image/gif":  true,
-				"image/webp": true,
-			}
-			contentType := foto.Header.Get("Content-Type")
-			if !allowedTypes[contentType] {
-				return nil, errors.New("only image files are allowed for foto (jpeg, png, gif, webp)")
-			}
-
-			// Upload foto to R2
-			fileKey, err := s.r2Storage.UploadFile(foto, "galeri-kegiatan")
+			// Validate, strip metadata and upload foto with its variants
+			stored, err := utils.SaveUpload(ctx, s.storage, foto, utils.UploadCategoryGaleri, "galeri-kegiatan")
 			if err != nil {
+				for _, item := range uploadedFotos {
+					s.deleteFoto(item)
+				}
 				return nil, err
 			}
+			fileKey := stored.Key
 
 			// Generate unique file ID
 			fileID := fmt.Sprintf("file_%d_%s", time.Now().UnixNano(), fileKey[len(fileKey)-8:])
@@ -316,13 +296,18 @@ func (s *ActivityGalleryServiceImpl) Update(id uint, fotos []*multipart.FileHead
 				thumbnail = "active"
 			}
 
-			existingFotoItems = append(existingFotoItems, models.FileItem{
+			fotoItem := models.FileItem{
 				ID:        fileID,
 				Filename:  foto.Filename,
 				URL:       fileKey,
-				Size:      foto.Size,
+				Size:      stored.Size,
 				Thumbnail: thumbnail,
-			})
+				Width:     stored.Width,
+				Height:    stored.Height,
+				Variants:  fileVariants(stored),
+			}
+			uploadedFotos = append(uploadedFotos, fotoItem)
+			existingFotoItems = append(existingFotoItems, fotoItem)
 		}
 
 		// Convert updated fotos to JSON
@@ -346,14 +331,13 @@ func (s *ActivityGalleryServiceImpl) Update(id uint, fotos []*multipart.FileHead
 		}
 	}
 
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	if err := s.repository.Update(existing); err != nil {
 		// If DB save fails, delete the uploaded fotos
-		if len(fotos) > 0 {
-			for _, foto := range fotos {
-				_ = s.r2Storage.DeleteFile(fmt.Sprintf("galeri-kegiatan/%s", foto.Filename))
-			}
+		for _, foto := range uploadedFotos {
+			s.deleteFoto(foto)
 		}
 		return nil, err
 	}
@@ -373,7 +357,7 @@ func (s *ActivityGalleryServiceImpl) Delete(id uint) error {
 	var fotoItems []models.FileItem
 	if err := json.Unmarshal(existing.Foto, &fotoItems); err == nil {
 		for _, foto := range fotoItems {
-			_ = s.r2Storage.DeleteFile(foto.URL)
+			s.deleteFoto(foto)
 		}
 	}
 
@@ -381,6 +365,12 @@ func (s *ActivityGalleryServiceImpl) Delete(id uint) error {
 	return s.repository.Delete(id)
 }
 
+// deleteFoto deletes a foto with its resized variants from R2
+func (s *ActivityGalleryServiceImpl) deleteFoto(foto models.FileItem) {
+	_ = s.storage.DeleteFile(foto.URL)
+	deleteFileVariants(s.storage, foto.URL, foto.Variants)
+}
+
 // mapToResponse maps model to DTO response
 func (s *ActivityGalleryServiceImpl) mapToResponse(data *models.ActivityGallery) *dtos.ActivityGalleryResponse {
 	// Map fotos from JSON
@@ -391,9 +381,12 @@ func (s *ActivityGalleryServiceImpl) mapToResponse(data *models.ActivityGallery)
 			fotoItems = append(fotoItems, dtos.FileItemDTO{
 				ID:        foto.ID,
 				Filename:  foto.Filename,
-				URL:       s.r2Storage.GetPublicURL(foto.URL),
+				URL:       s.storage.GetPublicURL(foto.URL),
 				Size:      foto.Size,
 				Thumbnail: foto.Thumbnail,
+				Width:     foto.Width,
+				Height:    foto.Height,
+				Variants:  fileVariantDTOs(s.storage, foto.Variants),
 			})
 		}
 	}
@@ -408,7 +401,9 @@ func (s *ActivityGalleryServiceImpl) mapToResponse(data *models.ActivityGallery)
 		CreatedAt:       data.CreatedAt,
 		UpdatedAt:       data.UpdatedAt,
 		CreatedByID:     data.CreatedByID,
+		CreatedByType:   data.CreatedByType,
 		UpdatedByID:     data.UpdatedByID,
+		UpdatedByType:   data.UpdatedByType,
 	}
 }
 
@@ -429,7 +424,7 @@ func (s *ActivityGalleryServiceImpl) GetPublicLatest() (*dtos.ActivityGalleryPub
 		if err := json.Unmarshal(item.Foto, &fotoModels); err == nil {
 			for _, fotoItem := range fotoModels {
 				if fotoItem.Thumbnail == "active" {
-					fotoThumbnail = s.r2Storage.GetPublicURL(fotoItem.URL)
+					fotoThumbnail = s.storage.GetPublicURL(fotoItem.URL)
 					break
 				}
 			}
@@ -472,7 +467,7 @@ func (s *ActivityGalleryServiceImpl) GetPublicList(req *dtos.ActivityGalleryPubl
 		if err := json.Unmarshal(item.Foto, &fotoModels); err == nil {
 			for _, fotoItem := range fotoModels {
 				if fotoItem.Thumbnail == "active" {
-					fotoThumbnail = s.r2Storage.GetPublicURL(fotoItem.URL)
+					fotoThumbnail = s.storage.GetPublicURL(fotoItem.URL)
 					break
 				}
 			}
@@ -512,9 +507,12 @@ func (s *ActivityGalleryServiceImpl) GetPublicDetailByID(id uint) (*dtos.Activit
 			fotoItems = append(fotoItems, dtos.FileItemDTO{
 				ID:        foto.ID,
 				Filename:  foto.Filename,
-				URL:       s.r2Storage.GetPublicURL(foto.URL),
+				URL:       s.storage.GetPublicURL(foto.URL),
 				Size:      foto.Size,
 				Thumbnail: foto.Thumbnail,
+				Width:     foto.Width,
+				Height:    foto.Height,
+				Variants:  fileVariantDTOs(s.storage, foto.Variants),
 			})
 		}
 	}
@@ -542,7 +540,7 @@ func (s *ActivityGalleryServiceImpl) GetPublicOtherGalleries(excludeID uint) (*d
 		if err := json.Unmarshal(item.Foto, &fotoModels); err == nil {
 			for _, fotoItem := range fotoModels {
 				if fotoItem.Thumbnail == "active" {
-					fotoThumbnail = s.r2Storage.GetPublicURL(fotoItem.URL)
+					fotoThumbnail = s.storage.GetPublicURL(fotoItem.URL)
 					break
 				}
 			}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,7 +15,7 @@ import (
 )
 
 type AnnouncementService interface {
-	Create(gambar *multipart.FileHeader, files []*multipart.FileHeader, req *dtos.AnnouncementCreateRequest, userID uint) (*dtos.AnnouncementResponse, error)
+	Create(ctx context.Context, gambar *multipart.FileHeader, files []*multipart.FileHeader, req *dtos.AnnouncementCreateRequest, actor utils.Principal) (*dtos.AnnouncementResponse, error)
 	GetByID(id uint) (*dtos.AnnouncementResponse, error)
 	GetAll(limit int, offset int) (*dtos.AnnouncementListResponse, error)
 	GetAllWithFilter(params repositories.GetAnnouncementParams) (*dtos.AnnouncementListWithPaginationResponse, error)
@@ -23,25 +24,25 @@ type AnnouncementService interface {
 	GetPublicList(req *dtos.AnnouncementPublicListRequest) (*dtos.AnnouncementPublicDaftarResponse, error)
 	GetPublicDetailByID(id uint) (*dtos.AnnouncementPublicDetailResponse, error)
 	GetPublicOtherAnnouncements(excludeID uint) (*dtos.AnnouncementPublicListResponse, error)
-	Update(id uint, gambar *multipart.FileHeader, files []*multipart.FileHeader, req *dtos.AnnouncementUpdateRequest, userID uint) (*dtos.AnnouncementResponse, error)
+	Update(ctx context.Context, id uint, gambar *multipart.FileHeader, files []*multipart.FileHeader, req *dtos.AnnouncementUpdateRequest, actor utils.Principal) (*dtos.AnnouncementResponse, error)
 	Delete(id uint) error
 }
 
 type AnnouncementServiceImpl struct {
 	repository repositories.AnnouncementRepository
-	r2Storage  *utils.R2Storage
+	storage    utils.Storage
 }
 
 // NewAnnouncementService creates a new Announcement service
-func NewAnnouncementService(repository repositories.AnnouncementRepository, r2Storage *utils.R2Storage) AnnouncementService {
+func NewAnnouncementService(repository repositories.AnnouncementRepository, storage utils.Storage) AnnouncementService {
 	return &AnnouncementServiceImpl{
 		repository: repository,
-		r2Storage:  r2Storage,
+		storage:    storage,
 	}
 }
 
 // Create creates a new Announcement with file uploads to R2
-func (s *AnnouncementServiceImpl) Create(gambar *multipart.FileHeader, files []*multipart.FileHeader, req *dtos.AnnouncementCreateRequest, userID uint) (*dtos.AnnouncementResponse, error) {
+func (s *AnnouncementServiceImpl) Create(ctx context.Context, gambar *multipart.FileHeader, files []*multipart.FileHeader, req *dtos.AnnouncementCreateRequest, actor utils.Principal) (*dtos.AnnouncementResponse, error) {
 	// Parse tanggal
 	tanggal, err := time.Parse("2006-01-02", req.Tanggal)
 	if err != nil {
@@ -51,27 +52,12 @@ func (s *AnnouncementServiceImpl) Create(gambar *multipart.FileHeader, files []*
 	// Upload gambar if provided
 	var gambarURL string
 	if gambar != nil {
-		// Validate gambar file
-		if gambar.Size > 5*1024*1024 { // 5MB
-			return nil, errors.New("gambar size must not exceed 5MB")
-		}
-
-		allowedTypes := map[string]bool{
-			"image/jpeg": true,
-			"image/png":  true,
-			"image/gif":  true,
-			"image/webp": true,
-		}
-		contentType := gambar.Header.Get("Content-Type")
-		if !allowedTypes[contentType] {
-			return nil, errors.New("only image files are allowed for gambar (jpeg, png, gif, webp)")
-		}
-
-		// Upload gambar to R2
-		fileKey, err := s.r2Storage.UploadFile(gambar, "pengumuman")
+		// Validate the file from its content and upload to R2
+		stored, err := utils.SaveUpload(ctx, s.storage, gambar, utils.UploadCategoryGambar, "pengumuman")
 		if err != nil {
 			return nil, err
 		}
+		fileKey := stored.Key
 		gambarURL = fileKey
 	}
 
@@ -83,32 +69,12 @@ func (s *AnnouncementServiceImpl) Create(gambar *multipart.FileHeader, files []*
 				continue
 			}
 
-			// Validate file size (max 10MB per file)
-			if file.Size > 10*1024*1024 { // 10MB
-				return nil, errors.New("each file must not exceed 10MB")
-			}
-
-			// Validate file type - allow multiple file types
-			allowedTypes := map[string]bool{
-				"application/pdf":                          true,
-				"application/msword":                       true,
-				"application/vnd.openxmlformats-officedocument.wordprocessingml.document": true,
-				"application/vnd.ms-excel": true,
-				"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": true,
-				"text/plain":               true,
-				"image/jpeg":               true,
-				"image/png":                true,
-			}
-			contentType := file.Header.Get("Content-Type")
-			if !allowedTypes[contentType] {
-				return nil, errors.New("file type not allowed. Allowed: PDF, DOC, DOCX, XLS, XLSX, TXT, JPG, PNG")
-			}
-
-			// Upload file to R2
-			fileKey, err := s.r2Storage.UploadFile(file, "pengumuman")
+			// Validate the file from its content and upload to R2
+			stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryLampiran, "pengumuman")
 			if err != nil {
 				return nil, err
 			}
+			fileKey := stored.Key
 
 			// Generate unique file ID: file_timestamp_randomstring
 			fileID := fmt.Sprintf("file_%d_%s", time.Now().UnixNano(), fileKey[len(fileKey)-8:])
@@ -117,7 +83,7 @@ func (s *AnnouncementServiceImpl) Create(gambar *multipart.FileHeader, files []*
 				ID:       fileID,
 				Filename: file.Filename,
 				URL:      fileKey,
-				Size:     file.Size,
+				Size:     stored.Size,
 			})
 		}
 	}
@@ -146,16 +112,17 @@ func (s *AnnouncementServiceImpl) Create(gambar *multipart.FileHeader, files []*
 		Penulis:         req.Penulis,
 		StatusPublikasi: statusPublikasi,
 		Status:          status,
-		CreatedByID:     &userID,
+		CreatedByID:     &actor.ID,
+		CreatedByType:   actor.TypePtr(),
 	}
 
 	if err := s.repository.Create(data); err != nil {
 		// If database save fails, delete uploaded files
 		if gambarURL != "" {
-			_ = s.r2Storage.DeleteFile(gambarURL)
+			_ = s.storage.DeleteFile(gambarURL)
 		}
 		for _, item := range fileItems {
-			_ = s.r2Storage.DeleteFile(item.URL)
+			_ = s.storage.DeleteFile(item.URL)
 		}
 		return nil, err
 	}
@@ -243,7 +210,7 @@ func (s *AnnouncementServiceImpl) GetAllWithFilter(params repositories.GetAnnoun
 }
 
 // Update updates Announcement
-func (s *AnnouncementServiceImpl) Update(id uint, gambar *multipart.FileHeader, files []*multipart.FileHeader, req *dtos.AnnouncementUpdateRequest, userID uint) (*dtos.AnnouncementResponse, error) {
+func (s *AnnouncementServiceImpl) Update(ctx context.Context, id uint, gambar *multipart.FileHeader, files []*multipart.FileHeader, req *dtos.AnnouncementUpdateRequest, actor utils.Principal) (*dtos.AnnouncementResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(id)
 	if err != nil {
@@ -278,31 +245,16 @@ func (s *AnnouncementServiceImpl) Update(id uint, gambar *multipart.FileHeader,
 
 	// Update gambar if provided
 	if gambar != nil {
-		// Validate gambar file
-		if gambar.Size > 5*1024*1024 { // 5MB
-			return nil, errors.New("gambar size must not exceed 5MB")
-		}
-
-		allowedTypes := map[string]bool{
-			"image/jpeg": true,
-			"image/png":  true,
-			"image/gif":  true,
-			"image/webp": true,
-		}
-		contentType := gambar.Header.Get("Content-Type")
-		if !allowedTypes[contentType] {
-			return nil, errors.New("only image files are allowed for gambar (jpeg, png, gif, webp)")
-		}
-
-		// Upload new gambar
-		newFileKey, err := s.r2Storage.UploadFile(gambar, "pengumuman")
+		// Validate the file from its content and upload to R2
+		stored, err := utils.SaveUpload(ctx, s.storage, gambar, utils.UploadCategoryGambar, "pengumuman")
 		if err != nil {
 			return nil, err
 		}
+		newFileKey := stored.Key
 
 		// Delete old gambar if exists
 		if oldGambar != "" {
-			_ = s.r2Storage.DeleteFile(oldGambar)
+			_ = s.storage.DeleteFile(oldGambar)
 		}
 
 		existing.Gambar = newFileKey
@@ -323,7 +275,7 @@ func (s *AnnouncementServiceImpl) Update(id uint, gambar *multipart.FileHeader,
 			for _, file := range existingFileItems {
 				if deleteMap[file.ID] {
 					// Delete from R2
-					_ = s.r2Storage.DeleteFile(file.URL)
+					_ = s.storage.DeleteFile(file.URL)
 				} else {
 					remainingFiles = append(remainingFiles, file)
 				}
@@ -349,32 +301,12 @@ func (s *AnnouncementServiceImpl) Update(id uint, gambar *multipart.FileHeader,
 				continue
 			}
 
-			// Validate file size (max 10MB per file)
-			if file.Size > 10*1024*1024 { // 10MB
-				return nil, errors.New("each file must not exceed 10MB")
-			}
-
-			// Validate file type
-			allowedTypes := map[string]bool{
-				"application/pdf":                          true,
-				"application/msword":                       true,
-				"application/vnd.openxmlformats-officedocument.wordprocessingml.document": true,
-				"application/vnd.ms-excel": true,
-				"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": true,
-				"text/plain":               true,
-				"image/jpeg":               true,
-				"image/png":                true,
-			}
-			contentType := file.Header.Get("Content-Type")
-			if !allowedTypes[contentType] {
-				return nil, errors.New("file type not allowed. Allowed: PDF, DOC, DOCX, XLS, XLSX, TXT, JPG, PNG")
-			}
-
-			// Upload file to R2
-			fileKey, err := s.r2Storage.UploadFile(file, "pengumuman")
+			// Validate the file from its content and upload to R2
+			stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryLampiran, "pengumuman")
 			if err != nil {
 				return nil, err
 			}
+			fileKey := stored.Key
 
 			// Generate unique file ID
 			fileID := fmt.Sprintf("file_%d_%s", time.Now().UnixNano(), fileKey[len(fileKey)-8:])
@@ -383,7 +315,7 @@ func (s *AnnouncementServiceImpl) Update(id uint, gambar *multipart.FileHeader,
 				ID:       fileID,
 				Filename: file.Filename,
 				URL:      fileKey,
-				Size:     file.Size,
+				Size:     stored.Size,
 			})
 		}
 
@@ -392,12 +324,13 @@ func (s *AnnouncementServiceImpl) Update(id uint, gambar *multipart.FileHeader,
 		existing.Files = filesJSON
 	}
 
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	if err := s.repository.Update(existing); err != nil {
 		// If DB save fails, delete the uploaded files
 		if gambar != nil {
-			_ = s.r2Storage.DeleteFile(existing.Gambar)
+			_ = s.storage.DeleteFile(existing.Gambar)
 		}
 		return nil, err
 	}
@@ -415,14 +348,14 @@ func (s *AnnouncementServiceImpl) Delete(id uint) error {
 
 	// Delete gambar from R2
 	if existing.Gambar != "" {
-		_ = s.r2Storage.DeleteFile(existing.Gambar)
+		_ = s.storage.DeleteFile(existing.Gambar)
 	}
 
 	// Delete all files from R2
 	var fileItems []models.FileItem
 	if err := json.Unmarshal(existing.Files, &fileItems); err == nil {
 		for _, file := range fileItems {
-			_ = s.r2Storage.DeleteFile(file.URL)
+			_ = s.storage.DeleteFile(file.URL)
 		}
 	}
 
@@ -440,7 +373,7 @@ func (s *AnnouncementServiceImpl) mapToResponse(data *models.Announcement) *dtos
 			fileItems = append(fileItems, dtos.FileItemDTO{
 				ID:       file.ID,
 				Filename: file.Filename,
-				URL:      s.r2Storage.GetPublicURL(file.URL),
+				URL:      s.storage.GetPublicURL(file.URL),
 				Size:     file.Size,
 			})
 		}
@@ -451,7 +384,7 @@ func (s *AnnouncementServiceImpl) mapToResponse(data *models.Announcement) *dtos
 		Judul:           data.Judul,
 		Tanggal:         data.Tanggal,
 		Deskripsi:       data.Deskripsi,
-		Gambar:          s.r2Storage.GetPublicURL(data.Gambar),
+		Gambar:          s.storage.GetPublicURL(data.Gambar),
 		Files:           fileItems,
 		Penulis:         data.Penulis,
 		StatusPublikasi: data.StatusPublikasi,
@@ -459,7 +392,9 @@ func (s *AnnouncementServiceImpl) mapToResponse(data *models.Announcement) *dtos
 		CreatedAt:       data.CreatedAt,
 		UpdatedAt:       data.UpdatedAt,
 		CreatedByID:     data.CreatedByID,
+		CreatedByType:   data.CreatedByType,
 		UpdatedByID:     data.UpdatedByID,
+		UpdatedByType:   data.UpdatedByType,
 	}
 }
 
@@ -475,7 +410,7 @@ func (s *AnnouncementServiceImpl) GetPublicLatest() (*dtos.AnnouncementPublicRes
 		Judul:     data.Judul,
 		Tanggal:   data.Tanggal,
 		Deskripsi: data.Deskripsi,
-		Gambar:    s.r2Storage.GetPublicURL(data.Gambar),
+		Gambar:    s.storage.GetPublicURL(data.Gambar),
 		Penulis:   data.Penulis,
 	}, nil
 }
@@ -495,7 +430,7 @@ func (s *AnnouncementServiceImpl) GetPublicNext3() (*dtos.AnnouncementPublicList
 			Judul:     item.Judul,
 			Tanggal:   item.Tanggal,
 			Deskripsi: item.Deskripsi,
-			Gambar:    s.r2Storage.GetPublicURL(item.Gambar),
+			Gambar:    s.storage.GetPublicURL(item.Gambar),
 			Penulis:   item.Penulis,
 		}
 		responses = append(responses, publicResponse)
@@ -528,7 +463,7 @@ func (s *AnnouncementServiceImpl) GetPublicList(req *dtos.AnnouncementPublicList
 			Judul:     item.Judul,
 			Tanggal:   item.Tanggal,
 			Deskripsi: item.Deskripsi,
-			Gambar:    s.r2Storage.GetPublicURL(item.Gambar),
+			Gambar:    s.storage.GetPublicURL(item.Gambar),
 			Penulis:   item.Penulis,
 		}
 		responses = append(responses, publicResponse)
@@ -560,7 +495,7 @@ func (s *AnnouncementServiceImpl) GetPublicDetailByID(id uint) (*dtos.Announceme
 			fileItems = append(fileItems, dtos.FileItemDTO{
 				ID:       file.ID,
 				Filename: file.Filename,
-				URL:      s.r2Storage.GetPublicURL(file.URL),
+				URL:      s.storage.GetPublicURL(file.URL),
 				Size:     file.Size,
 			})
 		}
@@ -571,7 +506,7 @@ func (s *AnnouncementServiceImpl) GetPublicDetailByID(id uint) (*dtos.Announceme
 		Judul:     data.Judul,
 		Tanggal:   data.Tanggal,
 		Deskripsi: data.Deskripsi,
-		Gambar:    s.r2Storage.GetPublicURL(data.Gambar),
+		Gambar:    s.storage.GetPublicURL(data.Gambar),
 		Penulis:   data.Penulis,
 		Files:     fileItems,
 	}, nil
@@ -592,7 +527,7 @@ func (s *AnnouncementServiceImpl) GetPublicOtherAnnouncements(excludeID uint) (*
 			Judul:     item.Judul,
 			Tanggal:   item.Tanggal,
 			Deskripsi: item.Deskripsi,
-			Gambar:    s.r2Storage.GetPublicURL(item.Gambar),
+			Gambar:    s.storage.GetPublicURL(item.Gambar),
 			Penulis:   item.Penulis,
 		}
 		responses = append(responses, publicResponse)
@@ -5,15 +5,16 @@ import (
 	"pintu-backend/src/dtos"
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
 )
 
 // ApplicationService handles business logic for Application
 type ApplicationService interface {
-	Create(req *dtos.ApplicationCreateRequest, userID uint) (*dtos.ApplicationResponse, error)
+	Create(req *dtos.ApplicationCreateRequest, actor utils.Principal) (*dtos.ApplicationResponse, error)
 	GetByID(id uint) (*dtos.ApplicationResponse, error)
 	GetAll(limit int, offset int) (*dtos.ApplicationListResponse, error)
 	GetAllWithFilter(params repositories.GetApplicationParams) (*dtos.ApplicationListWithPaginationResponse, error)
-	Update(req *dtos.ApplicationUpdateRequest, userID uint) (*dtos.ApplicationResponse, error)
+	Update(req *dtos.ApplicationUpdateRequest, actor utils.Principal) (*dtos.ApplicationResponse, error)
 	Delete(id uint) error
 	GetPublicList(req *dtos.ApplicationPublicRequest) (*dtos.ApplicationPublicListResponse, error)
 }
@@ -28,7 +29,7 @@ func NewApplicationService(repository repositories.ApplicationRepository) Applic
 }
 
 // Create creates a new Application
-func (s *ApplicationServiceImpl) Create(req *dtos.ApplicationCreateRequest, userID uint) (*dtos.ApplicationResponse, error) {
+func (s *ApplicationServiceImpl) Create(req *dtos.ApplicationCreateRequest, actor utils.Principal) (*dtos.ApplicationResponse, error) {
 	// Check if nama already exists
 	_, err := s.repository.GetByNama(req.Nama)
 	if err == nil {
@@ -53,7 +54,8 @@ func (s *ApplicationServiceImpl) Create(req *dtos.ApplicationCreateRequest, user
 		Link:            req.Link,
 		ShowInJumbotron: req.ShowInJumbotron,
 		Status:          status,
-		CreatedByID:     &userID,
+		CreatedByID:     &actor.ID,
+		CreatedByType:   actor.TypePtr(),
 	}
 
 	if err := s.repository.Create(data); err != nil {
@@ -102,7 +104,7 @@ func (s *ApplicationServiceImpl) GetAll(limit int, offset int) (*dtos.Applicatio
 }
 
 // Update updates Application
-func (s *ApplicationServiceImpl) Update(req *dtos.ApplicationUpdateRequest, userID uint) (*dtos.ApplicationResponse, error) {
+func (s *ApplicationServiceImpl) Update(req *dtos.ApplicationUpdateRequest, actor utils.Principal) (*dtos.ApplicationResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(req.ID)
 	if err != nil {
@@ -129,7 +131,8 @@ func (s *ApplicationServiceImpl) Update(req *dtos.ApplicationUpdateRequest, user
 		existing.Status = *req.Status
 	}
 
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	if err := s.repository.Update(existing); err != nil {
 		return nil, err
@@ -192,7 +195,9 @@ func (s *ApplicationServiceImpl) mapToResponse(data *models.Application) *dtos.A
 		CreatedAt:       data.CreatedAt,
 		UpdatedAt:       data.UpdatedAt,
 		CreatedByID:     data.CreatedByID,
+		CreatedByType:   data.CreatedByType,
 		UpdatedByID:     data.UpdatedByID,
+		UpdatedByType:   data.UpdatedByType,
 	}
 }
 
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,10 +12,12 @@ import (
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
 	"pintu-backend/src/utils"
+
+	"gorm.io/datatypes"
 )
 
 type ArticleService interface {
-	Create(gambar *multipart.FileHeader, files []*multipart.FileHeader, req *dtos.ArticleCreateRequest, userID uint) (*dtos.ArticleResponse, error)
+	Create(ctx context.Context, gambar *multipart.FileHeader, files []*multipart.FileHeader, req *dtos.ArticleCreateRequest, actor utils.Principal) (*dtos.ArticleResponse, error)
 	GetByID(id uint) (*dtos.ArticleResponse, error)
 	GetAll(limit int, offset int) (*dtos.ArticleListResponse, error)
 	GetAllWithFilter(params repositories.GetArticleParams) (*dtos.ArticleListWithPaginationResponse, error)
@@ -22,25 +25,25 @@ type ArticleService interface {
 	GetPublicList(req *dtos.ArticlePublicListRequest) (*dtos.ArticlePublicDaftarResponse, error)
 	GetPublicDetailByID(id uint) (*dtos.ArticlePublicDetailResponse, error)
 	GetPublicOtherArticles(excludeID uint) (*dtos.ArticlePublicListResponse, error)
-	Update(id uint, gambar *multipart.FileHeader, files []*multipart.FileHeader, req *dtos.ArticleUpdateRequest, userID uint) (*dtos.ArticleResponse, error)
+	Update(ctx context.Context, id uint, gambar *multipart.FileHeader, files []*multipart.FileHeader, req *dtos.ArticleUpdateRequest, actor utils.Principal) (*dtos.ArticleResponse, error)
 	Delete(id uint) error
 }
 
 type ArticleServiceImpl struct {
 	repository repositories.ArticleRepository
-	r2Storage  *utils.R2Storage
+	storage    utils.Storage
 }
 
 // NewArticleService creates a new Article service
-func NewArticleService(repository repositories.ArticleRepository, r2Storage *utils.R2Storage) ArticleService {
+func NewArticleService(repository repositories.ArticleRepository, storage utils.Storage) ArticleService {
 	return &ArticleServiceImpl{
 		repository: repository,
-		r2Storage:  r2Storage,
+		storage:    storage,
 	}
 }
 
 // Create creates a new Article with file uploads to R2
-func (s *ArticleServiceImpl) Create(gambar *multipart.FileHeader, files []*multipart.FileHeader, req *dtos.ArticleCreateRequest, userID uint) (*dtos.ArticleResponse, error) {
+func (s *ArticleServiceImpl) Create(ctx context.Context, gambar *multipart.FileHeader, files []*multipart.FileHeader, req *dtos.ArticleCreateRequest, actor utils.Principal) (*dtos.ArticleResponse, error) {
 	// Parse tanggal
 	tanggal, err := time.Parse("2006-01-02", req.Tanggal)
 	if err != nil {
@@ -49,29 +52,15 @@ func (s *ArticleServiceImpl) Create(gambar *multipart.FileHeader, files []*multi
 
 	// Upload gambar if provided
 	var gambarURL string
+	var gambarVariants datatypes.JSON
 	if gambar != nil {
-		// Validate gambar file
-		if gambar.Size > 5*1024*1024 { // 5MB
-			return nil, errors.New("gambar size must not exceed 5MB")
-		}
-
-		allowedTypes := map[string]bool{
-			"image/jpeg": true,
-			"image/png":  true,
-			"image/gif":  true,
-			"image/webp": true,
-		}
-		contentType := gambar.Header.Get("Content-Type")
-		if !allowedTypes[contentType] {
-			return nil, errors.New("only image files are allowed for gambar (jpeg, png, gif, webp)")
-		}
-
-		// Upload gambar to R2
-		fileKey, err := s.r2Storage.UploadFile(gambar, "artikel")
+		// Validate and upload gambar to R2 with its variants
+		stored, err := utils.SaveUpload(ctx, s.storage, gambar, utils.UploadCategoryPublikasi, "artikel")
 		if err != nil {
 			return nil, err
 		}
-		gambarURL = fileKey
+		gambarURL = stored.Key
+		gambarVariants = fileVariantsJSON(stored)
 	}
 
 	// Upload files if provided
@@ -82,32 +71,12 @@ func (s *ArticleServiceImpl) Create(gambar *multipart.FileHeader, files []*multi
 				continue
 			}
 
-			// Validate file size (max 10MB per file)
-			if file.Size > 10*1024*1024 { // 10MB
-				return nil, errors.New("each file must not exceed 10MB")
-			}
-
-			// Validate file type - allow multiple file types
-			allowedTypes := map[string]bool{
-				"application/pdf":                          true,
-				"application/msword":                       true,
-				"application/vnd.openxmlformats-officedocument.wordprocessingml.document": true,
-				"application/vnd.ms-excel": true,
-				"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": true,
-				"text/plain":               true,
-				"image/jpeg":               true,
-				"image/png":                true,
-			}
-			contentType := file.Header.Get("Content-Type")
-			if !allowedTypes[contentType] {
-				return nil, errors.New("file type not allowed. Allowed: PDF, DOC, DOCX, XLS, XLSX, TXT, JPG, PNG")
-			}
-
-			// Upload file to R2
-			fileKey, err := s.r2Storage.UploadFile(file, "artikel")
+			// Validate the file type from its content (PDF, DOC, DOCX, XLS, XLSX, TXT, JPG, PNG) and upload to R2
+			stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryLampiran, "artikel")
 			if err != nil {
 				return nil, err
 			}
+			fileKey := stored.Key
 
 			// Generate unique file ID: file_timestamp_randomstring
 			fileID := fmt.Sprintf("file_%d_%s", time.Now().UnixNano(), fileKey[len(fileKey)-8:])
@@ -116,7 +85,7 @@ func (s *ArticleServiceImpl) Create(gambar *multipart.FileHeader, files []*multi
 				ID:       fileID,
 				Filename: file.Filename,
 				URL:      fileKey,
-				Size:     file.Size,
+				Size:     stored.Size,
 			})
 		}
 	}
@@ -142,20 +111,23 @@ func (s *ArticleServiceImpl) Create(gambar *multipart.FileHeader, files []*multi
 		Kategori:        req.Kategori,
 		Deskripsi:       req.Deskripsi,
 		Gambar:          gambarURL,
+		GambarVariants:  gambarVariants,
 		Files:           filesJSON,
 		Penulis:         req.Penulis,
 		StatusPublikasi: statusPublikasi,
 		Status:          status,
-		CreatedByID:     &userID,
+		CreatedByID:     &actor.ID,
+		CreatedByType:   actor.TypePtr(),
 	}
 
 	if err := s.repository.Create(data); err != nil {
 		// If database save fails, delete uploaded files
 		if gambarURL != "" {
-			_ = s.r2Storage.DeleteFile(gambarURL)
+			_ = s.storage.DeleteFile(gambarURL)
+			deleteFileVariants(s.storage, gambarURL, parseFileVariants(gambarVariants))
 		}
 		for _, item := range fileItems {
-			_ = s.r2Storage.DeleteFile(item.URL)
+			_ = s.storage.DeleteFile(item.URL)
 		}
 		return nil, err
 	}
@@ -243,7 +215,7 @@ func (s *ArticleServiceImpl) GetAllWithFilter(params repositories.GetArticlePara
 }
 
 // Update updates Article
-func (s *ArticleServiceImpl) Update(id uint, gambar *multipart.FileHeader, files []*multipart.FileHeader, req *dtos.ArticleUpdateRequest, userID uint) (*dtos.ArticleResponse, error) {
+func (s *ArticleServiceImpl) Update(ctx context.Context, id uint, gambar *multipart.FileHeader, files []*multipart.FileHeader, req *dtos.ArticleUpdateRequest, actor utils.Principal) (*dtos.ArticleResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(id)
 	if err != nil {
@@ -281,34 +253,20 @@ func (s *ArticleServiceImpl) Update(id uint, gambar *multipart.FileHeader, files
 
 	// Update gambar if provided
 	if gambar != nil {
-		// Validate gambar file
-		if gambar.Size > 5*1024*1024 { // 5MB
-			return nil, errors.New("gambar size must not exceed 5MB")
-		}
-
-		allowedTypes := map[string]bool{
-			"image/jpeg": true,
-			"image/png":  true,
-			"image/gif":  true,
-			"image/webp": true,
-		}
-		contentType := gambar.Header.Get("Content-Type")
-		if !allowedTypes[contentType] {
-			return nil, errors.New("only image files are allowed for gambar (jpeg, png, gif, webp)")
-		}
-
-		// Upload new gambar
-		newFileKey, err := s.r2Storage.UploadFile(gambar, "artikel")
+		// Validate and upload new gambar with its variants
+		stored, err := utils.SaveUpload(ctx, s.storage, gambar, utils.UploadCategoryPublikasi, "artikel")
 		if err != nil {
 			return nil, err
 		}
 
 		// Delete old gambar if exists
 		if oldGambar != "" {
-			_ = s.r2Storage.DeleteFile(oldGambar)
+			_ = s.storage.DeleteFile(oldGambar)
+			deleteFileVariants(s.storage, oldGambar, parseFileVariants(existing.GambarVariants))
 		}
 
-		existing.Gambar = newFileKey
+		existing.Gambar = stored.Key
+		existing.GambarVariants = fileVariantsJSON(stored)
 	}
 
 	// Delete files if specified
@@ -326,7 +284,7 @@ func (s *ArticleServiceImpl) Update(id uint, gambar *multipart.FileHeader, files
 			for _, file := range existingFileItems {
 				if deleteMap[file.ID] {
 					// Delete from R2
-					_ = s.r2Storage.DeleteFile(file.URL)
+					_ = s.storage.DeleteFile(file.URL)
 				} else {
 					remainingFiles = append(remainingFiles, file)
 				}
@@ -352,32 +310,12 @@ func (s *ArticleServiceImpl) Update(id uint, gambar *multipart.FileHeader, files
 				continue
 			}
 
-			// Validate file size (max 10MB per file)
-			if file.Size > 10*1024*1024 { // 10MB
-				return nil, errors.New("each file must not exceed 10MB")
-			}
-
-			// Validate file type
-			allowedTypes := map[string]bool{
-				"application/pdf":                          true,
-				"application/msword":                       true,
-				"application/vnd.openxmlformats-officedocument.wordprocessingml.document": true,
-				"application/vnd.ms-excel": true,
-				"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": true,
-				"text/plain":               true,
-				"image/jpeg":               true,
-				"image/png":                true,
-			}
-			contentType := file.Header.Get("Content-Type")
-			if !allowedTypes[contentType] {
-				return nil, errors.New("file type not allowed. Allowed: PDF, DOC, DOCX, XLS, XLSX, TXT, JPG, PNG")
-			}
-
-			// Upload file to R2
-			fileKey, err := s.r2Storage.UploadFile(file, "artikel")
+			// Validate the file type from its content and upload to R2
+			stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryLampiran, "artikel")
 			if err != nil {
 				return nil, err
 			}
+			fileKey := stored.Key
 
 			// Generate unique file ID
 			fileID := fmt.Sprintf("file_%d_%s", time.Now().UnixNano(), fileKey[len(fileKey)-8:])
@@ -386,7 +324,7 @@ func (s *ArticleServiceImpl) Update(id uint, gambar *multipart.FileHeader, files
 				ID:       fileID,
 				Filename: file.Filename,
 				URL:      fileKey,
-				Size:     file.Size,
+				Size:     stored.Size,
 			})
 		}
 
@@ -395,12 +333,14 @@ func (s *ArticleServiceImpl) Update(id uint, gambar *multipart.FileHeader, files
 		existing.Files = filesJSON
 	}
 
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	if err := s.repository.Update(existing); err != nil {
 		// If DB save fails, delete the uploaded files
 		if gambar != nil {
-			_ = s.r2Storage.DeleteFile(existing.Gambar)
+			_ = s.storage.DeleteFile(existing.Gambar)
+			deleteFileVariants(s.storage, existing.Gambar, parseFileVariants(existing.GambarVariants))
 		}
 		return nil, err
 	}
@@ -418,14 +358,15 @@ func (s *ArticleServiceImpl) Delete(id uint) error {
 
 	// Delete gambar from R2
 	if existing.Gambar != "" {
-		_ = s.r2Storage.DeleteFile(existing.Gambar)
+		_ = s.storage.DeleteFile(existing.Gambar)
+		deleteFileVariants(s.storage, existing.Gambar, parseFileVariants(existing.GambarVariants))
 	}
 
 	// Delete all files from R2
 	var fileItems []models.FileItem
 	if err := json.Unmarshal(existing.Files, &fileItems); err == nil {
 		for _, file := range fileItems {
-			_ = s.r2Storage.DeleteFile(file.URL)
+			_ = s.storage.DeleteFile(file.URL)
 		}
 	}
 
@@ -443,7 +384,7 @@ func (s *ArticleServiceImpl) mapToResponse(data *models.Article) *dtos.ArticleRe
 			fileItems = append(fileItems, dtos.FileItemDTO{
 				ID:       file.ID,
 				Filename: file.Filename,
-				URL:      s.r2Storage.GetPublicURL(file.URL),
+				URL:      s.storage.GetPublicURL(file.URL),
 				Size:     file.Size,
 			})
 		}
@@ -455,7 +396,8 @@ func (s *ArticleServiceImpl) mapToResponse(data *models.Article) *dtos.ArticleRe
 		Tanggal:         data.Tanggal,
 		Kategori:        data.Kategori,
 		Deskripsi:       data.Deskripsi,
-		Gambar:          s.r2Storage.GetPublicURL(data.Gambar),
+		Gambar:          s.storage.GetPublicURL(data.Gambar),
+		GambarVariants:  fileVariantDTOs(s.storage, parseFileVariants(data.GambarVariants)),
 		Files:           fileItems,
 		Penulis:         data.Penulis,
 		StatusPublikasi: data.StatusPublikasi,
@@ -463,7 +405,9 @@ func (s *ArticleServiceImpl) mapToResponse(data *models.Article) *dtos.ArticleRe
 		CreatedAt:       data.CreatedAt,
 		UpdatedAt:       data.UpdatedAt,
 		CreatedByID:     data.CreatedByID,
+		CreatedByType:   data.CreatedByType,
 		UpdatedByID:     data.UpdatedByID,
+		UpdatedByType:   data.UpdatedByType,
 	}
 }
 
@@ -478,13 +422,14 @@ func (s *ArticleServiceImpl) GetPublicLatest() (*dtos.ArticlePublicListResponse,
 	responses := make([]dtos.ArticlePublicResponse, 0)
 	for _, item := range data {
 		publicResponse := dtos.ArticlePublicResponse{
-			ID:        item.ID,
-			Judul:     item.Judul,
-			Tanggal:   item.Tanggal,
-			Kategori:  item.Kategori,
-			Deskripsi: item.Deskripsi,
-			Gambar:    s.r2Storage.GetPublicURL(item.Gambar),
-			Penulis:   item.Penulis,
+			ID:             item.ID,
+			Judul:          item.Judul,
+			Tanggal:        item.Tanggal,
+			Kategori:       item.Kategori,
+			Deskripsi:      item.Deskripsi,
+			Gambar:         s.storage.GetPublicURL(item.Gambar),
+			GambarVariants: fileVariantDTOs(s.storage, parseFileVariants(item.GambarVariants)),
+			Penulis:        item.Penulis,
 		}
 		responses = append(responses, publicResponse)
 	}
@@ -512,13 +457,14 @@ func (s *ArticleServiceImpl) GetPublicList(req *dtos.ArticlePublicListRequest) (
 	responses := make([]dtos.ArticlePublicResponse, 0)
 	for _, item := range data {
 		publicResponse := dtos.ArticlePublicResponse{
-			ID:        item.ID,
-			Judul:     item.Judul,
-			Tanggal:   item.Tanggal,
-			Kategori:  item.Kategori,
-			Deskripsi: item.Deskripsi,
-			Gambar:    s.r2Storage.GetPublicURL(item.Gambar),
-			Penulis:   item.Penulis,
+			ID:             item.ID,
+			Judul:          item.Judul,
+			Tanggal:        item.Tanggal,
+			Kategori:       item.Kategori,
+			Deskripsi:      item.Deskripsi,
+			Gambar:         s.storage.GetPublicURL(item.Gambar),
+			GambarVariants: fileVariantDTOs(s.storage, parseFileVariants(item.GambarVariants)),
+			Penulis:        item.Penulis,
 		}
 		responses = append(responses, publicResponse)
 	}
@@ -549,21 +495,22 @@ func (s *ArticleServiceImpl) GetPublicDetailByID(id uint) (*dtos.ArticlePublicDe
 			fileItems = append(fileItems, dtos.FileItemDTO{
 				ID:       file.ID,
 				Filename: file.Filename,
-				URL:      s.r2Storage.GetPublicURL(file.URL),
+				URL:      s.storage.GetPublicURL(file.URL),
 				Size:     file.Size,
 			})
 		}
 	}
 
 	return &dtos.ArticlePublicDetailResponse{
-		ID:        data.ID,
-		Judul:     data.Judul,
-		Tanggal:   data.Tanggal,
-		Kategori:  data.Kategori,
-		Deskripsi: data.Deskripsi,
-		Gambar:    s.r2Storage.GetPublicURL(data.Gambar),
-		Penulis:   data.Penulis,
-		Files:     fileItems,
+		ID:             data.ID,
+		Judul:          data.Judul,
+		Tanggal:        data.Tanggal,
+		Kategori:       data.Kategori,
+		Deskripsi:      data.Deskripsi,
+		Gambar:         s.storage.GetPublicURL(data.Gambar),
+		GambarVariants: fileVariantDTOs(s.storage, parseFileVariants(data.GambarVariants)),
+		Penulis:        data.Penulis,
+		Files:          fileItems,
 	}, nil
 }
 
@@ -578,13 +525,14 @@ func (s *ArticleServiceImpl) GetPublicOtherArticles(excludeID uint) (*dtos.Artic
 	responses := make([]dtos.ArticlePublicResponse, 0)
 	for _, item := range data {
 		publicResponse := dtos.ArticlePublicResponse{
-			ID:        item.ID,
-			Judul:     item.Judul,
-			Tanggal:   item.Tanggal,
-			Kategori:  item.Kategori,
-			Deskripsi: item.Deskripsi,
-			Gambar:    s.r2Storage.GetPublicURL(item.Gambar),
-			Penulis:   item.Penulis,
+			ID:             item.ID,
+			Judul:          item.Judul,
+			Tanggal:        item.Tanggal,
+			Kategori:       item.Kategori,
+			Deskripsi:      item.Deskripsi,
+			Gambar:         s.storage.GetPublicURL(item.Gambar),
+			GambarVariants: fileVariantDTOs(s.storage, parseFileVariants(item.GambarVariants)),
+			Penulis:        item.Penulis,
 		}
 		responses = append(responses, publicResponse)
 	}
@@ -5,15 +5,16 @@ import (
 	"pintu-backend/src/dtos"
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
 )
 
 // BidangStudiService handles business logic for BidangStudi
 type BidangStudiService interface {
-	Create(req *dtos.BidangStudiCreateRequest, userID uint) (*dtos.BidangStudiResponse, error)
+	Create(req *dtos.BidangStudiCreateRequest, actor utils.Principal) (*dtos.BidangStudiResponse, error)
 	GetByID(id uint) (*dtos.BidangStudiResponse, error)
 	GetAll(limit int, offset int) (*dtos.BidangStudiListResponse, error)
 	GetAllWithFilter(params repositories.GetBidangStudiParams) (*dtos.BidangStudiListWithPaginationResponse, error)
-	Update(req *dtos.BidangStudiUpdateRequest, userID uint) (*dtos.BidangStudiResponse, error)
+	Update(req *dtos.BidangStudiUpdateRequest, actor utils.Principal) (*dtos.BidangStudiResponse, error)
 	Delete(id uint) error
 }
 
@@ -27,7 +28,7 @@ func NewBidangStudiService(repository repositories.BidangStudiRepository) Bidang
 }
 
 // Create creates a new BidangStudi
-func (s *BidangStudiServiceImpl) Create(req *dtos.BidangStudiCreateRequest, userID uint) (*dtos.BidangStudiResponse, error) {
+func (s *BidangStudiServiceImpl) Create(req *dtos.BidangStudiCreateRequest, actor utils.Principal) (*dtos.BidangStudiResponse, error) {
 	// Check if name already exists
 	_, err := s.repository.GetByName(req.Name)
 	if err == nil {
@@ -41,9 +42,10 @@ func (s *BidangStudiServiceImpl) Create(req *dtos.BidangStudiCreateRequest, user
 	}
 
 	data := &models.BidangStudi{
-		Name:        req.Name,
-		Status:      status,
-		CreatedByID: &userID,
+		Name:          req.Name,
+		Status:        status,
+		CreatedByID:   &actor.ID,
+		CreatedByType: actor.TypePtr(),
 	}
 
 	if err := s.repository.Create(data); err != nil {
@@ -92,7 +94,7 @@ func (s *BidangStudiServiceImpl) GetAll(limit int, offset int) (*dtos.BidangStud
 }
 
 // Update updates BidangStudi
-func (s *BidangStudiServiceImpl) Update(req *dtos.BidangStudiUpdateRequest, userID uint) (*dtos.BidangStudiResponse, error) {
+func (s *BidangStudiServiceImpl) Update(req *dtos.BidangStudiUpdateRequest, actor utils.Principal) (*dtos.BidangStudiResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(req.ID)
 	if err != nil {
@@ -107,7 +109,8 @@ func (s *BidangStudiServiceImpl) Update(req *dtos.BidangStudiUpdateRequest, user
 		existing.Status = *req.Status
 	}
 
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	if err := s.repository.Update(existing); err != nil {
 		return nil, err
@@ -162,12 +165,14 @@ func (s *BidangStudiServiceImpl) GetAllWithFilter(params repositories.GetBidangS
 // mapToResponse maps model to DTO response
 func (s *BidangStudiServiceImpl) mapToResponse(data *models.BidangStudi) *dtos.BidangStudiResponse {
 	return &dtos.BidangStudiResponse{
-		ID:          data.ID,
-		Name:        data.Name,
-		Status:      data.Status,
-		CreatedAt:   data.CreatedAt,
-		UpdatedAt:   data.UpdatedAt,
-		CreatedByID: data.CreatedByID,
-		UpdatedByID: data.UpdatedByID,
+		ID:            data.ID,
+		Name:          data.Name,
+		Status:        data.Status,
+		CreatedAt:     data.CreatedAt,
+		UpdatedAt:     data.UpdatedAt,
+		CreatedByID:   data.CreatedByID,
+		CreatedByType: data.CreatedByType,
+		UpdatedByID:   data.UpdatedByID,
+		UpdatedByType: data.UpdatedByType,
 	}
 }
@@ -7,14 +7,15 @@ import (
 	"pintu-backend/src/dtos"
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
 )
 
 type ContactService interface {
-	Create(req *dtos.ContactCreateRequest, userID uint) (*dtos.ContactResponse, error)
+	Create(req *dtos.ContactCreateRequest, actor utils.Principal) (*dtos.ContactResponse, error)
 	GetByID(id uint) (*dtos.ContactResponse, error)
 	GetAll() ([]*dtos.ContactResponse, error)
 	GetPublic() (*dtos.ContactPublicResponse, error)
-	Update(id uint, req *dtos.ContactUpdateRequest, userID uint) (*dtos.ContactResponse, error)
+	Update(id uint, req *dtos.ContactUpdateRequest, actor utils.Principal) (*dtos.ContactResponse, error)
 	Delete(id uint) error
 }
 
@@ -30,7 +31,7 @@ func NewContactService(repository repositories.ContactRepository) ContactService
 }
 
 // Create creates a new Contact
-func (s *ContactServiceImpl) Create(req *dtos.ContactCreateRequest, userID uint) (*dtos.ContactResponse, error) {
+func (s *ContactServiceImpl) Create(req *dtos.ContactCreateRequest, actor utils.Principal) (*dtos.ContactResponse, error) {
 	// Convert jam_buka to JSON
 	jamBukaJSON, _ := json.Marshal(req.JamBuka)
 
@@ -47,7 +48,8 @@ func (s *ContactServiceImpl) Create(req *dtos.ContactCreateRequest, userID uint)
 		Tiktok:    req.Tiktok,
 		Facebook:  req.Facebook,
 		Twitter:   req.Twitter,
-		CreatedByID: &userID,
+		CreatedByID: &actor.ID,
+		CreatedByType: actor.TypePtr(),
 	}
 
 	if err := s.repository.Create(data); err != nil {
@@ -83,7 +85,7 @@ func (s *ContactServiceImpl) GetAll() ([]*dtos.ContactResponse, error) {
 }
 
 // Update updates Contact
-func (s *ContactServiceImpl) Update(id uint, req *dtos.ContactUpdateRequest, userID uint) (*dtos.ContactResponse, error) {
+func (s *ContactServiceImpl) Update(id uint, req *dtos.ContactUpdateRequest, actor utils.Principal) (*dtos.ContactResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(id)
 	if err != nil {
@@ -126,7 +128,8 @@ func (s *ContactServiceImpl) Update(id uint, req *dtos.ContactUpdateRequest, use
 		existing.Twitter = req.Twitter
 	}
 
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	if err := s.repository.Update(existing); err != nil {
 		return nil, err
@@ -156,22 +159,24 @@ func (s *ContactServiceImpl) mapToResponse(data *models.Contact) *dtos.ContactRe
 	}
 
 	return &dtos.ContactResponse{
-		ID:        data.ID,
-		Alamat:    data.Alamat,
-		Telepon:   data.Telepon,
-		Email:     data.Email,
-		JamBuka:   jamBukaItems,
-		Gmaps:     data.Gmaps,
-		Website:   data.Website,
-		Youtube:   data.Youtube,
-		Instagram: data.Instagram,
-		Tiktok:    data.Tiktok,
-		Facebook:  data.Facebook,
-		Twitter:   data.Twitter,
-		CreatedAt: data.CreatedAt,
-		UpdatedAt: data.UpdatedAt,
-		CreatedByID: data.CreatedByID,
-		UpdatedByID: data.UpdatedByID,
+		ID:            data.ID,
+		Alamat:        data.Alamat,
+		Telepon:       data.Telepon,
+		Email:         data.Email,
+		JamBuka:       jamBukaItems,
+		Gmaps:         data.Gmaps,
+		Website:       data.Website,
+		Youtube:       data.Youtube,
+		Instagram:     data.Instagram,
+		Tiktok:        data.Tiktok,
+		Facebook:      data.Facebook,
+		Twitter:       data.Twitter,
+		CreatedAt:     data.CreatedAt,
+		UpdatedAt:     data.UpdatedAt,
+		CreatedByID:   data.CreatedByID,
+		CreatedByType: data.CreatedByType,
+		UpdatedByID:   data.UpdatedByID,
+		UpdatedByType: data.UpdatedByType,
 	}
 }
 
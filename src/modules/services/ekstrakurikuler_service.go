@@ -5,15 +5,16 @@ import (
 	"pintu-backend/src/dtos"
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
 )
 
 // EkstrakurikulerService handles business logic for Ekstrakurikuler
 type EkstrakurikulerService interface {
-	Create(req *dtos.EkstrakurikulerCreateRequest, userID uint) (*dtos.EkstrakurikulerResponse, error)
+	Create(req *dtos.EkstrakurikulerCreateRequest, actor utils.Principal) (*dtos.EkstrakurikulerResponse, error)
 	GetByID(id uint) (*dtos.EkstrakurikulerResponse, error)
 	GetAll(limit int, offset int) (*dtos.EkstrakurikulerListResponse, error)
 	GetAllWithFilter(params repositories.GetEkstrakurikulerParams) (*dtos.EkstrakurikulerListWithPaginationResponse, error)
-	Update(req *dtos.EkstrakurikulerUpdateRequest, userID uint) (*dtos.EkstrakurikulerResponse, error)
+	Update(req *dtos.EkstrakurikulerUpdateRequest, actor utils.Principal) (*dtos.EkstrakurikulerResponse, error)
 	Delete(id uint) error
 	GetTotalEkskul() (*dtos.TotalEkskulResponse, error)
 }
@@ -29,7 +30,7 @@ func NewEkstrakurikulerService(repository repositories.EkstrakurikulerRepository
 }
 
 // Create creates a new Ekstrakurikuler
-func (s *EkstrakurikulerServiceImpl) Create(req *dtos.EkstrakurikulerCreateRequest, userID uint) (*dtos.EkstrakurikulerResponse, error) {
+func (s *EkstrakurikulerServiceImpl) Create(req *dtos.EkstrakurikulerCreateRequest, actor utils.Principal) (*dtos.EkstrakurikulerResponse, error) {
 	// Check if name already exists
 	_, err := s.repository.GetByName(req.Name)
 	if err == nil {
@@ -58,11 +59,12 @@ func (s *EkstrakurikulerServiceImpl) Create(req *dtos.EkstrakurikulerCreateReque
 	}
 
 	data := &models.Ekstrakurikuler{
-		Name:        req.Name,
-		KelasIDs:    models.KelasIDs(req.KelasIDs),
-		Kategori:    req.Kategori,
-		Status:      status,
-		CreatedByID: &userID,
+		Name:          req.Name,
+		KelasIDs:      models.KelasIDs(req.KelasIDs),
+		Kategori:      req.Kategori,
+		Status:        status,
+		CreatedByID:   &actor.ID,
+		CreatedByType: actor.TypePtr(),
 	}
 
 	if err := s.repository.Create(data); err != nil {
@@ -111,7 +113,7 @@ func (s *EkstrakurikulerServiceImpl) GetAll(limit int, offset int) (*dtos.Ekstra
 }
 
 // Update updates Ekstrakurikuler
-func (s *EkstrakurikulerServiceImpl) Update(req *dtos.EkstrakurikulerUpdateRequest, userID uint) (*dtos.EkstrakurikulerResponse, error) {
+func (s *EkstrakurikulerServiceImpl) Update(req *dtos.EkstrakurikulerUpdateRequest, actor utils.Principal) (*dtos.EkstrakurikulerResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(req.ID)
 	if err != nil {
@@ -139,7 +141,8 @@ func (s *EkstrakurikulerServiceImpl) Update(req *dtos.EkstrakurikulerUpdateReque
 		existing.KelasIDs = models.KelasIDs(req.KelasIDs)
 	}
 
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	if err := s.repository.Update(existing); err != nil {
 		return nil, err
@@ -209,16 +212,18 @@ func (s *EkstrakurikulerServiceImpl) mapToResponse(data *models.Ekstrakurikuler)
 	}
 
 	return &dtos.EkstrakurikulerResponse{
-		ID:          data.ID,
-		Name:        data.Name,
-		KelasIDs:    kelasIDs,
-		Kelas:       kelasDetails,
-		Kategori:    data.Kategori,
-		Status:      data.Status,
-		CreatedAt:   data.CreatedAt,
-		UpdatedAt:   data.UpdatedAt,
-		CreatedByID: data.CreatedByID,
-		UpdatedByID: data.UpdatedByID,
+		ID:            data.ID,
+		Name:          data.Name,
+		KelasIDs:      kelasIDs,
+		Kelas:         kelasDetails,
+		Kategori:      data.Kategori,
+		Status:        data.Status,
+		CreatedAt:     data.CreatedAt,
+		UpdatedAt:     data.UpdatedAt,
+		CreatedByID:   data.CreatedByID,
+		CreatedByType: data.CreatedByType,
+		UpdatedByID:   data.UpdatedByID,
+		UpdatedByType: data.UpdatedByType,
 	}
 }
 
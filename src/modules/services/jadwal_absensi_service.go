@@ -0,0 +1,681 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// JadwalAbsensiService handles business logic for schedule profiles, their assignments
+// and resolving the scan windows that apply to a student on a date
+type JadwalAbsensiService interface {
+	CreateProfil(req *dtos.ProfilJadwalAbsensiCreateRequest, actor utils.Principal) (*dtos.ProfilJadwalAbsensiResponse, error)
+	GetProfilByID(id uint) (*dtos.ProfilJadwalAbsensiResponse, error)
+	GetAllProfil(params repositories.GetProfilJadwalAbsensiParams) (*dtos.ProfilJadwalAbsensiListWithPaginationResponse, error)
+	UpdateProfil(req *dtos.ProfilJadwalAbsensiUpdateRequest, actor utils.Principal) (*dtos.ProfilJadwalAbsensiResponse, error)
+	DeleteProfil(id uint) error
+	CreatePenugasan(req *dtos.PenugasanJadwalAbsensiRequest, actor utils.Principal) (*dtos.PenugasanJadwalAbsensiResponse, error)
+	GetAllPenugasan(params repositories.GetPenugasanJadwalAbsensiParams) (*dtos.PenugasanJadwalAbsensiListWithPaginationResponse, error)
+	UpdatePenugasan(req *dtos.PenugasanJadwalAbsensiUpdateRequest, actor utils.Principal) (*dtos.PenugasanJadwalAbsensiResponse, error)
+	DeletePenugasan(id uint) error
+	ResolveJadwal(pesertaDidikID uint, tanggal time.Time, fallback *models.KonfigurasiAbsensi) (*JadwalAbsensiEfektif, error)
+	PreviewJadwal(req *dtos.JadwalAbsensiPreviewRequest) (*dtos.JadwalAbsensiPreviewResponse, error)
+}
+
+// JadwalAbsensiEfektif is the schedule a student scans against on one date
+type JadwalAbsensiEfektif struct {
+	Rombel           *models.Rombel                 // Nil when the student has no active rombel
+	Penugasan        *models.PenugasanJadwalAbsensi // Nil when falling back to konfigurasi absensi
+	OverrideHari     bool
+	JamDatangMulai   string
+	JamMaxDatang     string
+	JamDatangSelesai string
+	JamPulangMulai   string
+	JamPulangSelesai string
+}
+
+// jendela returns the scan windows of the schedule
+func (j *JadwalAbsensiEfektif) jendela() jendelaScan {
+	return jendelaScan{
+		DatangMulai:   normalizeJam(j.JamDatangMulai),
+		MaxDatang:     normalizeJam(j.JamMaxDatang),
+		DatangSelesai: normalizeJam(j.JamDatangSelesai),
+		PulangMulai:   normalizeJam(j.JamPulangMulai),
+		PulangSelesai: normalizeJam(j.JamPulangSelesai),
+	}
+}
+
+type JadwalAbsensiServiceImpl struct {
+	repository       repositories.JadwalAbsensiRepository
+	pesertaDidikRepo repositories.PesertaDidikRepository
+	kalenderService  KalenderAkademikService
+}
+
+// NewJadwalAbsensiService creates a new JadwalAbsensi service
+func NewJadwalAbsensiService(repository repositories.JadwalAbsensiRepository, pesertaDidikRepo repositories.PesertaDidikRepository, kalenderService KalenderAkademikService) JadwalAbsensiService {
+	return &JadwalAbsensiServiceImpl{
+		repository:       repository,
+		pesertaDidikRepo: pesertaDidikRepo,
+		kalenderService:  kalenderService,
+	}
+}
+
+// CreateProfil creates a schedule profile with its weekday overrides
+func (s *JadwalAbsensiServiceImpl) CreateProfil(req *dtos.ProfilJadwalAbsensiCreateRequest, actor utils.Principal) (*dtos.ProfilJadwalAbsensiResponse, error) {
+	data := &models.ProfilJadwalAbsensi{
+		Nama:             req.Nama,
+		Keterangan:       req.Keterangan,
+		JamDatangMulai:   req.JamDatangMulai,
+		JamMaxDatang:     req.JamMaxDatang,
+		JamDatangSelesai: req.JamDatangSelesai,
+		JamPulangMulai:   req.JamPulangMulai,
+		JamPulangSelesai: req.JamPulangSelesai,
+		Status:           "active",
+		CreatedByID:      &actor.ID,
+		CreatedByType:    actor.TypePtr(),
+	}
+	if req.Status != "" {
+		data.Status = req.Status
+	}
+	for _, hari := range req.Hari {
+		data.Hari = append(data.Hari, mapHariRequestToModel(hari))
+	}
+
+	if err := validateProfilJadwal(data); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.CreateProfil(data); err != nil {
+		return nil, err
+	}
+
+	return s.GetProfilByID(data.ID)
+}
+
+// GetProfilByID retrieves a schedule profile by ID
+func (s *JadwalAbsensiServiceImpl) GetProfilByID(id uint) (*dtos.ProfilJadwalAbsensiResponse, error) {
+	data, err := s.repository.GetProfilByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.mapProfilToResponse(data), nil
+}
+
+// GetAllProfil retrieves schedule profiles with filters and pagination
+func (s *JadwalAbsensiServiceImpl) GetAllProfil(params repositories.GetProfilJadwalAbsensiParams) (*dtos.ProfilJadwalAbsensiListWithPaginationResponse, error) {
+	// Validate and set default limit and offset
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+	if params.Offset < 0 {
+		params.Offset = 0
+	}
+
+	data, total, err := s.repository.GetAllProfil(params)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dtos.ProfilJadwalAbsensiResponse, len(data))
+	for i, item := range data {
+		responses[i] = *s.mapProfilToResponse(&item)
+	}
+
+	totalPages := (int(total) + params.Limit - 1) / params.Limit
+
+	return &dtos.ProfilJadwalAbsensiListWithPaginationResponse{
+		Data: responses,
+		Pagination: dtos.PaginationInfo{
+			Limit:      params.Limit,
+			Offset:     params.Offset,
+			Page:       (params.Offset / params.Limit) + 1,
+			Total:      total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// UpdateProfil updates a schedule profile; weekday overrides are replaced when sent
+func (s *JadwalAbsensiServiceImpl) UpdateProfil(req *dtos.ProfilJadwalAbsensiUpdateRequest, actor utils.Principal) (*dtos.ProfilJadwalAbsensiResponse, error) {
+	existing, err := s.repository.GetProfilByID(req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update fields if provided
+	if req.Nama != nil {
+		existing.Nama = *req.Nama
+	}
+	if req.Keterangan != nil {
+		existing.Keterangan = req.Keterangan
+	}
+	if req.JamDatangMulai != nil {
+		existing.JamDatangMulai = *req.JamDatangMulai
+	}
+	if req.JamMaxDatang != nil {
+		existing.JamMaxDatang = *req.JamMaxDatang
+	}
+	if req.JamDatangSelesai != nil {
+		existing.JamDatangSelesai = *req.JamDatangSelesai
+	}
+	if req.JamPulangMulai != nil {
+		existing.JamPulangMulai = *req.JamPulangMulai
+	}
+	if req.JamPulangSelesai != nil {
+		existing.JamPulangSelesai = *req.JamPulangSelesai
+	}
+	if req.Status != nil {
+		existing.Status = *req.Status
+	}
+	if req.Hari != nil {
+		existing.Hari = nil
+		for _, hari := range *req.Hari {
+			existing.Hari = append(existing.Hari, mapHariRequestToModel(hari))
+		}
+	}
+
+	if err := validateProfilJadwal(existing); err != nil {
+		return nil, err
+	}
+
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
+
+	if err := s.repository.UpdateProfil(existing); err != nil {
+		return nil, err
+	}
+
+	return s.GetProfilByID(existing.ID)
+}
+
+// DeleteProfil deletes a schedule profile that is no longer assigned
+func (s *JadwalAbsensiServiceImpl) DeleteProfil(id uint) error {
+	if _, err := s.repository.GetProfilByID(id); err != nil {
+		return errors.New("profil jadwal absensi tidak ditemukan")
+	}
+
+	total, err := s.repository.CountPenugasanByProfil(id)
+	if err != nil {
+		return err
+	}
+	if total > 0 {
+		return fmt.Errorf("profil jadwal masih digunakan oleh %d penugasan, hapus penugasannya terlebih dahulu", total)
+	}
+	return s.repository.DeleteProfil(id)
+}
+
+// CreatePenugasan assigns a schedule profile to all students, a kelas or a rombel
+func (s *JadwalAbsensiServiceImpl) CreatePenugasan(req *dtos.PenugasanJadwalAbsensiRequest, actor utils.Principal) (*dtos.PenugasanJadwalAbsensiResponse, error) {
+	data := &models.PenugasanJadwalAbsensi{
+		CreatedByID:   &actor.ID,
+		CreatedByType: actor.TypePtr(),
+	}
+	if err := s.applyPenugasanRequest(data, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.CreatePenugasan(data); err != nil {
+		return nil, err
+	}
+
+	return s.getPenugasanByID(data.ID)
+}
+
+// GetAllPenugasan retrieves assignments with filters and pagination
+func (s *JadwalAbsensiServiceImpl) GetAllPenugasan(params repositories.GetPenugasanJadwalAbsensiParams) (*dtos.PenugasanJadwalAbsensiListWithPaginationResponse, error) {
+	// Validate and set default limit and offset
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+	if params.Offset < 0 {
+		params.Offset = 0
+	}
+
+	data, total, err := s.repository.GetAllPenugasan(params)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dtos.PenugasanJadwalAbsensiResponse, len(data))
+	for i, item := range data {
+		responses[i] = *s.mapPenugasanToResponse(&item)
+	}
+
+	totalPages := (int(total) + params.Limit - 1) / params.Limit
+
+	return &dtos.PenugasanJadwalAbsensiListWithPaginationResponse{
+		Data: responses,
+		Pagination: dtos.PaginationInfo{
+			Limit:      params.Limit,
+			Offset:     params.Offset,
+			Page:       (params.Offset / params.Limit) + 1,
+			Total:      total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// UpdatePenugasan replaces the profile, scope and date range of an assignment
+func (s *JadwalAbsensiServiceImpl) UpdatePenugasan(req *dtos.PenugasanJadwalAbsensiUpdateRequest, actor utils.Principal) (*dtos.PenugasanJadwalAbsensiResponse, error) {
+	existing, err := s.repository.GetPenugasanByID(req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyPenugasanRequest(existing, &req.PenugasanJadwalAbsensiRequest); err != nil {
+		return nil, err
+	}
+
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
+
+	if err := s.repository.UpdatePenugasan(existing); err != nil {
+		return nil, err
+	}
+
+	return s.getPenugasanByID(existing.ID)
+}
+
+// DeletePenugasan deletes an assignment
+func (s *JadwalAbsensiServiceImpl) DeletePenugasan(id uint) error {
+	return s.repository.DeletePenugasan(id)
+}
+
+// ResolveJadwal returns the schedule of a student on a date. Date-ranged assignments win over
+// regular ones; within each, rombel wins over kelas and kelas over all students. Without any
+// assignment the fallback (or the stored konfigurasi absensi) applies.
+func (s *JadwalAbsensiServiceImpl) ResolveJadwal(pesertaDidikID uint, tanggal time.Time, fallback *models.KonfigurasiAbsensi) (*JadwalAbsensiEfektif, error) {
+	jadwal := &JadwalAbsensiEfektif{}
+
+	var kelasID, rombelID *uint
+	pesertaDidikRombel, err := s.repository.GetRombelAktifPesertaDidik(pesertaDidikID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		rombelID = &pesertaDidikRombel.RombelID
+		if pesertaDidikRombel.Rombel != nil {
+			jadwal.Rombel = pesertaDidikRombel.Rombel
+			if pesertaDidikRombel.Rombel.KelasID != 0 {
+				kelasID = &pesertaDidikRombel.Rombel.KelasID
+			}
+		}
+	}
+
+	candidates, err := s.repository.GetPenugasanBerlaku(kelasID, rombelID, tanggal)
+	if err != nil {
+		return nil, err
+	}
+
+	var terpilih *models.PenugasanJadwalAbsensi
+	for i := range candidates {
+		if terpilih == nil || penugasanLebihSpesifik(&candidates[i], terpilih) {
+			terpilih = &candidates[i]
+		}
+	}
+
+	if terpilih == nil {
+		if fallback == nil {
+			if fallback, err = s.repository.GetKonfigurasiAbsensi(); err != nil {
+				return nil, errors.New("konfigurasi absensi belum diatur")
+			}
+		}
+		jadwal.JamDatangMulai = fallback.JamDatangMulai
+		jadwal.JamMaxDatang = fallback.JamMaxDatang
+		jadwal.JamDatangSelesai = fallback.JamDatangSelesai
+		jadwal.JamPulangMulai = fallback.JamPulangMulai
+		jadwal.JamPulangSelesai = fallback.JamPulangSelesai
+		return jadwal, nil
+	}
+
+	profil := terpilih.Profil
+	jadwal.Penugasan = terpilih
+	jadwal.JamDatangMulai = profil.JamDatangMulai
+	jadwal.JamMaxDatang = profil.JamMaxDatang
+	jadwal.JamDatangSelesai = profil.JamDatangSelesai
+	jadwal.JamPulangMulai = profil.JamPulangMulai
+	jadwal.JamPulangSelesai = profil.JamPulangSelesai
+
+	hari := isoWeekday(tanggal)
+	for _, override := range profil.Hari {
+		if override.Hari != hari {
+			continue
+		}
+		jadwal.OverrideHari = true
+		applyJamOverride(&jadwal.JamDatangMulai, override.JamDatangMulai)
+		applyJamOverride(&jadwal.JamMaxDatang, override.JamMaxDatang)
+		applyJamOverride(&jadwal.JamDatangSelesai, override.JamDatangSelesai)
+		applyJamOverride(&jadwal.JamPulangMulai, override.JamPulangMulai)
+		applyJamOverride(&jadwal.JamPulangSelesai, override.JamPulangSelesai)
+	}
+
+	return jadwal, nil
+}
+
+// PreviewJadwal shows which schedule a student scans against on a date and why
+func (s *JadwalAbsensiServiceImpl) PreviewJadwal(req *dtos.JadwalAbsensiPreviewRequest) (*dtos.JadwalAbsensiPreviewResponse, error) {
+	tanggal, err := parseTanggalKalender(req.Tanggal)
+	if err != nil {
+		return nil, errors.New("format tanggal tidak valid, gunakan YYYY-MM-DD")
+	}
+	tanggal = time.Date(tanggal.Year(), tanggal.Month(), tanggal.Day(), 0, 0, 0, 0, utils.JakartaLocation())
+
+	pesertaDidik, err := s.pesertaDidikRepo.GetByID(req.PesertaDidikID)
+	if err != nil {
+		return nil, errors.New("peserta didik tidak ditemukan")
+	}
+
+	hariSekolah, keterangan, err := s.kalenderService.CekHariSekolah(tanggal)
+	if err != nil {
+		return nil, err
+	}
+
+	jadwal, err := s.ResolveJadwal(pesertaDidik.ID, tanggal, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dtos.JadwalAbsensiPreviewResponse{
+		PesertaDidikID:   pesertaDidik.ID,
+		NamaSiswa:        pesertaDidik.Nama,
+		Tanggal:          tanggal.Format("2006-01-02"),
+		Hari:             isoWeekday(tanggal),
+		HariSekolah:      hariSekolah,
+		KeteranganHari:   keterangan,
+		Sumber:           "konfigurasi_absensi",
+		OverrideHari:     jadwal.OverrideHari,
+		JamDatangMulai:   jadwal.JamDatangMulai,
+		JamMaxDatang:     jadwal.JamMaxDatang,
+		JamDatangSelesai: jadwal.JamDatangSelesai,
+		JamPulangMulai:   jadwal.JamPulangMulai,
+		JamPulangSelesai: jadwal.JamPulangSelesai,
+	}
+	if jadwal.Rombel != nil {
+		response.RombelID = &jadwal.Rombel.ID
+		response.Rombel = jadwal.Rombel.Name
+	}
+	if jadwal.Penugasan != nil {
+		response.Sumber = "penugasan"
+		response.PenugasanID = &jadwal.Penugasan.ID
+		response.ProfilID = &jadwal.Penugasan.ProfilJadwalAbsensiID
+		response.ProfilNama = jadwal.Penugasan.Profil.Nama
+		response.Cakupan = cakupanPenugasan(jadwal.Penugasan)
+		response.JadwalKhusus = jadwal.Penugasan.TanggalMulai != nil
+	}
+
+	return response, nil
+}
+
+// applyPenugasanRequest validates an assignment request and copies it onto the model
+func (s *JadwalAbsensiServiceImpl) applyPenugasanRequest(data *models.PenugasanJadwalAbsensi, req *dtos.PenugasanJadwalAbsensiRequest) error {
+	if req.KelasID != nil && req.RombelID != nil {
+		return errors.New("pilih kelas_id atau rombel_id, tidak keduanya")
+	}
+
+	tanggalMulai, err := parseTanggalOpsional(req.TanggalMulai)
+	if err != nil {
+		return errors.New("format tanggal_mulai tidak valid, gunakan YYYY-MM-DD")
+	}
+	tanggalSelesai, err := parseTanggalOpsional(req.TanggalSelesai)
+	if err != nil {
+		return errors.New("format tanggal_selesai tidak valid, gunakan YYYY-MM-DD")
+	}
+	if (tanggalMulai == nil) != (tanggalSelesai == nil) {
+		return errors.New("tanggal_mulai dan tanggal_selesai harus diisi bersamaan untuk jadwal khusus")
+	}
+	if tanggalMulai != nil && tanggalSelesai.Before(*tanggalMulai) {
+		return errors.New("tanggal_selesai tidak boleh sebelum tanggal_mulai")
+	}
+
+	if _, err := s.repository.GetProfilByID(req.ProfilJadwalAbsensiID); err != nil {
+		return errors.New("profil jadwal absensi tidak ditemukan")
+	}
+
+	// Each scope has at most one regular schedule; special schedules may overlap it
+	if tanggalMulai == nil {
+		exists, err := s.repository.ExistsPenugasanReguler(req.KelasID, req.RombelID, data.ID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return errors.New("cakupan ini sudah memiliki penugasan jadwal reguler")
+		}
+	}
+
+	data.ProfilJadwalAbsensiID = req.ProfilJadwalAbsensiID
+	data.KelasID = req.KelasID
+	data.RombelID = req.RombelID
+	data.TanggalMulai = tanggalMulai
+	data.TanggalSelesai = tanggalSelesai
+	data.Keterangan = req.Keterangan
+	return nil
+}
+
+// getPenugasanByID retrieves an assignment by ID as response
+func (s *JadwalAbsensiServiceImpl) getPenugasanByID(id uint) (*dtos.PenugasanJadwalAbsensiResponse, error) {
+	data, err := s.repository.GetPenugasanByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.mapPenugasanToResponse(data), nil
+}
+
+// mapProfilToResponse maps ProfilJadwalAbsensi model to response DTO
+func (s *JadwalAbsensiServiceImpl) mapProfilToResponse(data *models.ProfilJadwalAbsensi) *dtos.ProfilJadwalAbsensiResponse {
+	hari := make([]dtos.ProfilJadwalAbsensiHariResponse, len(data.Hari))
+	for i, item := range data.Hari {
+		hari[i] = dtos.ProfilJadwalAbsensiHariResponse{
+			Hari:             item.Hari,
+			JamDatangMulai:   item.JamDatangMulai,
+			JamMaxDatang:     item.JamMaxDatang,
+			JamDatangSelesai: item.JamDatangSelesai,
+			JamPulangMulai:   item.JamPulangMulai,
+			JamPulangSelesai: item.JamPulangSelesai,
+		}
+	}
+
+	return &dtos.ProfilJadwalAbsensiResponse{
+		ID:               data.ID,
+		Nama:             data.Nama,
+		Keterangan:       data.Keterangan,
+		JamDatangMulai:   data.JamDatangMulai,
+		JamMaxDatang:     data.JamMaxDatang,
+		JamDatangSelesai: data.JamDatangSelesai,
+		JamPulangMulai:   data.JamPulangMulai,
+		JamPulangSelesai: data.JamPulangSelesai,
+		Status:           data.Status,
+		Hari:             hari,
+		CreatedAt:        data.CreatedAt,
+		UpdatedAt:        data.UpdatedAt,
+		CreatedByID:      data.CreatedByID,
+		CreatedByType:    data.CreatedByType,
+		UpdatedByID:      data.UpdatedByID,
+		UpdatedByType:    data.UpdatedByType,
+	}
+}
+
+// mapPenugasanToResponse maps PenugasanJadwalAbsensi model to response DTO
+func (s *JadwalAbsensiServiceImpl) mapPenugasanToResponse(data *models.PenugasanJadwalAbsensi) *dtos.PenugasanJadwalAbsensiResponse {
+	response := &dtos.PenugasanJadwalAbsensiResponse{
+		ID:                    data.ID,
+		ProfilJadwalAbsensiID: data.ProfilJadwalAbsensiID,
+		Cakupan:               cakupanPenugasan(data),
+		KelasID:               data.KelasID,
+		RombelID:              data.RombelID,
+		Keterangan:            data.Keterangan,
+		CreatedAt:             data.CreatedAt,
+		UpdatedAt:             data.UpdatedAt,
+	}
+	if data.Profil != nil {
+		response.ProfilNama = data.Profil.Nama
+	}
+	if data.Kelas != nil {
+		response.Kelas = data.Kelas.Name
+	}
+	if data.Rombel != nil {
+		response.Rombel = data.Rombel.Name
+	}
+	if data.TanggalMulai != nil && data.TanggalSelesai != nil {
+		mulai := data.TanggalMulai.Format("2006-01-02")
+		selesai := data.TanggalSelesai.Format("2006-01-02")
+		response.TanggalMulai = &mulai
+		response.TanggalSelesai = &selesai
+	}
+	return response
+}
+
+// mapHariRequestToModel maps a weekday override request to the model
+func mapHariRequestToModel(req dtos.ProfilJadwalAbsensiHariRequest) models.ProfilJadwalAbsensiHari {
+	return models.ProfilJadwalAbsensiHari{
+		Hari:             req.Hari,
+		JamDatangMulai:   emptyToNil(req.JamDatangMulai),
+		JamMaxDatang:     emptyToNil(req.JamMaxDatang),
+		JamDatangSelesai: emptyToNil(req.JamDatangSelesai),
+		JamPulangMulai:   emptyToNil(req.JamPulangMulai),
+		JamPulangSelesai: emptyToNil(req.JamPulangSelesai),
+	}
+}
+
+// validateProfilJadwal normalizes the profile times to HH:MM:SS and checks that the profile,
+// and the profile with each weekday override applied, keeps the windows in order
+func validateProfilJadwal(data *models.ProfilJadwalAbsensi) error {
+	base := []*string{&data.JamDatangMulai, &data.JamMaxDatang, &data.JamDatangSelesai, &data.JamPulangMulai, &data.JamPulangSelesai}
+	for _, jam := range base {
+		if err := normalizeJamJadwal(jam); err != nil {
+			return err
+		}
+	}
+	if err := validateUrutanJam(data.JamDatangMulai, data.JamMaxDatang, data.JamDatangSelesai, data.JamPulangMulai, data.JamPulangSelesai); err != nil {
+		return err
+	}
+
+	seen := make(map[int]bool)
+	for i := range data.Hari {
+		hari := &data.Hari[i]
+		if hari.Hari < 1 || hari.Hari > 7 {
+			return errors.New("hari harus berisi angka 1 (Senin) sampai 7 (Minggu)")
+		}
+		if seen[hari.Hari] {
+			return fmt.Errorf("hari %d diatur lebih dari sekali", hari.Hari)
+		}
+		seen[hari.Hari] = true
+
+		merged := make([]string, len(base))
+		for j, override := range []*string{hari.JamDatangMulai, hari.JamMaxDatang, hari.JamDatangSelesai, hari.JamPulangMulai, hari.JamPulangSelesai} {
+			merged[j] = *base[j]
+			if override == nil {
+				continue
+			}
+			if err := normalizeJamJadwal(override); err != nil {
+				return err
+			}
+			merged[j] = *override
+		}
+		if err := validateUrutanJam(merged[0], merged[1], merged[2], merged[3], merged[4]); err != nil {
+			return fmt.Errorf("hari %d: %v", hari.Hari, err)
+		}
+	}
+	return nil
+}
+
+// normalizeJamJadwal rewrites a HH:MM or HH:MM:SS time in place as HH:MM:SS
+func normalizeJamJadwal(jam *string) error {
+	value := strings.TrimSpace(*jam)
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			*jam = parsed.Format("15:04:05")
+			return nil
+		}
+	}
+	return fmt.Errorf("format jam %q tidak valid, gunakan HH:MM atau HH:MM:SS", *jam)
+}
+
+// validateUrutanJam checks datang_mulai <= max_datang <= datang_selesai <= pulang_mulai <= pulang_selesai
+func validateUrutanJam(datangMulai, maxDatang, datangSelesai, pulangMulai, pulangSelesai string) error {
+	if datangMulai > maxDatang || maxDatang > datangSelesai {
+		return errors.New("jam_max_datang harus berada di antara jam_datang_mulai dan jam_datang_selesai")
+	}
+	if datangSelesai > pulangMulai {
+		return errors.New("jam_pulang_mulai tidak boleh sebelum jam_datang_selesai")
+	}
+	if pulangMulai > pulangSelesai {
+		return errors.New("jam_pulang_selesai tidak boleh sebelum jam_pulang_mulai")
+	}
+	return nil
+}
+
+// parseTanggalOpsional parses an optional YYYY-MM-DD date; nil or empty stays nil
+func parseTanggalOpsional(value *string) (*time.Time, error) {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return nil, nil
+	}
+	tanggal, err := parseTanggalKalender(*value)
+	if err != nil {
+		return nil, err
+	}
+	return &tanggal, nil
+}
+
+// emptyToNil turns an empty optional string into nil
+func emptyToNil(value *string) *string {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return nil
+	}
+	return value
+}
+
+// applyJamOverride replaces jam with the override when it is set
+func applyJamOverride(jam *string, override *string) {
+	if override != nil {
+		*jam = *override
+	}
+}
+
+// cakupanPenugasan names the scope of an assignment: semua, kelas or rombel
+func cakupanPenugasan(data *models.PenugasanJadwalAbsensi) string {
+	switch {
+	case data.RombelID != nil:
+		return "rombel"
+	case data.KelasID != nil:
+		return "kelas"
+	default:
+		return "semua"
+	}
+}
+
+// penugasanLebihSpesifik reports whether a takes precedence over b: date-ranged first, then
+// rombel over kelas over all students, then the most recently started and most recently created
+func penugasanLebihSpesifik(a, b *models.PenugasanJadwalAbsensi) bool {
+	if (a.TanggalMulai != nil) != (b.TanggalMulai != nil) {
+		return a.TanggalMulai != nil
+	}
+	rank := map[string]int{"semua": 0, "kelas": 1, "rombel": 2}
+	if rankA, rankB := rank[cakupanPenugasan(a)], rank[cakupanPenugasan(b)]; rankA != rankB {
+		return rankA > rankB
+	}
+	if a.TanggalMulai != nil && !a.TanggalMulai.Equal(*b.TanggalMulai) {
+		return a.TanggalMulai.After(*b.TanggalMulai)
+	}
+	return a.ID > b.ID
+}
+
+// isoWeekday returns the ISO weekday of a date, 1 = Senin ... 7 = Minggu
+func isoWeekday(tanggal time.Time) int {
+	if tanggal.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(tanggal.Weekday())
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fakeJadwalAbsensiRepository holds the active rombel of one student and the stored assignments
+type fakeJadwalAbsensiRepository struct {
+	repositories.JadwalAbsensiRepository
+	rombel    *models.PesertaDidikRombel
+	penugasan []models.PenugasanJadwalAbsensi
+}
+
+func (r *fakeJadwalAbsensiRepository) GetRombelAktifPesertaDidik(pesertaDidikID uint) (*models.PesertaDidikRombel, error) {
+	if r.rombel == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.rombel, nil
+}
+
+// GetPenugasanBerlaku filters by scope and date range like the SQL query does
+func (r *fakeJadwalAbsensiRepository) GetPenugasanBerlaku(kelasID, rombelID *uint, tanggal time.Time) ([]models.PenugasanJadwalAbsensi, error) {
+	var data []models.PenugasanJadwalAbsensi
+	for _, penugasan := range r.penugasan {
+		semua := penugasan.KelasID == nil && penugasan.RombelID == nil
+		kelas := penugasan.KelasID != nil && kelasID != nil && *penugasan.KelasID == *kelasID
+		rombel := penugasan.RombelID != nil && rombelID != nil && *penugasan.RombelID == *rombelID
+		if !semua && !kelas && !rombel {
+			continue
+		}
+		if penugasan.TanggalMulai != nil && (tanggal.Before(*penugasan.TanggalMulai) || tanggal.After(*penugasan.TanggalSelesai)) {
+			continue
+		}
+		data = append(data, penugasan)
+	}
+	return data, nil
+}
+
+// testProfil returns a profile whose windows start at the given datang hour
+func testProfil(nama string, datangMulai string, hari ...models.ProfilJadwalAbsensiHari) *models.ProfilJadwalAbsensi {
+	return &models.ProfilJadwalAbsensi{
+		Nama:             nama,
+		JamDatangMulai:   datangMulai,
+		JamMaxDatang:     "07:00:00",
+		JamDatangSelesai: "09:00:00",
+		JamPulangMulai:   "13:00:00",
+		JamPulangSelesai: "16:00:00",
+		Hari:             hari,
+	}
+}
+
+func TestResolveJadwal(t *testing.T) {
+	kelasID, rombelID, kelasLain := uint(2), uint(20), uint(3)
+	kamis := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	jumat := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	mulai, selesai := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	pulangJumat := "11:00:00"
+
+	semua := models.PenugasanJadwalAbsensi{ID: 1, Profil: testProfil("Semua", "06:01:00")}
+	kelas := models.PenugasanJadwalAbsensi{ID: 2, KelasID: &kelasID, Profil: testProfil("Kelas", "06:02:00")}
+	rombel := models.PenugasanJadwalAbsensi{ID: 3, RombelID: &rombelID, Profil: testProfil("Rombel", "06:03:00",
+		models.ProfilJadwalAbsensiHari{Hari: 5, JamPulangMulai: &pulangJumat})}
+	khusus := models.PenugasanJadwalAbsensi{ID: 4, TanggalMulai: &mulai, TanggalSelesai: &selesai, Profil: testProfil("Khusus", "06:04:00")}
+	lainKelas := models.PenugasanJadwalAbsensi{ID: 5, KelasID: &kelasLain, Profil: testProfil("Kelas Lain", "06:05:00")}
+	fallback := &models.KonfigurasiAbsensi{JamDatangMulai: "06:00:00", JamMaxDatang: "07:00:00", JamDatangSelesai: "09:00:00", JamPulangMulai: "13:00:00", JamPulangSelesai: "16:00:00"}
+
+	tests := []struct {
+		name            string
+		tanpaRombel     bool
+		penugasan       []models.PenugasanJadwalAbsensi
+		tanggal         time.Time
+		wantDatangMulai string
+		wantPulangMulai string
+	}{
+		{"no assignment falls back to konfigurasi", false, nil, kamis, "06:00:00", "13:00:00"},
+		{"assignment of another kelas is ignored", false, []models.PenugasanJadwalAbsensi{lainKelas}, kamis, "06:00:00", "13:00:00"},
+		{"all students", false, []models.PenugasanJadwalAbsensi{semua, lainKelas}, kamis, "06:01:00", "13:00:00"},
+		{"kelas wins over all students", false, []models.PenugasanJadwalAbsensi{semua, kelas}, kamis, "06:02:00", "13:00:00"},
+		{"rombel wins over kelas", false, []models.PenugasanJadwalAbsensi{kelas, rombel, semua}, kamis, "06:03:00", "13:00:00"},
+		{"weekday override of the profile", false, []models.PenugasanJadwalAbsensi{rombel}, jumat, "06:03:00", "11:00:00"},
+		{"date-ranged wins over regular rombel", false, []models.PenugasanJadwalAbsensi{rombel, khusus}, kamis, "06:04:00", "13:00:00"},
+		{"date-ranged ends after its range", false, []models.PenugasanJadwalAbsensi{rombel, khusus}, jumat, "06:03:00", "11:00:00"},
+		{"student without rombel gets all students", true, []models.PenugasanJadwalAbsensi{semua, kelas, rombel}, kamis, "06:01:00", "13:00:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeJadwalAbsensiRepository{penugasan: tt.penugasan}
+			if !tt.tanpaRombel {
+				repository.rombel = &models.PesertaDidikRombel{RombelID: rombelID, Rombel: &models.Rombel{ID: rombelID, KelasID: kelasID}}
+			}
+			service := &JadwalAbsensiServiceImpl{repository: repository}
+
+			jadwal, err := service.ResolveJadwal(1, tt.tanggal, fallback)
+			if err != nil {
+				t.Fatalf("ResolveJadwal() error = %v", err)
+			}
+			if jadwal.JamDatangMulai != tt.wantDatangMulai || jadwal.JamPulangMulai != tt.wantPulangMulai {
+				t.Errorf("datang/pulang mulai = %s/%s, want %s/%s", jadwal.JamDatangMulai, jadwal.JamPulangMulai, tt.wantDatangMulai, tt.wantPulangMulai)
+			}
+			if overrideHari := tt.wantPulangMulai == pulangJumat; jadwal.OverrideHari != overrideHari {
+				t.Errorf("OverrideHari = %v, want %v", jadwal.OverrideHari, overrideHari)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"mime/multipart"
 	"pintu-backend/src/dtos"
@@ -11,58 +12,41 @@ import (
 
 // JumbotronService handles business logic for Jumbotron
 type JumbotronService interface {
-	Create(file *multipart.FileHeader, req *dtos.JumbotronCreateRequest, userID uint) (*dtos.JumbotronResponse, error)
+	Create(ctx context.Context, file *multipart.FileHeader, req *dtos.JumbotronCreateRequest, actor utils.Principal) (*dtos.JumbotronResponse, error)
 	GetByID(id uint) (*dtos.JumbotronResponse, error)
 	GetAll(limit int, offset int) (*dtos.JumbotronListResponse, error)
 	GetActiveLatest(limit int) ([]dtos.JumbotronPublicResponse, error)
-	Update(id uint, req *dtos.JumbotronUpdateRequest, userID uint) (*dtos.JumbotronResponse, error)
-	UpdateWithFile(id uint, file *multipart.FileHeader, status string, userID uint) (*dtos.JumbotronResponse, error)
+	Update(id uint, req *dtos.JumbotronUpdateRequest, actor utils.Principal) (*dtos.JumbotronResponse, error)
+	UpdateWithFile(ctx context.Context, id uint, file *multipart.FileHeader, status string, actor utils.Principal) (*dtos.JumbotronResponse, error)
 	Delete(id uint) error
 }
 
 type JumbotronServiceImpl struct {
 	repository repositories.JumbotronRepository
-	r2Storage  *utils.R2Storage
+	storage    utils.Storage
 }
 
 // NewJumbotronService creates a new Jumbotron service
-func NewJumbotronService(repository repositories.JumbotronRepository, r2Storage *utils.R2Storage) JumbotronService {
+func NewJumbotronService(repository repositories.JumbotronRepository, storage utils.Storage) JumbotronService {
 	return &JumbotronServiceImpl{
 		repository: repository,
-		r2Storage:  r2Storage,
+		storage:    storage,
 	}
 }
 
 // Create creates a new Jumbotron with file upload to R2
-func (s *JumbotronServiceImpl) Create(file *multipart.FileHeader, req *dtos.JumbotronCreateRequest, userID uint) (*dtos.JumbotronResponse, error) {
+func (s *JumbotronServiceImpl) Create(ctx context.Context, file *multipart.FileHeader, req *dtos.JumbotronCreateRequest, actor utils.Principal) (*dtos.JumbotronResponse, error) {
 	// Validate file
 	if file == nil {
 		return nil, errors.New("file is required")
 	}
 
-	// Validate file size (max 5MB)
-	const maxFileSize = 5 * 1024 * 1024 // 5MB
-	if file.Size > maxFileSize {
-		return nil, errors.New("file size must not exceed 5MB")
-	}
-
-	// Validate file type (only images)
-	allowedTypes := map[string]bool{
-		"image/jpeg": true,
-		"image/png":  true,
-		"image/gif":  true,
-		"image/webp": true,
-	}
-	contentType := file.Header.Get("Content-Type")
-	if !allowedTypes[contentType] {
-		return nil, errors.New("only image files are allowed (jpeg, png, gif, webp)")
-	}
-
-	// Upload file to R2
-	fileKey, err := s.r2Storage.UploadFile(file, "jumbotron")
+	// Validate the file from its content and upload to R2
+	stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryPublikasi, "jumbotron")
 	if err != nil {
 		return nil, err
 	}
+	fileKey := stored.Key
 
 	// Set default status
 	status := req.Status
@@ -72,14 +56,16 @@ func (s *JumbotronServiceImpl) Create(file *multipart.FileHeader, req *dtos.Jumb
 
 	// Create jumbotron record
 	data := &models.Jumbotron{
-		File:        fileKey,
-		Status:      status,
-		CreatedByID: &userID,
+		File:          fileKey,
+		FileVariants:  fileVariantsJSON(stored),
+		Status:        status,
+		CreatedByID:   &actor.ID,
+		CreatedByType: actor.TypePtr(),
 	}
 
 	if err := s.repository.Create(data); err != nil {
 		// If database save fails, delete the uploaded file
-		_ = s.r2Storage.DeleteFile(fileKey)
+		utils.DeleteStoredUpload(s.storage, stored)
 		return nil, err
 	}
 
@@ -128,7 +114,7 @@ func (s *JumbotronServiceImpl) GetAll(limit int, offset int) (*dtos.JumbotronLis
 }
 
 // Update updates Jumbotron
-func (s *JumbotronServiceImpl) Update(id uint, req *dtos.JumbotronUpdateRequest, userID uint) (*dtos.JumbotronResponse, error) {
+func (s *JumbotronServiceImpl) Update(id uint, req *dtos.JumbotronUpdateRequest, actor utils.Principal) (*dtos.JumbotronResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(id)
 	if err != nil {
@@ -140,7 +126,8 @@ func (s *JumbotronServiceImpl) Update(id uint, req *dtos.JumbotronUpdateRequest,
 		existing.Status = *req.Status
 	}
 
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	if err := s.repository.Update(existing); err != nil {
 		return nil, err
@@ -157,8 +144,9 @@ func (s *JumbotronServiceImpl) Delete(id uint) error {
 		return err
 	}
 
-	// Delete file from R2
-	if err := s.r2Storage.DeleteFile(existing.File); err != nil {
+	// Delete file and its variants from R2
+	deleteFileVariants(s.storage, existing.File, parseFileVariants(existing.FileVariants))
+	if err := s.storage.DeleteFile(existing.File); err != nil {
 		// Log error but continue with database deletion
 		// In production, you might want to handle this differently
 	}
@@ -168,7 +156,7 @@ func (s *JumbotronServiceImpl) Delete(id uint) error {
 }
 
 // UpdateWithFile updates Jumbotron with optional file upload
-func (s *JumbotronServiceImpl) UpdateWithFile(id uint, file *multipart.FileHeader, status string, userID uint) (*dtos.JumbotronResponse, error) {
+func (s *JumbotronServiceImpl) UpdateWithFile(ctx context.Context, id uint, file *multipart.FileHeader, status string, actor utils.Principal) (*dtos.JumbotronResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(id)
 	if err != nil {
@@ -176,36 +164,24 @@ func (s *JumbotronServiceImpl) UpdateWithFile(id uint, file *multipart.FileHeade
 	}
 
 	oldFile := existing.File
+	oldVariants := parseFileVariants(existing.FileVariants)
 
 	// If file provided, validate and upload
 	if file != nil {
-		// Validate file
-		if file.Size > 5*1024*1024 { // 5MB
-			return nil, errors.New("file size must not exceed 5MB")
-		}
-
-		allowedTypes := map[string]bool{
-			"image/jpeg": true,
-			"image/png":  true,
-			"image/gif":  true,
-			"image/webp": true,
-		}
-		contentType := file.Header.Get("Content-Type")
-		if !allowedTypes[contentType] {
-			return nil, errors.New("only image files are allowed (jpeg, png, gif, webp)")
-		}
-
-		// Upload new file
-		newFileKey, err := s.r2Storage.UploadFile(file, "jumbotron")
+		// Validate the file from its content and upload to R2
+		stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryPublikasi, "jumbotron")
 		if err != nil {
 			return nil, err
 		}
+		newFileKey := stored.Key
 
-		// Delete old file from R2
-		_ = s.r2Storage.DeleteFile(oldFile)
+		// Delete old file and its variants from R2
+		_ = s.storage.DeleteFile(oldFile)
+		deleteFileVariants(s.storage, oldFile, oldVariants)
 
 		// Update file in model
 		existing.File = newFileKey
+		existing.FileVariants = fileVariantsJSON(stored)
 	}
 
 	// Update status if provided
@@ -213,12 +189,14 @@ func (s *JumbotronServiceImpl) UpdateWithFile(id uint, file *multipart.FileHeade
 		existing.Status = status
 	}
 
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	if err := s.repository.Update(existing); err != nil {
 		// If DB save fails, delete the uploaded file
 		if file != nil {
-			_ = s.r2Storage.DeleteFile(existing.File)
+			_ = s.storage.DeleteFile(existing.File)
+			deleteFileVariants(s.storage, existing.File, parseFileVariants(existing.FileVariants))
 		}
 		return nil, err
 	}
@@ -242,8 +220,9 @@ func (s *JumbotronServiceImpl) GetActiveLatest(limit int) ([]dtos.JumbotronPubli
 	responses := make([]dtos.JumbotronPublicResponse, len(data))
 	for i, item := range data {
 		responses[i] = dtos.JumbotronPublicResponse{
-			File:   s.r2Storage.GetPublicURL(item.File),
-			Status: item.Status,
+			File:         s.storage.GetPublicURL(item.File),
+			FileVariants: fileVariantDTOs(s.storage, parseFileVariants(item.FileVariants)),
+			Status:       item.Status,
 		}
 	}
 
@@ -253,15 +232,18 @@ func (s *JumbotronServiceImpl) GetActiveLatest(limit int) ([]dtos.JumbotronPubli
 // mapToResponse maps model to DTO response
 func (s *JumbotronServiceImpl) mapToResponse(data *models.Jumbotron) *dtos.JumbotronResponse {
 	// Get public URL for the file
-	publicURL := s.r2Storage.GetPublicURL(data.File)
+	publicURL := s.storage.GetPublicURL(data.File)
 
 	return &dtos.JumbotronResponse{
-		ID:          data.ID,
-		File:        publicURL,
-		Status:      data.Status,
-		CreatedAt:   data.CreatedAt,
-		UpdatedAt:   data.UpdatedAt,
-		CreatedByID: data.CreatedByID,
-		UpdatedByID: data.UpdatedByID,
+		ID:            data.ID,
+		File:          publicURL,
+		FileVariants:  fileVariantDTOs(s.storage, parseFileVariants(data.FileVariants)),
+		Status:        data.Status,
+		CreatedAt:     data.CreatedAt,
+		UpdatedAt:     data.UpdatedAt,
+		CreatedByID:   data.CreatedByID,
+		CreatedByType: data.CreatedByType,
+		UpdatedByID:   data.UpdatedByID,
+		UpdatedByType: data.UpdatedByType,
 	}
 }
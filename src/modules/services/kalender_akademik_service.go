@@ -0,0 +1,658 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"time"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// defaultHariSekolah is used when konfigurasi absensi has not been set: Senin - Jumat
+const defaultHariSekolah = "1,2,3,4,5"
+
+// namaHari maps weekdays to Indonesian day names
+var namaHari = map[time.Weekday]string{
+	time.Monday:    "Senin",
+	time.Tuesday:   "Selasa",
+	time.Wednesday: "Rabu",
+	time.Thursday:  "Kamis",
+	time.Friday:    "Jumat",
+	time.Saturday:  "Sabtu",
+	time.Sunday:    "Minggu",
+}
+
+// KalenderSekolah answers whether a date is an effective school day. A date is not a school day when
+// its weekday is not in konfigurasi hari_sekolah, when it falls inside a libur entry, or when it lies
+// outside every semester set on tahun pelajaran (libur semester/kenaikan kelas). Dates are only checked
+// against semesters once at least one tahun pelajaran has its semester dates set.
+type KalenderSekolah struct {
+	hariSekolah map[time.Weekday]bool
+	libur       []models.KalenderAkademik
+	semesters   DaftarPeriodeAkademik
+}
+
+// IsHariSekolah reports whether the date is a school day and, if not, why
+func (k *KalenderSekolah) IsHariSekolah(tanggal time.Time) (bool, string) {
+	key := tanggal.Format("2006-01-02")
+
+	if !k.hariSekolah[tanggal.Weekday()] {
+		return false, fmt.Sprintf("hari %s bukan hari sekolah", namaHari[tanggal.Weekday()])
+	}
+
+	for _, libur := range k.libur {
+		if key >= libur.TanggalMulai.Format("2006-01-02") && key <= libur.TanggalSelesai.Format("2006-01-02") {
+			return false, libur.Nama
+		}
+	}
+
+	if len(k.semesters) > 0 {
+		if _, err := k.semesters.Resolve(tanggal); err != nil {
+			return false, "di luar periode semester"
+		}
+	}
+
+	return true, ""
+}
+
+// HariEfektif returns the school days between both dates, inclusive
+func (k *KalenderSekolah) HariEfektif(tanggalMulai, tanggalSelesai time.Time) []time.Time {
+	var days []time.Time
+	for d := tanggalMulai; !d.After(tanggalSelesai); d = d.AddDate(0, 0, 1) {
+		if ok, _ := k.IsHariSekolah(d); ok {
+			days = append(days, d)
+		}
+	}
+	return days
+}
+
+// KalenderAkademikService handles business logic for KalenderAkademik
+type KalenderAkademikService interface {
+	Create(req *dtos.KalenderAkademikCreateRequest, actor utils.Principal) (*dtos.KalenderAkademikResponse, error)
+	GetByID(id uint) (*dtos.KalenderAkademikResponse, error)
+	GetAllWithFilter(params repositories.GetKalenderAkademikParams) (*dtos.KalenderAkademikListWithPaginationResponse, error)
+	Update(req *dtos.KalenderAkademikUpdateRequest, actor utils.Principal) (*dtos.KalenderAkademikResponse, error)
+	Delete(id uint) error
+	DownloadTemplate() (*excelize.File, error)
+	ImportExcel(file multipart.File, tahunPelajaranID *uint, actor utils.Principal) (*dtos.KalenderAkademikImportResponse, error)
+	ImportICal(file multipart.File, tahunPelajaranID *uint, jenis string, actor utils.Principal) (*dtos.KalenderAkademikImportResponse, error)
+	GetHariEfektif(req *dtos.HariEfektifRequest) (*dtos.HariEfektifResponse, error)
+	LoadKalender(tanggalMulai, tanggalSelesai time.Time) (*KalenderSekolah, error)
+	CekHariSekolah(tanggal time.Time) (bool, string, error)
+}
+
+type KalenderAkademikServiceImpl struct {
+	repository            repositories.KalenderAkademikRepository
+	konfigurasiRepository repositories.KonfigurasiAbsensiRepository
+	periodeService        PeriodeAkademikService
+}
+
+// NewKalenderAkademikService creates a new KalenderAkademik service
+func NewKalenderAkademikService(repository repositories.KalenderAkademikRepository, konfigurasiRepository repositories.KonfigurasiAbsensiRepository, periodeService PeriodeAkademikService) KalenderAkademikService {
+	return &KalenderAkademikServiceImpl{
+		repository:            repository,
+		konfigurasiRepository: konfigurasiRepository,
+		periodeService:        periodeService,
+	}
+}
+
+// Create creates a new KalenderAkademik entry
+func (s *KalenderAkademikServiceImpl) Create(req *dtos.KalenderAkademikCreateRequest, actor utils.Principal) (*dtos.KalenderAkademikResponse, error) {
+	tanggalMulai, err := parseTanggalKalender(req.TanggalMulai)
+	if err != nil {
+		return nil, errors.New("format tanggal_mulai tidak valid, gunakan YYYY-MM-DD")
+	}
+	tanggalSelesai, err := parseTanggalKalender(req.TanggalSelesai)
+	if err != nil {
+		return nil, errors.New("format tanggal_selesai tidak valid, gunakan YYYY-MM-DD")
+	}
+
+	data := &models.KalenderAkademik{
+		TahunPelajaranID: req.TahunPelajaranID,
+		Jenis:            req.Jenis,
+		Nama:             req.Nama,
+		TanggalMulai:     tanggalMulai,
+		TanggalSelesai:   tanggalSelesai,
+		Semester:         req.Semester,
+		Libur:            defaultLibur(req.Jenis),
+		Keterangan:       req.Keterangan,
+		Sumber:           "manual",
+		CreatedByID:      &actor.ID,
+		CreatedByType:    actor.TypePtr(),
+	}
+	if req.Libur != nil {
+		data.Libur = *req.Libur
+	}
+
+	if err := validateKalenderAkademik(data); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.Create(data); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(data.ID)
+}
+
+// GetByID retrieves KalenderAkademik by ID
+func (s *KalenderAkademikServiceImpl) GetByID(id uint) (*dtos.KalenderAkademikResponse, error) {
+	data, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.mapToResponse(data), nil
+}
+
+// GetAllWithFilter retrieves KalenderAkademik with filters and pagination
+func (s *KalenderAkademikServiceImpl) GetAllWithFilter(params repositories.GetKalenderAkademikParams) (*dtos.KalenderAkademikListWithPaginationResponse, error) {
+	// Validate and set default limit and offset
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+	if params.Offset < 0 {
+		params.Offset = 0
+	}
+
+	data, total, err := s.repository.GetAllWithFilter(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map to response
+	responses := make([]dtos.KalenderAkademikResponse, len(data))
+	for i, item := range data {
+		responses[i] = *s.mapToResponse(&item)
+	}
+
+	totalPages := (int(total) + params.Limit - 1) / params.Limit
+
+	return &dtos.KalenderAkademikListWithPaginationResponse{
+		Data: responses,
+		Pagination: dtos.PaginationInfo{
+			Limit:      params.Limit,
+			Offset:     params.Offset,
+			Page:       (params.Offset / params.Limit) + 1,
+			Total:      total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// Update updates KalenderAkademik
+func (s *KalenderAkademikServiceImpl) Update(req *dtos.KalenderAkademikUpdateRequest, actor utils.Principal) (*dtos.KalenderAkademikResponse, error) {
+	existing, err := s.repository.GetByID(req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update fields if provided
+	if req.TahunPelajaranID != nil {
+		existing.TahunPelajaranID = req.TahunPelajaranID
+	}
+	if req.Jenis != nil {
+		existing.Jenis = *req.Jenis
+	}
+	if req.Nama != nil {
+		existing.Nama = *req.Nama
+	}
+	if req.TanggalMulai != nil {
+		tanggal, err := parseTanggalKalender(*req.TanggalMulai)
+		if err != nil {
+			return nil, errors.New("format tanggal_mulai tidak valid, gunakan YYYY-MM-DD")
+		}
+		existing.TanggalMulai = tanggal
+	}
+	if req.TanggalSelesai != nil {
+		tanggal, err := parseTanggalKalender(*req.TanggalSelesai)
+		if err != nil {
+			return nil, errors.New("format tanggal_selesai tidak valid, gunakan YYYY-MM-DD")
+		}
+		existing.TanggalSelesai = tanggal
+	}
+	if req.Semester != nil {
+		existing.Semester = req.Semester
+	}
+	if req.Libur != nil {
+		existing.Libur = *req.Libur
+	}
+	if req.Keterangan != nil {
+		existing.Keterangan = req.Keterangan
+	}
+
+	if err := validateKalenderAkademik(existing); err != nil {
+		return nil, err
+	}
+
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
+
+	if err := s.repository.Update(existing); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(existing.ID)
+}
+
+// Delete deletes KalenderAkademik by ID
+func (s *KalenderAkademikServiceImpl) Delete(id uint) error {
+	return s.repository.Delete(id)
+}
+
+// DownloadTemplate generates an Excel template for KalenderAkademik import
+func (s *KalenderAkademikServiceImpl) DownloadTemplate() (*excelize.File, error) {
+	f := excelize.NewFile()
+	sheetName := "Sheet1"
+
+	headers := []string{"nama", "jenis", "tanggal_mulai", "tanggal_selesai", "semester", "libur", "keterangan"}
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		f.SetCellValue(sheetName, cell, header)
+	}
+
+	// Keep dates as text so they are read back exactly as typed
+	textStyle, _ := f.NewStyle(&excelize.Style{NumFmt: 49})
+	f.SetColStyle(sheetName, "C:D", textStyle)
+
+	examples := [][]interface{}{
+		{"Hari Raya Natal", "libur_nasional", "2026-12-25", "2026-12-25", "", "ya", ""},
+		{"Libur Semester Ganjil", "libur_sekolah", "2026-12-21", "2027-01-01", "", "ya", ""},
+		{"Pentas Seni", "kegiatan_sekolah", "2026-11-14", "2026-11-14", "", "tidak", "Siswa tetap masuk"},
+	}
+	for rowIdx, example := range examples {
+		for colIdx, value := range example {
+			cell, _ := excelize.CoordinatesToCellName(colIdx+1, rowIdx+2)
+			f.SetCellValue(sheetName, cell, value)
+		}
+	}
+
+	f.SetColWidth(sheetName, "A", "A", 30)
+	f.SetColWidth(sheetName, "B", "B", 20)
+	f.SetColWidth(sheetName, "C", "D", 15)
+	f.SetColWidth(sheetName, "G", "G", 40)
+
+	return f, nil
+}
+
+// ImportExcel imports KalenderAkademik entries from an Excel file. Rows with the same jenis, nama and
+// tanggal_mulai as an earlier import are updated instead of duplicated.
+func (s *KalenderAkademikServiceImpl) ImportExcel(file multipart.File, tahunPelajaranID *uint, actor utils.Principal) (*dtos.KalenderAkademikImportResponse, error) {
+	f, err := excelize.OpenReader(file)
+	if err != nil {
+		return nil, errors.New("gagal membuka file excel")
+	}
+	defer f.Close()
+
+	rows, err := f.GetRows("Sheet1")
+	if err != nil {
+		return nil, errors.New("gagal membaca Sheet1")
+	}
+
+	if len(rows) < 2 {
+		return nil, errors.New("file excel kosong atau tidak ada data")
+	}
+
+	// Find column indices
+	columns := map[string]int{}
+	for i, header := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(header))] = i
+	}
+	for _, required := range []string{"nama", "jenis", "tanggal_mulai", "tanggal_selesai"} {
+		if _, ok := columns[required]; !ok {
+			return nil, errors.New("kolom wajib tidak lengkap: nama, jenis, tanggal_mulai, tanggal_selesai")
+		}
+	}
+
+	response := &dtos.KalenderAkademikImportResponse{}
+
+	for i, row := range rows {
+		// Skip header row and empty rows
+		if i == 0 || len(row) == 0 {
+			continue
+		}
+		rowNum := i + 1
+
+		// Helper to safely get column value
+		getCol := func(name string) string {
+			idx, ok := columns[name]
+			if ok && idx < len(row) {
+				return strings.TrimSpace(row[idx])
+			}
+			return ""
+		}
+
+		nama := getCol("nama")
+		jenis := strings.ToLower(getCol("jenis"))
+		if nama == "" && jenis == "" {
+			continue
+		}
+
+		data := &models.KalenderAkademik{
+			TahunPelajaranID: tahunPelajaranID,
+			Jenis:            jenis,
+			Nama:             nama,
+			Libur:            defaultLibur(jenis),
+			Sumber:           "excel",
+		}
+
+		if data.TanggalMulai, err = parseTanggalKalender(getCol("tanggal_mulai")); err != nil {
+			response.FailedCount++
+			response.Errors = append(response.Errors, dtos.KalenderAkademikImportError{Row: rowNum, Message: "format tanggal_mulai tidak valid"})
+			continue
+		}
+		if data.TanggalSelesai, err = parseTanggalKalender(getCol("tanggal_selesai")); err != nil {
+			response.FailedCount++
+			response.Errors = append(response.Errors, dtos.KalenderAkademikImportError{Row: rowNum, Message: "format tanggal_selesai tidak valid"})
+			continue
+		}
+		if semester := getCol("semester"); semester != "" {
+			value, err := strconv.Atoi(semester)
+			if err != nil {
+				response.FailedCount++
+				response.Errors = append(response.Errors, dtos.KalenderAkademikImportError{Row: rowNum, Message: "semester harus 1 atau 2"})
+				continue
+			}
+			data.Semester = &value
+		}
+		if libur := strings.ToLower(getCol("libur")); libur != "" {
+			data.Libur = libur == "ya" || libur == "true" || libur == "1"
+		}
+		if keterangan := getCol("keterangan"); keterangan != "" {
+			data.Keterangan = &keterangan
+		}
+
+		if err := validateKalenderAkademik(data); err != nil {
+			response.FailedCount++
+			response.Errors = append(response.Errors, dtos.KalenderAkademikImportError{Row: rowNum, Message: err.Error()})
+			continue
+		}
+
+		externalUID := fmt.Sprintf("excel:%s:%s:%s", data.Jenis, data.TanggalMulai.Format("2006-01-02"), strings.ToLower(data.Nama))
+		data.ExternalUID = &externalUID
+
+		updated, err := s.upsertImported(data, actor)
+		if err != nil {
+			response.FailedCount++
+			response.Errors = append(response.Errors, dtos.KalenderAkademikImportError{Row: rowNum, Message: "gagal menyimpan: " + err.Error()})
+			continue
+		}
+		if updated {
+			response.UpdatedCount++
+		} else {
+			response.SuccessCount++
+		}
+	}
+
+	return response, nil
+}
+
+// ImportICal imports the events of an iCalendar (.ics) file, e.g. a national holiday calendar.
+// Events are matched on their UID so importing the same file again updates instead of duplicating.
+func (s *KalenderAkademikServiceImpl) ImportICal(file multipart.File, tahunPelajaranID *uint, jenis string, actor utils.Principal) (*dtos.KalenderAkademikImportResponse, error) {
+	if jenis == "" {
+		jenis = "libur_nasional"
+	}
+	if !isJenisKalender(jenis) {
+		return nil, errJenisKalender
+	}
+
+	events, err := utils.ParseICalEvents(file)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca file iCal: %w", err)
+	}
+	if len(events) == 0 {
+		return nil, errors.New("file iCal tidak berisi event")
+	}
+
+	response := &dtos.KalenderAkademikImportResponse{}
+
+	for i, event := range events {
+		uid := event.UID
+		if uid == "" {
+			uid = fmt.Sprintf("%s:%s", event.TanggalMulai.Format("2006-01-02"), strings.ToLower(event.Summary))
+		}
+		externalUID := "ical:" + uid
+
+		data := &models.KalenderAkademik{
+			TahunPelajaranID: tahunPelajaranID,
+			Jenis:            jenis,
+			Nama:             event.Summary,
+			TanggalMulai:     event.TanggalMulai,
+			TanggalSelesai:   event.TanggalSelesai,
+			Libur:            defaultLibur(jenis),
+			Sumber:           "ical",
+			ExternalUID:      &externalUID,
+		}
+		if event.Description != "" {
+			description := event.Description
+			data.Keterangan = &description
+		}
+
+		if err := validateKalenderAkademik(data); err != nil {
+			response.FailedCount++
+			response.Errors = append(response.Errors, dtos.KalenderAkademikImportError{Row: i + 1, Message: err.Error()})
+			continue
+		}
+
+		updated, err := s.upsertImported(data, actor)
+		if err != nil {
+			response.FailedCount++
+			response.Errors = append(response.Errors, dtos.KalenderAkademikImportError{Row: i + 1, Message: "gagal menyimpan: " + err.Error()})
+			continue
+		}
+		if updated {
+			response.UpdatedCount++
+		} else {
+			response.SuccessCount++
+		}
+	}
+
+	return response, nil
+}
+
+// GetHariEfektif lists every date in the range and whether it is a school day
+func (s *KalenderAkademikServiceImpl) GetHariEfektif(req *dtos.HariEfektifRequest) (*dtos.HariEfektifResponse, error) {
+	tanggalMulai, err := time.Parse("2006-01-02", req.TanggalMulai)
+	if err != nil {
+		return nil, errors.New("format tanggal_mulai tidak valid, gunakan YYYY-MM-DD")
+	}
+	tanggalSelesai, err := time.Parse("2006-01-02", req.TanggalSelesai)
+	if err != nil {
+		return nil, errors.New("format tanggal_selesai tidak valid, gunakan YYYY-MM-DD")
+	}
+	if tanggalSelesai.Before(tanggalMulai) {
+		return nil, errors.New("tanggal_selesai harus setelah atau sama dengan tanggal_mulai")
+	}
+	if tanggalSelesai.Sub(tanggalMulai) > 366*24*time.Hour {
+		return nil, errors.New("rentang tanggal maksimal 1 tahun")
+	}
+
+	kalender, err := s.LoadKalender(tanggalMulai, tanggalSelesai)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dtos.HariEfektifResponse{
+		TanggalMulai:   req.TanggalMulai,
+		TanggalSelesai: req.TanggalSelesai,
+		Hari:           []dtos.HariKalender{},
+	}
+	for d := tanggalMulai; !d.After(tanggalSelesai); d = d.AddDate(0, 0, 1) {
+		hariSekolah, keterangan := kalender.IsHariSekolah(d)
+		response.TotalHari++
+		if hariSekolah {
+			response.TotalHariEfektif++
+		}
+		response.Hari = append(response.Hari, dtos.HariKalender{
+			Tanggal:     d.Format("2006-01-02"),
+			Hari:        namaHari[d.Weekday()],
+			HariSekolah: hariSekolah,
+			Keterangan:  keterangan,
+		})
+	}
+
+	return response, nil
+}
+
+// LoadKalender loads everything needed to decide school days between both dates
+func (s *KalenderAkademikServiceImpl) LoadKalender(tanggalMulai, tanggalSelesai time.Time) (*KalenderSekolah, error) {
+	hariSekolahConfig := defaultHariSekolah
+	if config, err := s.konfigurasiRepository.GetByID(1); err == nil && config.HariSekolah != "" {
+		hariSekolahConfig = config.HariSekolah
+	}
+	hariSekolah, err := parseHariSekolah(hariSekolahConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	libur, err := s.repository.GetLiburBetween(tanggalMulai, tanggalSelesai)
+	if err != nil {
+		return nil, errors.New("gagal mengambil kalender akademik")
+	}
+
+	semesters, err := s.periodeService.LoadPeriode()
+	if err != nil {
+		return nil, err
+	}
+
+	return &KalenderSekolah{
+		hariSekolah: hariSekolah,
+		libur:       libur,
+		semesters:   semesters,
+	}, nil
+}
+
+// CekHariSekolah reports whether a single date is a school day and, if not, why
+func (s *KalenderAkademikServiceImpl) CekHariSekolah(tanggal time.Time) (bool, string, error) {
+	kalender, err := s.LoadKalender(tanggal, tanggal)
+	if err != nil {
+		return false, "", err
+	}
+	hariSekolah, keterangan := kalender.IsHariSekolah(tanggal)
+	return hariSekolah, keterangan, nil
+}
+
+// upsertImported creates an imported entry or updates the one with the same external UID
+func (s *KalenderAkademikServiceImpl) upsertImported(data *models.KalenderAkademik, actor utils.Principal) (bool, error) {
+	existing, err := s.repository.GetByExternalUID(*data.ExternalUID)
+	if err != nil {
+		data.CreatedByID = &actor.ID
+		data.CreatedByType = actor.TypePtr()
+		return false, s.repository.Create(data)
+	}
+
+	existing.TahunPelajaranID = data.TahunPelajaranID
+	existing.Jenis = data.Jenis
+	existing.Nama = data.Nama
+	existing.TanggalMulai = data.TanggalMulai
+	existing.TanggalSelesai = data.TanggalSelesai
+	existing.Semester = data.Semester
+	existing.Libur = data.Libur
+	existing.Keterangan = data.Keterangan
+	existing.Sumber = data.Sumber
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
+	return true, s.repository.Update(existing)
+}
+
+// mapToResponse maps model to DTO response
+func (s *KalenderAkademikServiceImpl) mapToResponse(data *models.KalenderAkademik) *dtos.KalenderAkademikResponse {
+	response := &dtos.KalenderAkademikResponse{
+		ID:               data.ID,
+		TahunPelajaranID: data.TahunPelajaranID,
+		Jenis:            data.Jenis,
+		Nama:             data.Nama,
+		TanggalMulai:     data.TanggalMulai.Format("2006-01-02"),
+		TanggalSelesai:   data.TanggalSelesai.Format("2006-01-02"),
+		Semester:         data.Semester,
+		Libur:            data.Libur,
+		Keterangan:       data.Keterangan,
+		Sumber:           data.Sumber,
+		CreatedAt:        data.CreatedAt,
+		UpdatedAt:        data.UpdatedAt,
+		CreatedByID:      data.CreatedByID,
+		CreatedByType:    data.CreatedByType,
+		UpdatedByID:      data.UpdatedByID,
+		UpdatedByType:    data.UpdatedByType,
+	}
+	if data.TahunPelajaran != nil {
+		response.TahunPelajaranNama = data.TahunPelajaran.TahunPelajaran
+	}
+	return response
+}
+
+// validateKalenderAkademik checks the rules shared by manual input and imports
+func validateKalenderAkademik(data *models.KalenderAkademik) error {
+	if !isJenisKalender(data.Jenis) {
+		return errJenisKalender
+	}
+	if strings.TrimSpace(data.Nama) == "" {
+		return errors.New("nama wajib diisi")
+	}
+	if data.TanggalSelesai.Before(data.TanggalMulai) {
+		return errors.New("tanggal_selesai harus setelah atau sama dengan tanggal_mulai")
+	}
+	if data.Semester != nil && *data.Semester != 1 && *data.Semester != 2 {
+		return errors.New("semester harus 1 atau 2")
+	}
+	return nil
+}
+
+// errJenisKalender is returned for an unknown jenis. Semester periods are set on tahun pelajaran instead.
+var errJenisKalender = errors.New("jenis harus libur_nasional, libur_sekolah atau kegiatan_sekolah; tanggal semester diatur di tahun pelajaran")
+
+// isJenisKalender reports whether jenis is a kalender akademik entry type
+func isJenisKalender(jenis string) bool {
+	switch jenis {
+	case "libur_nasional", "libur_sekolah", "kegiatan_sekolah":
+		return true
+	}
+	return false
+}
+
+// defaultLibur tells whether entries of a jenis are non-school days unless stated otherwise
+func defaultLibur(jenis string) bool {
+	return jenis == "libur_nasional" || jenis == "libur_sekolah"
+}
+
+// parseTanggalKalender parses a date typed as YYYY-MM-DD or DD/MM/YYYY
+func parseTanggalKalender(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{"2006-01-02", "02/01/2006", "2/1/2006"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("format tanggal tidak valid")
+}
+
+// parseHariSekolah parses the ISO weekday list of konfigurasi absensi ("1,2,3,4,5")
+func parseHariSekolah(value string) (map[time.Weekday]bool, error) {
+	hariSekolah := make(map[time.Weekday]bool)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		day, err := strconv.Atoi(part)
+		if err != nil || day < 1 || day > 7 {
+			return nil, errors.New("hari_sekolah harus berisi angka 1 (Senin) sampai 7 (Minggu), contoh: 1,2,3,4,5")
+		}
+		hariSekolah[time.Weekday(day%7)] = true
+	}
+	if len(hariSekolah) == 0 {
+		return nil, errors.New("hari_sekolah tidak boleh kosong")
+	}
+	return hariSekolah, nil
+}
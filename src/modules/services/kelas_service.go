@@ -5,15 +5,16 @@ import (
 	"pintu-backend/src/dtos"
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
 )
 
 // KelasService handles business logic for Kelas
 type KelasService interface {
-	Create(req *dtos.KelasCreateRequest, userID uint) (*dtos.KelasResponse, error)
+	Create(req *dtos.KelasCreateRequest, actor utils.Principal) (*dtos.KelasResponse, error)
 	GetByID(id uint) (*dtos.KelasResponse, error)
 	GetAll(limit int, offset int) (*dtos.KelasListResponse, error)
 	GetAllWithFilter(params repositories.GetKelasParams) (*dtos.KelasListWithPaginationResponse, error)
-	Update(req *dtos.KelasUpdateRequest, userID uint) (*dtos.KelasResponse, error)
+	Update(req *dtos.KelasUpdateRequest, actor utils.Principal) (*dtos.KelasResponse, error)
 	Delete(id uint) error
 }
 
@@ -27,7 +28,7 @@ func NewKelasService(repository repositories.KelasRepository) KelasService {
 }
 
 // Create creates a new Kelas
-func (s *KelasServiceImpl) Create(req *dtos.KelasCreateRequest, userID uint) (*dtos.KelasResponse, error) {
+func (s *KelasServiceImpl) Create(req *dtos.KelasCreateRequest, actor utils.Principal) (*dtos.KelasResponse, error) {
 	// Check if name already exists
 	_, err := s.repository.GetByName(req.Name)
 	if err == nil {
@@ -41,9 +42,10 @@ func (s *KelasServiceImpl) Create(req *dtos.KelasCreateRequest, userID uint) (*d
 	}
 
 	data := &models.Kelas{
-		Name:        req.Name,
-		Status:      status,
-		CreatedByID: &userID,
+		Name:          req.Name,
+		Status:        status,
+		CreatedByID:   &actor.ID,
+		CreatedByType: actor.TypePtr(),
 	}
 
 	if err := s.repository.Create(data); err != nil {
@@ -92,7 +94,7 @@ func (s *KelasServiceImpl) GetAll(limit int, offset int) (*dtos.KelasListRespons
 }
 
 // Update updates Kelas
-func (s *KelasServiceImpl) Update(req *dtos.KelasUpdateRequest, userID uint) (*dtos.KelasResponse, error) {
+func (s *KelasServiceImpl) Update(req *dtos.KelasUpdateRequest, actor utils.Principal) (*dtos.KelasResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(req.ID)
 	if err != nil {
@@ -107,7 +109,8 @@ func (s *KelasServiceImpl) Update(req *dtos.KelasUpdateRequest, userID uint) (*d
 		existing.Status = *req.Status
 	}
 
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	if err := s.repository.Update(existing); err != nil {
 		return nil, err
@@ -162,12 +165,14 @@ func (s *KelasServiceImpl) GetAllWithFilter(params repositories.GetKelasParams)
 // mapToResponse maps model to DTO response
 func (s *KelasServiceImpl) mapToResponse(data *models.Kelas) *dtos.KelasResponse {
 	return &dtos.KelasResponse{
-		ID:          data.ID,
-		Name:        data.Name,
-		Status:      data.Status,
-		CreatedAt:   data.CreatedAt,
-		UpdatedAt:   data.UpdatedAt,
-		CreatedByID: data.CreatedByID,
-		UpdatedByID: data.UpdatedByID,
+		ID:            data.ID,
+		Name:          data.Name,
+		Status:        data.Status,
+		CreatedAt:     data.CreatedAt,
+		UpdatedAt:     data.UpdatedAt,
+		CreatedByID:   data.CreatedByID,
+		CreatedByType: data.CreatedByType,
+		UpdatedByID:   data.UpdatedByID,
+		UpdatedByType: data.UpdatedByType,
 	}
 }
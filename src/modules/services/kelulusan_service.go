@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -19,41 +20,47 @@ import (
 )
 
 type KelulusanService interface {
-	CreateKelulusan(req *dtos.KelulusanCreateRequest, file *multipart.FileHeader, userID uint) (*dtos.KelulusanResponse, error)
+	CreateKelulusan(ctx context.Context, req *dtos.KelulusanCreateRequest, file *multipart.FileHeader, actor utils.Principal) (*dtos.KelulusanResponse, error)
 	DownloadTemplate(mapelList []string) (*excelize.File, error)
-	ImportExcel(file multipart.File, userID uint) (*dtos.ImportKelulusanResponse, error)
+	ImportExcel(file multipart.File, actor utils.Principal) (*dtos.ImportKelulusanResponse, error)
 	GetAllWithFilter(params repositories.GetKelulusanParams) (*dtos.KelulusanListWithPaginationResponse, error)
 	GetByID(id uint) (*dtos.KelulusanResponse, error)
-	CekNilaiKelulusan(nisn string, tanggalLahir string) (*dtos.CekNilaiKelulusanResponse, error)
-	CekKelulusan(nisn string, tanggalLahir string) (*dtos.KelulusanResponse, error)
-	Update(id uint, req *dtos.KelulusanUpdateRequest, file *multipart.FileHeader, userID uint) (*dtos.KelulusanResponse, error)
+	CekNilaiKelulusan(nisn string, tanggalLahir string, ipAddress string) (*dtos.CekNilaiKelulusanResponse, error)
+	CekKelulusan(nisn string, tanggalLahir string, ipAddress string) (*dtos.KelulusanResponse, error)
+	Update(ctx context.Context, id uint, req *dtos.KelulusanUpdateRequest, file *multipart.FileHeader, actor utils.Principal) (*dtos.KelulusanResponse, error)
 	Delete(id uint) error
-	DownloadLaporanNilaiKelulusan(nisn string, tanggalLahir string) ([]byte, error)
+	DownloadLaporanNilaiKelulusan(nisn string, tanggalLahir string, ipAddress string) ([]byte, error)
 }
 
 type KelulusanServiceImpl struct {
 	repository                repositories.KelulusanRepository
 	tahunPelajaranRepo        repositories.TahunPelajaranRepository
+	periodeService            PeriodeAkademikService
 	pengumumanKelulusanRepo   repositories.PengumumanKelulusanRepository
-	r2Storage                 *utils.R2Storage
+	throttleService           ThrottleService
+	storage                   utils.Storage
 }
 
 // NewKelulusanService creates a new Kelulusan service
 func NewKelulusanService(
 	repository repositories.KelulusanRepository, 
 	tahunPelajaranRepo repositories.TahunPelajaranRepository,
+	periodeService PeriodeAkademikService,
 	pengumumanKelulusanRepo repositories.PengumumanKelulusanRepository,
+	throttleService ThrottleService,
 ) KelulusanService {
 	return &KelulusanServiceImpl{
 		repository:              repository,
 		tahunPelajaranRepo:      tahunPelajaranRepo,
+		periodeService:          periodeService,
 		pengumumanKelulusanRepo: pengumumanKelulusanRepo,
-		r2Storage:               utils.NewR2Storage(),
+		throttleService:         throttleService,
+		storage:                 utils.NewStorage(),
 	}
 }
 
 // CreateKelulusan creates a new kelulusan record with optional SKL file upload
-func (s *KelulusanServiceImpl) CreateKelulusan(req *dtos.KelulusanCreateRequest, file *multipart.FileHeader, userID uint) (*dtos.KelulusanResponse, error) {
+func (s *KelulusanServiceImpl) CreateKelulusan(ctx context.Context, req *dtos.KelulusanCreateRequest, file *multipart.FileHeader, actor utils.Principal) (*dtos.KelulusanResponse, error) {
 	// Parse tanggal_lahir (YYYY-MM-DD format)
 	tanggalLahir, err := time.Parse("2006-01-02", req.TanggalLahir)
 	if err != nil {
@@ -76,32 +83,35 @@ func (s *KelulusanServiceImpl) CreateKelulusan(req *dtos.KelulusanCreateRequest,
 	var sklPath string
 	if file != nil {
 		// Upload to R2 in kelulusan-skl folder
-		uploadedPath, err := s.r2Storage.UploadFile(file, "kelulusan-skl")
+		stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryDokumen, "kelulusan-skl")
 		if err != nil {
 			return nil, fmt.Errorf("gagal upload file SKL: %s", err.Error())
 		}
+		uploadedPath := stored.Key
 		sklPath = uploadedPath
 	}
 
 	// Create kelulusan record
 	kelulusan := &models.Kelulusan{
-		NomorPeserta: req.NomorPeserta,
-		NISN:         req.NISN,
-		Nama:         req.Nama,
-		TanggalLahir: tanggalLahir,
-		Nilai:        datatypes.JSON(nilaiJSON),
-		Lulus:        req.Lulus,
-		SKL:          sklPath,
-		MaxAttempts:  req.MaxAttempts,
-		AttemptCount: 0,
-		CreatedByID:  &userID,
-		UpdatedByID:  &userID,
+		NomorPeserta:  req.NomorPeserta,
+		NISN:          req.NISN,
+		Nama:          req.Nama,
+		TanggalLahir:  tanggalLahir,
+		Nilai:         datatypes.JSON(nilaiJSON),
+		Lulus:         req.Lulus,
+		SKL:           sklPath,
+		MaxAttempts:   req.MaxAttempts,
+		AttemptCount:  0,
+		CreatedByID:   &actor.ID,
+		CreatedByType: actor.TypePtr(),
+		UpdatedByID:   &actor.ID,
+		UpdatedByType: actor.TypePtr(),
 	}
 
 	if err := s.repository.Create(kelulusan); err != nil {
 		// Delete uploaded file from R2 if save to DB failed
 		if sklPath != "" {
-			s.r2Storage.DeleteFile(sklPath)
+			s.storage.DeleteFile(sklPath)
 		}
 		return nil, errors.New("gagal menyimpan data kelulusan")
 	}
@@ -148,7 +158,7 @@ func (s *KelulusanServiceImpl) mapToResponse(data *models.Kelulusan) *dtos.Kelul
 	}
 
 	// Generate full URL for SKL file
-	sklURL := s.r2Storage.GetPublicURL(data.SKL)
+	sklURL := s.storage.GetPublicURL(data.SKL)
 
 	response := &dtos.KelulusanResponse{
 		ID:            data.ID,
@@ -165,7 +175,11 @@ func (s *KelulusanServiceImpl) mapToResponse(data *models.Kelulusan) *dtos.Kelul
 		CreatedAt:     data.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt:     data.UpdatedAt.Format("2006-01-02 15:04:05"),
 		CreatedByID:   data.CreatedByID,
+		CreatedByType: data.CreatedByType,
 		UpdatedByID:   data.UpdatedByID,
+		UpdatedByType: data.UpdatedByType,
+		CreatedBy:     data.CreatedBy,
+		UpdatedBy:     data.UpdatedBy,
 	}
 
 	return response
@@ -283,7 +297,7 @@ func (s *KelulusanServiceImpl) DownloadTemplate(mapelList []string) (*excelize.F
 }
 
 // ImportExcel imports Kelulusan data from an Excel file
-func (s *KelulusanServiceImpl) ImportExcel(file multipart.File, userID uint) (*dtos.ImportKelulusanResponse, error) {
+func (s *KelulusanServiceImpl) ImportExcel(file multipart.File, actor utils.Principal) (*dtos.ImportKelulusanResponse, error) {
 	f, err := excelize.OpenReader(file)
 	if err != nil {
 		return nil, errors.New("gagal membuka file excel")
@@ -529,14 +543,16 @@ func (s *KelulusanServiceImpl) ImportExcel(file multipart.File, userID uint) (*d
 
 		// Create kelulusan record
 		kelulusan := &models.Kelulusan{
-			NomorPeserta: nomorPeserta,
-			NISN:         nisn,
-			Nama:         nama,
-			TanggalLahir: tanggalLahir,
-			Nilai:        datatypes.JSON(nilaiJSON),
-			Lulus:        lulus,
-			CreatedByID:  &userID,
-			UpdatedByID:  &userID,
+			NomorPeserta:  nomorPeserta,
+			NISN:          nisn,
+			Nama:          nama,
+			TanggalLahir:  tanggalLahir,
+			Nilai:         datatypes.JSON(nilaiJSON),
+			Lulus:         lulus,
+			CreatedByID:   &actor.ID,
+			CreatedByType: actor.TypePtr(),
+			UpdatedByID:   &actor.ID,
+			UpdatedByType: actor.TypePtr(),
 		}
 
 		if err := s.repository.Create(kelulusan); err != nil {
@@ -605,18 +621,35 @@ func (s *KelulusanServiceImpl) GetByID(id uint) (*dtos.KelulusanResponse, error)
 	return s.mapToResponse(data), nil
 }
 
+// findByNISNAndTanggalLahir looks up kelulusan for the public pages, a wrong NISN and
+// tanggal lahir pair counts as a failed attempt for the requesting IP and the NISN
+func (s *KelulusanServiceImpl) findByNISNAndTanggalLahir(nisn string, tanggalLahir string, ipAddress string) (*models.Kelulusan, error) {
+	if err := s.throttleService.Check(CekKelulusanThrottlePolicy, ipAddress, nisn); err != nil {
+		return nil, err
+	}
+
+	data, err := s.repository.GetByNISNAndTanggalLahir(nisn, tanggalLahir)
+	if err != nil {
+		s.throttleService.RecordFailure(CekKelulusanThrottlePolicy, ipAddress, nisn)
+		return nil, errors.New("data kelulusan tidak ditemukan")
+	}
+
+	s.throttleService.Reset(CekKelulusanThrottlePolicy, nisn)
+	return data, nil
+}
+
 // CekNilaiKelulusan retrieves Kelulusan by NISN and tanggal lahir (public API, no lulus info)
-func (s *KelulusanServiceImpl) CekNilaiKelulusan(nisn string, tanggalLahir string) (*dtos.CekNilaiKelulusanResponse, error) {
+func (s *KelulusanServiceImpl) CekNilaiKelulusan(nisn string, tanggalLahir string, ipAddress string) (*dtos.CekNilaiKelulusanResponse, error) {
 	// Parse tanggal_lahir to validate format
 	_, err := time.Parse("2006-01-02", tanggalLahir)
 	if err != nil {
 		return nil, errors.New("format tanggal_lahir tidak valid, gunakan YYYY-MM-DD")
 	}
 
-	// Get data from repository
-	data, err := s.repository.GetByNISNAndTanggalLahir(nisn, tanggalLahir)
+	// Get data from repository, throttled per IP and per NISN
+	data, err := s.findByNISNAndTanggalLahir(nisn, tanggalLahir, ipAddress)
 	if err != nil {
-		return nil, errors.New("data kelulusan tidak ditemukan")
+		return nil, err
 	}
 
 	// Parse nilai JSON to map for calculation only
@@ -653,7 +686,7 @@ func (s *KelulusanServiceImpl) CekNilaiKelulusan(nisn string, tanggalLahir strin
 	}
 
 	// Generate full URL for SKL file
-	sklURL := s.r2Storage.GetPublicURL(data.SKL)
+	sklURL := s.storage.GetPublicURL(data.SKL)
 
 	// Map to response (without lulus field)
 	response := &dtos.CekNilaiKelulusanResponse{
@@ -673,17 +706,17 @@ func (s *KelulusanServiceImpl) CekNilaiKelulusan(nisn string, tanggalLahir strin
 }
 
 // CekKelulusan retrieves full Kelulusan data by NISN and tanggal lahir (public API, with lulus info)
-func (s *KelulusanServiceImpl) CekKelulusan(nisn string, tanggalLahir string) (*dtos.KelulusanResponse, error) {
+func (s *KelulusanServiceImpl) CekKelulusan(nisn string, tanggalLahir string, ipAddress string) (*dtos.KelulusanResponse, error) {
 	// Parse tanggal_lahir to validate format
 	_, err := time.Parse("2006-01-02", tanggalLahir)
 	if err != nil {
 		return nil, errors.New("format tanggal_lahir tidak valid, gunakan YYYY-MM-DD")
 	}
 
-	// Get data from repository
-	data, err := s.repository.GetByNISNAndTanggalLahir(nisn, tanggalLahir)
+	// Get data from repository, throttled per IP and per NISN
+	data, err := s.findByNISNAndTanggalLahir(nisn, tanggalLahir, ipAddress)
 	if err != nil {
-		return nil, errors.New("data kelulusan tidak ditemukan")
+		return nil, err
 	}
 
 	// PRANK LOGIC: Check if max_attempts > 0 (prank mode enabled)
@@ -714,7 +747,7 @@ func (s *KelulusanServiceImpl) CekKelulusan(nisn string, tanggalLahir string) (*
 }
 
 // Update updates Kelulusan record
-func (s *KelulusanServiceImpl) Update(id uint, req *dtos.KelulusanUpdateRequest, file *multipart.FileHeader, userID uint) (*dtos.KelulusanResponse, error) {
+func (s *KelulusanServiceImpl) Update(ctx context.Context, id uint, req *dtos.KelulusanUpdateRequest, file *multipart.FileHeader, actor utils.Principal) (*dtos.KelulusanResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(id)
 	if err != nil {
@@ -771,7 +804,7 @@ func (s *KelulusanServiceImpl) Update(id uint, req *dtos.KelulusanUpdateRequest,
 	if req.DeleteSKL {
 		// Delete old file from R2 if exists
 		if oldSKL != "" {
-			_ = s.r2Storage.DeleteFile(oldSKL)
+			_ = s.storage.DeleteFile(oldSKL)
 		}
 		existing.SKL = ""
 	}
@@ -779,27 +812,29 @@ func (s *KelulusanServiceImpl) Update(id uint, req *dtos.KelulusanUpdateRequest,
 	// Handle file upload if provided (this will override delete_skl if both are sent)
 	if file != nil {
 		// Upload new file to R2
-		uploadedPath, err := s.r2Storage.UploadFile(file, "kelulusan-skl")
+		stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryDokumen, "kelulusan-skl")
 		if err != nil {
 			return nil, fmt.Errorf("gagal upload file SKL: %s", err.Error())
 		}
+		uploadedPath := stored.Key
 
 		// Delete old file from R2 if exists (only if different from new file)
 		if oldSKL != "" && oldSKL != uploadedPath {
-			_ = s.r2Storage.DeleteFile(oldSKL)
+			_ = s.storage.DeleteFile(oldSKL)
 		}
 
 		existing.SKL = uploadedPath
 	}
 
 	// Update metadata
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	// Save to database
 	if err := s.repository.Update(existing); err != nil {
 		// If update failed and new file was uploaded, delete the new file
 		if file != nil && existing.SKL != "" && existing.SKL != oldSKL {
-			s.r2Storage.DeleteFile(existing.SKL)
+			s.storage.DeleteFile(existing.SKL)
 		}
 		return nil, errors.New("gagal mengupdate data kelulusan")
 	}
@@ -821,7 +856,7 @@ func (s *KelulusanServiceImpl) Delete(id uint) error {
 
 	// Delete file SKL from R2 if exists
 	if existing.SKL != "" {
-		_ = s.r2Storage.DeleteFile(existing.SKL)
+		_ = s.storage.DeleteFile(existing.SKL)
 	}
 
 	// Soft delete from database
@@ -833,23 +868,30 @@ func (s *KelulusanServiceImpl) Delete(id uint) error {
 }
 
 // DownloadLaporanNilaiKelulusan generates PDF report for kelulusan by NISN and tanggal lahir
-func (s *KelulusanServiceImpl) DownloadLaporanNilaiKelulusan(nisn string, tanggalLahir string) ([]byte, error) {
+func (s *KelulusanServiceImpl) DownloadLaporanNilaiKelulusan(nisn string, tanggalLahir string, ipAddress string) ([]byte, error) {
 	// Parse tanggal_lahir to validate format
 	_, err := time.Parse("2006-01-02", tanggalLahir)
 	if err != nil {
 		return nil, errors.New("format tanggal_lahir tidak valid, gunakan YYYY-MM-DD")
 	}
 
-	// Get data from repository
-	data, err := s.repository.GetByNISNAndTanggalLahir(nisn, tanggalLahir)
+	// Get data from repository, throttled per IP and per NISN
+	data, err := s.findByNISNAndTanggalLahir(nisn, tanggalLahir, ipAddress)
 	if err != nil {
-		return nil, errors.New("data kelulusan tidak ditemukan")
+		return nil, err
 	}
 
-	// Get active tahun pelajaran
-	tahunPelajaran, err := s.tahunPelajaranRepo.GetActiveAcademicYear()
-	if err != nil {
-		return nil, errors.New("tahun pelajaran aktif tidak ditemukan")
+	// Tahun pelajaran of today, which stays the graduating year during the holiday after semester 2,
+	// falling back to the active tahun pelajaran when no semester dates are set
+	var namaTahunPelajaran string
+	if periode, err := s.periodeService.ResolveTahunPelajaran(time.Now().In(utils.JakartaLocation())); err == nil {
+		namaTahunPelajaran = periode.TahunPelajaran
+	} else {
+		tahunPelajaran, err := s.tahunPelajaranRepo.GetActiveAcademicYear()
+		if err != nil {
+			return nil, errors.New("tahun pelajaran aktif tidak ditemukan")
+		}
+		namaTahunPelajaran = tahunPelajaran.TahunPelajaran
 	}
 
 	// Get pengumuman kelulusan (ID = 1)
@@ -904,7 +946,7 @@ func (s *KelulusanServiceImpl) DownloadLaporanNilaiKelulusan(nisn string, tangga
 	pdf := utils.NewPDFGenerator()
 	
 	// Add header
-	pdf.AddHeader("LAPORAN SEMENTARA NILAI TES KEMAMPUAN AKADEMIK", tahunPelajaran.TahunPelajaran)
+	pdf.AddHeader("LAPORAN SEMENTARA NILAI TES KEMAMPUAN AKADEMIK", namaTahunPelajaran)
 	
 	// Add student info (Nama and NISN)
 	pdf.AddStudentInfoSimple(data.Nama, data.NISN)
@@ -917,7 +959,7 @@ func (s *KelulusanServiceImpl) DownloadLaporanNilaiKelulusan(nisn string, tangga
 	
 	// Add signatures (Orang Tua Murid and Kepala Sekolah)
 	// Get TTD Kepsek URL from R2 storage
-	ttdKepsekURL := s.r2Storage.GetPublicURL(pengumumanKelulusan.TtdKepsek)
+	ttdKepsekURL := s.storage.GetPublicURL(pengumumanKelulusan.TtdKepsek)
 	pdf.AddSignatures(pengumumanKelulusan.TanggalPengumumanKelulusan, pengumumanKelulusan.NamaKepsek, ttdKepsekURL)
 	
 	// Get PDF bytes
@@ -0,0 +1,539 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+)
+
+// KenaikanKelasService proposes, previews, processes and undoes academic year rollovers
+type KenaikanKelasService interface {
+	GetUsulan(req *dtos.KenaikanKelasUsulanRequest) (*dtos.KenaikanKelasUsulanResponse, error)
+	Preview(req *dtos.KenaikanKelasRequest) (*dtos.KenaikanKelasPreviewResponse, error)
+	Proses(req *dtos.KenaikanKelasRequest, actor utils.Principal) (*dtos.KenaikanKelasResponse, error)
+	Batalkan(id uint, actor utils.Principal) (*dtos.KenaikanKelasResponse, error)
+	GetAll(limit, offset int) (*dtos.KenaikanKelasListWithPaginationResponse, error)
+	GetByID(id uint) (*dtos.KenaikanKelasResponse, error)
+}
+
+type KenaikanKelasServiceImpl struct {
+	repository repositories.KenaikanKelasRepository
+}
+
+// NewKenaikanKelasService creates a new KenaikanKelas service
+func NewKenaikanKelasService(repository repositories.KenaikanKelasRepository) KenaikanKelasService {
+	return &KenaikanKelasServiceImpl{repository: repository}
+}
+
+// rombelKenaikan is an active rombel with its parsed kelas level (0 when the kelas name has no level)
+type rombelKenaikan struct {
+	rombel  models.Rombel
+	tingkat int
+	akhiran string
+}
+
+// rencanaKenaikanKelas is the full outcome of a rollover, shared by preview and proses
+type rencanaKenaikanKelas struct {
+	asal    *models.TahunPelajaran
+	tujuan  *models.TahunPelajaran
+	detail  []models.KenaikanKelasDetail
+	preview *dtos.KenaikanKelasPreviewResponse
+}
+
+// GetUsulan proposes the next rombel of every active rombel: same letter one kelas higher, or the only rombel
+// of the next kelas. Rombel of the highest kelas graduate.
+func (s *KenaikanKelasServiceImpl) GetUsulan(req *dtos.KenaikanKelasUsulanRequest) (*dtos.KenaikanKelasUsulanResponse, error) {
+	asal, tujuan, err := s.getTahunPelajaran(req.TahunPelajaranAsalID, req.TahunPelajaranTujuanID)
+	if err != nil {
+		return nil, err
+	}
+
+	rombels, tingkatAkhir, err := s.loadRombel()
+	if err != nil {
+		return nil, err
+	}
+	mapping := usulanMappingRombel(rombels, tingkatAkhir)
+
+	members, err := s.repository.GetPesertaDidikRombelAktif(asal.ID)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data siswa")
+	}
+	jumlahSiswa := make(map[uint]int)
+	for _, member := range members {
+		jumlahSiswa[member.RombelID]++
+	}
+
+	response := &dtos.KenaikanKelasUsulanResponse{
+		TahunPelajaranAsal:   asal.TahunPelajaran,
+		TahunPelajaranTujuan: tujuan.TahunPelajaran,
+		Rombel:               []dtos.KenaikanKelasUsulanRombel{},
+	}
+	for _, item := range rombels {
+		usulan := dtos.KenaikanKelasUsulanRombel{
+			RombelAsalID: item.rombel.ID,
+			RombelAsal:   item.rombel.Name,
+			JumlahSiswa:  jumlahSiswa[item.rombel.ID],
+			Lulus:        item.tingkat > 0 && item.tingkat == tingkatAkhir,
+		}
+		if item.rombel.Kelas != nil {
+			usulan.KelasAsal = item.rombel.Kelas.Name
+		}
+		if next, ok := mapping[item.rombel.ID]; ok {
+			usulan.RombelTujuanID = &next.ID
+			usulan.RombelTujuan = &next.Name
+		}
+		response.Rombel = append(response.Rombel, usulan)
+	}
+
+	return response, nil
+}
+
+// Preview returns what processing the rollover would do, including every error that blocks it
+func (s *KenaikanKelasServiceImpl) Preview(req *dtos.KenaikanKelasRequest) (*dtos.KenaikanKelasPreviewResponse, error) {
+	rencana, err := s.susunRencana(req)
+	if err != nil {
+		return nil, err
+	}
+	return rencana.preview, nil
+}
+
+// Proses creates the rombel members of the target year, marks the highest kelas as lulus and activates the
+// target year in one transaction
+func (s *KenaikanKelasServiceImpl) Proses(req *dtos.KenaikanKelasRequest, actor utils.Principal) (*dtos.KenaikanKelasResponse, error) {
+	rencana, err := s.susunRencana(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(rencana.preview.Errors) > 0 {
+		return nil, fmt.Errorf("kenaikan kelas belum dapat diproses: %s", strings.Join(rencana.preview.Errors, "; "))
+	}
+	if len(rencana.detail) == 0 {
+		return nil, errors.New("tidak ada siswa aktif pada tahun pelajaran asal")
+	}
+
+	data := &models.KenaikanKelas{
+		TahunPelajaranAsalID:   rencana.asal.ID,
+		TahunPelajaranTujuanID: rencana.tujuan.ID,
+		Status:                 models.KenaikanKelasStatusDiproses,
+		TotalNaik:              rencana.preview.TotalNaik,
+		TotalTinggalKelas:      rencana.preview.TotalTinggalKelas,
+		TotalLulus:             rencana.preview.TotalLulus,
+		DiprosesAt:             time.Now(),
+		CreatedByID:            &actor.ID,
+		CreatedByType:          actor.TypePtr(),
+		Detail:                 rencana.detail,
+	}
+	if aktif, err := s.repository.GetActiveTahunPelajaran(); err == nil {
+		data.TahunPelajaranAktifSebelumnyaID = &aktif.ID
+	}
+
+	if err := s.repository.Proses(data); err != nil {
+		if errors.Is(err, repositories.ErrTahunPelajaranTujuanTerisi) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("gagal memproses kenaikan kelas: %s", err.Error())
+	}
+
+	return s.GetByID(data.ID)
+}
+
+// Batalkan undoes the latest rollover on the day it was processed, as long as the target year has no attendance yet
+func (s *KenaikanKelasServiceImpl) Batalkan(id uint, actor utils.Principal) (*dtos.KenaikanKelasResponse, error) {
+	data, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, errors.New("kenaikan kelas tidak ditemukan")
+	}
+	if data.Status != models.KenaikanKelasStatusDiproses {
+		return nil, repositories.ErrKenaikanKelasDibatalkan
+	}
+	if !s.bisaDibatalkan(data) {
+		return nil, repositories.ErrKenaikanKelasBukanTerakhir
+	}
+
+	if err := s.repository.Batalkan(data, &actor.ID, actor.TypePtr()); err != nil {
+		if errors.Is(err, repositories.ErrKenaikanKelasSudahDipakai) || errors.Is(err, repositories.ErrKenaikanKelasDibatalkan) ||
+			errors.Is(err, repositories.ErrKenaikanKelasBukanTerakhir) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("gagal membatalkan kenaikan kelas: %s", err.Error())
+	}
+
+	return s.GetByID(id)
+}
+
+// GetAll retrieves the rollover history with pagination
+func (s *KenaikanKelasServiceImpl) GetAll(limit, offset int) (*dtos.KenaikanKelasListWithPaginationResponse, error) {
+	if limit == 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	data, total, err := s.repository.GetAll(limit, offset)
+	if err != nil {
+		return nil, errors.New("gagal mengambil riwayat kenaikan kelas")
+	}
+
+	latestID := uint(0)
+	if latest, err := s.repository.GetLatestDiproses(); err == nil {
+		latestID = latest.ID
+	}
+
+	responses := make([]dtos.KenaikanKelasResponse, len(data))
+	for i := range data {
+		responses[i] = *s.mapToResponse(&data[i], latestID)
+	}
+
+	return &dtos.KenaikanKelasListWithPaginationResponse{
+		Data: responses,
+		Pagination: dtos.PaginationInfo{
+			Limit:      limit,
+			Offset:     offset,
+			Page:       (offset / limit) + 1,
+			Total:      total,
+			TotalPages: (int(total) + limit - 1) / limit,
+		},
+	}, nil
+}
+
+// GetByID retrieves a rollover by ID
+func (s *KenaikanKelasServiceImpl) GetByID(id uint) (*dtos.KenaikanKelasResponse, error) {
+	data, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, errors.New("kenaikan kelas tidak ditemukan")
+	}
+
+	latestID := uint(0)
+	if latest, err := s.repository.GetLatestDiproses(); err == nil {
+		latestID = latest.ID
+	}
+	return s.mapToResponse(data, latestID), nil
+}
+
+// susunRencana decides the outcome for every active student of the source year: the proposed or overridden
+// next rombel for naik, the same rombel for tinggal kelas and no rombel for lulus
+func (s *KenaikanKelasServiceImpl) susunRencana(req *dtos.KenaikanKelasRequest) (*rencanaKenaikanKelas, error) {
+	asal, tujuan, err := s.getTahunPelajaran(req.TahunPelajaranAsalID, req.TahunPelajaranTujuanID)
+	if err != nil {
+		return nil, err
+	}
+
+	rombels, tingkatAkhir, err := s.loadRombel()
+	if err != nil {
+		return nil, err
+	}
+	rombelByID := make(map[uint]rombelKenaikan, len(rombels))
+	for _, item := range rombels {
+		rombelByID[item.rombel.ID] = item
+	}
+
+	preview := &dtos.KenaikanKelasPreviewResponse{
+		TahunPelajaranAsal:   asal.TahunPelajaran,
+		TahunPelajaranTujuan: tujuan.TahunPelajaran,
+		RombelTujuan:         []dtos.KenaikanKelasRombelTujuanItem{},
+		Siswa:                []dtos.KenaikanKelasSiswaItem{},
+		Errors:               []string{},
+	}
+
+	// Proposed mapping with the operator's overrides
+	mapping := usulanMappingRombel(rombels, tingkatAkhir)
+	for _, item := range req.MappingRombel {
+		if _, ok := rombelByID[item.RombelAsalID]; !ok {
+			return nil, fmt.Errorf("rombel asal ID %d tidak ditemukan atau tidak aktif", item.RombelAsalID)
+		}
+		next, ok := rombelByID[item.RombelTujuanID]
+		if !ok {
+			return nil, fmt.Errorf("rombel tujuan ID %d tidak ditemukan atau tidak aktif", item.RombelTujuanID)
+		}
+		mapping[item.RombelAsalID] = next.rombel
+	}
+
+	if total, err := s.repository.CountPesertaDidikRombel(tujuan.ID); err != nil {
+		return nil, errors.New("gagal memeriksa pemetaan rombel tahun pelajaran tujuan")
+	} else if total > 0 {
+		preview.Errors = append(preview.Errors, repositories.ErrTahunPelajaranTujuanTerisi.Error())
+	}
+
+	members, err := s.repository.GetPesertaDidikRombelAktif(asal.ID)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data siswa")
+	}
+	memberIDs := make(map[uint]bool, len(members))
+	for _, member := range members {
+		memberIDs[member.ID] = true
+	}
+
+	keputusan := make(map[uint]dtos.KenaikanKelasKeputusanSiswa, len(req.KeputusanSiswa))
+	for _, item := range req.KeputusanSiswa {
+		if !memberIDs[item.PesertaDidikRombelID] {
+			return nil, fmt.Errorf("peserta didik rombel ID %d bukan siswa aktif tahun pelajaran asal", item.PesertaDidikRombelID)
+		}
+		if item.RombelTujuanID != nil {
+			if _, ok := rombelByID[*item.RombelTujuanID]; !ok {
+				return nil, fmt.Errorf("rombel tujuan ID %d tidak ditemukan atau tidak aktif", *item.RombelTujuanID)
+			}
+		}
+		keputusan[item.PesertaDidikRombelID] = item
+	}
+
+	var detail []models.KenaikanKelasDetail
+	jumlahTujuan := make(map[uint]int)
+	belumDipetakan := make(map[uint]bool)
+	for _, member := range members {
+		asalRombel := rombelByID[member.RombelID]
+
+		// Default: the highest kelas graduates, everyone else moves to the mapped rombel
+		hasil := models.KeputusanNaik
+		if asalRombel.tingkat > 0 && asalRombel.tingkat == tingkatAkhir {
+			hasil = models.KeputusanLulus
+		}
+		var rombelTujuan *models.Rombel
+		override, adaOverride := keputusan[member.ID]
+		if adaOverride {
+			hasil = override.Keputusan
+		}
+		switch hasil {
+		case models.KeputusanNaik:
+			if next, ok := mapping[member.RombelID]; ok {
+				rombelTujuan = &next
+			}
+		case models.KeputusanTinggalKelas:
+			if member.Rombel != nil {
+				rombelTujuan = member.Rombel
+			}
+		}
+		if adaOverride && override.RombelTujuanID != nil && hasil != models.KeputusanLulus {
+			next := rombelByID[*override.RombelTujuanID].rombel
+			rombelTujuan = &next
+		}
+
+		nama := ""
+		if member.Rombel != nil {
+			nama = member.Rombel.Name
+		}
+		item := dtos.KenaikanKelasSiswaItem{
+			PesertaDidikRombelID: member.ID,
+			PesertaDidikID:       member.PesertaDidikID,
+			RombelAsal:           nama,
+			Keputusan:            hasil,
+		}
+		if member.PesertaDidik != nil {
+			item.NIS = member.PesertaDidik.NIS
+			item.Nama = member.PesertaDidik.Nama
+		}
+
+		statusSebelumnya := "active"
+		if member.PesertaDidik != nil {
+			statusSebelumnya = member.PesertaDidik.Status
+		}
+		row := models.KenaikanKelasDetail{
+			PesertaDidikID:               member.PesertaDidikID,
+			PesertaDidikRombelAsalID:     member.ID,
+			RombelAsalID:                 member.RombelID,
+			Keputusan:                    hasil,
+			StatusPesertaDidikSebelumnya: statusSebelumnya,
+		}
+
+		switch hasil {
+		case models.KeputusanLulus:
+			preview.TotalLulus++
+		default:
+			if hasil == models.KeputusanNaik {
+				preview.TotalNaik++
+			} else {
+				preview.TotalTinggalKelas++
+			}
+			if rombelTujuan == nil {
+				if !belumDipetakan[member.RombelID] {
+					belumDipetakan[member.RombelID] = true
+					preview.Errors = append(preview.Errors, fmt.Sprintf("rombel %s belum dipetakan ke rombel tujuan", nama))
+				}
+			} else {
+				id := rombelTujuan.ID
+				rombelNama := rombelTujuan.Name
+				item.RombelTujuanID = &id
+				item.RombelTujuan = &rombelNama
+				row.RombelTujuanID = &id
+				jumlahTujuan[id]++
+			}
+		}
+
+		preview.Siswa = append(preview.Siswa, item)
+		detail = append(detail, row)
+	}
+
+	for id, jumlah := range jumlahTujuan {
+		preview.RombelTujuan = append(preview.RombelTujuan, dtos.KenaikanKelasRombelTujuanItem{
+			RombelTujuanID: id,
+			RombelTujuan:   rombelByID[id].rombel.Name,
+			JumlahSiswa:    jumlah,
+		})
+	}
+	sort.Slice(preview.RombelTujuan, func(i, j int) bool {
+		return preview.RombelTujuan[i].RombelTujuan < preview.RombelTujuan[j].RombelTujuan
+	})
+
+	return &rencanaKenaikanKelas{
+		asal:    asal,
+		tujuan:  tujuan,
+		detail:  detail,
+		preview: preview,
+	}, nil
+}
+
+// getTahunPelajaran loads the source and target year, the target must start after the source
+func (s *KenaikanKelasServiceImpl) getTahunPelajaran(asalID, tujuanID uint) (*models.TahunPelajaran, *models.TahunPelajaran, error) {
+	if asalID == tujuanID {
+		return nil, nil, errors.New("tahun pelajaran asal dan tujuan tidak boleh sama")
+	}
+	asal, err := s.repository.GetTahunPelajaranByID(asalID)
+	if err != nil {
+		return nil, nil, errors.New("tahun pelajaran asal tidak ditemukan")
+	}
+	tujuan, err := s.repository.GetTahunPelajaranByID(tujuanID)
+	if err != nil {
+		return nil, nil, errors.New("tahun pelajaran tujuan tidak ditemukan")
+	}
+	if asal.Semester1Mulai != nil && tujuan.Semester1Mulai != nil && !tujuan.Semester1Mulai.After(*asal.Semester1Mulai) {
+		return nil, nil, errors.New("tahun pelajaran tujuan harus setelah tahun pelajaran asal")
+	}
+	return asal, tujuan, nil
+}
+
+// loadRombel loads the active rombel with their kelas level and the highest level found
+func (s *KenaikanKelasServiceImpl) loadRombel() ([]rombelKenaikan, int, error) {
+	data, err := s.repository.GetRombelAktif()
+	if err != nil {
+		return nil, 0, errors.New("gagal mengambil data rombel")
+	}
+
+	tingkatAkhir := 0
+	rombels := make([]rombelKenaikan, len(data))
+	for i, rombel := range data {
+		tingkat := 0
+		if rombel.Kelas != nil {
+			tingkat = tingkatKelas(rombel.Kelas.Name)
+		}
+		if tingkat == 0 {
+			tingkat = tingkatKelas(rombel.Name)
+		}
+		if tingkat > tingkatAkhir {
+			tingkatAkhir = tingkat
+		}
+		rombels[i] = rombelKenaikan{rombel: rombel, tingkat: tingkat, akhiran: akhiranRombel(rombel.Name)}
+	}
+	return rombels, tingkatAkhir, nil
+}
+
+// bisaDibatalkan reports whether a rollover is the latest one and was processed today (WIB)
+func (s *KenaikanKelasServiceImpl) bisaDibatalkan(data *models.KenaikanKelas) bool {
+	latest, err := s.repository.GetLatestDiproses()
+	if err != nil || latest.ID != data.ID {
+		return false
+	}
+	today := time.Now().In(utils.JakartaLocation()).Format("2006-01-02")
+	return data.DiprosesAt.In(utils.JakartaLocation()).Format("2006-01-02") == today
+}
+
+// mapToResponse maps model to DTO response
+func (s *KenaikanKelasServiceImpl) mapToResponse(data *models.KenaikanKelas, latestID uint) *dtos.KenaikanKelasResponse {
+	response := &dtos.KenaikanKelasResponse{
+		ID:                     data.ID,
+		TahunPelajaranAsalID:   data.TahunPelajaranAsalID,
+		TahunPelajaranTujuanID: data.TahunPelajaranTujuanID,
+		Status:                 data.Status,
+		TotalNaik:              data.TotalNaik,
+		TotalTinggalKelas:      data.TotalTinggalKelas,
+		TotalLulus:             data.TotalLulus,
+		DiprosesAt:             data.DiprosesAt.In(utils.JakartaLocation()).Format("2006-01-02 15:04:05"),
+		CreatedByID:            data.CreatedByID,
+		CreatedByType:          data.CreatedByType,
+	}
+	if data.TahunPelajaranAsal != nil {
+		response.TahunPelajaranAsal = data.TahunPelajaranAsal.TahunPelajaran
+	}
+	if data.TahunPelajaranTujuan != nil {
+		response.TahunPelajaranTujuan = data.TahunPelajaranTujuan.TahunPelajaran
+	}
+	if data.DibatalkanAt != nil {
+		dibatalkanAt := data.DibatalkanAt.In(utils.JakartaLocation()).Format("2006-01-02 15:04:05")
+		response.DibatalkanAt = &dibatalkanAt
+	}
+	today := time.Now().In(utils.JakartaLocation()).Format("2006-01-02")
+	response.BisaDibatalkan = data.Status == models.KenaikanKelasStatusDiproses && data.ID == latestID &&
+		data.DiprosesAt.In(utils.JakartaLocation()).Format("2006-01-02") == today
+	return response
+}
+
+// usulanMappingRombel maps every rombel below the highest kelas to a rombel one kelas higher with the same
+// letter, or to the only rombel of that kelas
+func usulanMappingRombel(rombels []rombelKenaikan, tingkatAkhir int) map[uint]models.Rombel {
+	mapping := make(map[uint]models.Rombel)
+	for _, asal := range rombels {
+		if asal.tingkat == 0 || asal.tingkat >= tingkatAkhir {
+			continue
+		}
+		var kandidat []rombelKenaikan
+		for _, next := range rombels {
+			if next.tingkat == asal.tingkat+1 {
+				kandidat = append(kandidat, next)
+			}
+		}
+		for _, next := range kandidat {
+			if next.akhiran == asal.akhiran {
+				mapping[asal.rombel.ID] = next.rombel
+				break
+			}
+		}
+		if _, ok := mapping[asal.rombel.ID]; !ok && len(kandidat) == 1 {
+			mapping[asal.rombel.ID] = kandidat[0].rombel
+		}
+	}
+	return mapping
+}
+
+var (
+	angkaKelasPattern   = regexp.MustCompile(`\d+`)
+	romawiKelasPattern  = regexp.MustCompile(`(?i)\b(VI|V|IV|III|II|I)\b`)
+	awalanKelasPattern  = regexp.MustCompile(`(?i)^\s*kelas\b`)
+	bukanHurufAtauAngka = regexp.MustCompile(`[^A-Za-z0-9]+`)
+)
+
+// romawiKelas maps the roman numerals used for SD kelas
+var romawiKelas = map[string]int{"I": 1, "II": 2, "III": 3, "IV": 4, "V": 5, "VI": 6}
+
+// tingkatKelas parses the level of a kelas or rombel name such as "Kelas 1", "1A" or "Kelas IV", 0 when none
+func tingkatKelas(name string) int {
+	if match := angkaKelasPattern.FindString(name); match != "" {
+		tingkat, _ := strconv.Atoi(match)
+		return tingkat
+	}
+	if match := romawiKelasPattern.FindString(name); match != "" {
+		return romawiKelas[strings.ToUpper(match)]
+	}
+	return 0
+}
+
+// akhiranRombel is a rombel name without "Kelas" and its level, e.g. "A" for "1A" and "Kelas 2 - A"
+func akhiranRombel(name string) string {
+	name = awalanKelasPattern.ReplaceAllString(name, "")
+	if loc := angkaKelasPattern.FindStringIndex(name); loc != nil {
+		name = name[:loc[0]] + name[loc[1]:]
+	} else if loc := romawiKelasPattern.FindStringIndex(name); loc != nil {
+		name = name[:loc[0]] + name[loc[1]:]
+	}
+	return strings.ToUpper(bukanHurufAtauAngka.ReplaceAllString(name, ""))
+}
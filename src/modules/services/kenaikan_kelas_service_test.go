@@ -0,0 +1,289 @@
+package services
+
+import (
+	"errors"
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+	"testing"
+	"time"
+)
+
+// fakeKenaikanKelasRepository holds the rombel and students of the source year and the processed rollovers
+type fakeKenaikanKelasRepository struct {
+	repositories.KenaikanKelasRepository
+	rombel       []models.Rombel
+	members      []models.PesertaDidikRombel
+	tujuanTerisi int64
+	kenaikan     []models.KenaikanKelas
+	dibatalkan   *models.KenaikanKelas
+	// batalMeanwhile simulates another request undoing the rollover after the service checked it
+	batalMeanwhile bool
+}
+
+func (r *fakeKenaikanKelasRepository) GetTahunPelajaranByID(id uint) (*models.TahunPelajaran, error) {
+	switch id {
+	case 1:
+		return &models.TahunPelajaran{ID: 1, TahunPelajaran: "2025/2026"}, nil
+	case 2:
+		return &models.TahunPelajaran{ID: 2, TahunPelajaran: "2026/2027"}, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeKenaikanKelasRepository) GetActiveTahunPelajaran() (*models.TahunPelajaran, error) {
+	return r.GetTahunPelajaranByID(1)
+}
+
+func (r *fakeKenaikanKelasRepository) GetRombelAktif() ([]models.Rombel, error) {
+	return r.rombel, nil
+}
+
+func (r *fakeKenaikanKelasRepository) GetPesertaDidikRombelAktif(tahunPelajaranID uint) ([]models.PesertaDidikRombel, error) {
+	return r.members, nil
+}
+
+func (r *fakeKenaikanKelasRepository) CountPesertaDidikRombel(tahunPelajaranID uint) (int64, error) {
+	return r.tujuanTerisi, nil
+}
+
+func (r *fakeKenaikanKelasRepository) Proses(data *models.KenaikanKelas) error {
+	data.ID = uint(len(r.kenaikan) + 1)
+	r.kenaikan = append(r.kenaikan, *data)
+	return nil
+}
+
+func (r *fakeKenaikanKelasRepository) Batalkan(data *models.KenaikanKelas, actorID *uint, actorType *string) error {
+	if r.batalMeanwhile || r.kenaikan[data.ID-1].Status != models.KenaikanKelasStatusDiproses {
+		return repositories.ErrKenaikanKelasDibatalkan
+	}
+	r.dibatalkan = data
+	r.kenaikan[data.ID-1].Status = models.KenaikanKelasStatusDibatalkan
+	return nil
+}
+
+func (r *fakeKenaikanKelasRepository) GetByID(id uint) (*models.KenaikanKelas, error) {
+	if id == 0 || int(id) > len(r.kenaikan) {
+		return nil, errors.New("record not found")
+	}
+	data := r.kenaikan[id-1]
+	return &data, nil
+}
+
+func (r *fakeKenaikanKelasRepository) GetLatestDiproses() (*models.KenaikanKelas, error) {
+	for i := len(r.kenaikan) - 1; i >= 0; i-- {
+		if r.kenaikan[i].Status == models.KenaikanKelasStatusDiproses {
+			data := r.kenaikan[i]
+			return &data, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+// testRombelKenaikan returns rombel 1A (ID 1), 1B (2), Kelas II - A (3), Kelas II - B (4), 3 (5) and 6A (6)
+func testRombelKenaikan() []models.Rombel {
+	kelas := func(id uint, name string) *models.Kelas { return &models.Kelas{ID: id, Name: name} }
+	return []models.Rombel{
+		{ID: 1, Name: "1A", KelasID: 1, Kelas: kelas(1, "Kelas 1")},
+		{ID: 2, Name: "1B", KelasID: 1, Kelas: kelas(1, "Kelas 1")},
+		{ID: 3, Name: "Kelas II - A", KelasID: 2, Kelas: kelas(2, "Kelas II")},
+		{ID: 4, Name: "Kelas II - B", KelasID: 2, Kelas: kelas(2, "Kelas II")},
+		{ID: 5, Name: "3", KelasID: 3, Kelas: kelas(3, "Kelas 3")},
+		{ID: 6, Name: "6A", KelasID: 6, Kelas: kelas(6, "Kelas 6")},
+	}
+}
+
+func TestTingkatKelas(t *testing.T) {
+	tests := []struct {
+		name        string
+		wantTingkat int
+		wantAkhiran string
+	}{
+		{"1A", 1, "A"},
+		{"Kelas 2 - B", 2, "B"},
+		{"Kelas IV", 4, ""},
+		{"kelas vi c", 6, "C"},
+		{"Perpustakaan", 0, "PERPUSTAKAAN"},
+	}
+
+	for _, tt := range tests {
+		if got := tingkatKelas(tt.name); got != tt.wantTingkat {
+			t.Errorf("tingkatKelas(%q) = %d, want %d", tt.name, got, tt.wantTingkat)
+		}
+		if got := akhiranRombel(tt.name); got != tt.wantAkhiran {
+			t.Errorf("akhiranRombel(%q) = %q, want %q", tt.name, got, tt.wantAkhiran)
+		}
+	}
+}
+
+func TestUsulanMappingRombel(t *testing.T) {
+	service := &KenaikanKelasServiceImpl{repository: &fakeKenaikanKelasRepository{rombel: testRombelKenaikan()}}
+	rombels, tingkatAkhir, err := service.loadRombel()
+	if err != nil {
+		t.Fatalf("loadRombel() error = %v", err)
+	}
+	if tingkatAkhir != 6 {
+		t.Fatalf("tingkatAkhir = %d, want 6", tingkatAkhir)
+	}
+
+	mapping := usulanMappingRombel(rombels, tingkatAkhir)
+	// 1A and 1B keep their letter, Kelas II - A and II - B go to the only rombel of kelas 3,
+	// kelas 3 has no kelas 4 rombel and 6A graduates
+	want := map[uint]uint{1: 3, 2: 4, 3: 5, 4: 5}
+	if len(mapping) != len(want) {
+		t.Errorf("mapping = %d rombel, want %d", len(mapping), len(want))
+	}
+	for asal, tujuan := range want {
+		if got, ok := mapping[asal]; !ok || got.ID != tujuan {
+			t.Errorf("rombel %d maps to %d (%v), want %d", asal, got.ID, ok, tujuan)
+		}
+	}
+}
+
+func TestProsesKenaikanKelas(t *testing.T) {
+	rombels := testRombelKenaikan()
+	member := func(id, rombelID uint) models.PesertaDidikRombel {
+		return models.PesertaDidikRombel{
+			ID: id, PesertaDidikID: id * 10, RombelID: rombelID, TahunPelajaranID: 1,
+			Rombel: &rombels[rombelID-1], PesertaDidik: &models.PesertaDidik{ID: id * 10, Status: "active"},
+		}
+	}
+	members := []models.PesertaDidikRombel{member(1, 1), member(2, 1), member(3, 6), member(4, 5)}
+	kelas2B := uint(4)
+
+	tests := []struct {
+		name         string
+		req          dtos.KenaikanKelasRequest
+		tujuanTerisi int64
+		wantErr      bool
+		wantRombel   map[uint]*uint // peserta didik rombel asal -> rombel tujuan, nil for lulus
+	}{
+		{
+			name:    "kelas 3 has no next rombel",
+			req:     dtos.KenaikanKelasRequest{TahunPelajaranAsalID: 1, TahunPelajaranTujuanID: 2},
+			wantErr: true,
+		},
+		{
+			name: "mapped, tinggal kelas and lulus",
+			req: dtos.KenaikanKelasRequest{
+				TahunPelajaranAsalID: 1, TahunPelajaranTujuanID: 2,
+				KeputusanSiswa: []dtos.KenaikanKelasKeputusanSiswa{
+					{PesertaDidikRombelID: 2, Keputusan: models.KeputusanNaik, RombelTujuanID: &kelas2B},
+					{PesertaDidikRombelID: 4, Keputusan: models.KeputusanTinggalKelas},
+				},
+			},
+			wantRombel: map[uint]*uint{1: uintPtr(3), 2: uintPtr(4), 3: nil, 4: uintPtr(5)},
+		},
+		{
+			name: "target year already filled",
+			req: dtos.KenaikanKelasRequest{
+				TahunPelajaranAsalID: 1, TahunPelajaranTujuanID: 2,
+				KeputusanSiswa: []dtos.KenaikanKelasKeputusanSiswa{{PesertaDidikRombelID: 4, Keputusan: models.KeputusanTinggalKelas}},
+			},
+			tujuanTerisi: 12,
+			wantErr:      true,
+		},
+		{
+			name:    "same source and target year",
+			req:     dtos.KenaikanKelasRequest{TahunPelajaranAsalID: 1, TahunPelajaranTujuanID: 1},
+			wantErr: true,
+		},
+		{
+			name: "student of another year",
+			req: dtos.KenaikanKelasRequest{
+				TahunPelajaranAsalID: 1, TahunPelajaranTujuanID: 2,
+				KeputusanSiswa: []dtos.KenaikanKelasKeputusanSiswa{{PesertaDidikRombelID: 99, Keputusan: models.KeputusanLulus}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeKenaikanKelasRepository{rombel: rombels, members: members, tujuanTerisi: tt.tujuanTerisi}
+			service := NewKenaikanKelasService(repository)
+
+			response, err := service.Proses(&tt.req, utils.Principal{ID: 1, Type: utils.PrincipalUser})
+			if tt.wantErr {
+				if err == nil || len(repository.kenaikan) != 0 {
+					t.Fatalf("Proses() error = %v, processed %d, want an error and nothing processed", err, len(repository.kenaikan))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Proses() error = %v", err)
+			}
+
+			if response.TotalNaik != 2 || response.TotalTinggalKelas != 1 || response.TotalLulus != 1 || !response.BisaDibatalkan {
+				t.Errorf("totals = naik %d, tinggal kelas %d, lulus %d, bisa dibatalkan %v, want 2, 1, 1, true",
+					response.TotalNaik, response.TotalTinggalKelas, response.TotalLulus, response.BisaDibatalkan)
+			}
+			processed := repository.kenaikan[0]
+			if *processed.TahunPelajaranAktifSebelumnyaID != 1 {
+				t.Errorf("TahunPelajaranAktifSebelumnyaID = %d, want 1", *processed.TahunPelajaranAktifSebelumnyaID)
+			}
+			for _, detail := range processed.Detail {
+				want := tt.wantRombel[detail.PesertaDidikRombelAsalID]
+				if (want == nil) != (detail.RombelTujuanID == nil) || (want != nil && *want != *detail.RombelTujuanID) {
+					t.Errorf("peserta didik rombel %d goes to %v, want %v", detail.PesertaDidikRombelAsalID, detail.RombelTujuanID, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBatalkanKenaikanKelas(t *testing.T) {
+	now := time.Now()
+	kenaikan := func(status string, diprosesAt time.Time) models.KenaikanKelas {
+		return models.KenaikanKelas{TahunPelajaranAsalID: 1, TahunPelajaranTujuanID: 2, Status: status, DiprosesAt: diprosesAt}
+	}
+
+	tests := []struct {
+		name           string
+		kenaikan       []models.KenaikanKelas
+		id             uint
+		batalMeanwhile bool
+		wantErr        bool
+	}{
+		{"latest processed today", []models.KenaikanKelas{kenaikan(models.KenaikanKelasStatusDiproses, now)}, 1, false, false},
+		{"processed yesterday", []models.KenaikanKelas{kenaikan(models.KenaikanKelasStatusDiproses, now.AddDate(0, 0, -1))}, 1, false, true},
+		{"not the latest", []models.KenaikanKelas{kenaikan(models.KenaikanKelasStatusDiproses, now), kenaikan(models.KenaikanKelasStatusDiproses, now)}, 1, false, true},
+		{"already undone", []models.KenaikanKelas{kenaikan(models.KenaikanKelasStatusDibatalkan, now)}, 1, false, true},
+		{"undone concurrently", []models.KenaikanKelas{kenaikan(models.KenaikanKelasStatusDiproses, now)}, 1, true, true},
+		{"unknown", nil, 1, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &fakeKenaikanKelasRepository{}
+			for i := range tt.kenaikan {
+				tt.kenaikan[i].ID = uint(i + 1)
+			}
+			repository.kenaikan = tt.kenaikan
+			repository.batalMeanwhile = tt.batalMeanwhile
+			service := NewKenaikanKelasService(repository)
+
+			response, err := service.Batalkan(tt.id, utils.Principal{ID: 1, Type: utils.PrincipalUser})
+			if tt.wantErr {
+				if err == nil || repository.dibatalkan != nil {
+					t.Fatalf("Batalkan() error = %v, undone %v, want an error and nothing undone", err, repository.dibatalkan != nil)
+				}
+				if tt.batalMeanwhile && !errors.Is(err, repositories.ErrKenaikanKelasDibatalkan) {
+					t.Errorf("Batalkan() error = %v, want %v", err, repositories.ErrKenaikanKelasDibatalkan)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Batalkan() error = %v", err)
+			}
+			if response.Status != models.KenaikanKelasStatusDibatalkan || response.BisaDibatalkan {
+				t.Errorf("status = %s, bisa dibatalkan %v, want dibatalkan and false", response.Status, response.BisaDibatalkan)
+			}
+		})
+	}
+}
+
+func uintPtr(value uint) *uint {
+	return &value
+}
@@ -1,51 +1,58 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"mime/multipart"
 	"strings"
-	"sync"
 
 	"pintu-backend/src/dtos"
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
 	"pintu-backend/src/utils"
+	qrcode "github.com/skip2/go-qrcode"
 	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/sync/errgroup"
 	"gorm.io/datatypes"
 )
 
 type KepegawaianService interface {
-	Create(req *dtos.KepegawaianCreateRequest, userID uint) (*dtos.KepegawaianResponse, error)
+	Create(req *dtos.KepegawaianCreateRequest, actor utils.Principal) (*dtos.KepegawaianResponse, error)
 	GetByID(id uint) (*dtos.KepegawaianResponse, error)
 	GetByNIP(nip string) (*dtos.KepegawaianResponse, error)
 	GetAll(limit int, offset int) (*dtos.KepegawaianListResponse, error)
 	GetAllWithFilter(params repositories.GetKepegawaianParams) (*dtos.KepegawaianListWithPaginationResponse, error)
 	GetAllWithoutPagination() ([]dtos.KepegawaianResponse, error)
-	Update(id uint, foto *multipart.FileHeader, docs map[string][]*multipart.FileHeader, req *dtos.KepegawaianUpdateRequest, userID uint) (*dtos.KepegawaianResponse, error)
+	Update(ctx context.Context, id uint, foto *multipart.FileHeader, docs map[string][]*multipart.FileHeader, req *dtos.KepegawaianUpdateRequest, actor utils.Principal) (*dtos.KepegawaianResponse, error)
 	Delete(id uint) error
 	GetTotalPendidik() (*dtos.TotalPendidikResponse, error)
 	GetTotalTendik() (*dtos.TotalTendikResponse, error)
 	GetPublicPendidikData() (*dtos.PublicPendidikListResponse, error)
 	GetPublicTendikData() (*dtos.PublicTendikListResponse, error)
+	GenerateBarcodeAllKepegawaian() (*dtos.GenerateBarcodeResponse, error)
+	GenerateBarcodeKepegawaianByID(id uint) (*dtos.GenerateBarcodeResponse, error)
+	DownloadBarcodeKepegawaian(id uint) ([]byte, string, error)
 }
 
 type KepegawaianServiceImpl struct {
-	repository repositories.KepegawaianRepository
-	r2Storage  *utils.R2Storage
+	repository      repositories.KepegawaianRepository
+	tokenRepository repositories.AuthTokenRepository
+	storage         utils.Storage
 }
 
 // NewKepegawaianService creates a new Kepegawaian service
-func NewKepegawaianService(repository repositories.KepegawaianRepository, r2Storage *utils.R2Storage) KepegawaianService {
+func NewKepegawaianService(repository repositories.KepegawaianRepository, tokenRepository repositories.AuthTokenRepository, storage utils.Storage) KepegawaianService {
 	return &KepegawaianServiceImpl{
-		repository: repository,
-		r2Storage:  r2Storage,
+		repository:      repository,
+		tokenRepository: tokenRepository,
+		storage:         storage,
 	}
 }
 
 // Create creates a new Kepegawaian (without file uploads)
-func (s *KepegawaianServiceImpl) Create(req *dtos.KepegawaianCreateRequest, userID uint) (*dtos.KepegawaianResponse, error) {
+func (s *KepegawaianServiceImpl) Create(req *dtos.KepegawaianCreateRequest, actor utils.Principal) (*dtos.KepegawaianResponse, error) {
 	// Check if NIP already exists (only if NIP is provided)
 	if req.NIP != "" {
 		existing, _ := s.repository.GetByNIP(req.NIP)
@@ -96,7 +103,8 @@ func (s *KepegawaianServiceImpl) Create(req *dtos.KepegawaianCreateRequest, user
 		RombelGuruKelasID: req.RombelGuruKelasID,
 		RombelBidangStudi: rombelBidangStudiJSON,
 		Status:            status,
-		CreatedByID:       &userID,
+		CreatedByID:       &actor.ID,
+		CreatedByType:     actor.TypePtr(),
 	}
 
 	if err := s.repository.Create(data); err != nil {
@@ -216,7 +224,7 @@ func (s *KepegawaianServiceImpl) GetAllWithoutPagination() ([]dtos.KepegawaianRe
 }
 
 // Update updates Kepegawaian
-func (s *KepegawaianServiceImpl) Update(id uint, foto *multipart.FileHeader, docs map[string][]*multipart.FileHeader, req *dtos.KepegawaianUpdateRequest, userID uint) (*dtos.KepegawaianResponse, error) {
+func (s *KepegawaianServiceImpl) Update(ctx context.Context, id uint, foto *multipart.FileHeader, docs map[string][]*multipart.FileHeader, req *dtos.KepegawaianUpdateRequest, actor utils.Principal) (*dtos.KepegawaianResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(id)
 	if err != nil {
@@ -225,6 +233,12 @@ func (s *KepegawaianServiceImpl) Update(id uint, foto *multipart.FileHeader, doc
 
 	oldFoto := existing.Foto
 
+	// New uploads are deleted when the update fails, the files they replace once it is saved
+	var uploadedKeys, replacedKeys []string
+
+	// Deactivation and password changes end every existing session
+	revokeSessions := req.Password != "" || (req.Status != "" && req.Status != "active" && req.Status != existing.Status)
+
 	// Update basic fields if provided
 	if req.Nama != "" {
 		existing.Nama = req.Nama
@@ -292,32 +306,19 @@ func (s *KepegawaianServiceImpl) Update(id uint, foto *multipart.FileHeader, doc
 
 	// Update foto if provided
 	if foto != nil {
-		if foto.Size > 5*1024*1024 { // 5MB
-			return nil, errors.New("foto size must not exceed 5MB")
-		}
-
-		allowedTypes := map[string]bool{
-			"image/jpeg": true,
-			"image/png":  true,
-			"image/gif":  true,
-			"image/webp": true,
-		}
-		contentType := foto.Header.Get("Content-Type")
-		if !allowedTypes[contentType] {
-			return nil, errors.New("only image files are allowed for foto (jpeg, png, gif, webp)")
-		}
-
-		newFileKey, err := s.r2Storage.UploadFile(foto, "kepegawaian/foto")
+		// Validate the file from its content and upload to R2
+		stored, err := utils.SaveUpload(ctx, s.storage, foto, utils.UploadCategoryFoto, "kepegawaian/foto")
 		if err != nil {
 			return nil, err
 		}
+		uploadedKeys = append(uploadedKeys, stored.Key)
 
-		// Delete old foto if exists
+		// Delete old foto once saved
 		if oldFoto != "" {
-			_ = s.r2Storage.DeleteFile(oldFoto)
+			replacedKeys = append(replacedKeys, oldFoto)
 		}
 
-		existing.Foto = newFileKey
+		existing.Foto = stored.Key
 	}
 
 	// Delete files if specified
@@ -326,62 +327,62 @@ func (s *KepegawaianServiceImpl) Update(id uint, foto *multipart.FileHeader, doc
 			switch fileType {
 			case "kk":
 				if existing.KK != "" {
-					_ = s.r2Storage.DeleteFile(existing.KK)
+					_ = s.storage.DeleteFile(existing.KK)
 					existing.KK = ""
 				}
 			case "akta_lahir":
 				if existing.AktaLahir != "" {
-					_ = s.r2Storage.DeleteFile(existing.AktaLahir)
+					_ = s.storage.DeleteFile(existing.AktaLahir)
 					existing.AktaLahir = ""
 				}
 			case "ktp":
 				if existing.KTP != "" {
-					_ = s.r2Storage.DeleteFile(existing.KTP)
+					_ = s.storage.DeleteFile(existing.KTP)
 					existing.KTP = ""
 				}
 			case "ijazah_sd":
 				if existing.IjazahSD != "" {
-					_ = s.r2Storage.DeleteFile(existing.IjazahSD)
+					_ = s.storage.DeleteFile(existing.IjazahSD)
 					existing.IjazahSD = ""
 				}
 			case "ijazah_smp":
 				if existing.IjazahSMP != "" {
-					_ = s.r2Storage.DeleteFile(existing.IjazahSMP)
+					_ = s.storage.DeleteFile(existing.IjazahSMP)
 					existing.IjazahSMP = ""
 				}
 			case "ijazah_sma":
 				if existing.IjazahSMA != "" {
-					_ = s.r2Storage.DeleteFile(existing.IjazahSMA)
+					_ = s.storage.DeleteFile(existing.IjazahSMA)
 					existing.IjazahSMA = ""
 				}
 			case "ijazah_s1":
 				if existing.IjazahS1 != "" {
-					_ = s.r2Storage.DeleteFile(existing.IjazahS1)
+					_ = s.storage.DeleteFile(existing.IjazahS1)
 					existing.IjazahS1 = ""
 				}
 			case "ijazah_s2":
 				if existing.IjazahS2 != "" {
-					_ = s.r2Storage.DeleteFile(existing.IjazahS2)
+					_ = s.storage.DeleteFile(existing.IjazahS2)
 					existing.IjazahS2 = ""
 				}
 			case "ijazah_s3":
 				if existing.IjazahS3 != "" {
-					_ = s.r2Storage.DeleteFile(existing.IjazahS3)
+					_ = s.storage.DeleteFile(existing.IjazahS3)
 					existing.IjazahS3 = ""
 				}
 			case "sertifikat_pendidik":
 				if existing.SertifikatPendidik != "" {
-					_ = s.r2Storage.DeleteFile(existing.SertifikatPendidik)
+					_ = s.storage.DeleteFile(existing.SertifikatPendidik)
 					existing.SertifikatPendidik = ""
 				}
 			case "sk":
 				if existing.SK != "" {
-					_ = s.r2Storage.DeleteFile(existing.SK)
+					_ = s.storage.DeleteFile(existing.SK)
 					existing.SK = ""
 				}
 			case "foto":
 				if existing.Foto != "" {
-					_ = s.r2Storage.DeleteFile(existing.Foto)
+					_ = s.storage.DeleteFile(existing.Foto)
 					existing.Foto = ""
 				}
 			}
@@ -398,69 +399,72 @@ func (s *KepegawaianServiceImpl) Update(id uint, foto *multipart.FileHeader, doc
 
 	// Update documents if provided (parallel)
 	if len(docs) > 0 {
-		uploadResults := s.uploadDocumentsParallel(docs)
+		uploadResults, err := s.uploadDocumentsParallel(ctx, docs)
+		if err != nil {
+			deleteFileKeys(s.storage, uploadedKeys)
+			return nil, err
+		}
 		
 		for docType, result := range uploadResults {
-			if result.err != nil {
-				return nil, result.err
-			}
+			uploadedKeys = append(uploadedKeys, result.fileKey)
+			uploadedKeys = append(uploadedKeys, result.fileKeys...)
 
-			if docType != "sertifikat_lainnya" && docType != "dokumen_lainnya" {
+			if !isMultiFileDocument(docType) {
 				// Single file result
 				switch docType {
 				case "kk":
 					if existing.KK != "" {
-						_ = s.r2Storage.DeleteFile(existing.KK)
+						replacedKeys = append(replacedKeys, existing.KK)
 					}
 					existing.KK = result.fileKey
 				case "akta_lahir":
 					if existing.AktaLahir != "" {
-						_ = s.r2Storage.DeleteFile(existing.AktaLahir)
+						replacedKeys = append(replacedKeys, existing.AktaLahir)
 					}
 					existing.AktaLahir = result.fileKey
 				case "ktp":
 					if existing.KTP != "" {
-						_ = s.r2Storage.DeleteFile(existing.KTP)
+						replacedKeys = append(replacedKeys, existing.KTP)
 					}
 					existing.KTP = result.fileKey
 				case "ijazah_sd":
 					if existing.IjazahSD != "" {
-						_ = s.r2Storage.DeleteFile(existing.IjazahSD)
+						replacedKeys = append(replacedKeys, existing.IjazahSD)
 					}
 					existing.IjazahSD = result.fileKey
 				case "ijazah_smp":
 					if existing.IjazahSMP != "" {
-						_ = s.r2Storage.DeleteFile(existing.IjazahSMP)
+						replacedKeys = append(replacedKeys, existing.IjazahSMP)
 					}
 					existing.IjazahSMP = result.fileKey
 				case "ijazah_sma":
 					if existing.IjazahSMA != "" {
-						_ = s.r2Storage.DeleteFile(existing.IjazahSMA)
+						replacedKeys = append(replacedKeys, existing.IjazahSMA)
 					}
 					existing.IjazahSMA = result.fileKey
 				case "ijazah_s1":
 					if existing.IjazahS1 != "" {
-						_ = s.r2Storage.DeleteFile(existing.IjazahS1)
+						replacedKeys = append(replacedKeys, existing.IjazahS1)
 					}
 					existing.IjazahS1 = result.fileKey
 				case "ijazah_s2":
 					if existing.IjazahS2 != "" {
-						_ = s.r2Storage.DeleteFile(existing.IjazahS2)
+						replacedKeys = append(replacedKeys, existing.IjazahS2)
 					}
 					existing.IjazahS2 = result.fileKey
 				case "ijazah_s3":
 					if existing.IjazahS3 != "" {
-						_ = s.r2Storage.DeleteFile(existing.IjazahS3)
+						replacedKeys = append(replacedKeys, existing.IjazahS3)
 					}
 					existing.IjazahS3 = result.fileKey
 				case "sertifikat_pendidik":
 					if existing.SertifikatPendidik != "" {
-						_ = s.r2Storage.DeleteFile(existing.SertifikatPendidik)
+						replacedKeys = append(replacedKeys, existing.SertifikatPendidik)
 					}
 					existing.SertifikatPendidik = result.fileKey
 				case "sk":
 					if existing.SK != "" {
-						_ = s.r2Storage.DeleteFile(existing.SK)
+						replacedKeys = append(replacedKeys, existing.SK)
 					}
 					existing.SK = result.fileKey
 				}
@@ -483,16 +487,26 @@ func (s *KepegawaianServiceImpl) Update(id uint, foto *multipart.FileHeader, doc
 		}
 	}
 
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	if err := s.repository.Update(existing); err != nil {
+		deleteFileKeys(s.storage, uploadedKeys)
 		return nil, err
 	}
+	deleteFileKeys(s.storage, replacedKeys)
 
 	// Assign roles (clear existing and assign new ones)
 	if err := s.repository.AssignRoles(id, req.RoleIDs); err != nil {
 		return nil, err
 	}
+	utils.InvalidatePermissionCache()
+
+	if revokeSessions {
+		if err := s.tokenRepository.RevokeAllForPrincipal(utils.PrincipalPegawai, id); err != nil {
+			return nil, errors.New("failed to revoke sessions")
+		}
+	}
 
 	return s.mapToResponse(existing), nil
 }
@@ -507,14 +521,21 @@ func (s *KepegawaianServiceImpl) Delete(id uint) error {
 
 	// Delete foto from R2
 	if existing.Foto != "" {
-		_ = s.r2Storage.DeleteFile(existing.Foto)
+		_ = s.storage.DeleteFile(existing.Foto)
 	}
 
 	// Delete all document files from R2
 	s.deleteDocumentFiles(existing)
 
 	// Delete from database
-	return s.repository.Delete(id)
+	if err := s.repository.Delete(id); err != nil {
+		return err
+	}
+
+	if err := s.tokenRepository.RevokeAllForPrincipal(utils.PrincipalPegawai, id); err != nil {
+		return errors.New("failed to revoke sessions")
+	}
+	return nil
 }
 
 // Helper function to delete all document files
@@ -524,7 +545,7 @@ func (s *KepegawaianServiceImpl) deleteDocumentFiles(data *models.Kepegawaian) {
 		data.IjazahSMA, data.IjazahS1, data.IjazahS2, data.IjazahS3, data.SertifikatPendidik, data.SK}
 	for _, file := range files {
 		if file != "" {
-			_ = s.r2Storage.DeleteFile(file)
+			_ = s.storage.DeleteFile(file)
 		}
 	}
 
@@ -533,7 +554,7 @@ func (s *KepegawaianServiceImpl) deleteDocumentFiles(data *models.Kepegawaian) {
 	json.Unmarshal(data.SertifikatLainnya, &certFiles)
 	for _, file := range certFiles {
 		if file != "" {
-			_ = s.r2Storage.DeleteFile(file)
+			_ = s.storage.DeleteFile(file)
 		}
 	}
 
@@ -541,7 +562,7 @@ func (s *KepegawaianServiceImpl) deleteDocumentFiles(data *models.Kepegawaian) {
 	json.Unmarshal(data.DokumenLainnya, &dokFiles)
 	for _, file := range dokFiles {
 		if file != "" {
-			_ = s.r2Storage.DeleteFile(file)
+			_ = s.storage.DeleteFile(file)
 		}
 	}
 }
@@ -581,7 +602,7 @@ func (s *KepegawaianServiceImpl) deleteDocumentsFromJSONB(jsonbField *datatypes.
 
 		if shouldDelete {
 			// Delete from R2
-			_ = s.r2Storage.DeleteFile(file)
+			_ = s.storage.DeleteFile(file)
 		} else {
 			// Keep this file
 			remainingFiles = append(remainingFiles, file)
@@ -643,52 +664,58 @@ func (s *KepegawaianServiceImpl) mapToResponse(data *models.Kepegawaian) *dtos.K
 		}
 
 		roles[i] = dtos.RoleResponse{
-			ID:          role.ID,
-			Name:        role.Name,
-			Description: role.Description,
-			SystemID:    role.SystemID,
-			System:      system,
-			Status:      role.Status,
-			CreatedAt:   role.CreatedAt,
-			UpdatedAt:   role.UpdatedAt,
-			CreatedByID: role.CreatedByID,
-			UpdatedByID: role.UpdatedByID,
+			ID:            role.ID,
+			Name:          role.Name,
+			Description:   role.Description,
+			SystemID:      role.SystemID,
+			System:        system,
+			Status:        role.Status,
+			CreatedAt:     role.CreatedAt,
+			UpdatedAt:     role.UpdatedAt,
+			CreatedByID:   role.CreatedByID,
+			CreatedByType: role.CreatedByType,
+			UpdatedByID:   role.UpdatedByID,
+			UpdatedByType: role.UpdatedByType,
 		}
 	}
 
 	return &dtos.KepegawaianResponse{
-		ID:                    data.ID,
-		Nama:                  data.Nama,
-		Username:              data.Username,
-		NIP:                   data.NIP,
-		NKKI:                  data.NKKI,
-		Foto:                  s.stringOrNil(s.r2Storage.GetPublicURL(data.Foto)),
-		Kategori:              data.Kategori,
-		Jabatan:               data.Jabatan,
-		BidangStudiID:         data.BidangStudiID,
-		BidangStudi:           s.mapBidangStudi(data.BidangStudi),
-		RombelGuruKelasID:     data.RombelGuruKelasID,
-		RombelGuruKelas:       s.mapRombel(data.RombelGuruKelas),
-		RombelBidangStudi:     rombelBidangStudiDetails,
-		KK:                    s.stringOrNil(s.r2Storage.GetPublicURL(data.KK)),
-		AktaLahir:             s.stringOrNil(s.r2Storage.GetPublicURL(data.AktaLahir)),
-		KTP:                   s.stringOrNil(s.r2Storage.GetPublicURL(data.KTP)),
-		IjazahSD:              s.stringOrNil(s.r2Storage.GetPublicURL(data.IjazahSD)),
-		IjazahSMP:             s.stringOrNil(s.r2Storage.GetPublicURL(data.IjazahSMP)),
-		IjazahSMA:             s.stringOrNil(s.r2Storage.GetPublicURL(data.IjazahSMA)),
-		IjazahS1:              s.stringOrNil(s.r2Storage.GetPublicURL(data.IjazahS1)),
-		IjazahS2:              s.stringOrNil(s.r2Storage.GetPublicURL(data.IjazahS2)),
-		IjazahS3:              s.stringOrNil(s.r2Storage.GetPublicURL(data.IjazahS3)),
-		SertifikatPendidik:    s.stringOrNil(s.r2Storage.GetPublicURL(data.SertifikatPendidik)),
-		SertifikatLainnya:     s.mapURLsToPublic(sertifikatLainnya),
-		SK:                    s.stringOrNil(s.r2Storage.GetPublicURL(data.SK)),
-		DokumenLainnya:        s.mapURLsToPublic(dokumenLainnya),
-		Status:                data.Status,
-		Roles:                 roles,
-		CreatedAt:             data.CreatedAt,
-		UpdatedAt:             data.UpdatedAt,
-		CreatedByID:           data.CreatedByID,
-		UpdatedByID:           data.UpdatedByID,
+		ID:                 data.ID,
+		Nama:               data.Nama,
+		Username:           data.Username,
+		NIP:                data.NIP,
+		NKKI:               data.NKKI,
+		Foto:               s.stringOrNil(s.storage.GetPublicURL(data.Foto)),
+		Kategori:           data.Kategori,
+		Jabatan:            data.Jabatan,
+		BidangStudiID:      data.BidangStudiID,
+		BidangStudi:        s.mapBidangStudi(data.BidangStudi),
+		RombelGuruKelasID:  data.RombelGuruKelasID,
+		RombelGuruKelas:    s.mapRombel(data.RombelGuruKelas),
+		RombelBidangStudi:  rombelBidangStudiDetails,
+		KK:                 s.stringOrNil(utils.FileURL(s.storage, data.KK)),
+		AktaLahir:          s.stringOrNil(utils.FileURL(s.storage, data.AktaLahir)),
+		KTP:                s.stringOrNil(utils.FileURL(s.storage, data.KTP)),
+		IjazahSD:           s.stringOrNil(utils.FileURL(s.storage, data.IjazahSD)),
+		IjazahSMP:          s.stringOrNil(utils.FileURL(s.storage, data.IjazahSMP)),
+		IjazahSMA:          s.stringOrNil(utils.FileURL(s.storage, data.IjazahSMA)),
+		IjazahS1:           s.stringOrNil(utils.FileURL(s.storage, data.IjazahS1)),
+		IjazahS2:           s.stringOrNil(utils.FileURL(s.storage, data.IjazahS2)),
+		IjazahS3:           s.stringOrNil(utils.FileURL(s.storage, data.IjazahS3)),
+		SertifikatPendidik: s.stringOrNil(utils.FileURL(s.storage, data.SertifikatPendidik)),
+		SertifikatLainnya:  s.mapFileURLs(sertifikatLainnya),
+		SK:                 s.stringOrNil(utils.FileURL(s.storage, data.SK)),
+		DokumenLainnya:     s.mapFileURLs(dokumenLainnya),
+		Barcode:            data.Barcode,
+		BarcodeGeneratedAt: data.BarcodeGeneratedAt,
+		Status:             data.Status,
+		Roles:              roles,
+		CreatedAt:          data.CreatedAt,
+		UpdatedAt:          data.UpdatedAt,
+		CreatedByID:        data.CreatedByID,
+		CreatedByType:      data.CreatedByType,
+		UpdatedByID:        data.UpdatedByID,
+		UpdatedByType:      data.UpdatedByType,
 	}
 }
 
@@ -726,15 +753,15 @@ func (s *KepegawaianServiceImpl) stringOrNil(str string) *string {
 	return &str
 }
 
-// Helper function to map storage URLs to public URLs
-func (s *KepegawaianServiceImpl) mapURLsToPublic(urls []string) []string {
-	var publicURLs []string
+// Helper function to map storage keys to URLs, private documents get short-lived presigned URLs
+func (s *KepegawaianServiceImpl) mapFileURLs(urls []string) []string {
+	var fileURLs []string
 	for _, url := range urls {
 		if url != "" {
-			publicURLs = append(publicURLs, s.r2Storage.GetPublicURL(url))
+			fileURLs = append(fileURLs, utils.FileURL(s.storage, url))
 		}
 	}
-	return publicURLs
+	return fileURLs
 }
 
 // Helper function to get document folder path based on document type
@@ -771,88 +798,73 @@ func (s *KepegawaianServiceImpl) getDocumentFolderPath(docType string) string {
 	}
 }
 
+// documentUploadWorkers is the number of documents of one request uploaded at the same time
+const documentUploadWorkers = 4
+
 // uploadResult holds upload result for each document type
 type uploadResult struct {
 	fileKey  string
 	fileKeys []string
-	err      error
 }
 
-// uploadDocumentsParallel uploads multiple documents in parallel (max 5 concurrent)
-func (s *KepegawaianServiceImpl) uploadDocumentsParallel(docs map[string][]*multipart.FileHeader) map[string]uploadResult {
-	results := make(map[string]uploadResult)
-	var wg sync.WaitGroup
-	resultsMutex := sync.Mutex{}
-	
-	// Semaphore to limit concurrent uploads (max 5)
-	semaphore := make(chan struct{}, 5)
-	
+// documentUpload is one file of a document type
+type documentUpload struct {
+	docType string
+	file    *multipart.FileHeader
+}
+
+// isMultiFileDocument reports whether a document type keeps a list of files
+func isMultiFileDocument(docType string) bool {
+	return docType == "sertifikat_lainnya" || docType == "dokumen_lainnya"
+}
+
+// uploadDocumentsParallel uploads documents with a bounded pool of workers, streaming each file to storage.
+// When an upload fails or ctx is canceled the remaining uploads stop and the documents already uploaded are
+// deleted.
+func (s *KepegawaianServiceImpl) uploadDocumentsParallel(ctx context.Context, docs map[string][]*multipart.FileHeader) (map[string]uploadResult, error) {
+	var uploads []documentUpload
 	for docType, files := range docs {
-		if len(files) == 0 {
-			continue
+		// Single file documents only take the first file
+		if !isMultiFileDocument(docType) && len(files) > 1 {
+			files = files[:1]
 		}
-		
-		wg.Add(1)
-		go func(docType string, files []*multipart.FileHeader) {
-			defer wg.Done()
-			
-			// Acquire semaphore
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
-			
-			folderPath := s.getDocumentFolderPath(docType)
-			result := uploadResult{}
-			
-			// For single file documents
-			if docType != "sertifikat_lainnya" && docType != "dokumen_lainnya" {
-				if len(files) > 0 && files[0] != nil {
-					file := files[0]
-					
-					// Validate file size
-					if file.Size > 10*1024*1024 {
-						result.err = fmt.Errorf("%s size must not exceed 10MB", docType)
-					} else {
-						// Upload file to R2
-						fileKey, err := s.r2Storage.UploadFile(file, folderPath)
-						if err != nil {
-							result.err = err
-						} else {
-							result.fileKey = fileKey
-						}
-					}
-				}
-			} else {
-				// For multiple file documents
-				var fileKeys []string
-				for _, file := range files {
-					if file == nil {
-						continue
-					}
-					
-					if file.Size > 10*1024*1024 {
-						result.err = fmt.Errorf("%s size must not exceed 10MB per file", docType)
-						break
-					}
-					
-					fileKey, err := s.r2Storage.UploadFile(file, folderPath)
-					if err != nil {
-						result.err = err
-						break
-					}
-					fileKeys = append(fileKeys, fileKey)
-				}
-				result.fileKeys = fileKeys
+		for _, file := range files {
+			if file != nil {
+				uploads = append(uploads, documentUpload{docType: docType, file: file})
 			}
-			
-			// Store result safely
-			resultsMutex.Lock()
-			results[docType] = result
-			resultsMutex.Unlock()
-		}(docType, files)
+		}
 	}
-	
-	wg.Wait()
-	return results
+
+	keys := make([]string, len(uploads))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(documentUploadWorkers)
+	for i, upload := range uploads {
+		group.Go(func() error {
+			// Validate the file from its content and stream it to R2
+			stored, err := utils.SaveUpload(groupCtx, s.storage, upload.file, utils.UploadCategoryDokumen, s.getDocumentFolderPath(upload.docType))
+			if err != nil {
+				return fmt.Errorf("%s: %w", upload.docType, err)
+			}
+			keys[i] = stored.Key
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		deleteFileKeys(s.storage, keys)
+		return nil, err
+	}
+
+	results := make(map[string]uploadResult)
+	for i, upload := range uploads {
+		result := results[upload.docType]
+		if isMultiFileDocument(upload.docType) {
+			result.fileKeys = append(result.fileKeys, keys[i])
+		} else {
+			result.fileKey = keys[i]
+		}
+		results[upload.docType] = result
+	}
+	return results, nil
 }
 
 // GetTotalPendidik retrieves total count of kepegawaian with kategori "Pendidik" and status "active"
@@ -892,7 +904,7 @@ func (s *KepegawaianServiceImpl) GetPublicPendidikData() (*dtos.PublicPendidikLi
 		// Convert foto to public URL
 		fotoURL := ""
 		if item.Foto != "" {
-			fotoURL = s.r2Storage.GetPublicURL(item.Foto)
+			fotoURL = s.storage.GetPublicURL(item.Foto)
 		}
 
 		response := dtos.PublicPendidikResponse{
@@ -922,7 +934,7 @@ func (s *KepegawaianServiceImpl) GetPublicTendikData() (*dtos.PublicTendikListRe
 		// Convert foto to public URL
 		fotoURL := ""
 		if item.Foto != "" {
-			fotoURL = s.r2Storage.GetPublicURL(item.Foto)
+			fotoURL = s.storage.GetPublicURL(item.Foto)
 		}
 
 		response := dtos.PublicTendikResponse{
@@ -939,3 +951,93 @@ func (s *KepegawaianServiceImpl) GetPublicTendikData() (*dtos.PublicTendikListRe
 		Data: responses,
 	}, nil
 }
+
+// generateBarcodeKepegawaian generates a unique barcode for a kepegawaian.
+// The PGW prefix keeps it from ever matching a peserta didik barcode.
+// Format: PGW-{NIP or ID}-{10_RANDOM}
+// Example: PGW-198501012010011001-A7B9X2K4M1
+func generateBarcodeKepegawaian(data models.Kepegawaian) string {
+	identitas := data.NIP
+	if identitas == "" {
+		identitas = fmt.Sprintf("%d", data.ID)
+	}
+	return fmt.Sprintf("PGW-%s-%s", identitas, generateRandomString(10))
+}
+
+// GenerateBarcodeAllKepegawaian generates barcodes for all active kepegawaian that do not have one yet
+func (s *KepegawaianServiceImpl) GenerateBarcodeAllKepegawaian() (*dtos.GenerateBarcodeResponse, error) {
+	kepegawaianList, err := s.repository.GetAllWithoutPagination()
+	if err != nil {
+		return nil, errors.New("gagal mengambil data kepegawaian")
+	}
+
+	if len(kepegawaianList) == 0 {
+		return nil, errors.New("tidak ada kepegawaian aktif")
+	}
+
+	totalGenerated := 0
+	var errorMessages []string
+
+	for _, pegawai := range kepegawaianList {
+		// Skip jika sudah punya barcode
+		if pegawai.Barcode != "" {
+			continue
+		}
+
+		if err := s.repository.UpdateBarcode(pegawai.ID, generateBarcodeKepegawaian(pegawai)); err != nil {
+			errorMessages = append(errorMessages, fmt.Sprintf("Gagal generate barcode untuk %s: %s", pegawai.Nama, err.Error()))
+			continue
+		}
+
+		totalGenerated++
+	}
+
+	message := fmt.Sprintf("Berhasil generate barcode untuk %d kepegawaian", totalGenerated)
+	if len(errorMessages) > 0 {
+		message += fmt.Sprintf(", %d gagal", len(errorMessages))
+	}
+
+	return &dtos.GenerateBarcodeResponse{
+		TotalGenerated: totalGenerated,
+		Message:        message,
+		Errors:         errorMessages,
+	}, nil
+}
+
+// GenerateBarcodeKepegawaianByID generates or regenerates barcode for a specific kepegawaian by ID
+func (s *KepegawaianServiceImpl) GenerateBarcodeKepegawaianByID(id uint) (*dtos.GenerateBarcodeResponse, error) {
+	pegawai, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, errors.New("kepegawaian tidak ditemukan")
+	}
+
+	// Update barcode (will overwrite if exists)
+	if err := s.repository.UpdateBarcode(pegawai.ID, generateBarcodeKepegawaian(*pegawai)); err != nil {
+		return nil, fmt.Errorf("gagal generate barcode untuk %s: %s", pegawai.Nama, err.Error())
+	}
+
+	return &dtos.GenerateBarcodeResponse{
+		TotalGenerated: 1,
+		Message:        fmt.Sprintf("Berhasil generate barcode untuk kepegawaian %s", pegawai.Nama),
+		Errors:         []string{},
+	}, nil
+}
+
+// DownloadBarcodeKepegawaian renders the barcode of a kepegawaian as a QR code PNG for printing on the ID card
+func (s *KepegawaianServiceImpl) DownloadBarcodeKepegawaian(id uint) ([]byte, string, error) {
+	pegawai, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, "", errors.New("kepegawaian tidak ditemukan")
+	}
+
+	if pegawai.Barcode == "" {
+		return nil, "", errors.New("kepegawaian belum memiliki barcode, generate barcode terlebih dahulu")
+	}
+
+	png, err := qrcode.Encode(pegawai.Barcode, qrcode.Medium, 512)
+	if err != nil {
+		return nil, "", fmt.Errorf("gagal membuat QR code: %w", err)
+	}
+
+	return png, fmt.Sprintf("barcode_%s.png", strings.ReplaceAll(pegawai.Nama, " ", "_")), nil
+}
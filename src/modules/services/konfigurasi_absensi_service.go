@@ -37,6 +37,13 @@ func (s *KonfigurasiAbsensiServiceImpl) UpsertKonfigurasi(req *dtos.KonfigurasiA
 		nipKepsek = &req.NIPKepsek
 	}
 
+	// Validate hari sekolah if provided
+	if req.HariSekolah != "" {
+		if _, err := parseHariSekolah(req.HariSekolah); err != nil {
+			return nil, err
+		}
+	}
+
 	if err != nil {
 		// Record not found, create new one with ID = 1
 		data := &models.KonfigurasiAbsensi{
@@ -48,6 +55,14 @@ func (s *KonfigurasiAbsensiServiceImpl) UpsertKonfigurasi(req *dtos.KonfigurasiA
 			JamPulangSelesai: req.JamPulangSelesai,
 			NamaKepsek:       namaKepsek,
 			NIPKepsek:        nipKepsek,
+			HariSekolah:      defaultHariSekolah,
+			ModeKartuQR:      models.ModeKartuQRStatis,
+		}
+		if req.HariSekolah != "" {
+			data.HariSekolah = req.HariSekolah
+		}
+		if req.ModeKartuQR != "" {
+			data.ModeKartuQR = req.ModeKartuQR
 		}
 
 		if err := s.repository.Create(data); err != nil {
@@ -65,6 +80,12 @@ func (s *KonfigurasiAbsensiServiceImpl) UpsertKonfigurasi(req *dtos.KonfigurasiA
 	existing.JamPulangSelesai = req.JamPulangSelesai
 	existing.NamaKepsek = namaKepsek
 	existing.NIPKepsek = nipKepsek
+	if req.HariSekolah != "" {
+		existing.HariSekolah = req.HariSekolah
+	}
+	if req.ModeKartuQR != "" {
+		existing.ModeKartuQR = req.ModeKartuQR
+	}
 
 	if err := s.repository.Update(existing); err != nil {
 		return nil, err
@@ -98,6 +119,8 @@ func (s *KonfigurasiAbsensiServiceImpl) mapToResponse(data *models.KonfigurasiAb
 		JamPulangSelesai: data.JamPulangSelesai,
 		NamaKepsek:       data.NamaKepsek,
 		NIPKepsek:        data.NIPKepsek,
+		HariSekolah:      data.HariSekolah,
+		ModeKartuQR:      data.ModeKartuQR,
 		CreatedAt:        data.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt:        data.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
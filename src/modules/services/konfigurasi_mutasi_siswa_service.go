@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"mime/multipart"
 	"time"
@@ -13,25 +14,25 @@ import (
 
 // KonfigurasiMutasiSiswaService handles business logic for Konfigurasi Mutasi Siswa
 type KonfigurasiMutasiSiswaService interface {
-	UpsertSetting(req *dtos.KonfigurasiMutasiSiswaRequest, file *multipart.FileHeader, userID uint) (*dtos.KonfigurasiMutasiSiswaResponse, error)
+	UpsertSetting(ctx context.Context, req *dtos.KonfigurasiMutasiSiswaRequest, file *multipart.FileHeader, actor utils.Principal) (*dtos.KonfigurasiMutasiSiswaResponse, error)
 	GetSetting() (*dtos.KonfigurasiMutasiSiswaResponse, error)
 }
 
 type KonfigurasiMutasiSiswaServiceImpl struct {
 	repository repositories.KonfigurasiMutasiSiswaRepository
-	r2Storage  *utils.R2Storage
+	storage    utils.Storage
 }
 
 // NewKonfigurasiMutasiSiswaService creates a new Konfigurasi Mutasi Siswa service
-func NewKonfigurasiMutasiSiswaService(repository repositories.KonfigurasiMutasiSiswaRepository, r2Storage *utils.R2Storage) KonfigurasiMutasiSiswaService {
+func NewKonfigurasiMutasiSiswaService(repository repositories.KonfigurasiMutasiSiswaRepository, storage utils.Storage) KonfigurasiMutasiSiswaService {
 	return &KonfigurasiMutasiSiswaServiceImpl{
 		repository: repository,
-		r2Storage:  r2Storage,
+		storage:    storage,
 	}
 }
 
 // UpsertSetting creates or updates Konfigurasi Mutasi Siswa with ID = 1
-func (s *KonfigurasiMutasiSiswaServiceImpl) UpsertSetting(req *dtos.KonfigurasiMutasiSiswaRequest, file *multipart.FileHeader, userID uint) (*dtos.KonfigurasiMutasiSiswaResponse, error) {
+func (s *KonfigurasiMutasiSiswaServiceImpl) UpsertSetting(ctx context.Context, req *dtos.KonfigurasiMutasiSiswaRequest, file *multipart.FileHeader, actor utils.Principal) (*dtos.KonfigurasiMutasiSiswaResponse, error) {
 	// Parse tanggal
 	tanggalBuka, err := time.Parse("2006-01-02", req.TanggalBukaPendaftaran)
 	if err != nil {
@@ -60,10 +61,11 @@ func (s *KonfigurasiMutasiSiswaServiceImpl) UpsertSetting(req *dtos.KonfigurasiM
 		
 		// Upload template SPTJM if provided
 		if file != nil {
-			path, err := s.r2Storage.UploadFile(file, "mutasi-siswa/template-sptjm")
+			stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryDokumen, "mutasi-siswa/template-sptjm")
 			if err != nil {
 				return nil, fmt.Errorf("gagal upload template SPTJM: %w", err)
 			}
+			path := stored.Key
 			templateSPTJMPath = &path
 		}
 
@@ -77,14 +79,16 @@ func (s *KonfigurasiMutasiSiswaServiceImpl) UpsertSetting(req *dtos.KonfigurasiM
 			NIPKetuaPanitia:         req.NIPKetuaPanitia,
 			TemplateSPTJM:           templateSPTJMPath,
 			GrupWA:                  grupWA,
-			CreatedByID:             &userID,
-			UpdatedByID:             &userID,
+			CreatedByID:             &actor.ID,
+			CreatedByType:           actor.TypePtr(),
+			UpdatedByID:             &actor.ID,
+			UpdatedByType:           actor.TypePtr(),
 		}
 
 		if err := s.repository.Create(data); err != nil {
 			// If database save fails, delete uploaded file
 			if templateSPTJMPath != nil && *templateSPTJMPath != "" {
-				_ = s.r2Storage.DeleteFile(*templateSPTJMPath)
+				_ = s.storage.DeleteFile(*templateSPTJMPath)
 			}
 			return nil, err
 		}
@@ -102,19 +106,21 @@ func (s *KonfigurasiMutasiSiswaServiceImpl) UpsertSetting(req *dtos.KonfigurasiM
 	existing.NamaKetuaPanitia = req.NamaKetuaPanitia
 	existing.NIPKetuaPanitia = req.NIPKetuaPanitia
 	existing.GrupWA = grupWA
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	// Update template SPTJM if provided
 	if file != nil {
 		// Upload new template
-		path, err := s.r2Storage.UploadFile(file, "mutasi-siswa/template-sptjm")
+		stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryDokumen, "mutasi-siswa/template-sptjm")
 		if err != nil {
 			return nil, fmt.Errorf("gagal upload template SPTJM: %w", err)
 		}
+		path := stored.Key
 
 		// Delete old template if exists
 		if oldTemplatePath != nil && *oldTemplatePath != "" {
-			_ = s.r2Storage.DeleteFile(*oldTemplatePath)
+			_ = s.storage.DeleteFile(*oldTemplatePath)
 		}
 
 		existing.TemplateSPTJM = &path
@@ -146,7 +152,7 @@ func (s *KonfigurasiMutasiSiswaServiceImpl) mapToResponse(data *models.Konfigura
 	// Convert template SPTJM path to public URL
 	var templateURL *string
 	if data.TemplateSPTJM != nil && *data.TemplateSPTJM != "" {
-		url := s.r2Storage.GetPublicURL(*data.TemplateSPTJM)
+		url := s.storage.GetPublicURL(*data.TemplateSPTJM)
 		templateURL = &url
 	}
 
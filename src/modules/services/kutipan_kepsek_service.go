@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"mime/multipart"
 	"pintu-backend/src/dtos"
@@ -11,68 +12,52 @@ import (
 
 // KutipanKepsekService handles business logic for KutipanKepsek
 type KutipanKepsekService interface {
-	Create(file *multipart.FileHeader, req *dtos.KutipanKepsekCreateRequest, userID uint) (*dtos.KutipanKepsekResponse, error)
+	Create(ctx context.Context, file *multipart.FileHeader, req *dtos.KutipanKepsekCreateRequest, actor utils.Principal) (*dtos.KutipanKepsekResponse, error)
 	GetByID(id uint) (*dtos.KutipanKepsekResponse, error)
 	GetAll(limit int, offset int) (*dtos.KutipanKepsekListResponse, error)
-	UpdateWithFile(id uint, file *multipart.FileHeader, req *dtos.KutipanKepsekUpdateRequest, userID uint) (*dtos.KutipanKepsekResponse, error)
+	UpdateWithFile(ctx context.Context, id uint, file *multipart.FileHeader, req *dtos.KutipanKepsekUpdateRequest, actor utils.Principal) (*dtos.KutipanKepsekResponse, error)
 	Delete(id uint) error
 	GetPublic() (*dtos.KutipanKepsekPublicResponse, error)
 }
 
 type KutipanKepsekServiceImpl struct {
 	repository repositories.KutipanKepsekRepository
-	r2Storage  *utils.R2Storage
+	storage    utils.Storage
 }
 
 // NewKutipanKepsekService creates a new KutipanKepsek service
-func NewKutipanKepsekService(repository repositories.KutipanKepsekRepository, r2Storage *utils.R2Storage) KutipanKepsekService {
+func NewKutipanKepsekService(repository repositories.KutipanKepsekRepository, storage utils.Storage) KutipanKepsekService {
 	return &KutipanKepsekServiceImpl{
 		repository: repository,
-		r2Storage:  r2Storage,
+		storage:    storage,
 	}
 }
 
 // Create creates a new KutipanKepsek with file upload
-func (s *KutipanKepsekServiceImpl) Create(file *multipart.FileHeader, req *dtos.KutipanKepsekCreateRequest, userID uint) (*dtos.KutipanKepsekResponse, error) {
+func (s *KutipanKepsekServiceImpl) Create(ctx context.Context, file *multipart.FileHeader, req *dtos.KutipanKepsekCreateRequest, actor utils.Principal) (*dtos.KutipanKepsekResponse, error) {
 	// Validate file
 	if file == nil {
 		return nil, errors.New("file is required")
 	}
 
-	// Validate file size (max 5MB)
-	const maxFileSize = 5 * 1024 * 1024
-	if file.Size > maxFileSize {
-		return nil, errors.New("file size must not exceed 5MB")
-	}
-
-	// Validate file type (only images)
-	allowedTypes := map[string]bool{
-		"image/jpeg": true,
-		"image/png":  true,
-		"image/gif":  true,
-		"image/webp": true,
-	}
-	contentType := file.Header.Get("Content-Type")
-	if !allowedTypes[contentType] {
-		return nil, errors.New("only image files are allowed (jpeg, png, gif, webp)")
-	}
-
-	// Upload file to R2 (kepsek directory)
-	fileKey, err := s.r2Storage.UploadFile(file, "kepsek")
+	// Validate the file from its content and upload to R2
+	stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryGambar, "kepsek")
 	if err != nil {
 		return nil, err
 	}
+	fileKey := stored.Key
 
 	data := &models.KutipanKepsek{
 		NamaKepsek:    req.NamaKepsek,
 		FotoKepsek:    fileKey,
 		KutipanKepsek: req.KutipanKepsek,
-		CreatedByID:   &userID,
+		CreatedByID:   &actor.ID,
+		CreatedByType: actor.TypePtr(),
 	}
 
 	if err := s.repository.Create(data); err != nil {
 		// If database save fails, delete the uploaded file
-		_ = s.r2Storage.DeleteFile(fileKey)
+		_ = s.storage.DeleteFile(fileKey)
 		return nil, err
 	}
 
@@ -121,7 +106,7 @@ func (s *KutipanKepsekServiceImpl) GetAll(limit int, offset int) (*dtos.KutipanK
 }
 
 // UpdateWithFile updates KutipanKepsek with optional file upload
-func (s *KutipanKepsekServiceImpl) UpdateWithFile(id uint, file *multipart.FileHeader, req *dtos.KutipanKepsekUpdateRequest, userID uint) (*dtos.KutipanKepsekResponse, error) {
+func (s *KutipanKepsekServiceImpl) UpdateWithFile(ctx context.Context, id uint, file *multipart.FileHeader, req *dtos.KutipanKepsekUpdateRequest, actor utils.Principal) (*dtos.KutipanKepsekResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(id)
 	if err != nil {
@@ -132,30 +117,15 @@ func (s *KutipanKepsekServiceImpl) UpdateWithFile(id uint, file *multipart.FileH
 
 	// If file provided, validate and upload
 	if file != nil {
-		// Validate file
-		if file.Size > 5*1024*1024 {
-			return nil, errors.New("file size must not exceed 5MB")
-		}
-
-		allowedTypes := map[string]bool{
-			"image/jpeg": true,
-			"image/png":  true,
-			"image/gif":  true,
-			"image/webp": true,
-		}
-		contentType := file.Header.Get("Content-Type")
-		if !allowedTypes[contentType] {
-			return nil, errors.New("only image files are allowed (jpeg, png, gif, webp)")
-		}
-
-		// Upload new file
-		newFileKey, err := s.r2Storage.UploadFile(file, "kepsek")
+		// Validate the file from its content and upload to R2
+		stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryGambar, "kepsek")
 		if err != nil {
 			return nil, err
 		}
+		newFileKey := stored.Key
 
 		// Delete old file from R2
-		_ = s.r2Storage.DeleteFile(oldFile)
+		_ = s.storage.DeleteFile(oldFile)
 
 		// Update file in model
 		existing.FotoKepsek = newFileKey
@@ -171,12 +141,13 @@ func (s *KutipanKepsekServiceImpl) UpdateWithFile(id uint, file *multipart.FileH
 		existing.KutipanKepsek = *req.KutipanKepsek
 	}
 
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	if err := s.repository.Update(existing); err != nil {
 		// If DB save fails, delete the uploaded file
 		if file != nil {
-			_ = s.r2Storage.DeleteFile(existing.FotoKepsek)
+			_ = s.storage.DeleteFile(existing.FotoKepsek)
 		}
 		return nil, err
 	}
@@ -193,7 +164,7 @@ func (s *KutipanKepsekServiceImpl) Delete(id uint) error {
 	}
 
 	// Delete file from R2
-	if err := s.r2Storage.DeleteFile(existing.FotoKepsek); err != nil {
+	if err := s.storage.DeleteFile(existing.FotoKepsek); err != nil {
 		// Log error but continue with database deletion
 	}
 
@@ -209,7 +180,7 @@ func (s *KutipanKepsekServiceImpl) GetPublic() (*dtos.KutipanKepsekPublicRespons
 	}
 
 	// Get public URL for the file
-	publicURL := s.r2Storage.GetPublicURL(data.FotoKepsek)
+	publicURL := s.storage.GetPublicURL(data.FotoKepsek)
 
 	return &dtos.KutipanKepsekPublicResponse{
 		NamaKepsek:    data.NamaKepsek,
@@ -221,7 +192,7 @@ func (s *KutipanKepsekServiceImpl) GetPublic() (*dtos.KutipanKepsekPublicRespons
 // mapToResponse maps model to DTO response
 func (s *KutipanKepsekServiceImpl) mapToResponse(data *models.KutipanKepsek) *dtos.KutipanKepsekResponse {
 	// Get public URL for the file
-	publicURL := s.r2Storage.GetPublicURL(data.FotoKepsek)
+	publicURL := s.storage.GetPublicURL(data.FotoKepsek)
 
 	return &dtos.KutipanKepsekResponse{
 		ID:            data.ID,
@@ -231,6 +202,8 @@ func (s *KutipanKepsekServiceImpl) mapToResponse(data *models.KutipanKepsek) *dt
 		CreatedAt:     data.CreatedAt,
 		UpdatedAt:     data.UpdatedAt,
 		CreatedByID:   data.CreatedByID,
+		CreatedByType: data.CreatedByType,
 		UpdatedByID:   data.UpdatedByID,
+		UpdatedByType: data.UpdatedByType,
 	}
 }
@@ -10,223 +10,522 @@ import (
 	"pintu-backend/src/utils"
 
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 // LoginService handles business logic for authentication
 type LoginService interface {
-	Login(req *dtos.LoginRequest) (*dtos.LoginResponse, error)
+	Login(req *dtos.LoginRequest, userAgent, ipAddress string) (*dtos.LoginResponse, error)
+	LoginSiswa(req *dtos.LoginRequest, userAgent, ipAddress string) (*dtos.LoginResponse, error)
+	Refresh(req *dtos.RefreshTokenRequest, userAgent, ipAddress string) (*dtos.RefreshTokenResponse, error)
+	SwitchRole(principal utils.Principal, jti string, expiresAt time.Time, req *dtos.SwitchRoleRequest, userAgent, ipAddress string) (*dtos.LoginResponse, error)
+	Logout(jti string, expiresAt time.Time) error
 }
 
+// ErrAccountLocked is returned while an account is temporarily locked after repeated failed logins
+var ErrAccountLocked = errors.New("akun terkunci sementara karena terlalu banyak percobaan login gagal, silakan coba lagi nanti")
+
+// ErrActiveRoleRemoved is returned on refresh when the session role was taken away from the account
+var ErrActiveRoleRemoved = errors.New("role aktif sudah tidak dimiliki akun ini, silakan login ulang atau ganti role")
+
+const (
+	// maxFailedLogins is the number of consecutive wrong passwords that locks an account
+	maxFailedLogins = 5
+	// lockoutDuration is how long a locked account rejects logins
+	lockoutDuration = 15 * time.Minute
+)
+
 type LoginServiceImpl struct {
-	repository repositories.LoginRepository
+	repository       repositories.LoginRepository
+	tokenRepository  repositories.AuthTokenRepository
+	systemRepository repositories.SystemRepository
+	throttleService  ThrottleService
 }
 
 // NewLoginService creates a new Login service
-func NewLoginService(repository repositories.LoginRepository) LoginService {
-	return &LoginServiceImpl{repository: repository}
-}
-
-// Login authenticates user and returns JWT token
-func (s *LoginServiceImpl) Login(req *dtos.LoginRequest) (*dtos.LoginResponse, error) {
-	// Try to get user from users table first
-	user, userErr := s.repository.GetByUsername(req.Username)
-	
-	// If user not found in users table, try kepegawaian table
-	if userErr != nil {
-		kepegawaian, kepErr := s.repository.GetKepegawaianByUsername(req.Username)
-		if kepErr != nil {
-			return nil, errors.New("username atau password salah")
-		}
-		
-		// Verify password for kepegawaian
-		if err := bcrypt.CompareHashAndPassword([]byte(kepegawaian.Password), []byte(req.Password)); err != nil {
-			return nil, errors.New("username atau password salah")
-		}
+func NewLoginService(repository repositories.LoginRepository, tokenRepository repositories.AuthTokenRepository, systemRepository repositories.SystemRepository, throttleService ThrottleService) LoginService {
+	return &LoginServiceImpl{
+		repository:       repository,
+		tokenRepository:  tokenRepository,
+		systemRepository: systemRepository,
+		throttleService:  throttleService,
+	}
+}
 
-		// Check if kepegawaian is active
-		if kepegawaian.Status != "active" {
-			return nil, errors.New("user tidak aktif")
-		}
+// loginAccount is a principal loaded from the users or kepegawaian table
+type loginAccount struct {
+	principalType string
+	id            uint
+	username      string
+	nama          string
+	status        string
+	password      string
+	failedLogins  int
+	lockedUntil   *time.Time
+	roles         []models.Role
+	profile       dtos.UserLoginResponse
+}
 
-		// Filter roles that have system_id = 1 (PINTU)
-		var pintuRoles []models.Role
-		for _, role := range kepegawaian.Roles {
-			if role.SystemID != nil && *role.SystemID == 1 {
-				pintuRoles = append(pintuRoles, role)
-			}
-		}
+// issuedSession holds a freshly signed access token and its not yet persisted refresh token
+type issuedSession struct {
+	accessToken  string
+	refreshToken string
+	claims       *utils.JWTClaims
+	record       *models.RefreshToken
+}
 
-		// Check if kepegawaian has at least one PINTU role
-		if len(pintuRoles) == 0 {
-			return nil, errors.New("anda tidak memiliki akses ke sistem PINTU")
-		}
+// Login authenticates user or kepegawaian and returns JWT token
+func (s *LoginServiceImpl) Login(req *dtos.LoginRequest, userAgent, ipAddress string) (*dtos.LoginResponse, error) {
+	account, err := s.authenticate(LoginThrottlePolicy, req, ipAddress, s.findStaffAccount)
+	if err != nil {
+		return nil, err
+	}
 
-		// Use first PINTU role for token
-		roleID := pintuRoles[0].ID
+	// Check if account is active
+	if account.status != "active" {
+		return nil, errors.New("user tidak aktif")
+	}
 
-		// Generate JWT token for kepegawaian
-		token, err := utils.GenerateToken(kepegawaian.ID, kepegawaian.Username, kepegawaian.Nama, &roleID, kepegawaian.Status)
-		if err != nil {
-			return nil, errors.New("gagal membuat token")
-		}
+	pintuRoles, err := s.filterPintuRoles(account.roles)
+	if err != nil {
+		return nil, err
+	}
 
-		// Map PINTU roles and extract permissions
-		roles := make([]dtos.RoleResponse, len(pintuRoles))
-		permissionMap := make(map[string]bool)
-		
-		for i, role := range pintuRoles {
-			var system *dtos.SystemResponse
-			if role.System != nil {
-				system = &dtos.SystemResponse{
-					ID:          role.System.ID,
-					Nama:        role.System.Nama,
-					Description: role.System.Description,
-				}
-			}
+	// Start with the first eligible role, the client can switch afterwards
+	return s.startSession(account, pintuRoles, &pintuRoles[0].ID, userAgent, ipAddress)
+}
 
-			roles[i] = dtos.RoleResponse{
-				ID:          role.ID,
-				Name:        role.Name,
-				Description: role.Description,
-				SystemID:    role.SystemID,
-				System:      system,
-				Status:      role.Status,
-				CreatedAt:   role.CreatedAt,
-				UpdatedAt:   role.UpdatedAt,
-				CreatedByID: role.CreatedByID,
-				UpdatedByID: role.UpdatedByID,
-			}
+// LoginSiswa authenticates peserta didik and returns a siswa token without an active role
+func (s *LoginServiceImpl) LoginSiswa(req *dtos.LoginRequest, userAgent, ipAddress string) (*dtos.LoginResponse, error) {
+	account, err := s.authenticate(LoginSiswaThrottlePolicy, req, ipAddress, s.findSiswaAccount)
+	if err != nil {
+		return nil, err
+	}
 
-			// Collect unique permissions from PINTU roles
-			for _, permission := range role.Permissions {
-				permissionMap[permission.Name] = true
-			}
-		}
+	if account.status != "active" {
+		return nil, errors.New("siswa tidak aktif")
+	}
 
-		// Convert permission map to slice
-		permissions := make([]string, 0, len(permissionMap))
-		for permName := range permissionMap {
-			permissions = append(permissions, permName)
-		}
+	return s.startSession(account, nil, nil, userAgent, ipAddress)
+}
 
-		// Prepare rombel_bidang_studi (handle JSONB data)
-		var rombelBidangStudi interface{} = nil
-		if len(kepegawaian.RombelBidangStudi) > 0 {
-			rombelBidangStudi = kepegawaian.RombelBidangStudi
+// authenticate verifies the password of the account found by username, enforcing the
+// throttling window of the policy and the account lockout
+func (s *LoginServiceImpl) authenticate(policy ThrottlePolicy, req *dtos.LoginRequest, ipAddress string, find func(username string) (*loginAccount, error)) (*loginAccount, error) {
+	// Reject early while the IP address or the username is throttled
+	if err := s.throttleService.Check(policy, ipAddress, req.Username); err != nil {
+		return nil, err
+	}
+
+	account, err := find(req.Username)
+	if err != nil {
+		s.throttleService.RecordFailure(policy, ipAddress, req.Username)
+		// Unknown usernames lock at the same count as accounts, so the response does not reveal which exist
+		if failures, err := s.throttleService.Failures(policy, req.Username); err == nil && failures >= maxFailedLogins {
+			return nil, ErrAccountLocked
 		}
+		return nil, errors.New("username atau password salah")
+	}
 
-		// Set jabatan to pointer
-		var jabatan *string = nil
-		if kepegawaian.Jabatan != "" {
-			jabatan = &kepegawaian.Jabatan
+	if account.lockedUntil != nil && time.Now().Before(*account.lockedUntil) {
+		s.throttleService.RecordFailure(policy, ipAddress, req.Username)
+		return nil, ErrAccountLocked
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(account.password), []byte(req.Password)); err != nil {
+		s.throttleService.RecordFailure(policy, ipAddress, req.Username)
+		if s.registerFailedLogin(account) {
+			return nil, ErrAccountLocked
 		}
+		return nil, errors.New("username atau password salah")
+	}
+
+	// A correct password ends the failure streak of the account and the username window
+	if account.failedLogins > 0 || account.lockedUntil != nil {
+		s.repository.Unlock(account.principalType, account.id)
+	}
+	s.throttleService.Reset(policy, req.Username)
+
+	return account, nil
+}
+
+// findStaffAccount looks the username up in the users table first, then kepegawaian
+func (s *LoginServiceImpl) findStaffAccount(username string) (*loginAccount, error) {
+	if user, err := s.repository.GetByUsername(username); err == nil {
+		return accountFromUser(user), nil
+	}
+
+	kepegawaian, err := s.repository.GetKepegawaianByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	return accountFromKepegawaian(kepegawaian), nil
+}
+
+// findSiswaAccount looks the username up in the peserta_didik table
+func (s *LoginServiceImpl) findSiswaAccount(username string) (*loginAccount, error) {
+	pesertaDidik, err := s.repository.GetPesertaDidikByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	return accountFromPesertaDidik(pesertaDidik), nil
+}
+
+// registerFailedLogin counts a wrong password and locks the account once the limit is reached
+func (s *LoginServiceImpl) registerFailedLogin(account *loginAccount) bool {
+	attempts, err := s.repository.IncrementFailedLogins(account.principalType, account.id)
+	if err != nil || attempts < maxFailedLogins {
+		return false
+	}
+
+	if err := s.repository.Lock(account.principalType, account.id, time.Now().Add(lockoutDuration)); err != nil {
+		return false
+	}
+	utils.AccountLockoutsTotal.WithLabelValues(account.principalType).Inc()
+	return true
+}
+
+// SwitchRole re-issues the session of the caller for another of its PINTU roles
+func (s *LoginServiceImpl) SwitchRole(principal utils.Principal, jti string, expiresAt time.Time, req *dtos.SwitchRoleRequest, userAgent, ipAddress string) (*dtos.LoginResponse, error) {
+	account, err := s.loadAccount(principal.Type, principal.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.status != "active" {
+		return nil, errors.New("user tidak aktif")
+	}
 
-		response := &dtos.LoginResponse{
-			Token:       token,
-			ExpiresAt:   time.Now().Add(24 * time.Hour),
-			Permissions: permissions,
-			User: dtos.UserLoginResponse{
-				ID:                kepegawaian.ID,
-				Nama:              kepegawaian.Nama,
-				Username:          kepegawaian.Username,
-				Status:            kepegawaian.Status,
-				Roles:             roles,
-				CreatedAt:         kepegawaian.CreatedAt,
-				Jabatan:           jabatan,
-				RombelGuruKelasID: kepegawaian.RombelGuruKelasID,
-				BidangStudiID:     kepegawaian.BidangStudiID,
-				RombelBidangStudi: rombelBidangStudi,
-			},
+	pintuRoles, err := s.filterPintuRoles(account.roles)
+	if err != nil {
+		return nil, err
+	}
+
+	eligible := false
+	for _, role := range pintuRoles {
+		if role.ID == req.RoleID {
+			eligible = true
+			break
 		}
+	}
+	if !eligible {
+		return nil, errors.New("role tidak tersedia untuk akun ini")
+	}
 
-		return response, nil
+	// End the current session so the previous role cannot be used any longer
+	if err := s.tokenRepository.RevokeSessionByAccessJTI(jti, expiresAt); err != nil {
+		return nil, errors.New("gagal mengganti role")
 	}
 
-	// User found in users table, proceed with normal user login
-	// Verify password
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return nil, errors.New("username atau password salah")
+	return s.startSession(account, pintuRoles, &req.RoleID, userAgent, ipAddress)
+}
+
+// Refresh rotates a refresh token and issues a new access token for the same principal
+func (s *LoginServiceImpl) Refresh(req *dtos.RefreshTokenRequest, userAgent, ipAddress string) (*dtos.RefreshTokenResponse, error) {
+	stored, err := s.tokenRepository.GetRefreshTokenByHash(utils.HashRefreshToken(req.RefreshToken))
+	if err != nil {
+		return nil, errors.New("refresh token tidak valid")
 	}
 
-	// Check if user is active
-	if user.Status != "active" {
+	if stored.RevokedAt != nil {
+		// A rotated token presented again has leaked, so end every session of the principal
+		s.tokenRepository.RevokeAllForPrincipal(stored.PrincipalType, stored.PrincipalID)
+		return nil, errors.New("refresh token sudah tidak berlaku")
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, errors.New("refresh token sudah kedaluwarsa")
+	}
+
+	// Reload the principal so status and role changes apply on refresh
+	account, err := s.loadAccount(stored.PrincipalType, stored.PrincipalID)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.status != "active" {
+		s.tokenRepository.RevokeAllForPrincipal(stored.PrincipalType, stored.PrincipalID)
 		return nil, errors.New("user tidak aktif")
 	}
 
-	// Filter roles that have system_id = 1 (PINTU)
-	var pintuRoles []models.Role
-	for _, role := range user.Roles {
-		if role.SystemID != nil && *role.SystemID == 1 {
-			pintuRoles = append(pintuRoles, role)
+	// A lockout after failed logins also stops sessions from being extended until it ends
+	if account.lockedUntil != nil && time.Now().Before(*account.lockedUntil) {
+		return nil, ErrAccountLocked
+	}
+
+	// Siswa sessions carry no role, staff keep the active role only while they still hold it
+	var roleID *uint
+	if account.principalType != utils.PrincipalSiswa {
+		pintuRoles, err := s.filterPintuRoles(account.roles)
+		if err != nil {
+			return nil, err
+		}
+
+		if stored.RoleID != nil {
+			for i := range pintuRoles {
+				if pintuRoles[i].ID == *stored.RoleID {
+					roleID = &pintuRoles[i].ID
+					break
+				}
+			}
+		}
+		if roleID == nil {
+			return nil, ErrActiveRoleRemoved
 		}
 	}
 
-	// Check if user has at least one PINTU role
-	if len(pintuRoles) == 0 {
-		return nil, errors.New("anda tidak memiliki akses ke sistem PINTU")
+	session, err := s.issueSession(account, roleID, userAgent, ipAddress)
+	if err != nil {
+		return nil, err
 	}
 
-	// Use first PINTU role for token
-	roleID := pintuRoles[0].ID
+	if err := s.tokenRepository.RotateRefreshToken(stored, session.record); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("refresh token sudah tidak berlaku")
+		}
+		return nil, errors.New("gagal memperbarui token")
+	}
+
+	return &dtos.RefreshTokenResponse{
+		Token:            session.accessToken,
+		RefreshToken:     session.refreshToken,
+		ExpiresAt:        session.claims.ExpiresAt.Time,
+		RefreshExpiresAt: session.record.ExpiresAt,
+	}, nil
+}
+
+// Logout revokes the current access token and the refresh token issued with it
+func (s *LoginServiceImpl) Logout(jti string, expiresAt time.Time) error {
+	if err := s.tokenRepository.RevokeSessionByAccessJTI(jti, expiresAt); err != nil {
+		return errors.New("gagal logout")
+	}
+	return nil
+}
 
-	// Generate JWT token
-	token, err := utils.GenerateToken(user.ID, user.Username, user.Nama, &roleID, user.Status)
+// startSession persists a new session for the active role and builds the login response.
+// activeRoleID is nil for siswa, which hold no PINTU roles.
+func (s *LoginServiceImpl) startSession(account *loginAccount, pintuRoles []models.Role, activeRoleID *uint, userAgent, ipAddress string) (*dtos.LoginResponse, error) {
+	session, err := s.issueSession(account, activeRoleID, userAgent, ipAddress)
 	if err != nil {
+		return nil, err
+	}
+	if err := s.tokenRepository.CreateRefreshToken(session.record); err != nil {
 		return nil, errors.New("gagal membuat token")
 	}
 
-	// Map PINTU roles and extract permissions
+	// Map all eligible PINTU roles, permissions come from the active role only
 	roles := make([]dtos.RoleResponse, len(pintuRoles))
-	permissionMap := make(map[string]bool) // To track unique permissions
-	
+	permissions := []string{}
 	for i, role := range pintuRoles {
 		var system *dtos.SystemResponse
 		if role.System != nil {
 			system = &dtos.SystemResponse{
 				ID:          role.System.ID,
 				Nama:        role.System.Nama,
+				Code:        role.System.Code,
 				Description: role.System.Description,
 			}
 		}
 
 		roles[i] = dtos.RoleResponse{
-			ID:          role.ID,
-			Name:        role.Name,
-			Description: role.Description,
-			SystemID:    role.SystemID,
-			System:      system,
-			Status:      role.Status,
-			CreatedAt:   role.CreatedAt,
-			UpdatedAt:   role.UpdatedAt,
-			CreatedByID: role.CreatedByID,
-			UpdatedByID: role.UpdatedByID,
+			ID:            role.ID,
+			Name:          role.Name,
+			Description:   role.Description,
+			SystemID:      role.SystemID,
+			System:        system,
+			Status:        role.Status,
+			CreatedAt:     role.CreatedAt,
+			UpdatedAt:     role.UpdatedAt,
+			CreatedByID:   role.CreatedByID,
+			CreatedByType: role.CreatedByType,
+			UpdatedByID:   role.UpdatedByID,
+			UpdatedByType: role.UpdatedByType,
 		}
 
-		// Collect unique permissions from PINTU roles
-		for _, permission := range role.Permissions {
-			permissionMap[permission.Name] = true
+		if activeRoleID != nil && role.ID == *activeRoleID {
+			for _, permission := range role.Permissions {
+				permissions = append(permissions, permission.Name)
+			}
 		}
 	}
 
-	// Convert permission map to slice
-	permissions := make([]string, 0, len(permissionMap))
-	for permName := range permissionMap {
-		permissions = append(permissions, permName)
+	user := account.profile
+	user.Roles = roles
+
+	return &dtos.LoginResponse{
+		Token:            session.accessToken,
+		RefreshToken:     session.refreshToken,
+		User:             user,
+		ActiveRoleID:     activeRoleID,
+		Permissions:      permissions,
+		ExpiresAt:        session.claims.ExpiresAt.Time,
+		RefreshExpiresAt: session.record.ExpiresAt,
+	}, nil
+}
+
+// issueSession signs an access token and prepares the refresh token record paired with it
+func (s *LoginServiceImpl) issueSession(account *loginAccount, roleID *uint, userAgent, ipAddress string) (*issuedSession, error) {
+	token, claims, err := utils.GenerateToken(account.principalType, account.id, account.username, account.nama, roleID, account.status)
+	if err != nil {
+		return nil, errors.New("gagal membuat token")
 	}
 
-	response := &dtos.LoginResponse{
-		Token:       token,
-		ExpiresAt:   time.Now().Add(24 * time.Hour),
-		Permissions: permissions,
-		User: dtos.UserLoginResponse{
-			ID:        user.ID,
-			Nama:      user.Nama,
-			Username:  user.Username,
-			Status:    user.Status,
-			Roles:     roles,
-			CreatedAt: user.CreatedAt,
+	refreshToken, refreshHash, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, errors.New("gagal membuat token")
+	}
+
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	return &issuedSession{
+		accessToken:  token,
+		refreshToken: refreshToken,
+		claims:       claims,
+		record: &models.RefreshToken{
+			PrincipalType:   account.principalType,
+			PrincipalID:     account.id,
+			RoleID:          roleID,
+			TokenHash:       refreshHash,
+			AccessJTI:       claims.ID,
+			AccessExpiresAt: claims.ExpiresAt.Time,
+			ExpiresAt:       time.Now().Add(utils.RefreshTokenTTL),
+			UserAgent:       userAgent,
+			IPAddress:       ipAddress,
+		},
+	}, nil
+}
+
+// loadAccount reloads a principal with its roles by type and ID
+func (s *LoginServiceImpl) loadAccount(principalType string, id uint) (*loginAccount, error) {
+	switch principalType {
+	case utils.PrincipalUser:
+		user, err := s.repository.GetByID(id)
+		if err != nil {
+			return nil, errors.New("user tidak ditemukan")
+		}
+		return accountFromUser(user), nil
+	case utils.PrincipalPegawai:
+		kepegawaian, err := s.repository.GetKepegawaianByID(id)
+		if err != nil {
+			return nil, errors.New("user tidak ditemukan")
+		}
+		return accountFromKepegawaian(kepegawaian), nil
+	case utils.PrincipalSiswa:
+		pesertaDidik, err := s.repository.GetPesertaDidikByID(id)
+		if err != nil {
+			return nil, errors.New("siswa tidak ditemukan")
+		}
+		return accountFromPesertaDidik(pesertaDidik), nil
+	default:
+		return nil, errors.New("anda tidak memiliki akses ke sistem PINTU")
+	}
+}
+
+// filterPintuRoles keeps the roles that belong to the configured PINTU system
+func (s *LoginServiceImpl) filterPintuRoles(roles []models.Role) ([]models.Role, error) {
+	systemID, err := s.systemRepository.GetIDByCode(utils.PintuSystemCode())
+	if err != nil {
+		return nil, errors.New("sistem PINTU belum dikonfigurasi")
+	}
+
+	var pintuRoles []models.Role
+	for _, role := range roles {
+		if role.SystemID != nil && *role.SystemID == systemID {
+			pintuRoles = append(pintuRoles, role)
+		}
+	}
+
+	// Check if account has at least one PINTU role
+	if len(pintuRoles) == 0 {
+		return nil, errors.New("anda tidak memiliki akses ke sistem PINTU")
+	}
+	return pintuRoles, nil
+}
+
+// accountFromUser maps a users row to a login account
+func accountFromUser(user *models.User) *loginAccount {
+	return &loginAccount{
+		principalType: utils.PrincipalUser,
+		id:            user.ID,
+		username:      user.Username,
+		nama:          user.Nama,
+		status:        user.Status,
+		password:      user.Password,
+		failedLogins:  user.FailedLoginAttempts,
+		lockedUntil:   user.LockedUntil,
+		roles:         user.Roles,
+		profile: dtos.UserLoginResponse{
+			ID:            user.ID,
+			PrincipalType: utils.PrincipalUser,
+			Nama:          user.Nama,
+			Username:      user.Username,
+			Status:        user.Status,
+			CreatedAt:     user.CreatedAt,
 		},
 	}
+}
 
-	return response, nil
+// accountFromKepegawaian maps a kepegawaian row to a login account
+func accountFromKepegawaian(kepegawaian *models.Kepegawaian) *loginAccount {
+	// Prepare rombel_bidang_studi (handle JSONB data)
+	var rombelBidangStudi interface{} = nil
+	if len(kepegawaian.RombelBidangStudi) > 0 {
+		rombelBidangStudi = kepegawaian.RombelBidangStudi
+	}
+
+	// Set jabatan to pointer
+	var jabatan *string = nil
+	if kepegawaian.Jabatan != "" {
+		jabatan = &kepegawaian.Jabatan
+	}
+
+	return &loginAccount{
+		principalType: utils.PrincipalPegawai,
+		id:            kepegawaian.ID,
+		username:      kepegawaian.Username,
+		nama:          kepegawaian.Nama,
+		status:        kepegawaian.Status,
+		password:      kepegawaian.Password,
+		failedLogins:  kepegawaian.FailedLoginAttempts,
+		lockedUntil:   kepegawaian.LockedUntil,
+		roles:         kepegawaian.Roles,
+		profile: dtos.UserLoginResponse{
+			ID:                kepegawaian.ID,
+			PrincipalType:     utils.PrincipalPegawai,
+			Nama:              kepegawaian.Nama,
+			Username:          kepegawaian.Username,
+			Status:            kepegawaian.Status,
+			CreatedAt:         kepegawaian.CreatedAt,
+			Jabatan:           jabatan,
+			RombelGuruKelasID: kepegawaian.RombelGuruKelasID,
+			BidangStudiID:     kepegawaian.BidangStudiID,
+			RombelBidangStudi: rombelBidangStudi,
+		},
+	}
+}
+
+// accountFromPesertaDidik maps a peserta_didik row to a login account
+func accountFromPesertaDidik(pesertaDidik *models.PesertaDidik) *loginAccount {
+	return &loginAccount{
+		principalType: utils.PrincipalSiswa,
+		id:            pesertaDidik.ID,
+		username:      pesertaDidik.Username,
+		nama:          pesertaDidik.Nama,
+		status:        pesertaDidik.Status,
+		password:      pesertaDidik.Password,
+		failedLogins:  pesertaDidik.FailedLoginAttempts,
+		lockedUntil:   pesertaDidik.LockedUntil,
+		profile: dtos.UserLoginResponse{
+			ID:            pesertaDidik.ID,
+			PrincipalType: utils.PrincipalSiswa,
+			Nama:          pesertaDidik.Nama,
+			Username:      pesertaDidik.Username,
+			Status:        pesertaDidik.Status,
+			CreatedAt:     pesertaDidik.CreatedAt,
+			NIS:           &pesertaDidik.NIS,
+			NISN:          &pesertaDidik.NISN,
+		},
+	}
 }
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeLoginRepository only implements loading a user and its failed login lockout
+type fakeLoginRepository struct {
+	repositories.LoginRepository
+	user *models.User
+}
+
+func (r *fakeLoginRepository) GetByID(id uint) (*models.User, error) {
+	return r.user, nil
+}
+
+func (r *fakeLoginRepository) GetByUsername(username string) (*models.User, error) {
+	if r.user == nil || r.user.Username != username {
+		return nil, errors.New("record not found")
+	}
+	return r.user, nil
+}
+
+func (r *fakeLoginRepository) GetKepegawaianByUsername(username string) (*models.Kepegawaian, error) {
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeLoginRepository) IncrementFailedLogins(principalType string, id uint) (int, error) {
+	r.user.FailedLoginAttempts++
+	return r.user.FailedLoginAttempts, nil
+}
+
+func (r *fakeLoginRepository) Lock(principalType string, id uint, until time.Time) error {
+	r.user.LockedUntil = &until
+	return nil
+}
+
+// fakeAuthTokenRepository only implements the refresh token lookups, rotation and revocation
+type fakeAuthTokenRepository struct {
+	repositories.AuthTokenRepository
+	stored  *models.RefreshToken
+	rotated bool
+	revoked []string
+}
+
+func (r *fakeAuthTokenRepository) GetRefreshTokenByHash(tokenHash string) (*models.RefreshToken, error) {
+	return r.stored, nil
+}
+
+func (r *fakeAuthTokenRepository) RotateRefreshToken(old *models.RefreshToken, data *models.RefreshToken) error {
+	r.rotated = true
+	return nil
+}
+
+func (r *fakeAuthTokenRepository) RevokeAllForPrincipal(principalType string, principalID uint) error {
+	r.revoked = append(r.revoked, fmt.Sprintf("%s:%d", principalType, principalID))
+	return nil
+}
+
+// fakeSystemRepository only implements resolving the PINTU system
+type fakeSystemRepository struct {
+	repositories.SystemRepository
+}
+
+func (r *fakeSystemRepository) GetIDByCode(code string) (uint, error) {
+	return 1, nil
+}
+
+func TestRefreshRoleAndLock(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	pintu := uint(1)
+	lainnya := uint(2)
+	admin := models.Role{ID: 10, Name: "admin", SystemID: &pintu}
+	guru := models.Role{ID: 11, Name: "guru", SystemID: &pintu}
+	luar := models.Role{ID: 12, Name: "luar", SystemID: &lainnya}
+	roleID := func(id uint) *uint { return &id }
+	terkunci := time.Now().Add(10 * time.Minute)
+	kunciLewat := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name        string
+		roles       []models.Role
+		lockedUntil *time.Time
+		storedRole  *uint
+		wantErr     error
+	}{
+		{"active role still held", []models.Role{admin, guru}, nil, roleID(guru.ID), nil},
+		{"expired lock", []models.Role{admin}, &kunciLewat, roleID(admin.ID), nil},
+		{"active role removed", []models.Role{admin}, nil, roleID(guru.ID), ErrActiveRoleRemoved},
+		{"active role moved to another system", []models.Role{admin, luar}, nil, roleID(luar.ID), ErrActiveRoleRemoved},
+		{"session without role", []models.Role{admin}, nil, nil, ErrActiveRoleRemoved},
+		{"account locked", []models.Role{admin}, &terkunci, roleID(admin.ID), ErrAccountLocked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := &fakeAuthTokenRepository{stored: &models.RefreshToken{
+				PrincipalType: utils.PrincipalUser,
+				PrincipalID:   5,
+				RoleID:        tt.storedRole,
+				ExpiresAt:     time.Now().Add(time.Hour),
+			}}
+			user := &models.User{ID: 5, Username: "admin", Status: "active", Roles: tt.roles, LockedUntil: tt.lockedUntil}
+			service := NewLoginService(&fakeLoginRepository{user: user}, tokens, &fakeSystemRepository{}, nil)
+
+			response, err := service.Refresh(&dtos.RefreshTokenRequest{RefreshToken: "token"}, "test", "203.0.113.7")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Refresh() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if tokens.rotated {
+					t.Error("refresh token was rotated on a rejected refresh")
+				}
+				return
+			}
+
+			claims, err := utils.VerifyToken(response.Token)
+			if err != nil {
+				t.Fatalf("VerifyToken() error = %v", err)
+			}
+			if claims.RoleID == nil || *claims.RoleID != *tt.storedRole {
+				t.Errorf("token role = %v, want %d", claims.RoleID, *tt.storedRole)
+			}
+		})
+	}
+}
+
+func TestLoginUnknownUsernameLocksLikeAccounts(t *testing.T) {
+	password, err := bcrypt.GenerateFromPassword([]byte("rahasia"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &models.User{ID: 5, Username: "admin", Status: "active", Password: string(password)}
+	service := NewLoginService(&fakeLoginRepository{user: user}, nil, &fakeSystemRepository{}, NewThrottleService(&fakeFailedAttemptRepository{}))
+
+	for attempt := 1; attempt <= int(LoginThrottlePolicy.MaxPerIdentifier)+1; attempt++ {
+		_, errAccount := service.Login(&dtos.LoginRequest{Username: "admin", Password: "salah"}, "test", "203.0.113.7")
+		_, errUnknown := service.Login(&dtos.LoginRequest{Username: "tidakada", Password: "salah"}, "test", "198.51.100.9")
+		if errAccount == nil || errUnknown == nil || errAccount.Error() != errUnknown.Error() {
+			t.Fatalf("attempt %d: existing username error = %v, unknown username error = %v, want the same error", attempt, errAccount, errUnknown)
+		}
+		if attempt >= maxFailedLogins && !errors.Is(errUnknown, ErrAccountLocked) && !errors.Is(errUnknown, ErrTooManyAttempts) {
+			t.Errorf("attempt %d: unknown username error = %v, want locked or throttled", attempt, errUnknown)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"mime/multipart"
 	"strconv"
@@ -15,10 +16,10 @@ import (
 
 // MutasiSiswaService handles business logic for Mutasi Siswa
 type MutasiSiswaService interface {
-	CreatePublic(req *dtos.MutasiSiswaCreateRequest, files map[string]*multipart.FileHeader) (*dtos.MutasiSiswaResponse, error)
+	CreatePublic(ctx context.Context, req *dtos.MutasiSiswaCreateRequest, files map[string]*multipart.FileHeader) (*dtos.MutasiSiswaResponse, error)
 	GetAllWithFilter(req *dtos.MutasiSiswaGetAllRequest) (*dtos.MutasiSiswaListWithPaginationResponse, error)
 	GetByID(id uint) (*dtos.MutasiSiswaResponse, error)
-	Update(req *dtos.MutasiSiswaUpdateRequest, files map[string]*multipart.FileHeader) (*dtos.MutasiSiswaResponse, error)
+	Update(ctx context.Context, req *dtos.MutasiSiswaUpdateRequest, files map[string]*multipart.FileHeader) (*dtos.MutasiSiswaResponse, error)
 	ExportFormulirPDF(id uint) ([]byte, error)
 	Delete(id uint) error
 	ExportExcel(req *dtos.MutasiSiswaExportExcelRequest) ([]byte, error)
@@ -26,20 +27,32 @@ type MutasiSiswaService interface {
 }
 
 type MutasiSiswaServiceImpl struct {
-	repository repositories.MutasiSiswaRepository
-	r2Storage  *utils.R2Storage
+	repository     repositories.MutasiSiswaRepository
+	periodeService PeriodeAkademikService
+	storage        utils.Storage
 }
 
 // NewMutasiSiswaService creates a new Mutasi Siswa service
-func NewMutasiSiswaService(repository repositories.MutasiSiswaRepository, r2Storage *utils.R2Storage) MutasiSiswaService {
+func NewMutasiSiswaService(repository repositories.MutasiSiswaRepository, periodeService PeriodeAkademikService, storage utils.Storage) MutasiSiswaService {
 	return &MutasiSiswaServiceImpl{
-		repository: repository,
-		r2Storage:  r2Storage,
+		repository:     repository,
+		periodeService: periodeService,
+		storage:        storage,
 	}
 }
 
 // CreatePublic creates a new Mutasi Siswa from public form
-func (s *MutasiSiswaServiceImpl) CreatePublic(req *dtos.MutasiSiswaCreateRequest, files map[string]*multipart.FileHeader) (*dtos.MutasiSiswaResponse, error) {
+func (s *MutasiSiswaServiceImpl) CreatePublic(ctx context.Context, req *dtos.MutasiSiswaCreateRequest, files map[string]*multipart.FileHeader) (*dtos.MutasiSiswaResponse, error) {
+	// Default to the tahun pelajaran and semester of today
+	if req.TahunPelajaranID == 0 && req.Semester == 0 {
+		periode, err := s.periodeService.ResolvePeriode(time.Now().In(utils.JakartaLocation()))
+		if err != nil {
+			return nil, fmt.Errorf("tahun_pelajaran_id dan semester wajib diisi: %s", err.Error())
+		}
+		req.TahunPelajaranID = int(periode.TahunPelajaranID)
+		req.Semester = periode.Semester
+	}
+
 	// Generate registration number
 	nomorPendaftaran, err := s.generateRegistrationNumber(req.TahunPelajaranID, req.Semester)
 	if err != nil {
@@ -56,34 +69,38 @@ func (s *MutasiSiswaServiceImpl) CreatePublic(req *dtos.MutasiSiswaCreateRequest
 	var raporPath, akteKelahiranPath, kartuKeluargaPath, sptjmPath *string
 
 	if files["rapor"] != nil {
-		path, err := s.r2Storage.UploadFile(files["rapor"], "mutasi-siswa/rapor")
+		stored, err := utils.SaveUpload(ctx, s.storage, files["rapor"], utils.UploadCategoryDokumen, "mutasi-siswa/rapor")
 		if err != nil {
 			return nil, fmt.Errorf("gagal upload rapor: %w", err)
 		}
+		path := stored.Key
 		raporPath = &path
 	}
 
 	if files["akte_kelahiran"] != nil {
-		path, err := s.r2Storage.UploadFile(files["akte_kelahiran"], "mutasi-siswa/akte")
+		stored, err := utils.SaveUpload(ctx, s.storage, files["akte_kelahiran"], utils.UploadCategoryDokumen, "mutasi-siswa/akte")
 		if err != nil {
 			return nil, fmt.Errorf("gagal upload akte kelahiran: %w", err)
 		}
+		path := stored.Key
 		akteKelahiranPath = &path
 	}
 
 	if files["kartu_keluarga"] != nil {
-		path, err := s.r2Storage.UploadFile(files["kartu_keluarga"], "mutasi-siswa/kk")
+		stored, err := utils.SaveUpload(ctx, s.storage, files["kartu_keluarga"], utils.UploadCategoryDokumen, "mutasi-siswa/kk")
 		if err != nil {
 			return nil, fmt.Errorf("gagal upload kartu keluarga: %w", err)
 		}
+		path := stored.Key
 		kartuKeluargaPath = &path
 	}
 
 	if files["sptjm"] != nil {
-		path, err := s.r2Storage.UploadFile(files["sptjm"], "mutasi-siswa/sptjm")
+		stored, err := utils.SaveUpload(ctx, s.storage, files["sptjm"], utils.UploadCategoryDokumen, "mutasi-siswa/sptjm")
 		if err != nil {
 			return nil, fmt.Errorf("gagal upload SPTJM: %w", err)
 		}
+		path := stored.Key
 		sptjmPath = &path
 	}
 
@@ -137,7 +154,13 @@ func (s *MutasiSiswaServiceImpl) CreatePublic(req *dtos.MutasiSiswaCreateRequest
 		return nil, err
 	}
 
-	return s.mapToResponse(data), nil
+	// The public form is unauthenticated, so it does not get URLs of the private documents back
+	resp := s.mapToResponse(data)
+	resp.Rapor = nil
+	resp.AkteKelahiran = nil
+	resp.KartuKeluarga = nil
+	resp.SPTJM = nil
+	return resp, nil
 }
 
 // generateRegistrationNumber generates a new registration number based on tahun pelajaran and semester
@@ -173,26 +196,26 @@ func (s *MutasiSiswaServiceImpl) generateRegistrationNumber(tahunPelajaranID, se
 
 // mapToResponse maps MutasiSiswa model to response DTO
 func (s *MutasiSiswaServiceImpl) mapToResponse(data *models.MutasiSiswa) *dtos.MutasiSiswaResponse {
-	// Convert file keys to public URLs
+	// Convert file keys to URLs, the documents are private so they get short-lived presigned URLs
 	var raporURL, akteKelahiranURL, kartuKeluargaURL, sptjmURL *string
 	
 	if data.Rapor != nil && *data.Rapor != "" {
-		url := s.r2Storage.GetPublicURL(*data.Rapor)
+		url := utils.FileURL(s.storage, *data.Rapor)
 		raporURL = &url
 	}
 	
 	if data.AkteKelahiran != nil && *data.AkteKelahiran != "" {
-		url := s.r2Storage.GetPublicURL(*data.AkteKelahiran)
+		url := utils.FileURL(s.storage, *data.AkteKelahiran)
 		akteKelahiranURL = &url
 	}
 	
 	if data.KartuKeluarga != nil && *data.KartuKeluarga != "" {
-		url := s.r2Storage.GetPublicURL(*data.KartuKeluarga)
+		url := utils.FileURL(s.storage, *data.KartuKeluarga)
 		kartuKeluargaURL = &url
 	}
 	
 	if data.SPTJM != nil && *data.SPTJM != "" {
-		url := s.r2Storage.GetPublicURL(*data.SPTJM)
+		url := utils.FileURL(s.storage, *data.SPTJM)
 		sptjmURL = &url
 	}
 
@@ -338,7 +361,7 @@ func (s *MutasiSiswaServiceImpl) GetByID(id uint) (*dtos.MutasiSiswaResponse, er
 
 
 // Update updates Mutasi Siswa data
-func (s *MutasiSiswaServiceImpl) Update(req *dtos.MutasiSiswaUpdateRequest, files map[string]*multipart.FileHeader) (*dtos.MutasiSiswaResponse, error) {
+func (s *MutasiSiswaServiceImpl) Update(ctx context.Context, req *dtos.MutasiSiswaUpdateRequest, files map[string]*multipart.FileHeader) (*dtos.MutasiSiswaResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(req.ID)
 	if err != nil {
@@ -471,64 +494,68 @@ func (s *MutasiSiswaServiceImpl) Update(req *dtos.MutasiSiswaUpdateRequest, file
 	// Update Rapor if provided
 	if files["rapor"] != nil {
 		// Upload new rapor
-		path, err := s.r2Storage.UploadFile(files["rapor"], "mutasi-siswa/rapor")
+		stored, err := utils.SaveUpload(ctx, s.storage, files["rapor"], utils.UploadCategoryDokumen, "mutasi-siswa/rapor")
 		if err != nil {
 			return nil, fmt.Errorf("gagal upload rapor: %w", err)
 		}
+		path := stored.Key
 
 		existing.Rapor = &path
 		
 		// Delete old rapor if exists (after successful upload)
 		if oldRapor != nil && *oldRapor != "" {
-			_ = s.r2Storage.DeleteFile(*oldRapor)
+			_ = s.storage.DeleteFile(*oldRapor)
 		}
 	}
 
 	// Update Akte Kelahiran if provided
 	if files["akte_kelahiran"] != nil {
 		// Upload new akte kelahiran
-		path, err := s.r2Storage.UploadFile(files["akte_kelahiran"], "mutasi-siswa/akte")
+		stored, err := utils.SaveUpload(ctx, s.storage, files["akte_kelahiran"], utils.UploadCategoryDokumen, "mutasi-siswa/akte")
 		if err != nil {
 			return nil, fmt.Errorf("gagal upload akte kelahiran: %w", err)
 		}
+		path := stored.Key
 
 		existing.AkteKelahiran = &path
 		
 		// Delete old akte if exists (after successful upload)
 		if oldAkte != nil && *oldAkte != "" {
-			_ = s.r2Storage.DeleteFile(*oldAkte)
+			_ = s.storage.DeleteFile(*oldAkte)
 		}
 	}
 
 	// Update Kartu Keluarga if provided
 	if files["kartu_keluarga"] != nil {
 		// Upload new kartu keluarga
-		path, err := s.r2Storage.UploadFile(files["kartu_keluarga"], "mutasi-siswa/kk")
+		stored, err := utils.SaveUpload(ctx, s.storage, files["kartu_keluarga"], utils.UploadCategoryDokumen, "mutasi-siswa/kk")
 		if err != nil {
 			return nil, fmt.Errorf("gagal upload kartu keluarga: %w", err)
 		}
+		path := stored.Key
 
 		existing.KartuKeluarga = &path
 		
 		// Delete old KK if exists (after successful upload)
 		if oldKK != nil && *oldKK != "" {
-			_ = s.r2Storage.DeleteFile(*oldKK)
+			_ = s.storage.DeleteFile(*oldKK)
 		}
 	}
 
 	// Update SPTJM if provided
 	if files["sptjm"] != nil {
 		// Upload new SPTJM
-		path, err := s.r2Storage.UploadFile(files["sptjm"], "mutasi-siswa/sptjm")
+		stored, err := utils.SaveUpload(ctx, s.storage, files["sptjm"], utils.UploadCategoryDokumen, "mutasi-siswa/sptjm")
 		if err != nil {
 			return nil, fmt.Errorf("gagal upload SPTJM: %w", err)
 		}
+		path := stored.Key
 
 		existing.SPTJM = &path
 		
 		// Delete old SPTJM if exists (after successful upload)
 		if oldSPTJM != nil && *oldSPTJM != "" {
-			_ = s.r2Storage.DeleteFile(*oldSPTJM)
+			_ = s.storage.DeleteFile(*oldSPTJM)
 		}
 	}
 
@@ -550,16 +577,16 @@ func (s *MutasiSiswaServiceImpl) Delete(id uint) error {
 
 	// Delete files from R2 storage
 	if existing.Rapor != nil && *existing.Rapor != "" {
-		_ = s.r2Storage.DeleteFile(*existing.Rapor)
+		_ = s.storage.DeleteFile(*existing.Rapor)
 	}
 	if existing.AkteKelahiran != nil && *existing.AkteKelahiran != "" {
-		_ = s.r2Storage.DeleteFile(*existing.AkteKelahiran)
+		_ = s.storage.DeleteFile(*existing.AkteKelahiran)
 	}
 	if existing.KartuKeluarga != nil && *existing.KartuKeluarga != "" {
-		_ = s.r2Storage.DeleteFile(*existing.KartuKeluarga)
+		_ = s.storage.DeleteFile(*existing.KartuKeluarga)
 	}
 	if existing.SPTJM != nil && *existing.SPTJM != "" {
-		_ = s.r2Storage.DeleteFile(*existing.SPTJM)
+		_ = s.storage.DeleteFile(*existing.SPTJM)
 	}
 
 	// Delete from database
@@ -0,0 +1,398 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"text/template"
+	"time"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+)
+
+const (
+	// maxNotifikasiAttempts is how often a message is tried before it is marked failed
+	maxNotifikasiAttempts = 10
+
+	// notifikasiClaimLease is how long a claimed message stays hidden from other workers
+	notifikasiClaimLease = 5 * time.Minute
+)
+
+// NotifikasiData holds the fields available to notifikasi templates
+type NotifikasiData struct {
+	Nama     string
+	NIS      string
+	Rombel   string
+	Tanggal  string // e.g. 17-10-2026
+	Jam      string // e.g. 07:15
+	NamaOrtu string
+}
+
+// NotifikasiService queues parent notifications for attendance events and delivers the outbox
+type NotifikasiService interface {
+	NotifyTerlambat(pesertaDidik *models.PesertaDidik, waktu time.Time) error
+	NotifyAlpa(pesertaDidik *models.PesertaDidik, rombel string, tanggal time.Time) error
+	ProcessOutbox(limit int) (*dtos.NotifikasiOutboxProcessResult, error)
+	GetOutboxWithFilter(params repositories.GetNotifikasiOutboxParams) (*dtos.NotifikasiOutboxListWithPaginationResponse, error)
+	RetryOutbox(id uint) (*dtos.NotifikasiOutboxResponse, error)
+	GetTemplates() ([]dtos.NotifikasiTemplateResponse, error)
+	UpdateTemplate(req *dtos.NotifikasiTemplateUpdateRequest, actor utils.Principal) (*dtos.NotifikasiTemplateResponse, error)
+}
+
+type NotifikasiServiceImpl struct {
+	repository repositories.NotifikasiRepository
+	channels   map[string]utils.NotifikasiChannel
+}
+
+// NewNotifikasiService creates a new Notifikasi service delivering over the given channels
+func NewNotifikasiService(repository repositories.NotifikasiRepository, channels map[string]utils.NotifikasiChannel) NotifikasiService {
+	return &NotifikasiServiceImpl{
+		repository: repository,
+		channels:   channels,
+	}
+}
+
+// NotifyTerlambat queues the late arrival message for the parents of an opted-in student
+func (s *NotifikasiServiceImpl) NotifyTerlambat(pesertaDidik *models.PesertaDidik, waktu time.Time) error {
+	return s.enqueue(models.NotifikasiEventTerlambat, pesertaDidik, "", waktu, waktu.Format("15:04"))
+}
+
+// NotifyAlpa queues the absence message for the parents of an opted-in student
+func (s *NotifikasiServiceImpl) NotifyAlpa(pesertaDidik *models.PesertaDidik, rombel string, tanggal time.Time) error {
+	return s.enqueue(models.NotifikasiEventAlpa, pesertaDidik, rombel, tanggal, "")
+}
+
+// enqueue renders every active template of the event for the channels the student has contact data for.
+// The dedupe key makes sure a parent gets one message per event, day and channel.
+func (s *NotifikasiServiceImpl) enqueue(event string, pesertaDidik *models.PesertaDidik, rombel string, tanggal time.Time, jam string) error {
+	if pesertaDidik == nil || !pesertaDidik.NotifikasiOrtu {
+		return nil
+	}
+
+	templates, err := s.repository.GetActiveTemplatesByEvent(event)
+	if err != nil {
+		return err
+	}
+	if len(templates) == 0 {
+		return nil
+	}
+
+	if rombel == "" {
+		rombel, _ = s.repository.GetRombelAktifName(pesertaDidik.ID)
+	}
+
+	namaOrtu := "Bapak/Ibu Orang Tua/Wali"
+	if pesertaDidik.NamaIbu != "" {
+		namaOrtu = "Ibu " + pesertaDidik.NamaIbu
+	} else if pesertaDidik.NamaAyah != "" {
+		namaOrtu = "Bapak " + pesertaDidik.NamaAyah
+	}
+
+	data := NotifikasiData{
+		Nama:     pesertaDidik.Nama,
+		NIS:      pesertaDidik.NIS,
+		Rombel:   rombel,
+		Tanggal:  tanggal.Format("02-01-2006"),
+		Jam:      jam,
+		NamaOrtu: namaOrtu,
+	}
+
+	for _, tmpl := range templates {
+		// Channels that are not configured on this server are skipped instead of queued forever
+		if _, ok := s.channels[tmpl.Channel]; !ok {
+			continue
+		}
+
+		recipient := ""
+		switch tmpl.Channel {
+		case models.NotifikasiChannelEmail:
+			recipient = pesertaDidik.EmailOrtu
+		case models.NotifikasiChannelWhatsApp:
+			recipient = utils.NormalizeNomorHP(pesertaDidik.NomorHPOrtu)
+		}
+		if recipient == "" {
+			continue
+		}
+
+		subject, body, err := renderNotifikasiTemplate(&tmpl, data)
+		if err != nil {
+			return fmt.Errorf("template %s/%s: %w", tmpl.Event, tmpl.Channel, err)
+		}
+
+		pesertaDidikID := pesertaDidik.ID
+		if _, err := s.repository.EnqueueOutbox(&models.NotifikasiOutbox{
+			Event:          event,
+			Channel:        tmpl.Channel,
+			PesertaDidikID: &pesertaDidikID,
+			Recipient:      recipient,
+			Subject:        subject,
+			Body:           body,
+			DedupeKey:      fmt.Sprintf("%s:%d:%s:%s", event, pesertaDidik.ID, tanggal.Format("2006-01-02"), tmpl.Channel),
+			Status:         models.NotifikasiStatusPending,
+			NextAttemptAt:  time.Now(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ProcessOutbox sends up to limit due messages. Failed deliveries are retried with exponential
+// backoff (1 minute doubling up to 1 hour) until maxNotifikasiAttempts is reached.
+func (s *NotifikasiServiceImpl) ProcessOutbox(limit int) (*dtos.NotifikasiOutboxProcessResult, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	messages, err := s.repository.ClaimDueOutbox(limit, notifikasiClaimLease)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dtos.NotifikasiOutboxProcessResult{Processed: len(messages)}
+	for i := range messages {
+		message := &messages[i]
+		message.Attempts++
+
+		sendErr := errors.New("channel " + message.Channel + " tidak dikonfigurasi")
+		if channel, ok := s.channels[message.Channel]; ok {
+			subject := ""
+			if message.Subject != nil {
+				subject = *message.Subject
+			}
+			sendErr = channel.Send(utils.NotifikasiMessage{
+				Recipient: message.Recipient,
+				Subject:   subject,
+				Body:      message.Body,
+			})
+		}
+
+		now := time.Now()
+		switch {
+		case sendErr == nil:
+			message.Status = models.NotifikasiStatusSent
+			message.SentAt = &now
+			message.LastError = nil
+			result.Sent++
+		case message.Attempts >= maxNotifikasiAttempts:
+			lastError := sendErr.Error()
+			message.Status = models.NotifikasiStatusFailed
+			message.LastError = &lastError
+			result.Failed++
+		default:
+			lastError := sendErr.Error()
+			message.LastError = &lastError
+			message.NextAttemptAt = now.Add(notifikasiBackoff(message.Attempts))
+			result.Retrying++
+		}
+
+		if err := s.repository.UpdateOutbox(message); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// notifikasiBackoff returns the wait before the next attempt: 1, 2, 4, ... minutes, at most 1 hour
+func notifikasiBackoff(attempts int) time.Duration {
+	backoff := time.Minute
+	for i := 1; i < attempts && backoff < time.Hour; i++ {
+		backoff *= 2
+	}
+	if backoff > time.Hour {
+		backoff = time.Hour
+	}
+	return backoff
+}
+
+// GetOutboxWithFilter retrieves outbox messages with filters and pagination
+func (s *NotifikasiServiceImpl) GetOutboxWithFilter(params repositories.GetNotifikasiOutboxParams) (*dtos.NotifikasiOutboxListWithPaginationResponse, error) {
+	// Validate and set default limit and offset
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+	if params.Offset < 0 {
+		params.Offset = 0
+	}
+
+	data, total, err := s.repository.GetOutboxWithFilter(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map to response
+	responses := make([]dtos.NotifikasiOutboxResponse, len(data))
+	for i, item := range data {
+		responses[i] = *mapNotifikasiOutboxToResponse(&item)
+	}
+
+	totalPages := (int(total) + params.Limit - 1) / params.Limit
+
+	return &dtos.NotifikasiOutboxListWithPaginationResponse{
+		Data: responses,
+		Pagination: dtos.PaginationInfo{
+			Limit:      params.Limit,
+			Offset:     params.Offset,
+			Page:       (params.Offset / params.Limit) + 1,
+			Total:      total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// RetryOutbox puts a failed message back in the queue with a fresh set of attempts
+func (s *NotifikasiServiceImpl) RetryOutbox(id uint) (*dtos.NotifikasiOutboxResponse, error) {
+	message, err := s.repository.GetOutboxByID(id)
+	if err != nil {
+		return nil, errors.New("notifikasi tidak ditemukan")
+	}
+	if message.Status != models.NotifikasiStatusFailed {
+		return nil, errors.New("hanya notifikasi dengan status failed yang dapat dikirim ulang")
+	}
+
+	message.Status = models.NotifikasiStatusPending
+	message.Attempts = 0
+	message.NextAttemptAt = time.Now()
+	if err := s.repository.UpdateOutbox(message); err != nil {
+		return nil, err
+	}
+
+	return mapNotifikasiOutboxToResponse(message), nil
+}
+
+// GetTemplates retrieves every notifikasi template
+func (s *NotifikasiServiceImpl) GetTemplates() ([]dtos.NotifikasiTemplateResponse, error) {
+	data, err := s.repository.GetAllTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dtos.NotifikasiTemplateResponse, len(data))
+	for i, item := range data {
+		responses[i] = *mapNotifikasiTemplateToResponse(&item)
+	}
+	return responses, nil
+}
+
+// UpdateTemplate updates a template after checking that it renders
+func (s *NotifikasiServiceImpl) UpdateTemplate(req *dtos.NotifikasiTemplateUpdateRequest, actor utils.Principal) (*dtos.NotifikasiTemplateResponse, error) {
+	existing, err := s.repository.GetTemplateByID(req.ID)
+	if err != nil {
+		return nil, errors.New("template notifikasi tidak ditemukan")
+	}
+
+	if req.Subject != nil {
+		existing.Subject = req.Subject
+		if *req.Subject == "" {
+			existing.Subject = nil
+		}
+	}
+	if req.Body != nil {
+		existing.Body = *req.Body
+	}
+	if req.Status != nil {
+		existing.Status = *req.Status
+	}
+
+	// Reject templates that do not parse or use unknown fields
+	sample := NotifikasiData{
+		Nama:     "Nama Siswa",
+		NIS:      "12345",
+		Rombel:   "1A",
+		Tanggal:  "17-10-2026",
+		Jam:      "07:15",
+		NamaOrtu: "Bapak/Ibu Orang Tua/Wali",
+	}
+	if _, _, err := renderNotifikasiTemplate(existing, sample); err != nil {
+		return nil, fmt.Errorf("template tidak valid: %v", err)
+	}
+
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
+
+	if err := s.repository.UpdateTemplate(existing); err != nil {
+		return nil, err
+	}
+
+	return mapNotifikasiTemplateToResponse(existing), nil
+}
+
+// renderNotifikasiTemplate executes the subject (if any) and body templates with the data
+func renderNotifikasiTemplate(tmpl *models.NotifikasiTemplate, data NotifikasiData) (*string, string, error) {
+	var subject *string
+	if tmpl.Subject != nil && *tmpl.Subject != "" {
+		rendered, err := executeNotifikasiTemplate("subject", *tmpl.Subject, data)
+		if err != nil {
+			return nil, "", err
+		}
+		subject = &rendered
+	}
+
+	body, err := executeNotifikasiTemplate("body", tmpl.Body, data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return subject, body, nil
+}
+
+// executeNotifikasiTemplate parses and executes one text/template
+func executeNotifikasiTemplate(name, text string, data NotifikasiData) (string, error) {
+	parsed, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := parsed.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// mapNotifikasiTemplateToResponse maps NotifikasiTemplate model to response DTO
+func mapNotifikasiTemplateToResponse(data *models.NotifikasiTemplate) *dtos.NotifikasiTemplateResponse {
+	return &dtos.NotifikasiTemplateResponse{
+		ID:            data.ID,
+		Event:         data.Event,
+		Channel:       data.Channel,
+		Subject:       data.Subject,
+		Body:          data.Body,
+		Status:        data.Status,
+		CreatedAt:     data.CreatedAt,
+		UpdatedAt:     data.UpdatedAt,
+		UpdatedByID:   data.UpdatedByID,
+		UpdatedByType: data.UpdatedByType,
+	}
+}
+
+// mapNotifikasiOutboxToResponse maps NotifikasiOutbox model to response DTO
+func mapNotifikasiOutboxToResponse(data *models.NotifikasiOutbox) *dtos.NotifikasiOutboxResponse {
+	response := &dtos.NotifikasiOutboxResponse{
+		ID:             data.ID,
+		Event:          data.Event,
+		Channel:        data.Channel,
+		PesertaDidikID: data.PesertaDidikID,
+		Recipient:      data.Recipient,
+		Subject:        data.Subject,
+		Body:           data.Body,
+		Status:         data.Status,
+		Attempts:       data.Attempts,
+		NextAttemptAt:  data.NextAttemptAt,
+		LastError:      data.LastError,
+		SentAt:         data.SentAt,
+		CreatedAt:      data.CreatedAt,
+	}
+	if data.PesertaDidik != nil {
+		response.NamaSiswa = data.PesertaDidik.Nama
+	}
+	return response
+}
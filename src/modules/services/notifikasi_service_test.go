@@ -0,0 +1,162 @@
+package services
+
+import (
+	"errors"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+	"testing"
+	"time"
+)
+
+// fakeNotifikasiRepository hands out the given due messages and keeps the updated ones
+type fakeNotifikasiRepository struct {
+	repositories.NotifikasiRepository
+	due     []models.NotifikasiOutbox
+	updated map[uint]models.NotifikasiOutbox
+}
+
+func (r *fakeNotifikasiRepository) ClaimDueOutbox(limit int, lease time.Duration) ([]models.NotifikasiOutbox, error) {
+	if len(r.due) > limit {
+		return r.due[:limit], nil
+	}
+	return r.due, nil
+}
+
+func (r *fakeNotifikasiRepository) UpdateOutbox(data *models.NotifikasiOutbox) error {
+	if r.updated == nil {
+		r.updated = make(map[uint]models.NotifikasiOutbox)
+	}
+	r.updated[data.ID] = *data
+	return nil
+}
+
+func (r *fakeNotifikasiRepository) GetOutboxByID(id uint) (*models.NotifikasiOutbox, error) {
+	for _, message := range r.due {
+		if message.ID == id {
+			return &message, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+// fakeNotifikasiChannel fails every send while err is set
+type fakeNotifikasiChannel struct {
+	err  error
+	sent []utils.NotifikasiMessage
+}
+
+func (c *fakeNotifikasiChannel) Send(message utils.NotifikasiMessage) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.sent = append(c.sent, message)
+	return nil
+}
+
+func TestNotifikasiBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{6, 32 * time.Minute},
+		{7, time.Hour},
+		{maxNotifikasiAttempts, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := notifikasiBackoff(tt.attempts); got != tt.want {
+			t.Errorf("notifikasiBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestProcessOutbox(t *testing.T) {
+	tests := []struct {
+		name         string
+		channel      string
+		attempts     int // before this run
+		sendErr      error
+		wantStatus   string
+		wantAttempts int
+		wantBackoff  time.Duration // 0 when no next attempt is scheduled
+		wantError    bool
+	}{
+		{"delivered", models.NotifikasiChannelEmail, 0, nil, models.NotifikasiStatusSent, 1, 0, false},
+		{"first failure is retried", models.NotifikasiChannelEmail, 0, errors.New("smtp timeout"), models.NotifikasiStatusPending, 1, time.Minute, true},
+		{"later failure backs off longer", models.NotifikasiChannelEmail, 3, errors.New("smtp timeout"), models.NotifikasiStatusPending, 4, 8 * time.Minute, true},
+		{"last attempt fails the message", models.NotifikasiChannelEmail, maxNotifikasiAttempts - 1, errors.New("smtp timeout"), models.NotifikasiStatusFailed, maxNotifikasiAttempts, 0, true},
+		{"channel not configured is retried", models.NotifikasiChannelWhatsApp, 0, nil, models.NotifikasiStatusPending, 1, time.Minute, true},
+		{"delivered after earlier failures", models.NotifikasiChannelEmail, 4, nil, models.NotifikasiStatusSent, 5, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lastError := "gagal sebelumnya"
+			repository := &fakeNotifikasiRepository{due: []models.NotifikasiOutbox{{
+				ID:        1,
+				Channel:   tt.channel,
+				Recipient: "ortu@example.com",
+				Body:      "Ananda terlambat",
+				Status:    models.NotifikasiStatusPending,
+				Attempts:  tt.attempts,
+				LastError: &lastError,
+			}}}
+			email := &fakeNotifikasiChannel{err: tt.sendErr}
+			service := NewNotifikasiService(repository, map[string]utils.NotifikasiChannel{models.NotifikasiChannelEmail: email})
+
+			start := time.Now()
+			if _, err := service.ProcessOutbox(10); err != nil {
+				t.Fatalf("ProcessOutbox() error = %v", err)
+			}
+
+			message, ok := repository.updated[1]
+			if !ok {
+				t.Fatal("message was not updated")
+			}
+			if message.Status != tt.wantStatus || message.Attempts != tt.wantAttempts {
+				t.Errorf("status/attempts = %s/%d, want %s/%d", message.Status, message.Attempts, tt.wantStatus, tt.wantAttempts)
+			}
+			if (message.LastError != nil) != tt.wantError {
+				t.Errorf("LastError = %v, want set %v", message.LastError, tt.wantError)
+			}
+			if tt.wantBackoff > 0 {
+				if wait := message.NextAttemptAt.Sub(start); wait < tt.wantBackoff || wait > tt.wantBackoff+time.Minute {
+					t.Errorf("next attempt in %v, want %v", wait, tt.wantBackoff)
+				}
+			}
+			if tt.wantStatus == models.NotifikasiStatusSent && (message.SentAt == nil || len(email.sent) != 1) {
+				t.Errorf("SentAt = %v, sends = %d, want a delivered message", message.SentAt, len(email.sent))
+			}
+		})
+	}
+}
+
+func TestRetryOutbox(t *testing.T) {
+	repository := &fakeNotifikasiRepository{due: []models.NotifikasiOutbox{
+		{ID: 1, Status: models.NotifikasiStatusFailed, Attempts: maxNotifikasiAttempts},
+		{ID: 2, Status: models.NotifikasiStatusPending, Attempts: 3},
+		{ID: 3, Status: models.NotifikasiStatusSent, Attempts: 1},
+	}}
+	service := NewNotifikasiService(repository, nil)
+
+	if _, err := service.RetryOutbox(1); err != nil {
+		t.Fatalf("RetryOutbox(failed) error = %v", err)
+	}
+	message := repository.updated[1]
+	if message.Status != models.NotifikasiStatusPending || message.Attempts != 0 || message.NextAttemptAt.After(time.Now()) {
+		t.Errorf("retried message = %s/%d due %v, want pending/0 due now", message.Status, message.Attempts, message.NextAttemptAt)
+	}
+
+	for _, id := range []uint{2, 3, 4} {
+		if _, err := service.RetryOutbox(id); err == nil {
+			t.Errorf("RetryOutbox(%d) error = nil, want an error", id)
+		}
+	}
+	if len(repository.updated) != 1 {
+		t.Errorf("updated %d messages, want only the failed one", len(repository.updated))
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/rand"
@@ -15,27 +16,27 @@ import (
 
 // PengaduanService handles business logic for Pengaduan
 type PengaduanService interface {
-	CreatePublic(files []*multipart.FileHeader, req *dtos.PengaduanCreateRequest) (*dtos.PengaduanResponse, error)
+	CreatePublic(ctx context.Context, files []*multipart.FileHeader, req *dtos.PengaduanCreateRequest) (*dtos.PengaduanResponse, error)
 	TrackByIDTiket(idTiket string) (*dtos.PengaduanTrackResponse, error)
 	GetAllWithFilter(req *dtos.PengaduanGetAllRequest) (*dtos.PengaduanListWithPaginationResponse, error)
 	GetByID(id uint) (*dtos.PengaduanResponse, error)
-	SendReply(files []*multipart.FileHeader, req *dtos.PengaduanSendReplyRequest, userID uint) (*dtos.PengaduanResponse, error)
-	SaveTindakLanjut(files []*multipart.FileHeader, req *dtos.PengaduanSaveTindakLanjutRequest, userID uint) (*dtos.PengaduanResponse, error)
+	SendReply(ctx context.Context, files []*multipart.FileHeader, req *dtos.PengaduanSendReplyRequest, actor utils.Principal) (*dtos.PengaduanResponse, error)
+	SaveTindakLanjut(ctx context.Context, files []*multipart.FileHeader, req *dtos.PengaduanSaveTindakLanjutRequest, actor utils.Principal) (*dtos.PengaduanResponse, error)
 	ClosePengaduan(id uint) (*dtos.PengaduanResponse, error)
-	DeletePengaduan(id uint, userID uint) error
+	DeletePengaduan(id uint, actor utils.Principal) error
 }
 
 type PengaduanServiceImpl struct {
 	repository   repositories.PengaduanRepository
-	r2Storage    *utils.R2Storage
+	storage      utils.Storage
 	emailService *utils.EmailService
 }
 
 // NewPengaduanService creates a new Pengaduan service
-func NewPengaduanService(repository repositories.PengaduanRepository, r2Storage *utils.R2Storage) PengaduanService {
+func NewPengaduanService(repository repositories.PengaduanRepository, storage utils.Storage) PengaduanService {
 	return &PengaduanServiceImpl{
 		repository:   repository,
-		r2Storage:    r2Storage,
+		storage:      storage,
 		emailService: utils.NewEmailService(),
 	}
 }
@@ -56,7 +57,7 @@ func (s *PengaduanServiceImpl) generateTicketID() string {
 }
 
 // CreatePublic creates a new Pengaduan from public form
-func (s *PengaduanServiceImpl) CreatePublic(files []*multipart.FileHeader, req *dtos.PengaduanCreateRequest) (*dtos.PengaduanResponse, error) {
+func (s *PengaduanServiceImpl) CreatePublic(ctx context.Context, files []*multipart.FileHeader, req *dtos.PengaduanCreateRequest) (*dtos.PengaduanResponse, error) {
 	// Upload files if provided
 	var fileItems []models.FileItem
 	if len(files) > 0 {
@@ -65,20 +66,16 @@ func (s *PengaduanServiceImpl) CreatePublic(files []*multipart.FileHeader, req *
 				continue
 			}
 
-			// Validate file size (max 10MB per file)
-			if file.Size > 10*1024*1024 {
-				return nil, fmt.Errorf("each file must not exceed 10MB")
-			}
-
-			// Upload file to R2 in layanan-umpan-balik/pengaduan directory
-			fileKey, err := s.r2Storage.UploadFile(file, "layanan-umpan-balik/pengaduan")
+			// Validate the file from its content and upload to R2
+			stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryDokumen, "layanan-umpan-balik/pengaduan")
 			if err != nil {
 				// Cleanup already uploaded files on error
 				for _, item := range fileItems {
-					_ = s.r2Storage.DeleteFile(item.URL)
+					_ = s.storage.DeleteFile(item.URL)
 				}
 				return nil, err
 			}
+			fileKey := stored.Key
 
 			// Generate unique file ID: file_timestamp_randomstring
 			fileID := fmt.Sprintf("file_%d_%s", time.Now().UnixNano(), fileKey[len(fileKey)-8:])
@@ -87,7 +84,7 @@ func (s *PengaduanServiceImpl) CreatePublic(files []*multipart.FileHeader, req *
 				ID:       fileID,
 				Filename: file.Filename,
 				URL:      fileKey,
-				Size:     file.Size,
+				Size:     stored.Size,
 			})
 		}
 	}
@@ -140,12 +137,17 @@ func (s *PengaduanServiceImpl) CreatePublic(files []*multipart.FileHeader, req *
 	if err := s.repository.Create(data); err != nil {
 		// Cleanup uploaded files on database error
 		for _, item := range fileItems {
-			_ = s.r2Storage.DeleteFile(item.URL)
+			_ = s.storage.DeleteFile(item.URL)
 		}
 		return nil, err
 	}
 
-	return s.mapToResponse(data), nil
+	// The public form is unauthenticated, so it does not get URLs of the private attachments back
+	resp := s.mapToResponse(data)
+	for i := range resp.FilePengaduan {
+		resp.FilePengaduan[i].URL = ""
+	}
+	return resp, nil
 }
 
 // mapToResponse converts model to response DTO
@@ -156,9 +158,9 @@ func (s *PengaduanServiceImpl) mapToResponse(data *models.Pengaduan) *dtos.Penga
 		_ = json.Unmarshal(data.FilePengaduan, &filePengaduan)
 	}
 
-	// Convert file URLs to full public URLs
+	// Convert file keys to URLs, attachments are private so they get short-lived presigned URLs
 	for i := range filePengaduan {
-		filePengaduan[i].URL = s.r2Storage.GetPublicURL(filePengaduan[i].URL)
+		filePengaduan[i].URL = utils.FileURL(s.storage, filePengaduan[i].URL)
 	}
 
 	// Parse file_jawaban JSON
@@ -167,9 +169,9 @@ func (s *PengaduanServiceImpl) mapToResponse(data *models.Pengaduan) *dtos.Penga
 		_ = json.Unmarshal(data.FileJawaban, &fileJawaban)
 	}
 
-	// Convert file URLs to full public URLs
+	// Convert file keys to URLs, attachments are private so they get short-lived presigned URLs
 	for i := range fileJawaban {
-		fileJawaban[i].URL = s.r2Storage.GetPublicURL(fileJawaban[i].URL)
+		fileJawaban[i].URL = utils.FileURL(s.storage, fileJawaban[i].URL)
 	}
 
 	// Parse file_tindak_lanjut JSON
@@ -178,9 +180,9 @@ func (s *PengaduanServiceImpl) mapToResponse(data *models.Pengaduan) *dtos.Penga
 		_ = json.Unmarshal(data.FileTindakLanjut, &fileTindakLanjut)
 	}
 
-	// Convert file URLs to full public URLs
+	// Convert file keys to URLs, attachments are private so they get short-lived presigned URLs
 	for i := range fileTindakLanjut {
-		fileTindakLanjut[i].URL = s.r2Storage.GetPublicURL(fileTindakLanjut[i].URL)
+		fileTindakLanjut[i].URL = utils.FileURL(s.storage, fileTindakLanjut[i].URL)
 	}
 
 	resp := &dtos.PengaduanResponse{
@@ -314,7 +316,7 @@ func (s *PengaduanServiceImpl) GetByID(id uint) (*dtos.PengaduanResponse, error)
 }
 
 // SendReply sends email reply and updates pengaduan record
-func (s *PengaduanServiceImpl) SendReply(files []*multipart.FileHeader, req *dtos.PengaduanSendReplyRequest, userID uint) (*dtos.PengaduanResponse, error) {
+func (s *PengaduanServiceImpl) SendReply(ctx context.Context, files []*multipart.FileHeader, req *dtos.PengaduanSendReplyRequest, actor utils.Principal) (*dtos.PengaduanResponse, error) {
 	// Get pengaduan data
 	data, err := s.repository.GetByID(req.ID)
 	if err != nil {
@@ -334,20 +336,16 @@ func (s *PengaduanServiceImpl) SendReply(files []*multipart.FileHeader, req *dto
 				continue
 			}
 
-			// Validate file size (max 10MB per file)
-			if file.Size > 10*1024*1024 {
-				return nil, fmt.Errorf("each file must not exceed 10MB")
-			}
-
-			// Upload file to R2
-			fileKey, err := s.r2Storage.UploadFile(file, "layanan-umpan-balik/pengaduan/jawaban")
+			// Validate the file from its content and upload to R2
+			stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryDokumen, "layanan-umpan-balik/pengaduan/jawaban")
 			if err != nil {
 				// Cleanup already uploaded files on error
 				for _, item := range fileItems {
-					_ = s.r2Storage.DeleteFile(item.URL)
+					_ = s.storage.DeleteFile(item.URL)
 				}
 				return nil, err
 			}
+			fileKey := stored.Key
 
 			// Generate unique file ID
 			fileID := fmt.Sprintf("file_%d_%s", time.Now().UnixNano(), fileKey[len(fileKey)-8:])
@@ -356,7 +354,7 @@ func (s *PengaduanServiceImpl) SendReply(files []*multipart.FileHeader, req *dto
 				ID:       fileID,
 				Filename: file.Filename,
 				URL:      fileKey,
-				Size:     file.Size,
+				Size:     stored.Size,
 			})
 		}
 	}
@@ -378,13 +376,14 @@ func (s *PengaduanServiceImpl) SendReply(files []*multipart.FileHeader, req *dto
 	data.TanggalProses = &now
 	data.EmailTerkirim = false // Set to false first, will update after email sent
 	data.Status = "processed"
-	data.RepliedBy = &userID
+	data.RepliedBy = &actor.ID
+	data.RepliedByType = actor.TypePtr()
 
 	// Save to database first
 	if err := s.repository.Update(data); err != nil {
 		// Cleanup uploaded files if database update fails
 		for _, item := range fileItems {
-			_ = s.r2Storage.DeleteFile(item.URL)
+			_ = s.storage.DeleteFile(item.URL)
 		}
 		return nil, fmt.Errorf("gagal menyimpan data ke database: %w", err)
 	}
@@ -400,7 +399,7 @@ func (s *PengaduanServiceImpl) SendReply(files []*multipart.FileHeader, req *dto
 	for _, item := range filePengaduanItems {
 		filePengaduanLinks = append(filePengaduanLinks, utils.FileLink{
 			Name: item.Filename,
-			URL:  s.r2Storage.GetPublicURL(item.URL),
+			URL:  utils.FileURLWithExpiry(s.storage, item.URL, utils.PrivateEmailURLExpirationMinutes),
 		})
 	}
 
@@ -408,7 +407,7 @@ func (s *PengaduanServiceImpl) SendReply(files []*multipart.FileHeader, req *dto
 	for _, item := range fileItems {
 		fileJawabanLinks = append(fileJawabanLinks, utils.FileLink{
 			Name: item.Filename,
-			URL:  s.r2Storage.GetPublicURL(item.URL),
+			URL:  utils.FileURLWithExpiry(s.storage, item.URL, utils.PrivateEmailURLExpirationMinutes),
 		})
 	}
 
@@ -457,7 +456,7 @@ func (s *PengaduanServiceImpl) SendReply(files []*multipart.FileHeader, req *dto
 }
 
 // SaveTindakLanjut saves tindak lanjut for pengaduan
-func (s *PengaduanServiceImpl) SaveTindakLanjut(files []*multipart.FileHeader, req *dtos.PengaduanSaveTindakLanjutRequest, userID uint) (*dtos.PengaduanResponse, error) {
+func (s *PengaduanServiceImpl) SaveTindakLanjut(ctx context.Context, files []*multipart.FileHeader, req *dtos.PengaduanSaveTindakLanjutRequest, actor utils.Principal) (*dtos.PengaduanResponse, error) {
 	// Get pengaduan data
 	data, err := s.repository.GetByID(req.ID)
 	if err != nil {
@@ -488,7 +487,7 @@ func (s *PengaduanServiceImpl) SaveTindakLanjut(files []*multipart.FileHeader, r
 			if deleteMap[file.ID] {
 				// Delete from R2
 				fmt.Printf("DEBUG - Deleting file from R2: ID='%s', URL='%s'\n", file.ID, file.URL)
-				if err := s.r2Storage.DeleteFile(file.URL); err != nil {
+				if err := s.storage.DeleteFile(file.URL); err != nil {
 					fmt.Printf("ERROR - Failed to delete file from R2: %v\n", err)
 				} else {
 					fmt.Printf("SUCCESS - File deleted from R2: %s\n", file.URL)
@@ -508,17 +507,13 @@ func (s *PengaduanServiceImpl) SaveTindakLanjut(files []*multipart.FileHeader, r
 				continue
 			}
 
-			// Validate file size (max 10MB per file)
-			if file.Size > 10*1024*1024 {
-				return nil, fmt.Errorf("each file must not exceed 10MB")
-			}
-
-			// Upload file to R2
-			fileKey, err := s.r2Storage.UploadFile(file, "layanan-umpan-balik/pengaduan/tindak-lanjut")
+			// Validate the file from its content and upload to R2
+			stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryDokumen, "layanan-umpan-balik/pengaduan/tindak-lanjut")
 			if err != nil {
 				// Cleanup already uploaded files on error
 				return nil, err
 			}
+			fileKey := stored.Key
 
 			// Generate unique file ID
 			fileID := fmt.Sprintf("file_%d_%s", time.Now().UnixNano(), fileKey[len(fileKey)-8:])
@@ -527,7 +522,7 @@ func (s *PengaduanServiceImpl) SaveTindakLanjut(files []*multipart.FileHeader, r
 				ID:       fileID,
 				Filename: file.Filename,
 				URL:      fileKey,
-				Size:     file.Size,
+				Size:     stored.Size,
 			})
 		}
 	}
@@ -580,7 +575,7 @@ func (s *PengaduanServiceImpl) ClosePengaduan(id uint) (*dtos.PengaduanResponse,
 }
 
 // DeletePengaduan soft deletes pengaduan by setting deleted_at and deleted_by_id
-func (s *PengaduanServiceImpl) DeletePengaduan(id uint, userID uint) error {
+func (s *PengaduanServiceImpl) DeletePengaduan(id uint, actor utils.Principal) error {
 	// Check if pengaduan exists
 	_, err := s.repository.GetByID(id)
 	if err != nil {
@@ -588,7 +583,7 @@ func (s *PengaduanServiceImpl) DeletePengaduan(id uint, userID uint) error {
 	}
 
 	// Soft delete with user tracking
-	if err := s.repository.SoftDeleteWithUser(id, userID); err != nil {
+	if err := s.repository.SoftDeleteWithUser(id, actor.ID, actor.TypePtr()); err != nil {
 		return fmt.Errorf("gagal menghapus pengaduan: %w", err)
 	}
 
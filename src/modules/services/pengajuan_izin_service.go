@@ -0,0 +1,553 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+)
+
+const (
+	// maxHariPengajuanIzin caps the calendar days of one request
+	maxHariPengajuanIzin = 14
+	// batasMundurPengajuanIzin is how many days back a request may start, for notes handed in afterwards
+	batasMundurPengajuanIzin = 7
+)
+
+// PengajuanIzinService handles business logic for izin/sakit requests and their approval by the wali kelas
+type PengajuanIzinService interface {
+	CreatePublic(ctx context.Context, file *multipart.FileHeader, req *dtos.PengajuanIzinPublicCreateRequest, ipAddress string) (*dtos.PengajuanIzinResponse, error)
+	CreateBySiswa(ctx context.Context, pesertaDidikID uint, file *multipart.FileHeader, req *dtos.PengajuanIzinCreateRequest) (*dtos.PengajuanIzinResponse, error)
+	TrackByIDTiket(idTiket string) (*dtos.PengajuanIzinTrackResponse, error)
+	GetBySiswa(pesertaDidikID uint) ([]dtos.PengajuanIzinResponse, error)
+	GetAllWithFilter(req *dtos.PengajuanIzinGetAllRequest, actor utils.Principal) (*dtos.PengajuanIzinListWithPaginationResponse, error)
+	GetByID(id uint, actor utils.Principal) (*dtos.PengajuanIzinResponse, error)
+	Approve(req *dtos.PengajuanIzinReviewRequest, actor utils.Principal, ipAddress string) (*dtos.PengajuanIzinApproveResponse, error)
+	Reject(req *dtos.PengajuanIzinReviewRequest, actor utils.Principal) (*dtos.PengajuanIzinResponse, error)
+}
+
+type PengajuanIzinServiceImpl struct {
+	repository             repositories.PengajuanIzinRepository
+	pesertaDidikRepo       repositories.PesertaDidikRepository
+	pesertaDidikRombelRepo repositories.PesertaDidikRombelRepository
+	periodeService         PeriodeAkademikService
+	kepegawaianRepo        repositories.KepegawaianRepository
+	kalenderService        KalenderAkademikService
+	throttleService        ThrottleService
+	storage                utils.Storage
+}
+
+// NewPengajuanIzinService creates a new PengajuanIzin service
+func NewPengajuanIzinService(
+	repository repositories.PengajuanIzinRepository,
+	pesertaDidikRepo repositories.PesertaDidikRepository,
+	pesertaDidikRombelRepo repositories.PesertaDidikRombelRepository,
+	periodeService PeriodeAkademikService,
+	kepegawaianRepo repositories.KepegawaianRepository,
+	kalenderService KalenderAkademikService,
+	throttleService ThrottleService,
+	storage utils.Storage,
+) PengajuanIzinService {
+	return &PengajuanIzinServiceImpl{
+		repository:             repository,
+		pesertaDidikRepo:       pesertaDidikRepo,
+		pesertaDidikRombelRepo: pesertaDidikRombelRepo,
+		periodeService:         periodeService,
+		kepegawaianRepo:        kepegawaianRepo,
+		kalenderService:        kalenderService,
+		throttleService:        throttleService,
+		storage:                storage,
+	}
+}
+
+// generateTicketID generates unique ticket ID: IZN-YYYYMMDD-XXXXXX
+func (s *PengajuanIzinServiceImpl) generateTicketID() string {
+	dateStr := time.Now().In(utils.JakartaLocation()).Format("20060102")
+
+	// 6 random alphanumeric characters from crypto/rand, the ticket is the only key of the public tracking page
+	return fmt.Sprintf("IZN-%s-%s", dateStr, generateRandomString(6))
+}
+
+// CreatePublic creates a PengajuanIzin from the public form after matching NIS and tanggal lahir.
+// A wrong pair counts as a failed attempt for the requesting IP and the NIS.
+func (s *PengajuanIzinServiceImpl) CreatePublic(ctx context.Context, file *multipart.FileHeader, req *dtos.PengajuanIzinPublicCreateRequest, ipAddress string) (*dtos.PengajuanIzinResponse, error) {
+	nis := strings.TrimSpace(req.NIS)
+	if err := s.throttleService.Check(PengajuanIzinThrottlePolicy, ipAddress, nis); err != nil {
+		return nil, err
+	}
+
+	// Same message for both mismatches so the form cannot be used to probe NIS
+	errTidakSesuai := errors.New("NIS atau tanggal lahir tidak sesuai")
+
+	pesertaDidik, err := s.pesertaDidikRepo.GetByNIS(nis)
+	if err != nil {
+		s.throttleService.RecordFailure(PengajuanIzinThrottlePolicy, ipAddress, nis)
+		return nil, errTidakSesuai
+	}
+	if pesertaDidik.TanggalLahir == nil || pesertaDidik.TanggalLahir.Format("2006-01-02") != strings.TrimSpace(req.TanggalLahir) {
+		s.throttleService.RecordFailure(PengajuanIzinThrottlePolicy, ipAddress, nis)
+		return nil, errTidakSesuai
+	}
+	s.throttleService.Reset(PengajuanIzinThrottlePolicy, nis)
+
+	if strings.TrimSpace(req.NamaPengaju) == "" {
+		return nil, errors.New("nama pengaju wajib diisi")
+	}
+
+	resp, err := s.create(ctx, pesertaDidik, file, &req.PengajuanIzinCreateRequest, models.PengajuanIzinMelaluiPublik)
+	if err != nil {
+		return nil, err
+	}
+
+	// The public form is unauthenticated, so it does not get the URL of the private surat back
+	resp.FileSurat = ""
+	return resp, nil
+}
+
+// CreateBySiswa creates a PengajuanIzin for the logged-in student
+func (s *PengajuanIzinServiceImpl) CreateBySiswa(ctx context.Context, pesertaDidikID uint, file *multipart.FileHeader, req *dtos.PengajuanIzinCreateRequest) (*dtos.PengajuanIzinResponse, error) {
+	pesertaDidik, err := s.pesertaDidikRepo.GetByID(pesertaDidikID)
+	if err != nil {
+		return nil, errors.New("data siswa tidak ditemukan")
+	}
+
+	if strings.TrimSpace(req.NamaPengaju) == "" {
+		req.NamaPengaju = pesertaDidik.Nama
+	}
+
+	return s.create(ctx, pesertaDidik, file, req, models.PengajuanIzinMelaluiSiswa)
+}
+
+// create validates the request against the active rombel and kalender akademik, uploads the surat and stores it
+func (s *PengajuanIzinServiceImpl) create(ctx context.Context, pesertaDidik *models.PesertaDidik, file *multipart.FileHeader, req *dtos.PengajuanIzinCreateRequest, melalui string) (*dtos.PengajuanIzinResponse, error) {
+	if pesertaDidik.Status != "active" {
+		return nil, errors.New("siswa tidak aktif")
+	}
+
+	// Parse tanggal (YYYY-MM-DD format)
+	tanggalMulai, err := time.Parse("2006-01-02", req.TanggalMulai)
+	if err != nil {
+		return nil, errors.New("format tanggal_mulai tidak valid, gunakan YYYY-MM-DD")
+	}
+	tanggalSelesai, err := time.Parse("2006-01-02", req.TanggalSelesai)
+	if err != nil {
+		return nil, errors.New("format tanggal_selesai tidak valid, gunakan YYYY-MM-DD")
+	}
+	if tanggalSelesai.Before(tanggalMulai) {
+		return nil, errors.New("tanggal_selesai tidak boleh sebelum tanggal_mulai")
+	}
+	if tanggalSelesai.Sub(tanggalMulai) >= maxHariPengajuanIzin*24*time.Hour {
+		return nil, fmt.Errorf("pengajuan izin paling lama %d hari", maxHariPengajuanIzin)
+	}
+	today := dateOnly(time.Now().In(utils.JakartaLocation()))
+	if tanggalMulai.Before(today.AddDate(0, 0, -batasMundurPengajuanIzin)) {
+		return nil, fmt.Errorf("tanggal_mulai paling lambat %d hari yang lalu", batasMundurPengajuanIzin)
+	}
+
+	// A doctor's note is required for sakit
+	if req.Jenis == "sakit" && file == nil {
+		return nil, errors.New("surat keterangan sakit wajib dilampirkan")
+	}
+
+	// The request belongs to the rombel of the tahun pelajaran of its dates
+	periodes, err := s.periodeService.LoadPeriode()
+	if err != nil {
+		return nil, err
+	}
+	periodeMulai, err := periodes.Resolve(tanggalMulai)
+	if err != nil {
+		return nil, err
+	}
+	periodeSelesai, err := periodes.TahunPelajaranPada(tanggalSelesai)
+	if err != nil || periodeSelesai.TahunPelajaranID != periodeMulai.TahunPelajaranID {
+		return nil, errors.New("rentang tanggal tidak boleh melewati pergantian tahun pelajaran")
+	}
+	mapping, err := s.pesertaDidikRombelRepo.GetByPesertaDidikAndTahunPelajaran(pesertaDidik.ID, periodeMulai.TahunPelajaranID)
+	if err != nil {
+		return nil, fmt.Errorf("siswa belum terdaftar di rombel pada tahun pelajaran %s", periodeMulai.TahunPelajaran)
+	}
+
+	kalender, err := s.kalenderService.LoadKalender(tanggalMulai, tanggalSelesai)
+	if err != nil {
+		return nil, err
+	}
+	if len(kalender.HariEfektif(tanggalMulai, tanggalSelesai)) == 0 {
+		return nil, errors.New("rentang tanggal tidak mencakup hari sekolah")
+	}
+
+	overlap, err := s.repository.HasOverlap(pesertaDidik.ID, tanggalMulai, tanggalSelesai)
+	if err != nil {
+		return nil, errors.New("gagal memeriksa pengajuan izin")
+	}
+	if overlap {
+		return nil, errors.New("sudah ada pengajuan izin pada rentang tanggal tersebut")
+	}
+
+	// Upload surat to R2 in absensi-siswa/pengajuan-izin folder
+	var fileSuratPath string
+	if file != nil {
+		stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryDokumen, "absensi-siswa/pengajuan-izin")
+		if err != nil {
+			return nil, fmt.Errorf("gagal upload file: %s", err.Error())
+		}
+		uploadedPath := stored.Key
+		fileSuratPath = uploadedPath
+	}
+
+	pengajuan := &models.PengajuanIzin{
+		IDTiket:              s.generateTicketID(),
+		PesertaDidikID:       pesertaDidik.ID,
+		PesertaDidikRombelID: mapping.ID,
+		RombelID:             mapping.RombelID,
+		TahunPelajaranID:     periodeMulai.TahunPelajaranID,
+		Jenis:                req.Jenis,
+		TanggalMulai:         tanggalMulai,
+		TanggalSelesai:       tanggalSelesai,
+		Alasan:               strings.TrimSpace(req.Alasan),
+		FileSurat:            fileSuratPath,
+		NamaPengaju:          strings.TrimSpace(req.NamaPengaju),
+		TeleponPengaju:       strings.TrimSpace(req.TeleponPengaju),
+		DiajukanMelalui:      melalui,
+		Status:               models.PengajuanIzinStatusPending,
+	}
+
+	if err := s.repository.Create(pengajuan); err != nil {
+		// Clean up uploaded file if save fails
+		if fileSuratPath != "" {
+			_ = s.storage.DeleteFile(fileSuratPath)
+		}
+		return nil, fmt.Errorf("gagal menyimpan pengajuan izin: %s", err.Error())
+	}
+
+	created, err := s.repository.GetByID(pengajuan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mapToResponse(created), nil
+}
+
+// TrackByIDTiket retrieves the status of a PengajuanIzin by ID Tiket
+func (s *PengajuanIzinServiceImpl) TrackByIDTiket(idTiket string) (*dtos.PengajuanIzinTrackResponse, error) {
+	data, err := s.repository.GetByIDTiket(strings.TrimSpace(idTiket))
+	if err != nil {
+		return nil, fmt.Errorf("pengajuan izin dengan ID Tiket %s tidak ditemukan", idTiket)
+	}
+
+	response := &dtos.PengajuanIzinTrackResponse{
+		IDTiket:        data.IDTiket,
+		Jenis:          data.Jenis,
+		TanggalMulai:   data.TanggalMulai.Format("2006-01-02"),
+		TanggalSelesai: data.TanggalSelesai.Format("2006-01-02"),
+		Status:         data.Status,
+		CatatanReview:  data.CatatanReview,
+		ReviewedAt:     data.ReviewedAt,
+		CreatedAt:      data.CreatedAt,
+	}
+	if data.PesertaDidik != nil {
+		response.NamaSiswa = data.PesertaDidik.Nama
+	}
+	if data.Rombel != nil {
+		response.Rombel = data.Rombel.Name
+	}
+
+	return response, nil
+}
+
+// GetBySiswa retrieves the most recent requests of the logged-in student
+func (s *PengajuanIzinServiceImpl) GetBySiswa(pesertaDidikID uint) ([]dtos.PengajuanIzinResponse, error) {
+	data, _, err := s.repository.GetAllWithFilter(repositories.GetPengajuanIzinParams{
+		Filter: repositories.GetPengajuanIzinFilter{PesertaDidikID: &pesertaDidikID},
+		Limit:  50,
+	})
+	if err != nil {
+		return nil, errors.New("gagal mengambil data pengajuan izin")
+	}
+
+	responses := make([]dtos.PengajuanIzinResponse, len(data))
+	for i := range data {
+		responses[i] = *s.mapToResponse(&data[i])
+	}
+	return responses, nil
+}
+
+// GetAllWithFilter retrieves the approval queue. A pegawai who is wali kelas only sees their own rombel.
+func (s *PengajuanIzinServiceImpl) GetAllWithFilter(req *dtos.PengajuanIzinGetAllRequest, actor utils.Principal) (*dtos.PengajuanIzinListWithPaginationResponse, error) {
+	// Set default pagination
+	limit := 10
+	page := 1
+	if req.Pagination.Limit > 0 && req.Pagination.Limit <= 100 {
+		limit = req.Pagination.Limit
+	}
+	if req.Pagination.Page > 0 {
+		page = req.Pagination.Page
+	}
+	offset := (page - 1) * limit
+
+	filter := repositories.GetPengajuanIzinFilter{
+		IDTiket:          req.Search.IDTiket,
+		Status:           req.Search.Status,
+		Jenis:            req.Search.Jenis,
+		RombelID:         req.Search.RombelID,
+		PesertaDidikID:   req.Search.PesertaDidikID,
+		TahunPelajaranID: req.Search.TahunPelajaranID,
+	}
+	if req.Search.TanggalMulai != "" {
+		t, err := time.Parse("2006-01-02", req.Search.TanggalMulai)
+		if err != nil {
+			return nil, errors.New("format tanggal_mulai tidak valid, gunakan YYYY-MM-DD")
+		}
+		filter.TanggalMulai = &t
+	}
+	if req.Search.TanggalSelesai != "" {
+		t, err := time.Parse("2006-01-02", req.Search.TanggalSelesai)
+		if err != nil {
+			return nil, errors.New("format tanggal_selesai tidak valid, gunakan YYYY-MM-DD")
+		}
+		filter.TanggalSelesai = &t
+	}
+
+	rombelWaliKelas, err := s.rombelWaliKelas(actor)
+	if err != nil {
+		return nil, err
+	}
+	if rombelWaliKelas != nil {
+		filter.RombelID = rombelWaliKelas
+	}
+
+	data, total, err := s.repository.GetAllWithFilter(repositories.GetPengajuanIzinParams{
+		Filter: filter,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, errors.New("gagal mengambil data pengajuan izin")
+	}
+
+	responses := make([]dtos.PengajuanIzinResponse, len(data))
+	for i := range data {
+		responses[i] = *s.mapToResponse(&data[i])
+	}
+
+	totalPages := (int(total) + limit - 1) / limit
+
+	return &dtos.PengajuanIzinListWithPaginationResponse{
+		Data: responses,
+		Pagination: dtos.PaginationInfo{
+			Limit:      limit,
+			Offset:     offset,
+			Page:       page,
+			Total:      total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// GetByID retrieves PengajuanIzin by ID
+func (s *PengajuanIzinServiceImpl) GetByID(id uint, actor utils.Principal) (*dtos.PengajuanIzinResponse, error) {
+	data, err := s.getForReviewer(id, actor)
+	if err != nil {
+		return nil, err
+	}
+	return s.mapToResponse(data), nil
+}
+
+// Approve approves a pending request and records izin/sakit for every effective school day in its range
+func (s *PengajuanIzinServiceImpl) Approve(req *dtos.PengajuanIzinReviewRequest, actor utils.Principal, ipAddress string) (*dtos.PengajuanIzinApproveResponse, error) {
+	pengajuan, err := s.getForReviewer(req.ID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if pengajuan.Status != models.PengajuanIzinStatusPending {
+		return nil, repositories.ErrPengajuanIzinSudahDireview
+	}
+
+	kalender, err := s.kalenderService.LoadKalender(pengajuan.TanggalMulai, pengajuan.TanggalSelesai)
+	if err != nil {
+		return nil, err
+	}
+	periodes, err := s.periodeService.LoadPeriode()
+	if err != nil {
+		return nil, err
+	}
+
+	keterangan := fmt.Sprintf("Pengajuan %s: %s", pengajuan.IDTiket, pengajuan.Alasan)
+	rombelID := pengajuan.RombelID
+	var rows []models.RekapitulasiAbsensi
+	for _, tanggal := range kalender.HariEfektif(pengajuan.TanggalMulai, pengajuan.TanggalSelesai) {
+		// Days outside the semesters of the pengajuan's tahun pelajaran are not recorded
+		periode, err := periodes.Resolve(tanggal)
+		if err != nil || periode.TahunPelajaranID != pengajuan.TahunPelajaranID {
+			continue
+		}
+		semester := periode.Semester
+
+		rows = append(rows, models.RekapitulasiAbsensi{
+			PesertaDidikRombelID: pengajuan.PesertaDidikRombelID,
+			RombelID:             &rombelID,
+			TahunPelajaranID:     pengajuan.TahunPelajaranID,
+			Semester:             semester,
+			Tanggal:              tanggal,
+			Status:               pengajuan.Jenis,
+			MetodeInput:          models.MetodeInputPengajuan,
+			Keterangan:           keterangan,
+			FileSurat:            pengajuan.FileSurat,
+			DicatatOlehID:        &actor.ID,
+			DicatatOlehType:      actor.TypePtr(),
+		})
+	}
+
+	now := time.Now()
+	pengajuan.Status = models.PengajuanIzinStatusApproved
+	pengajuan.ReviewedByID = &actor.ID
+	pengajuan.ReviewedByType = actor.TypePtr()
+	pengajuan.ReviewedAt = &now
+	if catatan := strings.TrimSpace(req.Catatan); catatan != "" {
+		pengajuan.CatatanReview = &catatan
+	}
+
+	written, skipped, err := s.repository.Approve(pengajuan, rows, auditRekapAbsensi(models.SumberRiwayatRekapPengajuan, actor, ipAddress))
+	if err != nil {
+		if errors.Is(err, repositories.ErrPengajuanIzinSudahDireview) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("gagal menyetujui pengajuan izin: %s", err.Error())
+	}
+
+	tanggalDilewati := make([]string, len(skipped))
+	for i, tanggal := range skipped {
+		tanggalDilewati[i] = tanggal.Format("2006-01-02")
+	}
+
+	return &dtos.PengajuanIzinApproveResponse{
+		Pengajuan:       *s.mapToResponse(pengajuan),
+		HariTercatat:    written,
+		TanggalDilewati: tanggalDilewati,
+	}, nil
+}
+
+// Reject rejects a pending request; the catatan tells the parent why
+func (s *PengajuanIzinServiceImpl) Reject(req *dtos.PengajuanIzinReviewRequest, actor utils.Principal) (*dtos.PengajuanIzinResponse, error) {
+	catatan := strings.TrimSpace(req.Catatan)
+	if catatan == "" {
+		return nil, errors.New("catatan wajib diisi saat menolak pengajuan izin")
+	}
+
+	pengajuan, err := s.getForReviewer(req.ID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if pengajuan.Status != models.PengajuanIzinStatusPending {
+		return nil, repositories.ErrPengajuanIzinSudahDireview
+	}
+
+	now := time.Now()
+	pengajuan.Status = models.PengajuanIzinStatusRejected
+	pengajuan.CatatanReview = &catatan
+	pengajuan.ReviewedByID = &actor.ID
+	pengajuan.ReviewedByType = actor.TypePtr()
+	pengajuan.ReviewedAt = &now
+
+	if err := s.repository.Reject(pengajuan); err != nil {
+		if errors.Is(err, repositories.ErrPengajuanIzinSudahDireview) {
+			return nil, err
+		}
+		return nil, errors.New("gagal menolak pengajuan izin")
+	}
+
+	return s.mapToResponse(pengajuan), nil
+}
+
+// getForReviewer retrieves a request the actor may review
+func (s *PengajuanIzinServiceImpl) getForReviewer(id uint, actor utils.Principal) (*models.PengajuanIzin, error) {
+	pengajuan, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, errors.New("pengajuan izin tidak ditemukan")
+	}
+
+	rombelWaliKelas, err := s.rombelWaliKelas(actor)
+	if err != nil {
+		return nil, err
+	}
+	if rombelWaliKelas != nil && *rombelWaliKelas != pengajuan.RombelID {
+		return nil, errors.New("pengajuan izin bukan dari rombel perwalian anda")
+	}
+
+	return pengajuan, nil
+}
+
+// rolesTanpaPerwalian are the role name prefixes of pegawai who review the pengajuan of every rombel
+var rolesTanpaPerwalian = []string{"Administrator", "Kepala Sekolah", "Tata Usaha"}
+
+// rombelWaliKelas returns the rombel of a pegawai who is guru kelas, or nil for those who may review every
+// rombel: admin users and pegawai whose active role is an administrator, kepala sekolah or tata usaha role.
+// Any other pegawai without a perwalian gets an error instead of the whole queue.
+func (s *PengajuanIzinServiceImpl) rombelWaliKelas(actor utils.Principal) (*uint, error) {
+	switch actor.Type {
+	case utils.PrincipalUser:
+		return nil, nil
+	case utils.PrincipalPegawai:
+	default:
+		return nil, errors.New("anda tidak berwenang meninjau pengajuan izin")
+	}
+
+	pegawai, err := s.kepegawaianRepo.GetByIDWithRoles(actor.ID)
+	if err != nil {
+		return nil, errors.New("data pegawai tidak ditemukan")
+	}
+	if pegawai.RombelGuruKelasID != nil {
+		return pegawai.RombelGuruKelasID, nil
+	}
+	for _, role := range pegawai.Roles {
+		if actor.RoleID != nil && role.ID != *actor.RoleID {
+			continue
+		}
+		for _, prefix := range rolesTanpaPerwalian {
+			if strings.HasPrefix(role.Name, prefix) {
+				return nil, nil
+			}
+		}
+	}
+	return nil, errors.New("anda bukan wali kelas dari rombel manapun")
+}
+
+// mapToResponse maps PengajuanIzin model to PengajuanIzinResponse DTO
+func (s *PengajuanIzinServiceImpl) mapToResponse(data *models.PengajuanIzin) *dtos.PengajuanIzinResponse {
+	response := &dtos.PengajuanIzinResponse{
+		ID:                 data.ID,
+		IDTiket:            data.IDTiket,
+		PesertaDidikID:     data.PesertaDidikID,
+		RombelID:           data.RombelID,
+		TahunPelajaranID:   data.TahunPelajaranID,
+		Jenis:              data.Jenis,
+		TanggalMulai:       data.TanggalMulai.Format("2006-01-02"),
+		TanggalSelesai:     data.TanggalSelesai.Format("2006-01-02"),
+		Alasan:             data.Alasan,
+		FileSurat:          utils.FileURL(s.storage, data.FileSurat),
+		NamaPengaju:        data.NamaPengaju,
+		TeleponPengaju:     data.TeleponPengaju,
+		DiajukanMelalui:    data.DiajukanMelalui,
+		Status:             data.Status,
+		CatatanReview:      data.CatatanReview,
+		JumlahHariTercatat: data.JumlahHariTercatat,
+		ReviewedByID:       data.ReviewedByID,
+		ReviewedByType:     data.ReviewedByType,
+		ReviewedAt:         data.ReviewedAt,
+		CreatedAt:          data.CreatedAt,
+	}
+	if data.PesertaDidik != nil {
+		response.NIS = data.PesertaDidik.NIS
+		response.NamaSiswa = data.PesertaDidik.Nama
+	}
+	if data.Rombel != nil {
+		response.Rombel = data.Rombel.Name
+	}
+	return response
+}
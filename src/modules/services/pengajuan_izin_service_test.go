@@ -0,0 +1,246 @@
+package services
+
+import (
+	"errors"
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+	"testing"
+	"time"
+)
+
+// fakePengajuanIzinRepository keeps one request in memory and the rekap rows written by Approve. Like the
+// conditional updates of the real repository, reviews fail once the stored request is no longer pending.
+type fakePengajuanIzinRepository struct {
+	repositories.PengajuanIzinRepository
+	pengajuan models.PengajuanIzin
+	rows      []models.RekapitulasiAbsensi
+	audit     repositories.AuditRekapAbsensi
+	updated   *models.PengajuanIzin
+	// reviewedMeanwhile makes the write find the request already reviewed by someone else
+	reviewedMeanwhile bool
+}
+
+func (r *fakePengajuanIzinRepository) GetByID(id uint) (*models.PengajuanIzin, error) {
+	if id != r.pengajuan.ID {
+		return nil, errors.New("record not found")
+	}
+	pengajuan := r.pengajuan
+	return &pengajuan, nil
+}
+
+func (r *fakePengajuanIzinRepository) Approve(data *models.PengajuanIzin, rows []models.RekapitulasiAbsensi, audit repositories.AuditRekapAbsensi) (int, []time.Time, error) {
+	r.updated = data
+	r.rows = rows
+	r.audit = audit
+	return len(rows), nil, nil
+}
+
+func (r *fakePengajuanIzinRepository) Reject(data *models.PengajuanIzin) error {
+	if r.reviewedMeanwhile || r.pengajuan.Status != models.PengajuanIzinStatusPending {
+		return repositories.ErrPengajuanIzinSudahDireview
+	}
+	r.updated = data
+	return nil
+}
+
+// fakeKepegawaianRepository only implements loading a pegawai with their roles by ID
+type fakeKepegawaianRepository struct {
+	repositories.KepegawaianRepository
+	pegawai map[uint]*models.Kepegawaian
+}
+
+func (r *fakeKepegawaianRepository) GetByIDWithRoles(id uint) (*models.Kepegawaian, error) {
+	if pegawai, ok := r.pegawai[id]; ok {
+		return pegawai, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+// fakeKalenderLoader loads a kalender where Monday to Friday are school days
+type fakeKalenderLoader struct {
+	KalenderAkademikService
+}
+
+func (s *fakeKalenderLoader) LoadKalender(tanggalMulai, tanggalSelesai time.Time) (*KalenderSekolah, error) {
+	return &KalenderSekolah{hariSekolah: map[time.Weekday]bool{
+		time.Monday: true, time.Tuesday: true, time.Wednesday: true, time.Thursday: true, time.Friday: true,
+	}}, nil
+}
+
+// newTestPengajuanIzinService builds the service with wali kelas 10 of rombel 5, guru 11 of rombel 6,
+// kepala sekolah 12 and guru mapel 13 who holds no perwalian
+func newTestPengajuanIzinService(pengajuan models.PengajuanIzin) (*PengajuanIzinServiceImpl, *fakePengajuanIzinRepository) {
+	rombel5, rombel6 := uint(5), uint(6)
+	repository := &fakePengajuanIzinRepository{pengajuan: pengajuan}
+	service := &PengajuanIzinServiceImpl{
+		repository: repository,
+		kepegawaianRepo: &fakeKepegawaianRepository{pegawai: map[uint]*models.Kepegawaian{
+			10: {ID: 10, RombelGuruKelasID: &rombel5},
+			11: {ID: 11, RombelGuruKelasID: &rombel6},
+			12: {ID: 12, Roles: []models.Role{{ID: 3, Name: "Guru"}, {ID: 2, Name: "Kepala Sekolah (PINTU)"}}},
+			13: {ID: 13, Roles: []models.Role{{ID: 3, Name: "Guru"}}},
+		}},
+		kalenderService: &fakeKalenderLoader{},
+		periodeService:  newTestPeriodeAkademikService(),
+		storage:         utils.NewMemoryStorage(),
+	}
+	return service, repository
+}
+
+func TestApprovePengajuanIzin(t *testing.T) {
+	// Thursday 15 to Monday 19 October 2026 holds three school days
+	pending := models.PengajuanIzin{
+		ID:                   1,
+		IDTiket:              "IZN-20261014-ABC123",
+		PesertaDidikRombelID: 3,
+		RombelID:             5,
+		TahunPelajaranID:     2,
+		Jenis:                "sakit",
+		TanggalMulai:         time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC),
+		TanggalSelesai:       time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+		Alasan:               "Demam",
+		Status:               models.PengajuanIzinStatusPending,
+	}
+	approved := pending
+	approved.Status = models.PengajuanIzinStatusApproved
+
+	tests := []struct {
+		name      string
+		pengajuan models.PengajuanIzin
+		actor     utils.Principal
+		wantErr   bool
+	}{
+		{"wali kelas of the rombel", pending, utils.Principal{ID: 10, Type: utils.PrincipalPegawai}, false},
+		{"admin user", pending, utils.Principal{ID: 1, Type: utils.PrincipalUser}, false},
+		{"kepala sekolah", pending, utils.Principal{ID: 12, Type: utils.PrincipalPegawai}, false},
+		{"guru without perwalian", pending, utils.Principal{ID: 13, Type: utils.PrincipalPegawai}, true},
+		{"wali kelas of another rombel", pending, utils.Principal{ID: 11, Type: utils.PrincipalPegawai}, true},
+		{"unknown pegawai", pending, utils.Principal{ID: 99, Type: utils.PrincipalPegawai}, true},
+		{"already reviewed", approved, utils.Principal{ID: 10, Type: utils.PrincipalPegawai}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repository := newTestPengajuanIzinService(tt.pengajuan)
+
+			response, err := service.Approve(&dtos.PengajuanIzinReviewRequest{ID: 1, Catatan: " Semoga lekas sembuh "}, tt.actor, "203.0.113.7")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Approve() error = nil, want an error")
+				}
+				if repository.updated != nil {
+					t.Error("request was written on a rejected approval")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Approve() error = %v", err)
+			}
+
+			if response.HariTercatat != 3 || len(repository.rows) != 3 {
+				t.Fatalf("HariTercatat = %d, rows = %d, want 3 school days", response.HariTercatat, len(repository.rows))
+			}
+			for i, want := range []int{15, 16, 19} {
+				row := repository.rows[i]
+				if row.Tanggal.Day() != want || row.Status != "sakit" || row.MetodeInput != models.MetodeInputPengajuan {
+					t.Errorf("row %d = %s %s %s, want 2026-10-%d sakit %s", i, row.Tanggal.Format("2006-01-02"), row.Status, row.MetodeInput, want, models.MetodeInputPengajuan)
+				}
+				if row.DicatatOlehID == nil || *row.DicatatOlehID != tt.actor.ID || *row.DicatatOlehType != tt.actor.Type {
+					t.Errorf("row %d recorded by %v, want %s %d", i, row.DicatatOlehID, tt.actor.Type, tt.actor.ID)
+				}
+			}
+
+			if audit := repository.audit; audit.Sumber != models.SumberRiwayatRekapPengajuan || *audit.ActorID != tt.actor.ID || audit.IPAddress != "203.0.113.7" {
+				t.Errorf("audit = %s by %v from %s, want pengajuan by %d from 203.0.113.7", audit.Sumber, audit.ActorID, audit.IPAddress, tt.actor.ID)
+			}
+
+			updated := repository.updated
+			if updated.Status != models.PengajuanIzinStatusApproved || updated.ReviewedAt == nil || *updated.ReviewedByID != tt.actor.ID {
+				t.Errorf("request = %s reviewed by %v, want approved by %d", updated.Status, updated.ReviewedByID, tt.actor.ID)
+			}
+			if updated.CatatanReview == nil || *updated.CatatanReview != "Semoga lekas sembuh" {
+				t.Errorf("CatatanReview = %v, want the trimmed catatan", updated.CatatanReview)
+			}
+		})
+	}
+}
+
+func TestRejectPengajuanIzin(t *testing.T) {
+	pending := models.PengajuanIzin{ID: 1, RombelID: 5, Status: models.PengajuanIzinStatusPending}
+	rejected := pending
+	rejected.Status = models.PengajuanIzinStatusRejected
+	waliKelas := utils.Principal{ID: 10, Type: utils.PrincipalPegawai}
+
+	tests := []struct {
+		name      string
+		pengajuan models.PengajuanIzin
+		actor     utils.Principal
+		catatan   string
+		wantErr   bool
+	}{
+		{"wali kelas of the rombel", pending, waliKelas, "Surat tidak terbaca", false},
+		{"without catatan", pending, waliKelas, "  ", true},
+		{"wali kelas of another rombel", pending, utils.Principal{ID: 11, Type: utils.PrincipalPegawai}, "Surat tidak terbaca", true},
+		{"already reviewed", rejected, waliKelas, "Surat tidak terbaca", true},
+		{"approved by another reviewer meanwhile", pending, waliKelas, "Surat tidak terbaca", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repository := newTestPengajuanIzinService(tt.pengajuan)
+			repository.reviewedMeanwhile = tt.name == "approved by another reviewer meanwhile"
+
+			_, err := service.Reject(&dtos.PengajuanIzinReviewRequest{ID: 1, Catatan: tt.catatan}, tt.actor)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reject() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if repository.updated != nil {
+					t.Error("request was written on a rejected review")
+				}
+				return
+			}
+			if repository.updated.Status != models.PengajuanIzinStatusRejected || *repository.updated.CatatanReview != tt.catatan {
+				t.Errorf("request = %s with catatan %v, want rejected with the catatan", repository.updated.Status, repository.updated.CatatanReview)
+			}
+		})
+	}
+}
+
+func TestRombelWaliKelas(t *testing.T) {
+	service, _ := newTestPengajuanIzinService(models.PengajuanIzin{})
+	kepalaSekolahRole, guruRole := uint(2), uint(3)
+
+	tests := []struct {
+		name       string
+		actor      utils.Principal
+		wantRombel uint // 0 = every rombel
+		wantErr    bool
+	}{
+		{"admin user", utils.Principal{ID: 1, Type: utils.PrincipalUser}, 0, false},
+		{"wali kelas", utils.Principal{ID: 10, Type: utils.PrincipalPegawai}, 5, false},
+		{"kepala sekolah", utils.Principal{ID: 12, Type: utils.PrincipalPegawai}, 0, false},
+		{"kepala sekolah with the active role", utils.Principal{ID: 12, Type: utils.PrincipalPegawai, RoleID: &kepalaSekolahRole}, 0, false},
+		{"kepala sekolah logged in as guru", utils.Principal{ID: 12, Type: utils.PrincipalPegawai, RoleID: &guruRole}, 0, true},
+		{"guru without perwalian", utils.Principal{ID: 13, Type: utils.PrincipalPegawai}, 0, true},
+		{"unknown pegawai", utils.Principal{ID: 99, Type: utils.PrincipalPegawai}, 0, true},
+		{"siswa", utils.Principal{ID: 1, Type: utils.PrincipalSiswa}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rombel, err := service.rombelWaliKelas(tt.actor)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("rombelWaliKelas() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (rombel == nil) != (tt.wantRombel == 0) || (rombel != nil && *rombel != tt.wantRombel) {
+				t.Errorf("rombelWaliKelas() = %v, want rombel %d", rombel, tt.wantRombel)
+			}
+		})
+	}
+}
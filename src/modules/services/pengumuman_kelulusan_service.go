@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"mime/multipart"
@@ -13,26 +14,26 @@ import (
 )
 
 type PengumumanKelulusanService interface {
-	ConfigurePengumuman(req *dtos.PengumumanKelulusanConfigRequest, fotoKepsek *multipart.FileHeader, ttdKepsek *multipart.FileHeader, userID uint) (*dtos.PengumumanKelulusanResponse, error)
+	ConfigurePengumuman(ctx context.Context, req *dtos.PengumumanKelulusanConfigRequest, fotoKepsek *multipart.FileHeader, ttdKepsek *multipart.FileHeader, actor utils.Principal) (*dtos.PengumumanKelulusanResponse, error)
 	GetPengumuman() (*dtos.PengumumanKelulusanResponse, error)
 	GetSettingPengumumanPublic() (*dtos.PengumumanKelulusanResponse, error)
 }
 
 type PengumumanKelulusanServiceImpl struct {
 	repository repositories.PengumumanKelulusanRepository
-	r2Storage  *utils.R2Storage
+	storage    utils.Storage
 }
 
 // NewPengumumanKelulusanService creates a new PengumumanKelulusan service
 func NewPengumumanKelulusanService(repository repositories.PengumumanKelulusanRepository) PengumumanKelulusanService {
 	return &PengumumanKelulusanServiceImpl{
 		repository: repository,
-		r2Storage:  utils.NewR2Storage(),
+		storage:    utils.NewStorage(),
 	}
 }
 
 // ConfigurePengumuman creates or updates pengumuman kelulusan configuration
-func (s *PengumumanKelulusanServiceImpl) ConfigurePengumuman(req *dtos.PengumumanKelulusanConfigRequest, fotoKepsek *multipart.FileHeader, ttdKepsek *multipart.FileHeader, userID uint) (*dtos.PengumumanKelulusanResponse, error) {
+func (s *PengumumanKelulusanServiceImpl) ConfigurePengumuman(ctx context.Context, req *dtos.PengumumanKelulusanConfigRequest, fotoKepsek *multipart.FileHeader, ttdKepsek *multipart.FileHeader, actor utils.Principal) (*dtos.PengumumanKelulusanResponse, error) {
 	// Parse tanggal_pengumuman_nilai (YYYY-MM-DD HH:MM:SS format)
 	tanggalNilai, err := time.Parse("2006-01-02 15:04:05", req.TanggalPengumumanNilai)
 	if err != nil {
@@ -60,26 +61,28 @@ func (s *PengumumanKelulusanServiceImpl) ConfigurePengumuman(req *dtos.Pengumuma
 		existing.TanggalPengumumanNilai = tanggalNilai
 		existing.TanggalPengumumanKelulusan = tanggalKelulusan
 		existing.NamaKepsek = req.NamaKepsek
-		existing.UpdatedByID = &userID
+		existing.UpdatedByID = &actor.ID
+		existing.UpdatedByType = actor.TypePtr()
 
 		// Handle foto_kepsek deletion if requested
 		if req.DeleteFotoKepsek {
 			if oldFotoKepsek != "" {
-				_ = s.r2Storage.DeleteFile(oldFotoKepsek)
+				_ = s.storage.DeleteFile(oldFotoKepsek)
 			}
 			existing.FotoKepsek = ""
 		}
 
 		// Handle foto_kepsek upload if provided
 		if fotoKepsek != nil {
-			uploadedPath, err := s.r2Storage.UploadFile(fotoKepsek, "pengumuman-kelulusan")
+			stored, err := utils.SaveUpload(ctx, s.storage, fotoKepsek, utils.UploadCategoryFoto, "pengumuman-kelulusan")
 			if err != nil {
 				return nil, fmt.Errorf("gagal upload foto kepsek: %s", err.Error())
 			}
+			uploadedPath := stored.Key
 
 			// Delete old file if exists
 			if oldFotoKepsek != "" && oldFotoKepsek != uploadedPath {
-				_ = s.r2Storage.DeleteFile(oldFotoKepsek)
+				_ = s.storage.DeleteFile(oldFotoKepsek)
 			}
 
 			existing.FotoKepsek = uploadedPath
@@ -88,21 +91,22 @@ func (s *PengumumanKelulusanServiceImpl) ConfigurePengumuman(req *dtos.Pengumuma
 		// Handle ttd_kepsek deletion if requested
 		if req.DeleteTtdKepsek {
 			if oldTtdKepsek != "" {
-				_ = s.r2Storage.DeleteFile(oldTtdKepsek)
+				_ = s.storage.DeleteFile(oldTtdKepsek)
 			}
 			existing.TtdKepsek = ""
 		}
 
 		// Handle ttd_kepsek upload if provided
 		if ttdKepsek != nil {
-			uploadedPath, err := s.r2Storage.UploadFile(ttdKepsek, "pengumuman-kelulusan")
+			stored, err := utils.SaveUpload(ctx, s.storage, ttdKepsek, utils.UploadCategoryGambar, "pengumuman-kelulusan")
 			if err != nil {
 				return nil, fmt.Errorf("gagal upload ttd kepsek: %s", err.Error())
 			}
+			uploadedPath := stored.Key
 
 			// Delete old file if exists
 			if oldTtdKepsek != "" && oldTtdKepsek != uploadedPath {
-				_ = s.r2Storage.DeleteFile(oldTtdKepsek)
+				_ = s.storage.DeleteFile(oldTtdKepsek)
 			}
 
 			existing.TtdKepsek = uploadedPath
@@ -120,23 +124,25 @@ func (s *PengumumanKelulusanServiceImpl) ConfigurePengumuman(req *dtos.Pengumuma
 
 	// Handle foto_kepsek upload if provided
 	if fotoKepsek != nil {
-		uploadedPath, err := s.r2Storage.UploadFile(fotoKepsek, "pengumuman-kelulusan")
+		stored, err := utils.SaveUpload(ctx, s.storage, fotoKepsek, utils.UploadCategoryFoto, "pengumuman-kelulusan")
 		if err != nil {
 			return nil, fmt.Errorf("gagal upload foto kepsek: %s", err.Error())
 		}
+		uploadedPath := stored.Key
 		fotoKepsekPath = uploadedPath
 	}
 
 	// Handle ttd_kepsek upload if provided
 	if ttdKepsek != nil {
-		uploadedPath, err := s.r2Storage.UploadFile(ttdKepsek, "pengumuman-kelulusan")
+		stored, err := utils.SaveUpload(ctx, s.storage, ttdKepsek, utils.UploadCategoryGambar, "pengumuman-kelulusan")
 		if err != nil {
 			// Delete foto_kepsek if ttd_kepsek upload fails
 			if fotoKepsekPath != "" {
-				s.r2Storage.DeleteFile(fotoKepsekPath)
+				s.storage.DeleteFile(fotoKepsekPath)
 			}
 			return nil, fmt.Errorf("gagal upload ttd kepsek: %s", err.Error())
 		}
+		uploadedPath := stored.Key
 		ttdKepsekPath = uploadedPath
 	}
 
@@ -147,17 +153,19 @@ func (s *PengumumanKelulusanServiceImpl) ConfigurePengumuman(req *dtos.Pengumuma
 		FotoKepsek:                 fotoKepsekPath,
 		TtdKepsek:                  ttdKepsekPath,
 		NamaKepsek:                 req.NamaKepsek,
-		CreatedByID:                &userID,
-		UpdatedByID:                &userID,
+		CreatedByID:                &actor.ID,
+		CreatedByType:              actor.TypePtr(),
+		UpdatedByID:                &actor.ID,
+		UpdatedByType:              actor.TypePtr(),
 	}
 
 	if err := s.repository.Create(pengumuman); err != nil {
 		// Delete uploaded files if save to DB failed
 		if fotoKepsekPath != "" {
-			s.r2Storage.DeleteFile(fotoKepsekPath)
+			s.storage.DeleteFile(fotoKepsekPath)
 		}
 		if ttdKepsekPath != "" {
-			s.r2Storage.DeleteFile(ttdKepsekPath)
+			s.storage.DeleteFile(ttdKepsekPath)
 		}
 		return nil, errors.New("gagal menyimpan konfigurasi pengumuman")
 	}
@@ -188,8 +196,8 @@ func (s *PengumumanKelulusanServiceImpl) GetSettingPengumumanPublic() (*dtos.Pen
 // mapToResponse maps PengumumanKelulusan model to PengumumanKelulusanResponse DTO
 func (s *PengumumanKelulusanServiceImpl) mapToResponse(data *models.PengumumanKelulusan) *dtos.PengumumanKelulusanResponse {
 	// Generate full URLs for files
-	fotoKepsekURL := s.r2Storage.GetPublicURL(data.FotoKepsek)
-	ttdKepsekURL := s.r2Storage.GetPublicURL(data.TtdKepsek)
+	fotoKepsekURL := s.storage.GetPublicURL(data.FotoKepsek)
+	ttdKepsekURL := s.storage.GetPublicURL(data.TtdKepsek)
 
 	response := &dtos.PengumumanKelulusanResponse{
 		ID:                         data.ID,
@@ -202,7 +210,11 @@ func (s *PengumumanKelulusanServiceImpl) mapToResponse(data *models.PengumumanKe
 		CreatedAt:                  data.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt:                  data.UpdatedAt.Format("2006-01-02 15:04:05"),
 		CreatedByID:                data.CreatedByID,
+		CreatedByType:              data.CreatedByType,
 		UpdatedByID:                data.UpdatedByID,
+		UpdatedByType:              data.UpdatedByType,
+		CreatedBy:                  data.CreatedBy,
+		UpdatedBy:                  data.UpdatedBy,
 	}
 
 	return response
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+)
+
+// Periode akademik errors
+var (
+	ErrDiLuarSemester               = errors.New("tanggal berada di luar periode semester, periksa tanggal semester pada tahun pelajaran")
+	ErrTahunPelajaranTidakDiatur    = errors.New("tidak ada tahun pelajaran dengan tanggal semester untuk tanggal tersebut")
+	ErrSemesterTahunPelajaranKosong = errors.New("tanggal semester tahun pelajaran belum diatur")
+)
+
+// PeriodeAkademik is one semester of a tahun pelajaran with its start and end date
+type PeriodeAkademik struct {
+	TahunPelajaranID uint
+	TahunPelajaran   string
+	Semester         int
+	TanggalMulai     time.Time
+	TanggalSelesai   time.Time
+}
+
+// Contains reports whether the date lies inside the semester, both ends inclusive
+func (p PeriodeAkademik) Contains(tanggal time.Time) bool {
+	key := tanggal.Format("2006-01-02")
+	return key >= p.TanggalMulai.Format("2006-01-02") && key <= p.TanggalSelesai.Format("2006-01-02")
+}
+
+// DaftarPeriodeAkademik is every configured semester, oldest first. Load it once to resolve many dates.
+type DaftarPeriodeAkademik []PeriodeAkademik
+
+// Resolve maps a date to its tahun pelajaran and semester
+func (d DaftarPeriodeAkademik) Resolve(tanggal time.Time) (*PeriodeAkademik, error) {
+	for i := range d {
+		if d[i].Contains(tanggal) {
+			periode := d[i]
+			return &periode, nil
+		}
+	}
+	return nil, ErrDiLuarSemester
+}
+
+// TahunPelajaranPada returns the semester that started last on or before the date, so the holidays
+// after a semester still belong to its tahun pelajaran
+func (d DaftarPeriodeAkademik) TahunPelajaranPada(tanggal time.Time) (*PeriodeAkademik, error) {
+	key := tanggal.Format("2006-01-02")
+	for i := len(d) - 1; i >= 0; i-- {
+		if d[i].TanggalMulai.Format("2006-01-02") <= key {
+			periode := d[i]
+			return &periode, nil
+		}
+	}
+	return nil, ErrTahunPelajaranTidakDiatur
+}
+
+// PeriodeAkademikService maps dates to (tahun pelajaran, semester) from the semester dates stored on tahun
+// pelajaran. Every service that needs the semester of a date goes through it.
+type PeriodeAkademikService interface {
+	LoadPeriode() (DaftarPeriodeAkademik, error)
+	ResolvePeriode(tanggal time.Time) (*PeriodeAkademik, error)
+	ResolveTahunPelajaran(tanggal time.Time) (*PeriodeAkademik, error)
+	GetPeriode(tahunPelajaranID uint, semester int) (*PeriodeAkademik, error)
+}
+
+type PeriodeAkademikServiceImpl struct {
+	tahunPelajaranRepo repositories.TahunPelajaranRepository
+}
+
+// NewPeriodeAkademikService creates a new PeriodeAkademik service
+func NewPeriodeAkademikService(tahunPelajaranRepo repositories.TahunPelajaranRepository) PeriodeAkademikService {
+	return &PeriodeAkademikServiceImpl{tahunPelajaranRepo: tahunPelajaranRepo}
+}
+
+// LoadPeriode loads the semesters of every tahun pelajaran whose semester dates are set
+func (s *PeriodeAkademikServiceImpl) LoadPeriode() (DaftarPeriodeAkademik, error) {
+	data, err := s.tahunPelajaranRepo.GetAllWithSemester()
+	if err != nil {
+		return nil, errors.New("gagal mengambil periode semester tahun pelajaran")
+	}
+
+	var daftar DaftarPeriodeAkademik
+	for i := range data {
+		daftar = append(daftar, periodeTahunPelajaran(&data[i])...)
+	}
+	return daftar, nil
+}
+
+// ResolvePeriode maps a date to its tahun pelajaran and semester, ErrDiLuarSemester when it is in none
+func (s *PeriodeAkademikServiceImpl) ResolvePeriode(tanggal time.Time) (*PeriodeAkademik, error) {
+	daftar, err := s.LoadPeriode()
+	if err != nil {
+		return nil, err
+	}
+	return daftar.Resolve(tanggal)
+}
+
+// ResolveTahunPelajaran maps a date, which may fall in a holiday between semesters, to a tahun pelajaran
+func (s *PeriodeAkademikServiceImpl) ResolveTahunPelajaran(tanggal time.Time) (*PeriodeAkademik, error) {
+	daftar, err := s.LoadPeriode()
+	if err != nil {
+		return nil, err
+	}
+	return daftar.TahunPelajaranPada(tanggal)
+}
+
+// GetPeriode returns the dates of one semester of a tahun pelajaran
+func (s *PeriodeAkademikServiceImpl) GetPeriode(tahunPelajaranID uint, semester int) (*PeriodeAkademik, error) {
+	tahunPelajaran, err := s.tahunPelajaranRepo.GetByID(tahunPelajaranID)
+	if err != nil {
+		return nil, errors.New("tahun pelajaran tidak ditemukan")
+	}
+	for _, periode := range periodeTahunPelajaran(tahunPelajaran) {
+		if periode.Semester == semester {
+			return &periode, nil
+		}
+	}
+	return nil, ErrSemesterTahunPelajaranKosong
+}
+
+// periodeTahunPelajaran returns both semesters of a tahun pelajaran, none when its dates are not set
+func periodeTahunPelajaran(data *models.TahunPelajaran) []PeriodeAkademik {
+	if data.Semester1Mulai == nil || data.Semester1Selesai == nil || data.Semester2Mulai == nil || data.Semester2Selesai == nil {
+		return nil
+	}
+	return []PeriodeAkademik{
+		{
+			TahunPelajaranID: data.ID,
+			TahunPelajaran:   data.TahunPelajaran,
+			Semester:         1,
+			TanggalMulai:     *data.Semester1Mulai,
+			TanggalSelesai:   *data.Semester1Selesai,
+		},
+		{
+			TahunPelajaranID: data.ID,
+			TahunPelajaran:   data.TahunPelajaran,
+			Semester:         2,
+			TanggalMulai:     *data.Semester2Mulai,
+			TanggalSelesai:   *data.Semester2Selesai,
+		},
+	}
+}
+
+// cocokkanPeriode checks that a date belongs to the given tahun pelajaran and semester
+func cocokkanPeriode(periode *PeriodeAkademik, tahunPelajaranID uint, semester int) error {
+	if periode.TahunPelajaranID != tahunPelajaranID || periode.Semester != semester {
+		return fmt.Errorf("tanggal termasuk semester %d tahun pelajaran %s", periode.Semester, periode.TahunPelajaran)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"errors"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"testing"
+	"time"
+)
+
+// fakeTahunPelajaranRepository holds the tahun pelajaran with their semester dates, oldest first
+type fakeTahunPelajaranRepository struct {
+	repositories.TahunPelajaranRepository
+	data []models.TahunPelajaran
+}
+
+func (r *fakeTahunPelajaranRepository) GetAllWithSemester() ([]models.TahunPelajaran, error) {
+	return r.data, nil
+}
+
+func (r *fakeTahunPelajaranRepository) GetByID(id uint) (*models.TahunPelajaran, error) {
+	for i := range r.data {
+		if r.data[i].ID == id {
+			return &r.data[i], nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func tanggalPeriode(year int, month time.Month, day int) *time.Time {
+	tanggal := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &tanggal
+}
+
+// testTahunPelajaran returns a tahun pelajaran starting in July of the given year, semester 2 starting in January
+func testTahunPelajaran(id uint, nama string, year int) models.TahunPelajaran {
+	return models.TahunPelajaran{
+		ID:               id,
+		TahunPelajaran:   nama,
+		Semester1Mulai:   tanggalPeriode(year, time.July, 13),
+		Semester1Selesai: tanggalPeriode(year, time.December, 19),
+		Semester2Mulai:   tanggalPeriode(year+1, time.January, 5),
+		Semester2Selesai: tanggalPeriode(year+1, time.June, 26),
+	}
+}
+
+// newTestPeriodeAkademikService serves 2025/2026 (ID 1), 2026/2027 (ID 2) and 2027/2028 (ID 3) without semester dates
+func newTestPeriodeAkademikService() PeriodeAkademikService {
+	return NewPeriodeAkademikService(&fakeTahunPelajaranRepository{data: []models.TahunPelajaran{
+		testTahunPelajaran(1, "2025/2026", 2025),
+		testTahunPelajaran(2, "2026/2027", 2026),
+		{ID: 3, TahunPelajaran: "2027/2028"},
+	}})
+}
+
+func TestResolvePeriode(t *testing.T) {
+	service := newTestPeriodeAkademikService()
+
+	tests := []struct {
+		name                 string
+		tanggal              time.Time
+		wantTahunPelajaranID uint
+		wantSemester         int
+		wantErr              error
+	}{
+		{"first day of semester 1", *tanggalPeriode(2026, time.July, 13), 2, 1, nil},
+		{"last day of semester 1", *tanggalPeriode(2026, time.December, 19), 2, 1, nil},
+		{"semester 2 in January", *tanggalPeriode(2027, time.January, 5), 2, 2, nil},
+		{"semester 2 of the previous tahun pelajaran", *tanggalPeriode(2026, time.March, 2), 1, 2, nil},
+		{"holiday between semesters", *tanggalPeriode(2026, time.December, 28), 0, 0, ErrDiLuarSemester},
+		{"holiday after kenaikan kelas", *tanggalPeriode(2026, time.July, 1), 0, 0, ErrDiLuarSemester},
+		{"time of day is ignored", time.Date(2026, 12, 19, 23, 30, 0, 0, time.UTC), 2, 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			periode, err := service.ResolvePeriode(tt.tanggal)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResolvePeriode() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if periode.TahunPelajaranID != tt.wantTahunPelajaranID || periode.Semester != tt.wantSemester {
+				t.Errorf("periode = tahun pelajaran %d semester %d, want %d semester %d", periode.TahunPelajaranID, periode.Semester, tt.wantTahunPelajaranID, tt.wantSemester)
+			}
+		})
+	}
+}
+
+func TestResolveTahunPelajaran(t *testing.T) {
+	service := newTestPeriodeAkademikService()
+
+	tests := []struct {
+		name                 string
+		tanggal              time.Time
+		wantTahunPelajaranID uint
+		wantErr              error
+	}{
+		{"inside a semester", *tanggalPeriode(2026, time.October, 15), 2, nil},
+		{"holiday between semesters", *tanggalPeriode(2026, time.December, 28), 2, nil},
+		{"holiday before the next tahun pelajaran", *tanggalPeriode(2026, time.July, 1), 1, nil},
+		{"before the first semester", *tanggalPeriode(2025, time.June, 30), 0, ErrTahunPelajaranTidakDiatur},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			periode, err := service.ResolveTahunPelajaran(tt.tanggal)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResolveTahunPelajaran() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && periode.TahunPelajaranID != tt.wantTahunPelajaranID {
+				t.Errorf("TahunPelajaranID = %d, want %d", periode.TahunPelajaranID, tt.wantTahunPelajaranID)
+			}
+		})
+	}
+}
+
+func TestGetPeriode(t *testing.T) {
+	service := newTestPeriodeAkademikService()
+
+	periode, err := service.GetPeriode(2, 2)
+	if err != nil {
+		t.Fatalf("GetPeriode(2, 2) error = %v", err)
+	}
+	if !periode.TanggalMulai.Equal(*tanggalPeriode(2027, time.January, 5)) || !periode.TanggalSelesai.Equal(*tanggalPeriode(2027, time.June, 26)) {
+		t.Errorf("semester 2 = %s - %s, want 2027-01-05 - 2027-06-26", periode.TanggalMulai.Format("2006-01-02"), periode.TanggalSelesai.Format("2006-01-02"))
+	}
+
+	if _, err := service.GetPeriode(3, 1); !errors.Is(err, ErrSemesterTahunPelajaranKosong) {
+		t.Errorf("GetPeriode() without semester dates error = %v, want %v", err, ErrSemesterTahunPelajaranKosong)
+	}
+	if _, err := service.GetPeriode(9, 1); err == nil {
+		t.Error("GetPeriode() of an unknown tahun pelajaran error = nil, want an error")
+	}
+}
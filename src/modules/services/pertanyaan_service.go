@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/rand"
@@ -15,26 +16,26 @@ import (
 
 // PertanyaanService handles business logic for Pertanyaan
 type PertanyaanService interface {
-	CreatePublic(files []*multipart.FileHeader, req *dtos.PertanyaanCreateRequest) (*dtos.PertanyaanResponse, error)
+	CreatePublic(ctx context.Context, files []*multipart.FileHeader, req *dtos.PertanyaanCreateRequest) (*dtos.PertanyaanResponse, error)
 	TrackByIDTiket(idTiket string) (*dtos.PertanyaanTrackResponse, error)
 	GetAllWithFilter(req *dtos.PertanyaanGetAllRequest) (*dtos.PertanyaanListWithPaginationResponse, error)
 	GetByID(id uint) (*dtos.PertanyaanResponse, error)
-	SendReply(files []*multipart.FileHeader, req *dtos.PertanyaanSendReplyRequest, userID uint) (*dtos.PertanyaanResponse, error)
+	SendReply(ctx context.Context, files []*multipart.FileHeader, req *dtos.PertanyaanSendReplyRequest, actor utils.Principal) (*dtos.PertanyaanResponse, error)
 	ClosePertanyaan(id uint) (*dtos.PertanyaanResponse, error)
-	DeletePertanyaan(id uint, userID uint) error
+	DeletePertanyaan(id uint, actor utils.Principal) error
 }
 
 type PertanyaanServiceImpl struct {
 	repository   repositories.PertanyaanRepository
-	r2Storage    *utils.R2Storage
+	storage      utils.Storage
 	emailService *utils.EmailService
 }
 
 // NewPertanyaanService creates a new Pertanyaan service
-func NewPertanyaanService(repository repositories.PertanyaanRepository, r2Storage *utils.R2Storage) PertanyaanService {
+func NewPertanyaanService(repository repositories.PertanyaanRepository, storage utils.Storage) PertanyaanService {
 	return &PertanyaanServiceImpl{
 		repository:   repository,
-		r2Storage:    r2Storage,
+		storage:      storage,
 		emailService: utils.NewEmailService(),
 	}
 }
@@ -55,7 +56,7 @@ func (s *PertanyaanServiceImpl) generateTicketID() string {
 }
 
 // CreatePublic creates a new Pertanyaan from public form
-func (s *PertanyaanServiceImpl) CreatePublic(files []*multipart.FileHeader, req *dtos.PertanyaanCreateRequest) (*dtos.PertanyaanResponse, error) {
+func (s *PertanyaanServiceImpl) CreatePublic(ctx context.Context, files []*multipart.FileHeader, req *dtos.PertanyaanCreateRequest) (*dtos.PertanyaanResponse, error) {
 	// Upload files if provided
 	var fileItems []models.FileItem
 	if len(files) > 0 {
@@ -64,20 +65,16 @@ func (s *PertanyaanServiceImpl) CreatePublic(files []*multipart.FileHeader, req
 				continue
 			}
 
-			// Validate file size (max 10MB per file)
-			if file.Size > 10*1024*1024 {
-				return nil, fmt.Errorf("each file must not exceed 10MB")
-			}
-
-			// Upload file to R2 in layanan-umpan-balik/pertanyaan directory
-			fileKey, err := s.r2Storage.UploadFile(file, "layanan-umpan-balik/pertanyaan")
+			// Validate the file from its content and upload to R2
+			stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryDokumen, "layanan-umpan-balik/pertanyaan")
 			if err != nil {
 				// Cleanup already uploaded files on error
 				for _, item := range fileItems {
-					_ = s.r2Storage.DeleteFile(item.URL)
+					_ = s.storage.DeleteFile(item.URL)
 				}
 				return nil, err
 			}
+			fileKey := stored.Key
 
 			// Generate unique file ID: file_timestamp_randomstring
 			fileID := fmt.Sprintf("file_%d_%s", time.Now().UnixNano(), fileKey[len(fileKey)-8:])
@@ -86,7 +83,7 @@ func (s *PertanyaanServiceImpl) CreatePublic(files []*multipart.FileHeader, req
 				ID:       fileID,
 				Filename: file.Filename,
 				URL:      fileKey,
-				Size:     file.Size,
+				Size:     stored.Size,
 			})
 		}
 	}
@@ -121,7 +118,7 @@ func (s *PertanyaanServiceImpl) CreatePublic(files []*multipart.FileHeader, req
 	if err := s.repository.Create(data); err != nil {
 		// Cleanup uploaded files on database error
 		for _, item := range fileItems {
-			_ = s.r2Storage.DeleteFile(item.URL)
+			_ = s.storage.DeleteFile(item.URL)
 		}
 		return nil, err
 	}
@@ -139,7 +136,7 @@ func (s *PertanyaanServiceImpl) mapToResponse(data *models.Pertanyaan) *dtos.Per
 
 	// Convert file URLs to full public URLs
 	for i := range filePertanyaan {
-		filePertanyaan[i].URL = s.r2Storage.GetPublicURL(filePertanyaan[i].URL)
+		filePertanyaan[i].URL = s.storage.GetPublicURL(filePertanyaan[i].URL)
 	}
 
 	// Parse file_jawaban JSON
@@ -150,7 +147,7 @@ func (s *PertanyaanServiceImpl) mapToResponse(data *models.Pertanyaan) *dtos.Per
 
 	// Convert file URLs to full public URLs
 	for i := range fileJawaban {
-		fileJawaban[i].URL = s.r2Storage.GetPublicURL(fileJawaban[i].URL)
+		fileJawaban[i].URL = s.storage.GetPublicURL(fileJawaban[i].URL)
 	}
 
 	resp := &dtos.PertanyaanResponse{
@@ -282,7 +279,7 @@ func (s *PertanyaanServiceImpl) GetByID(id uint) (*dtos.PertanyaanResponse, erro
 
 
 // SendReply sends email reply and updates pertanyaan record
-func (s *PertanyaanServiceImpl) SendReply(files []*multipart.FileHeader, req *dtos.PertanyaanSendReplyRequest, userID uint) (*dtos.PertanyaanResponse, error) {
+func (s *PertanyaanServiceImpl) SendReply(ctx context.Context, files []*multipart.FileHeader, req *dtos.PertanyaanSendReplyRequest, actor utils.Principal) (*dtos.PertanyaanResponse, error) {
 	// Get pertanyaan data
 	data, err := s.repository.GetByID(req.ID)
 	if err != nil {
@@ -297,20 +294,16 @@ func (s *PertanyaanServiceImpl) SendReply(files []*multipart.FileHeader, req *dt
 				continue
 			}
 
-			// Validate file size (max 10MB per file)
-			if file.Size > 10*1024*1024 {
-				return nil, fmt.Errorf("each file must not exceed 10MB")
-			}
-
-			// Upload file to R2
-			fileKey, err := s.r2Storage.UploadFile(file, "layanan-umpan-balik/pertanyaan/jawaban")
+			// Validate the file from its content and upload to R2
+			stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryDokumen, "layanan-umpan-balik/pertanyaan/jawaban")
 			if err != nil {
 				// Cleanup already uploaded files on error
 				for _, item := range fileItems {
-					_ = s.r2Storage.DeleteFile(item.URL)
+					_ = s.storage.DeleteFile(item.URL)
 				}
 				return nil, err
 			}
+			fileKey := stored.Key
 
 			// Generate unique file ID
 			fileID := fmt.Sprintf("file_%d_%s", time.Now().UnixNano(), fileKey[len(fileKey)-8:])
@@ -319,7 +312,7 @@ func (s *PertanyaanServiceImpl) SendReply(files []*multipart.FileHeader, req *dt
 				ID:       fileID,
 				Filename: file.Filename,
 				URL:      fileKey,
-				Size:     file.Size,
+				Size:     stored.Size,
 			})
 		}
 	}
@@ -341,13 +334,14 @@ func (s *PertanyaanServiceImpl) SendReply(files []*multipart.FileHeader, req *dt
 	data.TanggalProses = &now
 	data.EmailTerkirim = false // Set to false first, will update after email sent
 	data.Status = "processed"
-	data.RepliedBy = &userID
+	data.RepliedBy = &actor.ID
+	data.RepliedByType = actor.TypePtr()
 
 	// Save to database first
 	if err := s.repository.Update(data); err != nil {
 		// Cleanup uploaded files if database update fails
 		for _, item := range fileItems {
-			_ = s.r2Storage.DeleteFile(item.URL)
+			_ = s.storage.DeleteFile(item.URL)
 		}
 		return nil, fmt.Errorf("gagal menyimpan data ke database: %w", err)
 	}
@@ -363,7 +357,7 @@ func (s *PertanyaanServiceImpl) SendReply(files []*multipart.FileHeader, req *dt
 	for _, item := range filePertanyaanItems {
 		filePertanyaanLinks = append(filePertanyaanLinks, utils.FileLink{
 			Name: item.Filename,
-			URL:  s.r2Storage.GetPublicURL(item.URL),
+			URL:  s.storage.GetPublicURL(item.URL),
 		})
 	}
 
@@ -371,7 +365,7 @@ func (s *PertanyaanServiceImpl) SendReply(files []*multipart.FileHeader, req *dt
 	for _, item := range fileItems {
 		fileJawabanLinks = append(fileJawabanLinks, utils.FileLink{
 			Name: item.Filename,
-			URL:  s.r2Storage.GetPublicURL(item.URL),
+			URL:  s.storage.GetPublicURL(item.URL),
 		})
 	}
 
@@ -431,7 +425,7 @@ func (s *PertanyaanServiceImpl) ClosePertanyaan(id uint) (*dtos.PertanyaanRespon
 	return s.mapToResponse(data), nil
 }
 // DeletePertanyaan soft deletes pertanyaan by setting deleted_at and deleted_by_id
-func (s *PertanyaanServiceImpl) DeletePertanyaan(id uint, userID uint) error {
+func (s *PertanyaanServiceImpl) DeletePertanyaan(id uint, actor utils.Principal) error {
 	// Check if pertanyaan exists
 	_, err := s.repository.GetByID(id)
 	if err != nil {
@@ -439,7 +433,7 @@ func (s *PertanyaanServiceImpl) DeletePertanyaan(id uint, userID uint) error {
 	}
 
 	// Soft delete with user tracking
-	if err := s.repository.SoftDeleteWithUser(id, userID); err != nil {
+	if err := s.repository.SoftDeleteWithUser(id, actor.ID, actor.TypePtr()); err != nil {
 		return fmt.Errorf("gagal menghapus pertanyaan: %w", err)
 	}
 
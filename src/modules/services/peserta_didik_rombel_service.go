@@ -13,38 +13,38 @@ import (
 )
 
 type PesertaDidikRombelService interface {
-	BulkCreate(req *dtos.PesertaDidikRombelCreateRequest, userID uint) (*dtos.PesertaDidikRombelBulkCreateResponse, error)
+	BulkCreate(req *dtos.PesertaDidikRombelCreateRequest, actor utils.Principal) (*dtos.PesertaDidikRombelBulkCreateResponse, error)
 	GetAllWithFilter(params repositories.GetPesertaDidikRombelParams) (*dtos.PesertaDidikRombelListWithPaginationResponse, error)
 	GetByID(id uint) (*dtos.PesertaDidikRombelResponse, error)
-	Update(id uint, req *dtos.PesertaDidikRombelUpdateRequest, userID uint) (*dtos.PesertaDidikRombelResponse, error)
+	Update(id uint, req *dtos.PesertaDidikRombelUpdateRequest, actor utils.Principal) (*dtos.PesertaDidikRombelResponse, error)
 	Delete(id uint) error
 	DownloadTemplate() (*excelize.File, error)
-	ImportExcel(file multipart.File, userID uint) (*dtos.ImportExcelResponse, error)
+	ImportExcel(file multipart.File, actor utils.Principal) (*dtos.ImportExcelResponse, error)
 	Reset(req *dtos.PesertaDidikRombelResetRequest) (*dtos.PesertaDidikRombelResetResponse, error)
 }
 
 type PesertaDidikRombelServiceImpl struct {
 	repository               repositories.PesertaDidikRombelRepository
 	pesertaDidikRepository   repositories.PesertaDidikRepository
-	r2Storage                *utils.R2Storage
+	storage                  utils.Storage
 }
 
 // NewPesertaDidikRombelService creates a new PesertaDidikRombel service
 func NewPesertaDidikRombelService(
 	repository repositories.PesertaDidikRombelRepository,
 	pesertaDidikRepository repositories.PesertaDidikRepository,
-	r2Storage *utils.R2Storage,
+	storage utils.Storage,
 ) PesertaDidikRombelService {
 	return &PesertaDidikRombelServiceImpl{
 		repository:             repository,
 		pesertaDidikRepository: pesertaDidikRepository,
-		r2Storage:              r2Storage,
+		storage:                storage,
 	}
 }
 
 // BulkCreate creates multiple PesertaDidikRombel records for multiple students
 // Uses transaction to ensure all records are created or none (all or nothing)
-func (s *PesertaDidikRombelServiceImpl) BulkCreate(req *dtos.PesertaDidikRombelCreateRequest, userID uint) (*dtos.PesertaDidikRombelBulkCreateResponse, error) {
+func (s *PesertaDidikRombelServiceImpl) BulkCreate(req *dtos.PesertaDidikRombelCreateRequest, actor utils.Principal) (*dtos.PesertaDidikRombelBulkCreateResponse, error) {
 	// Set default status
 	status := req.Status
 	if status == "" {
@@ -118,7 +118,8 @@ func (s *PesertaDidikRombelServiceImpl) BulkCreate(req *dtos.PesertaDidikRombelC
 				RombelID:         req.RombelID,
 				TahunPelajaranID: req.TahunPelajaranID,
 				Status:           status,
-				CreatedByID:      &userID,
+				CreatedByID:      &actor.ID,
+				CreatedByType:    actor.TypePtr(),
 			}
 
 			if err := s.repository.CreateInTransaction(tx, data); err != nil {
@@ -162,7 +163,9 @@ func (s *PesertaDidikRombelServiceImpl) mapToResponse(data *models.PesertaDidikR
 		CreatedAt:        data.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt:        data.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 		CreatedByID:      data.CreatedByID,
+		CreatedByType:    data.CreatedByType,
 		UpdatedByID:      data.UpdatedByID,
+		UpdatedByType:    data.UpdatedByType,
 	}
 
 	// Map PesertaDidik (complete data)
@@ -180,7 +183,7 @@ func (s *PesertaDidikRombelServiceImpl) mapToResponse(data *models.PesertaDidikR
 		// Generate public URL for photo if exists
 		photoURL := ""
 		if data.PesertaDidik.Photo != "" {
-			photoURL = s.r2Storage.GetPublicURL(data.PesertaDidik.Photo)
+			photoURL = s.storage.GetPublicURL(data.PesertaDidik.Photo)
 		}
 
 		response.PesertaDidik = &dtos.PesertaDidikResponse{
@@ -210,7 +213,9 @@ func (s *PesertaDidikRombelServiceImpl) mapToResponse(data *models.PesertaDidikR
 			CreatedAt:          data.PesertaDidik.CreatedAt.Format("2006-01-02T15:04:05Z"),
 			UpdatedAt:          data.PesertaDidik.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 			CreatedByID:        data.PesertaDidik.CreatedByID,
+			CreatedByType:      data.PesertaDidik.CreatedByType,
 			UpdatedByID:        data.PesertaDidik.UpdatedByID,
+			UpdatedByType:      data.PesertaDidik.UpdatedByType,
 		}
 	}
 
@@ -247,7 +252,9 @@ func (s *PesertaDidikRombelServiceImpl) mapToResponse(data *models.PesertaDidikR
 			CreatedAt:      data.TahunPelajaran.CreatedAt.Format("2006-01-02T15:04:05Z"),
 			UpdatedAt:      data.TahunPelajaran.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 			CreatedByID:    data.TahunPelajaran.CreatedByID,
+			CreatedByType:  data.TahunPelajaran.CreatedByType,
 			UpdatedByID:    data.TahunPelajaran.UpdatedByID,
+			UpdatedByType:  data.TahunPelajaran.UpdatedByType,
 		}
 	}
 
@@ -302,7 +309,7 @@ func (s *PesertaDidikRombelServiceImpl) GetByID(id uint) (*dtos.PesertaDidikRomb
 }
 
 // Update updates PesertaDidikRombel
-func (s *PesertaDidikRombelServiceImpl) Update(id uint, req *dtos.PesertaDidikRombelUpdateRequest, userID uint) (*dtos.PesertaDidikRombelResponse, error) {
+func (s *PesertaDidikRombelServiceImpl) Update(id uint, req *dtos.PesertaDidikRombelUpdateRequest, actor utils.Principal) (*dtos.PesertaDidikRombelResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(id)
 	if err != nil {
@@ -349,7 +356,8 @@ func (s *PesertaDidikRombelServiceImpl) Update(id uint, req *dtos.PesertaDidikRo
 	existing.RombelID = req.RombelID
 	existing.TahunPelajaranID = req.TahunPelajaranID
 	existing.Status = status
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	// Save to database
 	if err := s.repository.Update(existing); err != nil {
@@ -520,7 +528,7 @@ func (s *PesertaDidikRombelServiceImpl) DownloadTemplate() (*excelize.File, erro
 }
 
 // ImportExcel imports PesertaDidikRombel data from an Excel file with transaction (optimized with caching)
-func (s *PesertaDidikRombelServiceImpl) ImportExcel(file multipart.File, userID uint) (*dtos.ImportExcelResponse, error) {
+func (s *PesertaDidikRombelServiceImpl) ImportExcel(file multipart.File, actor utils.Principal) (*dtos.ImportExcelResponse, error) {
 	f, err := excelize.OpenReader(file)
 	if err != nil {
 		return nil, fmt.Errorf("gagal membuka file excel: %s", err.Error())
@@ -719,7 +727,8 @@ func (s *PesertaDidikRombelServiceImpl) ImportExcel(file multipart.File, userID
 				RombelID:         validRow.RombelID,
 				TahunPelajaranID: validRow.TahunPelajaranID,
 				Status:           validRow.Status,
-				CreatedByID:      &userID,
+				CreatedByID:      &actor.ID,
+				CreatedByType:    actor.TypePtr(),
 			}
 
 			if err := s.repository.CreateInTransaction(tx, data); err != nil {
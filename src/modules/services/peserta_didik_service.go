@@ -2,6 +2,7 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"mime/multipart"
@@ -21,15 +22,15 @@ import (
 )
 
 type PesertaDidikService interface {
-	Create(req *dtos.PesertaDidikCreateRequest, userID uint) (*dtos.PesertaDidikResponse, error)
+	Create(req *dtos.PesertaDidikCreateRequest, actor utils.Principal) (*dtos.PesertaDidikResponse, error)
 	GetByID(id uint) (*dtos.PesertaDidikResponse, error)
 	GetByNIS(nis string) (*dtos.PesertaDidikResponse, error)
 	GetAll(limit int, offset int) (*dtos.PesertaDidikListResponse, error)
 	GetAllWithFilter(params repositories.GetPesertaDidikParams) (*dtos.PesertaDidikListWithPaginationResponse, error)
-	Update(id uint, photo *multipart.FileHeader, req *dtos.PesertaDidikUpdateRequest, userID uint) (*dtos.PesertaDidikResponse, error)
+	Update(ctx context.Context, id uint, photo *multipart.FileHeader, req *dtos.PesertaDidikUpdateRequest, actor utils.Principal) (*dtos.PesertaDidikResponse, error)
 	Delete(id uint) error
-	ImportExcel(file multipart.File, userID uint) (*dtos.ImportExcelResponse, error)
-	ImportSiswaLulus(file multipart.File, userID uint) (*dtos.ImportExcelResponse, error)
+	ImportExcel(file multipart.File, actor utils.Principal) (*dtos.ImportExcelResponse, error)
+	ImportSiswaLulus(file multipart.File, actor utils.Principal) (*dtos.ImportExcelResponse, error)
 	DownloadTemplate() (*excelize.File, error)
 	DownloadTemplateSiswaLulus() (*excelize.File, error)
 	ExportDataIndukSiswaExcel(status string) (*excelize.File, error)
@@ -43,20 +44,22 @@ type PesertaDidikService interface {
 }
 
 type PesertaDidikServiceImpl struct {
-	repository repositories.PesertaDidikRepository
-	r2Storage  *utils.R2Storage
+	repository      repositories.PesertaDidikRepository
+	tokenRepository repositories.AuthTokenRepository
+	storage         utils.Storage
 }
 
 // NewPesertaDidikService creates a new PesertaDidik service
-func NewPesertaDidikService(repository repositories.PesertaDidikRepository, r2Storage *utils.R2Storage) PesertaDidikService {
+func NewPesertaDidikService(repository repositories.PesertaDidikRepository, tokenRepository repositories.AuthTokenRepository, storage utils.Storage) PesertaDidikService {
 	return &PesertaDidikServiceImpl{
-		repository: repository,
-		r2Storage:  r2Storage,
+		repository:      repository,
+		tokenRepository: tokenRepository,
+		storage:         storage,
 	}
 }
 
 // Create creates a new PesertaDidik
-func (s *PesertaDidikServiceImpl) Create(req *dtos.PesertaDidikCreateRequest, userID uint) (*dtos.PesertaDidikResponse, error) {
+func (s *PesertaDidikServiceImpl) Create(req *dtos.PesertaDidikCreateRequest, actor utils.Principal) (*dtos.PesertaDidikResponse, error) {
 	// Check if NIS already exists
 	existing, _ := s.repository.GetByNIS(req.NIS)
 	if existing != nil {
@@ -105,26 +108,30 @@ func (s *PesertaDidikServiceImpl) Create(req *dtos.PesertaDidikCreateRequest, us
 
 	// Create peserta didik record
 	data := &models.PesertaDidik{
-		Nama:               req.Nama,
-		NIS:                req.NIS,
-		JenisKelamin:       req.JenisKelamin,
-		NISN:               req.NISN,
-		TempatLahir:        req.TempatLahir,
-		TanggalLahir:       tanggalLahir,
-		NIK:                req.NIK,
-		Agama:              req.Agama,
-		Alamat:             req.Alamat,
-		RT:                 req.RT,
-		RW:                 req.RW,
-		Kelurahan:          req.Kelurahan,
-		Kecamatan:          req.Kecamatan,
-		KodePos:            req.KodePos,
-		NamaAyah:           req.NamaAyah,
-		NamaIbu:            req.NamaIbu,
-		Status:             status,
-		Username:           req.Username,
-		Password:           hashedPassword,
-		CreatedByID:        &userID,
+		Nama:           req.Nama,
+		NIS:            req.NIS,
+		JenisKelamin:   req.JenisKelamin,
+		NISN:           req.NISN,
+		TempatLahir:    req.TempatLahir,
+		TanggalLahir:   tanggalLahir,
+		NIK:            req.NIK,
+		Agama:          req.Agama,
+		Alamat:         req.Alamat,
+		RT:             req.RT,
+		RW:             req.RW,
+		Kelurahan:      req.Kelurahan,
+		Kecamatan:      req.Kecamatan,
+		KodePos:        req.KodePos,
+		NamaAyah:       req.NamaAyah,
+		NamaIbu:        req.NamaIbu,
+		NomorHPOrtu:    req.NomorHPOrtu,
+		EmailOrtu:      req.EmailOrtu,
+		NotifikasiOrtu: req.NotifikasiOrtu,
+		Status:         status,
+		Username:       req.Username,
+		Password:       hashedPassword,
+		CreatedByID:    &actor.ID,
+		CreatedByType:  actor.TypePtr(),
 	}
 
 	if err := s.repository.Create(data); err != nil {
@@ -234,7 +241,7 @@ func (s *PesertaDidikServiceImpl) GetAllWithFilter(params repositories.GetPesert
 }
 
 // Update updates PesertaDidik
-func (s *PesertaDidikServiceImpl) Update(id uint, photo *multipart.FileHeader, req *dtos.PesertaDidikUpdateRequest, userID uint) (*dtos.PesertaDidikResponse, error) {
+func (s *PesertaDidikServiceImpl) Update(ctx context.Context, id uint, photo *multipart.FileHeader, req *dtos.PesertaDidikUpdateRequest, actor utils.Principal) (*dtos.PesertaDidikResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(id)
 	if err != nil {
@@ -243,33 +250,17 @@ func (s *PesertaDidikServiceImpl) Update(id uint, photo *multipart.FileHeader, r
 
 	// Upload photo if provided
 	if photo != nil {
-		// Validate photo file
-		if photo.Size > 5*1024*1024 { // 5MB
-			return nil, errors.New("ukuran photo maksimal 5MB")
-		}
-
-		allowedTypes := map[string]bool{
-			"image/jpeg": true,
-			"image/png":  true,
-			"image/jpg":  true,
-			"image/webp": true,
-		}
-		contentType := photo.Header.Get("Content-Type")
-		if !allowedTypes[contentType] {
-			return nil, errors.New("hanya file gambar yang diperbolehkan (jpeg, jpg, png, webp)")
+		// Validate the photo from its content (dropping EXIF/GPS metadata) and upload to R2
+		stored, err := utils.SaveUpload(ctx, s.storage, photo, utils.UploadCategoryFoto, "peserta-didik")
+		if err != nil {
+			return nil, err
 		}
 
 		// Delete old photo if exists
 		if existing.Photo != "" {
-			_ = s.r2Storage.DeleteFile(existing.Photo) // Ignore error if file doesn't exist
+			_ = s.storage.DeleteFile(existing.Photo) // Ignore error if file doesn't exist
 		}
-
-		// Upload photo to R2
-		fileKey, err := s.r2Storage.UploadFile(photo, "peserta-didik")
-		if err != nil {
-			return nil, err
-		}
-		existing.Photo = fileKey
+		existing.Photo = stored.Key
 	}
 
 	// Update basic fields if provided
@@ -335,6 +326,15 @@ func (s *PesertaDidikServiceImpl) Update(id uint, photo *multipart.FileHeader, r
 	if req.NamaIbu != "" {
 		existing.NamaIbu = req.NamaIbu
 	}
+	if req.NomorHPOrtu != "" {
+		existing.NomorHPOrtu = req.NomorHPOrtu
+	}
+	if req.EmailOrtu != "" {
+		existing.EmailOrtu = req.EmailOrtu
+	}
+	if req.NotifikasiOrtu != nil {
+		existing.NotifikasiOrtu = *req.NotifikasiOrtu
+	}
 	if req.Username != "" && req.Username != existing.Username {
 		// Check if new username already exists
 		existingUsername, _ := s.repository.GetByUsername(req.Username)
@@ -350,17 +350,27 @@ func (s *PesertaDidikServiceImpl) Update(id uint, photo *multipart.FileHeader, r
 		}
 		existing.Password = string(hashedPassword)
 	}
+	// Deactivation and password changes end every existing siswa session
+	revokeSessions := req.Password != "" || (req.Status != "" && req.Status != "active" && req.Status != existing.Status)
+
 	if req.Status != "" {
 		existing.Status = req.Status
 	}
 
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	// Update record
 	if err := s.repository.Update(existing); err != nil {
 		return nil, err
 	}
 
+	if revokeSessions {
+		if err := s.tokenRepository.RevokeAllForPrincipal(utils.PrincipalSiswa, existing.ID); err != nil {
+			return nil, errors.New("gagal mencabut sesi login siswa")
+		}
+	}
+
 	// Update roles only if RoleIDs is provided (even if empty to clear roles)
 	// Note: Controller will only set RoleIDs if the field was sent in the request
 	if req.RoleIDs != nil {
@@ -394,11 +404,18 @@ func (s *PesertaDidikServiceImpl) Delete(id uint) error {
 		return errors.New("peserta didik tidak ditemukan atau sudah dihapus")
 	}
 
-	return s.repository.Delete(id)
+	if err := s.repository.Delete(id); err != nil {
+		return err
+	}
+
+	if err := s.tokenRepository.RevokeAllForPrincipal(utils.PrincipalSiswa, id); err != nil {
+		return errors.New("gagal mencabut sesi login siswa")
+	}
+	return nil
 }
 
 // ImportExcel imports PesertaDidik data from an Excel file
-func (s *PesertaDidikServiceImpl) ImportExcel(file multipart.File, userID uint) (*dtos.ImportExcelResponse, error) {
+func (s *PesertaDidikServiceImpl) ImportExcel(file multipart.File, actor utils.Principal) (*dtos.ImportExcelResponse, error) {
 	f, err := excelize.OpenReader(file)
 	if err != nil {
 		return nil, errors.New("gagal membuka file excel")
@@ -528,26 +545,27 @@ func (s *PesertaDidikServiceImpl) ImportExcel(file multipart.File, userID uint)
 
 		// Create the PesertaDidik record
 		data := &models.PesertaDidik{
-			Nama:             namaLengkap,
-			NIS:              nis,
-			NISN:             nisn,
-			JenisKelamin:     jenisKelamin,
-			TempatLahir:      tempatLahir,
-			TanggalLahir:     tanggalLahir,
-			NIK:              nik,
-			Agama:            agama,
-			Alamat:           alamat,
-			RT:               rt,
-			RW:               rw,
-			Kelurahan:        kelurahan,
-			Kecamatan:        kecamatan,
-			KodePos:          kodePos,
-			NamaAyah:         namaAyah,
-			NamaIbu:          namaIbu,
-			Status:           status,
-			Username:         username,
-			Password:         string(hashedPassword),
-			CreatedByID:      &userID,
+			Nama:          namaLengkap,
+			NIS:           nis,
+			NISN:          nisn,
+			JenisKelamin:  jenisKelamin,
+			TempatLahir:   tempatLahir,
+			TanggalLahir:  tanggalLahir,
+			NIK:           nik,
+			Agama:         agama,
+			Alamat:        alamat,
+			RT:            rt,
+			RW:            rw,
+			Kelurahan:     kelurahan,
+			Kecamatan:     kecamatan,
+			KodePos:       kodePos,
+			NamaAyah:      namaAyah,
+			NamaIbu:       namaIbu,
+			Status:        status,
+			Username:      username,
+			Password:      string(hashedPassword),
+			CreatedByID:   &actor.ID,
+			CreatedByType: actor.TypePtr(),
 		}
 
 		if err := s.repository.Create(data); err != nil {
@@ -708,16 +726,18 @@ func (s *PesertaDidikServiceImpl) mapToResponse(data *models.PesertaDidik) *dtos
 		}
 
 		roles[i] = dtos.RoleResponse{
-			ID:          role.ID,
-			Name:        role.Name,
-			Description: role.Description,
-			SystemID:    role.SystemID,
-			System:      system,
-			Status:      role.Status,
-			CreatedAt:   role.CreatedAt,
-			UpdatedAt:   role.UpdatedAt,
-			CreatedByID: role.CreatedByID,
-			UpdatedByID: role.UpdatedByID,
+			ID:            role.ID,
+			Name:          role.Name,
+			Description:   role.Description,
+			SystemID:      role.SystemID,
+			System:        system,
+			Status:        role.Status,
+			CreatedAt:     role.CreatedAt,
+			UpdatedAt:     role.UpdatedAt,
+			CreatedByID:   role.CreatedByID,
+			CreatedByType: role.CreatedByType,
+			UpdatedByID:   role.UpdatedByID,
+			UpdatedByType: role.UpdatedByType,
 		}
 	}
 
@@ -734,37 +754,42 @@ func (s *PesertaDidikServiceImpl) mapToResponse(data *models.PesertaDidik) *dtos
 	// Generate public URL for photo if exists
 	photoURL := ""
 	if data.Photo != "" {
-		photoURL = s.r2Storage.GetPublicURL(data.Photo)
+		photoURL = s.storage.GetPublicURL(data.Photo)
 	}
 
 	return &dtos.PesertaDidikResponse{
-		ID:               data.ID,
-		Nama:             data.Nama,
-		NIS:              data.NIS,
-		JenisKelamin:     data.JenisKelamin,
-		NISN:             data.NISN,
-		TempatLahir:      data.TempatLahir,
-		TanggalLahir:     tanggalLahirStr,
-		NIK:              data.NIK,
-		Agama:            data.Agama,
-		Alamat:           data.Alamat,
-		RT:               data.RT,
-		RW:               data.RW,
-		Kelurahan:        data.Kelurahan,
-		Kecamatan:        data.Kecamatan,
-		KodePos:          data.KodePos,
-		NamaAyah:         data.NamaAyah,
-		NamaIbu:          data.NamaIbu,
-		Status:           data.Status,
-		Username:         data.Username,
-		Photo:            photoURL,
-		Barcode:          data.Barcode,
+		ID:                 data.ID,
+		Nama:               data.Nama,
+		NIS:                data.NIS,
+		JenisKelamin:       data.JenisKelamin,
+		NISN:               data.NISN,
+		TempatLahir:        data.TempatLahir,
+		TanggalLahir:       tanggalLahirStr,
+		NIK:                data.NIK,
+		Agama:              data.Agama,
+		Alamat:             data.Alamat,
+		RT:                 data.RT,
+		RW:                 data.RW,
+		Kelurahan:          data.Kelurahan,
+		Kecamatan:          data.Kecamatan,
+		KodePos:            data.KodePos,
+		NamaAyah:           data.NamaAyah,
+		NamaIbu:            data.NamaIbu,
+		NomorHPOrtu:        data.NomorHPOrtu,
+		EmailOrtu:          data.EmailOrtu,
+		NotifikasiOrtu:     data.NotifikasiOrtu,
+		Status:             data.Status,
+		Username:           data.Username,
+		Photo:              photoURL,
+		Barcode:            data.Barcode,
 		BarcodeGeneratedAt: barcodeGeneratedAtStr,
-		Roles:            roles,
-		CreatedAt:        data.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:        data.UpdatedAt.Format("2006-01-02T15:04:05Z"),
-		CreatedByID:      data.CreatedByID,
-		UpdatedByID:      data.UpdatedByID,
+		Roles:              roles,
+		CreatedAt:          data.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:          data.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		CreatedByID:        data.CreatedByID,
+		CreatedByType:      data.CreatedByType,
+		UpdatedByID:        data.UpdatedByID,
+		UpdatedByType:      data.UpdatedByType,
 	}
 }
 
@@ -1686,7 +1711,7 @@ func (s *PesertaDidikServiceImpl) DownloadTemplateSiswaLulus() (*excelize.File,
 }
 
 // ImportSiswaLulus imports siswa lulus data from Excel and updates status to "lulus" with transaction (all-or-nothing)
-func (s *PesertaDidikServiceImpl) ImportSiswaLulus(file multipart.File, userID uint) (*dtos.ImportExcelResponse, error) {
+func (s *PesertaDidikServiceImpl) ImportSiswaLulus(file multipart.File, actor utils.Principal) (*dtos.ImportExcelResponse, error) {
 	f, err := excelize.OpenReader(file)
 	if err != nil {
 		return nil, fmt.Errorf("gagal membuka file excel: %s", err.Error())
@@ -1801,7 +1826,8 @@ func (s *PesertaDidikServiceImpl) ImportSiswaLulus(file multipart.File, userID u
 
 			// Update status to "lulus"
 			pesertaDidik.Status = "lulus"
-			pesertaDidik.UpdatedByID = &userID
+			pesertaDidik.UpdatedByID = &actor.ID
+			pesertaDidik.UpdatedByType = actor.TypePtr()
 
 			if err := s.repository.UpdateInTransaction(tx, pesertaDidik); err != nil {
 				return fmt.Errorf("gagal mengupdate status peserta didik %s (NIS: %s) baris %d: %s", validRow.Nama, validRow.NIS, validRow.RowNum, err.Error())
@@ -1849,7 +1875,7 @@ func (s *PesertaDidikServiceImpl) DownloadKartuPelajar(pesertaDidikIDs []uint) (
 	// Convert Photo field to full R2 URL for each student
 	for i := range siswa {
 		if siswa[i].Photo != "" {
-			siswa[i].Photo = s.r2Storage.GetPublicURL(siswa[i].Photo)
+			siswa[i].Photo = s.storage.GetPublicURL(siswa[i].Photo)
 		}
 	}
 	
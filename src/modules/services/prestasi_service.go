@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,38 +16,51 @@ import (
 )
 
 type PrestasiService interface {
-	Create(foto []*multipart.FileHeader, fotoThumbnails []string, req *dtos.PrestasiCreateRequest, userID uint) (*dtos.PrestasiResponse, error)
+	Create(ctx context.Context, foto []*multipart.FileHeader, fotoThumbnails []string, req *dtos.PrestasiCreateRequest, actor utils.Principal) (*dtos.PrestasiResponse, error)
 	GetByID(id uint) (*dtos.PrestasiResponse, error)
 	GetAll(limit int, offset int) (*dtos.PrestasiListResponse, error)
 	GetAllWithFilter(params repositories.GetPrestasiParams) (*dtos.PrestasiListWithPaginationResponse, error)
+	GetByPesertaDidikID(pesertaDidikID uint) ([]dtos.PrestasiResponse, error)
 	GetPublicLatest() (*dtos.PrestasiPublicListResponse, error)
 	GetPublicList(req *dtos.PrestasiPublicListRequest) (*dtos.PrestasiPublicDaftarResponse, error)
 	GetPublicDetailByID(id uint) (*dtos.PrestasiResponse, error)
-	Update(id uint, foto []*multipart.FileHeader, fotoThumbnails []string, req *dtos.PrestasiUpdateRequest, userID uint) (*dtos.PrestasiResponse, error)
+	Update(ctx context.Context, id uint, foto []*multipart.FileHeader, fotoThumbnails []string, req *dtos.PrestasiUpdateRequest, actor utils.Principal) (*dtos.PrestasiResponse, error)
 	Delete(id uint) error
 }
 
 type PrestasiServiceImpl struct {
-	repository repositories.PrestasiRepository
-	r2Storage  *utils.R2Storage
+	repository     repositories.PrestasiRepository
+	periodeService PeriodeAkademikService
+	storage        utils.Storage
 }
 
 // NewPrestasiService creates a new Prestasi service
-func NewPrestasiService(repository repositories.PrestasiRepository, r2Storage *utils.R2Storage) PrestasiService {
+func NewPrestasiService(repository repositories.PrestasiRepository, periodeService PeriodeAkademikService, storage utils.Storage) PrestasiService {
 	return &PrestasiServiceImpl{
-		repository: repository,
-		r2Storage:  r2Storage,
+		repository:     repository,
+		periodeService: periodeService,
+		storage:        storage,
 	}
 }
 
 // Create creates a new Prestasi with foto uploads to R2
-func (s *PrestasiServiceImpl) Create(foto []*multipart.FileHeader, fotoThumbnails []string, req *dtos.PrestasiCreateRequest, userID uint) (*dtos.PrestasiResponse, error) {
+func (s *PrestasiServiceImpl) Create(ctx context.Context, foto []*multipart.FileHeader, fotoThumbnails []string, req *dtos.PrestasiCreateRequest, actor utils.Principal) (*dtos.PrestasiResponse, error) {
 	// Parse tanggal lomba
 	tanggalLomba, err := time.Parse("2006-01-02", req.TanggalLomba)
 	if err != nil {
 		return nil, errors.New("invalid tanggal_lomba format (use YYYY-MM-DD)")
 	}
 
+	// Default to the tahun pelajaran of tanggal lomba
+	tahunPelajaranID := req.TahunPelajaranID
+	if tahunPelajaranID == 0 {
+		periode, err := s.periodeService.ResolveTahunPelajaran(tanggalLomba)
+		if err != nil {
+			return nil, err
+		}
+		tahunPelajaranID = periode.TahunPelajaranID
+	}
+
 	// Upload foto if provided
 	var fotoItems []models.FotoItem
 	if len(foto) > 0 {
@@ -55,27 +69,12 @@ func (s *PrestasiServiceImpl) Create(foto []*multipart.FileHeader, fotoThumbnail
 				continue
 			}
 
-			// Validate foto file
-			if file.Size > 5*1024*1024 { // 5MB
-				return nil, errors.New("each foto must not exceed 5MB")
-			}
-
-			allowedTypes := map[string]bool{
-				"image/jpeg": true,
-				"image/png":  true,
-				"image/gif":  true,
-				"image/webp": true,
-			}
-			contentType := file.Header.Get("Content-Type")
-			if !allowedTypes[contentType] {
-				return nil, errors.New("only image files are allowed for foto (jpeg, png, gif, webp)")
-			}
-
-			// Upload foto to R2
-			fileKey, err := s.r2Storage.UploadFile(file, "prestasi")
+			// Validate the file from its content and upload to R2
+			stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryPublikasi, "prestasi")
 			if err != nil {
 				return nil, err
 			}
+			fileKey := stored.Key
 
 			// Generate unique foto ID
 			fotoID := fmt.Sprintf("foto_%d_%s", time.Now().UnixNano(), fileKey[len(fileKey)-8:])
@@ -93,7 +92,10 @@ func (s *PrestasiServiceImpl) Create(foto []*multipart.FileHeader, fotoThumbnail
 				ID:        fotoID,
 				Filename:  file.Filename,
 				URL:       fileKey,
-				Size:      file.Size,
+				Size:      stored.Size,
+				Width:     stored.Width,
+				Height:    stored.Height,
+				Variants:  fileVariants(stored),
 				Thumbnail: thumbnail,
 			})
 		}
@@ -121,15 +123,17 @@ func (s *PrestasiServiceImpl) Create(foto []*multipart.FileHeader, fotoThumbnail
 		Keterangan:        req.Keterangan,
 		Foto:              fotoJSON,
 		EkstrakurikulerID: req.EkstrakurikulerID,
-		TahunPelajaranID:  req.TahunPelajaranID,
+		TahunPelajaranID:  tahunPelajaranID,
 		Status:            status,
-		CreatedByID:       &userID,
+		CreatedByID:       &actor.ID,
+		CreatedByType: actor.TypePtr(),
 	}
 
 	if err := s.repository.Create(data); err != nil {
 		// If database save fails, delete uploaded files
 		for _, item := range fotoItems {
-			_ = s.r2Storage.DeleteFile(item.URL)
+			_ = s.storage.DeleteFile(item.URL)
+			deleteFileVariants(s.storage, item.URL, item.Variants)
 		}
 		return nil, err
 	}
@@ -140,7 +144,8 @@ func (s *PrestasiServiceImpl) Create(foto []*multipart.FileHeader, fotoThumbnail
 			anggotaData := &models.AnggotaTimPrestasi{
 				PrestasiID:           data.ID,
 				PesertaDidikRombelID: anggota.PesertaDidikRombelID,
-				CreatedByID:          &userID,
+				CreatedByID:          &actor.ID,
+				CreatedByType:        actor.TypePtr(),
 			}
 			if err := s.repository.CreateAnggotaTim(anggotaData); err != nil {
 				return nil, fmt.Errorf("failed to create anggota tim: %w", err)
@@ -236,8 +241,22 @@ func (s *PrestasiServiceImpl) GetAllWithFilter(params repositories.GetPrestasiPa
 	}, nil
 }
 
+// GetByPesertaDidikID retrieves the active Prestasi of a student
+func (s *PrestasiServiceImpl) GetByPesertaDidikID(pesertaDidikID uint) ([]dtos.PrestasiResponse, error) {
+	data, err := s.repository.GetByPesertaDidikID(pesertaDidikID)
+	if err != nil {
+		return nil, errors.New("gagal mengambil data prestasi")
+	}
+
+	responses := make([]dtos.PrestasiResponse, len(data))
+	for i, item := range data {
+		responses[i] = *s.mapToResponse(&item)
+	}
+	return responses, nil
+}
+
 // Update updates Prestasi
-func (s *PrestasiServiceImpl) Update(id uint, foto []*multipart.FileHeader, fotoThumbnails []string, req *dtos.PrestasiUpdateRequest, userID uint) (*dtos.PrestasiResponse, error) {
+func (s *PrestasiServiceImpl) Update(ctx context.Context, id uint, foto []*multipart.FileHeader, fotoThumbnails []string, req *dtos.PrestasiUpdateRequest, actor utils.Principal) (*dtos.PrestasiResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(id)
 	if err != nil {
@@ -307,7 +326,8 @@ func (s *PrestasiServiceImpl) Update(id uint, foto []*multipart.FileHeader, foto
 			for _, fotoItem := range existingFotoItems {
 				if deleteMap[fotoItem.ID] {
 					// Delete from R2
-					_ = s.r2Storage.DeleteFile(fotoItem.URL)
+					_ = s.storage.DeleteFile(fotoItem.URL)
+					deleteFileVariants(s.storage, fotoItem.URL, fotoItem.Variants)
 				} else {
 					remainingFoto = append(remainingFoto, fotoItem)
 				}
@@ -333,27 +353,12 @@ func (s *PrestasiServiceImpl) Update(id uint, foto []*multipart.FileHeader, foto
 				continue
 			}
 
-			// Validate foto file
-			if file.Size > 5*1024*1024 { // 5MB
-				return nil, errors.New("each foto must not exceed 5MB")
-			}
-
-			allowedTypes := map[string]bool{
-				"image/jpeg": true,
-				"image/png":  true,
-				"image/gif":  true,
-				"image/webp": true,
-			}
-			contentType := file.Header.Get("Content-Type")
-			if !allowedTypes[contentType] {
-				return nil, errors.New("only image files are allowed for foto (jpeg, png, gif, webp)")
-			}
-
-			// Upload foto to R2
-			fileKey, err := s.r2Storage.UploadFile(file, "prestasi")
+			// Validate the file from its content and upload to R2
+			stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryPublikasi, "prestasi")
 			if err != nil {
 				return nil, err
 			}
+			fileKey := stored.Key
 
 			// Generate unique foto ID
 			fotoID := fmt.Sprintf("foto_%d_%s", time.Now().UnixNano(), fileKey[len(fileKey)-8:])
@@ -371,7 +376,10 @@ func (s *PrestasiServiceImpl) Update(id uint, foto []*multipart.FileHeader, foto
 				ID:        fotoID,
 				Filename:  file.Filename,
 				URL:       fileKey,
-				Size:      file.Size,
+				Size:      stored.Size,
+				Width:     stored.Width,
+				Height:    stored.Height,
+				Variants:  fileVariants(stored),
 				Thumbnail: thumbnail,
 			})
 		}
@@ -391,7 +399,8 @@ func (s *PrestasiServiceImpl) Update(id uint, foto []*multipart.FileHeader, foto
 			anggotaData := &models.AnggotaTimPrestasi{
 				PrestasiID:           id,
 				PesertaDidikRombelID: anggota.PesertaDidikRombelID,
-				CreatedByID:          &userID,
+				CreatedByID:          &actor.ID,
+				CreatedByType:        actor.TypePtr(),
 			}
 			if err := s.repository.CreateAnggotaTim(anggotaData); err != nil {
 				return nil, fmt.Errorf("failed to create anggota tim: %w", err)
@@ -399,7 +408,8 @@ func (s *PrestasiServiceImpl) Update(id uint, foto []*multipart.FileHeader, foto
 		}
 	}
 
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	if err := s.repository.Update(existing); err != nil {
 		return nil, err
@@ -426,7 +436,8 @@ func (s *PrestasiServiceImpl) Delete(id uint) error {
 	var fotoItems []models.FotoItem
 	if err := json.Unmarshal(existing.Foto, &fotoItems); err == nil {
 		for _, fotoItem := range fotoItems {
-			_ = s.r2Storage.DeleteFile(fotoItem.URL)
+			_ = s.storage.DeleteFile(fotoItem.URL)
+			deleteFileVariants(s.storage, fotoItem.URL, fotoItem.Variants)
 		}
 	}
 
@@ -447,9 +458,12 @@ func (s *PrestasiServiceImpl) mapToResponse(data *models.Prestasi) *dtos.Prestas
 			fotoItems = append(fotoItems, dtos.FotoItemDTO{
 				ID:        fotoItem.ID,
 				Filename:  fotoItem.Filename,
-				URL:       s.r2Storage.GetPublicURL(fotoItem.URL),
+				URL:       s.storage.GetPublicURL(fotoItem.URL),
 				Size:      fotoItem.Size,
 				Thumbnail: fotoItem.Thumbnail,
+				Width:     fotoItem.Width,
+				Height:    fotoItem.Height,
+				Variants:  fileVariantDTOs(s.storage, fotoItem.Variants),
 			})
 		}
 	}
@@ -470,7 +484,9 @@ func (s *PrestasiServiceImpl) mapToResponse(data *models.Prestasi) *dtos.Prestas
 			CreatedAt:      data.TahunPelajaran.CreatedAt.Format("2006-01-02 15:04:05"),
 			UpdatedAt:      data.TahunPelajaran.UpdatedAt.Format("2006-01-02 15:04:05"),
 			CreatedByID:    data.TahunPelajaran.CreatedByID,
+			CreatedByType:  data.TahunPelajaran.CreatedByType,
 			UpdatedByID:    data.TahunPelajaran.UpdatedByID,
+			UpdatedByType:  data.TahunPelajaran.UpdatedByType,
 		}
 	}
 
@@ -508,24 +524,26 @@ func (s *PrestasiServiceImpl) mapToResponse(data *models.Prestasi) *dtos.Prestas
 		PesertaDidikRombelID: data.PesertaDidikRombelID,
 		PesertaDidikRombel:   pesertaDidikRombel,
 		Jenis:                data.Jenis,
-		NamaGrup:           data.NamaGrup,
-		NamaPrestasi:       data.NamaPrestasi,
-		TingkatPrestasi:    data.TingkatPrestasi,
-		Penyelenggara:      data.Penyelenggara,
-		TanggalLomba:       data.TanggalLomba,
-		Juara:              data.Juara,
-		Keterangan:         data.Keterangan,
-		Foto:               fotoItems,
-		EkstrakurikulerID:  data.EkstrakurikulerID,
-		Ekstrakurikuler:    ekstrakurikuler,
-		TahunPelajaranID:   data.TahunPelajaranID,
-		TahunPelajaran:     tahunPelajaran,
-		Status:             data.Status,
-		AnggotaTimPrestasi: anggotaTimPrestasi,
-		CreatedAt:          data.CreatedAt,
-		UpdatedAt:          data.UpdatedAt,
-		CreatedByID:        data.CreatedByID,
-		UpdatedByID:        data.UpdatedByID,
+		NamaGrup:             data.NamaGrup,
+		NamaPrestasi:         data.NamaPrestasi,
+		TingkatPrestasi:      data.TingkatPrestasi,
+		Penyelenggara:        data.Penyelenggara,
+		TanggalLomba:         data.TanggalLomba,
+		Juara:                data.Juara,
+		Keterangan:           data.Keterangan,
+		Foto:                 fotoItems,
+		EkstrakurikulerID:    data.EkstrakurikulerID,
+		Ekstrakurikuler:      ekstrakurikuler,
+		TahunPelajaranID:     data.TahunPelajaranID,
+		TahunPelajaran:       tahunPelajaran,
+		Status:               data.Status,
+		AnggotaTimPrestasi:   anggotaTimPrestasi,
+		CreatedAt:            data.CreatedAt,
+		UpdatedAt:            data.UpdatedAt,
+		CreatedByID:          data.CreatedByID,
+		CreatedByType:        data.CreatedByType,
+		UpdatedByID:          data.UpdatedByID,
+		UpdatedByType:        data.UpdatedByType,
 	}
 }
 
@@ -537,29 +555,31 @@ func (s *PrestasiServiceImpl) mapPesertaDidikToResponse(data *models.PesertaDidi
 	}
 
 	return &dtos.PesertaDidikResponse{
-		ID:           data.ID,
-		Nama:         data.Nama,
-		NIS:          data.NIS,
-		JenisKelamin: data.JenisKelamin,
-		NISN:         data.NISN,
-		TempatLahir:  data.TempatLahir,
-		TanggalLahir: tanggalLahir,
-		NIK:          data.NIK,
-		Agama:        data.Agama,
-		Alamat:       data.Alamat,
-		RT:           data.RT,
-		RW:           data.RW,
-		Kelurahan:    data.Kelurahan,
-		Kecamatan:    data.Kecamatan,
-		KodePos:      data.KodePos,
-		NamaAyah:     data.NamaAyah,
-		NamaIbu:      data.NamaIbu,
-		Status:       data.Status,
-		Username:     data.Username,
-		CreatedAt:    data.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:    data.UpdatedAt.Format("2006-01-02T15:04:05Z"),
-		CreatedByID:  data.CreatedByID,
-		UpdatedByID:  data.UpdatedByID,
+		ID:            data.ID,
+		Nama:          data.Nama,
+		NIS:           data.NIS,
+		JenisKelamin:  data.JenisKelamin,
+		NISN:          data.NISN,
+		TempatLahir:   data.TempatLahir,
+		TanggalLahir:  tanggalLahir,
+		NIK:           data.NIK,
+		Agama:         data.Agama,
+		Alamat:        data.Alamat,
+		RT:            data.RT,
+		RW:            data.RW,
+		Kelurahan:     data.Kelurahan,
+		Kecamatan:     data.Kecamatan,
+		KodePos:       data.KodePos,
+		NamaAyah:      data.NamaAyah,
+		NamaIbu:       data.NamaIbu,
+		Status:        data.Status,
+		Username:      data.Username,
+		CreatedAt:     data.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:     data.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		CreatedByID:   data.CreatedByID,
+		CreatedByType: data.CreatedByType,
+		UpdatedByID:   data.UpdatedByID,
+		UpdatedByType: data.UpdatedByType,
 	}
 }
 
@@ -602,7 +622,9 @@ func (s *PrestasiServiceImpl) mapPesertaDidikRombelToResponse(data *models.Peser
 		CreatedAt:        data.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt:        data.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 		CreatedByID:      data.CreatedByID,
+		CreatedByType:    data.CreatedByType,
 		UpdatedByID:      data.UpdatedByID,
+		UpdatedByType:    data.UpdatedByType,
 	}
 }
 
@@ -622,7 +644,7 @@ func (s *PrestasiServiceImpl) GetPublicLatest() (*dtos.PrestasiPublicListRespons
 		if err := json.Unmarshal(item.Foto, &fotoModels); err == nil {
 			for _, fotoItem := range fotoModels {
 				if fotoItem.Thumbnail == "active" {
-					fotoThumbnail = s.r2Storage.GetPublicURL(fotoItem.URL)
+					fotoThumbnail = s.storage.GetPublicURL(fotoItem.URL)
 					break
 				}
 			}
@@ -692,7 +714,7 @@ func (s *PrestasiServiceImpl) GetPublicList(req *dtos.PrestasiPublicListRequest)
 		if err := json.Unmarshal(item.Foto, &fotoModels); err == nil {
 			for _, fotoItem := range fotoModels {
 				if fotoItem.Thumbnail == "active" {
-					fotoThumbnail = s.r2Storage.GetPublicURL(fotoItem.URL)
+					fotoThumbnail = s.storage.GetPublicURL(fotoItem.URL)
 					break
 				}
 			}
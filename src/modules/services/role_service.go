@@ -3,6 +3,7 @@ package services
 import (
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
 )
 
 // RoleService handles business logic for Role
@@ -115,6 +116,9 @@ func (s *RoleServiceImpl) GetRoleWithPermissionDetails(id uint) (*models.Role, [
 
 // AssignPermissions assigns permissions to a role
 func (s *RoleServiceImpl) AssignPermissions(roleID uint, permissionIDs []uint) error {
+	// Cached permission sets are stale once the role changes
+	defer utils.InvalidatePermissionCache()
+
 	// Clear existing permissions first
 	if err := s.repository.DeleteRolePermissions(roleID); err != nil {
 		return err
@@ -126,6 +130,8 @@ func (s *RoleServiceImpl) AssignPermissions(roleID uint, permissionIDs []uint) e
 
 // Delete deletes Role by ID
 func (s *RoleServiceImpl) Delete(id uint) error {
+	defer utils.InvalidatePermissionCache()
+
 	// Delete role permissions first
 	if err := s.repository.DeleteRolePermissions(id); err != nil {
 		return err
@@ -5,15 +5,16 @@ import (
 	"pintu-backend/src/dtos"
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
 )
 
 // RombelService handles business logic for Rombel
 type RombelService interface {
-	Create(req *dtos.RombelCreateRequest, userID uint) (*dtos.RombelResponse, error)
+	Create(req *dtos.RombelCreateRequest, actor utils.Principal) (*dtos.RombelResponse, error)
 	GetByID(id uint) (*dtos.RombelResponse, error)
 	GetAll(limit int, offset int) (*dtos.RombelListResponse, error)
 	GetAllWithFilter(params repositories.GetRombelParams) (*dtos.RombelListWithPaginationResponse, error)
-	Update(req *dtos.RombelUpdateRequest, userID uint) (*dtos.RombelResponse, error)
+	Update(req *dtos.RombelUpdateRequest, actor utils.Principal) (*dtos.RombelResponse, error)
 	Delete(id uint) error
 	GetTotalRombel() (*dtos.TotalRombelResponse, error)
 }
@@ -29,7 +30,7 @@ func NewRombelService(repository repositories.RombelRepository, kelasRepository
 }
 
 // Create creates a new Rombel
-func (s *RombelServiceImpl) Create(req *dtos.RombelCreateRequest, userID uint) (*dtos.RombelResponse, error) {
+func (s *RombelServiceImpl) Create(req *dtos.RombelCreateRequest, actor utils.Principal) (*dtos.RombelResponse, error) {
 	// Check if name already exists
 	_, err := s.repository.GetByName(req.Name)
 	if err == nil {
@@ -52,10 +53,11 @@ func (s *RombelServiceImpl) Create(req *dtos.RombelCreateRequest, userID uint) (
 	}
 
 	data := &models.Rombel{
-		Name:        req.Name,
-		Status:      status,
-		KelasID:     req.KelasID,
-		CreatedByID: &userID,
+		Name:          req.Name,
+		Status:        status,
+		KelasID:       req.KelasID,
+		CreatedByID:   &actor.ID,
+		CreatedByType: actor.TypePtr(),
 	}
 
 	if err := s.repository.Create(data); err != nil {
@@ -104,7 +106,7 @@ func (s *RombelServiceImpl) GetAll(limit int, offset int) (*dtos.RombelListRespo
 }
 
 // Update updates Rombel
-func (s *RombelServiceImpl) Update(req *dtos.RombelUpdateRequest, userID uint) (*dtos.RombelResponse, error) {
+func (s *RombelServiceImpl) Update(req *dtos.RombelUpdateRequest, actor utils.Principal) (*dtos.RombelResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(req.ID)
 	if err != nil {
@@ -130,7 +132,8 @@ func (s *RombelServiceImpl) Update(req *dtos.RombelUpdateRequest, userID uint) (
 		existing.KelasID = *req.KelasID
 	}
 
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	if err := s.repository.Update(existing); err != nil {
 		return nil, err
@@ -194,15 +197,17 @@ func (s *RombelServiceImpl) mapToResponse(data *models.Rombel) *dtos.RombelRespo
 	}
 
 	return &dtos.RombelResponse{
-		ID:          data.ID,
-		Name:        data.Name,
-		Status:      data.Status,
-		KelasID:     data.KelasID,
-		Kelas:       kelasDetail,
-		CreatedAt:   data.CreatedAt,
-		UpdatedAt:   data.UpdatedAt,
-		CreatedByID: data.CreatedByID,
-		UpdatedByID: data.UpdatedByID,
+		ID:            data.ID,
+		Name:          data.Name,
+		Status:        data.Status,
+		KelasID:       data.KelasID,
+		Kelas:         kelasDetail,
+		CreatedAt:     data.CreatedAt,
+		UpdatedAt:     data.UpdatedAt,
+		CreatedByID:   data.CreatedByID,
+		CreatedByType: data.CreatedByType,
+		UpdatedByID:   data.UpdatedByID,
+		UpdatedByType: data.UpdatedByType,
 	}
 }
 
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"mime/multipart"
 	"pintu-backend/src/dtos"
@@ -11,58 +12,41 @@ import (
 
 // SaranaPrasaranaService handles business logic for SaranaPrasarana
 type SaranaPrasaranaService interface {
-	Create(file *multipart.FileHeader, req *dtos.SaranaPrasaranaCreateRequest, userID uint) (*dtos.SaranaPrasaranaResponse, error)
+	Create(ctx context.Context, file *multipart.FileHeader, req *dtos.SaranaPrasaranaCreateRequest, actor utils.Principal) (*dtos.SaranaPrasaranaResponse, error)
 	GetByID(id uint) (*dtos.SaranaPrasaranaResponse, error)
 	GetAll(limit int, offset int) (*dtos.SaranaPrasaranaListResponse, error)
 	GetAllWithFilter(params repositories.GetSaranaPrasaranaParams) (*dtos.SaranaPrasaranaListWithPaginationResponse, error)
-	UpdateWithFile(id uint, file *multipart.FileHeader, req *dtos.SaranaPrasaranaUpdateRequest, userID uint) (*dtos.SaranaPrasaranaResponse, error)
+	UpdateWithFile(ctx context.Context, id uint, file *multipart.FileHeader, req *dtos.SaranaPrasaranaUpdateRequest, actor utils.Principal) (*dtos.SaranaPrasaranaResponse, error)
 	Delete(id uint) error
 	GetPublic() ([]dtos.SaranaPrasaranaPublicResponse, error)
 }
 
 type SaranaPrasaranaServiceImpl struct {
 	repository repositories.SaranaPrasaranaRepository
-	r2Storage  *utils.R2Storage
+	storage    utils.Storage
 }
 
 // NewSaranaPrasaranaService creates a new SaranaPrasarana service
-func NewSaranaPrasaranaService(repository repositories.SaranaPrasaranaRepository, r2Storage *utils.R2Storage) SaranaPrasaranaService {
+func NewSaranaPrasaranaService(repository repositories.SaranaPrasaranaRepository, storage utils.Storage) SaranaPrasaranaService {
 	return &SaranaPrasaranaServiceImpl{
 		repository: repository,
-		r2Storage:  r2Storage,
+		storage:    storage,
 	}
 }
 
 // Create creates a new SaranaPrasarana with file upload
-func (s *SaranaPrasaranaServiceImpl) Create(file *multipart.FileHeader, req *dtos.SaranaPrasaranaCreateRequest, userID uint) (*dtos.SaranaPrasaranaResponse, error) {
+func (s *SaranaPrasaranaServiceImpl) Create(ctx context.Context, file *multipart.FileHeader, req *dtos.SaranaPrasaranaCreateRequest, actor utils.Principal) (*dtos.SaranaPrasaranaResponse, error) {
 	// Validate file
 	if file == nil {
 		return nil, errors.New("file is required")
 	}
 
-	// Validate file size (max 5MB)
-	const maxFileSize = 5 * 1024 * 1024
-	if file.Size > maxFileSize {
-		return nil, errors.New("file size must not exceed 5MB")
-	}
-
-	// Validate file type (only images)
-	allowedTypes := map[string]bool{
-		"image/jpeg": true,
-		"image/png":  true,
-		"image/gif":  true,
-		"image/webp": true,
-	}
-	contentType := file.Header.Get("Content-Type")
-	if !allowedTypes[contentType] {
-		return nil, errors.New("only image files are allowed (jpeg, png, gif, webp)")
-	}
-
-	// Upload file to R2 (sarpras directory)
-	fileKey, err := s.r2Storage.UploadFile(file, "sarpras")
+	// Validate the file from its content and upload to R2
+	stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryGambar, "sarpras")
 	if err != nil {
 		return nil, err
 	}
+	fileKey := stored.Key
 
 	// Set default status
 	status := req.Status
@@ -71,15 +55,16 @@ func (s *SaranaPrasaranaServiceImpl) Create(file *multipart.FileHeader, req *dto
 	}
 
 	data := &models.SaranaPrasarana{
-		Name:        req.Name,
-		Foto:        fileKey,
-		Status:      status,
-		CreatedByID: &userID,
+		Name:          req.Name,
+		Foto:          fileKey,
+		Status:        status,
+		CreatedByID:   &actor.ID,
+		CreatedByType: actor.TypePtr(),
 	}
 
 	if err := s.repository.Create(data); err != nil {
 		// If database save fails, delete the uploaded file
-		_ = s.r2Storage.DeleteFile(fileKey)
+		_ = s.storage.DeleteFile(fileKey)
 		return nil, err
 	}
 
@@ -166,7 +151,7 @@ func (s *SaranaPrasaranaServiceImpl) GetAllWithFilter(params repositories.GetSar
 }
 
 // UpdateWithFile updates SaranaPrasarana with optional file upload
-func (s *SaranaPrasaranaServiceImpl) UpdateWithFile(id uint, file *multipart.FileHeader, req *dtos.SaranaPrasaranaUpdateRequest, userID uint) (*dtos.SaranaPrasaranaResponse, error) {
+func (s *SaranaPrasaranaServiceImpl) UpdateWithFile(ctx context.Context, id uint, file *multipart.FileHeader, req *dtos.SaranaPrasaranaUpdateRequest, actor utils.Principal) (*dtos.SaranaPrasaranaResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(id)
 	if err != nil {
@@ -177,30 +162,15 @@ func (s *SaranaPrasaranaServiceImpl) UpdateWithFile(id uint, file *multipart.Fil
 
 	// If file provided, validate and upload
 	if file != nil {
-		// Validate file
-		if file.Size > 5*1024*1024 {
-			return nil, errors.New("file size must not exceed 5MB")
-		}
-
-		allowedTypes := map[string]bool{
-			"image/jpeg": true,
-			"image/png":  true,
-			"image/gif":  true,
-			"image/webp": true,
-		}
-		contentType := file.Header.Get("Content-Type")
-		if !allowedTypes[contentType] {
-			return nil, errors.New("only image files are allowed (jpeg, png, gif, webp)")
-		}
-
-		// Upload new file
-		newFileKey, err := s.r2Storage.UploadFile(file, "sarpras")
+		// Validate the file from its content and upload to R2
+		stored, err := utils.SaveUpload(ctx, s.storage, file, utils.UploadCategoryGambar, "sarpras")
 		if err != nil {
 			return nil, err
 		}
+		newFileKey := stored.Key
 
 		// Delete old file from R2
-		_ = s.r2Storage.DeleteFile(oldFile)
+		_ = s.storage.DeleteFile(oldFile)
 
 		// Update file in model
 		existing.Foto = newFileKey
@@ -216,12 +186,13 @@ func (s *SaranaPrasaranaServiceImpl) UpdateWithFile(id uint, file *multipart.Fil
 		existing.Status = *req.Status
 	}
 
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	if err := s.repository.Update(existing); err != nil {
 		// If DB save fails, delete the uploaded file
 		if file != nil {
-			_ = s.r2Storage.DeleteFile(existing.Foto)
+			_ = s.storage.DeleteFile(existing.Foto)
 		}
 		return nil, err
 	}
@@ -238,7 +209,7 @@ func (s *SaranaPrasaranaServiceImpl) Delete(id uint) error {
 	}
 
 	// Delete file from R2
-	if err := s.r2Storage.DeleteFile(existing.Foto); err != nil {
+	if err := s.storage.DeleteFile(existing.Foto); err != nil {
 		// Log error but continue with database deletion
 	}
 
@@ -256,7 +227,7 @@ func (s *SaranaPrasaranaServiceImpl) GetPublic() ([]dtos.SaranaPrasaranaPublicRe
 	// Map to public response
 	responses := make([]dtos.SaranaPrasaranaPublicResponse, len(data))
 	for i, item := range data {
-		publicURL := s.r2Storage.GetPublicURL(item.Foto)
+		publicURL := s.storage.GetPublicURL(item.Foto)
 		responses[i] = dtos.SaranaPrasaranaPublicResponse{
 			Name: item.Name,
 			Foto: publicURL,
@@ -269,16 +240,18 @@ func (s *SaranaPrasaranaServiceImpl) GetPublic() ([]dtos.SaranaPrasaranaPublicRe
 // mapToResponse maps model to DTO response
 func (s *SaranaPrasaranaServiceImpl) mapToResponse(data *models.SaranaPrasarana) *dtos.SaranaPrasaranaResponse {
 	// Get public URL for the file
-	publicURL := s.r2Storage.GetPublicURL(data.Foto)
+	publicURL := s.storage.GetPublicURL(data.Foto)
 
 	return &dtos.SaranaPrasaranaResponse{
-		ID:          data.ID,
-		Name:        data.Name,
-		Foto:        publicURL,
-		Status:      data.Status,
-		CreatedAt:   data.CreatedAt,
-		UpdatedAt:   data.UpdatedAt,
-		CreatedByID: data.CreatedByID,
-		UpdatedByID: data.UpdatedByID,
+		ID:            data.ID,
+		Name:          data.Name,
+		Foto:          publicURL,
+		Status:        data.Status,
+		CreatedAt:     data.CreatedAt,
+		UpdatedAt:     data.UpdatedAt,
+		CreatedByID:   data.CreatedByID,
+		CreatedByType: data.CreatedByType,
+		UpdatedByID:   data.UpdatedByID,
+		UpdatedByType: data.UpdatedByType,
 	}
 }
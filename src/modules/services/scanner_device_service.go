@@ -0,0 +1,332 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+
+	"gorm.io/datatypes"
+)
+
+// ScannerDeviceService handles business logic for ScannerDevice
+type ScannerDeviceService interface {
+	Create(req *dtos.ScannerDeviceCreateRequest, actor utils.Principal) (*dtos.ScannerDeviceCredentialResponse, error)
+	GetByID(id uint) (*dtos.ScannerDeviceResponse, error)
+	GetAllWithFilter(params repositories.GetScannerDeviceParams) (*dtos.ScannerDeviceListWithPaginationResponse, error)
+	Update(req *dtos.ScannerDeviceUpdateRequest, actor utils.Principal) (*dtos.ScannerDeviceResponse, error)
+	RotateSecret(req *dtos.ScannerDeviceRotateSecretRequest, actor utils.Principal) (*dtos.ScannerDeviceCredentialResponse, error)
+	Delete(id uint) error
+}
+
+type ScannerDeviceServiceImpl struct {
+	repository repositories.ScannerDeviceRepository
+}
+
+// NewScannerDeviceService creates a new ScannerDevice service
+func NewScannerDeviceService(repository repositories.ScannerDeviceRepository) ScannerDeviceService {
+	return &ScannerDeviceServiceImpl{repository: repository}
+}
+
+// Create registers a new scanner device and returns its credential once
+func (s *ScannerDeviceServiceImpl) Create(req *dtos.ScannerDeviceCreateRequest, actor utils.Principal) (*dtos.ScannerDeviceCredentialResponse, error) {
+	authMode := req.AuthMode
+	if authMode == "" {
+		authMode = utils.DeviceAuthHMAC
+	}
+
+	status := req.Status
+	if status == "" {
+		status = "active"
+	}
+
+	allowedCIDRs, err := normalizeAllowedCIDRs(req.AllowedCIDRs)
+	if err != nil {
+		return nil, err
+	}
+
+	jamAktifMulai, jamAktifSelesai, err := normalizeJamAktif(req.JamAktifMulai, req.JamAktifSelesai)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &models.ScannerDevice{
+		Nama:            req.Nama,
+		Lokasi:          req.Lokasi,
+		AllowedCIDRs:    allowedCIDRs,
+		JamAktifMulai:   jamAktifMulai,
+		JamAktifSelesai: jamAktifSelesai,
+		Status:          status,
+		CreatedByID:     &actor.ID,
+		CreatedByType:   actor.TypePtr(),
+	}
+
+	secret, err := s.assignSecret(data, authMode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.Create(data); err != nil {
+		return nil, err
+	}
+
+	return &dtos.ScannerDeviceCredentialResponse{
+		ScannerDeviceResponse: *s.mapToResponse(data),
+		Secret:                secret,
+	}, nil
+}
+
+// GetByID retrieves ScannerDevice by ID
+func (s *ScannerDeviceServiceImpl) GetByID(id uint) (*dtos.ScannerDeviceResponse, error) {
+	data, err := s.repository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.mapToResponse(data), nil
+}
+
+// GetAllWithFilter retrieves ScannerDevice with filters and pagination
+func (s *ScannerDeviceServiceImpl) GetAllWithFilter(params repositories.GetScannerDeviceParams) (*dtos.ScannerDeviceListWithPaginationResponse, error) {
+	// Validate and set default limit and offset
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+	if params.Limit > 100 {
+		params.Limit = 100
+	}
+	if params.Offset < 0 {
+		params.Offset = 0
+	}
+
+	data, total, err := s.repository.GetAllWithFilter(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map to response
+	responses := make([]dtos.ScannerDeviceResponse, len(data))
+	for i, item := range data {
+		responses[i] = *s.mapToResponse(&item)
+	}
+
+	totalPages := (int(total) + params.Limit - 1) / params.Limit
+
+	return &dtos.ScannerDeviceListWithPaginationResponse{
+		Data: responses,
+		Pagination: dtos.PaginationInfo{
+			Limit:      params.Limit,
+			Offset:     params.Offset,
+			Page:       (params.Offset / params.Limit) + 1,
+			Total:      total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// Update updates ScannerDevice details; credentials are changed through RotateSecret only
+func (s *ScannerDeviceServiceImpl) Update(req *dtos.ScannerDeviceUpdateRequest, actor utils.Principal) (*dtos.ScannerDeviceResponse, error) {
+	existing, err := s.repository.GetByID(req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update fields if provided
+	if req.Nama != nil {
+		existing.Nama = *req.Nama
+	}
+	if req.Lokasi != nil {
+		existing.Lokasi = req.Lokasi
+	}
+	if req.Status != nil {
+		existing.Status = *req.Status
+	}
+	if req.AllowedCIDRs != nil {
+		allowedCIDRs, err := normalizeAllowedCIDRs(*req.AllowedCIDRs)
+		if err != nil {
+			return nil, err
+		}
+		existing.AllowedCIDRs = allowedCIDRs
+	}
+	if req.JamAktifMulai != nil || req.JamAktifSelesai != nil {
+		mulai, selesai := existing.JamAktifMulai, existing.JamAktifSelesai
+		if req.JamAktifMulai != nil {
+			mulai = req.JamAktifMulai
+		}
+		if req.JamAktifSelesai != nil {
+			selesai = req.JamAktifSelesai
+		}
+		existing.JamAktifMulai, existing.JamAktifSelesai, err = normalizeJamAktif(mulai, selesai)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
+
+	if err := s.repository.Update(existing); err != nil {
+		return nil, err
+	}
+
+	return s.mapToResponse(existing), nil
+}
+
+// RotateSecret issues a new credential, optionally switching the auth mode.
+// The previous API key or HMAC secret stops working immediately.
+func (s *ScannerDeviceServiceImpl) RotateSecret(req *dtos.ScannerDeviceRotateSecretRequest, actor utils.Principal) (*dtos.ScannerDeviceCredentialResponse, error) {
+	existing, err := s.repository.GetByID(req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	authMode := existing.AuthMode
+	if req.AuthMode != nil {
+		authMode = *req.AuthMode
+	}
+
+	secret, err := s.assignSecret(existing, authMode)
+	if err != nil {
+		return nil, err
+	}
+
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
+
+	if err := s.repository.Update(existing); err != nil {
+		return nil, err
+	}
+
+	return &dtos.ScannerDeviceCredentialResponse{
+		ScannerDeviceResponse: *s.mapToResponse(existing),
+		Secret:                secret,
+	}, nil
+}
+
+// Delete deletes ScannerDevice by ID
+func (s *ScannerDeviceServiceImpl) Delete(id uint) error {
+	return s.repository.Delete(id)
+}
+
+// assignSecret generates a new credential for the device. API keys are stored hashed;
+// HMAC secrets must be kept in plain form because the server recomputes the signature.
+func (s *ScannerDeviceServiceImpl) assignSecret(data *models.ScannerDevice, authMode string) (string, error) {
+	secret, err := utils.GenerateDeviceSecret()
+	if err != nil {
+		return "", err
+	}
+
+	data.AuthMode = authMode
+	switch authMode {
+	case utils.DeviceAuthAPIKey:
+		keyHash := utils.HashDeviceKey(secret)
+		data.KeyHash = &keyHash
+		data.HMACSecret = nil
+	case utils.DeviceAuthHMAC:
+		data.HMACSecret = &secret
+		data.KeyHash = nil
+	default:
+		return "", errors.New("auth_mode tidak valid")
+	}
+
+	return secret, nil
+}
+
+// mapToResponse maps model to DTO response
+func (s *ScannerDeviceServiceImpl) mapToResponse(data *models.ScannerDevice) *dtos.ScannerDeviceResponse {
+	allowedCIDRs := []string{}
+	if len(data.AllowedCIDRs) > 0 {
+		_ = json.Unmarshal(data.AllowedCIDRs, &allowedCIDRs)
+	}
+
+	return &dtos.ScannerDeviceResponse{
+		ID:              data.ID,
+		Nama:            data.Nama,
+		Lokasi:          data.Lokasi,
+		AuthMode:        data.AuthMode,
+		AllowedCIDRs:    allowedCIDRs,
+		JamAktifMulai:   data.JamAktifMulai,
+		JamAktifSelesai: data.JamAktifSelesai,
+		Status:          data.Status,
+		LastSeenAt:      data.LastSeenAt,
+		LastSeenIP:      data.LastSeenIP,
+		CreatedAt:       data.CreatedAt,
+		UpdatedAt:       data.UpdatedAt,
+		CreatedByID:     data.CreatedByID,
+		CreatedByType:   data.CreatedByType,
+		UpdatedByID:     data.UpdatedByID,
+		UpdatedByType:   data.UpdatedByType,
+	}
+}
+
+// normalizeAllowedCIDRs validates the allowed IP ranges and turns single IPs into host CIDRs
+func normalizeAllowedCIDRs(values []string) (datatypes.JSON, error) {
+	cidrs := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("alamat IP tidak valid: %s", value)
+			}
+			if ip.To4() != nil {
+				value += "/32"
+			} else {
+				value += "/128"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("rentang IP tidak valid: %s", value)
+		}
+		cidrs = append(cidrs, network.String())
+	}
+
+	encoded, err := json.Marshal(cidrs)
+	if err != nil {
+		return nil, err
+	}
+	return datatypes.JSON(encoded), nil
+}
+
+// normalizeJamAktif validates the optional active time window. Both ends must be set
+// together; an empty string clears the window.
+func normalizeJamAktif(mulai, selesai *string) (*string, *string, error) {
+	normalized := make([]*string, 2)
+	for i, value := range []*string{mulai, selesai} {
+		if value == nil || strings.TrimSpace(*value) == "" {
+			continue
+		}
+
+		var parsed time.Time
+		var err error
+		for _, layout := range []string{"15:04:05", "15:04"} {
+			if parsed, err = time.Parse(layout, strings.TrimSpace(*value)); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return nil, nil, errors.New("format jam aktif harus HH:MM atau HH:MM:SS")
+		}
+
+		formatted := parsed.Format("15:04:05")
+		normalized[i] = &formatted
+	}
+
+	if (normalized[0] == nil) != (normalized[1] == nil) {
+		return nil, nil, errors.New("jam aktif mulai dan selesai harus diisi bersamaan")
+	}
+
+	return normalized[0], normalized[1], nil
+}
@@ -4,11 +4,12 @@ import (
 	"pintu-backend/src/dtos"
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
 )
 
 // SettingLayananSPMBService handles business logic for Setting Layanan SPMB
 type SettingLayananSPMBService interface {
-	UpsertSetting(req *dtos.SettingLayananSPMBRequest, userID uint) (*dtos.SettingLayananSPMBResponse, error)
+	UpsertSetting(req *dtos.SettingLayananSPMBRequest, actor utils.Principal) (*dtos.SettingLayananSPMBResponse, error)
 	GetGrupWAPublic() (*dtos.GrupWASPMBResponse, error)
 	GetSetting() (*dtos.SettingLayananSPMBResponse, error)
 }
@@ -25,7 +26,7 @@ func NewSettingLayananSPMBService(repository repositories.SettingLayananSPMBRepo
 }
 
 // UpsertSetting creates or updates Setting Layanan SPMB with ID = 1
-func (s *SettingLayananSPMBServiceImpl) UpsertSetting(req *dtos.SettingLayananSPMBRequest, userID uint) (*dtos.SettingLayananSPMBResponse, error) {
+func (s *SettingLayananSPMBServiceImpl) UpsertSetting(req *dtos.SettingLayananSPMBRequest, actor utils.Principal) (*dtos.SettingLayananSPMBResponse, error) {
 	// Check if record with ID = 1 exists
 	existing, err := s.repository.GetByID(1)
 
@@ -56,8 +57,10 @@ func (s *SettingLayananSPMBServiceImpl) UpsertSetting(req *dtos.SettingLayananSP
 			NamaKetuaPanitia:  namaKetua,
 			NIPKetuaPanitia:   nipKetua,
 			GrupWA:            grupWA,
-			CreatedByID:       &userID,
-			UpdatedByID:       &userID,
+			CreatedByID:       &actor.ID,
+			CreatedByType:     actor.TypePtr(),
+			UpdatedByID:       &actor.ID,
+			UpdatedByType:     actor.TypePtr(),
 		}
 
 		if err := s.repository.Create(data); err != nil {
@@ -73,7 +76,8 @@ func (s *SettingLayananSPMBServiceImpl) UpsertSetting(req *dtos.SettingLayananSP
 	existing.NamaKetuaPanitia = namaKetua
 	existing.NIPKetuaPanitia = nipKetua
 	existing.GrupWA = grupWA
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	if err := s.repository.Update(existing); err != nil {
 		return nil, err
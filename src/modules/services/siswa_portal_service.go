@@ -0,0 +1,153 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// SiswaPortalService handles read-only business logic for the logged-in peserta didik.
+// Every method takes the PesertaDidikID of the caller, never an ID from the request body.
+type SiswaPortalService interface {
+	GetProfil(pesertaDidikID uint) (*dtos.PesertaDidikResponse, error)
+	GetRombelAktif(pesertaDidikID uint) (*dtos.PesertaDidikRombelResponse, error)
+	GetRiwayatAbsensi(pesertaDidikID uint, req *dtos.SiswaAbsensiRequest) (*dtos.DashboardSiswaResponse, error)
+	GetPrestasi(pesertaDidikID uint) ([]dtos.PrestasiResponse, error)
+	DownloadKartuPelajar(pesertaDidikID uint) ([]byte, error)
+	GetKartuQR(pesertaDidikID uint) (*dtos.KartuQRResponse, error)
+}
+
+type SiswaPortalServiceImpl struct {
+	pesertaDidikService       PesertaDidikService
+	pesertaDidikRombelService PesertaDidikRombelService
+	absensiService            AbsensiService
+	prestasiService           PrestasiService
+	pesertaDidikRombelRepo    repositories.PesertaDidikRombelRepository
+	tahunPelajaranRepo        repositories.TahunPelajaranRepository
+	konfigurasiAbsensiRepo    repositories.KonfigurasiAbsensiRepository
+}
+
+// NewSiswaPortalService creates a new SiswaPortal service
+func NewSiswaPortalService(
+	pesertaDidikService PesertaDidikService,
+	pesertaDidikRombelService PesertaDidikRombelService,
+	absensiService AbsensiService,
+	prestasiService PrestasiService,
+	pesertaDidikRombelRepo repositories.PesertaDidikRombelRepository,
+	tahunPelajaranRepo repositories.TahunPelajaranRepository,
+	konfigurasiAbsensiRepo repositories.KonfigurasiAbsensiRepository,
+) SiswaPortalService {
+	return &SiswaPortalServiceImpl{
+		pesertaDidikService:       pesertaDidikService,
+		pesertaDidikRombelService: pesertaDidikRombelService,
+		absensiService:            absensiService,
+		prestasiService:           prestasiService,
+		pesertaDidikRombelRepo:    pesertaDidikRombelRepo,
+		tahunPelajaranRepo:        tahunPelajaranRepo,
+		konfigurasiAbsensiRepo:    konfigurasiAbsensiRepo,
+	}
+}
+
+// GetProfil retrieves the profile of the student
+func (s *SiswaPortalServiceImpl) GetProfil(pesertaDidikID uint) (*dtos.PesertaDidikResponse, error) {
+	profil, err := s.pesertaDidikService.GetByID(pesertaDidikID)
+	if err != nil {
+		return nil, errors.New("data siswa tidak ditemukan")
+	}
+	return profil, nil
+}
+
+// GetRombelAktif retrieves the rombel of the student in the active tahun pelajaran
+func (s *SiswaPortalServiceImpl) GetRombelAktif(pesertaDidikID uint) (*dtos.PesertaDidikRombelResponse, error) {
+	tahunPelajaran, err := s.tahunPelajaranRepo.GetActiveAcademicYear()
+	if err != nil {
+		return nil, errors.New("tahun pelajaran aktif tidak ditemukan")
+	}
+
+	mapping, err := s.pesertaDidikRombelRepo.GetByPesertaDidikAndTahunPelajaran(pesertaDidikID, tahunPelajaran.ID)
+	if err != nil {
+		return nil, errors.New("siswa belum terdaftar di rombel pada tahun pelajaran aktif")
+	}
+
+	return s.pesertaDidikRombelService.GetByID(mapping.ID)
+}
+
+// GetRiwayatAbsensi retrieves the attendance dashboard of the student for a tahun pelajaran
+func (s *SiswaPortalServiceImpl) GetRiwayatAbsensi(pesertaDidikID uint, req *dtos.SiswaAbsensiRequest) (*dtos.DashboardSiswaResponse, error) {
+	var tahunPelajaranID uint
+	if req.TahunPelajaranID != nil {
+		tahunPelajaranID = *req.TahunPelajaranID
+	} else {
+		tahunPelajaran, err := s.tahunPelajaranRepo.GetActiveAcademicYear()
+		if err != nil {
+			return nil, errors.New("tahun pelajaran aktif tidak ditemukan")
+		}
+		tahunPelajaranID = tahunPelajaran.ID
+	}
+
+	// Resolve the rombel mapping of the caller so other students' records stay out of reach
+	mapping, err := s.pesertaDidikRombelRepo.GetByPesertaDidikAndTahunPelajaran(pesertaDidikID, tahunPelajaranID)
+	if err != nil {
+		return nil, errors.New("siswa tidak terdaftar di rombel pada tahun pelajaran tersebut")
+	}
+
+	return s.absensiService.GetDashboardSiswa(&dtos.DashboardSiswaRequest{
+		PesertaDidikRombelID: mapping.ID,
+		TahunPelajaranID:     mapping.TahunPelajaranID,
+		RombelID:             mapping.RombelID,
+		Semester:             req.Semester,
+		BidangStudiID:        req.BidangStudiID,
+		Periode:              req.Periode,
+		TanggalMulai:         req.TanggalMulai,
+		TanggalSelesai:       req.TanggalSelesai,
+		LimitRiwayat:         req.LimitRiwayat,
+	})
+}
+
+// GetPrestasi retrieves the prestasi of the student, including team prestasi
+func (s *SiswaPortalServiceImpl) GetPrestasi(pesertaDidikID uint) ([]dtos.PrestasiResponse, error) {
+	return s.prestasiService.GetByPesertaDidikID(pesertaDidikID)
+}
+
+// DownloadKartuPelajar generates the kartu pelajar PDF of the student
+func (s *SiswaPortalServiceImpl) DownloadKartuPelajar(pesertaDidikID uint) ([]byte, error) {
+	return s.pesertaDidikService.DownloadKartuPelajar([]uint{pesertaDidikID})
+}
+
+// GetKartuQR signs a short-lived QR of the kartu pelajar for the scanner, replacing the printed barcode
+// when konfigurasi absensi allows the dinamis mode
+func (s *SiswaPortalServiceImpl) GetKartuQR(pesertaDidikID uint) (*dtos.KartuQRResponse, error) {
+	config, err := s.konfigurasiAbsensiRepo.GetByID(1)
+	if err != nil {
+		return nil, errors.New("konfigurasi absensi belum diatur")
+	}
+	if config.ModeKartuQR != models.ModeKartuQRCampuran && config.ModeKartuQR != models.ModeKartuQRDinamis {
+		return nil, errors.New("QR kartu pelajar dinamis tidak diaktifkan sekolah")
+	}
+
+	issuedAt := time.Now()
+	payload, err := utils.SignKartuQR(pesertaDidikID, issuedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	png, err := qrcode.Encode(payload, qrcode.Medium, 384)
+	if err != nil {
+		return nil, errors.New("gagal membuat QR code")
+	}
+
+	// Refresh halfway so the QR on screen never expires while being scanned
+	return &dtos.KartuQRResponse{
+		Payload:       payload,
+		QRCode:        "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+		BerlakuHingga: issuedAt.Add(utils.KartuQRTTL).In(utils.JakartaLocation()).Format(time.RFC3339),
+		RefreshDetik:  int(utils.KartuQRTTL.Seconds()) / 2,
+	}, nil
+}
@@ -6,15 +6,16 @@ import (
 	"pintu-backend/src/dtos"
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
 )
 
 // StrukturOrganisasiService handles business logic for StrukturOrganisasi
 type StrukturOrganisasiService interface {
-	Create(req *dtos.StrukturOrganisasiCreateRequest, userID uint) (*dtos.StrukturOrganisasiResponse, error)
+	Create(req *dtos.StrukturOrganisasiCreateRequest, actor utils.Principal) (*dtos.StrukturOrganisasiResponse, error)
 	GetByID(id uint) (*dtos.StrukturOrganisasiResponse, error)
 	GetAll(limit int, offset int) (*dtos.StrukturOrganisasiListResponse, error)
 	GetAllWithFilter(params repositories.GetStrukturOrganisasiParams) (*dtos.StrukturOrganisasiListWithPaginationResponse, error)
-	Update(req *dtos.StrukturOrganisasiUpdateRequest, userID uint) (*dtos.StrukturOrganisasiResponse, error)
+	Update(req *dtos.StrukturOrganisasiUpdateRequest, actor utils.Principal) (*dtos.StrukturOrganisasiResponse, error)
 	Delete(id uint) error
 	GetPublic() ([]dtos.StrukturOrganisasiGroupedResponse, error)
 }
@@ -33,7 +34,7 @@ func NewStrukturOrganisasiService(repository repositories.StrukturOrganisasiRepo
 }
 
 // Create creates a new StrukturOrganisasi
-func (s *StrukturOrganisasiServiceImpl) Create(req *dtos.StrukturOrganisasiCreateRequest, userID uint) (*dtos.StrukturOrganisasiResponse, error) {
+func (s *StrukturOrganisasiServiceImpl) Create(req *dtos.StrukturOrganisasiCreateRequest, actor utils.Principal) (*dtos.StrukturOrganisasiResponse, error) {
 	// Validate that either pegawai_id or nama_non_pegawai is provided
 	if req.PegawaiID == nil && req.NamaNonPegawai == "" {
 		return nil, errors.New("either pegawai_id or nama_non_pegawai must be provided")
@@ -60,7 +61,8 @@ func (s *StrukturOrganisasiServiceImpl) Create(req *dtos.StrukturOrganisasiCreat
 		Urutan:            req.Urutan,
 		Relasi:            req.Relasi,
 		Status:            status,
-		CreatedByID:       &userID,
+		CreatedByID:       &actor.ID,
+		CreatedByType:     actor.TypePtr(),
 	}
 
 	if err := s.repository.Create(data); err != nil {
@@ -150,7 +152,7 @@ func (s *StrukturOrganisasiServiceImpl) GetAllWithFilter(params repositories.Get
 }
 
 // Update updates StrukturOrganisasi
-func (s *StrukturOrganisasiServiceImpl) Update(req *dtos.StrukturOrganisasiUpdateRequest, userID uint) (*dtos.StrukturOrganisasiResponse, error) {
+func (s *StrukturOrganisasiServiceImpl) Update(req *dtos.StrukturOrganisasiUpdateRequest, actor utils.Principal) (*dtos.StrukturOrganisasiResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(req.ID)
 	if err != nil {
@@ -193,7 +195,8 @@ func (s *StrukturOrganisasiServiceImpl) Update(req *dtos.StrukturOrganisasiUpdat
 		existing.Status = *req.Status
 	}
 
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	if err := s.repository.Update(existing); err != nil {
 		return nil, err
@@ -485,7 +488,11 @@ func (s *StrukturOrganisasiServiceImpl) mapToResponse(data *models.StrukturOrgan
 		CreatedAt:         data.CreatedAt,
 		UpdatedAt:         data.UpdatedAt,
 		CreatedByID:       data.CreatedByID,
+		CreatedByType:     data.CreatedByType,
 		UpdatedByID:       data.UpdatedByID,
+		UpdatedByType:     data.UpdatedByType,
+		CreatedBy:         data.CreatedBy,
+		UpdatedBy:         data.UpdatedBy,
 	}
 
 	// Add Pegawai data if available
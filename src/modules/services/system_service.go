@@ -3,6 +3,7 @@ package services
 import (
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
 )
 
 // SystemService handles business logic for System
@@ -46,10 +47,15 @@ func (s *SystemServiceImpl) GetAllWithFilter(params repositories.GetSystemsParam
 
 // Update updates System
 func (s *SystemServiceImpl) Update(data *models.System) error {
+	// The system code may have moved to another row
+	defer utils.InvalidateSystemIDCache()
+
 	return s.repository.Update(data)
 }
 
 // Delete deletes System by ID
 func (s *SystemServiceImpl) Delete(id uint) error {
+	defer utils.InvalidateSystemIDCache()
+
 	return s.repository.Delete(id)
 }
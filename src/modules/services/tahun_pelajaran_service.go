@@ -2,19 +2,25 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
 	"pintu-backend/src/dtos"
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
 )
 
 // TahunPelajaranService handles business logic for TahunPelajaran
 type TahunPelajaranService interface {
-	Create(req *dtos.TahunPelajaranCreateRequest, userID uint) (*dtos.TahunPelajaranResponse, error)
+	Create(req *dtos.TahunPelajaranCreateRequest, actor utils.Principal) (*dtos.TahunPelajaranResponse, error)
 	GetByID(id uint) (*dtos.TahunPelajaranResponse, error)
 	GetAll(limit int, offset int) (*dtos.TahunPelajaranListResponse, error)
 	GetAllWithFilter(params repositories.GetTahunPelajaranParams) (*dtos.TahunPelajaranListWithPaginationResponse, error)
 	GetActiveTahunPelajaran() (*dtos.TahunPelajaranResponse, error)
-	Update(req *dtos.TahunPelajaranUpdateRequest, userID uint) (*dtos.TahunPelajaranResponse, error)
+	Update(req *dtos.TahunPelajaranUpdateRequest, actor utils.Principal) (*dtos.TahunPelajaranResponse, error)
 	Delete(id uint) error
 }
 
@@ -28,7 +34,7 @@ func NewTahunPelajaranService(repository repositories.TahunPelajaranRepository)
 }
 
 // Create creates a new TahunPelajaran
-func (s *TahunPelajaranServiceImpl) Create(req *dtos.TahunPelajaranCreateRequest, userID uint) (*dtos.TahunPelajaranResponse, error) {
+func (s *TahunPelajaranServiceImpl) Create(req *dtos.TahunPelajaranCreateRequest, actor utils.Principal) (*dtos.TahunPelajaranResponse, error) {
 	// Check if tahun_pelajaran already exists
 	_, err := s.repository.GetByTahunPelajaran(req.TahunPelajaran)
 	if err == nil {
@@ -41,6 +47,40 @@ func (s *TahunPelajaranServiceImpl) Create(req *dtos.TahunPelajaranCreateRequest
 		status = "active"
 	}
 
+	data := &models.TahunPelajaran{
+		TahunPelajaran: req.TahunPelajaran,
+		Status:         status,
+		CreatedByID:    &actor.ID,
+		CreatedByType:  actor.TypePtr(),
+	}
+
+	// Semester dates, defaulting from the name when none are given
+	if req.Semester1Mulai == "" && req.Semester1Selesai == "" && req.Semester2Mulai == "" && req.Semester2Selesai == "" {
+		if !setDefaultSemester(data) {
+			return nil, errors.New("semester1_mulai, semester1_selesai, semester2_mulai dan semester2_selesai wajib diisi jika tahun_pelajaran bukan format YYYY/YYYY")
+		}
+	} else {
+		for _, field := range []struct {
+			name  string
+			value string
+			dest  **time.Time
+		}{
+			{"semester1_mulai", req.Semester1Mulai, &data.Semester1Mulai},
+			{"semester1_selesai", req.Semester1Selesai, &data.Semester1Selesai},
+			{"semester2_mulai", req.Semester2Mulai, &data.Semester2Mulai},
+			{"semester2_selesai", req.Semester2Selesai, &data.Semester2Selesai},
+		} {
+			tanggal, err := parseTanggalSemester(field.name, field.value)
+			if err != nil {
+				return nil, err
+			}
+			*field.dest = &tanggal
+		}
+	}
+	if err := s.validateSemester(data); err != nil {
+		return nil, err
+	}
+
 	// If status is active, set all others to inactive
 	if status == "active" {
 		if err := s.repository.UpdateAllStatusToInactive(); err != nil {
@@ -48,12 +88,6 @@ func (s *TahunPelajaranServiceImpl) Create(req *dtos.TahunPelajaranCreateRequest
 		}
 	}
 
-	data := &models.TahunPelajaran{
-		TahunPelajaran: req.TahunPelajaran,
-		Status:         status,
-		CreatedByID:    &userID,
-	}
-
 	if err := s.repository.Create(data); err != nil {
 		return nil, err
 	}
@@ -100,7 +134,7 @@ func (s *TahunPelajaranServiceImpl) GetAll(limit int, offset int) (*dtos.TahunPe
 }
 
 // Update updates TahunPelajaran
-func (s *TahunPelajaranServiceImpl) Update(req *dtos.TahunPelajaranUpdateRequest, userID uint) (*dtos.TahunPelajaranResponse, error) {
+func (s *TahunPelajaranServiceImpl) Update(req *dtos.TahunPelajaranUpdateRequest, actor utils.Principal) (*dtos.TahunPelajaranResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(req.ID)
 	if err != nil {
@@ -114,8 +148,31 @@ func (s *TahunPelajaranServiceImpl) Update(req *dtos.TahunPelajaranUpdateRequest
 	if req.Status != nil {
 		existing.Status = *req.Status
 	}
+	for _, field := range []struct {
+		name  string
+		value *string
+		dest  **time.Time
+	}{
+		{"semester1_mulai", req.Semester1Mulai, &existing.Semester1Mulai},
+		{"semester1_selesai", req.Semester1Selesai, &existing.Semester1Selesai},
+		{"semester2_mulai", req.Semester2Mulai, &existing.Semester2Mulai},
+		{"semester2_selesai", req.Semester2Selesai, &existing.Semester2Selesai},
+	} {
+		if field.value == nil {
+			continue
+		}
+		tanggal, err := parseTanggalSemester(field.name, *field.value)
+		if err != nil {
+			return nil, err
+		}
+		*field.dest = &tanggal
+	}
+	if err := s.validateSemester(existing); err != nil {
+		return nil, err
+	}
 
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	// If status is being set to active, set all others to inactive
 	if req.Status != nil && *req.Status == "active" {
@@ -183,15 +240,96 @@ func (s *TahunPelajaranServiceImpl) GetActiveTahunPelajaran() (*dtos.TahunPelaja
 	return s.mapToResponse(data), nil
 }
 
+// validateSemester checks the order of the semester dates and that they do not overlap another tahun pelajaran
+func (s *TahunPelajaranServiceImpl) validateSemester(data *models.TahunPelajaran) error {
+	if data.Semester1Mulai == nil || data.Semester1Selesai == nil || data.Semester2Mulai == nil || data.Semester2Selesai == nil {
+		return errors.New("semester1_mulai, semester1_selesai, semester2_mulai dan semester2_selesai wajib diisi")
+	}
+	if data.Semester1Selesai.Before(*data.Semester1Mulai) {
+		return errors.New("semester1_selesai harus setelah atau sama dengan semester1_mulai")
+	}
+	if !data.Semester2Mulai.After(*data.Semester1Selesai) {
+		return errors.New("semester2_mulai harus setelah semester1_selesai")
+	}
+	if data.Semester2Selesai.Before(*data.Semester2Mulai) {
+		return errors.New("semester2_selesai harus setelah atau sama dengan semester2_mulai")
+	}
+	if data.Semester2Selesai.Sub(*data.Semester1Mulai) > 366*24*time.Hour {
+		return errors.New("rentang semester 1 sampai semester 2 maksimal 1 tahun")
+	}
+
+	others, err := s.repository.GetAllWithSemester()
+	if err != nil {
+		return errors.New("gagal mengambil data tahun pelajaran")
+	}
+	for _, other := range others {
+		if other.ID == data.ID {
+			continue
+		}
+		if !data.Semester1Mulai.After(*other.Semester2Selesai) && !other.Semester1Mulai.After(*data.Semester2Selesai) {
+			return fmt.Errorf("tanggal semester bertabrakan dengan tahun pelajaran %s", other.TahunPelajaran)
+		}
+	}
+	return nil
+}
+
 // mapToResponse maps model to DTO response
 func (s *TahunPelajaranServiceImpl) mapToResponse(data *models.TahunPelajaran) *dtos.TahunPelajaranResponse {
 	return &dtos.TahunPelajaranResponse{
-		ID:             data.ID,
-		TahunPelajaran: data.TahunPelajaran,
-		Status:         data.Status,
-		CreatedAt:      data.CreatedAt,
-		UpdatedAt:      data.UpdatedAt,
-		CreatedByID:    data.CreatedByID,
-		UpdatedByID:    data.UpdatedByID,
+		ID:               data.ID,
+		TahunPelajaran:   data.TahunPelajaran,
+		Status:           data.Status,
+		Semester1Mulai:   formatTanggalSemester(data.Semester1Mulai),
+		Semester1Selesai: formatTanggalSemester(data.Semester1Selesai),
+		Semester2Mulai:   formatTanggalSemester(data.Semester2Mulai),
+		Semester2Selesai: formatTanggalSemester(data.Semester2Selesai),
+		CreatedAt:        data.CreatedAt,
+		UpdatedAt:        data.UpdatedAt,
+		CreatedByID:      data.CreatedByID,
+		CreatedByType:    data.CreatedByType,
+		UpdatedByID:      data.UpdatedByID,
+		UpdatedByType:    data.UpdatedByType,
+	}
+}
+
+// namaTahunPelajaranPattern matches names like "2026/2027"
+var namaTahunPelajaranPattern = regexp.MustCompile(`^(\d{4})/(\d{4})$`)
+
+// setDefaultSemester sets semester 1 to 1 Juli - 31 Desember and semester 2 to 1 Januari - 30 Juni
+// from a "YYYY/YYYY" name, reporting false when the name has another format
+func setDefaultSemester(data *models.TahunPelajaran) bool {
+	match := namaTahunPelajaranPattern.FindStringSubmatch(data.TahunPelajaran)
+	if match == nil {
+		return false
+	}
+	tahunAwal, _ := strconv.Atoi(match[1])
+	tahunAkhir, _ := strconv.Atoi(match[2])
+
+	semester1Mulai := time.Date(tahunAwal, time.July, 1, 0, 0, 0, 0, time.UTC)
+	semester1Selesai := time.Date(tahunAwal, time.December, 31, 0, 0, 0, 0, time.UTC)
+	semester2Mulai := time.Date(tahunAkhir, time.January, 1, 0, 0, 0, 0, time.UTC)
+	semester2Selesai := time.Date(tahunAkhir, time.June, 30, 0, 0, 0, 0, time.UTC)
+	data.Semester1Mulai = &semester1Mulai
+	data.Semester1Selesai = &semester1Selesai
+	data.Semester2Mulai = &semester2Mulai
+	data.Semester2Selesai = &semester2Selesai
+	return true
+}
+
+// parseTanggalSemester parses a semester date typed as YYYY-MM-DD
+func parseTanggalSemester(field, value string) (time.Time, error) {
+	tanggal, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("format %s tidak valid, gunakan YYYY-MM-DD", field)
+	}
+	return tanggal, nil
+}
+
+// formatTanggalSemester formats an optional semester date as YYYY-MM-DD
+func formatTanggalSemester(tanggal *time.Time) *string {
+	if tanggal == nil {
+		return nil
 	}
+	formatted := tanggal.Format("2006-01-02")
+	return &formatted
 }
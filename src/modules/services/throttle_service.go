@@ -0,0 +1,138 @@
+package services
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
+)
+
+// ErrTooManyAttempts is returned while a throttling window is exhausted
+var ErrTooManyAttempts = errors.New("terlalu banyak percobaan gagal, silakan coba lagi nanti")
+
+// ThrottlePolicy limits failed attempts per IP address and per identifier within a sliding window
+type ThrottlePolicy struct {
+	Scope            string
+	Window           time.Duration
+	MaxPerIP         int64
+	MaxPerIdentifier int64
+}
+
+var (
+	// LoginThrottlePolicy guards username and password logins
+	LoginThrottlePolicy = ThrottlePolicy{
+		Scope:            "login",
+		Window:           15 * time.Minute,
+		MaxPerIP:         30,
+		MaxPerIdentifier: 10,
+	}
+
+	// LoginSiswaThrottlePolicy guards peserta didik logins
+	LoginSiswaThrottlePolicy = ThrottlePolicy{
+		Scope:            "login-siswa",
+		Window:           15 * time.Minute,
+		MaxPerIP:         30,
+		MaxPerIdentifier: 10,
+	}
+
+	// CekKelulusanThrottlePolicy guards the public NISN and tanggal lahir lookups
+	CekKelulusanThrottlePolicy = ThrottlePolicy{
+		Scope:            "cek-kelulusan",
+		Window:           15 * time.Minute,
+		MaxPerIP:         30,
+		MaxPerIdentifier: 5,
+	}
+
+	// PengajuanIzinThrottlePolicy guards the NIS and tanggal lahir check of the public izin form
+	PengajuanIzinThrottlePolicy = ThrottlePolicy{
+		Scope:            "pengajuan-izin",
+		Window:           15 * time.Minute,
+		MaxPerIP:         30,
+		MaxPerIdentifier: 5,
+	}
+)
+
+// ThrottleService handles business logic for throttling failed authentication attempts
+type ThrottleService interface {
+	Check(policy ThrottlePolicy, ipAddress, identifier string) error
+	RecordFailure(policy ThrottlePolicy, ipAddress, identifier string) error
+	Failures(policy ThrottlePolicy, identifier string) (int64, error)
+	Reset(policy ThrottlePolicy, identifier string) error
+}
+
+type ThrottleServiceImpl struct {
+	repository repositories.FailedAttemptRepository
+}
+
+// NewThrottleService creates a new Throttle service
+func NewThrottleService(repository repositories.FailedAttemptRepository) ThrottleService {
+	return &ThrottleServiceImpl{repository: repository}
+}
+
+// Check returns ErrTooManyAttempts when the IP address or the identifier used up its window
+func (s *ThrottleServiceImpl) Check(policy ThrottlePolicy, ipAddress, identifier string) error {
+	since := time.Now().Add(-policy.Window)
+
+	count, err := s.repository.CountByIPSince(policy.Scope, normalizeIPAddress(ipAddress), since)
+	if err != nil {
+		return errors.New("gagal memeriksa batas percobaan")
+	}
+	if count >= policy.MaxPerIP {
+		utils.ThrottledAttemptsTotal.WithLabelValues(policy.Scope, "ip").Inc()
+		return ErrTooManyAttempts
+	}
+
+	count, err = s.repository.CountByIdentifierSince(policy.Scope, normalizeIdentifier(identifier), since)
+	if err != nil {
+		return errors.New("gagal memeriksa batas percobaan")
+	}
+	if count >= policy.MaxPerIdentifier {
+		utils.ThrottledAttemptsTotal.WithLabelValues(policy.Scope, "identifier").Inc()
+		return ErrTooManyAttempts
+	}
+
+	return nil
+}
+
+// RecordFailure stores a failed attempt so it counts against both windows
+func (s *ThrottleServiceImpl) RecordFailure(policy ThrottlePolicy, ipAddress, identifier string) error {
+	return s.repository.Create(&models.FailedAttempt{
+		Scope:      policy.Scope,
+		IPAddress:  normalizeIPAddress(ipAddress),
+		Identifier: normalizeIdentifier(identifier),
+	})
+}
+
+// Failures counts the failed attempts of the identifier within the window
+func (s *ThrottleServiceImpl) Failures(policy ThrottlePolicy, identifier string) (int64, error) {
+	return s.repository.CountByIdentifierSince(policy.Scope, normalizeIdentifier(identifier), time.Now().Add(-policy.Window))
+}
+
+// Reset clears the identifier window after a success or an admin unlock, the IP window keeps counting
+func (s *ThrottleServiceImpl) Reset(policy ThrottlePolicy, identifier string) error {
+	return s.repository.DeleteByIdentifier(policy.Scope, normalizeIdentifier(identifier))
+}
+
+// normalizeIPAddress keys IPv6 clients on their /64 network, which a single client can rotate addresses within.
+// The address comes from gin's ClientIP, so forwarded headers only count from TRUSTED_PROXIES.
+func normalizeIPAddress(ipAddress string) string {
+	ip := net.ParseIP(ipAddress)
+	if ip == nil || ip.To4() != nil {
+		return ipAddress
+	}
+	network := &net.IPNet{IP: ip.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}
+	return network.String()
+}
+
+// normalizeIdentifier makes usernames and NISN compare case and whitespace insensitive
+func normalizeIdentifier(identifier string) string {
+	identifier = strings.ToLower(strings.TrimSpace(identifier))
+	if len(identifier) > 255 {
+		identifier = identifier[:255]
+	}
+	return identifier
+}
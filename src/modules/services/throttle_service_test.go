@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"pintu-backend/src/modules/models"
+	"testing"
+	"time"
+)
+
+// fakeFailedAttemptRepository keeps failed attempts in memory, ignoring their time
+type fakeFailedAttemptRepository struct {
+	attempts []models.FailedAttempt
+}
+
+func (r *fakeFailedAttemptRepository) Create(data *models.FailedAttempt) error {
+	r.attempts = append(r.attempts, *data)
+	return nil
+}
+
+func (r *fakeFailedAttemptRepository) CountByIPSince(scope, ipAddress string, since time.Time) (int64, error) {
+	var count int64
+	for _, attempt := range r.attempts {
+		if attempt.Scope == scope && attempt.IPAddress == ipAddress {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeFailedAttemptRepository) CountByIdentifierSince(scope, identifier string, since time.Time) (int64, error) {
+	var count int64
+	for _, attempt := range r.attempts {
+		if attempt.Scope == scope && attempt.Identifier == identifier {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeFailedAttemptRepository) DeleteByIdentifier(scope, identifier string) error {
+	kept := r.attempts[:0]
+	for _, attempt := range r.attempts {
+		if attempt.Scope != scope || attempt.Identifier != identifier {
+			kept = append(kept, attempt)
+		}
+	}
+	r.attempts = kept
+	return nil
+}
+
+func (r *fakeFailedAttemptRepository) DeleteOlderThan(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestNormalizeIPAddress(t *testing.T) {
+	tests := []struct {
+		ipAddress string
+		want      string
+	}{
+		{"203.0.113.7", "203.0.113.7"},
+		{"::ffff:203.0.113.7", "::ffff:203.0.113.7"},
+		{"2001:db8:1:2:aaaa:bbbb:cccc:dddd", "2001:db8:1:2::/64"},
+		{"2001:db8:1:2::1", "2001:db8:1:2::/64"},
+		{"not-an-ip", "not-an-ip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ipAddress, func(t *testing.T) {
+			if got := normalizeIPAddress(tt.ipAddress); got != tt.want {
+				t.Errorf("normalizeIPAddress(%q) = %q, want %q", tt.ipAddress, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThrottleCheck(t *testing.T) {
+	policy := ThrottlePolicy{Scope: "test", Window: time.Minute, MaxPerIP: 3, MaxPerIdentifier: 2}
+
+	// failure is one failed attempt from an IP address for an identifier
+	type failure struct{ ipAddress, identifier string }
+
+	tests := []struct {
+		name       string
+		failures   []failure
+		reset      string
+		ipAddress  string
+		identifier string
+		wantErr    error
+	}{
+		{"under both limits", []failure{{"203.0.113.7", "budi"}}, "", "203.0.113.7", "budi", nil},
+		{"identifier limit from several addresses", []failure{{"203.0.113.7", "budi"}, {"203.0.113.8", "budi"}}, "", "203.0.113.9", "budi", ErrTooManyAttempts},
+		{"identifier compared case and space insensitive", []failure{{"203.0.113.7", " Budi "}, {"203.0.113.8", "BUDI"}}, "", "203.0.113.9", "budi", ErrTooManyAttempts},
+		{"ip limit across identifiers", []failure{{"203.0.113.7", "a"}, {"203.0.113.7", "b"}, {"203.0.113.7", "c"}}, "", "203.0.113.7", "d", ErrTooManyAttempts},
+		{"other ip address", []failure{{"203.0.113.7", "a"}, {"203.0.113.7", "b"}, {"203.0.113.7", "c"}}, "", "203.0.113.8", "d", nil},
+		{"ipv6 addresses rotated within one /64", []failure{{"2001:db8::1", "a"}, {"2001:db8::2", "b"}, {"2001:db8::3", "c"}}, "", "2001:db8::4", "d", ErrTooManyAttempts},
+		{"other ipv6 /64", []failure{{"2001:db8::1", "a"}, {"2001:db8::2", "b"}, {"2001:db8::3", "c"}}, "", "2001:db8:0:1::1", "d", nil},
+		{"reset clears the identifier window", []failure{{"203.0.113.7", "budi"}, {"203.0.113.8", "budi"}}, "Budi", "203.0.113.9", "budi", nil},
+		{"reset keeps the ip window of other identifiers", []failure{{"203.0.113.7", "ani"}, {"203.0.113.7", "citra"}, {"203.0.113.7", "dedi"}, {"203.0.113.7", "budi"}}, "budi", "203.0.113.7", "budi", ErrTooManyAttempts},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewThrottleService(&fakeFailedAttemptRepository{})
+			for _, f := range tt.failures {
+				if err := service.RecordFailure(policy, f.ipAddress, f.identifier); err != nil {
+					t.Fatalf("RecordFailure() error = %v", err)
+				}
+			}
+			if tt.reset != "" {
+				if err := service.Reset(policy, tt.reset); err != nil {
+					t.Fatalf("Reset() error = %v", err)
+				}
+			}
+
+			if err := service.Check(policy, tt.ipAddress, tt.identifier); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Check() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
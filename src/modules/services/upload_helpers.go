@@ -0,0 +1,86 @@
+package services
+
+import (
+	"encoding/json"
+
+	"pintu-backend/src/dtos"
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/utils"
+
+	"gorm.io/datatypes"
+)
+
+// fileVariants converts the stored image variants to the map recorded in FileItem and FotoItem JSON
+func fileVariants(stored *utils.StoredUpload) map[string]models.FileVariant {
+	if len(stored.Variants) == 0 {
+		return nil
+	}
+	variants := make(map[string]models.FileVariant, len(stored.Variants))
+	for name, variant := range stored.Variants {
+		variants[name] = models.FileVariant{
+			URL:    variant.Key,
+			Width:  variant.Width,
+			Height: variant.Height,
+			Size:   variant.Size,
+		}
+	}
+	return variants
+}
+
+// fileVariantsJSON encodes the stored image variants for a jsonb column, nil without variants
+func fileVariantsJSON(stored *utils.StoredUpload) datatypes.JSON {
+	variants := fileVariants(stored)
+	if variants == nil {
+		return nil
+	}
+	encoded, _ := json.Marshal(variants)
+	return encoded
+}
+
+// parseFileVariants decodes a jsonb column of image variants, nil when empty or invalid
+func parseFileVariants(raw datatypes.JSON) map[string]models.FileVariant {
+	if len(raw) == 0 {
+		return nil
+	}
+	var variants map[string]models.FileVariant
+	if err := json.Unmarshal(raw, &variants); err != nil {
+		return nil
+	}
+	return variants
+}
+
+// fileVariantDTOs maps recorded image variants to response DTOs with URLs
+func fileVariantDTOs(storage utils.Storage, variants map[string]models.FileVariant) map[string]dtos.FileVariantDTO {
+	if len(variants) == 0 {
+		return nil
+	}
+	result := make(map[string]dtos.FileVariantDTO, len(variants))
+	for name, variant := range variants {
+		result[name] = dtos.FileVariantDTO{
+			URL:    utils.FileURL(storage, variant.URL),
+			Width:  variant.Width,
+			Height: variant.Height,
+			Size:   variant.Size,
+		}
+	}
+	return result
+}
+
+// deleteFileVariants deletes the resized variants of an image, best effort. The original (fileKey) is
+// deleted by the caller like any other file.
+func deleteFileVariants(storage utils.Storage, fileKey string, variants map[string]models.FileVariant) {
+	for _, variant := range variants {
+		if variant.URL != "" && variant.URL != fileKey {
+			_ = storage.DeleteFile(variant.URL)
+		}
+	}
+}
+
+// deleteFileKeys deletes stored files, best effort; empty keys are skipped
+func deleteFileKeys(storage utils.Storage, keys []string) {
+	for _, key := range keys {
+		if key != "" {
+			_ = storage.DeleteFile(key)
+		}
+	}
+}
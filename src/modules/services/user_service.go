@@ -5,6 +5,7 @@ import (
 
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
 )
 
 // UserService handles business logic for User
@@ -15,24 +16,33 @@ type UserService interface {
 	GetByUsername(username string) (*models.User, error)
 	GetAllWithFilter(params repositories.GetUsersParams) ([]models.User, int64, error)
 	Update(data *models.User, roleIDs []uint) error
+	UpdatePassword(data *models.User) error
 	Delete(id uint) error
+	Unlock(principalType string, id uint) error
 }
 
 type UserServiceImpl struct {
-	repository     repositories.UserRepository
-	roleRepository repositories.RoleRepository
+	repository      repositories.UserRepository
+	roleRepository  repositories.RoleRepository
+	tokenRepository repositories.AuthTokenRepository
+	loginRepository repositories.LoginRepository
+	throttleService ThrottleService
 }
 
-// NewUserService creates a new User service
-func NewUserService(repository repositories.UserRepository) UserService {
-	return &UserServiceImpl{repository: repository}
+// NewUserService creates a new User service. The token repository is required because password changes,
+// deactivation and deletion end the sessions of the user.
+func NewUserService(repository repositories.UserRepository, tokenRepo repositories.AuthTokenRepository) UserService {
+	return &UserServiceImpl{repository: repository, tokenRepository: tokenRepo}
 }
 
-// NewUserServiceWithRole creates a new User service with role repository
-func NewUserServiceWithRole(repository repositories.UserRepository, roleRepo repositories.RoleRepository) UserService {
+// NewUserServiceWithRole creates a new User service with role validation, session revocation and account unlocking
+func NewUserServiceWithRole(repository repositories.UserRepository, roleRepo repositories.RoleRepository, tokenRepo repositories.AuthTokenRepository, loginRepo repositories.LoginRepository, throttleService ThrottleService) UserService {
 	return &UserServiceImpl{
-		repository:     repository,
-		roleRepository: roleRepo,
+		repository:      repository,
+		roleRepository:  roleRepo,
+		tokenRepository: tokenRepo,
+		loginRepository: loginRepo,
+		throttleService: throttleService,
 	}
 }
 
@@ -95,11 +105,27 @@ func (s *UserServiceImpl) Update(data *models.User, roleIDs []uint) error {
 		}
 	}
 
+	// Compare against the stored row to detect deactivation and password changes
+	current, err := s.repository.GetByID(data.ID)
+	if err != nil || current == nil {
+		return errors.New("user tidak ditemukan atau sudah dihapus")
+	}
+	revokeSessions := current.Password != data.Password || (current.Status != data.Status && data.Status != "active")
+
 	// Update user
 	if err := s.repository.Update(data); err != nil {
 		return err
 	}
 
+	if revokeSessions {
+		if err := s.revokeSessions(data.ID); err != nil {
+			return err
+		}
+	}
+
+	// Cached permission sets are stale once the roles change
+	defer utils.InvalidatePermissionCache()
+
 	// Update roles
 	if len(roleIDs) > 0 {
 		if err := s.repository.AssignRoles(data.ID, roleIDs); err != nil {
@@ -115,6 +141,15 @@ func (s *UserServiceImpl) Update(data *models.User, roleIDs []uint) error {
 	return nil
 }
 
+// UpdatePassword updates the User password, keeps its roles and ends all of its sessions
+func (s *UserServiceImpl) UpdatePassword(data *models.User) error {
+	if err := s.repository.Update(data); err != nil {
+		return err
+	}
+
+	return s.revokeSessions(data.ID)
+}
+
 // Delete deletes User by ID
 func (s *UserServiceImpl) Delete(id uint) error {
 	// Validate user exists before delete
@@ -123,5 +158,61 @@ func (s *UserServiceImpl) Delete(id uint) error {
 		return errors.New("user tidak ditemukan atau sudah dihapus")
 	}
 
-	return s.repository.Delete(id)
+	if err := s.repository.Delete(id); err != nil {
+		return err
+	}
+
+	return s.revokeSessions(id)
+}
+
+// Unlock clears the login lockout of a user, pegawai or siswa account and its failed attempt window
+func (s *UserServiceImpl) Unlock(principalType string, id uint) error {
+	if s.loginRepository == nil || s.throttleService == nil {
+		return errors.New("unlock akun tidak tersedia")
+	}
+
+	policy := LoginThrottlePolicy
+	var username string
+	switch principalType {
+	case utils.PrincipalUser:
+		user, err := s.loginRepository.GetByID(id)
+		if err != nil {
+			return errors.New("user tidak ditemukan atau sudah dihapus")
+		}
+		username = user.Username
+	case utils.PrincipalPegawai:
+		kepegawaian, err := s.loginRepository.GetKepegawaianByID(id)
+		if err != nil {
+			return errors.New("pegawai tidak ditemukan atau sudah dihapus")
+		}
+		username = kepegawaian.Username
+	case utils.PrincipalSiswa:
+		pesertaDidik, err := s.loginRepository.GetPesertaDidikByID(id)
+		if err != nil {
+			return errors.New("peserta didik tidak ditemukan atau sudah dihapus")
+		}
+		username = pesertaDidik.Username
+		policy = LoginSiswaThrottlePolicy
+	default:
+		return errors.New("principal_type tidak valid")
+	}
+
+	if err := s.loginRepository.Unlock(principalType, id); err != nil {
+		return errors.New("gagal membuka kunci akun")
+	}
+	if err := s.throttleService.Reset(policy, username); err != nil {
+		return errors.New("gagal membuka kunci akun")
+	}
+	return nil
+}
+
+// revokeSessions revokes every refresh and access token issued to the user
+func (s *UserServiceImpl) revokeSessions(id uint) error {
+	if s.tokenRepository == nil {
+		return errors.New("gagal mencabut sesi login user: token repository tidak tersedia")
+	}
+	if err := s.tokenRepository.RevokeAllForPrincipal(utils.PrincipalUser, id); err != nil {
+		return errors.New("gagal mencabut sesi login user")
+	}
+	return nil
 }
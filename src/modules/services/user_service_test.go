@@ -0,0 +1,51 @@
+package services
+
+import (
+	"pintu-backend/src/modules/models"
+	"pintu-backend/src/modules/repositories"
+	"testing"
+)
+
+// fakeUserRepository holds one user and only implements the writes that end its sessions
+type fakeUserRepository struct {
+	repositories.UserRepository
+	user *models.User
+}
+
+func (r *fakeUserRepository) GetByID(id uint) (*models.User, error) {
+	return r.user, nil
+}
+
+func (r *fakeUserRepository) Update(data *models.User) error {
+	return nil
+}
+
+func (r *fakeUserRepository) Delete(id uint) error {
+	return nil
+}
+
+func TestUserServiceRevokesSessions(t *testing.T) {
+	tokens := &fakeAuthTokenRepository{}
+	service := NewUserService(&fakeUserRepository{user: &models.User{ID: 7}}, tokens)
+
+	if err := service.UpdatePassword(&models.User{ID: 7, Password: "baru"}); err != nil {
+		t.Fatalf("UpdatePassword() error = %v", err)
+	}
+	if err := service.Delete(7); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if len(tokens.revoked) != 2 || tokens.revoked[0] != "user:7" || tokens.revoked[1] != "user:7" {
+		t.Errorf("revoked = %v, want the sessions of user 7 twice", tokens.revoked)
+	}
+}
+
+func TestUserServiceWithoutTokenRepository(t *testing.T) {
+	service := NewUserService(&fakeUserRepository{user: &models.User{ID: 7}}, nil)
+
+	if err := service.UpdatePassword(&models.User{ID: 7, Password: "baru"}); err == nil {
+		t.Error("UpdatePassword() error = nil, want an error when sessions cannot be revoked")
+	}
+	if err := service.Delete(7); err == nil {
+		t.Error("Delete() error = nil, want an error when sessions cannot be revoked")
+	}
+}
@@ -4,14 +4,15 @@ import (
 	"pintu-backend/src/dtos"
 	"pintu-backend/src/modules/models"
 	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/utils"
 )
 
 // VisiMisiService handles business logic for VisiMisi
 type VisiMisiService interface {
-	Create(req *dtos.VisiMisiCreateRequest, userID uint) (*dtos.VisiMisiResponse, error)
+	Create(req *dtos.VisiMisiCreateRequest, actor utils.Principal) (*dtos.VisiMisiResponse, error)
 	GetByID(id uint) (*dtos.VisiMisiResponse, error)
 	GetAll(limit int, offset int) (*dtos.VisiMisiListResponse, error)
-	Update(req *dtos.VisiMisiUpdateRequest, userID uint) (*dtos.VisiMisiResponse, error)
+	Update(req *dtos.VisiMisiUpdateRequest, actor utils.Principal) (*dtos.VisiMisiResponse, error)
 	Delete(id uint) error
 	GetPublic() (*dtos.VisiMisiPublicResponse, error)
 }
@@ -26,11 +27,12 @@ func NewVisiMisiService(repository repositories.VisiMisiRepository) VisiMisiServ
 }
 
 // Create creates a new VisiMisi
-func (s *VisiMisiServiceImpl) Create(req *dtos.VisiMisiCreateRequest, userID uint) (*dtos.VisiMisiResponse, error) {
+func (s *VisiMisiServiceImpl) Create(req *dtos.VisiMisiCreateRequest, actor utils.Principal) (*dtos.VisiMisiResponse, error) {
 	data := &models.VisiMisi{
-		Visi:        req.Visi,
-		Misi:        req.Misi,
-		CreatedByID: &userID,
+		Visi:          req.Visi,
+		Misi:          req.Misi,
+		CreatedByID:   &actor.ID,
+		CreatedByType: actor.TypePtr(),
 	}
 
 	if err := s.repository.Create(data); err != nil {
@@ -82,7 +84,7 @@ func (s *VisiMisiServiceImpl) GetAll(limit int, offset int) (*dtos.VisiMisiListR
 }
 
 // Update updates VisiMisi
-func (s *VisiMisiServiceImpl) Update(req *dtos.VisiMisiUpdateRequest, userID uint) (*dtos.VisiMisiResponse, error) {
+func (s *VisiMisiServiceImpl) Update(req *dtos.VisiMisiUpdateRequest, actor utils.Principal) (*dtos.VisiMisiResponse, error) {
 	// Get existing data
 	existing, err := s.repository.GetByID(req.ID)
 	if err != nil {
@@ -97,7 +99,8 @@ func (s *VisiMisiServiceImpl) Update(req *dtos.VisiMisiUpdateRequest, userID uin
 		existing.Misi = *req.Misi
 	}
 
-	existing.UpdatedByID = &userID
+	existing.UpdatedByID = &actor.ID
+	existing.UpdatedByType = actor.TypePtr()
 
 	if err := s.repository.Update(existing); err != nil {
 		return nil, err
@@ -127,12 +130,14 @@ func (s *VisiMisiServiceImpl) GetPublic() (*dtos.VisiMisiPublicResponse, error)
 // mapToResponse maps model to DTO response
 func (s *VisiMisiServiceImpl) mapToResponse(data *models.VisiMisi) *dtos.VisiMisiResponse {
 	return &dtos.VisiMisiResponse{
-		ID:          data.ID,
-		Visi:        data.Visi,
-		Misi:        data.Misi,
-		CreatedAt:   data.CreatedAt,
-		UpdatedAt:   data.UpdatedAt,
-		CreatedByID: data.CreatedByID,
-		UpdatedByID: data.UpdatedByID,
+		ID:            data.ID,
+		Visi:          data.Visi,
+		Misi:          data.Misi,
+		CreatedAt:     data.CreatedAt,
+		UpdatedAt:     data.UpdatedAt,
+		CreatedByID:   data.CreatedByID,
+		CreatedByType: data.CreatedByType,
+		UpdatedByID:   data.UpdatedByID,
+		UpdatedByType: data.UpdatedByType,
 	}
 }
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"pintu-backend/src/middleware"
+	"pintu-backend/src/modules/controllers"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/modules/services"
+	"pintu-backend/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterAbsensiLiveRoutes registers the real-time attendance feed routes
+func RegisterAbsensiLiveRoutes(router *gin.Engine, db *gorm.DB) {
+	// Initialize repository, service, and controller; the hub is shared with the scan routes
+	repository := repositories.NewAbsensiLiveRepository(db)
+	service := services.NewAbsensiLiveService(repository, utils.DefaultAbsensiHub())
+	controller := controllers.NewAbsensiLiveController(service)
+
+	// Protected routes (auth required)
+	protected := router.Group("/api/v1/absensi-siswa")
+	protected.Use(middleware.AuthMiddleware(db))
+	{
+		protected.POST("/get-ringkasan-live-absensi", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetRingkasan)
+
+		// Server-Sent Events, POST so the bearer token can be sent from fetch()
+		protected.POST("/live-feed-absensi", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.StreamFeed)
+	}
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"pintu-backend/src/middleware"
+	"pintu-backend/src/modules/controllers"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/modules/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterAbsensiPegawaiRoutes registers all absensi pegawai routes
+func RegisterAbsensiPegawaiRoutes(router *gin.Engine, db *gorm.DB) {
+	// Initialize repository, service, and controller
+	repository := repositories.NewAbsensiPegawaiRepository(db)
+	periodeService := services.NewPeriodeAkademikService(repositories.NewTahunPelajaranRepository(db))
+	kalenderService := services.NewKalenderAkademikService(repositories.NewKalenderAkademikRepository(db), repositories.NewKonfigurasiAbsensiRepository(db), periodeService)
+	service := services.NewAbsensiPegawaiService(repository, kalenderService)
+	controller := controllers.NewAbsensiPegawaiController(service)
+
+	// Public routes (no user auth, registered scanner devices only)
+	public := router.Group("/api/v1/public")
+	{
+		public.POST("/absensi-pegawai", middleware.ScannerDeviceAuth(db), controller.ScanAbsensiPegawai)
+	}
+
+	// Protected routes (auth required)
+	protected := router.Group("/api/v1/absensi-pegawai")
+	protected.Use(middleware.AuthMiddleware(db))
+	{
+		protected.POST("/setting-konfigurasi-absensi-pegawai", middleware.RequirePermission(db, "UPDATE_KEPEGAWAIAN"), controller.UpsertKonfigurasi)
+		protected.POST("/get-konfigurasi-absensi-pegawai", middleware.RequirePermission(db, "READ_KEPEGAWAIAN"), controller.GetKonfigurasi)
+		protected.POST("/get-rekap-absensi-pegawai", middleware.RequirePermission(db, "READ_KEPEGAWAIAN"), controller.GetRekapBulanan)
+		protected.POST("/export-excel-absensi-pegawai", middleware.RequirePermission(db, "READ_KEPEGAWAIAN"), controller.ExportExcel)
+		protected.POST("/export-pdf-absensi-pegawai", middleware.RequirePermission(db, "READ_KEPEGAWAIAN"), controller.ExportPDF)
+	}
+}
@@ -14,40 +14,49 @@ import (
 func RegisterAbsensiRoutes(router *gin.Engine, db *gorm.DB) {
 	// Initialize repository, service, and controller
 	repository := repositories.NewAbsensiRepository(db)
-	service := services.NewAbsensiService(repository, db)
+	periodeService := services.NewPeriodeAkademikService(repositories.NewTahunPelajaranRepository(db))
+	kalenderService := services.NewKalenderAkademikService(repositories.NewKalenderAkademikRepository(db), repositories.NewKonfigurasiAbsensiRepository(db), periodeService)
+	service := services.NewAbsensiService(repository, repositories.NewRiwayatRekapitulasiAbsensiRepository(db), kalenderService, periodeService, db)
 	controller := controllers.NewAbsensiController(service)
 
 	// Protected routes (require authentication)
 	api := router.Group("/api/v1/absensi-siswa")
-	api.Use(middleware.AuthMiddleware())
+	api.Use(middleware.AuthMiddleware(db))
 	{
 		// Create absensi manual (bulk input with file upload)
-		api.POST("/create-absensi-manual", controller.CreateAbsensiManual)
+		api.POST("/create-absensi-manual", middleware.RequirePermission(db, "CREATE_PESERTA_DIDIK"), controller.CreateAbsensiManual)
 		
 		// Create absensi manual by ID (single student with auto semester detection)
-		api.POST("/create-absensi-manual-by-id", controller.CreateAbsensiManualByID)
+		api.POST("/create-absensi-manual-by-id", middleware.RequirePermission(db, "CREATE_PESERTA_DIDIK"), controller.CreateAbsensiManualByID)
 		
 		// Synchronize absensi from scanner to rekapitulasi
-		api.POST("/synchronize-absensi-siswa", controller.SynchronizeAbsensi)
+		api.POST("/synchronize-absensi-siswa", middleware.RequirePermission(db, "CREATE_PESERTA_DIDIK"), controller.SynchronizeAbsensi)
+		
+		// Import guru kelas rekap absensi from Excel
+		api.POST("/download-template-rekap-absensi", middleware.RequirePermission(db, "CREATE_PESERTA_DIDIK"), controller.DownloadTemplateImport)
+		api.POST("/import-excel-rekap-absensi", middleware.RequirePermission(db, "CREATE_PESERTA_DIDIK"), controller.ImportExcel)
 		
 		// Get rekap absensi
-		api.POST("/get-rekap-absensi", controller.GetRekapAbsensi)
+		api.POST("/get-rekap-absensi", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetRekapAbsensi)
 		
 		// Update rekap absensi
-		api.POST("/update-rekap-absensi", controller.UpdateRekapAbsensi)
+		api.POST("/update-rekap-absensi", middleware.RequirePermission(db, "UPDATE_PESERTA_DIDIK"), controller.UpdateRekapAbsensi)
+		
+		// Change history of a student-day (who changed what and when)
+		api.POST("/get-riwayat-rekap-absensi", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetRiwayatRekapAbsensi)
 		
 		// Export absensi to Excel
-		api.POST("/export-excel-absensi-siswa", controller.ExportAbsensiExcel)
+		api.POST("/export-excel-absensi-siswa", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.ExportAbsensiExcel)
 		
 		// Export absensi to PDF
-		api.POST("/export-pdf-absensi-siswa", controller.ExportAbsensiPDF)
+		api.POST("/export-pdf-absensi-siswa", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.ExportAbsensiPDF)
 		
 		// Dashboard monitoring
-		api.POST("/dashboard-summary", controller.GetDashboardSummary)
-		api.POST("/grafik-kehadiran", controller.GetGrafikKehadiran)
-		api.POST("/statistik-per-hari", controller.GetStatistikPerHari)
-		api.POST("/perbandingan-rombel", controller.GetPerbandinganRombel)
-		api.POST("/siswa-terendah", controller.GetSiswaTerendah)
-		api.POST("/dashboard-siswa", controller.GetDashboardSiswa)
+		api.POST("/dashboard-summary", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetDashboardSummary)
+		api.POST("/grafik-kehadiran", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetGrafikKehadiran)
+		api.POST("/statistik-per-hari", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetStatistikPerHari)
+		api.POST("/perbandingan-rombel", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetPerbandinganRombel)
+		api.POST("/siswa-terendah", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetSiswaTerendah)
+		api.POST("/dashboard-siswa", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetDashboardSiswa)
 	}
 }
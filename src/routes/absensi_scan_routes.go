@@ -1,9 +1,11 @@
 package routes
 
 import (
+	"pintu-backend/src/middleware"
 	"pintu-backend/src/modules/controllers"
 	"pintu-backend/src/modules/repositories"
 	"pintu-backend/src/modules/services"
+	"pintu-backend/src/utils"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -13,12 +15,20 @@ import (
 func RegisterAbsensiScanRoutes(router *gin.Engine, db *gorm.DB) {
 	// Initialize repository, service, and controller
 	repository := repositories.NewAbsensiScanRepository(db)
-	service := services.NewAbsensiScanService(repository)
+	periodeService := services.NewPeriodeAkademikService(repositories.NewTahunPelajaranRepository(db))
+	kalenderService := services.NewKalenderAkademikService(repositories.NewKalenderAkademikRepository(db), repositories.NewKonfigurasiAbsensiRepository(db), periodeService)
+	notifikasiService := services.NewNotifikasiService(repositories.NewNotifikasiRepository(db), utils.NewNotifikasiChannels())
+	jadwalService := services.NewJadwalAbsensiService(repositories.NewJadwalAbsensiRepository(db), repositories.NewPesertaDidikRepository(db), kalenderService)
+	liveService := services.NewAbsensiLiveService(repositories.NewAbsensiLiveRepository(db), utils.DefaultAbsensiHub())
+	service := services.NewAbsensiScanService(repository, kalenderService, notifikasiService, jadwalService, liveService)
 	controller := controllers.NewAbsensiScanController(service)
 
-	// Public routes (no auth required)
+	// Public routes (no user auth, registered scanner devices only)
 	public := router.Group("/api/v1/public")
 	{
-		public.POST("/absensi-siswa", controller.ScanAbsensi)
+		public.POST("/absensi-siswa", middleware.ScannerDeviceAuth(db), controller.ScanAbsensi)
+
+		// Offline batch upload, checked against each scan's own time instead of the upload time
+		public.POST("/absensi-siswa-batch", middleware.ScannerDeviceBatchAuth(db), controller.ScanAbsensiBatch)
 	}
 }
@@ -13,32 +13,32 @@ import (
 
 // RegisterActivityGalleryRoutes registers all activity gallery routes
 func RegisterActivityGalleryRoutes(router *gin.Engine, db *gorm.DB) {
-	// Initialize R2 storage
-	r2Storage := utils.NewR2Storage()
+	// Initialize storage
+	storage := utils.NewStorage()
 
 	// Initialize repository, service, and controller
 	galleryRepo := repositories.NewActivityGalleryRepository(db)
-	galleryService := services.NewActivityGalleryService(galleryRepo, r2Storage)
+	galleryService := services.NewActivityGalleryService(galleryRepo, storage)
 	galleryController := controllers.NewActivityGalleryController(galleryService)
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/activity-galleries")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
 		// Create activity gallery with fotos upload
-		protected.POST("/create-gallery", galleryController.Create)
+		protected.POST("/create-gallery", middleware.RequirePermission(db, "CREATE_MEDIA_PUBLIKASI"), galleryController.Create)
 
 		// Get all activity galleries
-		protected.POST("/get-galleries", galleryController.GetAll)
+		protected.POST("/get-galleries", middleware.RequirePermission(db, "READ_MEDIA_PUBLIKASI"), galleryController.GetAll)
 
 		// Get activity gallery by ID
-		protected.POST("/get-gallery-by-id", galleryController.GetByID)
+		protected.POST("/get-gallery-by-id", middleware.RequirePermission(db, "READ_MEDIA_PUBLIKASI"), galleryController.GetByID)
 
 		// Update activity gallery (handle update fields, add fotos, delete fotos)
-		protected.POST("/update-gallery", galleryController.Update)
+		protected.POST("/update-gallery", middleware.RequirePermission(db, "UPDATE_MEDIA_PUBLIKASI"), galleryController.Update)
 
 		// Delete activity gallery
-		protected.POST("/delete-gallery", galleryController.Delete)
+		protected.POST("/delete-gallery", middleware.RequirePermission(db, "DELETE_MEDIA_PUBLIKASI"), galleryController.Delete)
 	}
 
 	// Public routes (no auth required)
@@ -13,32 +13,32 @@ import (
 
 // RegisterAnnouncementRoutes registers all announcement routes
 func RegisterAnnouncementRoutes(router *gin.Engine, db *gorm.DB) {
-	// Initialize R2 storage
-	r2Storage := utils.NewR2Storage()
+	// Initialize storage
+	storage := utils.NewStorage()
 
 	// Initialize repository, service, and controller
 	announcementRepo := repositories.NewAnnouncementRepository(db)
-	announcementService := services.NewAnnouncementService(announcementRepo, r2Storage)
+	announcementService := services.NewAnnouncementService(announcementRepo, storage)
 	announcementController := controllers.NewAnnouncementController(announcementService)
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/announcements")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
 		// Create announcement with gambar and files upload
-		protected.POST("/create-announcement", announcementController.Create)
+		protected.POST("/create-announcement", middleware.RequirePermission(db, "CREATE_MEDIA_PUBLIKASI"), announcementController.Create)
 
 		// Get all announcements
-		protected.POST("/get-announcements", announcementController.GetAll)
+		protected.POST("/get-announcements", middleware.RequirePermission(db, "READ_MEDIA_PUBLIKASI"), announcementController.GetAll)
 
 		// Get announcement by ID
-		protected.POST("/get-announcement-by-id", announcementController.GetByID)
+		protected.POST("/get-announcement-by-id", middleware.RequirePermission(db, "READ_MEDIA_PUBLIKASI"), announcementController.GetByID)
 
 		// Update announcement (handle update fields, add files, delete files)
-		protected.POST("/update-announcement", announcementController.Update)
+		protected.POST("/update-announcement", middleware.RequirePermission(db, "UPDATE_MEDIA_PUBLIKASI"), announcementController.Update)
 
 		// Delete announcement
-		protected.POST("/delete-announcement", announcementController.Delete)
+		protected.POST("/delete-announcement", middleware.RequirePermission(db, "DELETE_MEDIA_PUBLIKASI"), announcementController.Delete)
 	}
 
 	// Public routes (no auth required)
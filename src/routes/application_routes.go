@@ -19,22 +19,22 @@ func RegisterApplicationRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/application")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
 		// Create application
-		protected.POST("/create-application", applicationController.Create)
+		protected.POST("/create-application", middleware.RequirePermission(db, "CREATE_INFORMASI_SEKOLAH"), applicationController.Create)
 
 		// Get all applications
-		protected.POST("/get-application", applicationController.GetAll)
+		protected.POST("/get-application", middleware.RequirePermission(db, "READ_INFORMASI_SEKOLAH"), applicationController.GetAll)
 
 		// Get application by ID
-		protected.POST("/get-application-by-id", applicationController.GetByID)
+		protected.POST("/get-application-by-id", middleware.RequirePermission(db, "READ_INFORMASI_SEKOLAH"), applicationController.GetByID)
 
 		// Update application
-		protected.POST("/update-application", applicationController.Update)
+		protected.POST("/update-application", middleware.RequirePermission(db, "UPDATE_INFORMASI_SEKOLAH"), applicationController.Update)
 
 		// Delete application
-		protected.POST("/delete-application", applicationController.Delete)
+		protected.POST("/delete-application", middleware.RequirePermission(db, "DELETE_INFORMASI_SEKOLAH"), applicationController.Delete)
 	}
 
 	// Public routes (no auth required)
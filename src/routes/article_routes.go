@@ -13,32 +13,32 @@ import (
 
 // RegisterArticleRoutes registers all article routes
 func RegisterArticleRoutes(router *gin.Engine, db *gorm.DB) {
-	// Initialize R2 storage
-	r2Storage := utils.NewR2Storage()
+	// Initialize storage
+	storage := utils.NewStorage()
 
 	// Initialize repository, service, and controller
 	articleRepo := repositories.NewArticleRepository(db)
-	articleService := services.NewArticleService(articleRepo, r2Storage)
+	articleService := services.NewArticleService(articleRepo, storage)
 	articleController := controllers.NewArticleController(articleService)
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/articles")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
 		// Create article with gambar and files upload
-		protected.POST("/create-article", articleController.Create)
+		protected.POST("/create-article", middleware.RequirePermission(db, "CREATE_MEDIA_PUBLIKASI"), articleController.Create)
 
 		// Get all articles
-		protected.POST("/get-articles", articleController.GetAll)
+		protected.POST("/get-articles", middleware.RequirePermission(db, "READ_MEDIA_PUBLIKASI"), articleController.GetAll)
 
 		// Get article by ID
-		protected.POST("/get-article-by-id", articleController.GetByID)
+		protected.POST("/get-article-by-id", middleware.RequirePermission(db, "READ_MEDIA_PUBLIKASI"), articleController.GetByID)
 
 		// Update article (handle update fields, add files, delete files)
-		protected.POST("/update-article", articleController.Update)
+		protected.POST("/update-article", middleware.RequirePermission(db, "UPDATE_MEDIA_PUBLIKASI"), articleController.Update)
 
 		// Delete article
-		protected.POST("/delete-article", articleController.Delete)
+		protected.POST("/delete-article", middleware.RequirePermission(db, "DELETE_MEDIA_PUBLIKASI"), articleController.Delete)
 	}
 
 	// Public routes (no auth required)
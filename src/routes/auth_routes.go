@@ -14,19 +14,25 @@ import (
 func RegisterAuthRoutes(router *gin.Engine, db *gorm.DB) {
 	// Initialize repository, service, and controller
 	loginRepo := repositories.NewLoginRepository(db)
-	loginService := services.NewLoginService(loginRepo)
+	authTokenRepo := repositories.NewAuthTokenRepository(db)
+	systemRepo := repositories.NewSystemRepository(db)
+	throttleService := services.NewThrottleService(repositories.NewFailedAttemptRepository(db))
+	loginService := services.NewLoginService(loginRepo, authTokenRepo, systemRepo, throttleService)
 	loginController := controllers.NewLoginController(loginService)
 
 	// Public routes (no auth required)
 	public := router.Group("/api/v1/auth")
 	{
 		public.POST("/login", loginController.Login) // Login endpoint
+		public.POST("/login-siswa", loginController.LoginSiswa) // Peserta didik login endpoint
+		public.POST("/refresh", loginController.Refresh) // Rotate refresh token
 	}
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/auth")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
+		protected.POST("/switch-role", loginController.SwitchRole) // Re-issue token for another role
 		protected.POST("/logout", loginController.Logout) // Logout endpoint
 	}
 }
@@ -19,21 +19,21 @@ func RegisterBidangStudiRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/bidang-studi")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
 		// Create bidang studi
-		protected.POST("/create-bidang-studi", bidangStudiController.Create)
+		protected.POST("/create-bidang-studi", middleware.RequirePermission(db, "CREATE_MASTER_DATA"), bidangStudiController.Create)
 
 		// Get all bidang studi
-		protected.POST("/get-bidang-studi", bidangStudiController.GetAll)
+		protected.POST("/get-bidang-studi", middleware.RequirePermission(db, "READ_MASTER_DATA"), bidangStudiController.GetAll)
 
 		// Get bidang studi by ID
-		protected.POST("/get-bidang-studi-by-id", bidangStudiController.GetByID)
+		protected.POST("/get-bidang-studi-by-id", middleware.RequirePermission(db, "READ_MASTER_DATA"), bidangStudiController.GetByID)
 
 		// Update bidang studi
-		protected.POST("/update-bidang-studi", bidangStudiController.Update)
+		protected.POST("/update-bidang-studi", middleware.RequirePermission(db, "UPDATE_MASTER_DATA"), bidangStudiController.Update)
 
 		// Delete bidang studi
-		protected.POST("/delete-bidang-studi", bidangStudiController.Delete)
+		protected.POST("/delete-bidang-studi", middleware.RequirePermission(db, "DELETE_MASTER_DATA"), bidangStudiController.Delete)
 	}
 }
@@ -19,22 +19,22 @@ func RegisterContactRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/contacts")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
 		// Create contact
-		protected.POST("/create-contact", contactController.Create)
+		protected.POST("/create-contact", middleware.RequirePermission(db, "CREATE_INFORMASI_SEKOLAH"), contactController.Create)
 
 		// Get all contacts
-		protected.POST("/get-contacts", contactController.GetAll)
+		protected.POST("/get-contacts", middleware.RequirePermission(db, "READ_INFORMASI_SEKOLAH"), contactController.GetAll)
 
 		// Get contact by ID
-		protected.POST("/get-contact-by-id", contactController.GetByID)
+		protected.POST("/get-contact-by-id", middleware.RequirePermission(db, "READ_INFORMASI_SEKOLAH"), contactController.GetByID)
 
 		// Update contact
-		protected.POST("/update-contact", contactController.Update)
+		protected.POST("/update-contact", middleware.RequirePermission(db, "UPDATE_INFORMASI_SEKOLAH"), contactController.Update)
 
 		// Delete contact
-		protected.POST("/delete-contact", contactController.Delete)
+		protected.POST("/delete-contact", middleware.RequirePermission(db, "DELETE_INFORMASI_SEKOLAH"), contactController.Delete)
 	}
 
 	// Public routes (no auth required)
@@ -27,21 +27,21 @@ func RegisterEkstrakurikulerRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/ekstrakurikuler")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
 		// Create ekstrakurikuler
-		protected.POST("/create-ekstrakurikuler", ekstrakurikulerController.Create)
+		protected.POST("/create-ekstrakurikuler", middleware.RequirePermission(db, "CREATE_MASTER_DATA"), ekstrakurikulerController.Create)
 
 		// Get all ekstrakurikuler
-		protected.POST("/get-ekstrakurikuler", ekstrakurikulerController.GetAll)
+		protected.POST("/get-ekstrakurikuler", middleware.RequirePermission(db, "READ_MASTER_DATA"), ekstrakurikulerController.GetAll)
 
 		// Get ekstrakurikuler by ID
-		protected.POST("/get-ekstrakurikuler-by-id", ekstrakurikulerController.GetByID)
+		protected.POST("/get-ekstrakurikuler-by-id", middleware.RequirePermission(db, "READ_MASTER_DATA"), ekstrakurikulerController.GetByID)
 
 		// Update ekstrakurikuler
-		protected.POST("/update-ekstrakurikuler", ekstrakurikulerController.Update)
+		protected.POST("/update-ekstrakurikuler", middleware.RequirePermission(db, "UPDATE_MASTER_DATA"), ekstrakurikulerController.Update)
 
 		// Delete ekstrakurikuler
-		protected.POST("/delete-ekstrakurikuler", ekstrakurikulerController.Delete)
+		protected.POST("/delete-ekstrakurikuler", middleware.RequirePermission(db, "DELETE_MASTER_DATA"), ekstrakurikulerController.Delete)
 	}
 }
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"pintu-backend/src/middleware"
+	"pintu-backend/src/modules/controllers"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/modules/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterJadwalAbsensiRoutes registers all jadwal absensi (schedule profile and assignment) routes
+func RegisterJadwalAbsensiRoutes(router *gin.Engine, db *gorm.DB) {
+	// Initialize repository, service, and controller
+	repository := repositories.NewJadwalAbsensiRepository(db)
+	periodeService := services.NewPeriodeAkademikService(repositories.NewTahunPelajaranRepository(db))
+	kalenderService := services.NewKalenderAkademikService(repositories.NewKalenderAkademikRepository(db), repositories.NewKonfigurasiAbsensiRepository(db), periodeService)
+	service := services.NewJadwalAbsensiService(repository, repositories.NewPesertaDidikRepository(db), kalenderService)
+	controller := controllers.NewJadwalAbsensiController(service)
+
+	// Protected routes (auth required)
+	protected := router.Group("/api/v1/absensi-siswa")
+	protected.Use(middleware.AuthMiddleware(db))
+	{
+		// Schedule profiles
+		protected.POST("/create-profil-jadwal-absensi", middleware.RequirePermission(db, "UPDATE_PESERTA_DIDIK"), controller.CreateProfil)
+		protected.POST("/get-profil-jadwal-absensi", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetAllProfil)
+		protected.POST("/get-profil-jadwal-absensi-by-id", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetProfilByID)
+		protected.POST("/update-profil-jadwal-absensi", middleware.RequirePermission(db, "UPDATE_PESERTA_DIDIK"), controller.UpdateProfil)
+		protected.POST("/delete-profil-jadwal-absensi", middleware.RequirePermission(db, "UPDATE_PESERTA_DIDIK"), controller.DeleteProfil)
+
+		// Assignments to all students, a kelas or a rombel, optionally date-ranged
+		protected.POST("/create-penugasan-jadwal-absensi", middleware.RequirePermission(db, "UPDATE_PESERTA_DIDIK"), controller.CreatePenugasan)
+		protected.POST("/get-penugasan-jadwal-absensi", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetAllPenugasan)
+		protected.POST("/update-penugasan-jadwal-absensi", middleware.RequirePermission(db, "UPDATE_PESERTA_DIDIK"), controller.UpdatePenugasan)
+		protected.POST("/delete-penugasan-jadwal-absensi", middleware.RequirePermission(db, "UPDATE_PESERTA_DIDIK"), controller.DeletePenugasan)
+
+		// Effective schedule of a student on a date
+		protected.POST("/preview-jadwal-absensi", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.PreviewJadwal)
+	}
+}
@@ -13,12 +13,12 @@ import (
 
 // RegisterJumbotronRoutes registers all jumbotron routes
 func RegisterJumbotronRoutes(router *gin.Engine, db *gorm.DB) {
-	// Initialize R2 storage
-	r2Storage := utils.NewR2Storage()
+	// Initialize storage
+	storage := utils.NewStorage()
 
 	// Initialize repository, service, and controller
 	jumbotronRepo := repositories.NewJumbotronRepository(db)
-	jumbotronService := services.NewJumbotronService(jumbotronRepo, r2Storage)
+	jumbotronService := services.NewJumbotronService(jumbotronRepo, storage)
 	jumbotronController := controllers.NewJumbotronController(jumbotronService)
 
 	// Public routes (no auth required)
@@ -30,21 +30,21 @@ func RegisterJumbotronRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/jumbotron")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
 		// Create jumbotron with file upload
-		protected.POST("/create-jumbotron", jumbotronController.Create)
+		protected.POST("/create-jumbotron", middleware.RequirePermission(db, "CREATE_INFORMASI_SEKOLAH"), jumbotronController.Create)
 
 		// Get all jumbotron
-		protected.POST("/get-jumbotron", jumbotronController.GetAll)
+		protected.POST("/get-jumbotron", middleware.RequirePermission(db, "READ_INFORMASI_SEKOLAH"), jumbotronController.GetAll)
 
 		// Get jumbotron by ID
-		protected.POST("/get-jumbotron-by-id", jumbotronController.GetByID)
+		protected.POST("/get-jumbotron-by-id", middleware.RequirePermission(db, "READ_INFORMASI_SEKOLAH"), jumbotronController.GetByID)
 
 		// Update jumbotron
-		protected.POST("/update-jumbotron", jumbotronController.Update)
+		protected.POST("/update-jumbotron", middleware.RequirePermission(db, "UPDATE_INFORMASI_SEKOLAH"), jumbotronController.Update)
 
 		// Delete jumbotron
-		protected.POST("/delete-jumbotron", jumbotronController.Delete)
+		protected.POST("/delete-jumbotron", middleware.RequirePermission(db, "DELETE_INFORMASI_SEKOLAH"), jumbotronController.Delete)
 	}
 }
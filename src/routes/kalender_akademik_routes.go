@@ -0,0 +1,53 @@
+package routes
+
+import (
+	"pintu-backend/src/middleware"
+	"pintu-backend/src/modules/controllers"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/modules/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterKalenderAkademikRoutes registers all kalender akademik routes
+func RegisterKalenderAkademikRoutes(router *gin.Engine, db *gorm.DB) {
+	// Initialize repositories, service, and controller
+	kalenderAkademikRepo := repositories.NewKalenderAkademikRepository(db)
+	konfigurasiAbsensiRepo := repositories.NewKonfigurasiAbsensiRepository(db)
+	periodeService := services.NewPeriodeAkademikService(repositories.NewTahunPelajaranRepository(db))
+	kalenderAkademikService := services.NewKalenderAkademikService(kalenderAkademikRepo, konfigurasiAbsensiRepo, periodeService)
+	kalenderAkademikController := controllers.NewKalenderAkademikController(kalenderAkademikService)
+
+	// Protected routes (auth required)
+	protected := router.Group("/api/v1/kalender-akademik")
+	protected.Use(middleware.AuthMiddleware(db))
+	{
+		// Create kalender akademik entry
+		protected.POST("/create-kalender-akademik", middleware.RequirePermission(db, "CREATE_MASTER_DATA"), kalenderAkademikController.Create)
+
+		// Get all kalender akademik entries
+		protected.POST("/get-kalender-akademik", middleware.RequirePermission(db, "READ_MASTER_DATA"), kalenderAkademikController.GetAll)
+
+		// Get kalender akademik entry by ID
+		protected.POST("/get-kalender-akademik-by-id", middleware.RequirePermission(db, "READ_MASTER_DATA"), kalenderAkademikController.GetByID)
+
+		// Update kalender akademik entry
+		protected.POST("/update-kalender-akademik", middleware.RequirePermission(db, "UPDATE_MASTER_DATA"), kalenderAkademikController.Update)
+
+		// Delete kalender akademik entry
+		protected.POST("/delete-kalender-akademik", middleware.RequirePermission(db, "DELETE_MASTER_DATA"), kalenderAkademikController.Delete)
+
+		// Download Excel import template
+		protected.POST("/download-template-kalender-akademik", middleware.RequirePermission(db, "READ_MASTER_DATA"), kalenderAkademikController.DownloadTemplate)
+
+		// Import from Excel
+		protected.POST("/import-excel-kalender-akademik", middleware.RequirePermission(db, "CREATE_MASTER_DATA"), kalenderAkademikController.ImportExcel)
+
+		// Import from iCal (.ics)
+		protected.POST("/import-ical-kalender-akademik", middleware.RequirePermission(db, "CREATE_MASTER_DATA"), kalenderAkademikController.ImportICal)
+
+		// List school days in a date range
+		protected.POST("/get-hari-efektif", middleware.RequirePermission(db, "READ_MASTER_DATA"), kalenderAkademikController.GetHariEfektif)
+	}
+}
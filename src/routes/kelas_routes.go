@@ -19,21 +19,21 @@ func RegisterKelasRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/kelas")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
 		// Create kelas
-		protected.POST("/create-kelas", kelasController.Create)
+		protected.POST("/create-kelas", middleware.RequirePermission(db, "CREATE_MASTER_DATA"), kelasController.Create)
 
 		// Get all kelas
-		protected.POST("/get-kelas", kelasController.GetAll)
+		protected.POST("/get-kelas", middleware.RequirePermission(db, "READ_MASTER_DATA"), kelasController.GetAll)
 
 		// Get kelas by ID
-		protected.POST("/get-kelas-by-id", kelasController.GetByID)
+		protected.POST("/get-kelas-by-id", middleware.RequirePermission(db, "READ_MASTER_DATA"), kelasController.GetByID)
 
 		// Update kelas
-		protected.POST("/update-kelas", kelasController.Update)
+		protected.POST("/update-kelas", middleware.RequirePermission(db, "UPDATE_MASTER_DATA"), kelasController.Update)
 
 		// Delete kelas
-		protected.POST("/delete-kelas", kelasController.Delete)
+		protected.POST("/delete-kelas", middleware.RequirePermission(db, "DELETE_MASTER_DATA"), kelasController.Delete)
 	}
 }
@@ -16,7 +16,9 @@ func RegisterKelulusanRoutes(router *gin.Engine, db *gorm.DB) {
 	kelulusanRepository := repositories.NewKelulusanRepository(db)
 	tahunPelajaranRepository := repositories.NewTahunPelajaranRepository(db)
 	pengumumanKelulusanRepository := repositories.NewPengumumanKelulusanRepository(db)
-	service := services.NewKelulusanService(kelulusanRepository, tahunPelajaranRepository, pengumumanKelulusanRepository)
+	throttleService := services.NewThrottleService(repositories.NewFailedAttemptRepository(db))
+	periodeService := services.NewPeriodeAkademikService(tahunPelajaranRepository)
+	service := services.NewKelulusanService(kelulusanRepository, tahunPelajaranRepository, periodeService, pengumumanKelulusanRepository, throttleService)
 	controller := controllers.NewKelulusanController(service)
 
 	// Public routes (no authentication required)
@@ -34,27 +36,27 @@ func RegisterKelulusanRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (require authentication)
 	api := router.Group("/api/v1/kelulusan")
-	api.Use(middleware.AuthMiddleware())
+	api.Use(middleware.AuthMiddleware(db))
 	{
 		// Create data kelulusan
-		api.POST("/create-data-kelulusan", controller.CreateKelulusan)
+		api.POST("/create-data-kelulusan", middleware.RequirePermission(db, "CREATE_PESERTA_DIDIK"), controller.CreateKelulusan)
 		
 		// Get all data kelulusan with filters
-		api.POST("/get-data-kelulusan", controller.GetAll)
+		api.POST("/get-data-kelulusan", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetAll)
 		
 		// Get by ID
-		api.POST("/get-data-kelulusan-by-id", controller.GetByID)
+		api.POST("/get-data-kelulusan-by-id", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetByID)
 		
 		// Update data kelulusan
-		api.POST("/update-data-kelulusan", controller.Update)
+		api.POST("/update-data-kelulusan", middleware.RequirePermission(db, "UPDATE_PESERTA_DIDIK"), controller.Update)
 		
 		// Delete data kelulusan
-		api.POST("/delete-data-kelulusan", controller.Delete)
+		api.POST("/delete-data-kelulusan", middleware.RequirePermission(db, "DELETE_PESERTA_DIDIK"), controller.Delete)
 		
 		// Download template
-		api.POST("/download-template", controller.DownloadTemplate)
+		api.POST("/download-template", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.DownloadTemplate)
 		
 		// Import Excel
-		api.POST("/import-excel", controller.ImportExcel)
+		api.POST("/import-excel", middleware.RequirePermission(db, "CREATE_PESERTA_DIDIK"), controller.ImportExcel)
 	}
 }
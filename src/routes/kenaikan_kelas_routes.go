@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"pintu-backend/src/middleware"
+	"pintu-backend/src/modules/controllers"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/modules/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterKenaikanKelasRoutes registers all kenaikan kelas (academic year rollover) routes
+func RegisterKenaikanKelasRoutes(router *gin.Engine, db *gorm.DB) {
+	// Initialize repository, service, and controller
+	repository := repositories.NewKenaikanKelasRepository(db)
+	service := services.NewKenaikanKelasService(repository)
+	controller := controllers.NewKenaikanKelasController(service)
+
+	// Protected routes (auth required)
+	protected := router.Group("/api/v1/kenaikan-kelas")
+	protected.Use(middleware.AuthMiddleware(db))
+	{
+		// Wizard: proposed mapping, preview, process
+		protected.POST("/get-usulan-kenaikan-kelas", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetUsulan)
+		protected.POST("/preview-kenaikan-kelas", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.Preview)
+		protected.POST("/proses-kenaikan-kelas", middleware.RequirePermission(db, "UPDATE_PESERTA_DIDIK"), controller.Proses)
+
+		// Undo, only the latest rollover on the day it was processed
+		protected.POST("/batalkan-kenaikan-kelas", middleware.RequirePermission(db, "UPDATE_PESERTA_DIDIK"), controller.Batalkan)
+
+		// History
+		protected.POST("/get-riwayat-kenaikan-kelas", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetAll)
+		protected.POST("/get-kenaikan-kelas-by-id", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetByID)
+	}
+}
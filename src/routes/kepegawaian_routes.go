@@ -14,11 +14,12 @@ import (
 // RegisterKepegawaianRoutes registers all Kepegawaian routes
 func RegisterKepegawaianRoutes(router *gin.Engine, db *gorm.DB) {
 	// Initialize storage
-	r2Storage := utils.NewR2Storage()
+	storage := utils.NewStorage()
 
 	// Initialize repository, service, and controller
 	repository := repositories.NewKepegawaianRepository(db)
-	service := services.NewKepegawaianService(repository, r2Storage)
+	authTokenRepo := repositories.NewAuthTokenRepository(db)
+	service := services.NewKepegawaianService(repository, authTokenRepo, storage)
 	controller := controllers.NewKepegawaianController(service)
 
 	// Public routes (no authentication required)
@@ -39,21 +40,26 @@ func RegisterKepegawaianRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (require authentication)
 	api := router.Group("/api/v1/kepegawaian")
-	api.Use(middleware.AuthMiddleware())
+	api.Use(middleware.AuthMiddleware(db))
 	{
 		// Create
-		api.POST("/create-kepegawaian", controller.Create)
+		api.POST("/create-kepegawaian", middleware.RequirePermission(db, "CREATE_KEPEGAWAIAN"), controller.Create)
 
 		// Read
-		api.POST("/get-kepegawaian", controller.GetAll)
-		api.POST("/get-kepegawaian-without-pagination", controller.GetAllWithoutPagination)
-		api.POST("/get-kepegawaian-by-id", controller.GetByID)
-		api.POST("/get-kepegawaian-by-nip", controller.GetByNIP)
+		api.POST("/get-kepegawaian", middleware.RequirePermission(db, "READ_KEPEGAWAIAN"), controller.GetAll)
+		api.POST("/get-kepegawaian-without-pagination", middleware.RequirePermission(db, "READ_KEPEGAWAIAN"), controller.GetAllWithoutPagination)
+		api.POST("/get-kepegawaian-by-id", middleware.RequirePermission(db, "READ_KEPEGAWAIAN"), controller.GetByID)
+		api.POST("/get-kepegawaian-by-nip", middleware.RequirePermission(db, "READ_KEPEGAWAIAN"), controller.GetByNIP)
 
 		// Update
-		api.POST("/update-kepegawaian", controller.Update)
+		api.POST("/update-kepegawaian", middleware.RequirePermission(db, "UPDATE_KEPEGAWAIAN"), controller.Update)
+
+		// Barcode absensi pegawai
+		api.POST("/generate-barcode-all-kepegawaian", middleware.RequirePermission(db, "UPDATE_KEPEGAWAIAN"), controller.GenerateBarcodeAllKepegawaian)
+		api.POST("/generate-barcode-kepegawaian-by-id", middleware.RequirePermission(db, "UPDATE_KEPEGAWAIAN"), controller.GenerateBarcodeKepegawaianByID)
+		api.POST("/download-barcode-kepegawaian", middleware.RequirePermission(db, "READ_KEPEGAWAIAN"), controller.DownloadBarcodeKepegawaian)
 
 		// Delete
-		api.POST("/delete-kepegawaian", controller.Delete)
+		api.POST("/delete-kepegawaian", middleware.RequirePermission(db, "DELETE_KEPEGAWAIAN"), controller.Delete)
 	}
 }
@@ -19,9 +19,9 @@ func RegisterKonfigurasiAbsensiRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/absensi-siswa")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
-		protected.POST("/setting-konfigurasi-absensi", controller.UpsertKonfigurasi)
-		protected.POST("/get-konfigurasi-absensi", controller.GetKonfigurasi)
+		protected.POST("/setting-konfigurasi-absensi", middleware.RequirePermission(db, "UPDATE_PESERTA_DIDIK"), controller.UpsertKonfigurasi)
+		protected.POST("/get-konfigurasi-absensi", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetKonfigurasi)
 	}
 }
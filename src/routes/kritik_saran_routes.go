@@ -25,15 +25,15 @@ func RegisterKritikSaranRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/kritik-saran")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
 		// Get all kritik saran
-		protected.POST("/get-kritik-saran", kritikSaranController.GetAll)
+		protected.POST("/get-kritik-saran", middleware.RequirePermission(db, "READ_LAYANAN_UMPAN_BALIK"), kritikSaranController.GetAll)
 
 		// Get kritik saran by ID
-		protected.POST("/get-kritik-saran-by-id", kritikSaranController.GetByID)
+		protected.POST("/get-kritik-saran-by-id", middleware.RequirePermission(db, "READ_LAYANAN_UMPAN_BALIK"), kritikSaranController.GetByID)
 
 		// Delete kritik saran
-		protected.POST("/delete-kritik-saran", kritikSaranController.Delete)
+		protected.POST("/delete-kritik-saran", middleware.RequirePermission(db, "DELETE_LAYANAN_UMPAN_BALIK"), kritikSaranController.Delete)
 	}
 }
@@ -13,12 +13,12 @@ import (
 
 // RegisterKutipanKepsekRoutes registers all kutipan kepsek routes
 func RegisterKutipanKepsekRoutes(router *gin.Engine, db *gorm.DB) {
-	// Initialize R2 storage
-	r2Storage := utils.NewR2Storage()
+	// Initialize storage
+	storage := utils.NewStorage()
 
 	// Initialize repository, service, and controller
 	kutipanKepsekRepo := repositories.NewKutipanKepsekRepository(db)
-	kutipanKepsekService := services.NewKutipanKepsekService(kutipanKepsekRepo, r2Storage)
+	kutipanKepsekService := services.NewKutipanKepsekService(kutipanKepsekRepo, storage)
 	kutipanKepsekController := controllers.NewKutipanKepsekController(kutipanKepsekService)
 
 	// Public routes (no auth required)
@@ -29,21 +29,21 @@ func RegisterKutipanKepsekRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/kutipan-kepsek")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
 		// Create kutipan kepsek with file upload
-		protected.POST("/create-kutipan-kepsek", kutipanKepsekController.Create)
+		protected.POST("/create-kutipan-kepsek", middleware.RequirePermission(db, "CREATE_INFORMASI_SEKOLAH"), kutipanKepsekController.Create)
 
 		// Get all kutipan kepsek
-		protected.POST("/get-kutipan-kepsek", kutipanKepsekController.GetAll)
+		protected.POST("/get-kutipan-kepsek", middleware.RequirePermission(db, "READ_INFORMASI_SEKOLAH"), kutipanKepsekController.GetAll)
 
 		// Get kutipan kepsek by ID
-		protected.POST("/get-kutipan-kepsek-by-id", kutipanKepsekController.GetByID)
+		protected.POST("/get-kutipan-kepsek-by-id", middleware.RequirePermission(db, "READ_INFORMASI_SEKOLAH"), kutipanKepsekController.GetByID)
 
 		// Update kutipan kepsek
-		protected.POST("/update-kutipan-kepsek", kutipanKepsekController.Update)
+		protected.POST("/update-kutipan-kepsek", middleware.RequirePermission(db, "UPDATE_INFORMASI_SEKOLAH"), kutipanKepsekController.Update)
 
 		// Delete kutipan kepsek
-		protected.POST("/delete-kutipan-kepsek", kutipanKepsekController.Delete)
+		protected.POST("/delete-kutipan-kepsek", middleware.RequirePermission(db, "DELETE_INFORMASI_SEKOLAH"), kutipanKepsekController.Delete)
 	}
 }
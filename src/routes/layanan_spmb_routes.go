@@ -31,14 +31,14 @@ func RegisterLayananSPMBRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/spmb")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
-		protected.POST("/get-layanan-spmb", layananSPMBController.GetAll)
-		protected.POST("/get-layanan-spmb-by-id", layananSPMBController.GetByID)
-		protected.POST("/set-status-selesai", layananSPMBController.UpdateStatus)
-		protected.POST("/delete-layanan-spmb", layananSPMBController.DeleteLayananSPMB)
-		protected.POST("/monitoring-pelayanan-spmb", layananSPMBController.GetMonitoringPelayanan)
-		protected.POST("/setting-layanan-spmb", settingLayananSPMBController.UpsertSetting)
-		protected.POST("/get-setting-layanan-spmb", settingLayananSPMBController.GetSetting)
+		protected.POST("/get-layanan-spmb", middleware.RequirePermission(db, "READ_MUTASI_SISWA"), layananSPMBController.GetAll)
+		protected.POST("/get-layanan-spmb-by-id", middleware.RequirePermission(db, "READ_MUTASI_SISWA"), layananSPMBController.GetByID)
+		protected.POST("/set-status-selesai", middleware.RequirePermission(db, "UPDATE_MUTASI_SISWA"), layananSPMBController.UpdateStatus)
+		protected.POST("/delete-layanan-spmb", middleware.RequirePermission(db, "DELETE_MUTASI_SISWA"), layananSPMBController.DeleteLayananSPMB)
+		protected.POST("/monitoring-pelayanan-spmb", middleware.RequirePermission(db, "READ_MUTASI_SISWA"), layananSPMBController.GetMonitoringPelayanan)
+		protected.POST("/setting-layanan-spmb", middleware.RequirePermission(db, "UPDATE_MUTASI_SISWA"), settingLayananSPMBController.UpsertSetting)
+		protected.POST("/get-setting-layanan-spmb", middleware.RequirePermission(db, "READ_MUTASI_SISWA"), settingLayananSPMBController.GetSetting)
 	}
 }
@@ -13,49 +13,50 @@ import (
 
 // RegisterMutasiSiswaRoutes registers all mutasi siswa routes
 func RegisterMutasiSiswaRoutes(router *gin.Engine, db *gorm.DB) {
-	// Initialize R2 storage
-	r2Storage := utils.NewR2Storage()
+	// Initialize storage
+	storage := utils.NewStorage()
 
 	// Initialize repository, service, and controller for Mutasi Siswa
 	mutasiSiswaRepo := repositories.NewMutasiSiswaRepository(db)
-	mutasiSiswaService := services.NewMutasiSiswaService(mutasiSiswaRepo, r2Storage)
+	periodeService := services.NewPeriodeAkademikService(repositories.NewTahunPelajaranRepository(db))
+	mutasiSiswaService := services.NewMutasiSiswaService(mutasiSiswaRepo, periodeService, storage)
 	mutasiSiswaController := controllers.NewMutasiSiswaController(mutasiSiswaService)
 
 	// Initialize repository, service, and controller for Konfigurasi Mutasi Siswa
 	konfigurasiRepo := repositories.NewKonfigurasiMutasiSiswaRepository(db)
-	konfigurasiService := services.NewKonfigurasiMutasiSiswaService(konfigurasiRepo, r2Storage)
+	konfigurasiService := services.NewKonfigurasiMutasiSiswaService(konfigurasiRepo, storage)
 	konfigurasiController := controllers.NewKonfigurasiMutasiSiswaController(konfigurasiService)
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/spmb-mutasi")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
 		// Get all mutasi siswa with filters
-		protected.POST("/get-mutasi-siswa", mutasiSiswaController.GetAll)
+		protected.POST("/get-mutasi-siswa", middleware.RequirePermission(db, "READ_MUTASI_SISWA"), mutasiSiswaController.GetAll)
 		
 		// Get mutasi siswa by ID
-		protected.POST("/get-mutasi-siswa-by-id", mutasiSiswaController.GetByID)
+		protected.POST("/get-mutasi-siswa-by-id", middleware.RequirePermission(db, "READ_MUTASI_SISWA"), mutasiSiswaController.GetByID)
 		
 		// Update mutasi siswa
-		protected.POST("/edit-mutasi-siswa", mutasiSiswaController.Update)
+		protected.POST("/edit-mutasi-siswa", middleware.RequirePermission(db, "UPDATE_MUTASI_SISWA"), mutasiSiswaController.Update)
 
 		// Delete mutasi siswa
-		protected.POST("/delete-mutasi-siswa", mutasiSiswaController.Delete)
+		protected.POST("/delete-mutasi-siswa", middleware.RequirePermission(db, "DELETE_MUTASI_SISWA"), mutasiSiswaController.Delete)
 
 		// Export formulir pendaftaran PDF (admin)
-		protected.POST("/export-pdf-formulir-mutasi-siswa", mutasiSiswaController.ExportFormulirPDFAuth)
+		protected.POST("/export-pdf-formulir-mutasi-siswa", middleware.RequirePermission(db, "READ_MUTASI_SISWA"), mutasiSiswaController.ExportFormulirPDFAuth)
 
 		// Export Excel data mutasi siswa
-		protected.POST("/export-excel-mutasi-siswa", mutasiSiswaController.ExportExcel)
+		protected.POST("/export-excel-mutasi-siswa", middleware.RequirePermission(db, "READ_MUTASI_SISWA"), mutasiSiswaController.ExportExcel)
 
 		// Export PDF list mutasi siswa
-		protected.POST("/export-pdf-mutasi-siswa", mutasiSiswaController.ExportListPDF)
+		protected.POST("/export-pdf-mutasi-siswa", middleware.RequirePermission(db, "READ_MUTASI_SISWA"), mutasiSiswaController.ExportListPDF)
 
 		// Setting konfigurasi mutasi siswa (upsert)
-		protected.POST("/setting-konfigurasi-mutasi-siswa", konfigurasiController.UpsertSetting)
+		protected.POST("/setting-konfigurasi-mutasi-siswa", middleware.RequirePermission(db, "UPDATE_MUTASI_SISWA"), konfigurasiController.UpsertSetting)
 
 		// Get konfigurasi mutasi siswa
-		protected.POST("/get-konfigurasi-mutasi-siswa", konfigurasiController.GetSetting)
+		protected.POST("/get-konfigurasi-mutasi-siswa", middleware.RequirePermission(db, "READ_MUTASI_SISWA"), konfigurasiController.GetSetting)
 	}
 
 	// Public routes (no auth required)
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"pintu-backend/src/middleware"
+	"pintu-backend/src/modules/controllers"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/modules/services"
+	"pintu-backend/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterNotifikasiRoutes registers all notifikasi routes
+func RegisterNotifikasiRoutes(router *gin.Engine, db *gorm.DB) {
+	// Initialize repository, service, and controller
+	notifikasiRepo := repositories.NewNotifikasiRepository(db)
+	notifikasiService := services.NewNotifikasiService(notifikasiRepo, utils.NewNotifikasiChannels())
+	notifikasiController := controllers.NewNotifikasiController(notifikasiService)
+
+	// Protected routes (auth required)
+	protected := router.Group("/api/v1/notifikasi")
+	protected.Use(middleware.AuthMiddleware(db))
+	{
+		// Message templates per event and channel
+		protected.POST("/get-notifikasi-templates", middleware.RequirePermission(db, "READ_MASTER_DATA"), notifikasiController.GetTemplates)
+		protected.POST("/update-notifikasi-template", middleware.RequirePermission(db, "UPDATE_MASTER_DATA"), notifikasiController.UpdateTemplate)
+
+		// Outbox
+		protected.POST("/get-notifikasi-outbox", middleware.RequirePermission(db, "READ_MASTER_DATA"), notifikasiController.GetOutbox)
+		protected.POST("/retry-notifikasi-outbox", middleware.RequirePermission(db, "UPDATE_MASTER_DATA"), notifikasiController.RetryOutbox)
+		protected.POST("/process-notifikasi-outbox", middleware.RequirePermission(db, "UPDATE_MASTER_DATA"), notifikasiController.ProcessOutbox)
+	}
+}
@@ -13,12 +13,12 @@ import (
 
 // RegisterPengaduanRoutes registers all pengaduan routes
 func RegisterPengaduanRoutes(router *gin.Engine, db *gorm.DB) {
-	// Initialize R2 storage
-	r2Storage := utils.NewR2Storage()
+	// Initialize storage
+	storage := utils.NewStorage()
 
 	// Initialize repository, service, and controller
 	pengaduanRepo := repositories.NewPengaduanRepository(db)
-	pengaduanService := services.NewPengaduanService(pengaduanRepo, r2Storage)
+	pengaduanService := services.NewPengaduanService(pengaduanRepo, storage)
 	pengaduanController := controllers.NewPengaduanController(pengaduanService)
 
 	// Public routes (no auth required)
@@ -30,13 +30,13 @@ func RegisterPengaduanRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/pengaduan")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
-		protected.POST("/get-pengaduan", pengaduanController.GetAll)
-		protected.POST("/get-pengaduan-by-id", pengaduanController.GetByID)
-		protected.POST("/send-reply", pengaduanController.SendReply)
-		protected.POST("/save-tindak-lanjut", pengaduanController.SaveTindakLanjut)
-		protected.POST("/close-pengaduan", pengaduanController.ClosePengaduan)
-		protected.POST("/delete-pengaduan", pengaduanController.DeletePengaduan)
+		protected.POST("/get-pengaduan", middleware.RequirePermission(db, "READ_LAYANAN_UMPAN_BALIK"), pengaduanController.GetAll)
+		protected.POST("/get-pengaduan-by-id", middleware.RequirePermission(db, "READ_LAYANAN_UMPAN_BALIK"), pengaduanController.GetByID)
+		protected.POST("/send-reply", middleware.RequirePermission(db, "UPDATE_LAYANAN_UMPAN_BALIK"), pengaduanController.SendReply)
+		protected.POST("/save-tindak-lanjut", middleware.RequirePermission(db, "UPDATE_LAYANAN_UMPAN_BALIK"), pengaduanController.SaveTindakLanjut)
+		protected.POST("/close-pengaduan", middleware.RequirePermission(db, "UPDATE_LAYANAN_UMPAN_BALIK"), pengaduanController.ClosePengaduan)
+		protected.POST("/delete-pengaduan", middleware.RequirePermission(db, "DELETE_LAYANAN_UMPAN_BALIK"), pengaduanController.DeletePengaduan)
 	}
 }
\ No newline at end of file
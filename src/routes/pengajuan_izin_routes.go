@@ -0,0 +1,59 @@
+package routes
+
+import (
+	"pintu-backend/src/middleware"
+	"pintu-backend/src/modules/controllers"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/modules/services"
+	"pintu-backend/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterPengajuanIzinRoutes registers all pengajuan izin routes
+func RegisterPengajuanIzinRoutes(router *gin.Engine, db *gorm.DB) {
+	// Initialize storage
+	storage := utils.NewStorage()
+
+	// Initialize repository, service, and controller
+	periodeService := services.NewPeriodeAkademikService(repositories.NewTahunPelajaranRepository(db))
+	kalenderService := services.NewKalenderAkademikService(repositories.NewKalenderAkademikRepository(db), repositories.NewKonfigurasiAbsensiRepository(db), periodeService)
+	service := services.NewPengajuanIzinService(
+		repositories.NewPengajuanIzinRepository(db),
+		repositories.NewPesertaDidikRepository(db),
+		repositories.NewPesertaDidikRombelRepository(db),
+		periodeService,
+		repositories.NewKepegawaianRepository(db),
+		kalenderService,
+		services.NewThrottleService(repositories.NewFailedAttemptRepository(db)),
+		storage,
+	)
+	controller := controllers.NewPengajuanIzinController(service)
+
+	// Public routes (no auth required), for parents
+	public := router.Group("/api/v1/public")
+	{
+		public.POST("/create-pengajuan-izin", controller.CreatePublic)
+		public.POST("/track-pengajuan-izin", controller.TrackPengajuanIzin)
+	}
+
+	// Siswa portal routes, the request is always for the caller
+	siswa := router.Group("/api/v1/siswa")
+	siswa.Use(middleware.AuthMiddleware(db))
+	siswa.Use(middleware.RequirePrincipalType(utils.PrincipalSiswa))
+	{
+		siswa.POST("/create-pengajuan-izin", controller.CreateBySiswa)
+		siswa.POST("/get-pengajuan-izin", controller.GetBySiswa)
+	}
+
+	// Approval queue of the wali kelas
+	protected := router.Group("/api/v1/pengajuan-izin")
+	protected.Use(middleware.AuthMiddleware(db))
+	{
+		protected.POST("/get-pengajuan-izin", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetAll)
+		protected.POST("/get-pengajuan-izin-by-id", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetByID)
+		protected.POST("/approve-pengajuan-izin", middleware.RequirePermission(db, "UPDATE_PESERTA_DIDIK"), controller.Approve)
+		protected.POST("/reject-pengajuan-izin", middleware.RequirePermission(db, "UPDATE_PESERTA_DIDIK"), controller.Reject)
+	}
+}
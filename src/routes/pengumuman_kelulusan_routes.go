@@ -26,12 +26,12 @@ func RegisterPengumumanKelulusanRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (require authentication)
 	api := router.Group("/api/v1/kelulusan")
-	api.Use(middleware.AuthMiddleware())
+	api.Use(middleware.AuthMiddleware(db))
 	{
 		// Configure pengumuman (create or update)
-		api.POST("/konfigurasi-pengumuman", controller.ConfigurePengumuman)
+		api.POST("/konfigurasi-pengumuman", middleware.RequirePermission(db, "UPDATE_PESERTA_DIDIK"), controller.ConfigurePengumuman)
 		
 		// Get pengumuman configuration
-		api.POST("/get-konfigurasi-pengumuman", controller.GetPengumuman)
+		api.POST("/get-konfigurasi-pengumuman", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetPengumuman)
 	}
 }
@@ -19,17 +19,17 @@ func RegisterPermissionRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Group routes under /api/v1/permissions with auth middleware
 	api := router.Group("/api/v1/permissions")
-	api.Use(middleware.AuthMiddleware()) // Require authentication
+	api.Use(middleware.AuthMiddleware(db)) // Require authentication
 	{
 		// CRUD operations - all POST with action-based routes
-		api.POST("/create-permission", permissionController.Create)                    // Create permission
-		api.POST("/get-permissions", permissionController.GetAll)                     // Get all permissions (with pagination)
-		api.POST("/get-permission-by-id", permissionController.GetByID)                     // Get permission by ID
-		api.POST("/update-permission", permissionController.Update)                   // Update permission
-		api.POST("/delete-permission", permissionController.Delete)                   // Delete permission
+		api.POST("/create-permission", middleware.RequirePermission(db, "CREATE_MASTER_DATA"), permissionController.Create) // Create permission
+		api.POST("/get-permissions", middleware.RequirePermission(db, "READ_MASTER_DATA"), permissionController.GetAll) // Get all permissions (with pagination)
+		api.POST("/get-permission-by-id", middleware.RequirePermission(db, "READ_MASTER_DATA"), permissionController.GetByID) // Get permission by ID
+		api.POST("/update-permission", middleware.RequirePermission(db, "UPDATE_MASTER_DATA"), permissionController.Update) // Update permission
+		api.POST("/delete-permission", middleware.RequirePermission(db, "DELETE_MASTER_DATA"), permissionController.Delete) // Delete permission
 
 		// Filter operations
-		api.POST("/get-permissions-by-group", permissionController.GetByGroupName)    // Get by group name
-		api.POST("/get-permissions-by-system", permissionController.GetBySystem)      // Get by system
+		api.POST("/get-permissions-by-group", middleware.RequirePermission(db, "READ_MASTER_DATA"), permissionController.GetByGroupName) // Get by group name
+		api.POST("/get-permissions-by-system", middleware.RequirePermission(db, "READ_MASTER_DATA"), permissionController.GetBySystem) // Get by system
 	}
 }
@@ -13,23 +13,23 @@ import (
 
 // RegisterPertanyaanRoutes registers all pertanyaan routes
 func RegisterPertanyaanRoutes(router *gin.Engine, db *gorm.DB) {
-	// Initialize R2 storage
-	r2Storage := utils.NewR2Storage()
+	// Initialize storage
+	storage := utils.NewStorage()
 
 	// Initialize repository, service, and controller
 	pertanyaanRepo := repositories.NewPertanyaanRepository(db)
-	pertanyaanService := services.NewPertanyaanService(pertanyaanRepo, r2Storage)
+	pertanyaanService := services.NewPertanyaanService(pertanyaanRepo, storage)
 	pertanyaanController := controllers.NewPertanyaanController(pertanyaanService)
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/pertanyaan")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
-		protected.POST("/get-pertanyaan", pertanyaanController.GetAll)
-		protected.POST("/get-pertanyaan-by-id", pertanyaanController.GetByID)
-		protected.POST("/send-reply", pertanyaanController.SendReply)
-		protected.POST("/close-pertanyaan", pertanyaanController.ClosePertanyaan)
-		protected.POST("/delete-pertanyaan", pertanyaanController.DeletePertanyaan)
+		protected.POST("/get-pertanyaan", middleware.RequirePermission(db, "READ_LAYANAN_UMPAN_BALIK"), pertanyaanController.GetAll)
+		protected.POST("/get-pertanyaan-by-id", middleware.RequirePermission(db, "READ_LAYANAN_UMPAN_BALIK"), pertanyaanController.GetByID)
+		protected.POST("/send-reply", middleware.RequirePermission(db, "UPDATE_LAYANAN_UMPAN_BALIK"), pertanyaanController.SendReply)
+		protected.POST("/close-pertanyaan", middleware.RequirePermission(db, "UPDATE_LAYANAN_UMPAN_BALIK"), pertanyaanController.ClosePertanyaan)
+		protected.POST("/delete-pertanyaan", middleware.RequirePermission(db, "DELETE_LAYANAN_UMPAN_BALIK"), pertanyaanController.DeletePertanyaan)
 	}
 
 	// Public routes (no auth required)
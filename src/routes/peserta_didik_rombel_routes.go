@@ -17,41 +17,41 @@ func RegisterPesertaDidikRombelRoutes(router *gin.Engine, db *gorm.DB) {
 	pesertaDidikRombelRepo := repositories.NewPesertaDidikRombelRepository(db)
 	pesertaDidikRepo := repositories.NewPesertaDidikRepository(db)
 
-	// Initialize R2 storage
-	r2Storage := utils.NewR2Storage()
+	// Initialize storage
+	storage := utils.NewStorage()
 
 	// Initialize service
-	service := services.NewPesertaDidikRombelService(pesertaDidikRombelRepo, pesertaDidikRepo, r2Storage)
+	service := services.NewPesertaDidikRombelService(pesertaDidikRombelRepo, pesertaDidikRepo, storage)
 
 	// Initialize controller
 	controller := controllers.NewPesertaDidikRombelController(service)
 
 	// Protected routes (require authentication)
 	api := router.Group("/api/v1/peserta-didik")
-	api.Use(middleware.AuthMiddleware())
+	api.Use(middleware.AuthMiddleware(db))
 	{
 		// Bulk create pemetaan rombel
-		api.POST("/create-pemetaan-rombel", controller.BulkCreate)
+		api.POST("/create-pemetaan-rombel", middleware.RequirePermission(db, "CREATE_PESERTA_DIDIK"), controller.BulkCreate)
 		
 		// Get pemetaan rombel with filters
-		api.POST("/get-pemetaan-rombel", controller.GetAll)
+		api.POST("/get-pemetaan-rombel", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetAll)
 		
 		// Get pemetaan rombel by ID
-		api.POST("/get-pemetaan-rombel-by-id", controller.GetByID)
+		api.POST("/get-pemetaan-rombel-by-id", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetByID)
 		
 		// Update pemetaan rombel
-		api.POST("/edit-pemetaan-rombel-by-id", controller.Update)
+		api.POST("/edit-pemetaan-rombel-by-id", middleware.RequirePermission(db, "UPDATE_PESERTA_DIDIK"), controller.Update)
 		
 		// Delete pemetaan rombel
-		api.POST("/delete-pemetaan-rombel-by-id", controller.Delete)
+		api.POST("/delete-pemetaan-rombel-by-id", middleware.RequirePermission(db, "DELETE_PESERTA_DIDIK"), controller.Delete)
 		
 		// Download Template
-		api.POST("/download-template-pemetaan-rombel", controller.DownloadTemplate)
+		api.POST("/download-template-pemetaan-rombel", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.DownloadTemplate)
 		
 		// Import Excel
-		api.POST("/import-excel-pemetaan-rombel", controller.ImportExcel)
+		api.POST("/import-excel-pemetaan-rombel", middleware.RequirePermission(db, "CREATE_PESERTA_DIDIK"), controller.ImportExcel)
 		
 		// Reset pemetaan rombel
-		api.POST("/reset-pemetaan-rombel", controller.Reset)
+		api.POST("/reset-pemetaan-rombel", middleware.RequirePermission(db, "DELETE_PESERTA_DIDIK"), controller.Reset)
 	}
 }
@@ -13,12 +13,13 @@ import (
 
 // RegisterPesertaDidikRoutes registers all PesertaDidik routes
 func RegisterPesertaDidikRoutes(router *gin.Engine, db *gorm.DB) {
-	// Initialize R2 storage
-	r2Storage := utils.NewR2Storage()
+	// Initialize storage
+	storage := utils.NewStorage()
 
 	// Initialize repository, service, and controller
 	repository := repositories.NewPesertaDidikRepository(db)
-	service := services.NewPesertaDidikService(repository, r2Storage)
+	authTokenRepo := repositories.NewAuthTokenRepository(db)
+	service := services.NewPesertaDidikService(repository, authTokenRepo, storage)
 	controller := controllers.NewPesertaDidikController(service)
 
 	// Public routes (no authentication required)
@@ -30,37 +31,37 @@ func RegisterPesertaDidikRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (require authentication)
 	api := router.Group("/api/v1/peserta-didik")
-	api.Use(middleware.AuthMiddleware())
+	api.Use(middleware.AuthMiddleware(db))
 	{
 		// Create
-		api.POST("/create-peserta-didik", controller.Create)
+		api.POST("/create-peserta-didik", middleware.RequirePermission(db, "CREATE_PESERTA_DIDIK"), controller.Create)
 
 		// Read
-		api.POST("/get-peserta-didik", controller.GetAll)
-		api.POST("/get-peserta-didik-by-id", controller.GetByID)
-		api.POST("/get-peserta-didik-by-nis", controller.GetByNIS)
+		api.POST("/get-peserta-didik", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetAll)
+		api.POST("/get-peserta-didik-by-id", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetByID)
+		api.POST("/get-peserta-didik-by-nis", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.GetByNIS)
 
 		// Update
-		api.POST("/update-peserta-didik", controller.Update)
+		api.POST("/update-peserta-didik", middleware.RequirePermission(db, "UPDATE_PESERTA_DIDIK"), controller.Update)
 
 		// Delete
-		api.POST("/delete-peserta-didik", controller.Delete)
+		api.POST("/delete-peserta-didik", middleware.RequirePermission(db, "DELETE_PESERTA_DIDIK"), controller.Delete)
 
 		// Import Excel
-		api.POST("/import-excel", controller.ImportExcel)
-		api.POST("/import-siswa-lulus", controller.ImportSiswaLulus)
+		api.POST("/import-excel", middleware.RequirePermission(db, "CREATE_PESERTA_DIDIK"), controller.ImportExcel)
+		api.POST("/import-siswa-lulus", middleware.RequirePermission(db, "CREATE_PESERTA_DIDIK"), controller.ImportSiswaLulus)
 
 		// Download Template
-		api.POST("/download-template", controller.DownloadTemplate)
-		api.POST("/download-template-siswa-lulus", controller.DownloadTemplateSiswaLulus)
-		api.POST("/export-data-induk-siswa-excel", controller.ExportDataIndukSiswaExcel)
-		api.POST("/export-data-induk-siswa-pdf", controller.ExportDataIndukSiswaPDF)
-		api.POST("/export-pemetaan-rombel-excel", controller.ExportPemetaanRombelExcel)
-		api.POST("/export-pemetaan-rombel-pdf", controller.ExportPemetaanRombelPDF)
-		api.POST("/download-kartu-pelajar", controller.DownloadKartuPelajar)
+		api.POST("/download-template", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.DownloadTemplate)
+		api.POST("/download-template-siswa-lulus", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.DownloadTemplateSiswaLulus)
+		api.POST("/export-data-induk-siswa-excel", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.ExportDataIndukSiswaExcel)
+		api.POST("/export-data-induk-siswa-pdf", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.ExportDataIndukSiswaPDF)
+		api.POST("/export-pemetaan-rombel-excel", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.ExportPemetaanRombelExcel)
+		api.POST("/export-pemetaan-rombel-pdf", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.ExportPemetaanRombelPDF)
+		api.POST("/download-kartu-pelajar", middleware.RequirePermission(db, "READ_PESERTA_DIDIK"), controller.DownloadKartuPelajar)
 
 		// Generate Barcode
-		api.POST("/generate-barcode-all-peserta-didik", controller.GenerateBarcodeAllPesertaDidik)
-		api.POST("/generate-barcode-peserta-didik-by-id", controller.GenerateBarcodePesertaDidikByID)
+		api.POST("/generate-barcode-all-peserta-didik", middleware.RequirePermission(db, "UPDATE_PESERTA_DIDIK"), controller.GenerateBarcodeAllPesertaDidik)
+		api.POST("/generate-barcode-peserta-didik-by-id", middleware.RequirePermission(db, "UPDATE_PESERTA_DIDIK"), controller.GenerateBarcodePesertaDidikByID)
 	}
 }
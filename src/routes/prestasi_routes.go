@@ -13,18 +13,19 @@ import (
 
 // RegisterPrestasiRoutes registers all Prestasi routes
 func RegisterPrestasiRoutes(router *gin.Engine, db *gorm.DB) {
-	// Initialize R2 storage
-	r2Storage := utils.NewR2Storage()
+	// Initialize storage
+	storage := utils.NewStorage()
 
 	// Initialize repository, service, and controller
 	repository := repositories.NewPrestasiRepository(db)
-	service := services.NewPrestasiService(repository, r2Storage)
+	periodeService := services.NewPeriodeAkademikService(repositories.NewTahunPelajaranRepository(db))
+	service := services.NewPrestasiService(repository, periodeService, storage)
 	controller := controllers.NewPrestasiController(service)
 
 	// Protected routes (require authentication)
 	api := router.Group("/api/v1")
 	{
-		PrestasiRoutes(api, controller)
+		PrestasiRoutes(api, controller, db)
 	}
 
 	// Public routes (no authentication required)
@@ -35,15 +36,15 @@ func RegisterPrestasiRoutes(router *gin.Engine, db *gorm.DB) {
 }
 
 // PrestasiRoutes sets up routes for prestasi endpoints
-func PrestasiRoutes(router *gin.RouterGroup, controller *controllers.PrestasiController) {
+func PrestasiRoutes(router *gin.RouterGroup, controller *controllers.PrestasiController, db *gorm.DB) {
 	prestasiGroup := router.Group("/prestasi")
-	prestasiGroup.Use(middleware.AuthMiddleware()) // Apply auth middleware to all prestasi routes
+	prestasiGroup.Use(middleware.AuthMiddleware(db)) // Apply auth middleware to all prestasi routes
 	{
-		prestasiGroup.POST("/create-prestasi", controller.Create)
-		prestasiGroup.POST("/get-prestasi", controller.GetAll)
-		prestasiGroup.POST("/get-prestasi-by-id", controller.GetByID)
-		prestasiGroup.POST("/update-prestasi", controller.Update)
-		prestasiGroup.POST("/delete-prestasi", controller.Delete)
+		prestasiGroup.POST("/create-prestasi", middleware.RequirePermission(db, "CREATE_MEDIA_PUBLIKASI"), controller.Create)
+		prestasiGroup.POST("/get-prestasi", middleware.RequirePermission(db, "READ_MEDIA_PUBLIKASI"), controller.GetAll)
+		prestasiGroup.POST("/get-prestasi-by-id", middleware.RequirePermission(db, "READ_MEDIA_PUBLIKASI"), controller.GetByID)
+		prestasiGroup.POST("/update-prestasi", middleware.RequirePermission(db, "UPDATE_MEDIA_PUBLIKASI"), controller.Update)
+		prestasiGroup.POST("/delete-prestasi", middleware.RequirePermission(db, "DELETE_MEDIA_PUBLIKASI"), controller.Delete)
 	}
 }
 
@@ -19,12 +19,12 @@ func RegisterRoleRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Group routes under /api/v1/roles with auth middleware
 	api := router.Group("/api/v1/roles")
-	api.Use(middleware.AuthMiddleware()) // Require authentication
+	api.Use(middleware.AuthMiddleware(db)) // Require authentication
 	{
-		api.POST("/create-role", roleController.Create)      // Create role
-		api.POST("/get-roles", roleController.GetAll)        // Get all roles
-		api.POST("/get-role-by-id", roleController.GetByID)        // Get role by ID
-		api.POST("/update-role", roleController.Update)      // Update role
-		api.POST("/delete-role", roleController.Delete)      // Delete role
+		api.POST("/create-role", middleware.RequirePermission(db, "CREATE_MASTER_DATA"), roleController.Create) // Create role
+		api.POST("/get-roles", middleware.RequirePermission(db, "READ_MASTER_DATA"), roleController.GetAll) // Get all roles
+		api.POST("/get-role-by-id", middleware.RequirePermission(db, "READ_MASTER_DATA"), roleController.GetByID) // Get role by ID
+		api.POST("/update-role", middleware.RequirePermission(db, "UPDATE_MASTER_DATA"), roleController.Update) // Update role
+		api.POST("/delete-role", middleware.RequirePermission(db, "DELETE_MASTER_DATA"), roleController.Delete) // Delete role
 	}
 }
@@ -27,21 +27,21 @@ func RegisterRombelRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/rombel")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
 		// Create rombel
-		protected.POST("/create-rombel", rombelController.Create)
+		protected.POST("/create-rombel", middleware.RequirePermission(db, "CREATE_MASTER_DATA"), rombelController.Create)
 
 		// Get all rombel
-		protected.POST("/get-rombel", rombelController.GetAll)
+		protected.POST("/get-rombel", middleware.RequirePermission(db, "READ_MASTER_DATA"), rombelController.GetAll)
 
 		// Get rombel by ID
-		protected.POST("/get-rombel-by-id", rombelController.GetByID)
+		protected.POST("/get-rombel-by-id", middleware.RequirePermission(db, "READ_MASTER_DATA"), rombelController.GetByID)
 
 		// Update rombel
-		protected.POST("/update-rombel", rombelController.Update)
+		protected.POST("/update-rombel", middleware.RequirePermission(db, "UPDATE_MASTER_DATA"), rombelController.Update)
 
 		// Delete rombel
-		protected.POST("/delete-rombel", rombelController.Delete)
+		protected.POST("/delete-rombel", middleware.RequirePermission(db, "DELETE_MASTER_DATA"), rombelController.Delete)
 	}
 }
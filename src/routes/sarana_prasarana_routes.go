@@ -13,12 +13,12 @@ import (
 
 // RegisterSaranaPrasaranaRoutes registers all sarana prasarana routes
 func RegisterSaranaPrasaranaRoutes(router *gin.Engine, db *gorm.DB) {
-	// Initialize R2 storage
-	r2Storage := utils.NewR2Storage()
+	// Initialize storage
+	storage := utils.NewStorage()
 
 	// Initialize repository, service, and controller
 	saranaPrasaranaRepo := repositories.NewSaranaPrasaranaRepository(db)
-	saranaPrasaranaService := services.NewSaranaPrasaranaService(saranaPrasaranaRepo, r2Storage)
+	saranaPrasaranaService := services.NewSaranaPrasaranaService(saranaPrasaranaRepo, storage)
 	saranaPrasaranaController := controllers.NewSaranaPrasaranaController(saranaPrasaranaService)
 
 	// Public routes (no auth required)
@@ -29,21 +29,21 @@ func RegisterSaranaPrasaranaRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/sarana-prasarana")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
 		// Create sarana prasarana with file upload
-		protected.POST("/create-sarana-prasarana", saranaPrasaranaController.Create)
+		protected.POST("/create-sarana-prasarana", middleware.RequirePermission(db, "CREATE_INFORMASI_SEKOLAH"), saranaPrasaranaController.Create)
 
 		// Get all sarana prasarana
-		protected.POST("/get-sarana-prasarana", saranaPrasaranaController.GetAll)
+		protected.POST("/get-sarana-prasarana", middleware.RequirePermission(db, "READ_INFORMASI_SEKOLAH"), saranaPrasaranaController.GetAll)
 
 		// Get sarana prasarana by ID
-		protected.POST("/get-sarana-prasarana-by-id", saranaPrasaranaController.GetByID)
+		protected.POST("/get-sarana-prasarana-by-id", middleware.RequirePermission(db, "READ_INFORMASI_SEKOLAH"), saranaPrasaranaController.GetByID)
 
 		// Update sarana prasarana
-		protected.POST("/update-sarana-prasarana", saranaPrasaranaController.Update)
+		protected.POST("/update-sarana-prasarana", middleware.RequirePermission(db, "UPDATE_INFORMASI_SEKOLAH"), saranaPrasaranaController.Update)
 
 		// Delete sarana prasarana
-		protected.POST("/delete-sarana-prasarana", saranaPrasaranaController.Delete)
+		protected.POST("/delete-sarana-prasarana", middleware.RequirePermission(db, "DELETE_INFORMASI_SEKOLAH"), saranaPrasaranaController.Delete)
 	}
 }
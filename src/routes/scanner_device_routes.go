@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"pintu-backend/src/middleware"
+	"pintu-backend/src/modules/controllers"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/modules/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterScannerDeviceRoutes registers all scanner device routes
+func RegisterScannerDeviceRoutes(router *gin.Engine, db *gorm.DB) {
+	// Initialize repository, service, and controller
+	scannerDeviceRepo := repositories.NewScannerDeviceRepository(db)
+	scannerDeviceService := services.NewScannerDeviceService(scannerDeviceRepo)
+	scannerDeviceController := controllers.NewScannerDeviceController(scannerDeviceService)
+
+	// Protected routes (auth required)
+	protected := router.Group("/api/v1/scanner-devices")
+	protected.Use(middleware.AuthMiddleware(db))
+	{
+		// Register scanner device (returns the credential once)
+		protected.POST("/create-scanner-device", middleware.RequirePermission(db, "CREATE_MASTER_DATA"), scannerDeviceController.Create)
+
+		// Get all scanner devices
+		protected.POST("/get-scanner-devices", middleware.RequirePermission(db, "READ_MASTER_DATA"), scannerDeviceController.GetAll)
+
+		// Get scanner device by ID
+		protected.POST("/get-scanner-device-by-id", middleware.RequirePermission(db, "READ_MASTER_DATA"), scannerDeviceController.GetByID)
+
+		// Update scanner device
+		protected.POST("/update-scanner-device", middleware.RequirePermission(db, "UPDATE_MASTER_DATA"), scannerDeviceController.Update)
+
+		// Rotate scanner device credential
+		protected.POST("/rotate-scanner-device-secret", middleware.RequirePermission(db, "UPDATE_MASTER_DATA"), scannerDeviceController.RotateSecret)
+
+		// Delete scanner device
+		protected.POST("/delete-scanner-device", middleware.RequirePermission(db, "DELETE_MASTER_DATA"), scannerDeviceController.Delete)
+	}
+}
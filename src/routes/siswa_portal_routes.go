@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"pintu-backend/src/middleware"
+	"pintu-backend/src/modules/controllers"
+	"pintu-backend/src/modules/repositories"
+	"pintu-backend/src/modules/services"
+	"pintu-backend/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterSiswaPortalRoutes registers the read-only routes of the logged-in peserta didik
+func RegisterSiswaPortalRoutes(router *gin.Engine, db *gorm.DB) {
+	// Initialize storage
+	storage := utils.NewStorage()
+
+	// Initialize repositories
+	pesertaDidikRepo := repositories.NewPesertaDidikRepository(db)
+	pesertaDidikRombelRepo := repositories.NewPesertaDidikRombelRepository(db)
+	tahunPelajaranRepo := repositories.NewTahunPelajaranRepository(db)
+	authTokenRepo := repositories.NewAuthTokenRepository(db)
+
+	// Reuse the existing services so the portal returns the same shapes as the admin pages
+	pesertaDidikService := services.NewPesertaDidikService(pesertaDidikRepo, authTokenRepo, storage)
+	pesertaDidikRombelService := services.NewPesertaDidikRombelService(pesertaDidikRombelRepo, pesertaDidikRepo, storage)
+	periodeService := services.NewPeriodeAkademikService(repositories.NewTahunPelajaranRepository(db))
+	kalenderService := services.NewKalenderAkademikService(repositories.NewKalenderAkademikRepository(db), repositories.NewKonfigurasiAbsensiRepository(db), periodeService)
+	absensiService := services.NewAbsensiService(repositories.NewAbsensiRepository(db), repositories.NewRiwayatRekapitulasiAbsensiRepository(db), kalenderService, periodeService, db)
+	prestasiService := services.NewPrestasiService(repositories.NewPrestasiRepository(db), periodeService, storage)
+
+	service := services.NewSiswaPortalService(pesertaDidikService, pesertaDidikRombelService, absensiService, prestasiService, pesertaDidikRombelRepo, tahunPelajaranRepo, repositories.NewKonfigurasiAbsensiRepository(db))
+	controller := controllers.NewSiswaPortalController(service)
+
+	// Siswa routes, every endpoint reads data of the caller only
+	api := router.Group("/api/v1/siswa")
+	api.Use(middleware.AuthMiddleware(db))
+	api.Use(middleware.RequirePrincipalType(utils.PrincipalSiswa))
+	{
+		api.POST("/get-profil", controller.GetProfil)
+		api.POST("/get-rombel-aktif", controller.GetRombelAktif)
+		api.POST("/get-riwayat-absensi", controller.GetRiwayatAbsensi)
+		api.POST("/get-prestasi", controller.GetPrestasi)
+		api.POST("/download-kartu-pelajar", controller.DownloadKartuPelajar)
+		api.POST("/get-kartu-qr", controller.GetKartuQR)
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"pintu-backend/src/modules/controllers"
+	"pintu-backend/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterStorageRoutes serves uploaded files when STORAGE_DRIVER=local; R2 serves its own public domain
+func RegisterStorageRoutes(router *gin.Engine, db *gorm.DB) {
+	localStorage, ok := utils.NewStorage().(*utils.LocalStorage)
+	if !ok {
+		return
+	}
+	controller := controllers.NewStorageController(localStorage)
+
+	// Public route like the R2 public domain, presigned URLs are verified by the controller
+	router.GET("/storage/*key", controller.ServeFile)
+}
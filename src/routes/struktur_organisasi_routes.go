@@ -26,21 +26,21 @@ func RegisterStrukturOrganisasiRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/struktur-organisasi")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
 		// Create struktur organisasi
-		protected.POST("/create-struktur-organisasi", strukturOrganisasiController.Create)
+		protected.POST("/create-struktur-organisasi", middleware.RequirePermission(db, "CREATE_INFORMASI_SEKOLAH"), strukturOrganisasiController.Create)
 
 		// Get all struktur organisasi
-		protected.POST("/get-struktur-organisasi", strukturOrganisasiController.GetAll)
+		protected.POST("/get-struktur-organisasi", middleware.RequirePermission(db, "READ_INFORMASI_SEKOLAH"), strukturOrganisasiController.GetAll)
 
 		// Get struktur organisasi by ID
-		protected.POST("/get-struktur-organisasi-by-id", strukturOrganisasiController.GetByID)
+		protected.POST("/get-struktur-organisasi-by-id", middleware.RequirePermission(db, "READ_INFORMASI_SEKOLAH"), strukturOrganisasiController.GetByID)
 
 		// Update struktur organisasi
-		protected.POST("/update-struktur-organisasi", strukturOrganisasiController.Update)
+		protected.POST("/update-struktur-organisasi", middleware.RequirePermission(db, "UPDATE_INFORMASI_SEKOLAH"), strukturOrganisasiController.Update)
 
 		// Delete struktur organisasi
-		protected.POST("/delete-struktur-organisasi", strukturOrganisasiController.Delete)
+		protected.POST("/delete-struktur-organisasi", middleware.RequirePermission(db, "DELETE_INFORMASI_SEKOLAH"), strukturOrganisasiController.Delete)
 	}
 }
@@ -19,12 +19,12 @@ func RegisterSystemRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Group routes under /api/v1/systems with auth middleware
 	api := router.Group("/api/v1/systems")
-	api.Use(middleware.AuthMiddleware()) // Require authentication
+	api.Use(middleware.AuthMiddleware(db)) // Require authentication
 	{
-		api.POST("/create-system", systemController.Create)      // Create system
-		api.POST("/get-systems", systemController.GetAll)        // Get all systems
-		api.POST("/get-system-by-id", systemController.GetByID)  // Get system by ID
-		api.POST("/update-system", systemController.Update)      // Update system
-		api.POST("/delete-system", systemController.Delete)      // Delete system
+		api.POST("/create-system", middleware.RequirePermission(db, "CREATE_MASTER_DATA"), systemController.Create) // Create system
+		api.POST("/get-systems", middleware.RequirePermission(db, "READ_MASTER_DATA"), systemController.GetAll) // Get all systems
+		api.POST("/get-system-by-id", middleware.RequirePermission(db, "READ_MASTER_DATA"), systemController.GetByID) // Get system by ID
+		api.POST("/update-system", middleware.RequirePermission(db, "UPDATE_MASTER_DATA"), systemController.Update) // Update system
+		api.POST("/delete-system", middleware.RequirePermission(db, "DELETE_MASTER_DATA"), systemController.Delete) // Delete system
 	}
 }
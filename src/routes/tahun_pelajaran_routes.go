@@ -26,21 +26,21 @@ func RegisterTahunPelajaranRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/tahun-pelajaran")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
 		// Create tahun pelajaran
-		protected.POST("/create-tahun-pelajaran", tahunPelajaranController.Create)
+		protected.POST("/create-tahun-pelajaran", middleware.RequirePermission(db, "CREATE_MASTER_DATA"), tahunPelajaranController.Create)
 
 		// Get all tahun pelajaran
-		protected.POST("/get-tahun-pelajaran", tahunPelajaranController.GetAll)
+		protected.POST("/get-tahun-pelajaran", middleware.RequirePermission(db, "READ_MASTER_DATA"), tahunPelajaranController.GetAll)
 
 		// Get tahun pelajaran by ID
-		protected.POST("/get-tahun-pelajaran-by-id", tahunPelajaranController.GetByID)
+		protected.POST("/get-tahun-pelajaran-by-id", middleware.RequirePermission(db, "READ_MASTER_DATA"), tahunPelajaranController.GetByID)
 
 		// Update tahun pelajaran
-		protected.POST("/update-tahun-pelajaran", tahunPelajaranController.Update)
+		protected.POST("/update-tahun-pelajaran", middleware.RequirePermission(db, "UPDATE_MASTER_DATA"), tahunPelajaranController.Update)
 
 		// Delete tahun pelajaran
-		protected.POST("/delete-tahun-pelajaran", tahunPelajaranController.Delete)
+		protected.POST("/delete-tahun-pelajaran", middleware.RequirePermission(db, "DELETE_MASTER_DATA"), tahunPelajaranController.Delete)
 	}
 }
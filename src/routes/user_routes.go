@@ -15,20 +15,24 @@ func RegisterUserRoutes(router *gin.Engine, db *gorm.DB) {
 	// Initialize repositories
 	userRepo := repositories.NewUserRepository(db)
 	roleRepo := repositories.NewRoleRepository(db)
+	authTokenRepo := repositories.NewAuthTokenRepository(db)
+	loginRepo := repositories.NewLoginRepository(db)
+	throttleService := services.NewThrottleService(repositories.NewFailedAttemptRepository(db))
 
-	// Initialize service with role validation
-	userService := services.NewUserServiceWithRole(userRepo, roleRepo)
+	// Initialize service with role validation, session revocation and account unlocking
+	userService := services.NewUserServiceWithRole(userRepo, roleRepo, authTokenRepo, loginRepo, throttleService)
 	userController := controllers.NewUserController(userService)
 
 	// Group routes under /api/v1/users with auth middleware
 	api := router.Group("/api/v1/users")
-	api.Use(middleware.AuthMiddleware()) // Require authentication
+	api.Use(middleware.AuthMiddleware(db)) // Require authentication
 	{
-		api.POST("/create-user", userController.Create)           // Create user
-		api.POST("/get-users", userController.GetAll)             // Get all users
-		api.POST("/get-user-by-id", userController.GetByID)             // Get user by ID
-		api.POST("/update-user", userController.Update)           // Update user
-		api.POST("/update-user-password", userController.UpdatePassword) // Update password
-		api.POST("/delete-user", userController.Delete)           // Delete user
+		api.POST("/create-user", middleware.RequirePermission(db, "CREATE_MASTER_DATA"), userController.Create) // Create user
+		api.POST("/get-users", middleware.RequirePermission(db, "READ_MASTER_DATA"), userController.GetAll) // Get all users
+		api.POST("/get-user-by-id", middleware.RequirePermission(db, "READ_MASTER_DATA"), userController.GetByID) // Get user by ID
+		api.POST("/update-user", middleware.RequirePermission(db, "UPDATE_MASTER_DATA"), userController.Update) // Update user
+		api.POST("/update-user-password", middleware.RequirePermission(db, "UPDATE_MASTER_DATA"), userController.UpdatePassword) // Update password
+		api.POST("/delete-user", middleware.RequirePermission(db, "DELETE_MASTER_DATA"), userController.Delete) // Delete user
+		api.POST("/unlock-user", middleware.RequirePermission(db, "UPDATE_MASTER_DATA"), userController.Unlock) // Lift login lockout
 	}
 }
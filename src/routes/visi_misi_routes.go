@@ -25,21 +25,21 @@ func RegisterVisiMisiRoutes(router *gin.Engine, db *gorm.DB) {
 
 	// Protected routes (auth required)
 	protected := router.Group("/api/v1/visi-misi")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(db))
 	{
 		// Create visi misi
-		protected.POST("/create-visi-misi", visiMisiController.Create)
+		protected.POST("/create-visi-misi", middleware.RequirePermission(db, "CREATE_INFORMASI_SEKOLAH"), visiMisiController.Create)
 
 		// Get all visi misi
-		protected.POST("/get-visi-misi", visiMisiController.GetAll)
+		protected.POST("/get-visi-misi", middleware.RequirePermission(db, "READ_INFORMASI_SEKOLAH"), visiMisiController.GetAll)
 
 		// Get visi misi by ID
-		protected.POST("/get-visi-misi-by-id", visiMisiController.GetByID)
+		protected.POST("/get-visi-misi-by-id", middleware.RequirePermission(db, "READ_INFORMASI_SEKOLAH"), visiMisiController.GetByID)
 
 		// Update visi misi
-		protected.POST("/update-visi-misi", visiMisiController.Update)
+		protected.POST("/update-visi-misi", middleware.RequirePermission(db, "UPDATE_INFORMASI_SEKOLAH"), visiMisiController.Update)
 
 		// Delete visi misi
-		protected.POST("/delete-visi-misi", visiMisiController.Delete)
+		protected.POST("/delete-visi-misi", middleware.RequirePermission(db, "DELETE_INFORMASI_SEKOLAH"), visiMisiController.Delete)
 	}
 }
@@ -0,0 +1,85 @@
+package utils
+
+import "sync"
+
+// absensiSubscriberBuffer is how many events a slow SSE client may lag behind before events are dropped for it
+const absensiSubscriberBuffer = 64
+
+// AbsensiEvent is a real-time attendance event, streamed to SSE clients as "event: <Name>" with Data as JSON
+type AbsensiEvent struct {
+	Name     string
+	RombelID *uint // Nil for students without an active rombel
+	Data     interface{}
+}
+
+// AbsensiHub is an in-process pub/sub hub for attendance events. Publishing never blocks the scan:
+// subscribers that do not keep up miss events instead.
+type AbsensiHub struct {
+	mu          sync.RWMutex
+	nextID      int
+	subscribers map[int]absensiSubscriber
+}
+
+type absensiSubscriber struct {
+	rombelID *uint
+	events   chan AbsensiEvent
+}
+
+var defaultAbsensiHub = NewAbsensiHub()
+
+// NewAbsensiHub creates an empty hub
+func NewAbsensiHub() *AbsensiHub {
+	return &AbsensiHub{subscribers: make(map[int]absensiSubscriber)}
+}
+
+// DefaultAbsensiHub returns the hub shared by the scan endpoints and the live feed of this process
+func DefaultAbsensiHub() *AbsensiHub {
+	return defaultAbsensiHub
+}
+
+// Subscribe returns a channel of events, optionally of one rombel only, and the function that ends the subscription
+func (h *AbsensiHub) Subscribe(rombelID *uint) (<-chan AbsensiEvent, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	id := h.nextID
+	h.nextID++
+	subscriber := absensiSubscriber{
+		rombelID: rombelID,
+		events:   make(chan AbsensiEvent, absensiSubscriberBuffer),
+	}
+	h.subscribers[id] = subscriber
+
+	var once sync.Once
+	return subscriber.events, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			delete(h.subscribers, id)
+			close(subscriber.events)
+		})
+	}
+}
+
+// Publish delivers an event to every matching subscriber without waiting
+func (h *AbsensiHub) Publish(event AbsensiEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, subscriber := range h.subscribers {
+		if subscriber.rombelID != nil && (event.RombelID == nil || *event.RombelID != *subscriber.rombelID) {
+			continue
+		}
+		select {
+		case subscriber.events <- event:
+		default:
+		}
+	}
+}
+
+// TotalSubscribers returns the number of connected subscribers
+func (h *AbsensiHub) TotalSubscribers() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subscribers)
+}
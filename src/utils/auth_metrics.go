@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// Accounts locked after too many failed logins
+	AccountLockoutsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_account_lockouts_total",
+			Help: "Total number of accounts temporarily locked after repeated failed logins",
+		},
+		[]string{"principal_type"},
+	)
+
+	// Attempts rejected by the sliding window throttle
+	ThrottledAttemptsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_throttled_attempts_total",
+			Help: "Total number of authentication attempts rejected by throttling",
+		},
+		[]string{"scope", "key"},
+	)
+)
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Scanner device authentication modes
+const (
+	DeviceAuthAPIKey = "api_key"
+	DeviceAuthHMAC   = "hmac"
+)
+
+// GenerateDeviceSecret returns a random API key or HMAC secret for a scanner device
+func GenerateDeviceSecret() (string, error) {
+	return randomToken(32)
+}
+
+// HashDeviceKey hashes a device API key so the raw value never reaches the database
+func HashDeviceKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// SignDeviceRequest computes the hex HMAC-SHA256 a scanner device sends in X-Signature.
+// The signed payload is "<timestamp>\n<METHOD>\n<path>\n<sha256 hex of body>".
+func SignDeviceRequest(secret, timestamp, method, path string, body []byte) string {
+	bodySum := sha256.Sum256(body)
+	payload := strings.Join([]string{
+		timestamp,
+		strings.ToUpper(method),
+		path,
+		hex.EncodeToString(bodySum[:]),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyDeviceSignature reports whether signature matches the request in constant time
+func VerifyDeviceSignature(secret, signature, timestamp, method, path string, body []byte) bool {
+	expected := SignDeviceRequest(secret, timestamp, method, path, body)
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(signature)))
+}
@@ -88,6 +88,22 @@ func (e *EmailService) SendPertanyaanReply(to string, data EmailData) error {
 	return nil
 }
 
+// SendPlainText sends a plain text email, e.g. a rendered notifikasi template
+func (e *EmailService) SendPlainText(to, subject, body string) error {
+	m := gomail.NewMessage()
+	m.SetHeader("From", fmt.Sprintf("%s <%s>", e.fromName, e.fromEmail))
+	m.SetHeader("To", to)
+	m.SetHeader("Subject", subject)
+	m.SetBody("text/plain", body)
+
+	d := gomail.NewDialer(e.smtpHost, e.smtpPort, e.smtpUsername, e.smtpPassword)
+	if err := d.DialAndSend(m); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
 // generateEmailHTML generates HTML email body
 func (e *EmailService) generateEmailHTML(data EmailData) (string, error) {
 	tmpl := `
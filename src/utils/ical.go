@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// ICalEvent is an all-day or timed VEVENT read from an iCalendar (.ics) file
+type ICalEvent struct {
+	UID            string
+	Summary        string
+	Description    string
+	TanggalMulai   time.Time // first day of the event
+	TanggalSelesai time.Time // last day of the event (inclusive)
+}
+
+// ParseICalEvents reads the VEVENTs of an iCalendar file such as the Google Calendar
+// export of Indonesian national holidays. Only UID, SUMMARY, DESCRIPTION, DTSTART and
+// DTEND are used; recurrence rules are not expanded.
+func ParseICalEvents(r io.Reader) ([]ICalEvent, error) {
+	lines, err := unfoldICalLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []ICalEvent
+	var current *ICalEvent
+	var hasEnd bool
+
+	for _, line := range lines {
+		switch {
+		case line == "BEGIN:VEVENT":
+			current = &ICalEvent{}
+			hasEnd = false
+		case line == "END:VEVENT":
+			if current == nil {
+				continue
+			}
+			if current.TanggalMulai.IsZero() {
+				return nil, errors.New("event tanpa DTSTART: " + current.Summary)
+			}
+			if !hasEnd || current.TanggalSelesai.Before(current.TanggalMulai) {
+				current.TanggalSelesai = current.TanggalMulai
+			}
+			events = append(events, *current)
+			current = nil
+		case current != nil:
+			name, params, value := splitICalProperty(line)
+			switch name {
+			case "UID":
+				current.UID = value
+			case "SUMMARY":
+				current.Summary = unescapeICalText(value)
+			case "DESCRIPTION":
+				current.Description = unescapeICalText(value)
+			case "DTSTART":
+				date, _, err := parseICalDate(value, params)
+				if err != nil {
+					return nil, err
+				}
+				current.TanggalMulai = date
+			case "DTEND":
+				date, allDay, err := parseICalDate(value, params)
+				if err != nil {
+					return nil, err
+				}
+				// DTEND of an all-day event is exclusive
+				if allDay {
+					date = date.AddDate(0, 0, -1)
+				}
+				current.TanggalSelesai = date
+				hasEnd = true
+			}
+		}
+	}
+
+	return events, nil
+}
+
+// unfoldICalLines joins continuation lines (RFC 5545 section 3.1)
+func unfoldICalLines(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// splitICalProperty splits "DTSTART;VALUE=DATE:20261225" into its name, parameters and value
+func splitICalProperty(line string) (string, map[string]string, string) {
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return "", nil, ""
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	params := make(map[string]string, len(parts)-1)
+	for _, part := range parts[1:] {
+		if eq := strings.Index(part, "="); eq > 0 {
+			params[strings.ToUpper(part[:eq])] = part[eq+1:]
+		}
+	}
+	return strings.ToUpper(parts[0]), params, line[colon+1:]
+}
+
+// parseICalDate parses DATE and DATE-TIME values into a calendar date in Asia/Jakarta
+func parseICalDate(value string, params map[string]string) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == 8 {
+		date, err := time.Parse("20060102", value)
+		if err != nil {
+			return time.Time{}, false, errors.New("format tanggal iCal tidak valid: " + value)
+		}
+		return date, true, nil
+	}
+
+	var parsed time.Time
+	var err error
+	if strings.HasSuffix(value, "Z") {
+		parsed, err = time.Parse("20060102T150405Z", value)
+	} else {
+		loc := JakartaLocation()
+		if tzid := params["TZID"]; tzid != "" {
+			if tz, tzErr := time.LoadLocation(tzid); tzErr == nil {
+				loc = tz
+			}
+		}
+		parsed, err = time.ParseInLocation("20060102T150405", value, loc)
+	}
+	if err != nil {
+		return time.Time{}, false, errors.New("format tanggal iCal tidak valid: " + value)
+	}
+
+	local := parsed.In(JakartaLocation())
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC), false, nil
+}
+
+// unescapeICalText reverses the TEXT escaping of RFC 5545 section 3.3.11
+func unescapeICalText(value string) string {
+	replacer := strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return replacer.Replace(value)
+}
@@ -0,0 +1,272 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path"
+	"strings"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Image variant names recorded with an upload
+const (
+	ImageVariantThumbnail = "thumbnail"
+	ImageVariantMedium    = "medium"
+	ImageVariantOriginal  = "original"
+)
+
+// imageVariantSizes is the longest side in pixels of each resized variant
+var imageVariantSizes = []struct {
+	name    string
+	maxSide int
+}{
+	{ImageVariantThumbnail, 320},
+	{ImageVariantMedium, 1280},
+}
+
+const (
+	// maxImagePixels rejects decompression bombs before decoding
+	maxImagePixels = 50_000_000
+	jpegQuality    = 85
+)
+
+// imageNeedsProcessing reports whether an image of contentType is re-encoded under rule, and so is read in
+// memory instead of streamed to storage
+func imageNeedsProcessing(contentType string, rule UploadRule) bool {
+	// GIFs may be animated and carry no EXIF, they are kept as uploaded
+	return strings.HasPrefix(contentType, "image/") && contentType != "image/gif" && (rule.StripMetadata || rule.Variants)
+}
+
+// readImageConfig reads the dimensions of an image without decoding it, rejecting decompression bombs
+func readImageConfig(r io.Reader) (image.Config, error) {
+	config, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return image.Config{}, errors.New("gambar tidak dapat dibaca")
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return image.Config{}, errors.New("resolusi gambar terlalu besar")
+	}
+	return config, nil
+}
+
+// processImage re-encodes an image upload (applying and dropping EXIF orientation and metadata) and adds
+// resized variants when the rule asks for them
+func processImage(upload *PreparedUpload, rule UploadRule) error {
+	if _, err := readImageConfig(bytes.NewReader(upload.Content)); err != nil {
+		return err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(upload.Content))
+	if err != nil {
+		return errors.New("gambar tidak dapat dibaca")
+	}
+	if upload.ContentType == "image/jpeg" {
+		img = orientImage(img, jpegOrientation(upload.Content))
+	}
+
+	content, contentType, err := encodeImage(img, upload.ContentType)
+	if err != nil {
+		return err
+	}
+	upload.Content = content
+	upload.Size = int64(len(content))
+	upload.Filename = replaceImageExtension(upload.Filename, contentType)
+	upload.ContentType = contentType
+	upload.Width, upload.Height = img.Bounds().Dx(), img.Bounds().Dy()
+
+	if !rule.Variants {
+		return nil
+	}
+	for _, size := range imageVariantSizes {
+		resized := resizeImage(img, size.maxSide)
+		if resized == img {
+			// Already small enough, the variant points at the original
+			upload.Variants = append(upload.Variants, PreparedVariant{Name: size.name, Width: upload.Width, Height: upload.Height})
+			continue
+		}
+		content, contentType, err := encodeImage(resized, upload.ContentType)
+		if err != nil {
+			return err
+		}
+		upload.Variants = append(upload.Variants, PreparedVariant{
+			Name:        size.name,
+			Filename:    upload.Filename,
+			Content:     content,
+			ContentType: contentType,
+			Width:       resized.Bounds().Dx(),
+			Height:      resized.Bounds().Dy(),
+		})
+	}
+	return nil
+}
+
+// encodeImage encodes PNG sources as PNG and everything else as JPEG, or PNG when it has transparency
+func encodeImage(img image.Image, sourceType string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if sourceType == "image/png" || !isOpaque(img) {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/png", nil
+	}
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/jpeg", nil
+}
+
+// resizeImage scales an image down so its longest side is at most maxSide, smaller images are returned as is
+func resizeImage(img image.Image, maxSide int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxSide && height <= maxSide {
+		return img
+	}
+
+	if width >= height {
+		height = max(1, height*maxSide/width)
+		width = maxSide
+	} else {
+		width = max(1, width*maxSide/height)
+		height = maxSide
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// orientImage rotates and flips an image according to its EXIF orientation (1-8). The pixels are
+// copied on RGBA buffers directly, going through image.Image per pixel is too slow for photos.
+func orientImage(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	src, ok := img.(*image.RGBA)
+	if !ok {
+		src = image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+	}
+	srcMin := src.Bounds().Min
+
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		row := dst.Pix[y*dst.Stride : y*dst.Stride+dstWidth*4]
+		for x := 0; x < dstWidth; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = width-1-x, y
+			case 3:
+				sx, sy = width-1-x, height-1-y
+			case 4:
+				sx, sy = x, height-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, height-1-x
+			case 7:
+				sx, sy = width-1-y, height-1-x
+			case 8:
+				sx, sy = width-1-y, x
+			}
+			i := src.PixOffset(srcMin.X+sx, srcMin.Y+sy)
+			copy(row[x*4:x*4+4], src.Pix[i:i+4])
+		}
+	}
+	return dst
+}
+
+// jpegOrientation returns the EXIF orientation of a JPEG, or 1 when it has none
+func jpegOrientation(content []byte) int {
+	if len(content) < 4 || content[0] != 0xFF || content[1] != 0xD8 {
+		return 1
+	}
+
+	for offset := 2; offset+4 <= len(content); {
+		if content[offset] != 0xFF {
+			return 1
+		}
+		marker := content[offset+1]
+		length := int(binary.BigEndian.Uint16(content[offset+2:]))
+		// Start of scan: metadata segments come before the image data
+		if marker == 0xDA || length < 2 || offset+2+length > len(content) {
+			return 1
+		}
+
+		segment := content[offset+4 : offset+2+length]
+		if marker == 0xE1 && len(segment) > 14 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+		offset += 2 + length
+	}
+	return 1
+}
+
+// tiffOrientation reads the orientation tag (0x0112) of the first IFD of an EXIF TIFF block
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 1
+}
+
+// isOpaque reports whether an image has no transparent pixels
+func isOpaque(img image.Image) bool {
+	if opaque, ok := img.(interface{ Opaque() bool }); ok {
+		return opaque.Opaque()
+	}
+	return true
+}
+
+// replaceImageExtension makes the extension of filename match a re-encoded content type
+func replaceImageExtension(filename, contentType string) string {
+	ext := ".jpg"
+	if contentType == "image/png" {
+		ext = ".png"
+	}
+	current := strings.ToLower(path.Ext(filename))
+	if current == ext || (ext == ".jpg" && current == ".jpeg") {
+		return filename
+	}
+	return strings.TrimSuffix(filename, path.Ext(filename)) + ext
+}
@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"encoding/binary"
+	"image"
+	"testing"
+)
+
+func TestOrientImage(t *testing.T) {
+	// 3x2 YCbCr source like a decoded JPEG, each pixel identified by its gray value:
+	//   10 20 30
+	//   40 50 60
+	src := image.NewYCbCr(image.Rect(0, 0, 3, 2), image.YCbCrSubsampleRatio444)
+	for i, v := range []uint8{10, 20, 30, 40, 50, 60} {
+		src.Y[src.YOffset(i%3, i/3)] = v
+		src.Cb[src.COffset(i%3, i/3)] = 128
+		src.Cr[src.COffset(i%3, i/3)] = 128
+	}
+
+	tests := []struct {
+		orientation int
+		want        [][]uint8 // rows of the oriented image
+	}{
+		{1, [][]uint8{{10, 20, 30}, {40, 50, 60}}},
+		{2, [][]uint8{{30, 20, 10}, {60, 50, 40}}},
+		{3, [][]uint8{{60, 50, 40}, {30, 20, 10}}},
+		{4, [][]uint8{{40, 50, 60}, {10, 20, 30}}},
+		{5, [][]uint8{{10, 40}, {20, 50}, {30, 60}}},
+		{6, [][]uint8{{40, 10}, {50, 20}, {60, 30}}},
+		{7, [][]uint8{{60, 30}, {50, 20}, {40, 10}}},
+		{8, [][]uint8{{30, 60}, {20, 50}, {10, 40}}},
+		{9, [][]uint8{{10, 20, 30}, {40, 50, 60}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(rune('0'+tt.orientation)), func(t *testing.T) {
+			got := orientImage(src, tt.orientation)
+			bounds := got.Bounds()
+			if bounds.Dy() != len(tt.want) || bounds.Dx() != len(tt.want[0]) {
+				t.Fatalf("size = %dx%d, want %dx%d", bounds.Dx(), bounds.Dy(), len(tt.want[0]), len(tt.want))
+			}
+			for y, row := range tt.want {
+				for x, want := range row {
+					r, _, _, _ := got.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+					if uint8(r>>8) != want {
+						t.Errorf("pixel (%d,%d) = %d, want %d", x, y, r>>8, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+// testTIFF builds an EXIF TIFF block whose first IFD holds the given tag/value pairs
+func testTIFF(order binary.ByteOrder, tags ...uint16) []byte {
+	entries := len(tags) / 2
+	tiff := make([]byte, 8+2+entries*12)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+
+	order.PutUint16(tiff[8:], uint16(entries))
+	for i := 0; i < entries; i++ {
+		entry := tiff[10+i*12:]
+		order.PutUint16(entry, tags[2*i])
+		order.PutUint16(entry[2:], 3) // SHORT
+		order.PutUint32(entry[4:], 1)
+		order.PutUint16(entry[8:], tags[2*i+1])
+	}
+	return tiff
+}
+
+// testJPEG builds the header of a JPEG made of the given segments, up to the start of scan
+func testJPEG(segments ...[]byte) []byte {
+	content := []byte{0xFF, 0xD8}
+	for _, segment := range segments {
+		content = append(content, segment...)
+	}
+	return append(content, 0xFF, 0xDA, 0x00, 0x02)
+}
+
+// testSegment builds a JPEG marker segment
+func testSegment(marker byte, payload []byte) []byte {
+	segment := []byte{0xFF, marker}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+func TestTIFFOrientation(t *testing.T) {
+	truncated := testTIFF(binary.BigEndian, 0x0100, 640, 0x0112, 6)
+
+	tests := []struct {
+		name string
+		tiff []byte
+		want int
+	}{
+		{"little endian", testTIFF(binary.LittleEndian, 0x0112, 6), 6},
+		{"big endian", testTIFF(binary.BigEndian, 0x0112, 8), 8},
+		{"after other tags", testTIFF(binary.BigEndian, 0x0100, 640, 0x0101, 480, 0x0112, 3), 3},
+		{"no orientation tag", testTIFF(binary.LittleEndian, 0x0100, 640), 1},
+		{"unknown byte order", append([]byte("XX"), testTIFF(binary.LittleEndian, 0x0112, 6)[2:]...), 1},
+		{"ifd past the end", []byte{'I', 'I', 42, 0, 0xFF, 0, 0, 0}, 1},
+		{"truncated entries", truncated[:len(truncated)-6], 1},
+		{"shorter than the header", []byte("II*"), 1},
+		{"empty", nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tiffOrientation(tt.tiff); got != tt.want {
+				t.Errorf("tiffOrientation() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	exif := func(tiff []byte) []byte {
+		return testSegment(0xE1, append([]byte("Exif\x00\x00"), tiff...))
+	}
+	jfif := testSegment(0xE0, []byte("JFIF\x00\x01\x01\x00\x00\x01\x00\x01\x00\x00"))
+	rotated := exif(testTIFF(binary.BigEndian, 0x0112, 6))
+
+	tests := []struct {
+		name    string
+		content []byte
+		want    int
+	}{
+		{"exif segment", testJPEG(rotated), 6},
+		{"exif after jfif", testJPEG(jfif, rotated), 6},
+		{"no exif", testJPEG(jfif), 1},
+		{"xmp in app1", testJPEG(testSegment(0xE1, []byte("http://ns.adobe.com/xap/1.0/\x00<x/>"))), 1},
+		{"exif after start of scan", append(testJPEG(jfif), rotated...), 1},
+		{"segment past the end", testJPEG(jfif, rotated)[:len(testJPEG(jfif))+10], 1},
+		{"not a jpeg", []byte("\x89PNG\r\n\x1a\n"), 1},
+		{"empty", nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jpegOrientation(tt.content); got != tt.want {
+				t.Errorf("jpegOrientation() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
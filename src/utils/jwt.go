@@ -1,38 +1,69 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	// AccessTokenTTL is kept short because access tokens are only revocable through the jti denylist
+	AccessTokenTTL = 15 * time.Minute
+	// RefreshTokenTTL bounds how long a session can be kept alive through rotation
+	RefreshTokenTTL = 7 * 24 * time.Hour
+)
+
 // JWTClaims represents JWT token claims
 type JWTClaims struct {
-	UserID   uint   `json:"user_id"`
-	Username string `json:"username"`
-	Nama     string `json:"nama"`
-	RoleID   *uint  `json:"role_id"`
-	Status   string `json:"status"`
+	UserID        uint   `json:"user_id"`
+	PrincipalType string `json:"principal_type"`
+	Username      string `json:"username"`
+	Nama          string `json:"nama"`
+	RoleID        *uint  `json:"role_id"`
+	Status        string `json:"status"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken generates JWT token
-func GenerateToken(userID uint, username, nama string, roleID *uint, status string) (string, error) {
+// Principal returns the typed principal described by the claims
+func (c *JWTClaims) Principal() Principal {
+	return Principal{
+		ID:       c.UserID,
+		Type:     c.PrincipalType,
+		Username: c.Username,
+		Nama:     c.Nama,
+		RoleID:   c.RoleID,
+		Status:   c.Status,
+	}
+}
+
+// GenerateToken generates a short-lived JWT access token with a unique jti
+func GenerateToken(principalType string, userID uint, username, nama string, roleID *uint, status string) (string, *JWTClaims, error) {
 	secretKey := os.Getenv("JWT_SECRET")
 	if secretKey == "" {
 		secretKey = "your-secret-key-change-this-in-production"
 	}
 
-	expirationTime := time.Now().Add(24 * time.Hour) // Token valid for 24 hours
+	jti, err := randomToken(16)
+	if err != nil {
+		return "", nil, err
+	}
+
+	expirationTime := time.Now().Add(AccessTokenTTL)
 
 	claims := &JWTClaims{
-		UserID:   userID,
-		Username: username,
-		Nama:     nama,
-		RoleID:   roleID,
-		Status:   status,
+		UserID:        userID,
+		PrincipalType: principalType,
+		Username:      username,
+		Nama:          nama,
+		RoleID:        roleID,
+		Status:        status,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
@@ -40,7 +71,11 @@ func GenerateToken(userID uint, username, nama string, roleID *uint, status stri
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secretKey))
+	signed, err := token.SignedString([]byte(secretKey))
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
 }
 
 // VerifyToken verifies JWT token and returns claims
@@ -63,5 +98,34 @@ func VerifyToken(tokenString string) (*JWTClaims, error) {
 		return nil, jwt.ErrSignatureInvalid
 	}
 
+	// Tokens issued before principal types and jti existed cannot be attributed or revoked
+	if !IsValidPrincipalType(claims.PrincipalType) || claims.ID == "" {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+
 	return claims, nil
 }
+
+// GenerateRefreshToken returns an opaque refresh token and the hash to store for it
+func GenerateRefreshToken() (string, string, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken hashes a refresh token so the raw value never reaches the database
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomToken returns n random bytes encoded as URL-safe base64
+func randomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
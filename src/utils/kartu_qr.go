@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// KartuQRPrefix marks a rotating signed kartu pelajar payload, as opposed to a printed static barcode
+	KartuQRPrefix = "PQR1"
+	// KartuQRTTL is how long a signed payload shown in the portal siswa can be scanned
+	KartuQRTTL = 60 * time.Second
+	// KartuQRClockSkew tolerates phone, scanner and server clocks drifting apart
+	KartuQRClockSkew = 30 * time.Second
+)
+
+// Kartu QR verification errors
+var (
+	ErrKartuQRFormat    = errors.New("format QR kartu pelajar tidak valid")
+	ErrKartuQRSignature = errors.New("tanda tangan QR kartu pelajar tidak valid")
+	ErrKartuQRExpired   = errors.New("QR kartu pelajar sudah kedaluwarsa, muat ulang QR di portal siswa")
+)
+
+// KartuQRClaims is the content of a verified signed kartu pelajar payload
+type KartuQRClaims struct {
+	PesertaDidikID uint
+	IssuedAt       time.Time
+	Nonce          string
+}
+
+// IsKartuQR reports whether a scanned value is a signed kartu pelajar payload
+func IsKartuQR(value string) bool {
+	return strings.HasPrefix(value, KartuQRPrefix+".")
+}
+
+// SignKartuQR builds "PQR1.<peserta didik id>.<unix seconds>.<nonce>.<signature>" where the signature
+// is the base64url HMAC-SHA256 of everything before it
+func SignKartuQR(pesertaDidikID uint, issuedAt time.Time) (string, error) {
+	nonce, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
+	payload := fmt.Sprintf("%s.%d.%d.%s", KartuQRPrefix, pesertaDidikID, issuedAt.Unix(), nonce)
+	return payload + "." + signKartuQR(payload), nil
+}
+
+// VerifyKartuQR checks the signature of a payload and that it was issued within ttl before at,
+// tolerating clocks up to skew apart. The nonce must still be checked for replay by the caller.
+func VerifyKartuQR(value string, at time.Time, ttl, skew time.Duration) (*KartuQRClaims, error) {
+	parts := strings.Split(value, ".")
+	if len(parts) != 5 || parts[0] != KartuQRPrefix {
+		return nil, ErrKartuQRFormat
+	}
+
+	payload := strings.Join(parts[:4], ".")
+	if !hmac.Equal([]byte(signKartuQR(payload)), []byte(parts[4])) {
+		return nil, ErrKartuQRSignature
+	}
+
+	id, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil || id == 0 {
+		return nil, ErrKartuQRFormat
+	}
+	unix, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, ErrKartuQRFormat
+	}
+	if parts[3] == "" {
+		return nil, ErrKartuQRFormat
+	}
+
+	issuedAt := time.Unix(unix, 0)
+	if issuedAt.After(at.Add(skew)) || at.Sub(issuedAt) > ttl+skew {
+		return nil, ErrKartuQRExpired
+	}
+
+	return &KartuQRClaims{
+		PesertaDidikID: uint(id),
+		IssuedAt:       issuedAt,
+		Nonce:          parts[3],
+	}, nil
+}
+
+// signKartuQR signs a payload with KARTU_QR_SECRET, falling back to JWT_SECRET
+func signKartuQR(payload string) string {
+	secretKey := os.Getenv("KARTU_QR_SECRET")
+	if secretKey == "" {
+		secretKey = os.Getenv("JWT_SECRET")
+	}
+	if secretKey == "" {
+		secretKey = "your-secret-key-change-this-in-production"
+	}
+
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
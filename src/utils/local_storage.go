@@ -0,0 +1,284 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var _ Storage = (*LocalStorage)(nil)
+
+// LocalStorage keeps files on the local disk for on-prem and development use. Files are served by the
+// GET /storage/*key route; presigned URLs carry an HMAC signature checked by that route.
+type LocalStorage struct {
+	baseDir   string
+	publicURL string
+	secret    string
+}
+
+// NewLocalStorage initializes local storage from STORAGE_LOCAL_DIR (default "storage") and
+// STORAGE_LOCAL_PUBLIC_URL (default http://localhost:$PORT/storage)
+func NewLocalStorage() *LocalStorage {
+	baseDir := os.Getenv("STORAGE_LOCAL_DIR")
+	if baseDir == "" {
+		baseDir = "storage"
+	}
+
+	publicURL := os.Getenv("STORAGE_LOCAL_PUBLIC_URL")
+	if publicURL == "" {
+		port := os.Getenv("PORT")
+		if port == "" {
+			port = "3000"
+		}
+		publicURL = fmt.Sprintf("http://localhost:%s/storage", port)
+	}
+
+	secret := os.Getenv("STORAGE_SIGNING_SECRET")
+	if secret == "" {
+		secret = os.Getenv("JWT_SECRET")
+	}
+
+	return &LocalStorage{
+		baseDir:   baseDir,
+		publicURL: strings.TrimRight(publicURL, "/"),
+		secret:    secret,
+	}
+}
+
+// UploadFile stores an uploaded file on disk
+func (l *LocalStorage) UploadFile(file *multipart.FileHeader, directory string) (string, error) {
+	return uploadMultipartFile(l, file, directory)
+}
+
+// UploadBytes stores content on disk under a new key inside directory
+func (l *LocalStorage) UploadBytes(content []byte, filename, contentType, directory string) (string, error) {
+	return l.UploadReader(context.Background(), bytes.NewReader(content), int64(len(content)), filename, contentType, directory)
+}
+
+// UploadReader copies size bytes of body to disk under a new key inside directory. A partially written
+// file is removed when copying fails or ctx is canceled.
+func (l *LocalStorage) UploadReader(ctx context.Context, body io.ReaderAt, size int64, filename, contentType, directory string) (string, error) {
+	fileKey := storageObjectKey(directory, filename)
+	filePath, err := l.filePath(fileKey)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	dst, err := os.Create(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+	_, err = io.Copy(dst, &contextReader{ctx: ctx, reader: io.NewSectionReader(body, 0, size)})
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(filePath)
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return fileKey, nil
+}
+
+// DeleteFile removes a file from disk, a missing file is not an error
+func (l *LocalStorage) DeleteFile(fileKey string) error {
+	filePath, err := l.filePath(fileKey)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// CopyFile copies a file to another key, overwriting an existing file
+func (l *LocalStorage) CopyFile(srcKey, dstKey string) error {
+	reader, _, err := l.Download(srcKey)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	dstPath, err := l.filePath(dstKey)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0o755); err != nil {
+		return fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if _, err := io.Copy(dst, reader); err != nil {
+		dst.Close()
+		return fmt.Errorf("failed to copy file: %w", err)
+	}
+	return dst.Close()
+}
+
+// GetPublicURL returns the URL of a file served by the storage route
+func (l *LocalStorage) GetPublicURL(fileKey string) string {
+	if fileKey == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", l.publicURL, fileKey)
+}
+
+// GetPresignedURL returns a URL with an expiry and signature that the storage route verifies
+func (l *LocalStorage) GetPresignedURL(fileKey string, expirationMinutes int) (string, error) {
+	if _, err := l.filePath(fileKey); err != nil {
+		return "", err
+	}
+	expires := time.Now().Add(time.Duration(expirationMinutes) * time.Minute).Unix()
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", l.sign(fileKey, expires))
+	return fmt.Sprintf("%s/%s?%s", l.publicURL, fileKey, query.Encode()), nil
+}
+
+// VerifyPresigned checks the expires and signature query values of a presigned URL
+func (l *LocalStorage) VerifyPresigned(fileKey, expires, signature string, at time.Time) bool {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || at.Unix() > expiresAt {
+		return false
+	}
+	return hmac.Equal([]byte(l.sign(fileKey, expiresAt)), []byte(signature))
+}
+
+// Stat returns the size, content type and modification time of a file
+func (l *LocalStorage) Stat(fileKey string) (*StorageObjectInfo, error) {
+	filePath, err := l.filePath(fileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrStorageObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	if info.IsDir() {
+		return nil, ErrStorageObjectNotFound
+	}
+
+	return &StorageObjectInfo{
+		Key:          fileKey,
+		Size:         info.Size(),
+		ContentType:  contentTypeByKey(fileKey),
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+// Download opens a file for reading, the caller closes the reader
+func (l *LocalStorage) Download(fileKey string) (io.ReadCloser, *StorageObjectInfo, error) {
+	info, err := l.Stat(fileKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	filePath, _ := l.filePath(fileKey)
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return file, info, nil
+}
+
+// List returns the files whose key starts with prefix, ordered by key
+func (l *LocalStorage) List(prefix string) ([]StorageObjectInfo, error) {
+	// Walk the deepest directory contained in the prefix
+	root := l.baseDir
+	if dir := path.Dir(prefix + "x"); dir != "." {
+		dirPath, err := l.filePath(dir)
+		if err != nil {
+			return nil, err
+		}
+		root = dirPath
+	}
+
+	var objects []StorageObjectInfo
+	err := filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(l.baseDir, filePath)
+		if err != nil {
+			return err
+		}
+		fileKey := filepath.ToSlash(rel)
+		if !strings.HasPrefix(fileKey, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, StorageObjectInfo{
+			Key:          fileKey,
+			Size:         info.Size(),
+			ContentType:  contentTypeByKey(fileKey),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	return objects, nil
+}
+
+// filePath maps a key to a path inside baseDir, rejecting keys that would escape it
+func (l *LocalStorage) filePath(fileKey string) (string, error) {
+	cleaned := strings.TrimPrefix(path.Clean("/"+fileKey), "/")
+	if fileKey == "" || cleaned == "" || cleaned != strings.TrimPrefix(fileKey, "/") {
+		return "", fmt.Errorf("invalid storage key: %q", fileKey)
+	}
+	return filepath.Join(l.baseDir, filepath.FromSlash(cleaned)), nil
+}
+
+// sign computes the presigned URL signature of a key and expiry
+func (l *LocalStorage) sign(fileKey string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(l.secret))
+	mac.Write([]byte(fmt.Sprintf("%s|%d", fileKey, expires)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// contentTypeByKey guesses the content type from the file extension
+func contentTypeByKey(fileKey string) string {
+	if contentType := mime.TypeByExtension(path.Ext(fileKey)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
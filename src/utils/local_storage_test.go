@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalStorageFilePath(t *testing.T) {
+	storage := &LocalStorage{baseDir: filepath.Join("tmp", "storage")}
+
+	tests := []struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{"artikel/foto.jpg", filepath.Join("tmp", "storage", "artikel", "foto.jpg"), false},
+		{"/artikel/foto.jpg", filepath.Join("tmp", "storage", "artikel", "foto.jpg"), false},
+		{"foto.jpg", filepath.Join("tmp", "storage", "foto.jpg"), false},
+		{"../foto.jpg", "", true},
+		{"artikel/../../foto.jpg", "", true},
+		{"artikel/../foto.jpg", "", true},
+		{"/../etc/passwd", "", true},
+		{"artikel//foto.jpg", "", true},
+		{"artikel/./foto.jpg", "", true},
+		{"artikel/", "", true},
+		{"..", "", true},
+		{".", "", true},
+		{"/", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := storage.filePath(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("filePath(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("filePath(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalStorageVerifyPresigned(t *testing.T) {
+	storage := &LocalStorage{baseDir: "storage", publicURL: "http://localhost:3000/storage", secret: "rahasia"}
+	other := &LocalStorage{baseDir: "storage", publicURL: "http://localhost:3000/storage", secret: "lainnya"}
+
+	presigned, err := storage.GetPresignedURL("izin/surat.pdf", 10)
+	if err != nil {
+		t.Fatalf("GetPresignedURL() error = %v", err)
+	}
+	parsed, err := url.Parse(presigned)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+	expires := parsed.Query().Get("expires")
+	signature := parsed.Query().Get("signature")
+	expiresAt, _ := strconv.ParseInt(expires, 10, 64)
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		storage   *LocalStorage
+		key       string
+		expires   string
+		signature string
+		at        time.Time
+		want      bool
+	}{
+		{"valid", storage, "izin/surat.pdf", expires, signature, now, true},
+		{"at the expiry", storage, "izin/surat.pdf", expires, signature, time.Unix(expiresAt, 0), true},
+		{"expired", storage, "izin/surat.pdf", expires, signature, time.Unix(expiresAt+1, 0), false},
+		{"other key", storage, "izin/lainnya.pdf", expires, signature, now, false},
+		{"extended expiry", storage, "izin/surat.pdf", strconv.FormatInt(expiresAt+3600, 10), signature, now, false},
+		{"tampered signature", storage, "izin/surat.pdf", expires, strings.Repeat("0", len(signature)), now, false},
+		{"missing signature", storage, "izin/surat.pdf", expires, "", now, false},
+		{"invalid expires", storage, "izin/surat.pdf", "besok", signature, now, false},
+		{"other secret", other, "izin/surat.pdf", expires, signature, now, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.storage.VerifyPresigned(tt.key, tt.expires, tt.signature, tt.at); got != tt.want {
+				t.Errorf("VerifyPresigned() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
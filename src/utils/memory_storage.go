@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var _ Storage = (*MemoryStorage)(nil)
+
+// MemoryStorage keeps files in memory. It is meant for tests and for running without any storage backend;
+// everything is lost when the process exits.
+type MemoryStorage struct {
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+type memoryObject struct {
+	content      []byte
+	contentType  string
+	lastModified time.Time
+}
+
+// NewMemoryStorage initializes an empty in-memory storage
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{objects: make(map[string]memoryObject)}
+}
+
+// UploadFile stores an uploaded file in memory
+func (m *MemoryStorage) UploadFile(file *multipart.FileHeader, directory string) (string, error) {
+	return uploadMultipartFile(m, file, directory)
+}
+
+// UploadReader stores a copy of size bytes read from body under a new key inside directory
+func (m *MemoryStorage) UploadReader(ctx context.Context, body io.ReaderAt, size int64, filename, contentType, directory string) (string, error) {
+	content, err := io.ReadAll(&contextReader{ctx: ctx, reader: io.NewSectionReader(body, 0, size)})
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	return m.UploadBytes(content, filename, contentType, directory)
+}
+
+// UploadBytes stores a copy of content under a new key inside directory
+func (m *MemoryStorage) UploadBytes(content []byte, filename, contentType, directory string) (string, error) {
+	fileKey := storageObjectKey(directory, filename)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[fileKey] = memoryObject{
+		content:      append([]byte(nil), content...),
+		contentType:  contentType,
+		lastModified: time.Now(),
+	}
+	return fileKey, nil
+}
+
+// DeleteFile removes a file, a missing file is not an error
+func (m *MemoryStorage) DeleteFile(fileKey string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, fileKey)
+	return nil
+}
+
+// CopyFile copies a file to another key, overwriting an existing file
+func (m *MemoryStorage) CopyFile(srcKey, dstKey string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	object, ok := m.objects[srcKey]
+	if !ok {
+		return ErrStorageObjectNotFound
+	}
+	object.content = append([]byte(nil), object.content...)
+	object.lastModified = time.Now()
+	m.objects[dstKey] = object
+	return nil
+}
+
+// GetPublicURL returns a memory:// URL of the key
+func (m *MemoryStorage) GetPublicURL(fileKey string) string {
+	if fileKey == "" {
+		return ""
+	}
+	return "memory://" + fileKey
+}
+
+// GetPresignedURL returns a memory:// URL of the key with its expiry
+func (m *MemoryStorage) GetPresignedURL(fileKey string, expirationMinutes int) (string, error) {
+	expires := time.Now().Add(time.Duration(expirationMinutes) * time.Minute).Unix()
+	return fmt.Sprintf("memory://%s?%s", fileKey, url.Values{"expires": {strconv.FormatInt(expires, 10)}}.Encode()), nil
+}
+
+// Stat returns the size, content type and modification time of a file
+func (m *MemoryStorage) Stat(fileKey string) (*StorageObjectInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	object, ok := m.objects[fileKey]
+	if !ok {
+		return nil, ErrStorageObjectNotFound
+	}
+	return object.info(fileKey), nil
+}
+
+// Download returns a reader over a copy of the file
+func (m *MemoryStorage) Download(fileKey string) (io.ReadCloser, *StorageObjectInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	object, ok := m.objects[fileKey]
+	if !ok {
+		return nil, nil, ErrStorageObjectNotFound
+	}
+	content := append([]byte(nil), object.content...)
+	return io.NopCloser(bytes.NewReader(content)), object.info(fileKey), nil
+}
+
+// List returns the objects whose key starts with prefix, ordered by key
+func (m *MemoryStorage) List(prefix string) ([]StorageObjectInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var objects []StorageObjectInfo
+	for key, object := range m.objects {
+		if strings.HasPrefix(key, prefix) {
+			objects = append(objects, *object.info(key))
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, nil
+}
+
+// Keys returns the stored keys in order, for assertions in tests
+func (m *MemoryStorage) Keys() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := make([]string, 0, len(m.objects))
+	for key := range m.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (o memoryObject) info(fileKey string) *StorageObjectInfo {
+	return &StorageObjectInfo{
+		Key:          fileKey,
+		Size:         int64(len(o.content)),
+		ContentType:  o.contentType,
+		LastModified: o.lastModified,
+	}
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// NotifikasiMessage is a rendered message for one recipient
+type NotifikasiMessage struct {
+	Recipient string
+	Subject   string
+	Body      string
+}
+
+// NotifikasiChannel delivers messages to parents over one medium. Send returns an error when the
+// message was not accepted, so the outbox can retry it later.
+type NotifikasiChannel interface {
+	Send(message NotifikasiMessage) error
+}
+
+// EmailNotifikasiChannel sends notifications through the SMTP EmailService
+type EmailNotifikasiChannel struct {
+	email *EmailService
+}
+
+// NewEmailNotifikasiChannel creates an email channel using the SMTP_* environment variables
+func NewEmailNotifikasiChannel() *EmailNotifikasiChannel {
+	return &EmailNotifikasiChannel{email: NewEmailService()}
+}
+
+// Send sends the message as a plain text email
+func (c *EmailNotifikasiChannel) Send(message NotifikasiMessage) error {
+	return c.email.SendPlainText(message.Recipient, message.Subject, message.Body)
+}
+
+// WebhookNotifikasiChannel posts notifications to a generic HTTP gateway (e.g. a WhatsApp gateway) as
+// {"to": "628...", "subject": "...", "message": "..."}, with an optional bearer token
+type WebhookNotifikasiChannel struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewWebhookNotifikasiChannel creates a webhook channel for the given gateway URL
+func NewWebhookNotifikasiChannel(url, token string) *WebhookNotifikasiChannel {
+	return &WebhookNotifikasiChannel{
+		url:    url,
+		token:  token,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Send posts the message and treats any non-2xx response as a failed delivery
+func (c *WebhookNotifikasiChannel) Send(message NotifikasiMessage) error {
+	payload, err := json.Marshal(map[string]string{
+		"to":      message.Recipient,
+		"subject": message.Subject,
+		"message": message.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook responded %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+// NewNotifikasiChannels returns the channels configured in the environment, keyed by channel name:
+// "email" when SMTP_HOST is set and "whatsapp" when NOTIFIKASI_WEBHOOK_URL is set
+func NewNotifikasiChannels() map[string]NotifikasiChannel {
+	channels := make(map[string]NotifikasiChannel)
+
+	if os.Getenv("SMTP_HOST") != "" {
+		channels["email"] = NewEmailNotifikasiChannel()
+	}
+
+	if url := os.Getenv("NOTIFIKASI_WEBHOOK_URL"); url != "" {
+		channels["whatsapp"] = NewWebhookNotifikasiChannel(url, os.Getenv("NOTIFIKASI_WEBHOOK_TOKEN"))
+	}
+
+	return channels
+}
+
+// NormalizeNomorHP converts an Indonesian phone number to the international digits-only form
+// gateways expect, e.g. "0812-3456 789" becomes "628123456789"
+func NormalizeNomorHP(nomor string) string {
+	var digits strings.Builder
+	for _, r := range nomor {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+
+	normalized := digits.String()
+	if strings.HasPrefix(normalized, "0") {
+		normalized = "62" + normalized[1:]
+	}
+	return normalized
+}
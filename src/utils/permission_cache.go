@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// permissionCacheTTL bounds how long resolved permissions are reused before
+// they are read again from the database
+const permissionCacheTTL = 5 * time.Minute
+
+type permissionCacheEntry struct {
+	permissions map[string]bool
+	expiresAt   time.Time
+}
+
+var (
+	permissionCacheMu sync.RWMutex
+	permissionCache   = make(map[string]permissionCacheEntry)
+)
+
+// GetCachedPermissions returns the cached permission set for a principal key
+func GetCachedPermissions(key string) (map[string]bool, bool) {
+	permissionCacheMu.RLock()
+	defer permissionCacheMu.RUnlock()
+
+	entry, ok := permissionCache[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.permissions, true
+}
+
+// SetCachedPermissions stores the permission set for a principal key
+func SetCachedPermissions(key string, permissionNames []string) map[string]bool {
+	permissions := make(map[string]bool, len(permissionNames))
+	for _, name := range permissionNames {
+		permissions[name] = true
+	}
+
+	permissionCacheMu.Lock()
+	defer permissionCacheMu.Unlock()
+
+	permissionCache[key] = permissionCacheEntry{
+		permissions: permissions,
+		expiresAt:   time.Now().Add(permissionCacheTTL),
+	}
+	return permissions
+}
+
+// InvalidatePermissionCache drops every cached permission set. Called whenever
+// role-permission or principal-role assignments change.
+func InvalidatePermissionCache() {
+	permissionCacheMu.Lock()
+	defer permissionCacheMu.Unlock()
+
+	permissionCache = make(map[string]permissionCacheEntry)
+}
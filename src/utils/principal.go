@@ -0,0 +1,33 @@
+package utils
+
+// Principal types carried in JWT claims and audit columns
+const (
+	PrincipalUser    = "user"
+	PrincipalPegawai = "pegawai"
+	PrincipalSiswa   = "siswa"
+)
+
+// Principal identifies the authenticated caller. ID alone is ambiguous because
+// users, kepegawaian and peserta_didik each have their own ID sequence.
+type Principal struct {
+	ID       uint
+	Type     string
+	Username string
+	Nama     string
+	RoleID   *uint
+	Status   string
+}
+
+// TypePtr returns the principal type as a pointer for nullable audit columns
+func (p Principal) TypePtr() *string {
+	if p.Type == "" {
+		return nil
+	}
+	principalType := p.Type
+	return &principalType
+}
+
+// IsValidPrincipalType reports whether t is a known principal type
+func IsValidPrincipalType(t string) bool {
+	return t == PrincipalUser || t == PrincipalPegawai || t == PrincipalSiswa
+}
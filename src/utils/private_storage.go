@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"log"
+	"path"
+	"strings"
+)
+
+// StoragePrivatePrefix is prepended to the keys of uploads in a private directory. With R2 these keys go
+// to the required R2_PRIVATE_BUCKET_NAME; the local driver only serves them through a presigned URL.
+const StoragePrivatePrefix = "private/"
+
+// PrivateURLExpirationMinutes is the lifetime of presigned URLs of private files in API responses
+const PrivateURLExpirationMinutes = 15
+
+// PrivateEmailURLExpirationMinutes is the lifetime of presigned URLs sent by email (7 days, the R2 maximum)
+const PrivateEmailURLExpirationMinutes = 7 * 24 * 60
+
+// privateStorageDirectories are upload directories holding personal documents of pegawai and siswa.
+// Subdirectories are private as well.
+var privateStorageDirectories = []string{
+	"kepegawaian/kk",
+	"kepegawaian/akta-lahir",
+	"kepegawaian/ktp",
+	"kepegawaian/ijazah-sd",
+	"kepegawaian/ijazah-smp",
+	"kepegawaian/ijazah-sma",
+	"kepegawaian/ijazah-s1",
+	"kepegawaian/ijazah-s2",
+	"kepegawaian/ijazah-s3",
+	"kepegawaian/sertifikat-pendidik",
+	"kepegawaian/sertifikat-lainnya",
+	"kepegawaian/sk",
+	"kepegawaian/dokumen-lainnya",
+	"mutasi-siswa/rapor",
+	"mutasi-siswa/akte",
+	"mutasi-siswa/kk",
+	"mutasi-siswa/sptjm",
+	"layanan-umpan-balik/pengaduan",
+	"absensi-siswa",
+}
+
+// IsPrivateStorageDirectory reports whether uploads to directory are private
+func IsPrivateStorageDirectory(directory string) bool {
+	directory = strings.Trim(directory, "/")
+	for _, private := range privateStorageDirectories {
+		if directory == private || strings.HasPrefix(directory, private+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPrivateStorageKey reports whether a key is private, either stored under StoragePrivatePrefix or
+// uploaded to a private directory before the prefix existed
+func IsPrivateStorageKey(fileKey string) bool {
+	return strings.HasPrefix(fileKey, StoragePrivatePrefix) || IsPrivateStorageDirectory(path.Dir(fileKey))
+}
+
+// FileURL returns the URL of a stored file for an authorized caller: a presigned URL valid for
+// PrivateURLExpirationMinutes for private keys and the public URL otherwise
+func FileURL(storage Storage, fileKey string) string {
+	return FileURLWithExpiry(storage, fileKey, PrivateURLExpirationMinutes)
+}
+
+// FileURLWithExpiry is FileURL with a custom lifetime of private presigned URLs
+func FileURLWithExpiry(storage Storage, fileKey string, expirationMinutes int) string {
+	if fileKey == "" {
+		return ""
+	}
+	if !IsPrivateStorageKey(fileKey) {
+		return storage.GetPublicURL(fileKey)
+	}
+
+	url, err := storage.GetPresignedURL(fileKey, expirationMinutes)
+	if err != nil {
+		log.Printf("storage: presign %s: %v", fileKey, err)
+		return ""
+	}
+	return url
+}
+
+// PublicFileURL returns the public URL of a stored file, or an empty string for private keys so
+// unauthenticated responses never expose them
+func PublicFileURL(storage Storage, fileKey string) string {
+	if fileKey == "" || IsPrivateStorageKey(fileKey) {
+		return ""
+	}
+	return storage.GetPublicURL(fileKey)
+}
@@ -0,0 +1,29 @@
+package utils
+
+import "testing"
+
+func TestIsPrivateStorageKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"private/kepegawaian/ktp/1700000000-ktp.pdf", true},
+		{"private/anything.pdf", true},
+		{"kepegawaian/ktp/1700000000-ktp.pdf", true},
+		{"kepegawaian/ktp/thumbnail/1700000000-ktp.jpg", true},
+		{"absensi-siswa/1700000000-surat.pdf", true},
+		{"kepegawaian/foto/1700000000-foto.jpg", false},
+		{"kepegawaian/ktp-lama/1700000000-ktp.pdf", false},
+		{"galeri/1700000000-foto.jpg", false},
+		{"privates/1700000000-foto.jpg", false},
+		{"1700000000-foto.jpg", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := IsPrivateStorageKey(tt.key); got != tt.want {
+				t.Errorf("IsPrivateStorageKey(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,23 +3,42 @@ package utils
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"mime/multipart"
+	"net/url"
 	"os"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"golang.org/x/sync/errgroup"
 )
 
-// R2Storage handles all R2 operations
+var _ Storage = (*R2Storage)(nil)
+
+const (
+	// Files larger than r2MultipartThreshold are sent as a multipart upload of r2PartSize parts (R2 requires at
+	// least 5MB for every part but the last), with up to r2PartConcurrency parts in flight
+	r2MultipartThreshold = 16 << 20
+	r2PartSize           = 8 << 20
+	r2PartConcurrency    = 4
+	// r2UploadTimeout bounds a single PutObject or UploadPart request
+	r2UploadTimeout = 2 * time.Minute
+)
+
+// R2Storage is the Cloudflare R2 (S3 compatible) storage
 type R2Storage struct {
-	client     *s3.Client
-	bucketName string
-	publicURL  string
+	client            *s3.Client
+	bucketName        string
+	privateBucketName string
+	publicURL         string
 }
 
 // NewR2Storage initializes R2 storage
@@ -30,6 +49,12 @@ func NewR2Storage() *R2Storage {
 	endpoint := os.Getenv("R2_ENDPOINT")
 	publicDomain := os.Getenv("R2_PUBLIC_DOMAIN")
 
+	// Private keys go to a separate bucket without public access, never to the public one
+	privateBucketName := os.Getenv("R2_PRIVATE_BUCKET_NAME")
+	if privateBucketName == "" || privateBucketName == bucketName {
+		log.Fatalf("storage: R2_PRIVATE_BUCKET_NAME must be set to a bucket without public access, separate from R2_BUCKET_NAME")
+	}
+
 	// Create credentials
 	creds := credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, "")
 
@@ -41,50 +66,120 @@ func NewR2Storage() *R2Storage {
 	})
 
 	return &R2Storage{
-		client:     client,
-		bucketName: bucketName,
-		publicURL:  publicDomain,
+		client:            client,
+		bucketName:        bucketName,
+		privateBucketName: privateBucketName,
+		publicURL:         publicDomain,
 	}
 }
 
-// UploadFile uploads file to R2 storage
+// UploadFile streams an uploaded file to R2 storage
 func (r *R2Storage) UploadFile(file *multipart.FileHeader, directory string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	return uploadMultipartFile(r, file, directory)
+}
 
-	// Open file
-	src, err := file.Open()
-	if err != nil {
-		return "", fmt.Errorf("failed to open file: %w", err)
-	}
-	defer src.Close()
+// UploadBytes uploads content to R2 storage under a new key inside directory
+func (r *R2Storage) UploadBytes(content []byte, filename, contentType, directory string) (string, error) {
+	return r.UploadReader(context.Background(), bytes.NewReader(content), int64(len(content)), filename, contentType, directory)
+}
 
-	// Read file content
-	fileContent, err := io.ReadAll(src)
-	if err != nil {
-		return "", fmt.Errorf("failed to read file: %w", err)
+// UploadReader streams size bytes of body to R2 storage under a new key inside directory, as a multipart
+// upload for large files. Parts are read with ReadAt so nothing beyond the SDK buffers is held in memory.
+func (r *R2Storage) UploadReader(ctx context.Context, body io.ReaderAt, size int64, filename, contentType, directory string) (string, error) {
+	// Generate filename with timestamp (inside directory)
+	fileKey := storageObjectKey(directory, filename)
+
+	if size > r2MultipartThreshold {
+		if err := r.uploadMultipart(ctx, fileKey, body, size, contentType); err != nil {
+			return "", err
+		}
+		return fileKey, nil
 	}
 
-	// Generate filename with timestamp (inside directory)
-	timestamp := time.Now().Unix()
-	sanitizedFilename := sanitizeFilename(file.Filename)
-	filename := fmt.Sprintf("%s/%d-%s", directory, timestamp, sanitizedFilename)
+	ctx, cancel := context.WithTimeout(ctx, r2UploadTimeout)
+	defer cancel()
 
 	// Upload to R2
 	putObjectInput := &s3.PutObjectInput{
-		Bucket:      aws.String(r.bucketName),
-		Key:         aws.String(filename),
-		Body:        bytes.NewReader(fileContent),
-		ContentType: aws.String(file.Header.Get("Content-Type")),
+		Bucket:        aws.String(r.bucket(fileKey)),
+		Key:           aws.String(fileKey),
+		Body:          io.NewSectionReader(body, 0, size),
+		ContentLength: aws.Int64(size),
+		ContentType:   aws.String(contentType),
 	}
 
-	_, err = r.client.PutObject(ctx, putObjectInput)
+	_, err := r.client.PutObject(ctx, putObjectInput)
 	if err != nil {
 		return "", fmt.Errorf("failed to upload file to R2: %w", err)
 	}
 
 	// Return the file path (object key in R2)
-	return filename, nil
+	return fileKey, nil
+}
+
+// uploadMultipart uploads body in parts, aborting the multipart upload when a part fails or ctx is canceled
+func (r *R2Storage) uploadMultipart(ctx context.Context, fileKey string, body io.ReaderAt, size int64, contentType string) error {
+	bucket := aws.String(r.bucket(fileKey))
+	created, err := r.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      bucket,
+		Key:         aws.String(fileKey),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload file to R2: %w", err)
+	}
+
+	partCount := int((size + r2PartSize - 1) / r2PartSize)
+	parts := make([]types.CompletedPart, partCount)
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(r2PartConcurrency)
+	for i := 0; i < partCount; i++ {
+		offset := int64(i) * r2PartSize
+		length := min(r2PartSize, size-offset)
+		partNumber := aws.Int32(int32(i + 1))
+
+		group.Go(func() error {
+			partCtx, cancel := context.WithTimeout(groupCtx, r2UploadTimeout)
+			defer cancel()
+
+			output, err := r.client.UploadPart(partCtx, &s3.UploadPartInput{
+				Bucket:        bucket,
+				Key:           aws.String(fileKey),
+				UploadId:      created.UploadId,
+				PartNumber:    partNumber,
+				Body:          io.NewSectionReader(body, offset, length),
+				ContentLength: aws.Int64(length),
+			})
+			if err != nil {
+				return err
+			}
+			parts[i] = types.CompletedPart{ETag: output.ETag, PartNumber: partNumber}
+			return nil
+		})
+	}
+
+	err = group.Wait()
+	if err == nil {
+		_, err = r.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          bucket,
+			Key:             aws.String(fileKey),
+			UploadId:        created.UploadId,
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+		})
+	}
+	if err != nil {
+		// Uploaded parts are billed until the upload is aborted, also when the request was canceled
+		abortCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_, _ = r.client.AbortMultipartUpload(abortCtx, &s3.AbortMultipartUploadInput{
+			Bucket:   bucket,
+			Key:      aws.String(fileKey),
+			UploadId: created.UploadId,
+		})
+		return fmt.Errorf("failed to upload file to R2: %w", err)
+	}
+	return nil
 }
 
 // DeleteFile deletes file from R2 storage
@@ -93,7 +188,7 @@ func (r *R2Storage) DeleteFile(fileKey string) error {
 	defer cancel()
 
 	deleteObjectInput := &s3.DeleteObjectInput{
-		Bucket: aws.String(r.bucketName),
+		Bucket: aws.String(r.bucket(fileKey)),
 		Key:    aws.String(fileKey),
 	}
 
@@ -105,6 +200,26 @@ func (r *R2Storage) DeleteFile(fileKey string) error {
 	return nil
 }
 
+// CopyFile copies an object to another key, also across the public and private bucket
+func (r *R2Storage) CopyFile(srcKey, dstKey string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	_, err := r.client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(r.bucket(dstKey)),
+		Key:        aws.String(dstKey),
+		CopySource: aws.String(r.bucket(srcKey) + "/" + (&url.URL{Path: srcKey}).EscapedPath()),
+	})
+	if err != nil {
+		if isR2NotFound(err) {
+			return ErrStorageObjectNotFound
+		}
+		return fmt.Errorf("failed to copy file in R2: %w", err)
+	}
+
+	return nil
+}
+
 // GetPublicURL returns the public URL for a file
 func (r *R2Storage) GetPublicURL(fileKey string) string {
 	// Return empty string if fileKey is empty
@@ -124,7 +239,7 @@ func (r *R2Storage) GetPresignedURL(fileKey string, expirationMinutes int) (stri
 	presigner := s3.NewPresignClient(r.client)
 
 	getObjectInput := &s3.GetObjectInput{
-		Bucket: aws.String(r.bucketName),
+		Bucket: aws.String(r.bucket(fileKey)),
 		Key:    aws.String(fileKey),
 	}
 
@@ -140,6 +255,94 @@ func (r *R2Storage) GetPresignedURL(fileKey string, expirationMinutes int) (stri
 	return presignedRequest.URL, nil
 }
 
+// Stat returns the size, content type and modification time of a file
+func (r *R2Storage) Stat(fileKey string) (*StorageObjectInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	output, err := r.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(r.bucket(fileKey)),
+		Key:    aws.String(fileKey),
+	})
+	if err != nil {
+		if isR2NotFound(err) {
+			return nil, ErrStorageObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to stat file in R2: %w", err)
+	}
+
+	return &StorageObjectInfo{
+		Key:          fileKey,
+		Size:         aws.ToInt64(output.ContentLength),
+		ContentType:  aws.ToString(output.ContentType),
+		LastModified: aws.ToTime(output.LastModified),
+	}, nil
+}
+
+// Download opens a file for reading, the caller closes the reader
+func (r *R2Storage) Download(fileKey string) (io.ReadCloser, *StorageObjectInfo, error) {
+	output, err := r.client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(r.bucket(fileKey)),
+		Key:    aws.String(fileKey),
+	})
+	if err != nil {
+		if isR2NotFound(err) {
+			return nil, nil, ErrStorageObjectNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to download file from R2: %w", err)
+	}
+
+	return output.Body, &StorageObjectInfo{
+		Key:          fileKey,
+		Size:         aws.ToInt64(output.ContentLength),
+		ContentType:  aws.ToString(output.ContentType),
+		LastModified: aws.ToTime(output.LastModified),
+	}, nil
+}
+
+// List returns the objects whose key starts with prefix, ordered by key. Prefixes under
+// StoragePrivatePrefix are listed in the private bucket.
+func (r *R2Storage) List(prefix string) ([]StorageObjectInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	paginator := s3.NewListObjectsV2Paginator(r.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(r.bucket(prefix)),
+		Prefix: aws.String(prefix),
+	})
+
+	var objects []StorageObjectInfo
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files in R2: %w", err)
+		}
+		for _, object := range page.Contents {
+			objects = append(objects, StorageObjectInfo{
+				Key:          aws.ToString(object.Key),
+				Size:         aws.ToInt64(object.Size),
+				LastModified: aws.ToTime(object.LastModified),
+			})
+		}
+	}
+	return objects, nil
+}
+
+// bucket returns the bucket holding a key, keys under StoragePrivatePrefix use the private bucket
+func (r *R2Storage) bucket(fileKey string) string {
+	if strings.HasPrefix(fileKey, StoragePrivatePrefix) {
+		return r.privateBucketName
+	}
+	return r.bucketName
+}
+
+// isR2NotFound reports whether an R2 error means the key does not exist
+func isR2NotFound(err error) bool {
+	var noSuchKey *types.NoSuchKey
+	var notFound *types.NotFound
+	return errors.As(err, &noSuchKey) || errors.As(err, &notFound)
+}
+
 // sanitizeFilename removes spaces and special characters from filename
 func sanitizeFilename(filename string) string {
 	// Replace spaces with dash
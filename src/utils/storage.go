@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrStorageObjectNotFound is returned by Stat and Download when the key does not exist
+var ErrStorageObjectNotFound = errors.New("file tidak ditemukan di storage")
+
+// Storage is the object storage used for uploaded files. Keys are slash separated paths such as
+// "artikel/1718000000-foto.jpg"; the database stores keys and responses expose FileURL.
+type Storage interface {
+	UploadFile(file *multipart.FileHeader, directory string) (string, error)
+	UploadBytes(content []byte, filename, contentType, directory string) (string, error)
+	UploadReader(ctx context.Context, body io.ReaderAt, size int64, filename, contentType, directory string) (string, error)
+	DeleteFile(fileKey string) error
+	CopyFile(srcKey, dstKey string) error
+	GetPublicURL(fileKey string) string
+	GetPresignedURL(fileKey string, expirationMinutes int) (string, error)
+	Stat(fileKey string) (*StorageObjectInfo, error)
+	Download(fileKey string) (io.ReadCloser, *StorageObjectInfo, error)
+	List(prefix string) ([]StorageObjectInfo, error)
+}
+
+// StorageObjectInfo describes a stored object. ContentType is empty in List results of drivers that do not
+// return it when listing.
+type StorageObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// Storage drivers selected with STORAGE_DRIVER
+const (
+	StorageDriverR2     = "r2"
+	StorageDriverLocal  = "local"
+	StorageDriverMemory = "memory"
+)
+
+var (
+	defaultStorage     Storage
+	defaultStorageOnce sync.Once
+)
+
+// NewStorage returns the process-wide storage of the driver in STORAGE_DRIVER (r2, local or memory; default r2)
+func NewStorage() Storage {
+	defaultStorageOnce.Do(func() {
+		driver := strings.ToLower(strings.TrimSpace(os.Getenv("STORAGE_DRIVER")))
+		switch driver {
+		case StorageDriverLocal:
+			defaultStorage = NewLocalStorage()
+		case StorageDriverMemory:
+			defaultStorage = NewMemoryStorage()
+		case "", StorageDriverR2:
+			defaultStorage = NewR2Storage()
+		default:
+			log.Printf("storage: unknown STORAGE_DRIVER %q, using r2", driver)
+			defaultStorage = NewR2Storage()
+		}
+	})
+	return defaultStorage
+}
+
+// storageObjectKey builds the key of a new upload inside directory, private directories get StoragePrivatePrefix
+func storageObjectKey(directory, filename string) string {
+	if IsPrivateStorageDirectory(directory) {
+		directory = StoragePrivatePrefix + strings.Trim(directory, "/")
+	}
+	return fmt.Sprintf("%s/%d-%s", directory, time.Now().Unix(), sanitizeFilename(filename))
+}
+
+// uploadMultipartFile streams an uploaded file with its declared content type to storage
+func uploadMultipartFile(storage Storage, file *multipart.FileHeader, directory string) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer src.Close()
+
+	return storage.UploadReader(context.Background(), src, file.Size, file.Filename, file.Header.Get("Content-Type"), directory)
+}
+
+// contextReader stops reading once its context is canceled
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.reader.Read(p)
+}
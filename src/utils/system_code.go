@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"os"
+	"sync"
+)
+
+// defaultPintuSystemCode is used when PINTU_SYSTEM_CODE is not configured
+const defaultPintuSystemCode = "PINTU"
+
+var (
+	systemIDCacheMu sync.RWMutex
+	systemIDCache   = make(map[string]uint)
+)
+
+// PintuSystemCode returns the systems.code whose roles grant access to PINTU
+func PintuSystemCode() string {
+	if code := os.Getenv("PINTU_SYSTEM_CODE"); code != "" {
+		return code
+	}
+	return defaultPintuSystemCode
+}
+
+// GetCachedSystemID returns the cached systems.id for a system code
+func GetCachedSystemID(code string) (uint, bool) {
+	systemIDCacheMu.RLock()
+	defer systemIDCacheMu.RUnlock()
+
+	id, ok := systemIDCache[code]
+	return id, ok
+}
+
+// SetCachedSystemID stores the systems.id resolved for a system code
+func SetCachedSystemID(code string, id uint) {
+	systemIDCacheMu.Lock()
+	defer systemIDCacheMu.Unlock()
+
+	systemIDCache[code] = id
+}
+
+// InvalidateSystemIDCache drops every resolved system code. Called whenever
+// systems are updated or deleted.
+func InvalidateSystemIDCache() {
+	systemIDCacheMu.Lock()
+	defer systemIDCacheMu.Unlock()
+
+	systemIDCache = make(map[string]uint)
+}
@@ -0,0 +1,27 @@
+package utils
+
+import "time"
+
+// JakartaLocation returns the Asia/Jakarta timezone used for all attendance times
+func JakartaLocation() *time.Location {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		// Fallback: use FixedZone if LoadLocation fails (for containers without tzdata)
+		loc = time.FixedZone("WIB", 7*60*60) // UTC+7
+	}
+	return loc
+}
+
+// WithinTimeWindow reports whether t (in Asia/Jakarta) falls inside the "HH:MM:SS" window.
+// A nil bound means no window; a start after the end wraps past midnight.
+func WithinTimeWindow(mulai, selesai *string, t time.Time) bool {
+	if mulai == nil || selesai == nil {
+		return true
+	}
+
+	current := t.In(JakartaLocation()).Format("15:04:05")
+	if *mulai <= *selesai {
+		return current >= *mulai && current <= *selesai
+	}
+	return current >= *mulai || current <= *selesai
+}
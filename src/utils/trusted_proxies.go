@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"os"
+	"strings"
+)
+
+// TrustedProxies returns the proxies in TRUSTED_PROXIES (comma separated IPs or CIDRs) whose
+// X-Forwarded-For/X-Real-IP headers are used for the client IP. Nil, the default, trusts no proxy so
+// the client IP is always the peer address.
+func TrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTrustedProxiesClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		env        string
+		remoteAddr string
+		forwarded  string
+		want       string
+	}{
+		{"default ignores forwarded header", "", "203.0.113.7:5000", "10.1.2.3", "203.0.113.7"},
+		{"untrusted peer ignores forwarded header", "127.0.0.1", "203.0.113.7:5000", "10.1.2.3", "203.0.113.7"},
+		{"trusted proxy", "127.0.0.1", "127.0.0.1:5000", "198.51.100.9", "198.51.100.9"},
+		{"trusted cidr with spaces", " 10.0.0.0/8 , 127.0.0.1 ", "10.4.0.2:5000", "198.51.100.9", "198.51.100.9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TRUSTED_PROXIES", tt.env)
+
+			router := gin.New()
+			if err := router.SetTrustedProxies(TrustedProxies()); err != nil {
+				t.Fatalf("SetTrustedProxies() error = %v", err)
+			}
+			var got string
+			router.GET("/", func(c *gin.Context) {
+				got = c.ClientIP()
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", tt.forwarded)
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
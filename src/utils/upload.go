@@ -0,0 +1,309 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// Upload categories, each with its own allow-list, size limit and processing
+const (
+	UploadCategoryFoto      = "foto"      // foto pegawai and siswa, metadata (EXIF/GPS) stripped
+	UploadCategoryGambar    = "gambar"    // single images such as sarpras, kepsek and pengumuman
+	UploadCategoryPublikasi = "publikasi" // artikel, prestasi and jumbotron images, stored with variants
+	UploadCategoryGaleri    = "galeri"    // galeri kegiatan photos, stored with variants
+	UploadCategoryLampiran  = "lampiran"  // attachments of artikel and pengumuman
+	UploadCategoryDokumen   = "dokumen"   // documents such as kepegawaian scans, surat and pengaduan attachments
+)
+
+// UploadRule is the validation and processing of an upload category
+type UploadRule struct {
+	AllowedTypes  []string
+	MaxSize       int64
+	StripMetadata bool // re-encode images so EXIF/GPS metadata is dropped
+	Variants      bool // also store thumbnail and medium variants of images
+}
+
+var (
+	imageTypes    = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+	documentTypes = []string{
+		"application/pdf",
+		"application/msword",
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		"application/vnd.ms-excel",
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		"text/plain",
+		"image/jpeg",
+		"image/png",
+	}
+)
+
+// defaultUploadRules are the rules before UPLOAD_<CATEGORY>_MAX_SIZE_MB and UPLOAD_<CATEGORY>_ALLOWED_TYPES overrides
+var defaultUploadRules = map[string]UploadRule{
+	UploadCategoryFoto:      {AllowedTypes: []string{"image/jpeg", "image/png", "image/webp"}, MaxSize: 5 << 20, StripMetadata: true},
+	UploadCategoryGambar:    {AllowedTypes: imageTypes, MaxSize: 5 << 20, StripMetadata: true},
+	UploadCategoryPublikasi: {AllowedTypes: imageTypes, MaxSize: 5 << 20, StripMetadata: true, Variants: true},
+	UploadCategoryGaleri:    {AllowedTypes: imageTypes, MaxSize: 10 << 20, StripMetadata: true, Variants: true},
+	UploadCategoryLampiran:  {AllowedTypes: documentTypes, MaxSize: 10 << 20},
+	UploadCategoryDokumen:   {AllowedTypes: append(documentTypes, "image/webp"), MaxSize: 10 << 20},
+}
+
+// GetUploadRule returns the rule of a category with the overrides from the environment
+func GetUploadRule(category string) (UploadRule, error) {
+	rule, ok := defaultUploadRules[category]
+	if !ok {
+		return UploadRule{}, fmt.Errorf("kategori upload %q tidak dikenal", category)
+	}
+
+	prefix := "UPLOAD_" + strings.ToUpper(category) + "_"
+	if value := os.Getenv(prefix + "MAX_SIZE_MB"); value != "" {
+		if mb, err := strconv.Atoi(value); err == nil && mb > 0 {
+			rule.MaxSize = int64(mb) << 20
+		}
+	}
+	if value := os.Getenv(prefix + "ALLOWED_TYPES"); value != "" {
+		var types []string
+		for _, t := range strings.Split(value, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				types = append(types, t)
+			}
+		}
+		rule.AllowedTypes = types
+	}
+	return rule, nil
+}
+
+// PreparedUpload is a validated upload ready to be stored, with its content type sniffed from the bytes.
+// Processed images are held in Content; other files are streamed from the uploaded file on Store, so the
+// caller must Close it.
+type PreparedUpload struct {
+	Filename    string
+	Content     []byte
+	Size        int64
+	ContentType string
+	Width       int
+	Height      int
+	Variants    []PreparedVariant
+	source      multipart.File
+}
+
+// Close releases the uploaded file a streamed upload reads from
+func (u *PreparedUpload) Close() error {
+	if u.source == nil {
+		return nil
+	}
+	return u.source.Close()
+}
+
+// PreparedVariant is a resized image of a PreparedUpload, without content when the original is small enough
+type PreparedVariant struct {
+	Name        string
+	Filename    string
+	Content     []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// StoredUpload is an upload written to storage
+type StoredUpload struct {
+	Key         string
+	Filename    string
+	Size        int64
+	ContentType string
+	Width       int
+	Height      int
+	Variants    map[string]StoredVariant // ImageVariant* names, empty unless the category stores variants
+}
+
+// StoredVariant is a stored image variant
+type StoredVariant struct {
+	Key    string
+	Width  int
+	Height int
+	Size   int64
+}
+
+// PrepareUpload validates an uploaded file against the rule of category. Images are read, re-encoded
+// (dropping metadata) and resized into variants when the rule asks for it; other files stay on the uploaded
+// file until StoreUpload streams them.
+func PrepareUpload(file *multipart.FileHeader, category string) (*PreparedUpload, error) {
+	rule, err := GetUploadRule(category)
+	if err != nil {
+		return nil, err
+	}
+	// Size is counted by the multipart parser, not declared by the client
+	if file.Size > rule.MaxSize {
+		return nil, fmt.Errorf("%s: ukuran file maksimal %dMB", file.Filename, rule.MaxSize>>20)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	contentType, ok, err := sniffAllowedType(io.NewSectionReader(src, 0, file.Size), rule.AllowedTypes)
+	if err != nil {
+		src.Close()
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if !ok {
+		src.Close()
+		return nil, fmt.Errorf("%s: tipe file %s tidak diizinkan (diizinkan: %s)",
+			file.Filename, contentType, strings.Join(rule.AllowedTypes, ", "))
+	}
+
+	upload := &PreparedUpload{Filename: file.Filename, Size: file.Size, ContentType: contentType}
+	if !imageNeedsProcessing(contentType, rule) {
+		if strings.HasPrefix(contentType, "image/") {
+			config, err := readImageConfig(io.NewSectionReader(src, 0, file.Size))
+			if err != nil {
+				src.Close()
+				return nil, fmt.Errorf("%s: %w", file.Filename, err)
+			}
+			upload.Width, upload.Height = config.Width, config.Height
+		}
+		upload.source = src
+		return upload, nil
+	}
+
+	defer src.Close()
+	upload.Content, err = io.ReadAll(io.NewSectionReader(src, 0, file.Size))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if err := processImage(upload, rule); err != nil {
+		return nil, fmt.Errorf("%s: %w", file.Filename, err)
+	}
+	return upload, nil
+}
+
+// StoreUpload writes a prepared upload and its variants inside directory; variants go to
+// directory/<variant>. Already written objects are deleted when a later write fails or ctx is canceled.
+func StoreUpload(ctx context.Context, storage Storage, upload *PreparedUpload, directory string) (*StoredUpload, error) {
+	var body io.ReaderAt = upload.source
+	if upload.Content != nil {
+		body = bytes.NewReader(upload.Content)
+	}
+	key, err := storage.UploadReader(ctx, body, upload.Size, upload.Filename, upload.ContentType, directory)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &StoredUpload{
+		Key:         key,
+		Filename:    upload.Filename,
+		Size:        upload.Size,
+		ContentType: upload.ContentType,
+		Width:       upload.Width,
+		Height:      upload.Height,
+	}
+	if len(upload.Variants) == 0 {
+		return stored, nil
+	}
+
+	stored.Variants = map[string]StoredVariant{
+		ImageVariantOriginal: {Key: key, Width: upload.Width, Height: upload.Height, Size: stored.Size},
+	}
+	for _, variant := range upload.Variants {
+		if variant.Content == nil {
+			stored.Variants[variant.Name] = stored.Variants[ImageVariantOriginal]
+			continue
+		}
+		variantKey, err := storage.UploadReader(ctx, bytes.NewReader(variant.Content), int64(len(variant.Content)),
+			variant.Filename, variant.ContentType, path.Join(directory, variant.Name))
+		if err != nil {
+			DeleteStoredUpload(storage, stored)
+			return nil, err
+		}
+		stored.Variants[variant.Name] = StoredVariant{
+			Key:    variantKey,
+			Width:  variant.Width,
+			Height: variant.Height,
+			Size:   int64(len(variant.Content)),
+		}
+	}
+	return stored, nil
+}
+
+// SaveUpload validates, processes and stores an uploaded file, stopping when ctx (the request context) is canceled.
+// It waits for the process-wide upload budget (UPLOAD_MAX_CONCURRENT, UPLOAD_MAX_INFLIGHT_MB) before decoding the file.
+func SaveUpload(ctx context.Context, storage Storage, file *multipart.FileHeader, category, directory string) (*StoredUpload, error) {
+	release, err := getUploadBudget().acquire(ctx, uploadBudgetWeight(file, category))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	upload, err := PrepareUpload(file, category)
+	if err != nil {
+		return nil, err
+	}
+	defer upload.Close()
+
+	stored, err := StoreUpload(ctx, storage, upload, directory)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("upload dibatalkan: %w", ctx.Err())
+		}
+		return nil, err
+	}
+	return stored, nil
+}
+
+// uploadBudgetWeight estimates the memory an upload takes while it is saved: its size, plus 4 bytes per pixel
+// for an image that is decoded and re-encoded. Files failing validation weigh their size, PrepareUpload rejects them.
+func uploadBudgetWeight(file *multipart.FileHeader, category string) int64 {
+	rule, err := GetUploadRule(category)
+	if err != nil {
+		return file.Size
+	}
+	src, err := file.Open()
+	if err != nil {
+		return file.Size
+	}
+	defer src.Close()
+
+	contentType, ok, err := sniffAllowedType(io.NewSectionReader(src, 0, file.Size), rule.AllowedTypes)
+	if err != nil || !ok || !imageNeedsProcessing(contentType, rule) {
+		return file.Size
+	}
+	config, err := readImageConfig(io.NewSectionReader(src, 0, file.Size))
+	if err != nil {
+		return file.Size
+	}
+	return file.Size + int64(config.Width)*int64(config.Height)*4
+}
+
+// DeleteStoredUpload deletes an upload with its variants, best effort
+func DeleteStoredUpload(storage Storage, stored *StoredUpload) {
+	_ = storage.DeleteFile(stored.Key)
+	for _, variant := range stored.Variants {
+		if variant.Key != stored.Key {
+			_ = storage.DeleteFile(variant.Key)
+		}
+	}
+}
+
+// sniffAllowedType detects the content type from the first bytes and matches it against the allow-list
+func sniffAllowedType(r io.Reader, allowedTypes []string) (string, bool, error) {
+	detected, err := mimetype.DetectReader(r)
+	if err != nil {
+		return "", false, err
+	}
+	for _, allowed := range allowedTypes {
+		if detected.Is(allowed) {
+			return allowed, true, nil
+		}
+	}
+	contentType, _, _ := strings.Cut(detected.String(), ";")
+	return contentType, false, nil
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// Defaults of UPLOAD_MAX_CONCURRENT and UPLOAD_MAX_INFLIGHT_MB
+const (
+	defaultUploadMaxConcurrent = 8
+	defaultUploadMaxInflightMB = 64
+)
+
+// uploadBudget bounds the uploads processed at the same time across all requests, both in number and in bytes
+type uploadBudget struct {
+	slots    *semaphore.Weighted
+	bytes    *semaphore.Weighted
+	maxBytes int64
+}
+
+var (
+	defaultUploadBudget     *uploadBudget
+	defaultUploadBudgetOnce sync.Once
+)
+
+// getUploadBudget returns the process-wide budget from UPLOAD_MAX_CONCURRENT and UPLOAD_MAX_INFLIGHT_MB
+func getUploadBudget() *uploadBudget {
+	defaultUploadBudgetOnce.Do(func() {
+		concurrent := int64(defaultUploadMaxConcurrent)
+		if value, err := strconv.Atoi(os.Getenv("UPLOAD_MAX_CONCURRENT")); err == nil && value > 0 {
+			concurrent = int64(value)
+		}
+		maxBytes := int64(defaultUploadMaxInflightMB) << 20
+		if value, err := strconv.Atoi(os.Getenv("UPLOAD_MAX_INFLIGHT_MB")); err == nil && value > 0 {
+			maxBytes = int64(value) << 20
+		}
+
+		defaultUploadBudget = &uploadBudget{
+			slots:    semaphore.NewWeighted(concurrent),
+			bytes:    semaphore.NewWeighted(maxBytes),
+			maxBytes: maxBytes,
+		}
+	})
+	return defaultUploadBudget
+}
+
+// acquire waits for a slot and size bytes of the budget, or until ctx is canceled. A file larger than the whole
+// budget takes all of it. The returned func gives both back.
+func (b *uploadBudget) acquire(ctx context.Context, size int64) (func(), error) {
+	if err := b.slots.Acquire(ctx, 1); err != nil {
+		return nil, fmt.Errorf("upload dibatalkan: %w", err)
+	}
+
+	weight := min(max(size, 1), b.maxBytes)
+	if err := b.bytes.Acquire(ctx, weight); err != nil {
+		b.slots.Release(1)
+		return nil, fmt.Errorf("upload dibatalkan: %w", err)
+	}
+
+	return func() {
+		b.bytes.Release(weight)
+		b.slots.Release(1)
+	}, nil
+}
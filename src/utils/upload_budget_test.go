@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+)
+
+func newTestUploadBudget(slots, maxBytes int64) *uploadBudget {
+	return &uploadBudget{
+		slots:    semaphore.NewWeighted(slots),
+		bytes:    semaphore.NewWeighted(maxBytes),
+		maxBytes: maxBytes,
+	}
+}
+
+func TestUploadBudgetAcquire(t *testing.T) {
+	tests := []struct {
+		name     string
+		held     []int64 // sizes acquired and not released before the checked one
+		size     int64
+		wantWait bool
+	}{
+		{"empty budget", nil, 60, false},
+		{"fits next to another upload", []int64{40}, 60, false},
+		{"bytes exhausted", []int64{50}, 60, true},
+		{"slots exhausted", []int64{1, 1}, 1, true},
+		{"larger than the budget takes all of it", nil, 500, false},
+		{"larger than the budget waits for the rest", []int64{1}, 500, true},
+		{"empty file still takes a byte", []int64{99}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := newTestUploadBudget(2, 100)
+			for _, size := range tt.held {
+				if _, err := budget.acquire(context.Background(), size); err != nil {
+					t.Fatalf("acquire(%d) error = %v", size, err)
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			release, err := budget.acquire(ctx, tt.size)
+			if tt.wantWait {
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Fatalf("acquire(%d) error = %v, want context.DeadlineExceeded", tt.size, err)
+				}
+				// A canceled acquire gives its slot back
+				if !budget.slots.TryAcquire(int64(2 - len(tt.held))) {
+					t.Errorf("slot of the canceled acquire was not released")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("acquire(%d) error = %v", tt.size, err)
+			}
+
+			release()
+			if !budget.slots.TryAcquire(int64(2 - len(tt.held))) {
+				t.Errorf("release() did not give the slot back")
+			}
+		})
+	}
+}
+
+func TestUploadBudgetReleaseWakesWaiter(t *testing.T) {
+	budget := newTestUploadBudget(1, 100)
+	release, err := budget.acquire(context.Background(), 100)
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	acquired := make(chan error, 1)
+	go func() {
+		release, err := budget.acquire(context.Background(), 100)
+		if err == nil {
+			release()
+		}
+		acquired <- err
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second acquire did not wait for the first release")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	release()
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatalf("second acquire error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("second acquire still waiting after release")
+	}
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"mime/multipart"
+	"testing"
+)
+
+// newTestFileHeader builds the header of a file as parsed from a multipart request
+func newTestFileHeader(t *testing.T, filename string, content []byte) *multipart.FileHeader {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	writer.Close()
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { form.RemoveAll() })
+	return form.File["file"][0]
+}
+
+func TestUploadBudgetWeight(t *testing.T) {
+	var pngContent, gifContent bytes.Buffer
+	if err := png.Encode(&pngContent, image.NewNRGBA(image.Rect(0, 0, 100, 50))); err != nil {
+		t.Fatal(err)
+	}
+	if err := gif.Encode(&gifContent, image.NewPaletted(image.Rect(0, 0, 100, 50), color.Palette{color.Black, color.White}), nil); err != nil {
+		t.Fatal(err)
+	}
+	pdfContent := []byte("%PDF-1.4\n1 0 obj\n<<>>\nendobj\ntrailer\n<<>>\n%%EOF\n")
+
+	tests := []struct {
+		name      string
+		filename  string
+		content   []byte
+		category  string
+		wantExtra int64 // on top of the file size
+	}{
+		{"processed image", "foto.png", pngContent.Bytes(), UploadCategoryGaleri, 100 * 50 * 4},
+		{"image kept as uploaded", "scan.png", pngContent.Bytes(), UploadCategoryDokumen, 0},
+		{"gif is never processed", "animasi.gif", gifContent.Bytes(), UploadCategoryGaleri, 0},
+		{"document", "surat.pdf", pdfContent, UploadCategoryDokumen, 0},
+		{"type not allowed", "surat.pdf", pdfContent, UploadCategoryGaleri, 0},
+		{"unknown category", "foto.png", pngContent.Bytes(), "tidak-ada", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := newTestFileHeader(t, tt.filename, tt.content)
+			want := file.Size + tt.wantExtra
+			if got := uploadBudgetWeight(file, tt.category); got != want {
+				t.Errorf("uploadBudgetWeight() = %d, want %d", got, want)
+			}
+		})
+	}
+}